@@ -10,6 +10,7 @@ import (
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
@@ -24,6 +25,286 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// The completion states of tasks.
+type ListTasksRequest_Completion int32
+
+const (
+	// Both open and completed tasks.
+	ListTasksRequest_COMPLETION_UNSPECIFIED ListTasksRequest_Completion = 0
+	// Only tasks that have not been completed yet.
+	ListTasksRequest_COMPLETION_OPEN ListTasksRequest_Completion = 1
+	// Only completed tasks.
+	ListTasksRequest_COMPLETION_COMPLETED ListTasksRequest_Completion = 2
+)
+
+// Enum value maps for ListTasksRequest_Completion.
+var (
+	ListTasksRequest_Completion_name = map[int32]string{
+		0: "COMPLETION_UNSPECIFIED",
+		1: "COMPLETION_OPEN",
+		2: "COMPLETION_COMPLETED",
+	}
+	ListTasksRequest_Completion_value = map[string]int32{
+		"COMPLETION_UNSPECIFIED": 0,
+		"COMPLETION_OPEN":        1,
+		"COMPLETION_COMPLETED":   2,
+	}
+)
+
+func (x ListTasksRequest_Completion) Enum() *ListTasksRequest_Completion {
+	p := new(ListTasksRequest_Completion)
+	*p = x
+	return p
+}
+
+func (x ListTasksRequest_Completion) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ListTasksRequest_Completion) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_v1_todo_proto_enumTypes[0].Descriptor()
+}
+
+func (ListTasksRequest_Completion) Type() protoreflect.EnumType {
+	return &file_todo_v1_todo_proto_enumTypes[0]
+}
+
+func (x ListTasksRequest_Completion) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ListTasksRequest_Completion.Descriptor instead.
+func (ListTasksRequest_Completion) EnumDescriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{15, 0}
+}
+
+// The fields to sort the tasks by.
+type ListTasksRequest_SortBy int32
+
+const (
+	// Sort by creation time, starred tasks first.
+	ListTasksRequest_SORT_BY_UNSPECIFIED ListTasksRequest_SortBy = 0
+	// Sort by due time; tasks without due time come last.
+	ListTasksRequest_SORT_BY_DUE ListTasksRequest_SortBy = 1
+	// Sort by the time of the last update.
+	ListTasksRequest_SORT_BY_UPDATED ListTasksRequest_SortBy = 2
+	// Sort by the position in the manual order, see MoveTask.
+	ListTasksRequest_SORT_BY_POSITION ListTasksRequest_SortBy = 3
+)
+
+// Enum value maps for ListTasksRequest_SortBy.
+var (
+	ListTasksRequest_SortBy_name = map[int32]string{
+		0: "SORT_BY_UNSPECIFIED",
+		1: "SORT_BY_DUE",
+		2: "SORT_BY_UPDATED",
+		3: "SORT_BY_POSITION",
+	}
+	ListTasksRequest_SortBy_value = map[string]int32{
+		"SORT_BY_UNSPECIFIED": 0,
+		"SORT_BY_DUE":         1,
+		"SORT_BY_UPDATED":     2,
+		"SORT_BY_POSITION":    3,
+	}
+)
+
+func (x ListTasksRequest_SortBy) Enum() *ListTasksRequest_SortBy {
+	p := new(ListTasksRequest_SortBy)
+	*p = x
+	return p
+}
+
+func (x ListTasksRequest_SortBy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ListTasksRequest_SortBy) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_v1_todo_proto_enumTypes[1].Descriptor()
+}
+
+func (ListTasksRequest_SortBy) Type() protoreflect.EnumType {
+	return &file_todo_v1_todo_proto_enumTypes[1]
+}
+
+func (x ListTasksRequest_SortBy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ListTasksRequest_SortBy.Descriptor instead.
+func (ListTasksRequest_SortBy) EnumDescriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{15, 1}
+}
+
+type TaskEvent_Type int32
+
+const (
+	TaskEvent_TYPE_UNSPECIFIED TaskEvent_Type = 0
+	TaskEvent_TYPE_CREATED     TaskEvent_Type = 1
+	TaskEvent_TYPE_UPDATED     TaskEvent_Type = 2
+	TaskEvent_TYPE_COMPLETED   TaskEvent_Type = 3
+	TaskEvent_TYPE_DELETED     TaskEvent_Type = 4
+	// The task was assigned to a user, in addition to TYPE_UPDATED or
+	// TYPE_CREATED.
+	TaskEvent_TYPE_ASSIGNED TaskEvent_Type = 5
+)
+
+// Enum value maps for TaskEvent_Type.
+var (
+	TaskEvent_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "TYPE_CREATED",
+		2: "TYPE_UPDATED",
+		3: "TYPE_COMPLETED",
+		4: "TYPE_DELETED",
+		5: "TYPE_ASSIGNED",
+	}
+	TaskEvent_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"TYPE_CREATED":     1,
+		"TYPE_UPDATED":     2,
+		"TYPE_COMPLETED":   3,
+		"TYPE_DELETED":     4,
+		"TYPE_ASSIGNED":    5,
+	}
+)
+
+func (x TaskEvent_Type) Enum() *TaskEvent_Type {
+	p := new(TaskEvent_Type)
+	*p = x
+	return p
+}
+
+func (x TaskEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TaskEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_v1_todo_proto_enumTypes[2].Descriptor()
+}
+
+func (TaskEvent_Type) Type() protoreflect.EnumType {
+	return &file_todo_v1_todo_proto_enumTypes[2]
+}
+
+func (x TaskEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TaskEvent_Type.Descriptor instead.
+func (TaskEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{32, 0}
+}
+
+// The ways of resolving a conflict.
+type SyncConflict_Resolution int32
+
+const (
+	SyncConflict_RESOLUTION_UNSPECIFIED SyncConflict_Resolution = 0
+	// The change in this to-do list was more recent and was kept.
+	SyncConflict_RESOLUTION_LOCAL_WINS SyncConflict_Resolution = 1
+	// The change in the other to-do list was more recent and was applied.
+	SyncConflict_RESOLUTION_REMOTE_WINS SyncConflict_Resolution = 2
+)
+
+// Enum value maps for SyncConflict_Resolution.
+var (
+	SyncConflict_Resolution_name = map[int32]string{
+		0: "RESOLUTION_UNSPECIFIED",
+		1: "RESOLUTION_LOCAL_WINS",
+		2: "RESOLUTION_REMOTE_WINS",
+	}
+	SyncConflict_Resolution_value = map[string]int32{
+		"RESOLUTION_UNSPECIFIED": 0,
+		"RESOLUTION_LOCAL_WINS":  1,
+		"RESOLUTION_REMOTE_WINS": 2,
+	}
+)
+
+func (x SyncConflict_Resolution) Enum() *SyncConflict_Resolution {
+	p := new(SyncConflict_Resolution)
+	*p = x
+	return p
+}
+
+func (x SyncConflict_Resolution) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SyncConflict_Resolution) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_v1_todo_proto_enumTypes[3].Descriptor()
+}
+
+func (SyncConflict_Resolution) Type() protoreflect.EnumType {
+	return &file_todo_v1_todo_proto_enumTypes[3]
+}
+
+func (x SyncConflict_Resolution) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SyncConflict_Resolution.Descriptor instead.
+func (SyncConflict_Resolution) EnumDescriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{59, 0}
+}
+
+// The due times that tasks can be selected by, relative to the time when
+// the tasks are listed.
+type Filter_Due int32
+
+const (
+	// Any due time, or none.
+	Filter_DUE_UNSPECIFIED Filter_Due = 0
+	// Due before the end of the day.
+	Filter_DUE_TODAY Filter_Due = 1
+	// Due before the end of the sixth day after today.
+	Filter_DUE_WEEK Filter_Due = 2
+	// Overdue, i.e. due in the past but not completed.
+	Filter_DUE_OVERDUE Filter_Due = 3
+)
+
+// Enum value maps for Filter_Due.
+var (
+	Filter_Due_name = map[int32]string{
+		0: "DUE_UNSPECIFIED",
+		1: "DUE_TODAY",
+		2: "DUE_WEEK",
+		3: "DUE_OVERDUE",
+	}
+	Filter_Due_value = map[string]int32{
+		"DUE_UNSPECIFIED": 0,
+		"DUE_TODAY":       1,
+		"DUE_WEEK":        2,
+		"DUE_OVERDUE":     3,
+	}
+)
+
+func (x Filter_Due) Enum() *Filter_Due {
+	p := new(Filter_Due)
+	*p = x
+	return p
+}
+
+func (x Filter_Due) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Filter_Due) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_v1_todo_proto_enumTypes[4].Descriptor()
+}
+
+func (Filter_Due) Type() protoreflect.EnumType {
+	return &file_todo_v1_todo_proto_enumTypes[4]
+}
+
+func (x Filter_Due) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Filter_Due.Descriptor instead.
+func (Filter_Due) EnumDescriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{60, 0}
+}
+
 type StatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -65,9 +346,33 @@ type StatusResponse struct {
 	// The identifier of the To-do Daemon's server process.
 	Pid uint32 `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
 	// The URL of the To-do Daemon's REST API.
-	ApiBaseUrl    string `protobuf:"bytes,2,opt,name=api_base_url,json=apiBaseUrl,proto3" json:"api_base_url,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	ApiBaseUrl string `protobuf:"bytes,2,opt,name=api_base_url,json=apiBaseUrl,proto3" json:"api_base_url,omitempty"`
+	// The version of the To-do Daemon server.
+	Version string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	// The time elapsed since the To-do Daemon server was started.
+	Uptime *durationpb.Duration `protobuf:"bytes,4,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	// The name of the storage backend used for persisting tasks.
+	StorageBackend string `protobuf:"bytes,5,opt,name=storage_backend,json=storageBackend,proto3" json:"storage_backend,omitempty"`
+	// The number of tasks in the to-do list.
+	TaskCount uint32 `protobuf:"varint,6,opt,name=task_count,json=taskCount,proto3" json:"task_count,omitempty"`
+	// The address of the socket or named pipe the gRPC server is listening on.
+	SocketAddress string `protobuf:"bytes,7,opt,name=socket_address,json=socketAddress,proto3" json:"socket_address,omitempty"`
+	// The address the HTTP server is listening on.
+	HttpAddress string `protobuf:"bytes,8,opt,name=http_address,json=httpAddress,proto3" json:"http_address,omitempty"`
+	// The minimum version of the CLI that the server supports. Older CLIs are
+	// rejected with FAILED_PRECONDITION, except for this RPC.
+	MinClientVersion string `protobuf:"bytes,9,opt,name=min_client_version,json=minClientVersion,proto3" json:"min_client_version,omitempty"`
+	// The number of clients currently watching the tasks, including the event
+	// streams, webhooks, and hook scripts.
+	EventSubscribers uint32 `protobuf:"varint,10,opt,name=event_subscribers,json=eventSubscribers,proto3" json:"event_subscribers,omitempty"`
+	// The number of events dropped for watching clients that fell behind since
+	// the server was started.
+	DroppedEvents uint64 `protobuf:"varint,11,opt,name=dropped_events,json=droppedEvents,proto3" json:"dropped_events,omitempty"`
+	// The number of watching clients disconnected for falling behind since the
+	// server was started.
+	DisconnectedSubscribers uint64 `protobuf:"varint,12,opt,name=disconnected_subscribers,json=disconnectedSubscribers,proto3" json:"disconnected_subscribers,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
 }
 
 func (x *StatusResponse) Reset() {
@@ -114,32 +419,96 @@ func (x *StatusResponse) GetApiBaseUrl() string {
 	return ""
 }
 
-// A single task to complete in a to-do list.
-type Task struct {
+func (x *StatusResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *StatusResponse) GetUptime() *durationpb.Duration {
+	if x != nil {
+		return x.Uptime
+	}
+	return nil
+}
+
+func (x *StatusResponse) GetStorageBackend() string {
+	if x != nil {
+		return x.StorageBackend
+	}
+	return ""
+}
+
+func (x *StatusResponse) GetTaskCount() uint32 {
+	if x != nil {
+		return x.TaskCount
+	}
+	return 0
+}
+
+func (x *StatusResponse) GetSocketAddress() string {
+	if x != nil {
+		return x.SocketAddress
+	}
+	return ""
+}
+
+func (x *StatusResponse) GetHttpAddress() string {
+	if x != nil {
+		return x.HttpAddress
+	}
+	return ""
+}
+
+func (x *StatusResponse) GetMinClientVersion() string {
+	if x != nil {
+		return x.MinClientVersion
+	}
+	return ""
+}
+
+func (x *StatusResponse) GetEventSubscribers() uint32 {
+	if x != nil {
+		return x.EventSubscribers
+	}
+	return 0
+}
+
+func (x *StatusResponse) GetDroppedEvents() uint64 {
+	if x != nil {
+		return x.DroppedEvents
+	}
+	return 0
+}
+
+func (x *StatusResponse) GetDisconnectedSubscribers() uint64 {
+	if x != nil {
+		return x.DisconnectedSubscribers
+	}
+	return 0
+}
+
+type GetCapabilitiesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Summary       string                 `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Task) Reset() {
-	*x = Task{}
+func (x *GetCapabilitiesRequest) Reset() {
+	*x = GetCapabilitiesRequest{}
 	mi := &file_todo_v1_todo_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Task) String() string {
+func (x *GetCapabilitiesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Task) ProtoMessage() {}
+func (*GetCapabilitiesRequest) ProtoMessage() {}
 
-func (x *Task) ProtoReflect() protoreflect.Message {
+func (x *GetCapabilitiesRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_todo_v1_todo_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -151,70 +520,4119 @@ func (x *Task) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Task.ProtoReflect.Descriptor instead.
-func (*Task) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetCapabilitiesRequest.ProtoReflect.Descriptor instead.
+func (*GetCapabilitiesRequest) Descriptor() ([]byte, []int) {
 	return file_todo_v1_todo_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *Task) GetId() string {
+type GetCapabilitiesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The names of the optional features enabled on the server, sorted, e.g.
+	// "sync" or "webhooks".
+	Features []string `protobuf:"bytes,1,rep,name=features,proto3" json:"features,omitempty"`
+	// The limits that requests must stay within.
+	Limits *Limits `protobuf:"bytes,2,opt,name=limits,proto3" json:"limits,omitempty"`
+	// The versions of the API that the server supports, e.g. "v1".
+	ApiVersions []string `protobuf:"bytes,3,rep,name=api_versions,json=apiVersions,proto3" json:"api_versions,omitempty"`
+	// The name of the storage backend used for persisting and searching tasks.
+	StorageBackend string `protobuf:"bytes,4,opt,name=storage_backend,json=storageBackend,proto3" json:"storage_backend,omitempty"`
+	// Whether the server rejects all modifications, e.g. while handing over
+	// to a new instance.
+	ReadOnly      bool `protobuf:"varint,5,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCapabilitiesResponse) Reset() {
+	*x = GetCapabilitiesResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCapabilitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapabilitiesResponse) ProtoMessage() {}
+
+func (x *GetCapabilitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[3]
 	if x != nil {
-		return x.Id
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *Task) GetSummary() string {
+// Deprecated: Use GetCapabilitiesResponse.ProtoReflect.Descriptor instead.
+func (*GetCapabilitiesResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetCapabilitiesResponse) GetFeatures() []string {
 	if x != nil {
-		return x.Summary
+		return x.Features
 	}
-	return ""
+	return nil
 }
 
-func (x *Task) GetCreatedAt() *timestamppb.Timestamp {
+func (x *GetCapabilitiesResponse) GetLimits() *Limits {
 	if x != nil {
-		return x.CreatedAt
+		return x.Limits
 	}
 	return nil
 }
 
-func (x *Task) GetUpdatedAt() *timestamppb.Timestamp {
+func (x *GetCapabilitiesResponse) GetApiVersions() []string {
 	if x != nil {
-		return x.UpdatedAt
+		return x.ApiVersions
 	}
 	return nil
 }
 
-func (x *Task) GetCompletedAt() *timestamppb.Timestamp {
+func (x *GetCapabilitiesResponse) GetStorageBackend() string {
 	if x != nil {
-		return x.CompletedAt
+		return x.StorageBackend
 	}
-	return nil
+	return ""
 }
 
-// A new task to be added to the to-do list.
-type NewTask struct {
+func (x *GetCapabilitiesResponse) GetReadOnly() bool {
+	if x != nil {
+		return x.ReadOnly
+	}
+	return false
+}
+
+// The limits of the To-do Daemon server.
+type Limits struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The maximum length of a task's summary in characters.
+	MaxSummaryLength uint32 `protobuf:"varint,1,opt,name=max_summary_length,json=maxSummaryLength,proto3" json:"max_summary_length,omitempty"`
+	// The maximum length of a task's description in characters.
+	MaxDescriptionLength uint32 `protobuf:"varint,2,opt,name=max_description_length,json=maxDescriptionLength,proto3" json:"max_description_length,omitempty"`
+	// The maximum length of a task's project in characters.
+	MaxProjectLength uint32 `protobuf:"varint,3,opt,name=max_project_length,json=maxProjectLength,proto3" json:"max_project_length,omitempty"`
+	// The maximum length of a tag in characters.
+	MaxTagLength uint32 `protobuf:"varint,4,opt,name=max_tag_length,json=maxTagLength,proto3" json:"max_tag_length,omitempty"`
+	// The maximum number of tags of a task, which may be lowered by the
+	// server's quotas.
+	MaxTags uint32 `protobuf:"varint,5,opt,name=max_tags,json=maxTags,proto3" json:"max_tags,omitempty"`
+	// The maximum length of the name of a filter or template in characters.
+	MaxNameLength uint32 `protobuf:"varint,6,opt,name=max_name_length,json=maxNameLength,proto3" json:"max_name_length,omitempty"`
+	// The maximum number of tasks of a template.
+	MaxTemplateTasks uint32 `protobuf:"varint,7,opt,name=max_template_tasks,json=maxTemplateTasks,proto3" json:"max_template_tasks,omitempty"`
+	// The number of tasks per page of the v2 API if no page size is requested.
+	DefaultPageSize uint32 `protobuf:"varint,8,opt,name=default_page_size,json=defaultPageSize,proto3" json:"default_page_size,omitempty"`
+	// The maximum number of tasks per page of the v2 API.
+	MaxPageSize uint32 `protobuf:"varint,9,opt,name=max_page_size,json=maxPageSize,proto3" json:"max_page_size,omitempty"`
+	// The maximum length of a task's assignee in characters.
+	MaxAssigneeLength uint32 `protobuf:"varint,10,opt,name=max_assignee_length,json=maxAssigneeLength,proto3" json:"max_assignee_length,omitempty"`
+	// The maximum size of the body of a REST API request in bytes.
+	MaxBodySize uint32 `protobuf:"varint,11,opt,name=max_body_size,json=maxBodySize,proto3" json:"max_body_size,omitempty"`
+	// The maximum number of links of a task.
+	MaxLinks uint32 `protobuf:"varint,12,opt,name=max_links,json=maxLinks,proto3" json:"max_links,omitempty"`
+	// The maximum length of a link in characters.
+	MaxLinkLength uint32 `protobuf:"varint,13,opt,name=max_link_length,json=maxLinkLength,proto3" json:"max_link_length,omitempty"`
+	// The maximum number of open tasks in the to-do list, or 0 if the number is
+	// not limited.
+	MaxOpenTasks  uint32 `protobuf:"varint,14,opt,name=max_open_tasks,json=maxOpenTasks,proto3" json:"max_open_tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Limits) Reset() {
+	*x = Limits{}
+	mi := &file_todo_v1_todo_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Limits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Limits) ProtoMessage() {}
+
+func (x *Limits) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Limits.ProtoReflect.Descriptor instead.
+func (*Limits) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Limits) GetMaxSummaryLength() uint32 {
+	if x != nil {
+		return x.MaxSummaryLength
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxDescriptionLength() uint32 {
+	if x != nil {
+		return x.MaxDescriptionLength
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxProjectLength() uint32 {
+	if x != nil {
+		return x.MaxProjectLength
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxTagLength() uint32 {
+	if x != nil {
+		return x.MaxTagLength
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxTags() uint32 {
+	if x != nil {
+		return x.MaxTags
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxNameLength() uint32 {
+	if x != nil {
+		return x.MaxNameLength
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxTemplateTasks() uint32 {
+	if x != nil {
+		return x.MaxTemplateTasks
+	}
+	return 0
+}
+
+func (x *Limits) GetDefaultPageSize() uint32 {
+	if x != nil {
+		return x.DefaultPageSize
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxPageSize() uint32 {
+	if x != nil {
+		return x.MaxPageSize
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxAssigneeLength() uint32 {
+	if x != nil {
+		return x.MaxAssigneeLength
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxBodySize() uint32 {
+	if x != nil {
+		return x.MaxBodySize
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxLinks() uint32 {
+	if x != nil {
+		return x.MaxLinks
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxLinkLength() uint32 {
+	if x != nil {
+		return x.MaxLinkLength
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxOpenTasks() uint32 {
+	if x != nil {
+		return x.MaxOpenTasks
+	}
+	return 0
+}
+
+// A single task to complete in a to-do list.
+type Task struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Summary   string                 `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// The time of the last update of the task. Unset if the task has never
+	// been updated.
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// The time the task was completed. Unset if the task is open.
+	CompletedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	Description string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	// The time the task is due. Unset if the task has no due time.
+	DueAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`
+	// The version of the task, which is incremented with each update.
+	Version uint64 `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
+	// A short, human-friendly code derived from the ID, which ResolveTask
+	// accepts in place of the ID.
+	ShortCode string `protobuf:"bytes,9,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	// The tags of the task, e.g. "errands".
+	Tags []string `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`
+	// The project the task belongs to, if any.
+	Project string `protobuf:"bytes,11,opt,name=project,proto3" json:"project,omitempty"`
+	// The position of the task in the manual order of the to-do list. New
+	// tasks come last.
+	Position int64 `protobuf:"varint,12,opt,name=position,proto3" json:"position,omitempty"`
+	// The IDs of the tasks that must be completed before this task.
+	DependsOn []string `protobuf:"bytes,13,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	// The IDs of the tasks this task depends on that are still open.
+	BlockedBy []string `protobuf:"bytes,14,rep,name=blocked_by,json=blockedBy,proto3" json:"blocked_by,omitempty"`
+	// Whether the task is blocked, i.e. whether blocked_by is non-empty.
+	Blocked bool `protobuf:"varint,15,opt,name=blocked,proto3" json:"blocked,omitempty"`
+	// The rule that the task recurs by, e.g. "FREQ=WEEKLY;BYDAY=MO". When a
+	// recurring task is completed, its next occurrence is added as a new task.
+	Recurrence string `protobuf:"bytes,16,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	// The IANA name of the time zone that the task's times refer to, e.g.
+	// "Europe/Berlin". If empty, the server's local time zone is used.
+	TimeZone string `protobuf:"bytes,17,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// The due time in the task's time zone as an RFC 3339 timestamp with an
+	// explicit offset, e.g. "2025-12-24T18:00:00+01:00". Read-only.
+	DueAtLocal string `protobuf:"bytes,18,opt,name=due_at_local,json=dueAtLocal,proto3" json:"due_at_local,omitempty"`
+	// Whether the task is starred. Starred tasks are listed first when the
+	// tasks are sorted by creation time.
+	Starred bool `protobuf:"varint,19,opt,name=starred,proto3" json:"starred,omitempty"`
+	// The globally unique ID of the task, which identifies it across
+	// synchronized to-do lists, unlike the ID. Read-only.
+	Uid string `protobuf:"bytes,20,opt,name=uid,proto3" json:"uid,omitempty"`
+	// The user the task is assigned to, if any, e.g. "alice".
+	Assignee string `protobuf:"bytes,21,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	// Whether the task is completed, i.e. whether completed_at is set.
+	// Read-only. Clients should rely on it instead of comparing completed_at to
+	// their own clock, which may be skewed.
+	Completed bool `protobuf:"varint,22,opt,name=completed,proto3" json:"completed,omitempty"`
+	// The URLs of web pages related to the task, e.g. tickets or documents.
+	// Each link is an absolute http or https URL.
+	Links         []string `protobuf:"bytes,23,rep,name=links,proto3" json:"links,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Task) Reset() {
+	*x = Task{}
+	mi := &file_todo_v1_todo_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Task) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Task) ProtoMessage() {}
+
+func (x *Task) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Task.ProtoReflect.Descriptor instead.
+func (*Task) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Task) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Task) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Task) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Task) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Task) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *Task) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Task) GetDueAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueAt
+	}
+	return nil
+}
+
+func (x *Task) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Task) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *Task) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Task) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *Task) GetPosition() int64 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *Task) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *Task) GetBlockedBy() []string {
+	if x != nil {
+		return x.BlockedBy
+	}
+	return nil
+}
+
+func (x *Task) GetBlocked() bool {
+	if x != nil {
+		return x.Blocked
+	}
+	return false
+}
+
+func (x *Task) GetRecurrence() string {
+	if x != nil {
+		return x.Recurrence
+	}
+	return ""
+}
+
+func (x *Task) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *Task) GetDueAtLocal() string {
+	if x != nil {
+		return x.DueAtLocal
+	}
+	return ""
+}
+
+func (x *Task) GetStarred() bool {
+	if x != nil {
+		return x.Starred
+	}
+	return false
+}
+
+func (x *Task) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *Task) GetAssignee() string {
+	if x != nil {
+		return x.Assignee
+	}
+	return ""
+}
+
+func (x *Task) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+func (x *Task) GetLinks() []string {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+// A new task to be added to the to-do list.
+type NewTask struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The initial summary of the task.
+	Summary string `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"`
+	// The initial description of the task.
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// The time when the task is due, if any.
+	DueAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`
+	// The initial tags of the task.
+	Tags []string `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	// The project the task belongs to, if any.
+	Project string `protobuf:"bytes,5,opt,name=project,proto3" json:"project,omitempty"`
+	// The IDs of the tasks that must be completed before this task.
+	DependsOn []string `protobuf:"bytes,6,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	// The rule that the task recurs by, if any, e.g. "FREQ=WEEKLY;BYDAY=MO".
+	Recurrence string `protobuf:"bytes,7,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	// The IANA name of the time zone that the task's times refer to. If empty,
+	// the server's default time zone is used.
+	TimeZone string `protobuf:"bytes,8,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// Whether the task is starred.
+	Starred bool `protobuf:"varint,9,opt,name=starred,proto3" json:"starred,omitempty"`
+	// The user the task is assigned to, if any.
+	Assignee string `protobuf:"bytes,10,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	// The URLs of web pages related to the task.
+	Links []string `protobuf:"bytes,11,rep,name=links,proto3" json:"links,omitempty"`
+	// Whether to create the task even if its summary equals the summary of an
+	// open task, ignoring case, and the server rejects such duplicates, see the
+	// "unique_summaries" feature.
+	AllowDuplicate bool `protobuf:"varint,12,opt,name=allow_duplicate,json=allowDuplicate,proto3" json:"allow_duplicate,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *NewTask) Reset() {
+	*x = NewTask{}
+	mi := &file_todo_v1_todo_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NewTask) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewTask) ProtoMessage() {}
+
+func (x *NewTask) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewTask.ProtoReflect.Descriptor instead.
+func (*NewTask) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *NewTask) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *NewTask) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *NewTask) GetDueAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueAt
+	}
+	return nil
+}
+
+func (x *NewTask) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *NewTask) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *NewTask) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *NewTask) GetRecurrence() string {
+	if x != nil {
+		return x.Recurrence
+	}
+	return ""
+}
+
+func (x *NewTask) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *NewTask) GetStarred() bool {
+	if x != nil {
+		return x.Starred
+	}
+	return false
+}
+
+func (x *NewTask) GetAssignee() string {
+	if x != nil {
+		return x.Assignee
+	}
+	return ""
+}
+
+func (x *NewTask) GetLinks() []string {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+func (x *NewTask) GetAllowDuplicate() bool {
+	if x != nil {
+		return x.AllowDuplicate
+	}
+	return false
+}
+
+// The changes to apply to an existing task in the to-do list.
+type TaskUpdate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The new summary to assign to the task.
+	Summary string `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"`
+	// The completion timestamp to assign to the task.
+	CompletedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	// The new description to assign to the task.
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// The new due time to assign to the task.
+	DueAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`
+	// The new tags to assign to the task.
+	Tags []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	// The new project to assign to the task.
+	Project string `protobuf:"bytes,6,opt,name=project,proto3" json:"project,omitempty"`
+	// The IDs of the tasks that the task depends on from now on.
+	DependsOn []string `protobuf:"bytes,7,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	// The new rule that the task recurs by, or empty to stop the recurrence.
+	Recurrence string `protobuf:"bytes,8,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	// The IANA name of the new time zone that the task's times refer to.
+	TimeZone string `protobuf:"bytes,9,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// Whether the task is starred from now on.
+	Starred bool `protobuf:"varint,10,opt,name=starred,proto3" json:"starred,omitempty"`
+	// The user the task is assigned to from now on, or empty to unassign it.
+	Assignee string `protobuf:"bytes,11,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	// The new URLs of web pages related to the task.
+	Links         []string `protobuf:"bytes,12,rep,name=links,proto3" json:"links,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskUpdate) Reset() {
+	*x = TaskUpdate{}
+	mi := &file_todo_v1_todo_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskUpdate) ProtoMessage() {}
+
+func (x *TaskUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskUpdate.ProtoReflect.Descriptor instead.
+func (*TaskUpdate) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TaskUpdate) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *TaskUpdate) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *TaskUpdate) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TaskUpdate) GetDueAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueAt
+	}
+	return nil
+}
+
+func (x *TaskUpdate) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *TaskUpdate) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *TaskUpdate) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *TaskUpdate) GetRecurrence() string {
+	if x != nil {
+		return x.Recurrence
+	}
+	return ""
+}
+
+func (x *TaskUpdate) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *TaskUpdate) GetStarred() bool {
+	if x != nil {
+		return x.Starred
+	}
+	return false
+}
+
+func (x *TaskUpdate) GetAssignee() string {
+	if x != nil {
+		return x.Assignee
+	}
+	return ""
+}
+
+func (x *TaskUpdate) GetLinks() []string {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+type CreateTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The task to create.
+	Task *NewTask `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	// A unique key chosen by the client, e.g. a random UUID, so that the
+	// request can be retried safely: the server creates the task only once and
+	// returns the same task for requests with the same key within its
+	// idempotency window. The REST API also accepts it as Idempotency-Key
+	// header. At most 255 printable ASCII characters.
+	IdempotencyKey string `protobuf:"bytes,2,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateTaskRequest) Reset() {
+	*x = CreateTaskRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTaskRequest) ProtoMessage() {}
+
+func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTaskRequest.ProtoReflect.Descriptor instead.
+func (*CreateTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreateTaskRequest) GetTask() *NewTask {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type CreateTaskResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The task that was created.
+	Task          *Task `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTaskResponse) Reset() {
+	*x = CreateTaskResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTaskResponse) ProtoMessage() {}
+
+func (x *CreateTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTaskResponse.ProtoReflect.Descriptor instead.
+func (*CreateTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CreateTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type BatchCreateTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The tasks to create, in order.
+	Tasks         []*NewTask `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateTasksRequest) Reset() {
+	*x = BatchCreateTasksRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateTasksRequest) ProtoMessage() {}
+
+func (x *BatchCreateTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateTasksRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateTasksRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchCreateTasksRequest) GetTasks() []*NewTask {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type BatchCreateTasksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The tasks that were created, in the order of the request.
+	Tasks         []*Task `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateTasksResponse) Reset() {
+	*x = BatchCreateTasksResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateTasksResponse) ProtoMessage() {}
+
+func (x *BatchCreateTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateTasksResponse.ProtoReflect.Descriptor instead.
+func (*BatchCreateTasksResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchCreateTasksResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type BatchOperation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Operation:
+	//
+	//	*BatchOperation_Create
+	//	*BatchOperation_Update
+	//	*BatchOperation_Delete
+	Operation     isBatchOperation_Operation `protobuf_oneof:"operation"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchOperation) Reset() {
+	*x = BatchOperation{}
+	mi := &file_todo_v1_todo_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchOperation) ProtoMessage() {}
+
+func (x *BatchOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchOperation.ProtoReflect.Descriptor instead.
+func (*BatchOperation) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BatchOperation) GetOperation() isBatchOperation_Operation {
+	if x != nil {
+		return x.Operation
+	}
+	return nil
+}
+
+func (x *BatchOperation) GetCreate() *NewTask {
+	if x != nil {
+		if x, ok := x.Operation.(*BatchOperation_Create); ok {
+			return x.Create
+		}
+	}
+	return nil
+}
+
+func (x *BatchOperation) GetUpdate() *UpdateTaskRequest {
+	if x != nil {
+		if x, ok := x.Operation.(*BatchOperation_Update); ok {
+			return x.Update
+		}
+	}
+	return nil
+}
+
+func (x *BatchOperation) GetDelete() *DeleteTaskRequest {
+	if x != nil {
+		if x, ok := x.Operation.(*BatchOperation_Delete); ok {
+			return x.Delete
+		}
+	}
+	return nil
+}
+
+type isBatchOperation_Operation interface {
+	isBatchOperation_Operation()
+}
+
+type BatchOperation_Create struct {
+	// Creates a new task.
+	Create *NewTask `protobuf:"bytes,1,opt,name=create,proto3,oneof"`
+}
+
+type BatchOperation_Update struct {
+	// Updates an existing task, including a task created by an earlier
+	// operation of the batch.
+	Update *UpdateTaskRequest `protobuf:"bytes,2,opt,name=update,proto3,oneof"`
+}
+
+type BatchOperation_Delete struct {
+	// Moves an existing task to the trash.
+	Delete *DeleteTaskRequest `protobuf:"bytes,3,opt,name=delete,proto3,oneof"`
+}
+
+func (*BatchOperation_Create) isBatchOperation_Operation() {}
+
+func (*BatchOperation_Update) isBatchOperation_Operation() {}
+
+func (*BatchOperation_Delete) isBatchOperation_Operation() {}
+
+type ApplyBatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The operations to apply, in this order. Each operation sees the changes
+	// of the operations before it.
+	Operations    []*BatchOperation `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyBatchRequest) Reset() {
+	*x = ApplyBatchRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyBatchRequest) ProtoMessage() {}
+
+func (x *ApplyBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyBatchRequest.ProtoReflect.Descriptor instead.
+func (*ApplyBatchRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ApplyBatchRequest) GetOperations() []*BatchOperation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+type ApplyBatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The resulting task of each operation, in the order of the request. For a
+	// delete operation, this is the task that was moved to the trash.
+	Tasks         []*Task `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyBatchResponse) Reset() {
+	*x = ApplyBatchResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyBatchResponse) ProtoMessage() {}
+
+func (x *ApplyBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyBatchResponse.ProtoReflect.Descriptor instead.
+func (*ApplyBatchResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ApplyBatchResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type ListTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// If set, only the tasks due before this time are returned.
+	DueBefore *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=due_before,json=dueBefore,proto3" json:"due_before,omitempty"`
+	// If true, only the tasks that are overdue, i.e. due in the past but not
+	// completed, are returned.
+	Overdue bool `protobuf:"varint,2,opt,name=overdue,proto3" json:"overdue,omitempty"`
+	// If set, only the tasks due at or after this time are returned.
+	DueAfter *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=due_after,json=dueAfter,proto3" json:"due_after,omitempty"`
+	// Selects the tasks by their completion state.
+	Completion ListTasksRequest_Completion `protobuf:"varint,4,opt,name=completion,proto3,enum=todo.v1.ListTasksRequest_Completion" json:"completion,omitempty"`
+	// If set, only the tasks having all of these tags are returned.
+	Tags []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	// If set, only the tasks of this project are returned.
+	Project string `protobuf:"bytes,6,opt,name=project,proto3" json:"project,omitempty"`
+	// The field to sort the tasks by.
+	SortBy ListTasksRequest_SortBy `protobuf:"varint,7,opt,name=sort_by,json=sortBy,proto3,enum=todo.v1.ListTasksRequest_SortBy" json:"sort_by,omitempty"`
+	// If true, the tasks are sorted in descending order.
+	Descending bool `protobuf:"varint,8,opt,name=descending,proto3" json:"descending,omitempty"`
+	// The number of tasks to skip, for pagination.
+	Offset uint32 `protobuf:"varint,9,opt,name=offset,proto3" json:"offset,omitempty"`
+	// The maximum number of tasks to return. Zero means no limit.
+	Limit uint32 `protobuf:"varint,10,opt,name=limit,proto3" json:"limit,omitempty"`
+	// If true, only the starred tasks are returned.
+	Starred bool `protobuf:"varint,11,opt,name=starred,proto3" json:"starred,omitempty"`
+	// If set, only the tasks selected by the saved filter with this name are
+	// returned, see ListFilters. Its tags are combined with the tags above; its
+	// other criteria apply unless set above.
+	Filter string `protobuf:"bytes,12,opt,name=filter,proto3" json:"filter,omitempty"`
+	// If set, only the tasks assigned to this user are returned.
+	Assignee      string `protobuf:"bytes,13,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksRequest) Reset() {
+	*x = ListTasksRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksRequest) ProtoMessage() {}
+
+func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
+func (*ListTasksRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListTasksRequest) GetDueBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueBefore
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetOverdue() bool {
+	if x != nil {
+		return x.Overdue
+	}
+	return false
+}
+
+func (x *ListTasksRequest) GetDueAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueAfter
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetCompletion() ListTasksRequest_Completion {
+	if x != nil {
+		return x.Completion
+	}
+	return ListTasksRequest_COMPLETION_UNSPECIFIED
+}
+
+func (x *ListTasksRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetSortBy() ListTasksRequest_SortBy {
+	if x != nil {
+		return x.SortBy
+	}
+	return ListTasksRequest_SORT_BY_UNSPECIFIED
+}
+
+func (x *ListTasksRequest) GetDescending() bool {
+	if x != nil {
+		return x.Descending
+	}
+	return false
+}
+
+func (x *ListTasksRequest) GetOffset() uint32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListTasksRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListTasksRequest) GetStarred() bool {
+	if x != nil {
+		return x.Starred
+	}
+	return false
+}
+
+func (x *ListTasksRequest) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetAssignee() string {
+	if x != nil {
+		return x.Assignee
+	}
+	return ""
+}
+
+type ListTasksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The tasks available in the to-do list.
+	Tasks         []*Task `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksResponse) Reset() {
+	*x = ListTasksResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksResponse) ProtoMessage() {}
+
+func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksResponse.ProtoReflect.Descriptor instead.
+func (*ListTasksResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListTasksResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type GetTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The ID of the task to retrieve.
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskRequest) Reset() {
+	*x = GetTaskRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskRequest) ProtoMessage() {}
+
+func (x *GetTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskResponse) Reset() {
+	*x = GetTaskResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskResponse) ProtoMessage() {}
+
+func (x *GetTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskResponse.ProtoReflect.Descriptor instead.
+func (*GetTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type ResolveTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The ID, the short code, or a unique prefix of the short code of the task.
+	Ref           string `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveTaskRequest) Reset() {
+	*x = ResolveTaskRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveTaskRequest) ProtoMessage() {}
+
+func (x *ResolveTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveTaskRequest.ProtoReflect.Descriptor instead.
+func (*ResolveTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ResolveTaskRequest) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+type ResolveTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveTaskResponse) Reset() {
+	*x = ResolveTaskResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveTaskResponse) ProtoMessage() {}
+
+func (x *ResolveTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveTaskResponse.ProtoReflect.Descriptor instead.
+func (*ResolveTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ResolveTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type UpdateTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The ID of the task to update.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The changes to apply to the task's fields.
+	Update *TaskUpdate `protobuf:"bytes,2,opt,name=update,proto3" json:"update,omitempty"`
+	// The fields of the task to be updated.
+	Fields *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=fields,proto3" json:"fields,omitempty"`
+	// If set, the update is only applied if the task's current version matches
+	// the expected version. Otherwise, the request fails with ABORTED.
+	ExpectedVersion uint64 `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateTaskRequest) Reset() {
+	*x = UpdateTaskRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTaskRequest) ProtoMessage() {}
+
+func (x *UpdateTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTaskRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UpdateTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetUpdate() *TaskUpdate {
+	if x != nil {
+		return x.Update
+	}
+	return nil
+}
+
+func (x *UpdateTaskRequest) GetFields() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *UpdateTaskRequest) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type UpdateTaskResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The task after applying the update.
+	Task          *Task `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTaskResponse) Reset() {
+	*x = UpdateTaskResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTaskResponse) ProtoMessage() {}
+
+func (x *UpdateTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTaskResponse.ProtoReflect.Descriptor instead.
+func (*UpdateTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *UpdateTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type MoveTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The ID of the task to move.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The ID of the task to move the task before. Exactly one of before_id and
+	// after_id must be set.
+	BeforeId string `protobuf:"bytes,2,opt,name=before_id,json=beforeId,proto3" json:"before_id,omitempty"`
+	// The ID of the task to move the task after.
+	AfterId       string `protobuf:"bytes,3,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveTaskRequest) Reset() {
+	*x = MoveTaskRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveTaskRequest) ProtoMessage() {}
+
+func (x *MoveTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveTaskRequest.ProtoReflect.Descriptor instead.
+func (*MoveTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *MoveTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MoveTaskRequest) GetBeforeId() string {
+	if x != nil {
+		return x.BeforeId
+	}
+	return ""
+}
+
+func (x *MoveTaskRequest) GetAfterId() string {
+	if x != nil {
+		return x.AfterId
+	}
+	return ""
+}
+
+type MoveTaskResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The task at its new position.
+	Task          *Task `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveTaskResponse) Reset() {
+	*x = MoveTaskResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveTaskResponse) ProtoMessage() {}
+
+func (x *MoveTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveTaskResponse.ProtoReflect.Descriptor instead.
+func (*MoveTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *MoveTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type SearchTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The search query. Tasks matching any of the words in the query are
+	// returned.
+	Q string `protobuf:"bytes,1,opt,name=q,proto3" json:"q,omitempty"`
+	// The maximum number of results to return. Zero means no limit.
+	Limit         uint32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchTasksRequest) Reset() {
+	*x = SearchTasksRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchTasksRequest) ProtoMessage() {}
+
+func (x *SearchTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchTasksRequest.ProtoReflect.Descriptor instead.
+func (*SearchTasksRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SearchTasksRequest) GetQ() string {
+	if x != nil {
+		return x.Q
+	}
+	return ""
+}
+
+func (x *SearchTasksRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SearchTasksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The matching tasks, ordered by descending relevance.
+	Results       []*SearchResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchTasksResponse) Reset() {
+	*x = SearchTasksResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchTasksResponse) ProtoMessage() {}
+
+func (x *SearchTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchTasksResponse.ProtoReflect.Descriptor instead.
+func (*SearchTasksResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SearchTasksResponse) GetResults() []*SearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// A task matching a search query.
+type SearchResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The matching task.
+	Task *Task `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	// The relevance of the task; higher is more relevant.
+	Score         float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResult) Reset() {
+	*x = SearchResult{}
+	mi := &file_todo_v1_todo_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResult) ProtoMessage() {}
+
+func (x *SearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResult.ProtoReflect.Descriptor instead.
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SearchResult) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *SearchResult) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type GetStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{28}
+}
+
+type GetStatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The number of tasks that have not been completed yet.
+	OpenCount uint32 `protobuf:"varint,1,opt,name=open_count,json=openCount,proto3" json:"open_count,omitempty"`
+	// The number of completed tasks.
+	CompletedCount uint32 `protobuf:"varint,2,opt,name=completed_count,json=completedCount,proto3" json:"completed_count,omitempty"`
+	// The number of tasks completed since midnight in the server's time zone.
+	CompletedTodayCount uint32 `protobuf:"varint,3,opt,name=completed_today_count,json=completedTodayCount,proto3" json:"completed_today_count,omitempty"`
+	// The number of tasks completed since Monday midnight in the server's time
+	// zone.
+	CompletedThisWeekCount uint32 `protobuf:"varint,4,opt,name=completed_this_week_count,json=completedThisWeekCount,proto3" json:"completed_this_week_count,omitempty"`
+	// The number of open tasks that are past their due time.
+	OverdueCount uint32 `protobuf:"varint,5,opt,name=overdue_count,json=overdueCount,proto3" json:"overdue_count,omitempty"`
+	// The average time from the creation to the completion of the completed
+	// tasks, if any.
+	AverageCompletionTime *durationpb.Duration `protobuf:"bytes,6,opt,name=average_completion_time,json=averageCompletionTime,proto3" json:"average_completion_time,omitempty"`
+	// The number of tasks per tag, ordered by tag.
+	Tags []*GroupStats `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
+	// The number of tasks per project, ordered by project. Tasks without project
+	// are not counted.
+	Projects      []*GroupStats `protobuf:"bytes,8,rep,name=projects,proto3" json:"projects,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsResponse) ProtoMessage() {}
+
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStatsResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetStatsResponse) GetOpenCount() uint32 {
+	if x != nil {
+		return x.OpenCount
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetCompletedCount() uint32 {
+	if x != nil {
+		return x.CompletedCount
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetCompletedTodayCount() uint32 {
+	if x != nil {
+		return x.CompletedTodayCount
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetCompletedThisWeekCount() uint32 {
+	if x != nil {
+		return x.CompletedThisWeekCount
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetOverdueCount() uint32 {
+	if x != nil {
+		return x.OverdueCount
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetAverageCompletionTime() *durationpb.Duration {
+	if x != nil {
+		return x.AverageCompletionTime
+	}
+	return nil
+}
+
+func (x *GetStatsResponse) GetTags() []*GroupStats {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *GetStatsResponse) GetProjects() []*GroupStats {
+	if x != nil {
+		return x.Projects
+	}
+	return nil
+}
+
+// The number of tasks in a group of tasks, e.g. with the same tag.
+type GroupStats struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the group, e.g. the tag.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The number of tasks in the group that have not been completed yet.
+	OpenCount uint32 `protobuf:"varint,2,opt,name=open_count,json=openCount,proto3" json:"open_count,omitempty"`
+	// The number of completed tasks in the group.
+	CompletedCount uint32 `protobuf:"varint,3,opt,name=completed_count,json=completedCount,proto3" json:"completed_count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GroupStats) Reset() {
+	*x = GroupStats{}
+	mi := &file_todo_v1_todo_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupStats) ProtoMessage() {}
+
+func (x *GroupStats) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupStats.ProtoReflect.Descriptor instead.
+func (*GroupStats) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GroupStats) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GroupStats) GetOpenCount() uint32 {
+	if x != nil {
+		return x.OpenCount
+	}
+	return 0
+}
+
+func (x *GroupStats) GetCompletedCount() uint32 {
+	if x != nil {
+		return x.CompletedCount
+	}
+	return 0
+}
+
+type WatchTasksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTasksRequest) Reset() {
+	*x = WatchTasksRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTasksRequest) ProtoMessage() {}
+
+func (x *WatchTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTasksRequest.ProtoReflect.Descriptor instead.
+func (*WatchTasksRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{31}
+}
+
+// A change to a task in the to-do list.
+type TaskEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  TaskEvent_Type         `protobuf:"varint,1,opt,name=type,proto3,enum=todo.v1.TaskEvent_Type" json:"type,omitempty"`
+	// The task after the change. Only the ID is set for deleted tasks.
+	Task *Task                  `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`
+	Time *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	// The sequence number of the event. The events are numbered consecutively,
+	// starting at 1 when the server starts.
+	Sequence      uint64 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskEvent) Reset() {
+	*x = TaskEvent{}
+	mi := &file_todo_v1_todo_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskEvent) ProtoMessage() {}
+
+func (x *TaskEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskEvent.ProtoReflect.Descriptor instead.
+func (*TaskEvent) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *TaskEvent) GetType() TaskEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return TaskEvent_TYPE_UNSPECIFIED
+}
+
+func (x *TaskEvent) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *TaskEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *TaskEvent) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+type CreateBackupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBackupRequest) Reset() {
+	*x = CreateBackupRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBackupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBackupRequest) ProtoMessage() {}
+
+func (x *CreateBackupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBackupRequest.ProtoReflect.Descriptor instead.
+func (*CreateBackupRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{33}
+}
+
+type CreateBackupResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The snapshot of the to-do list as versioned, gzip-compressed JSON
+	// document.
+	Archive []byte `protobuf:"bytes,1,opt,name=archive,proto3" json:"archive,omitempty"`
+	// The number of tasks in the snapshot.
+	TaskCount     uint32 `protobuf:"varint,2,opt,name=task_count,json=taskCount,proto3" json:"task_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBackupResponse) Reset() {
+	*x = CreateBackupResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBackupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBackupResponse) ProtoMessage() {}
+
+func (x *CreateBackupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBackupResponse.ProtoReflect.Descriptor instead.
+func (*CreateBackupResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *CreateBackupResponse) GetArchive() []byte {
+	if x != nil {
+		return x.Archive
+	}
+	return nil
+}
+
+func (x *CreateBackupResponse) GetTaskCount() uint32 {
+	if x != nil {
+		return x.TaskCount
+	}
+	return 0
+}
+
+type RestoreBackupRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The snapshot created by CreateBackup.
+	Archive       []byte `protobuf:"bytes,1,opt,name=archive,proto3" json:"archive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreBackupRequest) Reset() {
+	*x = RestoreBackupRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreBackupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreBackupRequest) ProtoMessage() {}
+
+func (x *RestoreBackupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreBackupRequest.ProtoReflect.Descriptor instead.
+func (*RestoreBackupRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *RestoreBackupRequest) GetArchive() []byte {
+	if x != nil {
+		return x.Archive
+	}
+	return nil
+}
+
+type RestoreBackupResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The number of restored tasks.
+	TaskCount     uint32 `protobuf:"varint,1,opt,name=task_count,json=taskCount,proto3" json:"task_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreBackupResponse) Reset() {
+	*x = RestoreBackupResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreBackupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreBackupResponse) ProtoMessage() {}
+
+func (x *RestoreBackupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreBackupResponse.ProtoReflect.Descriptor instead.
+func (*RestoreBackupResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *RestoreBackupResponse) GetTaskCount() uint32 {
+	if x != nil {
+		return x.TaskCount
+	}
+	return 0
+}
+
+type ReloadConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReloadConfigRequest) Reset() {
+	*x = ReloadConfigRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReloadConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadConfigRequest) ProtoMessage() {}
+
+func (x *ReloadConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadConfigRequest.ProtoReflect.Descriptor instead.
+func (*ReloadConfigRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{37}
+}
+
+type ReloadConfigResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The changed settings that have been applied, e.g. "log_level".
+	Applied []string `protobuf:"bytes,1,rep,name=applied,proto3" json:"applied,omitempty"`
+	// The changed settings that only take effect after restarting the server,
+	// e.g. "sock_file".
+	RequiresRestart []string `protobuf:"bytes,2,rep,name=requires_restart,json=requiresRestart,proto3" json:"requires_restart,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ReloadConfigResponse) Reset() {
+	*x = ReloadConfigResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReloadConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadConfigResponse) ProtoMessage() {}
+
+func (x *ReloadConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadConfigResponse.ProtoReflect.Descriptor instead.
+func (*ReloadConfigResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ReloadConfigResponse) GetApplied() []string {
+	if x != nil {
+		return x.Applied
+	}
+	return nil
+}
+
+func (x *ReloadConfigResponse) GetRequiresRestart() []string {
+	if x != nil {
+		return x.RequiresRestart
+	}
+	return nil
+}
+
+type TakeoverRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The path of the Unix domain socket that the new instance receives the
+	// listeners on.
+	HandoverAddress string `protobuf:"bytes,1,opt,name=handover_address,json=handoverAddress,proto3" json:"handover_address,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *TakeoverRequest) Reset() {
+	*x = TakeoverRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TakeoverRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TakeoverRequest) ProtoMessage() {}
+
+func (x *TakeoverRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TakeoverRequest.ProtoReflect.Descriptor instead.
+func (*TakeoverRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *TakeoverRequest) GetHandoverAddress() string {
+	if x != nil {
+		return x.HandoverAddress
+	}
+	return ""
+}
+
+type TakeoverResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// A snapshot of the entire to-do list, including the trash, taken after
+	// the server switched to read-only mode.
+	Archive []byte `protobuf:"bytes,1,opt,name=archive,proto3" json:"archive,omitempty"`
+	// The identifier of the server process that handed over its listeners.
+	Pid           uint32 `protobuf:"varint,2,opt,name=pid,proto3" json:"pid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TakeoverResponse) Reset() {
+	*x = TakeoverResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TakeoverResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TakeoverResponse) ProtoMessage() {}
+
+func (x *TakeoverResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TakeoverResponse.ProtoReflect.Descriptor instead.
+func (*TakeoverResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *TakeoverResponse) GetArchive() []byte {
+	if x != nil {
+		return x.Archive
+	}
+	return nil
+}
+
+func (x *TakeoverResponse) GetPid() uint32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+type ListJobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsRequest) Reset() {
+	*x = ListJobsRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsRequest) ProtoMessage() {}
+
+func (x *ListJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsRequest.ProtoReflect.Descriptor instead.
+func (*ListJobsRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{41}
+}
+
+type ListJobsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The background jobs, sorted by name.
+	Jobs          []*BackgroundJob `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsResponse) Reset() {
+	*x = ListJobsResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsResponse) ProtoMessage() {}
+
+func (x *ListJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsResponse.ProtoReflect.Descriptor instead.
+func (*ListJobsResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ListJobsResponse) GetJobs() []*BackgroundJob {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+// A periodic background job of the To-do Daemon server.
+type BackgroundJob struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the job, e.g. "backup".
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The time between two runs, without jitter.
+	Interval *durationpb.Duration `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	// Whether the job is running right now.
+	Running bool `protobuf:"varint,3,opt,name=running,proto3" json:"running,omitempty"`
+	// The number of finished runs.
+	Runs uint32 `protobuf:"varint,4,opt,name=runs,proto3" json:"runs,omitempty"`
+	// The number of finished runs that failed.
+	Failures uint32 `protobuf:"varint,5,opt,name=failures,proto3" json:"failures,omitempty"`
+	// The total duration of all finished runs.
+	TotalDuration *durationpb.Duration `protobuf:"bytes,6,opt,name=total_duration,json=totalDuration,proto3" json:"total_duration,omitempty"`
+	// The time the last run started at. Unset if the job hasn't run yet.
+	LastRunAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_run_at,json=lastRunAt,proto3" json:"last_run_at,omitempty"`
+	// The duration of the last finished run.
+	LastDuration *durationpb.Duration `protobuf:"bytes,8,opt,name=last_duration,json=lastDuration,proto3" json:"last_duration,omitempty"`
+	// The error of the last finished run, if it failed.
+	LastError string `protobuf:"bytes,9,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	// The time the next run is scheduled for. Unset while the job is running.
+	NextRunAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=next_run_at,json=nextRunAt,proto3" json:"next_run_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackgroundJob) Reset() {
+	*x = BackgroundJob{}
+	mi := &file_todo_v1_todo_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackgroundJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackgroundJob) ProtoMessage() {}
+
+func (x *BackgroundJob) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackgroundJob.ProtoReflect.Descriptor instead.
+func (*BackgroundJob) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *BackgroundJob) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BackgroundJob) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *BackgroundJob) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *BackgroundJob) GetRuns() uint32 {
+	if x != nil {
+		return x.Runs
+	}
+	return 0
+}
+
+func (x *BackgroundJob) GetFailures() uint32 {
+	if x != nil {
+		return x.Failures
+	}
+	return 0
+}
+
+func (x *BackgroundJob) GetTotalDuration() *durationpb.Duration {
+	if x != nil {
+		return x.TotalDuration
+	}
+	return nil
+}
+
+func (x *BackgroundJob) GetLastRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastRunAt
+	}
+	return nil
+}
+
+func (x *BackgroundJob) GetLastDuration() *durationpb.Duration {
+	if x != nil {
+		return x.LastDuration
+	}
+	return nil
+}
+
+func (x *BackgroundJob) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *BackgroundJob) GetNextRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRunAt
+	}
+	return nil
+}
+
+type TailLogsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The minimum level of the messages to stream: "debug", "info", "warn", or
+	// "error". Empty means all messages.
+	MinLevel string `protobuf:"bytes,1,opt,name=min_level,json=minLevel,proto3" json:"min_level,omitempty"`
+	// The maximum number of recent messages to stream first. 0 means all
+	// messages kept by the server.
+	Lines uint32 `protobuf:"varint,2,opt,name=lines,proto3" json:"lines,omitempty"`
+	// Whether to keep streaming the messages logged after the call was made,
+	// until the call is canceled.
+	Follow        bool `protobuf:"varint,3,opt,name=follow,proto3" json:"follow,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TailLogsRequest) Reset() {
+	*x = TailLogsRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TailLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TailLogsRequest) ProtoMessage() {}
+
+func (x *TailLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TailLogsRequest.ProtoReflect.Descriptor instead.
+func (*TailLogsRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *TailLogsRequest) GetMinLevel() string {
+	if x != nil {
+		return x.MinLevel
+	}
+	return ""
+}
+
+func (x *TailLogsRequest) GetLines() uint32 {
+	if x != nil {
+		return x.Lines
+	}
+	return 0
+}
+
+func (x *TailLogsRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+// A log message of the To-do Daemon server.
+type LogEntry struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The time the message was logged.
+	Time *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	// The level of the message, e.g. "WARN".
+	Level string `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	// The message formatted like in the server's log output.
+	Line          string `protobuf:"bytes,3,opt,name=line,proto3" json:"line,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogEntry) Reset() {
+	*x = LogEntry{}
+	mi := &file_todo_v1_todo_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogEntry) ProtoMessage() {}
+
+func (x *LogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogEntry.ProtoReflect.Descriptor instead.
+func (*LogEntry) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *LogEntry) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *LogEntry) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *LogEntry) GetLine() string {
+	if x != nil {
+		return x.Line
+	}
+	return ""
+}
+
+type DeleteTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The ID of the task to delete.
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTaskRequest) Reset() {
+	*x = DeleteTaskRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTaskRequest) ProtoMessage() {}
+
+func (x *DeleteTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTaskRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *DeleteTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTaskResponse) Reset() {
+	*x = DeleteTaskResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTaskResponse) ProtoMessage() {}
+
+func (x *DeleteTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTaskResponse.ProtoReflect.Descriptor instead.
+func (*DeleteTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{47}
+}
+
+type RestoreTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The ID of the task to restore.
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreTaskRequest) Reset() {
+	*x = RestoreTaskRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreTaskRequest) ProtoMessage() {}
+
+func (x *RestoreTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreTaskRequest.ProtoReflect.Descriptor instead.
+func (*RestoreTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *RestoreTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RestoreTaskResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The restored task.
+	Task          *Task `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreTaskResponse) Reset() {
+	*x = RestoreTaskResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreTaskResponse) ProtoMessage() {}
+
+func (x *RestoreTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreTaskResponse.ProtoReflect.Descriptor instead.
+func (*RestoreTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *RestoreTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type DuplicateTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The ID of the task to copy.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The number of copies to create, at most 100. Zero means one copy.
+	Count uint32 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	// The text appended to the summary of each copy, e.g. " (copy)".
+	SummarySuffix string `protobuf:"bytes,3,opt,name=summary_suffix,json=summarySuffix,proto3" json:"summary_suffix,omitempty"`
+	// If set, the due time of the first copy is this long after the due time
+	// of the task, and each further copy is due this long after the previous
+	// one. Otherwise, all copies are due at the same time as the task.
+	DueShift      *durationpb.Duration `protobuf:"bytes,4,opt,name=due_shift,json=dueShift,proto3" json:"due_shift,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DuplicateTaskRequest) Reset() {
+	*x = DuplicateTaskRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DuplicateTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DuplicateTaskRequest) ProtoMessage() {}
+
+func (x *DuplicateTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DuplicateTaskRequest.ProtoReflect.Descriptor instead.
+func (*DuplicateTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *DuplicateTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DuplicateTaskRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *DuplicateTaskRequest) GetSummarySuffix() string {
+	if x != nil {
+		return x.SummarySuffix
+	}
+	return ""
+}
+
+func (x *DuplicateTaskRequest) GetDueShift() *durationpb.Duration {
+	if x != nil {
+		return x.DueShift
+	}
+	return nil
+}
+
+type DuplicateTaskResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The created copies, in the order of their due times.
+	Tasks         []*Task `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DuplicateTaskResponse) Reset() {
+	*x = DuplicateTaskResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DuplicateTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DuplicateTaskResponse) ProtoMessage() {}
+
+func (x *DuplicateTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DuplicateTaskResponse.ProtoReflect.Descriptor instead.
+func (*DuplicateTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *DuplicateTaskResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type PurgeCompletedRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// If set, only the tasks that were completed at least this long ago are
+	// removed.
+	OlderThan *durationpb.Duration `protobuf:"bytes,1,opt,name=older_than,json=olderThan,proto3" json:"older_than,omitempty"`
+	// Whether to return an archive of the removed tasks.
+	Archive       bool `protobuf:"varint,2,opt,name=archive,proto3" json:"archive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeCompletedRequest) Reset() {
+	*x = PurgeCompletedRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeCompletedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeCompletedRequest) ProtoMessage() {}
+
+func (x *PurgeCompletedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeCompletedRequest.ProtoReflect.Descriptor instead.
+func (*PurgeCompletedRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *PurgeCompletedRequest) GetOlderThan() *durationpb.Duration {
+	if x != nil {
+		return x.OlderThan
+	}
+	return nil
+}
+
+func (x *PurgeCompletedRequest) GetArchive() bool {
+	if x != nil {
+		return x.Archive
+	}
+	return false
+}
+
+type PurgeCompletedResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The removed tasks, as they were before their removal.
+	Tasks []*Task `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	// If requested, a snapshot of the removed tasks in the format of
+	// CreateBackup.
+	Archive       []byte `protobuf:"bytes,2,opt,name=archive,proto3" json:"archive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeCompletedResponse) Reset() {
+	*x = PurgeCompletedResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeCompletedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeCompletedResponse) ProtoMessage() {}
+
+func (x *PurgeCompletedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeCompletedResponse.ProtoReflect.Descriptor instead.
+func (*PurgeCompletedResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *PurgeCompletedResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *PurgeCompletedResponse) GetArchive() []byte {
+	if x != nil {
+		return x.Archive
+	}
+	return nil
+}
+
+type PullChangesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// If set, only the tasks changed after this time are returned. Otherwise,
+	// all tasks are returned.
+	Since         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PullChangesRequest) Reset() {
+	*x = PullChangesRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullChangesRequest) ProtoMessage() {}
+
+func (x *PullChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullChangesRequest.ProtoReflect.Descriptor instead.
+func (*PullChangesRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *PullChangesRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+type PullChangesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The changed tasks, including the deleted ones.
+	Changes []*TaskChange `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	// The time up to which the changes are included, according to the clock of
+	// the server. Pass it as since to pull the next changes.
+	Time          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PullChangesResponse) Reset() {
+	*x = PullChangesResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullChangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullChangesResponse) ProtoMessage() {}
+
+func (x *PullChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullChangesResponse.ProtoReflect.Descriptor instead.
+func (*PullChangesResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *PullChangesResponse) GetChanges() []*TaskChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *PullChangesResponse) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+// The state of a task after its last change, for synchronizing two to-do
+// lists.
+type TaskChange struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The task. Its uid identifies it in both to-do lists; its id, position,
+	// version, and depends_on only apply to the to-do list it was pulled from.
+	Task *Task `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	// The time when the task was deleted, if it was.
+	DeletedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	// The UIDs of the tasks that must be completed before this task.
+	DependsOnUids []string `protobuf:"bytes,3,rep,name=depends_on_uids,json=dependsOnUids,proto3" json:"depends_on_uids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskChange) Reset() {
+	*x = TaskChange{}
+	mi := &file_todo_v1_todo_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskChange) ProtoMessage() {}
+
+func (x *TaskChange) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskChange.ProtoReflect.Descriptor instead.
+func (*TaskChange) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *TaskChange) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *TaskChange) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *TaskChange) GetDependsOnUids() []string {
+	if x != nil {
+		return x.DependsOnUids
+	}
+	return nil
+}
+
+type PushChangesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The changes pulled from the other to-do list.
+	Changes []*TaskChange `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	// The time of the last synchronization with the other to-do list according
+	// to the clock of this server, i.e. the time returned by its last
+	// PullChanges. Tasks changed in both to-do lists since then are reported as
+	// conflicts. If unset, all differing tasks are conflicts.
+	Since         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushChangesRequest) Reset() {
+	*x = PushChangesRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushChangesRequest) ProtoMessage() {}
+
+func (x *PushChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushChangesRequest.ProtoReflect.Descriptor instead.
+func (*PushChangesRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *PushChangesRequest) GetChanges() []*TaskChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *PushChangesRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+type PushChangesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The number of tasks that were created, updated, or deleted.
+	AppliedCount uint32 `protobuf:"varint,1,opt,name=applied_count,json=appliedCount,proto3" json:"applied_count,omitempty"`
+	// The tasks that were changed in both to-do lists, along with how the
+	// conflicts were resolved.
+	Conflicts     []*SyncConflict `protobuf:"bytes,2,rep,name=conflicts,proto3" json:"conflicts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushChangesResponse) Reset() {
+	*x = PushChangesResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushChangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushChangesResponse) ProtoMessage() {}
+
+func (x *PushChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushChangesResponse.ProtoReflect.Descriptor instead.
+func (*PushChangesResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *PushChangesResponse) GetAppliedCount() uint32 {
+	if x != nil {
+		return x.AppliedCount
+	}
+	return 0
+}
+
+func (x *PushChangesResponse) GetConflicts() []*SyncConflict {
+	if x != nil {
+		return x.Conflicts
+	}
+	return nil
+}
+
+// A task that was changed in both of two synchronized to-do lists.
+type SyncConflict struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The task after resolving the conflict.
+	Task       *Task                   `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	Resolution SyncConflict_Resolution `protobuf:"varint,2,opt,name=resolution,proto3,enum=todo.v1.SyncConflict_Resolution" json:"resolution,omitempty"`
+	// The time of the change in this to-do list.
+	LocalChangedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=local_changed_at,json=localChangedAt,proto3" json:"local_changed_at,omitempty"`
+	// The time of the change in the other to-do list.
+	RemoteChangedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=remote_changed_at,json=remoteChangedAt,proto3" json:"remote_changed_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SyncConflict) Reset() {
+	*x = SyncConflict{}
+	mi := &file_todo_v1_todo_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncConflict) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncConflict) ProtoMessage() {}
+
+func (x *SyncConflict) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncConflict.ProtoReflect.Descriptor instead.
+func (*SyncConflict) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *SyncConflict) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *SyncConflict) GetResolution() SyncConflict_Resolution {
+	if x != nil {
+		return x.Resolution
+	}
+	return SyncConflict_RESOLUTION_UNSPECIFIED
+}
+
+func (x *SyncConflict) GetLocalChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LocalChangedAt
+	}
+	return nil
+}
+
+func (x *SyncConflict) GetRemoteChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RemoteChangedAt
+	}
+	return nil
+}
+
+// A named set of criteria for selecting tasks, also known as a smart list.
+type Filter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The unique name of the filter, e.g. "urgent-work". It consists of letters,
+	// digits, underscores, and hyphens, and starts with a letter or digit.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Selects the tasks by their completion state.
+	Completion ListTasksRequest_Completion `protobuf:"varint,2,opt,name=completion,proto3,enum=todo.v1.ListTasksRequest_Completion" json:"completion,omitempty"`
+	// If set, only the tasks having all of these tags are selected.
+	Tags []string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
+	// If set, only the tasks of this project are selected.
+	Project string `protobuf:"bytes,4,opt,name=project,proto3" json:"project,omitempty"`
+	// Selects the tasks by their due time.
+	Due Filter_Due `protobuf:"varint,5,opt,name=due,proto3,enum=todo.v1.Filter_Due" json:"due,omitempty"`
+	// If true, only the starred tasks are selected.
+	Starred bool `protobuf:"varint,6,opt,name=starred,proto3" json:"starred,omitempty"`
+	// Output only. Whether the filter is defined in the configuration file.
+	Configured    bool `protobuf:"varint,7,opt,name=configured,proto3" json:"configured,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Filter) Reset() {
+	*x = Filter{}
+	mi := &file_todo_v1_todo_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Filter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Filter) ProtoMessage() {}
+
+func (x *Filter) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Filter.ProtoReflect.Descriptor instead.
+func (*Filter) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *Filter) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Filter) GetCompletion() ListTasksRequest_Completion {
+	if x != nil {
+		return x.Completion
+	}
+	return ListTasksRequest_COMPLETION_UNSPECIFIED
+}
+
+func (x *Filter) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Filter) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *Filter) GetDue() Filter_Due {
+	if x != nil {
+		return x.Due
+	}
+	return Filter_DUE_UNSPECIFIED
+}
+
+func (x *Filter) GetStarred() bool {
+	if x != nil {
+		return x.Starred
+	}
+	return false
+}
+
+func (x *Filter) GetConfigured() bool {
+	if x != nil {
+		return x.Configured
+	}
+	return false
+}
+
+type ListFiltersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFiltersRequest) Reset() {
+	*x = ListFiltersRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFiltersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFiltersRequest) ProtoMessage() {}
+
+func (x *ListFiltersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFiltersRequest.ProtoReflect.Descriptor instead.
+func (*ListFiltersRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{61}
+}
+
+type ListFiltersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The filters, ordered by name.
+	Filters       []*Filter `protobuf:"bytes,1,rep,name=filters,proto3" json:"filters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFiltersResponse) Reset() {
+	*x = ListFiltersResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFiltersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFiltersResponse) ProtoMessage() {}
+
+func (x *ListFiltersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFiltersResponse.ProtoReflect.Descriptor instead.
+func (*ListFiltersResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *ListFiltersResponse) GetFilters() []*Filter {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+type CreateFilterRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The filter to save.
+	Filter        *Filter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFilterRequest) Reset() {
+	*x = CreateFilterRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFilterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFilterRequest) ProtoMessage() {}
+
+func (x *CreateFilterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFilterRequest.ProtoReflect.Descriptor instead.
+func (*CreateFilterRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *CreateFilterRequest) GetFilter() *Filter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type CreateFilterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *Filter                `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFilterResponse) Reset() {
+	*x = CreateFilterResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFilterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFilterResponse) ProtoMessage() {}
+
+func (x *CreateFilterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFilterResponse.ProtoReflect.Descriptor instead.
+func (*CreateFilterResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *CreateFilterResponse) GetFilter() *Filter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type DeleteFilterRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the filter to delete.
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFilterRequest) Reset() {
+	*x = DeleteFilterRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFilterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFilterRequest) ProtoMessage() {}
+
+func (x *DeleteFilterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFilterRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFilterRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *DeleteFilterRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteFilterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFilterResponse) Reset() {
+	*x = DeleteFilterResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFilterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFilterResponse) ProtoMessage() {}
+
+func (x *DeleteFilterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFilterResponse.ProtoReflect.Descriptor instead.
+func (*DeleteFilterResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{66}
+}
+
+// A task to be created from a template.
+type TemplateTask struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The initial summary of the task.
-	Summary       string `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"`
+	// The summary of the task.
+	Summary string `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"`
+	// The description of the task.
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// The tags of the task.
+	Tags []string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
+	// The project the task belongs to, if any.
+	Project string `protobuf:"bytes,4,opt,name=project,proto3" json:"project,omitempty"`
+	// Whether the task is starred.
+	Starred bool `protobuf:"varint,5,opt,name=starred,proto3" json:"starred,omitempty"`
+	// The time between creating the task and its due time. If unset, the task
+	// has no due time.
+	DueAfter      *durationpb.Duration `protobuf:"bytes,6,opt,name=due_after,json=dueAfter,proto3" json:"due_after,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NewTask) Reset() {
-	*x = NewTask{}
-	mi := &file_todo_v1_todo_proto_msgTypes[3]
+func (x *TemplateTask) Reset() {
+	*x = TemplateTask{}
+	mi := &file_todo_v1_todo_proto_msgTypes[67]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NewTask) String() string {
+func (x *TemplateTask) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NewTask) ProtoMessage() {}
+func (*TemplateTask) ProtoMessage() {}
 
-func (x *NewTask) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_v1_todo_proto_msgTypes[3]
+func (x *TemplateTask) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[67]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -225,44 +4643,89 @@ func (x *NewTask) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NewTask.ProtoReflect.Descriptor instead.
-func (*NewTask) Descriptor() ([]byte, []int) {
-	return file_todo_v1_todo_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use TemplateTask.ProtoReflect.Descriptor instead.
+func (*TemplateTask) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{67}
 }
 
-func (x *NewTask) GetSummary() string {
+func (x *TemplateTask) GetSummary() string {
 	if x != nil {
 		return x.Summary
 	}
 	return ""
 }
 
-// The changes to apply to an existing task in the to-do list.
-type TaskUpdate struct {
+func (x *TemplateTask) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TemplateTask) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *TemplateTask) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *TemplateTask) GetStarred() bool {
+	if x != nil {
+		return x.Starred
+	}
+	return false
+}
+
+func (x *TemplateTask) GetDueAfter() *durationpb.Duration {
+	if x != nil {
+		return x.DueAfter
+	}
+	return nil
+}
+
+// A named set of tasks that are created together, e.g. the items of a weekly
+// review checklist.
+type Template struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The new summary to assign to the task.
-	Summary string `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"`
-	// The completion timestamp to assign to the task.
-	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	// The unique name of the template, e.g. "weekly-review". It consists of
+	// letters, digits, underscores, and hyphens, and starts with a letter or
+	// digit.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The tasks to create, in this order.
+	Tasks []*TemplateTask `protobuf:"bytes,2,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	// The rule that the template is applied by, if any, e.g.
+	// "FREQ=WEEKLY;BYDAY=FR". The server applies the template at the start of
+	// each day that the rule selects.
+	Schedule string `protobuf:"bytes,3,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	// Output only. The time when the server applies the scheduled template
+	// next.
+	NextRunAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=next_run_at,json=nextRunAt,proto3" json:"next_run_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TaskUpdate) Reset() {
-	*x = TaskUpdate{}
-	mi := &file_todo_v1_todo_proto_msgTypes[4]
+func (x *Template) Reset() {
+	*x = Template{}
+	mi := &file_todo_v1_todo_proto_msgTypes[68]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TaskUpdate) String() string {
+func (x *Template) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TaskUpdate) ProtoMessage() {}
+func (*Template) ProtoMessage() {}
 
-func (x *TaskUpdate) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_v1_todo_proto_msgTypes[4]
+func (x *Template) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[68]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -273,48 +4736,60 @@ func (x *TaskUpdate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TaskUpdate.ProtoReflect.Descriptor instead.
-func (*TaskUpdate) Descriptor() ([]byte, []int) {
-	return file_todo_v1_todo_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use Template.ProtoReflect.Descriptor instead.
+func (*Template) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{68}
 }
 
-func (x *TaskUpdate) GetSummary() string {
+func (x *Template) GetName() string {
 	if x != nil {
-		return x.Summary
+		return x.Name
 	}
 	return ""
 }
 
-func (x *TaskUpdate) GetCompletedAt() *timestamppb.Timestamp {
+func (x *Template) GetTasks() []*TemplateTask {
 	if x != nil {
-		return x.CompletedAt
+		return x.Tasks
 	}
 	return nil
 }
 
-type CreateTaskRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// The task to create.
-	Task          *NewTask `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+func (x *Template) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+func (x *Template) GetNextRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRunAt
+	}
+	return nil
+}
+
+type ListTemplatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateTaskRequest) Reset() {
-	*x = CreateTaskRequest{}
-	mi := &file_todo_v1_todo_proto_msgTypes[5]
+func (x *ListTemplatesRequest) Reset() {
+	*x = ListTemplatesRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[69]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateTaskRequest) String() string {
+func (x *ListTemplatesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateTaskRequest) ProtoMessage() {}
+func (*ListTemplatesRequest) ProtoMessage() {}
 
-func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_v1_todo_proto_msgTypes[5]
+func (x *ListTemplatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[69]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -325,41 +4800,34 @@ func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateTaskRequest.ProtoReflect.Descriptor instead.
-func (*CreateTaskRequest) Descriptor() ([]byte, []int) {
-	return file_todo_v1_todo_proto_rawDescGZIP(), []int{5}
-}
-
-func (x *CreateTaskRequest) GetTask() *NewTask {
-	if x != nil {
-		return x.Task
-	}
-	return nil
+// Deprecated: Use ListTemplatesRequest.ProtoReflect.Descriptor instead.
+func (*ListTemplatesRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{69}
 }
 
-type CreateTaskResponse struct {
+type ListTemplatesResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The task that was created.
-	Task          *Task `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	// The templates, ordered by name.
+	Templates     []*Template `protobuf:"bytes,1,rep,name=templates,proto3" json:"templates,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateTaskResponse) Reset() {
-	*x = CreateTaskResponse{}
-	mi := &file_todo_v1_todo_proto_msgTypes[6]
+func (x *ListTemplatesResponse) Reset() {
+	*x = ListTemplatesResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[70]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateTaskResponse) String() string {
+func (x *ListTemplatesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateTaskResponse) ProtoMessage() {}
+func (*ListTemplatesResponse) ProtoMessage() {}
 
-func (x *CreateTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_v1_todo_proto_msgTypes[6]
+func (x *ListTemplatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[70]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -370,39 +4838,41 @@ func (x *CreateTaskResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateTaskResponse.ProtoReflect.Descriptor instead.
-func (*CreateTaskResponse) Descriptor() ([]byte, []int) {
-	return file_todo_v1_todo_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ListTemplatesResponse.ProtoReflect.Descriptor instead.
+func (*ListTemplatesResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{70}
 }
 
-func (x *CreateTaskResponse) GetTask() *Task {
+func (x *ListTemplatesResponse) GetTemplates() []*Template {
 	if x != nil {
-		return x.Task
+		return x.Templates
 	}
 	return nil
 }
 
-type ListTasksRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+type CreateTemplateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The template to save.
+	Template      *Template `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListTasksRequest) Reset() {
-	*x = ListTasksRequest{}
-	mi := &file_todo_v1_todo_proto_msgTypes[7]
+func (x *CreateTemplateRequest) Reset() {
+	*x = CreateTemplateRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListTasksRequest) String() string {
+func (x *CreateTemplateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListTasksRequest) ProtoMessage() {}
+func (*CreateTemplateRequest) ProtoMessage() {}
 
-func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_v1_todo_proto_msgTypes[7]
+func (x *CreateTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -413,34 +4883,41 @@ func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
-func (*ListTasksRequest) Descriptor() ([]byte, []int) {
-	return file_todo_v1_todo_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use CreateTemplateRequest.ProtoReflect.Descriptor instead.
+func (*CreateTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{71}
 }
 
-type ListTasksResponse struct {
+func (x *CreateTemplateRequest) GetTemplate() *Template {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+type CreateTemplateResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The tasks available in the to-do list.
-	Tasks         []*Task `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	// The saved template.
+	Template      *Template `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListTasksResponse) Reset() {
-	*x = ListTasksResponse{}
-	mi := &file_todo_v1_todo_proto_msgTypes[8]
+func (x *CreateTemplateResponse) Reset() {
+	*x = CreateTemplateResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListTasksResponse) String() string {
+func (x *CreateTemplateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListTasksResponse) ProtoMessage() {}
+func (*CreateTemplateResponse) ProtoMessage() {}
 
-func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_v1_todo_proto_msgTypes[8]
+func (x *CreateTemplateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -451,45 +4928,41 @@ func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListTasksResponse.ProtoReflect.Descriptor instead.
-func (*ListTasksResponse) Descriptor() ([]byte, []int) {
-	return file_todo_v1_todo_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use CreateTemplateResponse.ProtoReflect.Descriptor instead.
+func (*CreateTemplateResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{72}
 }
 
-func (x *ListTasksResponse) GetTasks() []*Task {
+func (x *CreateTemplateResponse) GetTemplate() *Template {
 	if x != nil {
-		return x.Tasks
+		return x.Template
 	}
 	return nil
 }
 
-type UpdateTaskRequest struct {
+type DeleteTemplateRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The ID of the task to update.
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// The changes to apply to the task's fields.
-	Update *TaskUpdate `protobuf:"bytes,2,opt,name=update,proto3" json:"update,omitempty"`
-	// The fields of the task to be updated.
-	Fields        *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=fields,proto3" json:"fields,omitempty"`
+	// The name of the template to delete.
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateTaskRequest) Reset() {
-	*x = UpdateTaskRequest{}
-	mi := &file_todo_v1_todo_proto_msgTypes[9]
+func (x *DeleteTemplateRequest) Reset() {
+	*x = DeleteTemplateRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateTaskRequest) String() string {
+func (x *DeleteTemplateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateTaskRequest) ProtoMessage() {}
+func (*DeleteTemplateRequest) ProtoMessage() {}
 
-func (x *UpdateTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_v1_todo_proto_msgTypes[9]
+func (x *DeleteTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -500,55 +4973,39 @@ func (x *UpdateTaskRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateTaskRequest.ProtoReflect.Descriptor instead.
-func (*UpdateTaskRequest) Descriptor() ([]byte, []int) {
-	return file_todo_v1_todo_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use DeleteTemplateRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{73}
 }
 
-func (x *UpdateTaskRequest) GetId() string {
+func (x *DeleteTemplateRequest) GetName() string {
 	if x != nil {
-		return x.Id
+		return x.Name
 	}
 	return ""
 }
 
-func (x *UpdateTaskRequest) GetUpdate() *TaskUpdate {
-	if x != nil {
-		return x.Update
-	}
-	return nil
-}
-
-func (x *UpdateTaskRequest) GetFields() *fieldmaskpb.FieldMask {
-	if x != nil {
-		return x.Fields
-	}
-	return nil
-}
-
-type UpdateTaskResponse struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// The task after applying the update.
-	Task          *Task `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+type DeleteTemplateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateTaskResponse) Reset() {
-	*x = UpdateTaskResponse{}
-	mi := &file_todo_v1_todo_proto_msgTypes[10]
+func (x *DeleteTemplateResponse) Reset() {
+	*x = DeleteTemplateResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[74]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateTaskResponse) String() string {
+func (x *DeleteTemplateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateTaskResponse) ProtoMessage() {}
+func (*DeleteTemplateResponse) ProtoMessage() {}
 
-func (x *UpdateTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_v1_todo_proto_msgTypes[10]
+func (x *DeleteTemplateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -559,41 +5016,34 @@ func (x *UpdateTaskResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateTaskResponse.ProtoReflect.Descriptor instead.
-func (*UpdateTaskResponse) Descriptor() ([]byte, []int) {
-	return file_todo_v1_todo_proto_rawDescGZIP(), []int{10}
-}
-
-func (x *UpdateTaskResponse) GetTask() *Task {
-	if x != nil {
-		return x.Task
-	}
-	return nil
+// Deprecated: Use DeleteTemplateResponse.ProtoReflect.Descriptor instead.
+func (*DeleteTemplateResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{74}
 }
 
-type DeleteTaskRequest struct {
+type ApplyTemplateRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The ID of the task to delete.
-	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The name of the template to apply.
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteTaskRequest) Reset() {
-	*x = DeleteTaskRequest{}
-	mi := &file_todo_v1_todo_proto_msgTypes[11]
+func (x *ApplyTemplateRequest) Reset() {
+	*x = ApplyTemplateRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteTaskRequest) String() string {
+func (x *ApplyTemplateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteTaskRequest) ProtoMessage() {}
+func (*ApplyTemplateRequest) ProtoMessage() {}
 
-func (x *DeleteTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_v1_todo_proto_msgTypes[11]
+func (x *ApplyTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -604,39 +5054,41 @@ func (x *DeleteTaskRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteTaskRequest.ProtoReflect.Descriptor instead.
-func (*DeleteTaskRequest) Descriptor() ([]byte, []int) {
-	return file_todo_v1_todo_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use ApplyTemplateRequest.ProtoReflect.Descriptor instead.
+func (*ApplyTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{75}
 }
 
-func (x *DeleteTaskRequest) GetId() string {
+func (x *ApplyTemplateRequest) GetName() string {
 	if x != nil {
-		return x.Id
+		return x.Name
 	}
 	return ""
 }
 
-type DeleteTaskResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+type ApplyTemplateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The created tasks, in the order of the template's tasks.
+	Tasks         []*Task `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteTaskResponse) Reset() {
-	*x = DeleteTaskResponse{}
-	mi := &file_todo_v1_todo_proto_msgTypes[12]
+func (x *ApplyTemplateResponse) Reset() {
+	*x = ApplyTemplateResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteTaskResponse) String() string {
+func (x *ApplyTemplateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteTaskResponse) ProtoMessage() {}
+func (*ApplyTemplateResponse) ProtoMessage() {}
 
-func (x *DeleteTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_v1_todo_proto_msgTypes[12]
+func (x *ApplyTemplateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -647,21 +5099,63 @@ func (x *DeleteTaskResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteTaskResponse.ProtoReflect.Descriptor instead.
-func (*DeleteTaskResponse) Descriptor() ([]byte, []int) {
-	return file_todo_v1_todo_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use ApplyTemplateResponse.ProtoReflect.Descriptor instead.
+func (*ApplyTemplateResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *ApplyTemplateResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
 }
 
 var File_todo_v1_todo_proto protoreflect.FileDescriptor
 
 const file_todo_v1_todo_proto_rawDesc = "" +
 	"\n" +
-	"\x12todo/v1/todo.proto\x12\atodo.v1\x1a\x1cgoogle/api/annotations.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x0f\n" +
-	"\rStatusRequest\"D\n" +
+	"\x12todo/v1/todo.proto\x12\atodo.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1egoogle/protobuf/duration.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x0f\n" +
+	"\rStatusRequest\"\xe0\x03\n" +
 	"\x0eStatusResponse\x12\x10\n" +
 	"\x03pid\x18\x01 \x01(\rR\x03pid\x12 \n" +
 	"\fapi_base_url\x18\x02 \x01(\tR\n" +
-	"apiBaseUrl\"\xe5\x01\n" +
+	"apiBaseUrl\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x121\n" +
+	"\x06uptime\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x06uptime\x12'\n" +
+	"\x0fstorage_backend\x18\x05 \x01(\tR\x0estorageBackend\x12\x1d\n" +
+	"\n" +
+	"task_count\x18\x06 \x01(\rR\ttaskCount\x12%\n" +
+	"\x0esocket_address\x18\a \x01(\tR\rsocketAddress\x12!\n" +
+	"\fhttp_address\x18\b \x01(\tR\vhttpAddress\x12,\n" +
+	"\x12min_client_version\x18\t \x01(\tR\x10minClientVersion\x12+\n" +
+	"\x11event_subscribers\x18\n" +
+	" \x01(\rR\x10eventSubscribers\x12%\n" +
+	"\x0edropped_events\x18\v \x01(\x04R\rdroppedEvents\x129\n" +
+	"\x18disconnected_subscribers\x18\f \x01(\x04R\x17disconnectedSubscribers\"\x18\n" +
+	"\x16GetCapabilitiesRequest\"\xc7\x01\n" +
+	"\x17GetCapabilitiesResponse\x12\x1a\n" +
+	"\bfeatures\x18\x01 \x03(\tR\bfeatures\x12'\n" +
+	"\x06limits\x18\x02 \x01(\v2\x0f.todo.v1.LimitsR\x06limits\x12!\n" +
+	"\fapi_versions\x18\x03 \x03(\tR\vapiVersions\x12'\n" +
+	"\x0fstorage_backend\x18\x04 \x01(\tR\x0estorageBackend\x12\x1b\n" +
+	"\tread_only\x18\x05 \x01(\bR\breadOnly\"\xc0\x04\n" +
+	"\x06Limits\x12,\n" +
+	"\x12max_summary_length\x18\x01 \x01(\rR\x10maxSummaryLength\x124\n" +
+	"\x16max_description_length\x18\x02 \x01(\rR\x14maxDescriptionLength\x12,\n" +
+	"\x12max_project_length\x18\x03 \x01(\rR\x10maxProjectLength\x12$\n" +
+	"\x0emax_tag_length\x18\x04 \x01(\rR\fmaxTagLength\x12\x19\n" +
+	"\bmax_tags\x18\x05 \x01(\rR\amaxTags\x12&\n" +
+	"\x0fmax_name_length\x18\x06 \x01(\rR\rmaxNameLength\x12,\n" +
+	"\x12max_template_tasks\x18\a \x01(\rR\x10maxTemplateTasks\x12*\n" +
+	"\x11default_page_size\x18\b \x01(\rR\x0fdefaultPageSize\x12\"\n" +
+	"\rmax_page_size\x18\t \x01(\rR\vmaxPageSize\x12.\n" +
+	"\x13max_assignee_length\x18\n" +
+	" \x01(\rR\x11maxAssigneeLength\x12\"\n" +
+	"\rmax_body_size\x18\v \x01(\rR\vmaxBodySize\x12\x1b\n" +
+	"\tmax_links\x18\f \x01(\rR\bmaxLinks\x12&\n" +
+	"\x0fmax_link_length\x18\r \x01(\rR\rmaxLinkLength\x12$\n" +
+	"\x0emax_open_tasks\x18\x0e \x01(\rR\fmaxOpenTasks\"\xf0\x05\n" +
 	"\x04Task\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
 	"\asummary\x18\x02 \x01(\tR\asummary\x129\n" +
@@ -669,38 +5163,362 @@ const file_todo_v1_todo_proto_rawDesc = "" +
 	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
 	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12=\n" +
-	"\fcompleted_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\"#\n" +
+	"\fcompleted_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x121\n" +
+	"\x06due_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x05dueAt\x12\x18\n" +
+	"\aversion\x18\b \x01(\x04R\aversion\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\t \x01(\tR\tshortCode\x12\x12\n" +
+	"\x04tags\x18\n" +
+	" \x03(\tR\x04tags\x12\x18\n" +
+	"\aproject\x18\v \x01(\tR\aproject\x12\x1a\n" +
+	"\bposition\x18\f \x01(\x03R\bposition\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18\r \x03(\tR\tdependsOn\x12\x1d\n" +
+	"\n" +
+	"blocked_by\x18\x0e \x03(\tR\tblockedBy\x12\x18\n" +
+	"\ablocked\x18\x0f \x01(\bR\ablocked\x12\x1e\n" +
+	"\n" +
+	"recurrence\x18\x10 \x01(\tR\n" +
+	"recurrence\x12\x1b\n" +
+	"\ttime_zone\x18\x11 \x01(\tR\btimeZone\x12 \n" +
+	"\fdue_at_local\x18\x12 \x01(\tR\n" +
+	"dueAtLocal\x12\x18\n" +
+	"\astarred\x18\x13 \x01(\bR\astarred\x12\x10\n" +
+	"\x03uid\x18\x14 \x01(\tR\x03uid\x12\x1a\n" +
+	"\bassignee\x18\x15 \x01(\tR\bassignee\x12\x1c\n" +
+	"\tcompleted\x18\x16 \x01(\bR\tcompleted\x12\x14\n" +
+	"\x05links\x18\x17 \x03(\tR\x05links\"\xf7\x02\n" +
 	"\aNewTask\x12\x18\n" +
-	"\asummary\x18\x01 \x01(\tR\asummary\"e\n" +
+	"\asummary\x18\x01 \x01(\tR\asummary\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x121\n" +
+	"\x06due_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05dueAt\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\x12\x18\n" +
+	"\aproject\x18\x05 \x01(\tR\aproject\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18\x06 \x03(\tR\tdependsOn\x12\x1e\n" +
+	"\n" +
+	"recurrence\x18\a \x01(\tR\n" +
+	"recurrence\x12\x1b\n" +
+	"\ttime_zone\x18\b \x01(\tR\btimeZone\x12\x18\n" +
+	"\astarred\x18\t \x01(\bR\astarred\x12\x1a\n" +
+	"\bassignee\x18\n" +
+	" \x01(\tR\bassignee\x12\x14\n" +
+	"\x05links\x18\v \x03(\tR\x05links\x12'\n" +
+	"\x0fallow_duplicate\x18\f \x01(\bR\x0eallowDuplicate\"\x90\x03\n" +
 	"\n" +
 	"TaskUpdate\x12\x18\n" +
 	"\asummary\x18\x01 \x01(\tR\asummary\x12=\n" +
-	"\fcompleted_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\"9\n" +
+	"\fcompleted_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x121\n" +
+	"\x06due_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x05dueAt\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x12\x18\n" +
+	"\aproject\x18\x06 \x01(\tR\aproject\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18\a \x03(\tR\tdependsOn\x12\x1e\n" +
+	"\n" +
+	"recurrence\x18\b \x01(\tR\n" +
+	"recurrence\x12\x1b\n" +
+	"\ttime_zone\x18\t \x01(\tR\btimeZone\x12\x18\n" +
+	"\astarred\x18\n" +
+	" \x01(\bR\astarred\x12\x1a\n" +
+	"\bassignee\x18\v \x01(\tR\bassignee\x12\x14\n" +
+	"\x05links\x18\f \x03(\tR\x05links\"b\n" +
 	"\x11CreateTaskRequest\x12$\n" +
-	"\x04task\x18\x01 \x01(\v2\x10.todo.v1.NewTaskR\x04task\"7\n" +
+	"\x04task\x18\x01 \x01(\v2\x10.todo.v1.NewTaskR\x04task\x12'\n" +
+	"\x0fidempotency_key\x18\x02 \x01(\tR\x0eidempotencyKey\"7\n" +
 	"\x12CreateTaskResponse\x12!\n" +
-	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"\x12\n" +
-	"\x10ListTasksRequest\"8\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"A\n" +
+	"\x17BatchCreateTasksRequest\x12&\n" +
+	"\x05tasks\x18\x01 \x03(\v2\x10.todo.v1.NewTaskR\x05tasks\"?\n" +
+	"\x18BatchCreateTasksResponse\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\"\xb5\x01\n" +
+	"\x0eBatchOperation\x12*\n" +
+	"\x06create\x18\x01 \x01(\v2\x10.todo.v1.NewTaskH\x00R\x06create\x124\n" +
+	"\x06update\x18\x02 \x01(\v2\x1a.todo.v1.UpdateTaskRequestH\x00R\x06update\x124\n" +
+	"\x06delete\x18\x03 \x01(\v2\x1a.todo.v1.DeleteTaskRequestH\x00R\x06deleteB\v\n" +
+	"\toperation\"L\n" +
+	"\x11ApplyBatchRequest\x127\n" +
+	"\n" +
+	"operations\x18\x01 \x03(\v2\x17.todo.v1.BatchOperationR\n" +
+	"operations\"9\n" +
+	"\x12ApplyBatchResponse\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\"\xa3\x05\n" +
+	"\x10ListTasksRequest\x129\n" +
+	"\n" +
+	"due_before\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tdueBefore\x12\x18\n" +
+	"\aoverdue\x18\x02 \x01(\bR\aoverdue\x127\n" +
+	"\tdue_after\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bdueAfter\x12D\n" +
+	"\n" +
+	"completion\x18\x04 \x01(\x0e2$.todo.v1.ListTasksRequest.CompletionR\n" +
+	"completion\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x12\x18\n" +
+	"\aproject\x18\x06 \x01(\tR\aproject\x129\n" +
+	"\asort_by\x18\a \x01(\x0e2 .todo.v1.ListTasksRequest.SortByR\x06sortBy\x12\x1e\n" +
+	"\n" +
+	"descending\x18\b \x01(\bR\n" +
+	"descending\x12\x16\n" +
+	"\x06offset\x18\t \x01(\rR\x06offset\x12\x14\n" +
+	"\x05limit\x18\n" +
+	" \x01(\rR\x05limit\x12\x18\n" +
+	"\astarred\x18\v \x01(\bR\astarred\x12\x16\n" +
+	"\x06filter\x18\f \x01(\tR\x06filter\x12\x1a\n" +
+	"\bassignee\x18\r \x01(\tR\bassignee\"W\n" +
+	"\n" +
+	"Completion\x12\x1a\n" +
+	"\x16COMPLETION_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fCOMPLETION_OPEN\x10\x01\x12\x18\n" +
+	"\x14COMPLETION_COMPLETED\x10\x02\"]\n" +
+	"\x06SortBy\x12\x17\n" +
+	"\x13SORT_BY_UNSPECIFIED\x10\x00\x12\x0f\n" +
+	"\vSORT_BY_DUE\x10\x01\x12\x13\n" +
+	"\x0fSORT_BY_UPDATED\x10\x02\x12\x14\n" +
+	"\x10SORT_BY_POSITION\x10\x03\"8\n" +
 	"\x11ListTasksResponse\x12#\n" +
-	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\"\x84\x01\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\" \n" +
+	"\x0eGetTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"4\n" +
+	"\x0fGetTaskResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"&\n" +
+	"\x12ResolveTaskRequest\x12\x10\n" +
+	"\x03ref\x18\x01 \x01(\tR\x03ref\"8\n" +
+	"\x13ResolveTaskResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"\xaf\x01\n" +
 	"\x11UpdateTaskRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12+\n" +
 	"\x06update\x18\x02 \x01(\v2\x13.todo.v1.TaskUpdateR\x06update\x122\n" +
-	"\x06fields\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\x06fields\"7\n" +
+	"\x06fields\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\x06fields\x12)\n" +
+	"\x10expected_version\x18\x04 \x01(\x04R\x0fexpectedVersion\"7\n" +
 	"\x12UpdateTaskResponse\x12!\n" +
-	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"#\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"Y\n" +
+	"\x0fMoveTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tbefore_id\x18\x02 \x01(\tR\bbeforeId\x12\x19\n" +
+	"\bafter_id\x18\x03 \x01(\tR\aafterId\"5\n" +
+	"\x10MoveTaskResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"8\n" +
+	"\x12SearchTasksRequest\x12\f\n" +
+	"\x01q\x18\x01 \x01(\tR\x01q\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\rR\x05limit\"F\n" +
+	"\x13SearchTasksResponse\x12/\n" +
+	"\aresults\x18\x01 \x03(\v2\x15.todo.v1.SearchResultR\aresults\"G\n" +
+	"\fSearchResult\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\"\x11\n" +
+	"\x0fGetStatsRequest\"\x9b\x03\n" +
+	"\x10GetStatsResponse\x12\x1d\n" +
+	"\n" +
+	"open_count\x18\x01 \x01(\rR\topenCount\x12'\n" +
+	"\x0fcompleted_count\x18\x02 \x01(\rR\x0ecompletedCount\x122\n" +
+	"\x15completed_today_count\x18\x03 \x01(\rR\x13completedTodayCount\x129\n" +
+	"\x19completed_this_week_count\x18\x04 \x01(\rR\x16completedThisWeekCount\x12#\n" +
+	"\roverdue_count\x18\x05 \x01(\rR\foverdueCount\x12Q\n" +
+	"\x17average_completion_time\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\x15averageCompletionTime\x12'\n" +
+	"\x04tags\x18\a \x03(\v2\x13.todo.v1.GroupStatsR\x04tags\x12/\n" +
+	"\bprojects\x18\b \x03(\v2\x13.todo.v1.GroupStatsR\bprojects\"h\n" +
+	"\n" +
+	"GroupStats\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"open_count\x18\x02 \x01(\rR\topenCount\x12'\n" +
+	"\x0fcompleted_count\x18\x03 \x01(\rR\x0ecompletedCount\"\x13\n" +
+	"\x11WatchTasksRequest\"\xa2\x02\n" +
+	"\tTaskEvent\x12+\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x17.todo.v1.TaskEvent.TypeR\x04type\x12!\n" +
+	"\x04task\x18\x02 \x01(\v2\r.todo.v1.TaskR\x04task\x12.\n" +
+	"\x04time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x04R\bsequence\"y\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fTYPE_CREATED\x10\x01\x12\x10\n" +
+	"\fTYPE_UPDATED\x10\x02\x12\x12\n" +
+	"\x0eTYPE_COMPLETED\x10\x03\x12\x10\n" +
+	"\fTYPE_DELETED\x10\x04\x12\x11\n" +
+	"\rTYPE_ASSIGNED\x10\x05\"\x15\n" +
+	"\x13CreateBackupRequest\"O\n" +
+	"\x14CreateBackupResponse\x12\x18\n" +
+	"\aarchive\x18\x01 \x01(\fR\aarchive\x12\x1d\n" +
+	"\n" +
+	"task_count\x18\x02 \x01(\rR\ttaskCount\"0\n" +
+	"\x14RestoreBackupRequest\x12\x18\n" +
+	"\aarchive\x18\x01 \x01(\fR\aarchive\"6\n" +
+	"\x15RestoreBackupResponse\x12\x1d\n" +
+	"\n" +
+	"task_count\x18\x01 \x01(\rR\ttaskCount\"\x15\n" +
+	"\x13ReloadConfigRequest\"[\n" +
+	"\x14ReloadConfigResponse\x12\x18\n" +
+	"\aapplied\x18\x01 \x03(\tR\aapplied\x12)\n" +
+	"\x10requires_restart\x18\x02 \x03(\tR\x0frequiresRestart\"<\n" +
+	"\x0fTakeoverRequest\x12)\n" +
+	"\x10handover_address\x18\x01 \x01(\tR\x0fhandoverAddress\">\n" +
+	"\x10TakeoverResponse\x12\x18\n" +
+	"\aarchive\x18\x01 \x01(\fR\aarchive\x12\x10\n" +
+	"\x03pid\x18\x02 \x01(\rR\x03pid\"\x11\n" +
+	"\x0fListJobsRequest\">\n" +
+	"\x10ListJobsResponse\x12*\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x16.todo.v1.BackgroundJobR\x04jobs\"\xbd\x03\n" +
+	"\rBackgroundJob\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x125\n" +
+	"\binterval\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\binterval\x12\x18\n" +
+	"\arunning\x18\x03 \x01(\bR\arunning\x12\x12\n" +
+	"\x04runs\x18\x04 \x01(\rR\x04runs\x12\x1a\n" +
+	"\bfailures\x18\x05 \x01(\rR\bfailures\x12@\n" +
+	"\x0etotal_duration\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\rtotalDuration\x12:\n" +
+	"\vlast_run_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tlastRunAt\x12>\n" +
+	"\rlast_duration\x18\b \x01(\v2\x19.google.protobuf.DurationR\flastDuration\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\t \x01(\tR\tlastError\x12:\n" +
+	"\vnext_run_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tnextRunAt\"\\\n" +
+	"\x0fTailLogsRequest\x12\x1b\n" +
+	"\tmin_level\x18\x01 \x01(\tR\bminLevel\x12\x14\n" +
+	"\x05lines\x18\x02 \x01(\rR\x05lines\x12\x16\n" +
+	"\x06follow\x18\x03 \x01(\bR\x06follow\"d\n" +
+	"\bLogEntry\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\x12\x12\n" +
+	"\x04line\x18\x03 \x01(\tR\x04line\"#\n" +
 	"\x11DeleteTaskRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"\x14\n" +
-	"\x12DeleteTaskResponse2\xc2\x03\n" +
+	"\x12DeleteTaskResponse\"$\n" +
+	"\x12RestoreTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"8\n" +
+	"\x13RestoreTaskResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"\x9b\x01\n" +
+	"\x14DuplicateTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\rR\x05count\x12%\n" +
+	"\x0esummary_suffix\x18\x03 \x01(\tR\rsummarySuffix\x126\n" +
+	"\tdue_shift\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\bdueShift\"<\n" +
+	"\x15DuplicateTaskResponse\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\"k\n" +
+	"\x15PurgeCompletedRequest\x128\n" +
+	"\n" +
+	"older_than\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\tolderThan\x12\x18\n" +
+	"\aarchive\x18\x02 \x01(\bR\aarchive\"W\n" +
+	"\x16PurgeCompletedResponse\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\x12\x18\n" +
+	"\aarchive\x18\x02 \x01(\fR\aarchive\"F\n" +
+	"\x12PullChangesRequest\x120\n" +
+	"\x05since\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\"t\n" +
+	"\x13PullChangesResponse\x12-\n" +
+	"\achanges\x18\x01 \x03(\v2\x13.todo.v1.TaskChangeR\achanges\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\"\x92\x01\n" +
+	"\n" +
+	"TaskChange\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\x129\n" +
+	"\n" +
+	"deleted_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12&\n" +
+	"\x0fdepends_on_uids\x18\x03 \x03(\tR\rdependsOnUids\"u\n" +
+	"\x12PushChangesRequest\x12-\n" +
+	"\achanges\x18\x01 \x03(\v2\x13.todo.v1.TaskChangeR\achanges\x120\n" +
+	"\x05since\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\"o\n" +
+	"\x13PushChangesResponse\x12#\n" +
+	"\rapplied_count\x18\x01 \x01(\rR\fappliedCount\x123\n" +
+	"\tconflicts\x18\x02 \x03(\v2\x15.todo.v1.SyncConflictR\tconflicts\"\xe2\x02\n" +
+	"\fSyncConflict\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\x12@\n" +
+	"\n" +
+	"resolution\x18\x02 \x01(\x0e2 .todo.v1.SyncConflict.ResolutionR\n" +
+	"resolution\x12D\n" +
+	"\x10local_changed_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x0elocalChangedAt\x12F\n" +
+	"\x11remote_changed_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x0fremoteChangedAt\"_\n" +
+	"\n" +
+	"Resolution\x12\x1a\n" +
+	"\x16RESOLUTION_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15RESOLUTION_LOCAL_WINS\x10\x01\x12\x1a\n" +
+	"\x16RESOLUTION_REMOTE_WINS\x10\x02\"\xbb\x02\n" +
+	"\x06Filter\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12D\n" +
+	"\n" +
+	"completion\x18\x02 \x01(\x0e2$.todo.v1.ListTasksRequest.CompletionR\n" +
+	"completion\x12\x12\n" +
+	"\x04tags\x18\x03 \x03(\tR\x04tags\x12\x18\n" +
+	"\aproject\x18\x04 \x01(\tR\aproject\x12%\n" +
+	"\x03due\x18\x05 \x01(\x0e2\x13.todo.v1.Filter.DueR\x03due\x12\x18\n" +
+	"\astarred\x18\x06 \x01(\bR\astarred\x12\x1e\n" +
+	"\n" +
+	"configured\x18\a \x01(\bR\n" +
+	"configured\"H\n" +
+	"\x03Due\x12\x13\n" +
+	"\x0fDUE_UNSPECIFIED\x10\x00\x12\r\n" +
+	"\tDUE_TODAY\x10\x01\x12\f\n" +
+	"\bDUE_WEEK\x10\x02\x12\x0f\n" +
+	"\vDUE_OVERDUE\x10\x03\"\x14\n" +
+	"\x12ListFiltersRequest\"@\n" +
+	"\x13ListFiltersResponse\x12)\n" +
+	"\afilters\x18\x01 \x03(\v2\x0f.todo.v1.FilterR\afilters\">\n" +
+	"\x13CreateFilterRequest\x12'\n" +
+	"\x06filter\x18\x01 \x01(\v2\x0f.todo.v1.FilterR\x06filter\"?\n" +
+	"\x14CreateFilterResponse\x12'\n" +
+	"\x06filter\x18\x01 \x01(\v2\x0f.todo.v1.FilterR\x06filter\")\n" +
+	"\x13DeleteFilterRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x16\n" +
+	"\x14DeleteFilterResponse\"\xca\x01\n" +
+	"\fTemplateTask\x12\x18\n" +
+	"\asummary\x18\x01 \x01(\tR\asummary\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04tags\x18\x03 \x03(\tR\x04tags\x12\x18\n" +
+	"\aproject\x18\x04 \x01(\tR\aproject\x12\x18\n" +
+	"\astarred\x18\x05 \x01(\bR\astarred\x126\n" +
+	"\tdue_after\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\bdueAfter\"\xa3\x01\n" +
+	"\bTemplate\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12+\n" +
+	"\x05tasks\x18\x02 \x03(\v2\x15.todo.v1.TemplateTaskR\x05tasks\x12\x1a\n" +
+	"\bschedule\x18\x03 \x01(\tR\bschedule\x12:\n" +
+	"\vnext_run_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tnextRunAt\"\x16\n" +
+	"\x14ListTemplatesRequest\"H\n" +
+	"\x15ListTemplatesResponse\x12/\n" +
+	"\ttemplates\x18\x01 \x03(\v2\x11.todo.v1.TemplateR\ttemplates\"F\n" +
+	"\x15CreateTemplateRequest\x12-\n" +
+	"\btemplate\x18\x01 \x01(\v2\x11.todo.v1.TemplateR\btemplate\"G\n" +
+	"\x16CreateTemplateResponse\x12-\n" +
+	"\btemplate\x18\x01 \x01(\v2\x11.todo.v1.TemplateR\btemplate\"+\n" +
+	"\x15DeleteTemplateRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x18\n" +
+	"\x16DeleteTemplateResponse\"*\n" +
+	"\x14ApplyTemplateRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"<\n" +
+	"\x15ApplyTemplateResponse\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks2\xed\x17\n" +
 	"\vTodoService\x12;\n" +
-	"\x06Status\x12\x16.todo.v1.StatusRequest\x1a\x17.todo.v1.StatusResponse\"\x00\x12^\n" +
+	"\x06Status\x12\x16.todo.v1.StatusRequest\x1a\x17.todo.v1.StatusResponse\"\x00\x12n\n" +
+	"\x0fGetCapabilities\x12\x1f.todo.v1.GetCapabilitiesRequest\x1a .todo.v1.GetCapabilitiesResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/capabilities\x12^\n" +
+	"\n" +
+	"CreateTask\x12\x1a.todo.v1.CreateTaskRequest\x1a\x1b.todo.v1.CreateTaskResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x04task\"\t/v1/tasks\x12y\n" +
+	"\x10BatchCreateTasks\x12 .todo.v1.BatchCreateTasksRequest\x1a!.todo.v1.BatchCreateTasksResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/tasks:batchCreate\x12a\n" +
 	"\n" +
-	"CreateTask\x12\x1a.todo.v1.CreateTaskRequest\x1a\x1b.todo.v1.CreateTaskResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x04task\"\t/v1/tasks\x12U\n" +
-	"\tListTasks\x12\x19.todo.v1.ListTasksRequest\x1a\x1a.todo.v1.ListTasksResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/v1/tasks\x12`\n" +
+	"ApplyBatch\x12\x1a.todo.v1.ApplyBatchRequest\x1a\x1b.todo.v1.ApplyBatchResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/tasks:batch\x12U\n" +
+	"\tListTasks\x12\x19.todo.v1.ListTasksRequest\x1a\x1a.todo.v1.ListTasksResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/v1/tasks\x12T\n" +
+	"\aGetTask\x12\x17.todo.v1.GetTaskRequest\x1a\x18.todo.v1.GetTaskResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/tasks/{id}\x12c\n" +
+	"\vResolveTask\x12\x1b.todo.v1.ResolveTaskRequest\x1a\x1c.todo.v1.ResolveTaskResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/tasks/resolve\x12`\n" +
 	"\n" +
-	"UpdateTask\x12\x1a.todo.v1.UpdateTaskRequest\x1a\x1b.todo.v1.UpdateTaskResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*2\x0e/v1/tasks/{id}\x12]\n" +
+	"UpdateTask\x12\x1a.todo.v1.UpdateTaskRequest\x1a\x1b.todo.v1.UpdateTaskResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*2\x0e/v1/tasks/{id}\x12c\n" +
+	"\bMoveTask\x12\x18.todo.v1.MoveTaskRequest\x1a\x19.todo.v1.MoveTaskResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*2\x17/v1/tasks/{id}/position\x12b\n" +
+	"\vSearchTasks\x12\x1b.todo.v1.SearchTasksRequest\x1a\x1c.todo.v1.SearchTasksResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/tasks/search\x12R\n" +
+	"\bGetStats\x12\x18.todo.v1.GetStatsRequest\x1a\x19.todo.v1.GetStatsResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/v1/stats\x12@\n" +
 	"\n" +
-	"DeleteTask\x12\x1a.todo.v1.DeleteTaskRequest\x1a\x1b.todo.v1.DeleteTaskResponse\"\x16\x82\xd3\xe4\x93\x02\x10*\x0e/v1/tasks/{id}B,Z*github.com/mwopitz/todo-daemon/api/v1/todob\x06proto3"
+	"WatchTasks\x12\x1a.todo.v1.WatchTasksRequest\x1a\x12.todo.v1.TaskEvent\"\x000\x01\x12M\n" +
+	"\fCreateBackup\x12\x1c.todo.v1.CreateBackupRequest\x1a\x1d.todo.v1.CreateBackupResponse\"\x00\x12P\n" +
+	"\rRestoreBackup\x12\x1d.todo.v1.RestoreBackupRequest\x1a\x1e.todo.v1.RestoreBackupResponse\"\x00\x12M\n" +
+	"\fReloadConfig\x12\x1c.todo.v1.ReloadConfigRequest\x1a\x1d.todo.v1.ReloadConfigResponse\"\x00\x12A\n" +
+	"\bTakeover\x12\x18.todo.v1.TakeoverRequest\x1a\x19.todo.v1.TakeoverResponse\"\x00\x12A\n" +
+	"\bListJobs\x12\x18.todo.v1.ListJobsRequest\x1a\x19.todo.v1.ListJobsResponse\"\x00\x12;\n" +
+	"\bTailLogs\x12\x18.todo.v1.TailLogsRequest\x1a\x11.todo.v1.LogEntry\"\x000\x01\x12]\n" +
+	"\n" +
+	"DeleteTask\x12\x1a.todo.v1.DeleteTaskRequest\x1a\x1b.todo.v1.DeleteTaskResponse\"\x16\x82\xd3\xe4\x93\x02\x10*\x0e/v1/tasks/{id}\x12k\n" +
+	"\vRestoreTask\x12\x1b.todo.v1.RestoreTaskRequest\x1a\x1c.todo.v1.RestoreTaskResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/tasks/{id}:restore\x12s\n" +
+	"\rDuplicateTask\x12\x1d.todo.v1.DuplicateTaskRequest\x1a\x1e.todo.v1.DuplicateTaskResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/tasks/{id}:duplicate\x12v\n" +
+	"\x0ePurgeCompleted\x12\x1e.todo.v1.PurgeCompletedRequest\x1a\x1f.todo.v1.PurgeCompletedResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/tasks:purgeCompleted\x12]\n" +
+	"\vPullChanges\x12\x1b.todo.v1.PullChangesRequest\x1a\x1c.todo.v1.PullChangesResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/v1/changes\x12`\n" +
+	"\vPushChanges\x12\x1b.todo.v1.PushChangesRequest\x1a\x1c.todo.v1.PushChangesResponse\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/v1/changes\x12]\n" +
+	"\vListFilters\x12\x1b.todo.v1.ListFiltersRequest\x1a\x1c.todo.v1.ListFiltersResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/v1/filters\x12h\n" +
+	"\fCreateFilter\x12\x1c.todo.v1.CreateFilterRequest\x1a\x1d.todo.v1.CreateFilterResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x06filter\"\v/v1/filters\x12g\n" +
+	"\fDeleteFilter\x12\x1c.todo.v1.DeleteFilterRequest\x1a\x1d.todo.v1.DeleteFilterResponse\"\x1a\x82\xd3\xe4\x93\x02\x14*\x12/v1/filters/{name}\x12e\n" +
+	"\rListTemplates\x12\x1d.todo.v1.ListTemplatesRequest\x1a\x1e.todo.v1.ListTemplatesResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/templates\x12r\n" +
+	"\x0eCreateTemplate\x12\x1e.todo.v1.CreateTemplateRequest\x1a\x1f.todo.v1.CreateTemplateResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\btemplate\"\r/v1/templates\x12o\n" +
+	"\x0eDeleteTemplate\x12\x1e.todo.v1.DeleteTemplateRequest\x1a\x1f.todo.v1.DeleteTemplateResponse\"\x1c\x82\xd3\xe4\x93\x02\x16*\x14/v1/templates/{name}\x12u\n" +
+	"\rApplyTemplate\x12\x1d.todo.v1.ApplyTemplateRequest\x1a\x1e.todo.v1.ApplyTemplateResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/templates/{name}:applyB,Z*github.com/mwopitz/todo-daemon/api/v1/todob\x06proto3"
 
 var (
 	file_todo_v1_todo_proto_rawDescOnce sync.Once
@@ -714,50 +5532,238 @@ func file_todo_v1_todo_proto_rawDescGZIP() []byte {
 	return file_todo_v1_todo_proto_rawDescData
 }
 
-var file_todo_v1_todo_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_todo_v1_todo_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_todo_v1_todo_proto_msgTypes = make([]protoimpl.MessageInfo, 77)
 var file_todo_v1_todo_proto_goTypes = []any{
-	(*StatusRequest)(nil),         // 0: todo.v1.StatusRequest
-	(*StatusResponse)(nil),        // 1: todo.v1.StatusResponse
-	(*Task)(nil),                  // 2: todo.v1.Task
-	(*NewTask)(nil),               // 3: todo.v1.NewTask
-	(*TaskUpdate)(nil),            // 4: todo.v1.TaskUpdate
-	(*CreateTaskRequest)(nil),     // 5: todo.v1.CreateTaskRequest
-	(*CreateTaskResponse)(nil),    // 6: todo.v1.CreateTaskResponse
-	(*ListTasksRequest)(nil),      // 7: todo.v1.ListTasksRequest
-	(*ListTasksResponse)(nil),     // 8: todo.v1.ListTasksResponse
-	(*UpdateTaskRequest)(nil),     // 9: todo.v1.UpdateTaskRequest
-	(*UpdateTaskResponse)(nil),    // 10: todo.v1.UpdateTaskResponse
-	(*DeleteTaskRequest)(nil),     // 11: todo.v1.DeleteTaskRequest
-	(*DeleteTaskResponse)(nil),    // 12: todo.v1.DeleteTaskResponse
-	(*timestamppb.Timestamp)(nil), // 13: google.protobuf.Timestamp
-	(*fieldmaskpb.FieldMask)(nil), // 14: google.protobuf.FieldMask
+	(ListTasksRequest_Completion)(0), // 0: todo.v1.ListTasksRequest.Completion
+	(ListTasksRequest_SortBy)(0),     // 1: todo.v1.ListTasksRequest.SortBy
+	(TaskEvent_Type)(0),              // 2: todo.v1.TaskEvent.Type
+	(SyncConflict_Resolution)(0),     // 3: todo.v1.SyncConflict.Resolution
+	(Filter_Due)(0),                  // 4: todo.v1.Filter.Due
+	(*StatusRequest)(nil),            // 5: todo.v1.StatusRequest
+	(*StatusResponse)(nil),           // 6: todo.v1.StatusResponse
+	(*GetCapabilitiesRequest)(nil),   // 7: todo.v1.GetCapabilitiesRequest
+	(*GetCapabilitiesResponse)(nil),  // 8: todo.v1.GetCapabilitiesResponse
+	(*Limits)(nil),                   // 9: todo.v1.Limits
+	(*Task)(nil),                     // 10: todo.v1.Task
+	(*NewTask)(nil),                  // 11: todo.v1.NewTask
+	(*TaskUpdate)(nil),               // 12: todo.v1.TaskUpdate
+	(*CreateTaskRequest)(nil),        // 13: todo.v1.CreateTaskRequest
+	(*CreateTaskResponse)(nil),       // 14: todo.v1.CreateTaskResponse
+	(*BatchCreateTasksRequest)(nil),  // 15: todo.v1.BatchCreateTasksRequest
+	(*BatchCreateTasksResponse)(nil), // 16: todo.v1.BatchCreateTasksResponse
+	(*BatchOperation)(nil),           // 17: todo.v1.BatchOperation
+	(*ApplyBatchRequest)(nil),        // 18: todo.v1.ApplyBatchRequest
+	(*ApplyBatchResponse)(nil),       // 19: todo.v1.ApplyBatchResponse
+	(*ListTasksRequest)(nil),         // 20: todo.v1.ListTasksRequest
+	(*ListTasksResponse)(nil),        // 21: todo.v1.ListTasksResponse
+	(*GetTaskRequest)(nil),           // 22: todo.v1.GetTaskRequest
+	(*GetTaskResponse)(nil),          // 23: todo.v1.GetTaskResponse
+	(*ResolveTaskRequest)(nil),       // 24: todo.v1.ResolveTaskRequest
+	(*ResolveTaskResponse)(nil),      // 25: todo.v1.ResolveTaskResponse
+	(*UpdateTaskRequest)(nil),        // 26: todo.v1.UpdateTaskRequest
+	(*UpdateTaskResponse)(nil),       // 27: todo.v1.UpdateTaskResponse
+	(*MoveTaskRequest)(nil),          // 28: todo.v1.MoveTaskRequest
+	(*MoveTaskResponse)(nil),         // 29: todo.v1.MoveTaskResponse
+	(*SearchTasksRequest)(nil),       // 30: todo.v1.SearchTasksRequest
+	(*SearchTasksResponse)(nil),      // 31: todo.v1.SearchTasksResponse
+	(*SearchResult)(nil),             // 32: todo.v1.SearchResult
+	(*GetStatsRequest)(nil),          // 33: todo.v1.GetStatsRequest
+	(*GetStatsResponse)(nil),         // 34: todo.v1.GetStatsResponse
+	(*GroupStats)(nil),               // 35: todo.v1.GroupStats
+	(*WatchTasksRequest)(nil),        // 36: todo.v1.WatchTasksRequest
+	(*TaskEvent)(nil),                // 37: todo.v1.TaskEvent
+	(*CreateBackupRequest)(nil),      // 38: todo.v1.CreateBackupRequest
+	(*CreateBackupResponse)(nil),     // 39: todo.v1.CreateBackupResponse
+	(*RestoreBackupRequest)(nil),     // 40: todo.v1.RestoreBackupRequest
+	(*RestoreBackupResponse)(nil),    // 41: todo.v1.RestoreBackupResponse
+	(*ReloadConfigRequest)(nil),      // 42: todo.v1.ReloadConfigRequest
+	(*ReloadConfigResponse)(nil),     // 43: todo.v1.ReloadConfigResponse
+	(*TakeoverRequest)(nil),          // 44: todo.v1.TakeoverRequest
+	(*TakeoverResponse)(nil),         // 45: todo.v1.TakeoverResponse
+	(*ListJobsRequest)(nil),          // 46: todo.v1.ListJobsRequest
+	(*ListJobsResponse)(nil),         // 47: todo.v1.ListJobsResponse
+	(*BackgroundJob)(nil),            // 48: todo.v1.BackgroundJob
+	(*TailLogsRequest)(nil),          // 49: todo.v1.TailLogsRequest
+	(*LogEntry)(nil),                 // 50: todo.v1.LogEntry
+	(*DeleteTaskRequest)(nil),        // 51: todo.v1.DeleteTaskRequest
+	(*DeleteTaskResponse)(nil),       // 52: todo.v1.DeleteTaskResponse
+	(*RestoreTaskRequest)(nil),       // 53: todo.v1.RestoreTaskRequest
+	(*RestoreTaskResponse)(nil),      // 54: todo.v1.RestoreTaskResponse
+	(*DuplicateTaskRequest)(nil),     // 55: todo.v1.DuplicateTaskRequest
+	(*DuplicateTaskResponse)(nil),    // 56: todo.v1.DuplicateTaskResponse
+	(*PurgeCompletedRequest)(nil),    // 57: todo.v1.PurgeCompletedRequest
+	(*PurgeCompletedResponse)(nil),   // 58: todo.v1.PurgeCompletedResponse
+	(*PullChangesRequest)(nil),       // 59: todo.v1.PullChangesRequest
+	(*PullChangesResponse)(nil),      // 60: todo.v1.PullChangesResponse
+	(*TaskChange)(nil),               // 61: todo.v1.TaskChange
+	(*PushChangesRequest)(nil),       // 62: todo.v1.PushChangesRequest
+	(*PushChangesResponse)(nil),      // 63: todo.v1.PushChangesResponse
+	(*SyncConflict)(nil),             // 64: todo.v1.SyncConflict
+	(*Filter)(nil),                   // 65: todo.v1.Filter
+	(*ListFiltersRequest)(nil),       // 66: todo.v1.ListFiltersRequest
+	(*ListFiltersResponse)(nil),      // 67: todo.v1.ListFiltersResponse
+	(*CreateFilterRequest)(nil),      // 68: todo.v1.CreateFilterRequest
+	(*CreateFilterResponse)(nil),     // 69: todo.v1.CreateFilterResponse
+	(*DeleteFilterRequest)(nil),      // 70: todo.v1.DeleteFilterRequest
+	(*DeleteFilterResponse)(nil),     // 71: todo.v1.DeleteFilterResponse
+	(*TemplateTask)(nil),             // 72: todo.v1.TemplateTask
+	(*Template)(nil),                 // 73: todo.v1.Template
+	(*ListTemplatesRequest)(nil),     // 74: todo.v1.ListTemplatesRequest
+	(*ListTemplatesResponse)(nil),    // 75: todo.v1.ListTemplatesResponse
+	(*CreateTemplateRequest)(nil),    // 76: todo.v1.CreateTemplateRequest
+	(*CreateTemplateResponse)(nil),   // 77: todo.v1.CreateTemplateResponse
+	(*DeleteTemplateRequest)(nil),    // 78: todo.v1.DeleteTemplateRequest
+	(*DeleteTemplateResponse)(nil),   // 79: todo.v1.DeleteTemplateResponse
+	(*ApplyTemplateRequest)(nil),     // 80: todo.v1.ApplyTemplateRequest
+	(*ApplyTemplateResponse)(nil),    // 81: todo.v1.ApplyTemplateResponse
+	(*durationpb.Duration)(nil),      // 82: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),    // 83: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),    // 84: google.protobuf.FieldMask
 }
 var file_todo_v1_todo_proto_depIdxs = []int32{
-	13, // 0: todo.v1.Task.created_at:type_name -> google.protobuf.Timestamp
-	13, // 1: todo.v1.Task.updated_at:type_name -> google.protobuf.Timestamp
-	13, // 2: todo.v1.Task.completed_at:type_name -> google.protobuf.Timestamp
-	13, // 3: todo.v1.TaskUpdate.completed_at:type_name -> google.protobuf.Timestamp
-	3,  // 4: todo.v1.CreateTaskRequest.task:type_name -> todo.v1.NewTask
-	2,  // 5: todo.v1.CreateTaskResponse.task:type_name -> todo.v1.Task
-	2,  // 6: todo.v1.ListTasksResponse.tasks:type_name -> todo.v1.Task
-	4,  // 7: todo.v1.UpdateTaskRequest.update:type_name -> todo.v1.TaskUpdate
-	14, // 8: todo.v1.UpdateTaskRequest.fields:type_name -> google.protobuf.FieldMask
-	2,  // 9: todo.v1.UpdateTaskResponse.task:type_name -> todo.v1.Task
-	0,  // 10: todo.v1.TodoService.Status:input_type -> todo.v1.StatusRequest
-	5,  // 11: todo.v1.TodoService.CreateTask:input_type -> todo.v1.CreateTaskRequest
-	7,  // 12: todo.v1.TodoService.ListTasks:input_type -> todo.v1.ListTasksRequest
-	9,  // 13: todo.v1.TodoService.UpdateTask:input_type -> todo.v1.UpdateTaskRequest
-	11, // 14: todo.v1.TodoService.DeleteTask:input_type -> todo.v1.DeleteTaskRequest
-	1,  // 15: todo.v1.TodoService.Status:output_type -> todo.v1.StatusResponse
-	6,  // 16: todo.v1.TodoService.CreateTask:output_type -> todo.v1.CreateTaskResponse
-	8,  // 17: todo.v1.TodoService.ListTasks:output_type -> todo.v1.ListTasksResponse
-	10, // 18: todo.v1.TodoService.UpdateTask:output_type -> todo.v1.UpdateTaskResponse
-	12, // 19: todo.v1.TodoService.DeleteTask:output_type -> todo.v1.DeleteTaskResponse
-	15, // [15:20] is the sub-list for method output_type
-	10, // [10:15] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	82,  // 0: todo.v1.StatusResponse.uptime:type_name -> google.protobuf.Duration
+	9,   // 1: todo.v1.GetCapabilitiesResponse.limits:type_name -> todo.v1.Limits
+	83,  // 2: todo.v1.Task.created_at:type_name -> google.protobuf.Timestamp
+	83,  // 3: todo.v1.Task.updated_at:type_name -> google.protobuf.Timestamp
+	83,  // 4: todo.v1.Task.completed_at:type_name -> google.protobuf.Timestamp
+	83,  // 5: todo.v1.Task.due_at:type_name -> google.protobuf.Timestamp
+	83,  // 6: todo.v1.NewTask.due_at:type_name -> google.protobuf.Timestamp
+	83,  // 7: todo.v1.TaskUpdate.completed_at:type_name -> google.protobuf.Timestamp
+	83,  // 8: todo.v1.TaskUpdate.due_at:type_name -> google.protobuf.Timestamp
+	11,  // 9: todo.v1.CreateTaskRequest.task:type_name -> todo.v1.NewTask
+	10,  // 10: todo.v1.CreateTaskResponse.task:type_name -> todo.v1.Task
+	11,  // 11: todo.v1.BatchCreateTasksRequest.tasks:type_name -> todo.v1.NewTask
+	10,  // 12: todo.v1.BatchCreateTasksResponse.tasks:type_name -> todo.v1.Task
+	11,  // 13: todo.v1.BatchOperation.create:type_name -> todo.v1.NewTask
+	26,  // 14: todo.v1.BatchOperation.update:type_name -> todo.v1.UpdateTaskRequest
+	51,  // 15: todo.v1.BatchOperation.delete:type_name -> todo.v1.DeleteTaskRequest
+	17,  // 16: todo.v1.ApplyBatchRequest.operations:type_name -> todo.v1.BatchOperation
+	10,  // 17: todo.v1.ApplyBatchResponse.tasks:type_name -> todo.v1.Task
+	83,  // 18: todo.v1.ListTasksRequest.due_before:type_name -> google.protobuf.Timestamp
+	83,  // 19: todo.v1.ListTasksRequest.due_after:type_name -> google.protobuf.Timestamp
+	0,   // 20: todo.v1.ListTasksRequest.completion:type_name -> todo.v1.ListTasksRequest.Completion
+	1,   // 21: todo.v1.ListTasksRequest.sort_by:type_name -> todo.v1.ListTasksRequest.SortBy
+	10,  // 22: todo.v1.ListTasksResponse.tasks:type_name -> todo.v1.Task
+	10,  // 23: todo.v1.GetTaskResponse.task:type_name -> todo.v1.Task
+	10,  // 24: todo.v1.ResolveTaskResponse.task:type_name -> todo.v1.Task
+	12,  // 25: todo.v1.UpdateTaskRequest.update:type_name -> todo.v1.TaskUpdate
+	84,  // 26: todo.v1.UpdateTaskRequest.fields:type_name -> google.protobuf.FieldMask
+	10,  // 27: todo.v1.UpdateTaskResponse.task:type_name -> todo.v1.Task
+	10,  // 28: todo.v1.MoveTaskResponse.task:type_name -> todo.v1.Task
+	32,  // 29: todo.v1.SearchTasksResponse.results:type_name -> todo.v1.SearchResult
+	10,  // 30: todo.v1.SearchResult.task:type_name -> todo.v1.Task
+	82,  // 31: todo.v1.GetStatsResponse.average_completion_time:type_name -> google.protobuf.Duration
+	35,  // 32: todo.v1.GetStatsResponse.tags:type_name -> todo.v1.GroupStats
+	35,  // 33: todo.v1.GetStatsResponse.projects:type_name -> todo.v1.GroupStats
+	2,   // 34: todo.v1.TaskEvent.type:type_name -> todo.v1.TaskEvent.Type
+	10,  // 35: todo.v1.TaskEvent.task:type_name -> todo.v1.Task
+	83,  // 36: todo.v1.TaskEvent.time:type_name -> google.protobuf.Timestamp
+	48,  // 37: todo.v1.ListJobsResponse.jobs:type_name -> todo.v1.BackgroundJob
+	82,  // 38: todo.v1.BackgroundJob.interval:type_name -> google.protobuf.Duration
+	82,  // 39: todo.v1.BackgroundJob.total_duration:type_name -> google.protobuf.Duration
+	83,  // 40: todo.v1.BackgroundJob.last_run_at:type_name -> google.protobuf.Timestamp
+	82,  // 41: todo.v1.BackgroundJob.last_duration:type_name -> google.protobuf.Duration
+	83,  // 42: todo.v1.BackgroundJob.next_run_at:type_name -> google.protobuf.Timestamp
+	83,  // 43: todo.v1.LogEntry.time:type_name -> google.protobuf.Timestamp
+	10,  // 44: todo.v1.RestoreTaskResponse.task:type_name -> todo.v1.Task
+	82,  // 45: todo.v1.DuplicateTaskRequest.due_shift:type_name -> google.protobuf.Duration
+	10,  // 46: todo.v1.DuplicateTaskResponse.tasks:type_name -> todo.v1.Task
+	82,  // 47: todo.v1.PurgeCompletedRequest.older_than:type_name -> google.protobuf.Duration
+	10,  // 48: todo.v1.PurgeCompletedResponse.tasks:type_name -> todo.v1.Task
+	83,  // 49: todo.v1.PullChangesRequest.since:type_name -> google.protobuf.Timestamp
+	61,  // 50: todo.v1.PullChangesResponse.changes:type_name -> todo.v1.TaskChange
+	83,  // 51: todo.v1.PullChangesResponse.time:type_name -> google.protobuf.Timestamp
+	10,  // 52: todo.v1.TaskChange.task:type_name -> todo.v1.Task
+	83,  // 53: todo.v1.TaskChange.deleted_at:type_name -> google.protobuf.Timestamp
+	61,  // 54: todo.v1.PushChangesRequest.changes:type_name -> todo.v1.TaskChange
+	83,  // 55: todo.v1.PushChangesRequest.since:type_name -> google.protobuf.Timestamp
+	64,  // 56: todo.v1.PushChangesResponse.conflicts:type_name -> todo.v1.SyncConflict
+	10,  // 57: todo.v1.SyncConflict.task:type_name -> todo.v1.Task
+	3,   // 58: todo.v1.SyncConflict.resolution:type_name -> todo.v1.SyncConflict.Resolution
+	83,  // 59: todo.v1.SyncConflict.local_changed_at:type_name -> google.protobuf.Timestamp
+	83,  // 60: todo.v1.SyncConflict.remote_changed_at:type_name -> google.protobuf.Timestamp
+	0,   // 61: todo.v1.Filter.completion:type_name -> todo.v1.ListTasksRequest.Completion
+	4,   // 62: todo.v1.Filter.due:type_name -> todo.v1.Filter.Due
+	65,  // 63: todo.v1.ListFiltersResponse.filters:type_name -> todo.v1.Filter
+	65,  // 64: todo.v1.CreateFilterRequest.filter:type_name -> todo.v1.Filter
+	65,  // 65: todo.v1.CreateFilterResponse.filter:type_name -> todo.v1.Filter
+	82,  // 66: todo.v1.TemplateTask.due_after:type_name -> google.protobuf.Duration
+	72,  // 67: todo.v1.Template.tasks:type_name -> todo.v1.TemplateTask
+	83,  // 68: todo.v1.Template.next_run_at:type_name -> google.protobuf.Timestamp
+	73,  // 69: todo.v1.ListTemplatesResponse.templates:type_name -> todo.v1.Template
+	73,  // 70: todo.v1.CreateTemplateRequest.template:type_name -> todo.v1.Template
+	73,  // 71: todo.v1.CreateTemplateResponse.template:type_name -> todo.v1.Template
+	10,  // 72: todo.v1.ApplyTemplateResponse.tasks:type_name -> todo.v1.Task
+	5,   // 73: todo.v1.TodoService.Status:input_type -> todo.v1.StatusRequest
+	7,   // 74: todo.v1.TodoService.GetCapabilities:input_type -> todo.v1.GetCapabilitiesRequest
+	13,  // 75: todo.v1.TodoService.CreateTask:input_type -> todo.v1.CreateTaskRequest
+	15,  // 76: todo.v1.TodoService.BatchCreateTasks:input_type -> todo.v1.BatchCreateTasksRequest
+	18,  // 77: todo.v1.TodoService.ApplyBatch:input_type -> todo.v1.ApplyBatchRequest
+	20,  // 78: todo.v1.TodoService.ListTasks:input_type -> todo.v1.ListTasksRequest
+	22,  // 79: todo.v1.TodoService.GetTask:input_type -> todo.v1.GetTaskRequest
+	24,  // 80: todo.v1.TodoService.ResolveTask:input_type -> todo.v1.ResolveTaskRequest
+	26,  // 81: todo.v1.TodoService.UpdateTask:input_type -> todo.v1.UpdateTaskRequest
+	28,  // 82: todo.v1.TodoService.MoveTask:input_type -> todo.v1.MoveTaskRequest
+	30,  // 83: todo.v1.TodoService.SearchTasks:input_type -> todo.v1.SearchTasksRequest
+	33,  // 84: todo.v1.TodoService.GetStats:input_type -> todo.v1.GetStatsRequest
+	36,  // 85: todo.v1.TodoService.WatchTasks:input_type -> todo.v1.WatchTasksRequest
+	38,  // 86: todo.v1.TodoService.CreateBackup:input_type -> todo.v1.CreateBackupRequest
+	40,  // 87: todo.v1.TodoService.RestoreBackup:input_type -> todo.v1.RestoreBackupRequest
+	42,  // 88: todo.v1.TodoService.ReloadConfig:input_type -> todo.v1.ReloadConfigRequest
+	44,  // 89: todo.v1.TodoService.Takeover:input_type -> todo.v1.TakeoverRequest
+	46,  // 90: todo.v1.TodoService.ListJobs:input_type -> todo.v1.ListJobsRequest
+	49,  // 91: todo.v1.TodoService.TailLogs:input_type -> todo.v1.TailLogsRequest
+	51,  // 92: todo.v1.TodoService.DeleteTask:input_type -> todo.v1.DeleteTaskRequest
+	53,  // 93: todo.v1.TodoService.RestoreTask:input_type -> todo.v1.RestoreTaskRequest
+	55,  // 94: todo.v1.TodoService.DuplicateTask:input_type -> todo.v1.DuplicateTaskRequest
+	57,  // 95: todo.v1.TodoService.PurgeCompleted:input_type -> todo.v1.PurgeCompletedRequest
+	59,  // 96: todo.v1.TodoService.PullChanges:input_type -> todo.v1.PullChangesRequest
+	62,  // 97: todo.v1.TodoService.PushChanges:input_type -> todo.v1.PushChangesRequest
+	66,  // 98: todo.v1.TodoService.ListFilters:input_type -> todo.v1.ListFiltersRequest
+	68,  // 99: todo.v1.TodoService.CreateFilter:input_type -> todo.v1.CreateFilterRequest
+	70,  // 100: todo.v1.TodoService.DeleteFilter:input_type -> todo.v1.DeleteFilterRequest
+	74,  // 101: todo.v1.TodoService.ListTemplates:input_type -> todo.v1.ListTemplatesRequest
+	76,  // 102: todo.v1.TodoService.CreateTemplate:input_type -> todo.v1.CreateTemplateRequest
+	78,  // 103: todo.v1.TodoService.DeleteTemplate:input_type -> todo.v1.DeleteTemplateRequest
+	80,  // 104: todo.v1.TodoService.ApplyTemplate:input_type -> todo.v1.ApplyTemplateRequest
+	6,   // 105: todo.v1.TodoService.Status:output_type -> todo.v1.StatusResponse
+	8,   // 106: todo.v1.TodoService.GetCapabilities:output_type -> todo.v1.GetCapabilitiesResponse
+	14,  // 107: todo.v1.TodoService.CreateTask:output_type -> todo.v1.CreateTaskResponse
+	16,  // 108: todo.v1.TodoService.BatchCreateTasks:output_type -> todo.v1.BatchCreateTasksResponse
+	19,  // 109: todo.v1.TodoService.ApplyBatch:output_type -> todo.v1.ApplyBatchResponse
+	21,  // 110: todo.v1.TodoService.ListTasks:output_type -> todo.v1.ListTasksResponse
+	23,  // 111: todo.v1.TodoService.GetTask:output_type -> todo.v1.GetTaskResponse
+	25,  // 112: todo.v1.TodoService.ResolveTask:output_type -> todo.v1.ResolveTaskResponse
+	27,  // 113: todo.v1.TodoService.UpdateTask:output_type -> todo.v1.UpdateTaskResponse
+	29,  // 114: todo.v1.TodoService.MoveTask:output_type -> todo.v1.MoveTaskResponse
+	31,  // 115: todo.v1.TodoService.SearchTasks:output_type -> todo.v1.SearchTasksResponse
+	34,  // 116: todo.v1.TodoService.GetStats:output_type -> todo.v1.GetStatsResponse
+	37,  // 117: todo.v1.TodoService.WatchTasks:output_type -> todo.v1.TaskEvent
+	39,  // 118: todo.v1.TodoService.CreateBackup:output_type -> todo.v1.CreateBackupResponse
+	41,  // 119: todo.v1.TodoService.RestoreBackup:output_type -> todo.v1.RestoreBackupResponse
+	43,  // 120: todo.v1.TodoService.ReloadConfig:output_type -> todo.v1.ReloadConfigResponse
+	45,  // 121: todo.v1.TodoService.Takeover:output_type -> todo.v1.TakeoverResponse
+	47,  // 122: todo.v1.TodoService.ListJobs:output_type -> todo.v1.ListJobsResponse
+	50,  // 123: todo.v1.TodoService.TailLogs:output_type -> todo.v1.LogEntry
+	52,  // 124: todo.v1.TodoService.DeleteTask:output_type -> todo.v1.DeleteTaskResponse
+	54,  // 125: todo.v1.TodoService.RestoreTask:output_type -> todo.v1.RestoreTaskResponse
+	56,  // 126: todo.v1.TodoService.DuplicateTask:output_type -> todo.v1.DuplicateTaskResponse
+	58,  // 127: todo.v1.TodoService.PurgeCompleted:output_type -> todo.v1.PurgeCompletedResponse
+	60,  // 128: todo.v1.TodoService.PullChanges:output_type -> todo.v1.PullChangesResponse
+	63,  // 129: todo.v1.TodoService.PushChanges:output_type -> todo.v1.PushChangesResponse
+	67,  // 130: todo.v1.TodoService.ListFilters:output_type -> todo.v1.ListFiltersResponse
+	69,  // 131: todo.v1.TodoService.CreateFilter:output_type -> todo.v1.CreateFilterResponse
+	71,  // 132: todo.v1.TodoService.DeleteFilter:output_type -> todo.v1.DeleteFilterResponse
+	75,  // 133: todo.v1.TodoService.ListTemplates:output_type -> todo.v1.ListTemplatesResponse
+	77,  // 134: todo.v1.TodoService.CreateTemplate:output_type -> todo.v1.CreateTemplateResponse
+	79,  // 135: todo.v1.TodoService.DeleteTemplate:output_type -> todo.v1.DeleteTemplateResponse
+	81,  // 136: todo.v1.TodoService.ApplyTemplate:output_type -> todo.v1.ApplyTemplateResponse
+	105, // [105:137] is the sub-list for method output_type
+	73,  // [73:105] is the sub-list for method input_type
+	73,  // [73:73] is the sub-list for extension type_name
+	73,  // [73:73] is the sub-list for extension extendee
+	0,   // [0:73] is the sub-list for field type_name
 }
 
 func init() { file_todo_v1_todo_proto_init() }
@@ -765,18 +5771,24 @@ func file_todo_v1_todo_proto_init() {
 	if File_todo_v1_todo_proto != nil {
 		return
 	}
+	file_todo_v1_todo_proto_msgTypes[12].OneofWrappers = []any{
+		(*BatchOperation_Create)(nil),
+		(*BatchOperation_Update)(nil),
+		(*BatchOperation_Delete)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_todo_v1_todo_proto_rawDesc), len(file_todo_v1_todo_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   13,
+			NumEnums:      5,
+			NumMessages:   77,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_todo_v1_todo_proto_goTypes,
 		DependencyIndexes: file_todo_v1_todo_proto_depIdxs,
+		EnumInfos:         file_todo_v1_todo_proto_enumTypes,
 		MessageInfos:      file_todo_v1_todo_proto_msgTypes,
 	}.Build()
 	File_todo_v1_todo_proto = out.File
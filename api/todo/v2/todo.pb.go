@@ -0,0 +1,904 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: todo/v2/todo.proto
+
+package todo
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// The completion states of tasks.
+type Task_State int32
+
+const (
+	Task_STATE_UNSPECIFIED Task_State = 0
+	// The task has not been completed yet.
+	Task_STATE_OPEN Task_State = 1
+	// The task has been completed.
+	Task_STATE_COMPLETED Task_State = 2
+)
+
+// Enum value maps for Task_State.
+var (
+	Task_State_name = map[int32]string{
+		0: "STATE_UNSPECIFIED",
+		1: "STATE_OPEN",
+		2: "STATE_COMPLETED",
+	}
+	Task_State_value = map[string]int32{
+		"STATE_UNSPECIFIED": 0,
+		"STATE_OPEN":        1,
+		"STATE_COMPLETED":   2,
+	}
+)
+
+func (x Task_State) Enum() *Task_State {
+	p := new(Task_State)
+	*p = x
+	return p
+}
+
+func (x Task_State) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Task_State) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_v2_todo_proto_enumTypes[0].Descriptor()
+}
+
+func (Task_State) Type() protoreflect.EnumType {
+	return &file_todo_v2_todo_proto_enumTypes[0]
+}
+
+func (x Task_State) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Task_State.Descriptor instead.
+func (Task_State) EnumDescriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{0, 0}
+}
+
+// A single task to complete in a to-do list.
+type Task struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The ID of the task. Output only.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// A concise description of the task. Required.
+	Summary string `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	// A more detailed description of the task.
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// The completion state of the task. New tasks are open. Completing a task
+	// sets complete_time to the current time.
+	State Task_State `protobuf:"varint,4,opt,name=state,proto3,enum=todo.v2.Task_State" json:"state,omitempty"`
+	// The time when the task was created. Output only.
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// The time of the last update of the task, if any. Output only.
+	UpdateTime *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	// The time when the task was completed, if it is completed. Output only.
+	CompleteTime *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=complete_time,json=completeTime,proto3" json:"complete_time,omitempty"`
+	// The time when the task is due, if any.
+	DueTime *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=due_time,json=dueTime,proto3" json:"due_time,omitempty"`
+	// The due time in the task's time zone as an RFC 3339 timestamp with an
+	// explicit offset, e.g. "2025-12-24T18:00:00+01:00". Output only.
+	DueTimeLocal string `protobuf:"bytes,9,opt,name=due_time_local,json=dueTimeLocal,proto3" json:"due_time_local,omitempty"`
+	// The IANA name of the time zone that the task's times refer to, e.g.
+	// "Europe/Berlin". If empty, the server's default time zone is used.
+	TimeZone string `protobuf:"bytes,10,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// The rule that the task recurs by, e.g. "FREQ=WEEKLY;BYDAY=MO". When a
+	// recurring task is completed, its next occurrence is added as a new task.
+	Recurrence string `protobuf:"bytes,11,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	// The tags of the task, e.g. "errands".
+	Tags []string `protobuf:"bytes,12,rep,name=tags,proto3" json:"tags,omitempty"`
+	// The project the task belongs to, if any.
+	Project string `protobuf:"bytes,13,opt,name=project,proto3" json:"project,omitempty"`
+	// Whether the task is starred.
+	Starred bool `protobuf:"varint,14,opt,name=starred,proto3" json:"starred,omitempty"`
+	// The IDs of the tasks that must be completed before this task.
+	DependsOn []string `protobuf:"bytes,15,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	// The IDs of the tasks this task depends on that are still open. Output
+	// only.
+	BlockedBy []string `protobuf:"bytes,16,rep,name=blocked_by,json=blockedBy,proto3" json:"blocked_by,omitempty"`
+	// The position of the task in the manual order of the to-do list. Output
+	// only.
+	Position int64 `protobuf:"varint,17,opt,name=position,proto3" json:"position,omitempty"`
+	// The version of the task, which is incremented with each update. Output
+	// only.
+	Version uint64 `protobuf:"varint,18,opt,name=version,proto3" json:"version,omitempty"`
+	// A short, human-friendly code derived from the ID. Output only.
+	ShortCode string `protobuf:"bytes,19,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	// The globally unique ID of the task, which identifies it across
+	// synchronized to-do lists, unlike the ID. Output only.
+	Uid string `protobuf:"bytes,20,opt,name=uid,proto3" json:"uid,omitempty"`
+	// The user the task is assigned to, if any, e.g. "alice".
+	Assignee string `protobuf:"bytes,21,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	// The URLs of web pages related to the task, e.g. tickets or documents.
+	// Each link is an absolute http or https URL.
+	Links         []string `protobuf:"bytes,22,rep,name=links,proto3" json:"links,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Task) Reset() {
+	*x = Task{}
+	mi := &file_todo_v2_todo_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Task) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Task) ProtoMessage() {}
+
+func (x *Task) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v2_todo_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Task.ProtoReflect.Descriptor instead.
+func (*Task) Descriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Task) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Task) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Task) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Task) GetState() Task_State {
+	if x != nil {
+		return x.State
+	}
+	return Task_STATE_UNSPECIFIED
+}
+
+func (x *Task) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *Task) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+func (x *Task) GetCompleteTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompleteTime
+	}
+	return nil
+}
+
+func (x *Task) GetDueTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueTime
+	}
+	return nil
+}
+
+func (x *Task) GetDueTimeLocal() string {
+	if x != nil {
+		return x.DueTimeLocal
+	}
+	return ""
+}
+
+func (x *Task) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *Task) GetRecurrence() string {
+	if x != nil {
+		return x.Recurrence
+	}
+	return ""
+}
+
+func (x *Task) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Task) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *Task) GetStarred() bool {
+	if x != nil {
+		return x.Starred
+	}
+	return false
+}
+
+func (x *Task) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *Task) GetBlockedBy() []string {
+	if x != nil {
+		return x.BlockedBy
+	}
+	return nil
+}
+
+func (x *Task) GetPosition() int64 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *Task) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Task) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *Task) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *Task) GetAssignee() string {
+	if x != nil {
+		return x.Assignee
+	}
+	return ""
+}
+
+func (x *Task) GetLinks() []string {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+type CreateTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The task to create. Output only fields are ignored.
+	Task *Task `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	// Whether to create the task even if its summary equals the summary of an
+	// open task, ignoring case, and the server rejects such duplicates, see the
+	// "unique_summaries" feature.
+	AllowDuplicate bool `protobuf:"varint,2,opt,name=allow_duplicate,json=allowDuplicate,proto3" json:"allow_duplicate,omitempty"`
+	// A unique key chosen by the client, e.g. a random UUID, so that the
+	// request can be retried safely: the server creates the task only once and
+	// returns the same task for requests with the same key within its
+	// idempotency window. The REST API also accepts it as Idempotency-Key
+	// header. At most 255 printable ASCII characters.
+	IdempotencyKey string `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateTaskRequest) Reset() {
+	*x = CreateTaskRequest{}
+	mi := &file_todo_v2_todo_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTaskRequest) ProtoMessage() {}
+
+func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v2_todo_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTaskRequest.ProtoReflect.Descriptor instead.
+func (*CreateTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateTaskRequest) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetAllowDuplicate() bool {
+	if x != nil {
+		return x.AllowDuplicate
+	}
+	return false
+}
+
+func (x *CreateTaskRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type ListTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// If set, only the tasks in this completion state are returned.
+	State Task_State `protobuf:"varint,1,opt,name=state,proto3,enum=todo.v2.Task_State" json:"state,omitempty"`
+	// If set, only the tasks having all of these tags are returned.
+	Tags []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	// If set, only the tasks of this project are returned.
+	Project string `protobuf:"bytes,3,opt,name=project,proto3" json:"project,omitempty"`
+	// If true, only the starred tasks are returned.
+	Starred bool `protobuf:"varint,4,opt,name=starred,proto3" json:"starred,omitempty"`
+	// If set, only the tasks due at or after this time are returned.
+	DueAfter *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=due_after,json=dueAfter,proto3" json:"due_after,omitempty"`
+	// If set, only the tasks due before this time are returned.
+	DueBefore *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=due_before,json=dueBefore,proto3" json:"due_before,omitempty"`
+	// If true, only the open tasks that are past their due time are returned.
+	Overdue bool `protobuf:"varint,7,opt,name=overdue,proto3" json:"overdue,omitempty"`
+	// The field to sort the tasks by, optionally followed by " desc" for
+	// descending order: "create_time" (the default, starred tasks first),
+	// "due_time", "update_time", or "position".
+	OrderBy string `protobuf:"bytes,8,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	// The maximum number of tasks to return. The default is 100, and larger
+	// values than 1000 are reduced to 1000.
+	PageSize int32 `protobuf:"varint,9,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// The next_page_token of the previous response, to retrieve the next page.
+	// All other fields must be the same as in the previous request.
+	PageToken string `protobuf:"bytes,10,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// If set, only the tasks assigned to this user are returned.
+	Assignee      string `protobuf:"bytes,11,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksRequest) Reset() {
+	*x = ListTasksRequest{}
+	mi := &file_todo_v2_todo_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksRequest) ProtoMessage() {}
+
+func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v2_todo_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
+func (*ListTasksRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListTasksRequest) GetState() Task_State {
+	if x != nil {
+		return x.State
+	}
+	return Task_STATE_UNSPECIFIED
+}
+
+func (x *ListTasksRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetStarred() bool {
+	if x != nil {
+		return x.Starred
+	}
+	return false
+}
+
+func (x *ListTasksRequest) GetDueAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueAfter
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetDueBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueBefore
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetOverdue() bool {
+	if x != nil {
+		return x.Overdue
+	}
+	return false
+}
+
+func (x *ListTasksRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListTasksRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetAssignee() string {
+	if x != nil {
+		return x.Assignee
+	}
+	return ""
+}
+
+type ListTasksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The tasks on the requested page.
+	Tasks []*Task `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	// The token to retrieve the next page, or empty if this is the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksResponse) Reset() {
+	*x = ListTasksResponse{}
+	mi := &file_todo_v2_todo_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksResponse) ProtoMessage() {}
+
+func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v2_todo_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksResponse.ProtoReflect.Descriptor instead.
+func (*ListTasksResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListTasksResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *ListTasksResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The ID of the task to retrieve.
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskRequest) Reset() {
+	*x = GetTaskRequest{}
+	mi := &file_todo_v2_todo_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskRequest) ProtoMessage() {}
+
+func (x *GetTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v2_todo_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UpdateTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The task to update, identified by its ID, with the new values of the
+	// fields to update.
+	Task *Task `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	// The fields to update. Over REST, it defaults to the fields present in the
+	// request body.
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// If set, the update is only applied if the task's current version matches
+	// the expected version. Otherwise, the request fails with ABORTED.
+	ExpectedVersion uint64 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateTaskRequest) Reset() {
+	*x = UpdateTaskRequest{}
+	mi := &file_todo_v2_todo_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTaskRequest) ProtoMessage() {}
+
+func (x *UpdateTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v2_todo_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTaskRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdateTaskRequest) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *UpdateTaskRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateTaskRequest) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type DeleteTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The ID of the task to delete.
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTaskRequest) Reset() {
+	*x = DeleteTaskRequest{}
+	mi := &file_todo_v2_todo_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTaskRequest) ProtoMessage() {}
+
+func (x *DeleteTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v2_todo_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTaskRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v2_todo_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeleteTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+var File_todo_v2_todo_proto protoreflect.FileDescriptor
+
+const file_todo_v2_todo_proto_rawDesc = "" +
+	"\n" +
+	"\x12todo/v2/todo.proto\x12\atodo.v2\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb6\x06\n" +
+	"\x04Task\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\asummary\x18\x02 \x01(\tR\asummary\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12)\n" +
+	"\x05state\x18\x04 \x01(\x0e2\x13.todo.v2.Task.StateR\x05state\x12;\n" +
+	"\vcreate_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\x12;\n" +
+	"\vupdate_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"updateTime\x12?\n" +
+	"\rcomplete_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\fcompleteTime\x125\n" +
+	"\bdue_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\adueTime\x12$\n" +
+	"\x0edue_time_local\x18\t \x01(\tR\fdueTimeLocal\x12\x1b\n" +
+	"\ttime_zone\x18\n" +
+	" \x01(\tR\btimeZone\x12\x1e\n" +
+	"\n" +
+	"recurrence\x18\v \x01(\tR\n" +
+	"recurrence\x12\x12\n" +
+	"\x04tags\x18\f \x03(\tR\x04tags\x12\x18\n" +
+	"\aproject\x18\r \x01(\tR\aproject\x12\x18\n" +
+	"\astarred\x18\x0e \x01(\bR\astarred\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18\x0f \x03(\tR\tdependsOn\x12\x1d\n" +
+	"\n" +
+	"blocked_by\x18\x10 \x03(\tR\tblockedBy\x12\x1a\n" +
+	"\bposition\x18\x11 \x01(\x03R\bposition\x12\x18\n" +
+	"\aversion\x18\x12 \x01(\x04R\aversion\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x13 \x01(\tR\tshortCode\x12\x10\n" +
+	"\x03uid\x18\x14 \x01(\tR\x03uid\x12\x1a\n" +
+	"\bassignee\x18\x15 \x01(\tR\bassignee\x12\x14\n" +
+	"\x05links\x18\x16 \x03(\tR\x05links\"C\n" +
+	"\x05State\x12\x15\n" +
+	"\x11STATE_UNSPECIFIED\x10\x00\x12\x0e\n" +
+	"\n" +
+	"STATE_OPEN\x10\x01\x12\x13\n" +
+	"\x0fSTATE_COMPLETED\x10\x02\"\x88\x01\n" +
+	"\x11CreateTaskRequest\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v2.TaskR\x04task\x12'\n" +
+	"\x0fallow_duplicate\x18\x02 \x01(\bR\x0eallowDuplicate\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\"\x86\x03\n" +
+	"\x10ListTasksRequest\x12)\n" +
+	"\x05state\x18\x01 \x01(\x0e2\x13.todo.v2.Task.StateR\x05state\x12\x12\n" +
+	"\x04tags\x18\x02 \x03(\tR\x04tags\x12\x18\n" +
+	"\aproject\x18\x03 \x01(\tR\aproject\x12\x18\n" +
+	"\astarred\x18\x04 \x01(\bR\astarred\x127\n" +
+	"\tdue_after\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\bdueAfter\x129\n" +
+	"\n" +
+	"due_before\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tdueBefore\x12\x18\n" +
+	"\aoverdue\x18\a \x01(\bR\aoverdue\x12\x19\n" +
+	"\border_by\x18\b \x01(\tR\aorderBy\x12\x1b\n" +
+	"\tpage_size\x18\t \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\n" +
+	" \x01(\tR\tpageToken\x12\x1a\n" +
+	"\bassignee\x18\v \x01(\tR\bassignee\"`\n" +
+	"\x11ListTasksResponse\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.todo.v2.TaskR\x05tasks\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\" \n" +
+	"\x0eGetTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x9e\x01\n" +
+	"\x11UpdateTaskRequest\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v2.TaskR\x04task\x12;\n" +
+	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x04R\x0fexpectedVersion\"#\n" +
+	"\x11DeleteTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id2\xb7\x03\n" +
+	"\vTaskService\x12P\n" +
+	"\n" +
+	"CreateTask\x12\x1a.todo.v2.CreateTaskRequest\x1a\r.todo.v2.Task\"\x17\x82\xd3\xe4\x93\x02\x11:\x04task\"\t/v2/tasks\x12U\n" +
+	"\tListTasks\x12\x19.todo.v2.ListTasksRequest\x1a\x1a.todo.v2.ListTasksResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/v2/tasks\x12I\n" +
+	"\aGetTask\x12\x17.todo.v2.GetTaskRequest\x1a\r.todo.v2.Task\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v2/tasks/{id}\x12Z\n" +
+	"\n" +
+	"UpdateTask\x12\x1a.todo.v2.UpdateTaskRequest\x1a\r.todo.v2.Task\"!\x82\xd3\xe4\x93\x02\x1b:\x04task2\x13/v2/tasks/{task.id}\x12X\n" +
+	"\n" +
+	"DeleteTask\x12\x1a.todo.v2.DeleteTaskRequest\x1a\x16.google.protobuf.Empty\"\x16\x82\xd3\xe4\x93\x02\x10*\x0e/v2/tasks/{id}B,Z*github.com/mwopitz/todo-daemon/api/v2/todob\x06proto3"
+
+var (
+	file_todo_v2_todo_proto_rawDescOnce sync.Once
+	file_todo_v2_todo_proto_rawDescData []byte
+)
+
+func file_todo_v2_todo_proto_rawDescGZIP() []byte {
+	file_todo_v2_todo_proto_rawDescOnce.Do(func() {
+		file_todo_v2_todo_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_todo_v2_todo_proto_rawDesc), len(file_todo_v2_todo_proto_rawDesc)))
+	})
+	return file_todo_v2_todo_proto_rawDescData
+}
+
+var file_todo_v2_todo_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_todo_v2_todo_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_todo_v2_todo_proto_goTypes = []any{
+	(Task_State)(0),               // 0: todo.v2.Task.State
+	(*Task)(nil),                  // 1: todo.v2.Task
+	(*CreateTaskRequest)(nil),     // 2: todo.v2.CreateTaskRequest
+	(*ListTasksRequest)(nil),      // 3: todo.v2.ListTasksRequest
+	(*ListTasksResponse)(nil),     // 4: todo.v2.ListTasksResponse
+	(*GetTaskRequest)(nil),        // 5: todo.v2.GetTaskRequest
+	(*UpdateTaskRequest)(nil),     // 6: todo.v2.UpdateTaskRequest
+	(*DeleteTaskRequest)(nil),     // 7: todo.v2.DeleteTaskRequest
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil), // 9: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),         // 10: google.protobuf.Empty
+}
+var file_todo_v2_todo_proto_depIdxs = []int32{
+	0,  // 0: todo.v2.Task.state:type_name -> todo.v2.Task.State
+	8,  // 1: todo.v2.Task.create_time:type_name -> google.protobuf.Timestamp
+	8,  // 2: todo.v2.Task.update_time:type_name -> google.protobuf.Timestamp
+	8,  // 3: todo.v2.Task.complete_time:type_name -> google.protobuf.Timestamp
+	8,  // 4: todo.v2.Task.due_time:type_name -> google.protobuf.Timestamp
+	1,  // 5: todo.v2.CreateTaskRequest.task:type_name -> todo.v2.Task
+	0,  // 6: todo.v2.ListTasksRequest.state:type_name -> todo.v2.Task.State
+	8,  // 7: todo.v2.ListTasksRequest.due_after:type_name -> google.protobuf.Timestamp
+	8,  // 8: todo.v2.ListTasksRequest.due_before:type_name -> google.protobuf.Timestamp
+	1,  // 9: todo.v2.ListTasksResponse.tasks:type_name -> todo.v2.Task
+	1,  // 10: todo.v2.UpdateTaskRequest.task:type_name -> todo.v2.Task
+	9,  // 11: todo.v2.UpdateTaskRequest.update_mask:type_name -> google.protobuf.FieldMask
+	2,  // 12: todo.v2.TaskService.CreateTask:input_type -> todo.v2.CreateTaskRequest
+	3,  // 13: todo.v2.TaskService.ListTasks:input_type -> todo.v2.ListTasksRequest
+	5,  // 14: todo.v2.TaskService.GetTask:input_type -> todo.v2.GetTaskRequest
+	6,  // 15: todo.v2.TaskService.UpdateTask:input_type -> todo.v2.UpdateTaskRequest
+	7,  // 16: todo.v2.TaskService.DeleteTask:input_type -> todo.v2.DeleteTaskRequest
+	1,  // 17: todo.v2.TaskService.CreateTask:output_type -> todo.v2.Task
+	4,  // 18: todo.v2.TaskService.ListTasks:output_type -> todo.v2.ListTasksResponse
+	1,  // 19: todo.v2.TaskService.GetTask:output_type -> todo.v2.Task
+	1,  // 20: todo.v2.TaskService.UpdateTask:output_type -> todo.v2.Task
+	10, // 21: todo.v2.TaskService.DeleteTask:output_type -> google.protobuf.Empty
+	17, // [17:22] is the sub-list for method output_type
+	12, // [12:17] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_todo_v2_todo_proto_init() }
+func file_todo_v2_todo_proto_init() {
+	if File_todo_v2_todo_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_todo_v2_todo_proto_rawDesc), len(file_todo_v2_todo_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_todo_v2_todo_proto_goTypes,
+		DependencyIndexes: file_todo_v2_todo_proto_depIdxs,
+		EnumInfos:         file_todo_v2_todo_proto_enumTypes,
+		MessageInfos:      file_todo_v2_todo_proto_msgTypes,
+	}.Build()
+	File_todo_v2_todo_proto = out.File
+	file_todo_v2_todo_proto_goTypes = nil
+	file_todo_v2_todo_proto_depIdxs = nil
+}
@@ -0,0 +1,167 @@
+// Package backup implements the scheduled snapshots of the To-do Daemon's
+// task repository, which are written to a directory and optionally uploaded
+// to S3-compatible object storage.
+package backup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/logging"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+const (
+	// filePrefix and fileSuffix enclose the timestamp in the names of the
+	// snapshot files.
+	filePrefix = "todo-daemon-"
+	fileSuffix = ".json.gz"
+	// timeLayout is the layout of the timestamp in the names of the snapshot
+	// files, which sorts lexically in chronological order.
+	timeLayout = "20060102T150405Z"
+)
+
+// FileName returns the name of the snapshot file for the specified time, e.g.
+// "todo-daemon-20250102T150405Z.json.gz".
+func FileName(t time.Time) string {
+	return filePrefix + t.UTC().Format(timeLayout) + fileSuffix
+}
+
+// Scheduler writes snapshots of a task repository to a directory and removes
+// old snapshots. The server runs [Scheduler.Backup] as a periodic background
+// job.
+type Scheduler struct {
+	// Dir is the directory that the snapshots are written to.
+	Dir string
+	// Interval is the time between two snapshots.
+	Interval time.Duration
+	// Retain is the number of snapshots to keep. If Retain <= 0, all
+	// snapshots are kept.
+	Retain int
+	// Tasks is the repository to take the snapshots of.
+	Tasks todo.TaskRepository
+	// Remote is the object storage that the snapshots are uploaded to,
+	// encrypted, in addition to the directory. If nil, the snapshots are only
+	// written to the directory.
+	Remote *Remote
+}
+
+// Backup writes a snapshot, uploads it if a remote is configured, and removes
+// the old snapshots that exceed Retain and Remote.Retain, respectively.
+func (s *Scheduler) Backup(ctx context.Context) error {
+	path, err := s.Snapshot(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot write scheduled snapshot: %w", err)
+	}
+	logger().Info("wrote scheduled snapshot", "path", path)
+	if s.Remote != nil {
+		if err := s.upload(ctx, path); err != nil {
+			return fmt.Errorf("cannot upload scheduled snapshot: %w", err)
+		}
+	}
+	if err := s.prune(); err != nil {
+		logger().Warn("cannot remove old snapshots", "cause", err)
+	}
+	return nil
+}
+
+// upload uploads the snapshot file at the specified path to the remote and
+// removes the old snapshots from the bucket.
+func (s *Scheduler) upload(ctx context.Context, path string) error {
+	archive, err := os.ReadFile(path) // #nosec G304 -- the path is in the backup directory.
+	if err != nil {
+		return err
+	}
+	key, err := s.Remote.Upload(ctx, filepath.Base(path), archive)
+	if err != nil {
+		return err
+	}
+	logger().Info("uploaded scheduled snapshot", "url", s.Remote.URL(key))
+	if err := s.Remote.prune(ctx); err != nil {
+		logger().Warn("cannot remove old remote snapshots", "cause", err)
+	}
+	return nil
+}
+
+// Snapshot writes a snapshot of the repository to the directory and returns
+// the path to the snapshot file.
+func (s *Scheduler) Snapshot(ctx context.Context) (string, error) {
+	tasks, err := s.Tasks.List(ctx, &todo.ListOptions{IncludeDeleted: true})
+	if err != nil {
+		return "", fmt.Errorf("cannot retrieve tasks: %w", err)
+	}
+	snapshot := todo.NewSnapshot(tasks)
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(s.Dir, FileName(snapshot.CreatedAt))
+
+	// Write to a temporary file first, so there are no partial snapshots.
+	f, err := os.CreateTemp(s.Dir, filePrefix+"*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := os.Remove(f.Name()); err != nil && !os.IsNotExist(err) {
+			logger().Warn("cannot remove temporary snapshot file", "path", f.Name(), "cause", err)
+		}
+	}()
+	if err := todo.WriteSnapshot(f, snapshot); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Files returns the names of the snapshot files in the specified directory,
+// oldest first. A missing directory has no snapshot files.
+func Files(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.Type().IsRegular() && strings.HasPrefix(name, filePrefix) && strings.HasSuffix(name, fileSuffix) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// prune removes the oldest snapshots, so at most Retain snapshots are left.
+func (s *Scheduler) prune() error {
+	if s.Retain <= 0 {
+		return nil
+	}
+	names, err := Files(s.Dir)
+	if err != nil {
+		return err
+	}
+	for len(names) > s.Retain {
+		if err := os.Remove(filepath.Join(s.Dir, names[0])); err != nil {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
+
+// logger returns the logger of the messages about the scheduled backups.
+func logger() *slog.Logger {
+	return logging.Component(logging.ComponentBackup)
+}
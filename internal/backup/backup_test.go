@@ -0,0 +1,70 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	db := todo.NewInMemoryTaskDB()
+	if _, err := db.Create(ctx, &todo.TaskCreate{Summary: "foo", Description: "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	s := &Scheduler{Dir: t.TempDir(), Tasks: db}
+	path, err := s.Snapshot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	snapshot, err := todo.ReadSnapshot(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks := snapshot.TaskList()
+	if len(tasks) != 1 || tasks[0].Summary != "foo" || tasks[0].Description != "bar" {
+		t.Errorf("unexpected tasks in snapshot: %+v", tasks)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	for i := range 4 {
+		name := FileName(start.Add(time.Duration(i) * time.Hour))
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := &Scheduler{Dir: dir, Retain: 2}
+	if err := s.prune(); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	want := []string{FileName(start.Add(2 * time.Hour)), FileName(start.Add(3 * time.Hour))}
+	if !slices.Equal(got, want) {
+		t.Errorf("want: %v; got: %v", want, got)
+	}
+}
@@ -0,0 +1,92 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+const (
+	// encryptedMagic is the header of encrypted snapshots, which identifies
+	// the format and its version.
+	encryptedMagic = "TDENC\x00\x00\x01"
+	// saltSize is the size of the random salt used for deriving the key.
+	saltSize = 16
+	// kdfIterations is the number of PBKDF2-HMAC-SHA256 iterations used for
+	// deriving the key from the passphrase.
+	kdfIterations = 600_000
+)
+
+// ErrDecrypt is returned by [Decrypt] if the passphrase is wrong or the
+// encrypted snapshot is corrupt.
+var ErrDecrypt = errors.New("cannot decrypt snapshot: wrong passphrase or corrupt data")
+
+// Encrypt encrypts a snapshot with AES-256-GCM, using a key derived from the
+// passphrase with PBKDF2. The result starts with a header and the random salt
+// and nonce, so it can be decrypted with [Decrypt] and the passphrase alone.
+func Encrypt(data []byte, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("no passphrase specified")
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, len(encryptedMagic)+len(salt)+len(nonce)+len(data)+aead.Overhead())
+	out = append(out, encryptedMagic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	// The header is authenticated, too, so it cannot be swapped.
+	return aead.Seal(out, nonce, data, out[:len(encryptedMagic)]), nil
+}
+
+// Decrypt decrypts a snapshot encrypted with [Encrypt].
+func Decrypt(data []byte, passphrase string) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(encryptedMagic)) {
+		return nil, errors.New("cannot decrypt snapshot: not an encrypted snapshot")
+	}
+	rest := data[len(encryptedMagic):]
+	if len(rest) < saltSize {
+		return nil, ErrDecrypt
+	}
+	aead, err := newAEAD(passphrase, rest[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	rest = rest[saltSize:]
+	if len(rest) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	plain, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], data[:len(encryptedMagic)])
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
+
+// newAEAD derives the key from the passphrase and salt and returns the
+// AES-256-GCM cipher using it.
+func newAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, kdfIterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("cannot derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,154 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mwopitz/todo-daemon/internal/config"
+)
+
+// encryptedSuffix is appended to the names of the snapshot files in the
+// object storage, e.g. "todo-daemon-20250102T150405Z.json.gz.enc".
+const encryptedSuffix = ".enc"
+
+// ParseS3URL splits a URL like "s3://bucket/key" into the bucket and the key,
+// which may be empty.
+func ParseS3URL(s string) (bucket, key string, err error) {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme != "s3" || u.Host == "" {
+		return "", "", fmt.Errorf("invalid S3 URL '%s', want s3://bucket/key", s)
+	}
+	return u.Host, strings.TrimPrefix(u.Path, "/"), nil
+}
+
+// Remote uploads encrypted snapshots to a bucket in S3-compatible object
+// storage and removes old ones.
+type Remote struct {
+	// Client is the client of the object storage.
+	Client *S3Client
+	// Bucket is the bucket that the snapshots are uploaded to.
+	Bucket string
+	// Prefix is prepended to the names of the snapshots, e.g. "todo/".
+	Prefix string
+	// Passphrase is the passphrase that the snapshots are encrypted with.
+	Passphrase string
+	// Retain is the number of snapshots to keep in the bucket. If Retain <= 0,
+	// all snapshots are kept.
+	Retain int
+}
+
+// errNoPassphrase is returned if remote backups are used without a
+// passphrase.
+var errNoPassphrase = errors.New("no passphrase for encrypting remote backups specified")
+
+// NewS3Client creates the client of the object storage in the specified
+// configuration.
+func NewS3Client(conf config.BackupRemote) *S3Client {
+	return &S3Client{
+		Endpoint:        conf.Endpoint,
+		Region:          conf.Region,
+		AccessKeyID:     conf.AccessKeyID,
+		SecretAccessKey: conf.SecretAccessKey,
+	}
+}
+
+// NewRemote creates a [Remote] for the specified configuration, whose URL is
+// like "s3://bucket/prefix/". A prefix without a trailing slash gets one, so
+// the snapshots end up in a "directory" of the bucket.
+func NewRemote(conf config.BackupRemote) (*Remote, error) {
+	if conf.URL == "" {
+		return nil, errors.New("remote backups are not configured")
+	}
+	bucket, prefix, err := ParseS3URL(conf.URL)
+	if err != nil {
+		return nil, err
+	}
+	if conf.Passphrase == "" {
+		return nil, errNoPassphrase
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &Remote{
+		Client:     NewS3Client(conf),
+		Bucket:     bucket,
+		Prefix:     prefix,
+		Passphrase: conf.Passphrase,
+		Retain:     conf.Retain,
+	}, nil
+}
+
+// URL returns the URL of the object with the specified key in the bucket.
+func (r *Remote) URL(key string) string {
+	return "s3://" + r.Bucket + "/" + key
+}
+
+// Upload encrypts the archive of the snapshot file with the specified name,
+// see [FileName], uploads it, and returns the key of the object.
+func (r *Remote) Upload(ctx context.Context, name string, archive []byte) (string, error) {
+	data, err := Encrypt(archive, r.Passphrase)
+	if err != nil {
+		return "", err
+	}
+	key := r.Prefix + name + encryptedSuffix
+	if err := r.Client.PutObject(ctx, r.Bucket, key, data); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// List returns the encrypted snapshots in the bucket, oldest first.
+func (r *Remote) List(ctx context.Context) ([]S3Object, error) {
+	objects, err := r.Client.ListObjects(ctx, r.Bucket, r.Prefix+filePrefix)
+	if err != nil {
+		return nil, err
+	}
+	snapshots := objects[:0]
+	for _, o := range objects {
+		name := strings.TrimPrefix(o.Key, r.Prefix)
+		if !strings.Contains(name, "/") && strings.HasSuffix(name, fileSuffix+encryptedSuffix) {
+			snapshots = append(snapshots, o)
+		}
+	}
+	return snapshots, nil
+}
+
+// Download downloads a snapshot from the specified bucket and returns the
+// archive, decrypting it if it is encrypted. Unencrypted snapshots, e.g. ones
+// uploaded by hand, are returned as they are.
+func (r *Remote) Download(ctx context.Context, bucket, key string) ([]byte, error) {
+	data, err := r.Client.GetObject(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte(encryptedMagic)) {
+		return data, nil
+	}
+	if r.Passphrase == "" {
+		return nil, errNoPassphrase
+	}
+	return Decrypt(data, r.Passphrase)
+}
+
+// prune removes the oldest snapshots from the bucket, so at most Retain
+// snapshots are left.
+func (r *Remote) prune(ctx context.Context) error {
+	if r.Retain <= 0 {
+		return nil
+	}
+	snapshots, err := r.List(ctx)
+	if err != nil {
+		return err
+	}
+	for len(snapshots) > r.Retain {
+		if err := r.Client.DeleteObject(ctx, r.Bucket, snapshots[0].Key); err != nil {
+			return err
+		}
+		snapshots = snapshots[1:]
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultS3Region is the region used for signing requests to S3-compatible
+// object storage if no region is configured. MinIO accepts it by default.
+const DefaultS3Region = "us-east-1"
+
+// S3Client is a minimal client of S3-compatible object storage, e.g. Amazon S3
+// or MinIO. It supports only the operations needed for storing snapshots, and
+// always uses path-style URLs like "https://endpoint/bucket/key", which all
+// S3-compatible services support.
+type S3Client struct {
+	// Endpoint is the base URL of the service, e.g. "http://localhost:9000".
+	// If empty, the Amazon S3 endpoint of the region is used, e.g.
+	// "https://s3.eu-central-1.amazonaws.com".
+	Endpoint string
+	// Region is the region used for signing the requests. If empty,
+	// [DefaultS3Region] is used.
+	Region string
+	// AccessKeyID and SecretAccessKey are the credentials used for signing
+	// the requests.
+	AccessKeyID     string
+	SecretAccessKey string
+	// HTTPClient sends the requests. If nil, [http.DefaultClient] is used.
+	HTTPClient *http.Client
+
+	// now returns the current time; it is replaced by the tests.
+	now func() time.Time
+}
+
+// S3Object describes an object in a bucket.
+type S3Object struct {
+	// Key is the key of the object.
+	Key string
+	// Size is the size of the object in bytes.
+	Size int64
+	// LastModified is the time the object was last modified.
+	LastModified time.Time
+}
+
+// S3Error is returned by the [S3Client] if the service responds with an error.
+type S3Error struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Code is the S3 error code, e.g. "NoSuchKey", if the response has one.
+	Code string
+	// Message is the error message of the response.
+	Message string
+}
+
+func (e *S3Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("object storage responded with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("object storage responded with status %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// PutObject uploads an object with the specified content.
+func (c *S3Client) PutObject(ctx context.Context, bucket, key string, data []byte) error {
+	_, err := c.do(ctx, http.MethodPut, bucket, key, nil, data)
+	return err
+}
+
+// GetObject downloads the content of an object.
+func (c *S3Client) GetObject(ctx context.Context, bucket, key string) ([]byte, error) {
+	return c.do(ctx, http.MethodGet, bucket, key, nil, nil)
+}
+
+// DeleteObject deletes an object. Deleting a missing object is not an error.
+func (c *S3Client) DeleteObject(ctx context.Context, bucket, key string) error {
+	_, err := c.do(ctx, http.MethodDelete, bucket, key, nil, nil)
+	return err
+}
+
+// listBucketResult is the response to a ListObjectsV2 request.
+type listBucketResult struct {
+	Contents              []listBucketObject `xml:"Contents"`
+	IsTruncated           bool               `xml:"IsTruncated"`
+	NextContinuationToken string             `xml:"NextContinuationToken"`
+}
+
+// listBucketObject describes an object in a [listBucketResult].
+type listBucketObject struct {
+	Key          string    `xml:"Key"`
+	Size         int64     `xml:"Size"`
+	LastModified time.Time `xml:"LastModified"`
+}
+
+// ListObjects returns the objects in a bucket whose keys start with the
+// specified prefix, sorted by key.
+func (c *S3Client) ListObjects(ctx context.Context, bucket, prefix string) ([]S3Object, error) {
+	var objects []S3Object
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		data, err := c.do(ctx, http.MethodGet, bucket, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result listBucketResult
+		if err := xml.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("invalid response from object storage: %w", err)
+		}
+		for _, o := range result.Contents {
+			objects = append(objects, S3Object{Key: o.Key, Size: o.Size, LastModified: o.LastModified})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+	slices.SortFunc(objects, func(a, b S3Object) int { return strings.Compare(a.Key, b.Key) })
+	return objects, nil
+}
+
+// do sends a signed request and returns the body of the response if it
+// succeeded, or an [S3Error] otherwise.
+func (c *S3Client) do(ctx context.Context, method, bucket, key string, query url.Values, body []byte) ([]byte, error) {
+	if bucket == "" {
+		return nil, errors.New("no bucket specified")
+	}
+	rawEndpoint := c.Endpoint
+	if rawEndpoint == "" {
+		rawEndpoint = "https://s3." + c.region() + ".amazonaws.com"
+	}
+	endpoint, err := url.Parse(rawEndpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid object storage endpoint '%s'", rawEndpoint)
+	}
+	u := *endpoint
+	u.Path = strings.TrimSuffix(endpoint.Path, "/") + "/" + bucket
+	u.RawPath = strings.TrimSuffix(endpoint.EscapedPath(), "/") + "/" + uriEncode(bucket, false)
+	if key != "" {
+		u.Path += "/" + key
+		u.RawPath += "/" + uriEncode(key, true)
+	}
+	u.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	c.sign(req, body)
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	if cerr := resp.Body.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return data, nil
+	}
+	e := &S3Error{StatusCode: resp.StatusCode}
+	var result struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if xml.Unmarshal(data, &result) == nil {
+		e.Code, e.Message = result.Code, result.Message
+	}
+	return nil, e
+}
+
+// sign adds the headers of AWS Signature Version 4 to the request, see
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html.
+func (c *S3Client) sign(req *http.Request, body []byte) {
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	t := now().UTC()
+	date := t.Format("20060102")
+	region := c.region()
+	payloadHash := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Date", t.Format("20060102T150405Z"))
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	// The canonical headers include the host and all headers set so far,
+	// sorted by their lowercase names.
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	scope := date + "/" + region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + t.Format("20060102T150405Z") + "\n" + scope + "\n" +
+		hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+c.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+c.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// region returns the configured region or [DefaultS3Region].
+func (c *S3Client) region() string {
+	if c.Region == "" {
+		return DefaultS3Region
+	}
+	return c.Region
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// canonicalQuery encodes the query parameters sorted by name, as required for
+// signing the request.
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var params []string
+	for _, name := range names {
+		for _, value := range query[name] {
+			params = append(params, uriEncode(name, false)+"="+uriEncode(value, false))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// uriEncode percent-encodes all bytes of s except the unreserved characters,
+// and the slashes if requested, as required for signing the request.
+func uriEncode(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,68 @@
+// Package browser opens URLs in the user's default web browser.
+//
+// It runs the platform's opener: xdg-open on Linux and the BSDs, open on
+// macOS, and the URL protocol handler on Windows. It only opens http and https
+// URLs, so that a link of a task cannot make the opener run a program or open
+// a local file.
+package browser
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+)
+
+// ErrUnsupportedPlatform is returned by [Open] if there is no known way to
+// open a URL on the current platform.
+var ErrUnsupportedPlatform = fmt.Errorf("cannot open URLs on %s", runtime.GOOS)
+
+// Open opens the specified URL in the default web browser. It returns once the
+// opener has been started, without waiting for the browser.
+func Open(rawURL string) error {
+	if err := check(rawURL); err != nil {
+		return err
+	}
+	name, args, err := command(runtime.GOOS, rawURL)
+	if err != nil {
+		return err
+	}
+	// #nosec G204 -- the URL is checked to be an http or https URL.
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("cannot open %s: %w", rawURL, err)
+	}
+	// The opener exits as soon as it has handed the URL over to the browser,
+	// so reap it in the background.
+	go func() {
+		_ = cmd.Wait()
+	}()
+	return nil
+}
+
+// check checks that the specified URL is an absolute http or https URL.
+func check(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("cannot open %s: %w", rawURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("cannot open %s: not an http or https URL", rawURL)
+	}
+	return nil
+}
+
+// command returns the name and the arguments of the command that opens the
+// specified URL on the specified operating system.
+func command(goos, rawURL string) (string, []string, error) {
+	switch goos {
+	case "linux", "freebsd", "openbsd", "netbsd", "dragonfly", "solaris", "illumos":
+		return "xdg-open", []string{rawURL}, nil
+	case "darwin":
+		return "open", []string{rawURL}, nil
+	case "windows":
+		return "rundll32", []string{"url.dll,FileProtocolHandler", rawURL}, nil
+	default:
+		return "", nil, ErrUnsupportedPlatform
+	}
+}
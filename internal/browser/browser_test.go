@@ -0,0 +1,44 @@
+package browser
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	const u = "https://example.com/tickets/42"
+	tests := []struct {
+		goos     string
+		wantName string
+		wantArgs []string
+	}{
+		{"linux", "xdg-open", []string{u}},
+		{"freebsd", "xdg-open", []string{u}},
+		{"darwin", "open", []string{u}},
+		{"windows", "rundll32", []string{"url.dll,FileProtocolHandler", u}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			name, args, err := command(tt.goos, u)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if name != tt.wantName || !slices.Equal(args, tt.wantArgs) {
+				t.Errorf("want %s %q; got: %s %q", tt.wantName, tt.wantArgs, name, args)
+			}
+		})
+	}
+
+	if _, _, err := command("plan9", u); !errors.Is(err, ErrUnsupportedPlatform) {
+		t.Errorf("want ErrUnsupportedPlatform; got: %v", err)
+	}
+}
+
+func TestOpenRejectsUnsafeURLs(t *testing.T) {
+	for _, u := range []string{"", "example.com", "file:///etc/passwd", "javascript:alert(1)", "-a Calculator"} {
+		if err := Open(u); err == nil {
+			t.Errorf("want error for %q; got: nil", u)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Package backup implements the 'backup' command of the To-do Daemon CLI.
+//
+// The 'backup' command provides subcommands for creating snapshots of the
+// to-do list, listing the scheduled snapshots, and restoring them.
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/backup/create"
+	"github.com/mwopitz/todo-daemon/internal/cli/backup/list"
+	"github.com/mwopitz/todo-daemon/internal/cli/backup/restore"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// NewCommand creates a new 'backup' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "backup",
+		Usage: "Back up and restore the to-do list",
+		Commands: []*cli.Command{
+			create.NewCommand(conf),
+			list.NewCommand(conf),
+			restore.NewCommand(conf),
+		},
+		CommandNotFound: func(_ context.Context, _ *cli.Command, name string) {
+			// revive:disable-next-line:unhandled-error
+			fmt.Fprintf(os.Stderr, "todo-daemon: %s: '%s'\n", i18n.Translate("invalid command"), name)
+		},
+	}
+}
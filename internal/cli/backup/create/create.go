@@ -0,0 +1,102 @@
+// Package create implements the 'create' subcommand of the To-do Daemon CLI's
+// 'backup' command.
+//
+// The 'create' subcommand writes a snapshot of the entire to-do list to a
+// file.
+package create
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/backup"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// Executor is used for executing the 'create' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewClient creates the client for connecting to the To-do Daemon
+	// server.
+	NewClient client.Factory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+	// Path is the path to the snapshot file to be written.
+	Path string
+}
+
+// NewExecutor creates an executor for the specified 'create' command.
+func NewExecutor(cmd *cli.Command) (*Executor, error) {
+	path := cmd.StringArg("path")
+	if path == "" {
+		path = backup.FileName(time.Now())
+	}
+	return &Executor{
+		SockFile:  cmd.String("sock"),
+		Timeout:   cmd.Duration("timeout"),
+		NewClient: client.New,
+		Stdout:    cmd.Root().Writer,
+		Path:      path,
+		Quiet:     cmd.Bool("quiet"),
+	}, nil
+}
+
+// Execute executes the 'create' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewClient(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	resp, err := c.CreateBackup(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot create backup: %w", err)
+	}
+	if err := os.WriteFile(e.Path, resp.GetArchive(), 0o600); err != nil {
+		return fmt.Errorf("cannot write backup: %w", err)
+	}
+	if e.Quiet {
+		return nil
+	}
+
+	// revive:disable-next-line:unhandled-error
+	fmt.Fprintln(e.Stdout, i18n.Sprintf("Backed up %d tasks to %s", resp.GetTaskCount(), e.Path))
+	return nil
+}
+
+// NewCommand creates a new 'create' command with the specified configuration.
+func NewCommand(_ *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "create",
+		Usage: "Write a snapshot of the to-do list to a file",
+		Arguments: []cli.Argument{
+			&cli.StringArg{Name: "path"},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
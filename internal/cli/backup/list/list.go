@@ -0,0 +1,140 @@
+// Package list implements the 'list' subcommand of the To-do Daemon CLI's
+// 'backup' command.
+//
+// The 'list' subcommand prints the scheduled snapshots in the backup
+// directory, or the encrypted snapshots in the object storage if the --remote
+// flag is set.
+package list
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/backup"
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// Executor is used for executing the 'list' command.
+type Executor struct {
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Dir is the directory of the scheduled snapshots.
+	Dir string
+	// Remote is the object storage whose snapshots are listed instead of
+	// those in Dir, or nil.
+	Remote *backup.Remote
+}
+
+// snapshot is a row of the output.
+type snapshot struct {
+	name string
+	size int64
+	time time.Time
+}
+
+// NewExecutor creates an executor for the specified 'list' command.
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	e := &Executor{
+		Stdout: cmd.Root().Writer,
+		Dir:    conf.Backup.Dir,
+	}
+	if cmd.Bool("remote") {
+		remote, err := backup.NewRemote(conf.Backup.Remote)
+		if err != nil {
+			return nil, exitcode.NewUsageError("%w", err)
+		}
+		e.Remote = remote
+	}
+	return e, nil
+}
+
+// Execute executes the 'list' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	var snapshots []snapshot
+	var err error
+	if e.Remote != nil {
+		snapshots, err = e.listRemote(ctx)
+	} else {
+		snapshots, err = e.listLocal()
+	}
+	if err != nil {
+		return fmt.Errorf("cannot list backups: %w", err)
+	}
+	if len(snapshots) == 0 {
+		_, err := fmt.Fprintln(e.Stdout, i18n.Translate("No backups"))
+		return err
+	}
+	tw := tabwriter.NewWriter(e.Stdout, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "TIME\tSIZE\tSNAPSHOT"); err != nil {
+		return err
+	}
+	loc := clifmt.Location(e.Stdout)
+	for _, s := range snapshots {
+		if _, err := fmt.Fprintf(tw, "%s\t%d\t%s\n", s.time.In(loc).Format(time.DateTime), s.size, s.name); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// listLocal returns the snapshot files in the backup directory.
+func (e *Executor) listLocal() ([]snapshot, error) {
+	names, err := backup.Files(e.Dir)
+	if err != nil {
+		return nil, err
+	}
+	snapshots := make([]snapshot, 0, len(names))
+	for _, name := range names {
+		path := filepath.Join(e.Dir, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot{name: path, size: info.Size(), time: info.ModTime()})
+	}
+	return snapshots, nil
+}
+
+// listRemote returns the snapshots in the object storage.
+func (e *Executor) listRemote(ctx context.Context) ([]snapshot, error) {
+	objects, err := e.Remote.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snapshots := make([]snapshot, 0, len(objects))
+	for _, o := range objects {
+		snapshots = append(snapshots, snapshot{name: e.Remote.URL(o.Key), size: o.Size, time: o.LastModified})
+	}
+	return snapshots, nil
+}
+
+// NewCommand creates a new 'list' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "List the scheduled snapshots",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "remote",
+				Usage: "list the encrypted snapshots in the object storage instead",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd, conf)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
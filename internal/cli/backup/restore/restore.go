@@ -0,0 +1,149 @@
+// Package restore implements the 'restore' subcommand of the To-do Daemon
+// CLI's 'backup' command.
+//
+// The 'restore' subcommand replaces the entire to-do list with the content of
+// a snapshot file, or of a snapshot in S3-compatible object storage.
+package restore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/backup"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// Executor is used for executing the 'restore' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewClient creates the client for connecting to the To-do Daemon
+	// server.
+	NewClient client.Factory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+	// Path is the path to the snapshot file to be restored, or the URL of
+	// the snapshot in the object storage if Remote is set.
+	Path string
+	// Remote is the object storage that the snapshot is downloaded from, or
+	// nil if it is restored from a file.
+	Remote *backup.Remote
+}
+
+// NewExecutor creates an executor for the specified 'restore' command.
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	path := cmd.StringArg("path")
+	from := cmd.String("from")
+	if path != "" && from != "" {
+		return nil, exitcode.NewUsageError("cannot restore both a backup file and --from")
+	}
+	e := &Executor{
+		SockFile:  cmd.String("sock"),
+		Timeout:   cmd.Duration("timeout"),
+		NewClient: client.New,
+		Stdout:    cmd.Root().Writer,
+		Path:      path,
+		Quiet:     cmd.Bool("quiet"),
+	}
+	if from != "" {
+		_, key, err := backup.ParseS3URL(from)
+		if err != nil {
+			return nil, exitcode.NewUsageError("%w", err)
+		}
+		if key == "" {
+			return nil, exitcode.NewUsageError("no object key in '%s'", from)
+		}
+		// The bucket of the URL is used, so snapshots can be restored from
+		// other buckets than the configured one, e.g. on a new machine.
+		e.Path = from
+		e.Remote = &backup.Remote{
+			Client:     backup.NewS3Client(conf.Backup.Remote),
+			Passphrase: conf.Backup.Remote.Passphrase,
+		}
+	}
+	if e.Path == "" {
+		return nil, exitcode.NewUsageError("no backup file specified")
+	}
+	return e, nil
+}
+
+// Execute executes the 'restore' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	archive, err := e.read(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot read backup: %w", err)
+	}
+
+	c, err := e.NewClient(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	count, err := c.RestoreBackup(ctx, archive)
+	if err != nil {
+		return fmt.Errorf("cannot restore backup: %w", err)
+	}
+	if e.Quiet {
+		return nil
+	}
+
+	// revive:disable-next-line:unhandled-error
+	fmt.Fprintln(e.Stdout, i18n.Sprintf("Restored %d tasks from %s", count, e.Path))
+	return nil
+}
+
+// read returns the archive of the snapshot, downloading and decrypting it if
+// it is in the object storage.
+func (e *Executor) read(ctx context.Context) ([]byte, error) {
+	if e.Remote == nil {
+		return os.ReadFile(e.Path)
+	}
+	bucket, key, err := backup.ParseS3URL(e.Path)
+	if err != nil {
+		return nil, err
+	}
+	return e.Remote.Download(ctx, bucket, key)
+}
+
+// NewCommand creates a new 'restore' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "restore",
+		Usage: "Replace the to-do list with the content of a snapshot file",
+		Arguments: []cli.Argument{
+			&cli.StringArg{Name: "path"},
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "from",
+				Usage: "download the snapshot from object storage, e.g. s3://bucket/key",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd, conf)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
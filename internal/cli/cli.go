@@ -4,40 +4,299 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
+	"github.com/mwopitz/todo-daemon/internal/cli/backup"
+	"github.com/mwopitz/todo-daemon/internal/cli/debug"
+	"github.com/mwopitz/todo-daemon/internal/cli/doctor"
+	"github.com/mwopitz/todo-daemon/internal/cli/filters"
+	"github.com/mwopitz/todo-daemon/internal/cli/flush"
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
+	"github.com/mwopitz/todo-daemon/internal/cli/logs"
+	"github.com/mwopitz/todo-daemon/internal/cli/profiles"
+	cliproxy "github.com/mwopitz/todo-daemon/internal/cli/proxy"
+	"github.com/mwopitz/todo-daemon/internal/cli/reload"
 	"github.com/mwopitz/todo-daemon/internal/cli/run"
+	cliservice "github.com/mwopitz/todo-daemon/internal/cli/service"
+	"github.com/mwopitz/todo-daemon/internal/cli/stats"
 	"github.com/mwopitz/todo-daemon/internal/cli/status"
+	clisync "github.com/mwopitz/todo-daemon/internal/cli/sync"
 	"github.com/mwopitz/todo-daemon/internal/cli/tasks"
+	"github.com/mwopitz/todo-daemon/internal/cli/templates"
+	"github.com/mwopitz/todo-daemon/internal/client"
 	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+	"github.com/mwopitz/todo-daemon/internal/logging"
 	"github.com/mwopitz/todo-daemon/internal/version"
 )
 
 // NewTodoDaemonCommand creates the root command of the To-do Daemon CLI with
 // the specified configuration.
 func NewTodoDaemonCommand(conf *config.Config) *cli.Command {
-	return &cli.Command{
+	return localize(withUsageErrors(&cli.Command{
 		Name:    "todo-daemon",
 		Version: version.Semantic(),
 		Usage:   "A daemon for managing a to-do list",
 		Commands: []*cli.Command{
 			run.NewCommand(conf),
 			status.NewCommand(conf),
+			reload.NewCommand(conf),
+			logs.NewCommand(conf),
 			tasks.NewCommand(conf),
+			filters.NewCommand(conf),
+			templates.NewCommand(conf),
+			stats.NewCommand(conf),
+			backup.NewCommand(conf),
+			clisync.NewCommand(conf),
+			cliproxy.NewCommand(conf),
+			flush.NewCommand(conf),
+			profiles.NewCommand(conf),
+			cliservice.NewCommand(conf),
+			doctor.NewCommand(conf),
+			debug.NewCommand(conf),
 		},
 		CommandNotFound: func(_ context.Context, _ *cli.Command, name string) {
 			// revive:disable-next-line:unhandled-error
-			fmt.Fprintf(os.Stderr, "todo-daemon: invalid command: '%s'\n", name)
+			fmt.Fprintf(os.Stderr, "todo-daemon: %s: '%s'\n", i18n.Translate("invalid command"), name)
 		},
 		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "profile",
+				Usage:   "the profile, which namespaces the lock file, socket, data, and configuration",
+				Value:   conf.Profile,
+				Sources: cli.EnvVars(config.EnvProfile),
+			},
 			&cli.StringFlag{
 				Name:      "sock",
-				Usage:     "path to the socket file",
+				Usage:     "address of the socket or named pipe",
 				Value:     conf.SockFile,
+				Sources:   cli.EnvVars(config.EnvSockFile),
 				TakesFile: true,
 			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "maximum time to wait for each response of the server (0 means no timeout)",
+				Value: 5 * time.Second,
+			},
+			&cli.BoolFlag{
+				Name:    "quiet",
+				Aliases: []string{"q"},
+				Usage:   "don't print the results of commands that modify the to-do list, e.g. for scripts",
+			},
+			&cli.StringFlag{
+				Name:    "log-level",
+				Usage:   "minimum level of log messages (debug, info, warn, or error)",
+				Value:   conf.LogLevel,
+				Sources: cli.EnvVars(config.EnvLogLevel),
+			},
+			&cli.StringFlag{
+				Name:  "color",
+				Usage: "when to color the output: auto (if it is a terminal and NO_COLOR is unset), always, or never",
+				Value: string(clifmt.ColorAuto),
+			},
+			&cli.StringFlag{
+				Name:  "lang",
+				Usage: "the language of the CLI output (default: from LC_ALL, LC_MESSAGES, or LANG)",
+			},
+			&cli.BoolFlag{
+				Name:  "compress",
+				Usage: "compress the messages to and from the server with gzip, e.g. for a remote server",
+				Value: conf.GRPCCompression,
+			},
+			&cli.StringFlag{
+				Name:    "time-zone",
+				Usage:   "the IANA time zone to interpret and print times in, e.g. Europe/Berlin (default: local)",
+				Value:   conf.TimeZone,
+				Sources: cli.EnvVars(config.EnvTimeZone),
+			},
+		},
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			var level slog.Level
+			if err := level.UnmarshalText([]byte(cmd.String("log-level"))); err != nil {
+				return ctx, exitcode.NewUsageError("invalid log level: %w", err)
+			}
+			logging.Init(os.Stderr, level)
+			mode, err := clifmt.ParseColorMode(cmd.String("color"))
+			if err != nil {
+				return ctx, exitcode.NewUsageError("%w", err)
+			}
+			if lang := cmd.String("lang"); lang != "" {
+				// The language has already been selected by main, since the
+				// help text is printed while parsing the arguments.
+				if _, err := i18n.ParseLanguage(lang); err != nil {
+					return ctx, exitcode.NewUsageError("%w", err)
+				}
+			}
+			// The time zone is passed to the commands rather than made the
+			// local one, so that it doesn't affect how the server started by
+			// 'run' logs and schedules.
+			if zone := cmd.String("time-zone"); zone != "" {
+				conf.TimeZone = zone
+			}
+			loc, err := conf.Location()
+			if err != nil {
+				return ctx, exitcode.NewUsageError("%w", err)
+			}
+			root := cmd.Root()
+			term := clifmt.NewTerminal(root.Writer, mode)
+			term.Location = loc
+			root.Writer = term
+			// The CLI shares the configuration file with the server, so it
+			// receives the messages the server may send, and vice versa.
+			client.SetDefaults(
+				client.WithCompression(cmd.Bool("compress")),
+				client.WithMaxMessageSize(conf.MaxSendMsgSize, conf.MaxRecvMsgSize),
+			)
+			return ctx, nil
 		},
+	}))
+}
+
+// withUsageErrors makes the specified command and its subcommands return a
+// [exitcode.UsageError] for invalid flags, arguments, and commands, so that
+// the CLI exits with [exitcode.Usage] in these cases.
+func withUsageErrors(cmd *cli.Command) *cli.Command {
+	cmd.OnUsageError = func(_ context.Context, _ *cli.Command, err error, _ bool) error {
+		return exitcode.NewUsageError("%w", err)
+	}
+	if len(cmd.Commands) > 0 && cmd.Action == nil {
+		cmd.Action = func(_ context.Context, cmd *cli.Command) error {
+			if name := cmd.Args().First(); name != "" {
+				return exitcode.NewUsageError("invalid command: '%s'", name)
+			}
+			return cli.ShowSubcommandHelp(cmd)
+		}
+	}
+	for _, sub := range cmd.Commands {
+		withUsageErrors(sub)
+	}
+	return cmd
+}
+
+// localize translates the usage texts of the specified command, its flags,
+// and its subcommands, as well as the section headers of the help templates,
+// into the language selected with [i18n.SetLanguage].
+func localize(cmd *cli.Command) *cli.Command {
+	headers := []string{
+		"NAME:", "USAGE:", "VERSION:", "DESCRIPTION:", "CATEGORY:", "COMMANDS:", "GLOBAL OPTIONS:", "OPTIONS:",
+		"COPYRIGHT:", "[global options]", "[command [command options]]", "[arguments...]",
+	}
+	var oldnew []string
+	for _, h := range headers {
+		oldnew = append(oldnew, h, i18n.Translate(h))
+	}
+	r := strings.NewReplacer(oldnew...)
+	cli.RootCommandHelpTemplate = r.Replace(cli.RootCommandHelpTemplate)
+	cli.CommandHelpTemplate = r.Replace(cli.CommandHelpTemplate)
+	cli.SubcommandHelpTemplate = r.Replace(cli.SubcommandHelpTemplate)
+	localizeFlag(cli.HelpFlag)
+	localizeFlag(cli.VersionFlag)
+	localizeCommand(cmd)
+
+	// The 'help' command is only added while the arguments are parsed, so
+	// it's translated right before the help text is printed.
+	printHelp := cli.HelpPrinter
+	cli.HelpPrinter = func(w io.Writer, templ string, data any) {
+		if cmd, ok := data.(*cli.Command); ok {
+			if help := cmd.Command("help"); help != nil {
+				localizeCommand(help)
+			}
+		}
+		printHelp(w, templ, data)
+	}
+	stringifyFlag := cli.FlagStringer
+	defaultText := " (" + i18n.Translate("default") + ": "
+	cli.FlagStringer = func(f cli.Flag) string {
+		return strings.ReplaceAll(stringifyFlag(f), " (default: ", defaultText)
+	}
+	return cmd
+}
+
+// localizeCommand translates the usage texts of the specified command, its
+// flags, and its subcommands.
+func localizeCommand(cmd *cli.Command) {
+	cmd.Usage = i18n.Translate(cmd.Usage)
+	for _, f := range cmd.Flags {
+		localizeFlag(f)
+	}
+	for _, sub := range cmd.Commands {
+		localizeCommand(sub)
+	}
+}
+
+// localizeFlag translates the usage text of the specified flag. The flag types
+// of package cli have no common method for setting it, but all of them are
+// pointers to structs with a Usage field.
+func localizeFlag(f cli.Flag) {
+	v := reflect.ValueOf(f)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	usage := v.Elem().FieldByName("Usage")
+	if usage.IsValid() && usage.CanSet() && usage.Kind() == reflect.String {
+		usage.SetString(i18n.Translate(usage.String()))
+	}
+}
+
+// Profile returns the profile specified by the --profile flag in the specified
+// command-line arguments, by the environment variable [config.EnvProfile], or
+// [config.DefaultProfile], in this order of precedence. The configuration, and
+// thus the defaults of the other flags, depend on the profile, so the profile
+// is needed before the arguments are parsed.
+func Profile(args []string) string {
+	if profile, ok := flagValue(args, "profile"); ok {
+		return profile
+	}
+	if profile, ok := os.LookupEnv(config.EnvProfile); ok && profile != "" {
+		return profile
+	}
+	return config.DefaultProfile
+}
+
+// Language returns the language specified by the --lang flag in the specified
+// command-line arguments or by the environment, see [i18n.EnvLanguage]. An
+// unsupported language selects English; the root command rejects it once the
+// arguments are parsed. Like the profile, the language is needed before the
+// arguments are parsed, since the help text is printed while parsing them.
+func Language(args []string) string {
+	if name, ok := flagValue(args, "lang"); ok && name != "" {
+		lang, err := i18n.ParseLanguage(name)
+		if err != nil {
+			return i18n.English
+		}
+		return lang
+	}
+	return i18n.EnvLanguage()
+}
+
+// flagValue returns the value of the flag with the specified name in the
+// specified command-line arguments, which haven't been parsed yet.
+func flagValue(args []string, flag string) (string, bool) {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, value, ok := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name != flag {
+			continue
+		}
+		if ok {
+			return value, true
+		}
+		if i+1 < len(args) {
+			return args[i+1], true
+		}
 	}
+	return "", false
 }
@@ -0,0 +1,126 @@
+// Package clitest provides helpers for testing the commands of the To-do Daemon
+// CLI end-to-end without a running To-do Daemon server.
+//
+// A test starts an in-process [Server] and passes its [Server.NewClient] or
+// [Server.NewTaskService] as the client factory of the command's executor,
+// along with a buffer as the executor's output writer:
+//
+//	srv := clitest.NewServer(t, "Buy milk")
+//	var out bytes.Buffer
+//	e := &done.Executor{NewService: srv.NewTaskService, Stdout: &out, TaskID: "1"}
+//	err := e.Execute(t.Context())
+package clitest
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+	"github.com/mwopitz/todo-daemon/internal/transport"
+	"github.com/mwopitz/todo-daemon/internal/version"
+)
+
+// Address is the address reported by the [Server]. Clients created by
+// [Server.NewClient] never actually connect to it.
+const Address = "unix:///clitest/todo-daemon.sock"
+
+// bufferSize is the size of the in-memory pipe between the server and its
+// clients.
+const bufferSize = 1 << 20
+
+// Server is an in-process To-do Daemon gRPC server that keeps its tasks in
+// memory. Its clients are connected to it through an in-memory pipe rather
+// than a socket.
+type Server struct {
+	// DB is the repository holding the server's tasks, e.g. for checking the
+	// effect of a command.
+	DB *todo.InMemoryTaskDB
+	// Filters is the registry holding the server's named filters, which are
+	// kept in memory.
+	Filters  *todo.FilterRegistry
+	listener *bufconn.Listener
+}
+
+// NewServer starts a [Server] holding a task for each of the specified
+// summaries, with the IDs "1", "2", and so on. The server is stopped when the
+// test ends.
+func NewServer(t testing.TB, summaries ...string) *Server {
+	t.Helper()
+	db := todo.NewInMemoryTaskDB()
+	for _, summary := range summaries {
+		if _, err := db.Create(context.Background(), &todo.TaskCreate{Summary: summary}); err != nil {
+			t.Fatalf("cannot create task: %v", err)
+		}
+	}
+	startedAt := time.Now()
+	status := func(ctx context.Context) (*todo.ServerStatus, error) {
+		tasks, err := db.List(ctx, &todo.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &todo.ServerStatus{
+			PID:              os.Getpid(),
+			Version:          version.Semantic(),
+			MinClientVersion: version.MinClient.String(),
+			Uptime:           time.Since(startedAt),
+			StorageBackend:   "memory",
+			TaskCount:        len(tasks),
+			SocketAddress:    Address,
+		}, nil
+	}
+	filters, err := todo.NewFilterRegistry("")
+	if err != nil {
+		t.Fatalf("cannot create filter registry: %v", err)
+	}
+	events := todo.NewEventBus()
+	ctrl := todo.NewController(
+		todo.ServerStatusProviderFunc(status),
+		nil,
+		todo.NewPublishingRepository(db, events),
+		events,
+		todo.WithFilters(filters),
+	)
+	grpcServer := grpc.NewServer()
+	todopb.RegisterTodoServiceServer(grpcServer, ctrl)
+
+	s := &Server{
+		DB:       db,
+		Filters:  filters,
+		listener: bufconn.Listen(bufferSize),
+	}
+	go func() {
+		// Serve only fails if the listener is closed, i.e. when the server
+		// stops.
+		_ = grpcServer.Serve(s.listener)
+	}()
+	t.Cleanup(grpcServer.Stop)
+	return s
+}
+
+// NewClient creates a client connected to the server. It is a
+// [client.Factory], which ignores the specified address.
+func (s *Server) NewClient(_ string, opts ...client.Option) (*client.Client, error) {
+	opts = append(opts, client.WithDialer(func(ctx context.Context, _ transport.Address) (net.Conn, error) {
+		return s.listener.DialContext(ctx)
+	}))
+	return client.New(Address, opts...)
+}
+
+// NewTaskService creates a client connected to the server like
+// [Server.NewClient]. It is a [client.TaskServiceFactory] for the executors of
+// the 'tasks' commands.
+func (s *Server) NewTaskService(address string, opts ...client.Option) (client.TaskService, error) {
+	c, err := s.NewClient(address, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
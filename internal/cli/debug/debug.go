@@ -0,0 +1,35 @@
+// Package debug implements the hidden 'debug' command of the To-do Daemon CLI.
+//
+// The 'debug' command provides subcommands that help with developing and
+// troubleshooting the To-do Daemon.
+package debug
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/debug/jobs"
+	"github.com/mwopitz/todo-daemon/internal/cli/debug/rpc"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// NewCommand creates a new 'debug' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:   "debug",
+		Usage:  "Debug the To-do Daemon",
+		Hidden: true,
+		Commands: []*cli.Command{
+			jobs.NewCommand(conf),
+			rpc.NewCommand(conf),
+		},
+		CommandNotFound: func(_ context.Context, _ *cli.Command, name string) {
+			// revive:disable-next-line:unhandled-error
+			fmt.Fprintf(os.Stderr, "todo-daemon: %s: '%s'\n", i18n.Translate("invalid command"), name)
+		},
+	}
+}
@@ -0,0 +1,77 @@
+// Package jobs implements the 'jobs' subcommand of the To-do Daemon CLI's
+// 'debug' command.
+//
+// The 'jobs' subcommand lists the periodic background jobs of the To-do Daemon
+// server, e.g. the scheduled backups, along with their last and next runs.
+package jobs
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+)
+
+// Executor is used for executing the 'jobs' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewClient creates the client for connecting to the To-do Daemon
+	// server.
+	NewClient client.Factory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+}
+
+// NewExecutor creates an executor for the specified 'jobs' command.
+func NewExecutor(cmd *cli.Command) (*Executor, error) {
+	return &Executor{
+		SockFile:  cmd.String("sock"),
+		Timeout:   cmd.Duration("timeout"),
+		NewClient: client.New,
+		Stdout:    cmd.Root().Writer,
+	}, nil
+}
+
+// Execute executes the 'jobs' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewClient(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	jobs, err := c.ListJobs(ctx)
+	if err != nil {
+		return err
+	}
+	return clifmt.PrintJobs(e.Stdout, jobs)
+}
+
+// NewCommand creates a new 'jobs' command with the specified configuration.
+func NewCommand(_ *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "jobs",
+		Usage: "List the background jobs of the server with their last and next runs",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
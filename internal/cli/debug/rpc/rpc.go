@@ -0,0 +1,95 @@
+// Package rpc implements the 'rpc' subcommand of the To-do Daemon CLI's 'debug'
+// command.
+//
+// The 'rpc' subcommand invokes an arbitrary unary gRPC method of the To-do
+// Daemon server with a JSON-encoded request message and prints the
+// JSON-encoded response message to standard output.
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+)
+
+// Executor is used for executing the 'rpc' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewClient creates the client for connecting to the To-do Daemon
+	// server.
+	NewClient client.Factory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Method is the fully qualified name of the gRPC method to invoke.
+	Method string
+	// Request is the JSON-encoded request message.
+	Request string
+}
+
+// NewExecutor creates an executor for the specified 'rpc' command.
+func NewExecutor(cmd *cli.Command) (*Executor, error) {
+	method := cmd.StringArg("method")
+	if method == "" {
+		return nil, exitcode.NewUsageError("no method specified")
+	}
+	return &Executor{
+		SockFile:  cmd.String("sock"),
+		Timeout:   cmd.Duration("timeout"),
+		NewClient: client.New,
+		Stdout:    cmd.Root().Writer,
+		Method:    method,
+		Request:   cmd.StringArg("json"),
+	}, nil
+}
+
+// Execute executes the 'rpc' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewClient(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	resp, err := c.InvokeJSON(ctx, e.Method, []byte(e.Request))
+	if err != nil {
+		return fmt.Errorf("cannot invoke %s: %w", e.Method, err)
+	}
+	_, err = fmt.Fprintf(e.Stdout, "%s\n", resp)
+	return err
+}
+
+// NewCommand creates a new 'rpc' command with the specified configuration.
+func NewCommand(_ *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:      "rpc",
+		Usage:     "Invoke a gRPC method with a JSON request",
+		UsageText: "todo-daemon debug rpc todo.v1.TodoService/ListTasks '{}'",
+		Arguments: []cli.Argument{
+			&cli.StringArg{Name: "method"},
+			&cli.StringArg{Name: "json"},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
@@ -0,0 +1,443 @@
+// Package doctor implements the 'doctor' command of the To-do Daemon CLI.
+//
+// The 'doctor' command checks the local setup of the To-do Daemon for common
+// problems, e.g. a stale socket file or an invalid configuration file, and
+// prints how to fix them. It fails if it finds any problem. With --repair, it
+// repairs a corrupt event log of a stopped server.
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/cors"
+	"github.com/mwopitz/todo-daemon/internal/duedate"
+	"github.com/mwopitz/todo-daemon/internal/hook"
+	"github.com/mwopitz/todo-daemon/internal/lockfile"
+	"github.com/mwopitz/todo-daemon/internal/server"
+	"github.com/mwopitz/todo-daemon/internal/storage"
+	"github.com/mwopitz/todo-daemon/internal/transport"
+	"github.com/mwopitz/todo-daemon/internal/version"
+	"github.com/mwopitz/todo-daemon/internal/webhook"
+)
+
+// dialTimeout is the maximum amount of time for checking whether something is
+// listening on the socket.
+const dialTimeout = time.Second
+
+// result is the outcome of a single check.
+type result struct {
+	// ok specifies whether the check passed.
+	ok bool
+	// message describes what was found.
+	message string
+	// fix describes how to fix the problem, if the check failed.
+	fix string
+}
+
+func pass(format string, args ...any) result {
+	return result{ok: true, message: fmt.Sprintf(format, args...)}
+}
+
+func fail(fix, format string, args ...any) result {
+	return result{message: fmt.Sprintf(format, args...), fix: fix}
+}
+
+// Executor is used for executing the 'doctor' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// LockFile is the path to the lock file of the To-do Daemon server.
+	LockFile string
+	// Database is the data source name of the storage backend of the To-do
+	// Daemon server, see package storage.
+	Database string
+	// Repair specifies whether to repair a corrupt event log instead of just
+	// reporting it.
+	Repair bool
+	// ConfigFile is the path to the configuration file to check.
+	ConfigFile string
+	// Profile is the profile whose section of the configuration file to
+	// check.
+	Profile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewClient creates the client for connecting to the To-do Daemon
+	// server.
+	NewClient client.Factory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+}
+
+// NewExecutor creates an executor for the specified 'doctor' command.
+func NewExecutor(cmd *cli.Command) (*Executor, error) {
+	return &Executor{
+		SockFile:   cmd.String("sock"),
+		LockFile:   cmd.String("lock"),
+		Database:   cmd.String("db"),
+		Repair:     cmd.Bool("repair"),
+		ConfigFile: config.DefaultFile(),
+		Profile:    cmd.String("profile"),
+		Timeout:    cmd.Duration("timeout"),
+		NewClient:  client.New,
+		Stdout:     cmd.Root().Writer,
+	}, nil
+}
+
+// Execute executes the 'doctor' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	problems, err := e.run(ctx, e.Stdout)
+	if err != nil {
+		return err
+	}
+	if problems > 0 {
+		return fmt.Errorf("found %d problem(s)", problems)
+	}
+	return nil
+}
+
+// run runs all checks, prints their results to the specified writer, and
+// returns the number of failed checks.
+func (e *Executor) run(ctx context.Context, w io.Writer) (int, error) {
+	addr, err := transport.ParseAddress(e.SockFile)
+	if err != nil {
+		return 0, err
+	}
+	results := e.checkRunDirs(addr)
+	lock, locked := e.checkLock()
+	results = append(results, lock)
+	if addr.Scheme == transport.SchemeUnix {
+		results = append(results, checkSocket(ctx, addr))
+	}
+	results = append(results, e.checkServer(ctx, locked)...)
+	if !locked {
+		results = append(results, e.checkStorage()...)
+	}
+	results = append(results, e.checkConfig())
+
+	problems := 0
+	for _, r := range results {
+		mark := "✓"
+		if !r.ok {
+			mark = "✗"
+			problems++
+		}
+		if _, err := fmt.Fprintf(w, "%s %s\n", mark, r.message); err != nil {
+			return 0, err
+		}
+		if r.fix != "" {
+			if _, err := fmt.Fprintf(w, "  → %s\n", r.fix); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return problems, nil
+}
+
+// checkRunDirs checks that the directories of the lock file and the socket
+// exist and are writable.
+func (e *Executor) checkRunDirs(addr transport.Address) []result {
+	dirs := []string{filepath.Dir(e.LockFile)}
+	if addr.Scheme == transport.SchemeUnix && !slices.Contains(dirs, filepath.Dir(addr.Path)) {
+		dirs = append(dirs, filepath.Dir(addr.Path))
+	}
+	results := make([]result, len(dirs))
+	for i, dir := range dirs {
+		results[i] = checkRunDir(dir)
+	}
+	return results
+}
+
+func checkRunDir(dir string) result {
+	info, err := os.Stat(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return fail(fmt.Sprintf("create it with 'mkdir -p -m 700 %s'", dir),
+			"run directory %s does not exist", dir)
+	}
+	if err != nil {
+		return fail("check the permissions of its parent directories", "cannot access run directory %s: %v", dir, err)
+	}
+	if !info.IsDir() {
+		return fail("remove the file or choose another path", "run directory %s is not a directory", dir)
+	}
+	f, err := os.CreateTemp(dir, ".todo-daemon-doctor-*")
+	if err != nil {
+		return fail(fmt.Sprintf("make it writable with 'chmod u+w %s'", dir), "run directory %s is not writable", dir)
+	}
+	if err := f.Close(); err != nil {
+		slog.Warn("cannot close temporary file", "path", f.Name(), "cause", err)
+	}
+	if err := os.Remove(f.Name()); err != nil {
+		slog.Warn("cannot remove temporary file", "path", f.Name(), "cause", err)
+	}
+	return pass("run directory %s is writable", dir)
+}
+
+// checkLock checks whether a server holds the lock file, which it does for as
+// long as it is running, and whether the server recorded in the lock file is
+// actually running. A lock file that no process holds is harmless, since the
+// next server just reuses it.
+func (e *Executor) checkLock() (result, bool) {
+	if _, err := os.Stat(e.LockFile); errors.Is(err, os.ErrNotExist) {
+		return pass("no lock file at %s, so no server is running", e.LockFile), false
+	}
+	pid, err := lockfile.ReadPID(e.LockFile)
+	if err != nil {
+		slog.Warn("cannot read PID from lock file", "cause", err)
+	}
+	// Probe the lock without recording a PID, which would hide the PID of a
+	// server that did not shut down cleanly.
+	lock := flock.New(e.LockFile)
+	locked, err := lock.TryLock()
+	if err != nil {
+		return fail("check the permissions of the lock file", "cannot check lock file %s: %v", e.LockFile, err), false
+	}
+	if !locked {
+		if pid != 0 && !lockfile.IsRunning(pid) {
+			return fail(
+				"find the process holding it, e.g. with 'fuser "+e.LockFile+"', and stop it",
+				"lock file %s is held, but the server (PID %d) recorded in it is not running", e.LockFile, pid,
+			), true
+		}
+		return pass("lock file %s is held by a running server (PID %d)", e.LockFile, pid), true
+	}
+	if err := lock.Unlock(); err != nil {
+		slog.Warn("cannot release file lock", "cause", err)
+	}
+	if pid != 0 {
+		return pass("lock file %s is left over from a server (PID %d) that did not shut down cleanly, which is harmless",
+			e.LockFile, pid), false
+	}
+	return pass("lock file %s is not held by any process", e.LockFile), false
+}
+
+// checkSocket checks that the Unix socket, if it exists, is not orphaned, i.e.
+// left behind by a server that was killed.
+func checkSocket(ctx context.Context, addr transport.Address) result {
+	if _, err := os.Stat(addr.Path); errors.Is(err, os.ErrNotExist) {
+		return pass("no socket file at %s", addr.Path)
+	}
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+	conn, err := transport.Dial(ctx, addr)
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return fail(fmt.Sprintf("remove it with 'rm %s', or just start the server, which replaces it", addr.Path),
+			"socket file %s is orphaned; nothing is listening on it", addr.Path)
+	}
+	if err != nil {
+		return fail("check the permissions of the socket file", "cannot connect to socket file %s: %v", addr.Path, err)
+	}
+	if err := conn.Close(); err != nil {
+		slog.Warn("cannot close probe connection", "cause", err)
+	}
+	return pass("socket file %s is accepting connections", addr.Path)
+}
+
+// checkServer checks that the server is reachable, that its version matches
+// the version of the CLI, and that its tasks are consistent. locked specifies
+// whether a server holds the lock file.
+func (e *Executor) checkServer(ctx context.Context, locked bool) []result {
+	c, err := e.NewClient(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return []result{fail("check the --sock flag", "cannot connect to server: %v", err)}
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	status, err := c.ServerStatus(ctx)
+	switch {
+	case errors.Is(err, client.ErrDaemonNotRunning) && locked:
+		return []result{fail(
+			"check that the --sock flag or "+config.EnvSockFile+" matches the server's socket, or restart the server",
+			"a server holds the lock file, but it isn't listening on %s", e.SockFile,
+		)}
+	case errors.Is(err, client.ErrDaemonNotRunning):
+		return []result{fail("start it with 'todo-daemon run'", "server is not running")}
+	case err != nil:
+		return []result{fail("check the server's log messages, or restart the server", "server is not responding: %v", err)}
+	}
+	results := []result{pass("server (PID %d) is reachable at %s", status.GetPid(), status.GetSocketAddress())}
+
+	if v := status.GetVersion(); v != version.Semantic() {
+		results = append(results, fail(
+			"restart the server with the same executable as the CLI",
+			"server version %s doesn't match CLI version %s", v, version.Semantic(),
+		))
+	} else {
+		results = append(results, pass("server and CLI have the same version %s", v))
+	}
+	if m, err := version.Parse(status.GetMinClientVersion()); err == nil && version.Current().Compare(m) < 0 {
+		results = append(results, fail(
+			"update the CLI",
+			"CLI version %s is older than the minimum version %s that the server supports", version.Semantic(), m,
+		))
+	}
+
+	tasks, err := c.ListTasks(ctx)
+	if err != nil {
+		return append(results, fail("check the server's log messages", "cannot retrieve tasks: %v", err))
+	}
+	ids := make(map[string]bool, len(tasks))
+	var invalid []string
+	for _, t := range tasks {
+		switch {
+		case t.GetId() == "" || ids[t.GetId()]:
+			invalid = append(invalid, fmt.Sprintf("'%s' (missing or duplicate ID)", t.GetId()))
+		case t.GetVersion() == 0:
+			invalid = append(invalid, fmt.Sprintf("'%s' (no version)", t.GetId()))
+		}
+		ids[t.GetId()] = true
+	}
+	if len(invalid) > 0 {
+		return append(results, fail(
+			"restore a backup with 'todo-daemon backup restore <path>'",
+			"storage (%s) holds invalid tasks: %v", status.GetStorageBackend(), invalid,
+		))
+	}
+	return append(results, pass("storage (%s) holds %d valid task(s)", status.GetStorageBackend(), len(tasks)))
+}
+
+// checkStorage checks that the event log of a stopped server, if it is the
+// configured storage backend, can be replayed, and repairs it if requested.
+// The log of a running server has been replayed already, and its tasks are
+// checked by [Executor.checkServer].
+func (e *Executor) checkStorage() []result {
+	if name, err := storage.DriverName(e.Database); err != nil || name != storage.EventLog {
+		return nil
+	}
+	path, err := storage.EventLogPath(e.Database)
+	if err != nil {
+		return []result{fail("check the --db flag", "%v", err)}
+	}
+	check, err := storage.CheckEventLog(e.Database)
+	if errors.Is(err, os.ErrNotExist) {
+		return []result{pass("no event log at %s yet", path)}
+	}
+	if err != nil {
+		return []result{fail("check the --db flag and the permissions of the event log", "%v", err)}
+	}
+	if check.Valid() {
+		if check.Partial {
+			return []result{pass("event log %s holds %d valid record(s) and a partial record, "+
+				"which the server removes when it starts", path, check.Records)}
+		}
+		return []result{pass("event log %s holds %d valid record(s)", path, check.Records)}
+	}
+	if !e.Repair {
+		return []result{fail(
+			"repair it with 'todo-daemon doctor --repair', which drops the invalid record and all later ones",
+			"event log %s is corrupt at line %d: %v", path, check.Line, check.Err,
+		)}
+	}
+
+	// The server must not start while the log is repaired.
+	lock := flock.New(e.LockFile)
+	locked, err := lock.TryLock()
+	if err != nil || !locked {
+		return []result{fail("stop the server and try again", "cannot repair event log %s while the server is starting",
+			path)}
+	}
+	defer func() {
+		if err := lock.Unlock(); err != nil {
+			slog.Warn("cannot release file lock", "cause", err)
+		}
+	}()
+	check, backup, err := storage.RepairEventLog(e.Database)
+	if err != nil {
+		return []result{fail("restore a backup with 'todo-daemon backup restore <path>' once the server is running",
+			"cannot repair event log %s: %v", path, err)}
+	}
+	return []result{pass("repaired event log %s, which now holds %d record(s); the original is at %s",
+		path, check.Records, backup)}
+}
+
+// checkConfig checks that the configuration file, if it exists, is valid.
+func (e *Executor) checkConfig() result {
+	if _, err := os.Stat(e.ConfigFile); errors.Is(err, os.ErrNotExist) {
+		return pass("no configuration file at %s, using the defaults", e.ConfigFile)
+	}
+	fix := "edit " + e.ConfigFile
+	conf, err := config.Load(e.ConfigFile, e.Profile)
+	if err != nil {
+		return fail(fix, "%v", err)
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(conf.LogLevel)); err != nil {
+		return fail(fix, "configuration file %s has an invalid log level: '%s'", e.ConfigFile, conf.LogLevel)
+	}
+	if _, err := storage.DriverName(conf.Database); err != nil {
+		return fail(fix, "configuration file %s has an unsupported database: %v", e.ConfigFile, err)
+	}
+	if _, err := duedate.NewParser(conf.Locale, conf.TimeZone); err != nil {
+		return fail(fix, "configuration file %s has an %v", e.ConfigFile, err)
+	}
+	if _, err := server.ParseHTTPListenAddress(conf.HTTPListen); err != nil {
+		return fail(fix, "configuration file %s has an %v", e.ConfigFile, err)
+	}
+	corsPolicy := cors.Policy{AllowedOrigins: conf.CORS.AllowedOrigins}
+	if err := corsPolicy.Validate(); err != nil {
+		return fail(fix, "configuration file %s has an %v", e.ConfigFile, err)
+	}
+	for _, name := range conf.Hooks.Allow {
+		if !hook.IsValidName(name) {
+			return fail(fix, "configuration file %s has an invalid hook name: '%s'", e.ConfigFile, name)
+		}
+	}
+	for _, spec := range webhook.NewSpecs(conf.Webhooks) {
+		if err := spec.Validate(); err != nil {
+			return fail(fix, "configuration file %s has an invalid webhook: %v", e.ConfigFile, err)
+		}
+	}
+	return pass("configuration file %s is valid", e.ConfigFile)
+}
+
+// NewCommand creates a new 'doctor' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "doctor",
+		Usage: "Check the setup of the To-do Daemon for problems",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:      "lock",
+				Usage:     "path to the lock file",
+				Value:     conf.LockFile,
+				Sources:   cli.EnvVars(config.EnvLockFile),
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:    "db",
+				Usage:   "the data source name of the database for storing the tasks, e.g. memory or eventlog:<path>",
+				Value:   conf.Database,
+				Sources: cli.EnvVars(config.EnvDatabase),
+			},
+			&cli.BoolFlag{
+				Name:  "repair",
+				Usage: "repair a corrupt event log of a stopped server, keeping the original next to it",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
@@ -0,0 +1,152 @@
+// Package add implements the 'add' subcommand of the To-do Daemon CLI's
+// 'filters' command.
+//
+// The 'add' subcommand saves a named filter on the server, which selects the
+// tasks printed by 'tasks list --filter'.
+package add
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+var dueWindows = map[string]todopb.Filter_Due{
+	"today":   todopb.Filter_DUE_TODAY,
+	"week":    todopb.Filter_DUE_WEEK,
+	"overdue": todopb.Filter_DUE_OVERDUE,
+}
+
+var statuses = map[string]todopb.ListTasksRequest_Completion{
+	"open":      todopb.ListTasksRequest_COMPLETION_OPEN,
+	"completed": todopb.ListTasksRequest_COMPLETION_COMPLETED,
+}
+
+// Executor is used for executing the 'add' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewClient creates the client for connecting to the To-do Daemon
+	// server.
+	NewClient client.Factory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+	// Filter is the filter to be saved.
+	Filter *todopb.Filter
+}
+
+// NewExecutor creates an executor for the specified 'add' command.
+func NewExecutor(cmd *cli.Command) (*Executor, error) {
+	name := cmd.StringArg("name")
+	if name == "" {
+		return nil, exitcode.NewUsageError("no filter name specified")
+	}
+	filter := &todopb.Filter{
+		Name:    name,
+		Tags:    cmd.StringSlice("tag"),
+		Project: cmd.String("project"),
+		Starred: cmd.Bool("starred"),
+	}
+	if due := cmd.String("due"); due != "" {
+		window, ok := dueWindows[due]
+		if !ok {
+			return nil, exitcode.NewUsageError("invalid due filter: %s", due)
+		}
+		filter.Due = window
+	}
+	if status := cmd.String("status"); status != "" {
+		completion, ok := statuses[status]
+		if !ok {
+			return nil, exitcode.NewUsageError("invalid status filter: %s", status)
+		}
+		filter.Completion = completion
+	}
+	return &Executor{
+		SockFile:  cmd.String("sock"),
+		Timeout:   cmd.Duration("timeout"),
+		NewClient: client.New,
+		Stdout:    cmd.Root().Writer,
+		Quiet:     cmd.Bool("quiet"),
+		Filter:    filter,
+	}, nil
+}
+
+// Execute executes the 'add' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewClient(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	filter, err := c.CreateFilter(ctx, e.Filter)
+	if err != nil {
+		return err
+	}
+	if e.Quiet {
+		return nil
+	}
+	// revive:disable-next-line:unhandled-error
+	fmt.Fprintln(e.Stdout, i18n.Sprintf("Saved filter '%s'; list its tasks with 'tasks list --filter %s'",
+		filter.GetName(), filter.GetName()))
+	return nil
+}
+
+// NewCommand creates a new 'add' command with the specified configuration.
+func NewCommand(_ *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "add",
+		Usage: "Save a named filter for listing tasks",
+		Arguments: []cli.Argument{
+			&cli.StringArg{Name: "name"},
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "due",
+				Usage: "select the tasks that are due (today, week, or overdue)",
+			},
+			&cli.StringFlag{
+				Name:  "status",
+				Usage: "select the tasks with this status (open or completed)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "tag",
+				Usage: "select the tasks with this tag (can be repeated)",
+			},
+			&cli.StringFlag{
+				Name:  "project",
+				Usage: "select the tasks of this project",
+			},
+			&cli.BoolFlag{
+				Name:  "starred",
+				Usage: "select the starred tasks",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
@@ -0,0 +1,75 @@
+package add
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/cli/clitest"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+func TestExecute(t *testing.T) {
+	srv := clitest.NewServer(t)
+	var out bytes.Buffer
+	e := &Executor{
+		SockFile:  clitest.Address,
+		NewClient: srv.NewClient,
+		Stdout:    &out,
+		Filter: &todopb.Filter{
+			Name: "urgent-work",
+			Tags: []string{"work"},
+			Due:  todopb.Filter_DUE_WEEK,
+		},
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	want := "Saved filter 'urgent-work'; list its tasks with 'tasks list --filter urgent-work'\n"
+	if out.String() != want {
+		t.Errorf("want output: %q; got: %q", want, out.String())
+	}
+	f, err := srv.Filters.Get("urgent-work")
+	if err != nil {
+		t.Fatalf("want filter to be saved; got: %v", err)
+	}
+	if !slices.Equal(f.Tags, []string{"work"}) || f.Due != todo.DueWeek {
+		t.Errorf("want filter with tag work due this week; got: %+v", f)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *todopb.Filter
+		want   codes.Code
+	}{
+		{"Exists", &todopb.Filter{Name: "urgent-work"}, codes.AlreadyExists},
+		{"InvalidName", &todopb.Filter{Name: "urgent work"}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := clitest.NewServer(t)
+			if err := srv.Filters.Add(&todo.Filter{Name: "urgent-work"}); err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			e := &Executor{
+				SockFile:  clitest.Address,
+				NewClient: srv.NewClient,
+				Stdout:    &out,
+				Filter:    tt.filter,
+			}
+			if err := e.Execute(t.Context()); status.Code(err) != tt.want {
+				t.Errorf("want error with code %s; got: %v", tt.want, err)
+			}
+			if out.Len() > 0 {
+				t.Errorf("want no output; got: %q", out.String())
+			}
+		})
+	}
+}
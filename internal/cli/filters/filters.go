@@ -0,0 +1,37 @@
+// Package filters implements the 'filters' command of the To-do Daemon CLI.
+//
+// The 'filters' command provides subcommands for managing the named filters
+// that the server keeps, which select tasks for 'tasks list --filter'.
+package filters
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/filters/add"
+	"github.com/mwopitz/todo-daemon/internal/cli/filters/list"
+	"github.com/mwopitz/todo-daemon/internal/cli/filters/remove"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// NewCommand creates a new 'filters' command with the specified
+// configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "filters",
+		Usage: "Manage the named filters for listing tasks",
+		Commands: []*cli.Command{
+			add.NewCommand(conf),
+			list.NewCommand(conf),
+			remove.NewCommand(conf),
+		},
+		CommandNotFound: func(_ context.Context, _ *cli.Command, name string) {
+			// revive:disable-next-line:unhandled-error
+			fmt.Fprintf(os.Stderr, "todo-daemon: %s: '%s'\n", i18n.Translate("invalid command"), name)
+		},
+	}
+}
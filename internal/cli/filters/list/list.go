@@ -0,0 +1,132 @@
+// Package list implements the 'list' subcommand of the To-do Daemon CLI's
+// 'filters' command.
+//
+// The 'list' subcommand prints the named filters, both those defined in the
+// configuration file and those saved with 'filters add', along with the
+// criteria they select tasks by.
+package list
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// Executor is used for executing the 'list' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewClient creates the client for connecting to the To-do Daemon
+	// server.
+	NewClient client.Factory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+}
+
+// NewExecutor creates an executor for the specified 'list' command.
+func NewExecutor(cmd *cli.Command) (*Executor, error) {
+	return &Executor{
+		SockFile:  cmd.String("sock"),
+		Timeout:   cmd.Duration("timeout"),
+		NewClient: client.New,
+		Stdout:    cmd.Root().Writer,
+	}, nil
+}
+
+// Execute executes the 'list' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewClient(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	filters, err := c.ListFilters(ctx)
+	if err != nil {
+		return err
+	}
+	if len(filters) == 0 {
+		_, err := fmt.Fprintln(e.Stdout, i18n.Translate("No filters"))
+		return err
+	}
+	tw := tabwriter.NewWriter(e.Stdout, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "NAME\tSOURCE\tCRITERIA"); err != nil {
+		return err
+	}
+	for _, f := range filters {
+		source := "saved"
+		if f.GetConfigured() {
+			source = "config"
+		}
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\n", f.GetName(), source, criteria(f)); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// criteria describes the criteria of the specified filter like the flags of
+// 'filters add'.
+func criteria(f *todopb.Filter) string {
+	var parts []string
+	switch f.GetCompletion() {
+	case todopb.ListTasksRequest_COMPLETION_OPEN:
+		parts = append(parts, "--status open")
+	case todopb.ListTasksRequest_COMPLETION_COMPLETED:
+		parts = append(parts, "--status completed")
+	}
+	for _, tag := range f.GetTags() {
+		parts = append(parts, "--tag "+tag)
+	}
+	if project := f.GetProject(); project != "" {
+		parts = append(parts, "--project "+project)
+	}
+	switch f.GetDue() {
+	case todopb.Filter_DUE_TODAY:
+		parts = append(parts, "--due today")
+	case todopb.Filter_DUE_WEEK:
+		parts = append(parts, "--due week")
+	case todopb.Filter_DUE_OVERDUE:
+		parts = append(parts, "--due overdue")
+	}
+	if f.GetStarred() {
+		parts = append(parts, "--starred")
+	}
+	if len(parts) == 0 {
+		return "(all tasks)"
+	}
+	return strings.Join(parts, " ")
+}
+
+// NewCommand creates a new 'list' command with the specified configuration.
+func NewCommand(_ *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "List the named filters",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
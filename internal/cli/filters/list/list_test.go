@@ -0,0 +1,51 @@
+package list
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/clitest"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+func TestExecute(t *testing.T) {
+	srv := clitest.NewServer(t)
+	if err := srv.Filters.SetConfigured([]todo.Filter{{Name: "starred", Starred: true}}); err != nil {
+		t.Fatal(err)
+	}
+	saved := &todo.Filter{Name: "urgent-work", Completion: todo.CompletionOpen, Tags: []string{"work"}, Due: todo.DueWeek}
+	if err := srv.Filters.Add(saved); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	e := &Executor{
+		SockFile:  clitest.Address,
+		NewClient: srv.NewClient,
+		Stdout:    &out,
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	want := "NAME         SOURCE  CRITERIA\n" +
+		"starred      config  --starred\n" +
+		"urgent-work  saved   --status open --tag work --due week\n"
+	if out.String() != want {
+		t.Errorf("want output:\n%s\ngot:\n%s", want, out.String())
+	}
+}
+
+func TestExecuteEmpty(t *testing.T) {
+	srv := clitest.NewServer(t)
+	var out bytes.Buffer
+	e := &Executor{
+		SockFile:  clitest.Address,
+		NewClient: srv.NewClient,
+		Stdout:    &out,
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	if want := "No filters\n"; out.String() != want {
+		t.Errorf("want output: %q; got: %q", want, out.String())
+	}
+}
@@ -0,0 +1,95 @@
+// Package remove implements the 'remove' subcommand of the To-do Daemon CLI's
+// 'filters' command.
+//
+// The 'remove' subcommand deletes a named filter saved on the server. Filters
+// defined in the configuration file cannot be removed.
+package remove
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// Executor is used for executing the 'remove' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewClient creates the client for connecting to the To-do Daemon
+	// server.
+	NewClient client.Factory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+	// Name is the name of the filter to be removed.
+	Name string
+}
+
+// NewExecutor creates an executor for the specified 'remove' command.
+func NewExecutor(cmd *cli.Command) (*Executor, error) {
+	name := cmd.StringArg("name")
+	if name == "" {
+		return nil, exitcode.NewUsageError("no filter name specified")
+	}
+	return &Executor{
+		SockFile:  cmd.String("sock"),
+		Timeout:   cmd.Duration("timeout"),
+		NewClient: client.New,
+		Stdout:    cmd.Root().Writer,
+		Quiet:     cmd.Bool("quiet"),
+		Name:      name,
+	}, nil
+}
+
+// Execute executes the 'remove' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewClient(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	if err := c.DeleteFilter(ctx, e.Name); err != nil {
+		return err
+	}
+	if !e.Quiet {
+		// revive:disable-next-line:unhandled-error
+		fmt.Fprintln(e.Stdout, i18n.Sprintf("Removed filter '%s'", e.Name))
+	}
+	return nil
+}
+
+// NewCommand creates a new 'remove' command with the specified configuration.
+func NewCommand(_ *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "remove",
+		Usage: "Remove a saved filter",
+		Arguments: []cli.Argument{
+			&cli.StringArg{Name: "name"},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
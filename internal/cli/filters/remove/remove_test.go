@@ -0,0 +1,75 @@
+package remove
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/clitest"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// newServer starts a server with a saved filter "urgent-work" and a
+// configured filter "starred".
+func newServer(t *testing.T) *clitest.Server {
+	t.Helper()
+	srv := clitest.NewServer(t)
+	if err := srv.Filters.SetConfigured([]todo.Filter{{Name: "starred", Starred: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Filters.Add(&todo.Filter{Name: "urgent-work", Tags: []string{"work"}}); err != nil {
+		t.Fatal(err)
+	}
+	return srv
+}
+
+func TestExecute(t *testing.T) {
+	srv := newServer(t)
+	var out bytes.Buffer
+	e := &Executor{
+		SockFile:  clitest.Address,
+		NewClient: srv.NewClient,
+		Stdout:    &out,
+		Name:      "urgent-work",
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	if want := "Removed filter 'urgent-work'\n"; out.String() != want {
+		t.Errorf("want output: %q; got: %q", want, out.String())
+	}
+	if _, err := srv.Filters.Get("urgent-work"); !todo.IsFilterNotFoundError(err) {
+		t.Errorf("want filter to be removed; got: %v", err)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		want   codes.Code
+	}{
+		{"NotFound", "someday", codes.NotFound},
+		{"Configured", "starred", codes.FailedPrecondition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newServer(t)
+			var out bytes.Buffer
+			e := &Executor{
+				SockFile:  clitest.Address,
+				NewClient: srv.NewClient,
+				Stdout:    &out,
+				Name:      tt.filter,
+			}
+			if err := e.Execute(t.Context()); status.Code(err) != tt.want {
+				t.Errorf("want error with code %s; got: %v", tt.want, err)
+			}
+			if out.Len() > 0 {
+				t.Errorf("want no output; got: %q", out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Package flush implements the 'flush' command of the To-do Daemon CLI.
+//
+// The 'flush' command adds the tasks queued by 'tasks add --offline' while the
+// server was not running to the to-do list, and reports the tasks that the
+// server rejected. The 'tasks' commands flush the queue, too, before doing
+// anything else.
+package flush
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+	"github.com/mwopitz/todo-daemon/internal/queue"
+)
+
+// Executor is used for executing the 'flush' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewService creates the service that the queued tasks are added to.
+	NewService client.TaskServiceFactory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print only the tasks that the server
+	// rejected.
+	Quiet bool
+	// Queue is the offline queue to be flushed.
+	Queue *queue.Queue
+}
+
+// NewExecutor creates an executor for the specified 'flush' command.
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	return &Executor{
+		SockFile:   cmd.String("sock"),
+		Timeout:    cmd.Duration("timeout"),
+		NewService: client.NewTaskService,
+		Stdout:     cmd.Root().Writer,
+		Quiet:      cmd.Bool("quiet"),
+		Queue:      queue.New(conf.QueueFile()),
+	}, nil
+}
+
+// Execute executes the 'flush' command. It fails if the server is not
+// running or rejected any of the queued tasks.
+func (e *Executor) Execute(ctx context.Context) error {
+	result, err := e.Flush(ctx)
+	if err != nil {
+		return err
+	}
+	if result.Pending > 0 {
+		return fmt.Errorf("%d tasks are still queued: %w", result.Pending, client.ErrDaemonNotRunning)
+	}
+	if n := len(result.Failed); n > 0 {
+		return fmt.Errorf("cannot add %d queued tasks", n)
+	}
+	if len(result.Created) == 0 && !e.Quiet {
+		// revive:disable-next-line:unhandled-error
+		fmt.Fprintln(e.Stdout, i18n.Translate("No queued tasks"))
+	}
+	return nil
+}
+
+// Flush adds the queued tasks to the to-do list, and prints the added tasks
+// and the tasks that the server rejected. If the server is not running, the
+// tasks stay in the queue without an error.
+func (e *Executor) Flush(ctx context.Context) (*queue.Result, error) {
+	if e.Queue.Empty() {
+		return &queue.Result{}, nil
+	}
+	c, err := e.NewService(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	result, err := e.Queue.Flush(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range result.Failed {
+		// revive:disable-next-line:unhandled-error
+		fmt.Fprintln(e.Stdout, i18n.Sprintf("Cannot add the task '%s' queued at %s: %s",
+			f.Operation.Summary(), f.Operation.Time.In(clifmt.Location(e.Stdout)).Format(time.DateTime), i18n.Error(f.Err)))
+	}
+	if len(result.Created) == 0 || e.Quiet {
+		return result, nil
+	}
+	// revive:disable-next-line:unhandled-error
+	fmt.Fprintln(e.Stdout, i18n.Sprintf("Added %d queued tasks:", len(result.Created)))
+	return result, clifmt.PrintTasks(e.Stdout, result.Created)
+}
+
+// NewCommand creates a new 'flush' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "flush",
+		Usage: "Add the tasks queued with 'tasks add --offline' to the to-do list",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd, conf)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
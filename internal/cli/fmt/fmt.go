@@ -4,23 +4,354 @@ package fmt
 import (
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 	"time"
 
+	"google.golang.org/protobuf/types/known/timestamppb"
+
 	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
 )
 
-// PrintTasks pretty-prints the specified to-do list tasks to the given writer.
-func PrintTasks(w io.Writer, tasks []*todopb.Task) error {
-	now := time.Now()
-	for _, t := range tasks {
-		status := ' '
-		completedAt := t.GetCompletedAt()
-		if completedAt.IsValid() && completedAt.AsTime().After(time.Unix(0, 0)) && completedAt.AsTime().Before(now) {
-			status = '✓'
-		}
-		if _, err := fmt.Fprintf(w, "#%s [%c] %s\n", t.GetId(), status, t.GetSummary()); err != nil {
+// minSummaryWidth is the number of columns that task summaries are never
+// truncated to less than.
+const minSummaryWidth = 10
+
+// taskLine is a task to be printed as a line of a task list.
+type taskLine struct {
+	id      string
+	status  rune
+	starred bool
+	summary string
+	// suffix holds the annotations printed after the summary, like the due
+	// time.
+	suffix string
+}
+
+// taskLayout holds the widths of the columns of a task list.
+type taskLayout struct {
+	id, summary, suffix int
+	// star specifies whether the list has a column for the star of starred
+	// tasks, i.e. whether any task is starred.
+	star bool
+}
+
+// newTaskLines converts the specified tasks into lines of a task list and
+// widens the columns of the specified layout to fit them. Due times are
+// printed in the time zone of now.
+func newTaskLines(tasks []*todopb.Task, now time.Time, layout *taskLayout) []taskLine {
+	lines := make([]taskLine, len(tasks))
+	for i, t := range tasks {
+		l := taskLine{id: "#" + displayID(t), status: taskStatus(t, now), starred: t.GetStarred(), summary: t.GetSummary()}
+		if assignee := t.GetAssignee(); assignee != "" {
+			l.suffix += " @" + assignee
+		}
+		if dueAt := t.GetDueAt(); timestampSet(dueAt) {
+			l.suffix += " " + i18n.Sprintf("(due %s)", dueAt.AsTime().In(now.Location()).Format(i18n.Translate("2006-01-02 15:04")))
+		}
+		if t.GetBlocked() && l.status != '✓' {
+			l.suffix += " " + i18n.Translate("(blocked)")
+		}
+		layout.id = max(layout.id, textWidth(l.id))
+		layout.summary = max(layout.summary, textWidth(l.summary))
+		layout.suffix = max(layout.suffix, textWidth(l.suffix))
+		layout.star = layout.star || l.starred
+		lines[i] = l
+	}
+	return lines
+}
+
+// fit narrows the summary column of the layout so that the lines fit the
+// specified width. Zero means no limit.
+func (l *taskLayout) fit(width int) {
+	// The summary starts after the ID, " [x] ", and the star column.
+	avail := width - l.id - 5 - l.suffix
+	if l.star {
+		avail -= 2
+	}
+	if width > 0 && l.summary > avail {
+		l.summary = max(avail, minSummaryWidth)
+	}
+}
+
+// writeTaskLines writes the specified lines of a task list in the specified
+// layout. Overdue tasks are printed in red and completed tasks dimmed.
+func writeTaskLines(w io.Writer, st style, lines []taskLine, layout *taskLayout) error {
+	for _, l := range lines {
+		summary := truncate(l.summary, layout.summary, st.ellipsis)
+		if l.suffix != "" {
+			summary = pad(summary, layout.summary)
+		}
+		star := ""
+		switch {
+		case l.starred:
+			star = st.starred + " "
+		case layout.star:
+			star = "  "
+		}
+		line := fmt.Sprintf("%s [%s] %s%s%s", pad(l.id, layout.id), st.marker(l.status), star, summary, l.suffix)
+		switch l.status {
+		case '!':
+			line = st.red + line + st.reset
+		case '✓':
+			line = st.dim + line + st.reset
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// PrintTasks pretty-prints the specified to-do list tasks to the given writer,
+// one per line with aligned columns. Overdue tasks are marked with "!" and
+// completed tasks with "✓", and starred tasks with "★", or with "x" and "*"
+// if the output is limited to ASCII. If the output is colored, see
+// [Terminal], overdue tasks are printed in red and completed tasks dimmed.
+// Tasks that depend on open tasks are marked as blocked, and assigned tasks
+// are followed by "@" and their assignee. Summaries are truncated to fit the
+// width of the terminal.
+func PrintTasks(w io.Writer, tasks []*todopb.Task) error {
+	term := terminalOf(w)
+	var layout taskLayout
+	lines := newTaskLines(tasks, time.Now().In(Location(w)), &layout)
+	layout.fit(term.Width)
+	return writeTaskLines(w, term.style(), lines, &layout)
+}
+
+// row is a row of a list of names and values.
+type row struct {
+	name  string
+	value any
+}
+
+// writeRows writes the specified rows as a list with aligned values and the
+// names printed in bold.
+func writeRows(w io.Writer, st style, rows []row) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, r := range rows {
+		if _, err := fmt.Fprintf(tw, "%s%s:%s\t%v\n", st.bold, i18n.Translate(r.name), st.reset, r.value); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// PrintTask pretty-prints the details of the specified task to the given
+// writer.
+func PrintTask(w io.Writer, t *todopb.Task) error {
+	loc := Location(w)
+	rows := []row{
+		{"ID", t.GetId()},
+		{"Short code", t.GetShortCode()},
+		{"Summary", t.GetSummary()},
+		{"Description", t.GetDescription()},
+		{"Project", t.GetProject()},
+		{"Tags", strings.Join(t.GetTags(), ", ")},
+		{"Depends on", strings.Join(t.GetDependsOn(), ", ")},
+		{"Status", taskStatusText(t, time.Now())},
+		{"Starred", formatBool(t.GetStarred())},
+		{"Assignee", t.GetAssignee()},
+		{"Links", strings.Join(t.GetLinks(), ", ")},
+		{"Created", formatTimestamp(t.GetCreatedAt(), loc)},
+		{"Updated", formatTimestamp(t.GetUpdatedAt(), loc)},
+		{"Completed", formatTimestamp(t.GetCompletedAt(), loc)},
+		{"Due", formatTimestamp(t.GetDueAt(), loc)},
+		{"Recurrence", t.GetRecurrence()},
+		{"Time zone", t.GetTimeZone()},
+		{"Version", t.GetVersion()},
+	}
+	return writeRows(w, terminalOf(w).style(), rows)
+}
+
+// PrintTaskEvent prints the specified task event as single line to the given
+// writer.
+func PrintTaskEvent(w io.Writer, e *todopb.TaskEvent) error {
+	st := terminalOf(w).style()
+	var action string
+	switch e.GetType() {
+	case todopb.TaskEvent_TYPE_CREATED:
+		action = "created"
+	case todopb.TaskEvent_TYPE_UPDATED:
+		action = "updated"
+	case todopb.TaskEvent_TYPE_COMPLETED:
+		action = "completed"
+	case todopb.TaskEvent_TYPE_ASSIGNED:
+		action = "assigned"
+	case todopb.TaskEvent_TYPE_DELETED:
+		_, err := fmt.Fprintf(w, "%s #%s\n", pad(i18n.Translate("deleted"), 9), displayID(e.GetTask()))
+		return err
+	default:
+		action = "changed"
+	}
+	t := e.GetTask()
+	status := st.marker(taskStatus(t, time.Now()))
+	_, err := fmt.Fprintf(w, "%s #%s [%s] %s\n", pad(i18n.Translate(action), 9), displayID(t), status, t.GetSummary())
+	return err
+}
+
+// displayID returns the identifier of the task to print: its short code if the
+// short code is shorter than its ID, or its ID otherwise. The CLI accepts both.
+func displayID(t *todopb.Task) string {
+	if code := t.GetShortCode(); code != "" && len(code) < len(t.GetId()) {
+		return code
+	}
+	return t.GetId()
+}
+
+// taskStatus returns the status marker of the specified task: "✓" for
+// completed tasks, "!" for overdue tasks, and " " for all other tasks.
+func taskStatus(t *todopb.Task, now time.Time) rune {
+	if isCompleted(t) {
+		return '✓'
+	}
+	if dueAt := t.GetDueAt(); timestampSet(dueAt) && dueAt.AsTime().Before(now) {
+		return '!'
+	}
+	return ' '
+}
+
+// isCompleted checks if the specified task is completed. Servers that predate
+// the completed field only set the completion time. Either way, the time is
+// not compared to the local clock, so tasks completed on a machine whose clock
+// is ahead are still shown as completed.
+func isCompleted(t *todopb.Task) bool {
+	return t.GetCompleted() || timestampSet(t.GetCompletedAt())
+}
+
+// timestampSet checks if the specified timestamp is set. Unset times are sent
+// as nil, or by older servers as the zero time, i.e. the year 1, so all
+// timestamps up to the Unix epoch are considered unset.
+func timestampSet(ts *timestamppb.Timestamp) bool {
+	return ts.IsValid() && ts.AsTime().After(time.Unix(0, 0))
+}
+
+func taskStatusText(t *todopb.Task, now time.Time) string {
+	status := taskStatus(t, now)
+	switch {
+	case status == '✓':
+		return i18n.Translate("completed")
+	case status == '!' && t.GetBlocked():
+		return i18n.Sprintf("overdue, blocked by %s", strings.Join(t.GetBlockedBy(), ", "))
+	case status == '!':
+		return i18n.Translate("overdue")
+	case t.GetBlocked():
+		return i18n.Sprintf("blocked by %s", strings.Join(t.GetBlockedBy(), ", "))
+	default:
+		return i18n.Translate("open")
+	}
+}
+
+// formatBool formats the specified boolean as "yes" or "no".
+func formatBool(b bool) string {
+	if b {
+		return i18n.Translate("yes")
+	}
+	return i18n.Translate("no")
+}
+
+// formatTimestamp formats the specified timestamp in the specified time zone,
+// or returns "-" if the timestamp is not set.
+func formatTimestamp(ts *timestamppb.Timestamp, loc *time.Location) string {
+	if !timestampSet(ts) {
+		return "-"
+	}
+	return ts.AsTime().In(loc).Format(i18n.Translate("2006-01-02 15:04:05"))
+}
+
+// PrintStatus pretty-prints the specified server status to the given writer.
+func PrintStatus(w io.Writer, status *todopb.StatusResponse) error {
+	rows := []row{
+		{"PID", status.GetPid()},
+		{"Version", status.GetVersion()},
+		{"Min. CLI version", status.GetMinClientVersion()},
+		{"Uptime", status.GetUptime().AsDuration().Round(time.Second)},
+		{"Socket", status.GetSocketAddress()},
+		{"HTTP address", status.GetHttpAddress()},
+		{"API base URL", status.GetApiBaseUrl()},
+		{"Storage", status.GetStorageBackend()},
+		{"Tasks", status.GetTaskCount()},
+		{"Subscribers", status.GetEventSubscribers()},
+		{"Dropped events", status.GetDroppedEvents()},
+		{"Disconnections", status.GetDisconnectedSubscribers()},
+	}
+	return writeRows(w, terminalOf(w).style(), rows)
+}
+
+// PrintStats pretty-prints the specified task statistics to the given writer
+// as a small dashboard.
+func PrintStats(w io.Writer, stats *todopb.GetStatsResponse) error {
+	st := terminalOf(w).style()
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	average := "-"
+	if d := stats.GetAverageCompletionTime(); d != nil {
+		average = d.AsDuration().Round(time.Second).String()
+	}
+	rows := []row{
+		{"Open", stats.GetOpenCount()},
+		{"Overdue", stats.GetOverdueCount()},
+		{"Completed", stats.GetCompletedCount()},
+		{"Completed today", stats.GetCompletedTodayCount()},
+		{"Completed this week", stats.GetCompletedThisWeekCount()},
+		{"Avg. completion time", average},
+	}
+	for _, r := range rows {
+		if _, err := fmt.Fprintf(tw, "%s%s:%s\t%v\n", st.bold, i18n.Translate(r.name), st.reset, r.value); err != nil {
+			return err
+		}
+	}
+	groups := []struct {
+		name   string
+		groups []*todopb.GroupStats
+	}{
+		{"Tags", stats.GetTags()},
+		{"Projects", stats.GetProjects()},
+	}
+	for _, g := range groups {
+		if len(g.groups) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(tw, "\n%s%s:%s\n", st.bold, i18n.Translate(g.name), st.reset); err != nil {
+			return err
+		}
+		for _, group := range g.groups {
+			_, err := fmt.Fprintf(tw, "  %s\t%s\t%s\n", group.GetName(),
+				i18n.Sprintf("%d open", group.GetOpenCount()), i18n.Sprintf("%d completed", group.GetCompletedCount()))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return tw.Flush()
+}
+
+// PrintJobs pretty-prints the specified background jobs of the server to the
+// given writer as a table.
+func PrintJobs(w io.Writer, jobs []*todopb.BackgroundJob) error {
+	header := []string{"NAME", "INTERVAL", "RUNS", "FAILURES", "LAST RUN", "DURATION", "NEXT RUN", "LAST ERROR"}
+	for i, h := range header {
+		header[i] = i18n.Translate(h)
+	}
+	loc := Location(w)
+	rows := make([][]string, len(jobs))
+	for i, job := range jobs {
+		duration, next, lastErr := "-", formatTimestamp(job.GetNextRunAt(), loc), "-"
+		if job.GetRuns() > 0 {
+			duration = job.GetLastDuration().AsDuration().Round(time.Millisecond).String()
+		}
+		if job.GetRunning() {
+			next = i18n.Translate("running")
+		}
+		if job.GetLastError() != "" {
+			lastErr = job.GetLastError()
+		}
+		rows[i] = []string{
+			job.GetName(), job.GetInterval().AsDuration().String(),
+			strconv.FormatUint(uint64(job.GetRuns()), 10), strconv.FormatUint(uint64(job.GetFailures()), 10),
+			formatTimestamp(job.GetLastRunAt(), loc), duration, next, lastErr,
+		}
+	}
+	return writeTable(w, terminalOf(w).style(), header, rows)
+}
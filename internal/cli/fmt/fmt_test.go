@@ -3,9 +3,11 @@ package fmt
 import (
 	"bytes"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
@@ -39,8 +41,23 @@ func TestPrintTasks(t *testing.T) {
 			CreatedAt:   now,
 			CompletedAt: &timestamppb.Timestamp{},
 		},
+		{
+			// Completed by a machine whose clock is ahead.
+			Id:          "4",
+			Summary:     "qux",
+			CreatedAt:   now,
+			CompletedAt: timestamppb.New(now.AsTime().Add(time.Minute)),
+			Completed:   true,
+		},
+		{
+			// The zero time, as sent for unset times.
+			Id:          "5",
+			Summary:     "quux",
+			CreatedAt:   now,
+			CompletedAt: timestamppb.New(time.Time{}),
+		},
 	}
-	want := "#1 [✓] foo\n#2 [✓] bar\n#3 [ ] baz\n"
+	want := "#1 [✓] foo\n#2 [✓] bar\n#3 [ ] baz\n#4 [✓] qux\n#5 [ ] quux\n"
 	if err := PrintTasks(buf, tasks); err != nil {
 		t.Fatal(err)
 	}
@@ -65,3 +82,285 @@ func TestPrintTasksToFullDisk(t *testing.T) {
 		t.Errorf("want: %v; got: %v", want, got)
 	}
 }
+
+func TestPrintStatus(t *testing.T) {
+	buf := &bytes.Buffer{}
+	status := &todopb.StatusResponse{
+		Pid:              42,
+		ApiBaseUrl:       "http://127.0.0.1:8080/api",
+		Version:          "1.2.3",
+		MinClientVersion: "1.0.0",
+		Uptime:           durationpb.New(90*time.Second + 400*time.Millisecond),
+		StorageBackend:   "memory",
+		TaskCount:        3,
+		SocketAddress:    "unix:///tmp/todo-daemon.sock",
+		HttpAddress:      "127.0.0.1:8080",
+
+		EventSubscribers:        2,
+		DroppedEvents:           5,
+		DisconnectedSubscribers: 1,
+	}
+	want := "PID:               42\n" +
+		"Version:           1.2.3\n" +
+		"Min. CLI version:  1.0.0\n" +
+		"Uptime:            1m30s\n" +
+		"Socket:            unix:///tmp/todo-daemon.sock\n" +
+		"HTTP address:      127.0.0.1:8080\n" +
+		"API base URL:      http://127.0.0.1:8080/api\n" +
+		"Storage:           memory\n" +
+		"Tasks:             3\n" +
+		"Subscribers:       2\n" +
+		"Dropped events:    5\n" +
+		"Disconnections:    1\n"
+	if err := PrintStatus(buf, status); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("want: %q; got: %q", want, got)
+	}
+}
+
+func TestPrintStats(t *testing.T) {
+	buf := &bytes.Buffer{}
+	stats := &todopb.GetStatsResponse{
+		OpenCount:              3,
+		CompletedCount:         2,
+		CompletedTodayCount:    1,
+		CompletedThisWeekCount: 2,
+		OverdueCount:           1,
+		AverageCompletionTime:  durationpb.New(90*time.Minute + 400*time.Millisecond),
+		Tags: []*todopb.GroupStats{
+			{Name: "errands", OpenCount: 2, CompletedCount: 1},
+		},
+	}
+	want := "Open:                  3\n" +
+		"Overdue:               1\n" +
+		"Completed:             2\n" +
+		"Completed today:       1\n" +
+		"Completed this week:   2\n" +
+		"Avg. completion time:  1h30m0s\n" +
+		"\n" +
+		"Tags:\n" +
+		"  errands  2 open  1 completed\n"
+	if err := PrintStats(buf, stats); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("want: %q; got: %q", want, got)
+	}
+}
+
+func TestPrintJobs(t *testing.T) {
+	buf := &bytes.Buffer{}
+	lastRun := time.Date(2025, 1, 2, 15, 4, 5, 0, time.Local)
+	jobs := []*todopb.BackgroundJob{
+		{
+			Name:         "backup",
+			Interval:     durationpb.New(time.Hour),
+			Runs:         2,
+			Failures:     1,
+			LastRunAt:    timestamppb.New(lastRun),
+			LastDuration: durationpb.New(1234 * time.Microsecond),
+			LastError:    "disk full",
+			NextRunAt:    timestamppb.New(lastRun.Add(time.Hour)),
+		},
+		{
+			Name:     "purge",
+			Interval: durationpb.New(24 * time.Hour),
+			Running:  true,
+		},
+	}
+	want := "NAME    INTERVAL  RUNS  FAILURES  LAST RUN             DURATION  NEXT RUN             LAST ERROR\n" +
+		"backup  1h0m0s    2     1         2025-01-02 15:04:05  1ms       2025-01-02 16:04:05  disk full\n" +
+		"purge   24h0m0s   0     0         -                    -         running              -\n"
+	if err := PrintJobs(buf, jobs); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("want: %q; got: %q", want, got)
+	}
+}
+
+func TestPrintTaskEvent(t *testing.T) {
+	buf := &bytes.Buffer{}
+	events := []*todopb.TaskEvent{
+		{Type: todopb.TaskEvent_TYPE_CREATED, Task: &todopb.Task{Id: "4", Summary: "qux"}},
+		{Type: todopb.TaskEvent_TYPE_DELETED, Task: &todopb.Task{Id: "2"}},
+	}
+	for _, e := range events {
+		if err := PrintTaskEvent(buf, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := "created   #4 [ ] qux\ndeleted   #2\n"
+	if got := buf.String(); got != want {
+		t.Errorf("want: %q; got: %q", want, got)
+	}
+}
+
+func TestPrintOverdueTasks(t *testing.T) {
+	buf := &bytes.Buffer{}
+	dueAt := time.Date(2025, 1, 2, 15, 4, 0, 0, time.Local)
+	tasks := []*todopb.Task{
+		{Id: "1", Summary: "foo", DueAt: timestamppb.New(dueAt)},
+		{Id: "2", Summary: "bar", DueAt: timestamppb.New(dueAt), CompletedAt: timestamppb.New(dueAt)},
+	}
+	want := "#1 [!] foo (due 2025-01-02 15:04)\n#2 [✓] bar (due 2025-01-02 15:04)\n"
+	if err := PrintTasks(buf, tasks); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("want: %q; got: %q", want, got)
+	}
+}
+
+func TestPrintTasksLocation(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	dueAt := time.Date(2025, 1, 2, 15, 4, 0, 0, time.UTC)
+	tasks := []*todopb.Task{{Id: "1", Summary: "foo", DueAt: timestamppb.New(dueAt)}}
+	want := "#1 [!] foo (due 2025-01-03 00:04)\n"
+	if err := PrintTasks(&Terminal{Writer: buf, Location: loc}, tasks); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("want: %q; got: %q", want, got)
+	}
+}
+
+func TestPrintBlockedTasks(t *testing.T) {
+	buf := &bytes.Buffer{}
+	completedAt := time.Date(2025, 1, 2, 15, 4, 0, 0, time.Local)
+	tasks := []*todopb.Task{
+		{Id: "1", Summary: "foo"},
+		{Id: "2", Summary: "bar", DependsOn: []string{"1"}, BlockedBy: []string{"1"}, Blocked: true},
+		{Id: "3", Summary: "baz", DependsOn: []string{"1"}, BlockedBy: []string{"1"}, Blocked: true,
+			CompletedAt: timestamppb.New(completedAt)},
+	}
+	want := "#1 [ ] foo\n#2 [ ] bar (blocked)\n#3 [✓] baz\n"
+	if err := PrintTasks(buf, tasks); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("want: %q; got: %q", want, got)
+	}
+}
+
+func TestPrintStarredTasks(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tasks := []*todopb.Task{
+		{Id: "1", Summary: "foo", Starred: true},
+		{Id: "2", Summary: "bar"},
+	}
+	want := "#1 [ ] ★ foo\n#2 [ ]   bar\n"
+	if err := PrintTasks(buf, tasks); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("want: %q; got: %q", want, got)
+	}
+}
+
+func TestPrintTasksShortCode(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tasks := []*todopb.Task{
+		{Id: "1", ShortCode: "6b86b27", Summary: "foo"},
+		{Id: "0f8fad5b-d9cb-469f-a165-70867728950e", ShortCode: "2c26b46", Summary: "bar"},
+	}
+	want := "#1       [ ] foo\n#2c26b46 [ ] bar\n"
+	if err := PrintTasks(buf, tasks); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("want: %q; got: %q", want, got)
+	}
+}
+
+func TestPrintTasksAligned(t *testing.T) {
+	dueAt := time.Date(2025, 1, 2, 15, 4, 0, 0, time.Local)
+	tasks := []*todopb.Task{
+		{Id: "9", Summary: "Get some milk 🥛", DueAt: timestamppb.New(dueAt)},
+		{Id: "10", Summary: "Take over the world! 🌍"},
+		{Id: "11", Summary: "bar", DueAt: timestamppb.New(dueAt)},
+	}
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{
+			width: 0,
+			want: "#9  [!] Get some milk 🥛        (due 2025-01-02 15:04)\n" +
+				"#10 [ ] Take over the world! 🌍\n" +
+				"#11 [!] bar                     (due 2025-01-02 15:04)\n",
+		},
+		{
+			width: 40,
+			want: "#9  [!] Get some…  (due 2025-01-02 15:04)\n" +
+				"#10 [ ] Take over…\n" +
+				"#11 [!] bar        (due 2025-01-02 15:04)\n",
+		},
+	}
+	for _, tt := range tests {
+		buf := &bytes.Buffer{}
+		if err := PrintTasks(&Terminal{Writer: buf, Width: tt.width}, tasks); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("width %d: want: %q; got: %q", tt.width, tt.want, got)
+		}
+	}
+}
+
+func TestPrintTasksColored(t *testing.T) {
+	buf := &bytes.Buffer{}
+	now := time.Now()
+	tasks := []*todopb.Task{
+		{Id: "1", Summary: "foo", DueAt: timestamppb.New(now.Add(-time.Hour))},
+		{Id: "2", Summary: "bar", CompletedAt: timestamppb.New(now.Add(-time.Hour))},
+		{Id: "3", Summary: "baz"},
+	}
+	if err := PrintTasks(&Terminal{Writer: buf, Color: true}, tasks); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.HasPrefix(lines[0], ansiRed) || !strings.HasSuffix(lines[0], ansiReset) {
+		t.Errorf("want overdue task in red; got: %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], ansiDim) || !strings.HasSuffix(lines[1], ansiReset) {
+		t.Errorf("want completed task dimmed; got: %q", lines[1])
+	}
+	if lines[2] != "#3 [ ] baz" {
+		t.Errorf("want open task without colors; got: %q", lines[2])
+	}
+}
+
+func TestPrintTasksASCII(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tasks := []*todopb.Task{
+		{Id: "1", Summary: "foo", CompletedAt: timestamppb.Now()},
+		{Id: "2", Summary: "bar", Starred: true},
+		{Id: "3", Summary: "a very long summary"},
+	}
+	want := "#1 [x]   foo\n#2 [ ] * bar\n#3 [ ]   a very l...\n"
+	if err := PrintTasks(&Terminal{Writer: buf, Width: 20, ASCII: true}, tasks); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("want: %q; got: %q", want, got)
+	}
+}
+
+func TestPrintJobsColored(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jobs := []*todopb.BackgroundJob{{Name: "backup", Interval: durationpb.New(time.Hour)}}
+	if err := PrintJobs(&Terminal{Writer: buf, Color: true}, jobs); err != nil {
+		t.Fatal(err)
+	}
+	header, _, _ := strings.Cut(buf.String(), "\n")
+	if !strings.HasPrefix(header, ansiBold+"NAME") || !strings.HasSuffix(header, ansiReset) {
+		t.Errorf("want bold header; got: %q", header)
+	}
+}
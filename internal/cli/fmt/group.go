@@ -0,0 +1,158 @@
+package fmt
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// The fields that tasks can be grouped by, see [GroupTasks].
+const (
+	GroupByTag     = "tag"
+	GroupByProject = "project"
+	GroupByDue     = "due"
+)
+
+// The groups of tasks grouped by due time, in the order they are printed.
+const (
+	dueOverdue = iota
+	dueToday
+	dueThisWeek
+	dueLater
+	dueNever
+	dueCompleted
+)
+
+// dueGroupNames are the names of the groups of tasks grouped by due time.
+var dueGroupNames = [...]string{"Overdue", "Today", "This week", "Later", "No due date", "Completed"}
+
+// TaskGroup is a group of tasks printed under a common header.
+type TaskGroup struct {
+	// Name is the header of the group, e.g. a tag.
+	Name string
+	// Tasks are the tasks of the group.
+	Tasks []*todopb.Task
+}
+
+// GroupTasks groups the specified tasks by the specified field, keeping their
+// order within each group. Empty groups are omitted.
+//
+//   - "tag" groups the tasks by their tags, in alphabetical order. Tasks with
+//     several tags appear in several groups, and tasks without tags come last.
+//   - "project" groups the tasks by their project, in alphabetical order.
+//     Tasks without project come last.
+//   - "due" groups the open tasks into overdue tasks, tasks due today, tasks
+//     due within the next seven days, tasks due later, and tasks without due
+//     date, followed by the completed tasks. Days begin at midnight in the
+//     time zone of now.
+func GroupTasks(tasks []*todopb.Task, by string, now time.Time) ([]TaskGroup, error) {
+	switch by {
+	case GroupByTag:
+		return groupByName(tasks, (*todopb.Task).GetTags, i18n.Translate("No tag")), nil
+	case GroupByProject:
+		return groupByName(tasks, func(t *todopb.Task) []string {
+			if p := t.GetProject(); p != "" {
+				return []string{p}
+			}
+			return nil
+		}, i18n.Translate("No project")), nil
+	case GroupByDue:
+		return groupByDue(tasks, now), nil
+	default:
+		return nil, fmt.Errorf("invalid group field: '%s' (want tag, project, or due)", by)
+	}
+}
+
+// groupByName groups the specified tasks by the names returned by the
+// specified function, in alphabetical order. Tasks without names are put into
+// a last group with the specified name.
+func groupByName(tasks []*todopb.Task, names func(*todopb.Task) []string, none string) []TaskGroup {
+	byName := make(map[string][]*todopb.Task)
+	var rest []*todopb.Task
+	for _, t := range tasks {
+		ns := names(t)
+		if len(ns) == 0 {
+			rest = append(rest, t)
+		}
+		for _, name := range ns {
+			byName[name] = append(byName[name], t)
+		}
+	}
+	groups := make([]TaskGroup, 0, len(byName)+1)
+	for name, ts := range byName {
+		groups = append(groups, TaskGroup{Name: name, Tasks: ts})
+	}
+	slices.SortFunc(groups, func(a, b TaskGroup) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	if len(rest) > 0 {
+		groups = append(groups, TaskGroup{Name: none, Tasks: rest})
+	}
+	return groups
+}
+
+// groupByDue groups the specified tasks by their due time relative to now.
+func groupByDue(tasks []*todopb.Task, now time.Time) []TaskGroup {
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	tomorrow, nextWeek := startOfDay.AddDate(0, 0, 1), startOfDay.AddDate(0, 0, 7)
+	groups := make([]TaskGroup, len(dueGroupNames))
+	for i, name := range dueGroupNames {
+		groups[i].Name = i18n.Translate(name)
+	}
+	for _, t := range tasks {
+		var i int
+		dueAt := t.GetDueAt().AsTime()
+		switch {
+		case taskStatus(t, now) == '✓':
+			i = dueCompleted
+		case !timestampSet(t.GetDueAt()):
+			i = dueNever
+		case dueAt.Before(now):
+			i = dueOverdue
+		case dueAt.Before(tomorrow):
+			i = dueToday
+		case dueAt.Before(nextWeek):
+			i = dueThisWeek
+		default:
+			i = dueLater
+		}
+		groups[i].Tasks = append(groups[i].Tasks, t)
+	}
+	return slices.DeleteFunc(groups, func(g TaskGroup) bool {
+		return len(g.Tasks) == 0
+	})
+}
+
+// PrintTaskGroups pretty-prints the specified groups of tasks to the given
+// writer: each group's name in bold, followed by its number of tasks and the
+// tasks, see [PrintTasks]. The columns are aligned across all groups, and the
+// groups are separated by blank lines.
+func PrintTaskGroups(w io.Writer, groups []TaskGroup) error {
+	term := terminalOf(w)
+	st := term.style()
+	now := time.Now().In(Location(w))
+	var layout taskLayout
+	lines := make([][]taskLine, len(groups))
+	for i, g := range groups {
+		lines[i] = newTaskLines(g.Tasks, now, &layout)
+	}
+	layout.fit(term.Width)
+	for i, g := range groups {
+		sep := ""
+		if i > 0 {
+			sep = "\n"
+		}
+		if _, err := fmt.Fprintf(w, "%s%s%s%s (%d)\n", sep, st.bold, g.Name, st.reset, len(g.Tasks)); err != nil {
+			return err
+		}
+		if err := writeTaskLines(w, st, lines[i], &layout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
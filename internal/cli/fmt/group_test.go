@@ -0,0 +1,91 @@
+package fmt
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+)
+
+func TestGroupTasks(t *testing.T) {
+	// now is a Wednesday.
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.Local)
+	due := func(d time.Duration) *timestamppb.Timestamp {
+		return timestamppb.New(now.Add(d))
+	}
+	tasks := []*todopb.Task{
+		{Id: "1", Summary: "a", Tags: []string{"work", "errands"}, Project: "home", DueAt: due(-time.Hour)},
+		{Id: "2", Summary: "b", Tags: []string{"errands"}, DueAt: due(3 * time.Hour)},
+		{Id: "3", Summary: "c", DueAt: due(13 * time.Hour)},
+		{Id: "4", Summary: "d", Project: "garden", DueAt: due(30 * 24 * time.Hour)},
+		{Id: "5", Summary: "e", Project: "home"},
+		{Id: "6", Summary: "f", DueAt: due(-time.Hour), CompletedAt: due(-time.Minute)},
+	}
+	tests := []struct {
+		by   string
+		want map[string][]string
+		keys []string
+	}{
+		{
+			by:   GroupByTag,
+			keys: []string{"errands", "work", "No tag"},
+			want: map[string][]string{"errands": {"1", "2"}, "work": {"1"}, "No tag": {"3", "4", "5", "6"}},
+		},
+		{
+			by:   GroupByProject,
+			keys: []string{"garden", "home", "No project"},
+			want: map[string][]string{"garden": {"4"}, "home": {"1", "5"}, "No project": {"2", "3", "6"}},
+		},
+		{
+			by:   GroupByDue,
+			keys: []string{"Overdue", "Today", "This week", "Later", "No due date", "Completed"},
+			want: map[string][]string{
+				"Overdue": {"1"}, "Today": {"2"}, "This week": {"3"}, "Later": {"4"}, "No due date": {"5"},
+				"Completed": {"6"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		groups, err := GroupTasks(tasks, tt.by, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(groups) != len(tt.keys) {
+			t.Fatalf("%s: want groups %v; got: %v", tt.by, tt.keys, groups)
+		}
+		for i, g := range groups {
+			if g.Name != tt.keys[i] {
+				t.Errorf("%s: want group %d to be %q; got: %q", tt.by, i, tt.keys[i], g.Name)
+			}
+			var ids []string
+			for _, task := range g.Tasks {
+				ids = append(ids, task.GetId())
+			}
+			if want := tt.want[g.Name]; !slices.Equal(ids, want) {
+				t.Errorf("%s: want group %q to hold %v; got: %v", tt.by, g.Name, want, ids)
+			}
+		}
+	}
+	if _, err := GroupTasks(tasks, "priority", now); err == nil {
+		t.Error("want error for invalid group field")
+	}
+}
+
+func TestPrintTaskGroups(t *testing.T) {
+	buf := &bytes.Buffer{}
+	groups := []TaskGroup{
+		{Name: "errands", Tasks: []*todopb.Task{{Id: "1", Summary: "foo"}, {Id: "12", Summary: "bar"}}},
+		{Name: "No tag", Tasks: []*todopb.Task{{Id: "3", Summary: "baz"}}},
+	}
+	want := "errands (2)\n#1  [ ] foo\n#12 [ ] bar\n\nNo tag (1)\n#3  [ ] baz\n"
+	if err := PrintTaskGroups(buf, groups); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("want: %q; got: %q", want, got)
+	}
+}
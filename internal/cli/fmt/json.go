@@ -0,0 +1,60 @@
+package fmt
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+)
+
+// StatusJSON is the JSON representation of the server status printed by
+// 'status --json'. Unlike the protobuf message, its field names are part of
+// the CLI's interface: fields may be added, but are never renamed or removed.
+type StatusJSON struct {
+	PID           uint32 `json:"pid"`
+	APIBaseURL    string `json:"api_base_url"`
+	Version       string `json:"version"`
+	MinCLIVersion string `json:"min_cli_version"`
+	UptimeSeconds int64  `json:"uptime_seconds"`
+	Socket        string `json:"socket"`
+	HTTPAddress   string `json:"http_address"`
+	Storage       string `json:"storage"`
+	Tasks         uint32 `json:"tasks"`
+	// EventSubscribers is the number of clients watching the tasks, and
+	// DroppedEvents and DisconnectedSubscribers count the events dropped for
+	// and the clients disconnected for falling behind.
+	EventSubscribers        uint32 `json:"event_subscribers"`
+	DroppedEvents           uint64 `json:"dropped_events"`
+	DisconnectedSubscribers uint64 `json:"disconnected_subscribers"`
+}
+
+// NewStatusJSON converts the specified server status into its JSON
+// representation.
+func NewStatusJSON(status *todopb.StatusResponse) *StatusJSON {
+	return &StatusJSON{
+		PID:                     status.GetPid(),
+		APIBaseURL:              status.GetApiBaseUrl(),
+		Version:                 status.GetVersion(),
+		MinCLIVersion:           status.GetMinClientVersion(),
+		UptimeSeconds:           int64(status.GetUptime().AsDuration().Seconds()),
+		Socket:                  status.GetSocketAddress(),
+		HTTPAddress:             status.GetHttpAddress(),
+		Storage:                 status.GetStorageBackend(),
+		Tasks:                   status.GetTaskCount(),
+		EventSubscribers:        status.GetEventSubscribers(),
+		DroppedEvents:           status.GetDroppedEvents(),
+		DisconnectedSubscribers: status.GetDisconnectedSubscribers(),
+	}
+}
+
+// PrintStatusJSON prints the specified server status to the given writer as
+// JSON document, see [StatusJSON].
+func PrintStatusJSON(w io.Writer, status *todopb.StatusResponse) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(NewStatusJSON(status)); err != nil {
+		return fmt.Errorf("cannot print status: %w", err)
+	}
+	return nil
+}
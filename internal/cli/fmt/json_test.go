@@ -0,0 +1,72 @@
+package fmt
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// checkGolden compares the specified output to the golden file with the
+// specified name in testdata, or updates the file if the -update flag is set.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o600); err != nil {
+			t.Fatalf("cannot update golden file: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("cannot read golden file: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\nwant:\n%s\ngot:\n%s", path, want, got)
+	}
+}
+
+func TestPrintStatusJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		status *todopb.StatusResponse
+	}{
+		{
+			"status.json",
+			&todopb.StatusResponse{
+				Pid:              42,
+				ApiBaseUrl:       "http://127.0.0.1:8080/api",
+				Version:          "1.2.3",
+				MinClientVersion: "1.0.0",
+				Uptime:           durationpb.New(90*time.Second + 400*time.Millisecond),
+				StorageBackend:   "memory",
+				TaskCount:        3,
+				SocketAddress:    "unix:///tmp/todo-daemon.sock",
+				HttpAddress:      "127.0.0.1:8080",
+
+				EventSubscribers:        2,
+				DroppedEvents:           5,
+				DisconnectedSubscribers: 1,
+			},
+		},
+		{"status_empty.json", &todopb.StatusResponse{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := PrintStatusJSON(buf, tt.status); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, tt.name, buf.Bytes())
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package fmt
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+)
+
+// porcelainEscaper escapes the characters that would break the lines and
+// fields of the porcelain format.
+var porcelainEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// writePorcelain writes the specified fields as a line of the porcelain
+// format.
+//
+// The porcelain format is a stable, tab-separated output format for scripts,
+// like the porcelain format of git. Unlike the human-readable format, it is
+// never colored, aligned, truncated, or translated, and new fields are only
+// ever appended to the end of a line. Tabs, line breaks, and backslashes in
+// values are escaped as "\t", "\n", and "\\". Timestamps are written in RFC
+// 3339 format in UTC, durations in whole seconds, and booleans as "true" or
+// "false". Unset values are empty.
+func writePorcelain(w io.Writer, fields ...string) error {
+	for i, f := range fields {
+		fields[i] = porcelainEscaper.Replace(f)
+	}
+	_, err := io.WriteString(w, strings.Join(fields, "\t")+"\n")
+	return err
+}
+
+// porcelainTimestamp formats the specified timestamp for the porcelain format.
+func porcelainTimestamp(ts *timestamppb.Timestamp) string {
+	if !timestampSet(ts) {
+		return ""
+	}
+	return ts.AsTime().UTC().Format(time.RFC3339)
+}
+
+// porcelainStatus returns the status of the specified task in the porcelain
+// format: "completed", "overdue", or "open".
+func porcelainStatus(t *todopb.Task, now time.Time) string {
+	switch taskStatus(t, now) {
+	case '✓':
+		return "completed"
+	case '!':
+		return "overdue"
+	default:
+		return "open"
+	}
+}
+
+// PrintTasksPorcelain prints the specified tasks in the porcelain format, one
+// line per task with these fields: ID, short code, status ("open",
+// "completed", or "overdue"), starred, blocked, due time, project, tags
+// separated by commas, and summary.
+func PrintTasksPorcelain(w io.Writer, tasks []*todopb.Task) error {
+	now := time.Now()
+	for _, t := range tasks {
+		err := writePorcelain(w,
+			t.GetId(),
+			t.GetShortCode(),
+			porcelainStatus(t, now),
+			strconv.FormatBool(t.GetStarred()),
+			strconv.FormatBool(t.GetBlocked() && taskStatus(t, now) != '✓'),
+			porcelainTimestamp(t.GetDueAt()),
+			t.GetProject(),
+			strings.Join(t.GetTags(), ","),
+			t.GetSummary(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintStatusPorcelain prints the specified server status in the porcelain
+// format, one line per key and value.
+func PrintStatusPorcelain(w io.Writer, status *todopb.StatusResponse) error {
+	rows := [][]string{
+		{"pid", strconv.FormatUint(uint64(status.GetPid()), 10)},
+		{"version", status.GetVersion()},
+		{"min_cli_version", status.GetMinClientVersion()},
+		{"uptime", seconds(status.GetUptime().AsDuration())},
+		{"socket", status.GetSocketAddress()},
+		{"http_address", status.GetHttpAddress()},
+		{"api_base_url", status.GetApiBaseUrl()},
+		{"storage", status.GetStorageBackend()},
+		{"tasks", strconv.FormatUint(uint64(status.GetTaskCount()), 10)},
+		{"event_subscribers", strconv.FormatUint(uint64(status.GetEventSubscribers()), 10)},
+		{"dropped_events", strconv.FormatUint(status.GetDroppedEvents(), 10)},
+		{"disconnected_subscribers", strconv.FormatUint(status.GetDisconnectedSubscribers(), 10)},
+	}
+	for _, r := range rows {
+		if err := writePorcelain(w, r...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintStatsPorcelain prints the specified task statistics in the porcelain
+// format, one line per key and value, followed by one line per tag and project
+// with the kind ("tag" or "project"), the name, and the numbers of open and
+// completed tasks.
+func PrintStatsPorcelain(w io.Writer, stats *todopb.GetStatsResponse) error {
+	average := ""
+	if d := stats.GetAverageCompletionTime(); d != nil {
+		average = seconds(d.AsDuration())
+	}
+	rows := [][]string{
+		{"open", strconv.FormatUint(uint64(stats.GetOpenCount()), 10)},
+		{"overdue", strconv.FormatUint(uint64(stats.GetOverdueCount()), 10)},
+		{"completed", strconv.FormatUint(uint64(stats.GetCompletedCount()), 10)},
+		{"completed_today", strconv.FormatUint(uint64(stats.GetCompletedTodayCount()), 10)},
+		{"completed_this_week", strconv.FormatUint(uint64(stats.GetCompletedThisWeekCount()), 10)},
+		{"average_completion_time", average},
+	}
+	for _, g := range stats.GetTags() {
+		rows = append(rows, groupStatsRow("tag", g))
+	}
+	for _, g := range stats.GetProjects() {
+		rows = append(rows, groupStatsRow("project", g))
+	}
+	for _, r := range rows {
+		if err := writePorcelain(w, r...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// groupStatsRow returns the porcelain fields of the specified statistics of a
+// tag or project.
+func groupStatsRow(kind string, g *todopb.GroupStats) []string {
+	return []string{
+		kind,
+		g.GetName(),
+		strconv.FormatUint(uint64(g.GetOpenCount()), 10),
+		strconv.FormatUint(uint64(g.GetCompletedCount()), 10),
+	}
+}
+
+// seconds formats the specified duration as a number of whole seconds.
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
@@ -0,0 +1,112 @@
+package fmt
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+func TestPrintTasksPorcelain(t *testing.T) {
+	buf := &bytes.Buffer{}
+	now := time.Now()
+	due := time.Date(2025, 12, 24, 17, 0, 0, 0, time.FixedZone("CET", 60*60))
+	tasks := []*todopb.Task{
+		{
+			Id:        "1",
+			ShortCode: "k3",
+			Summary:   "Buy presents",
+			Starred:   true,
+			DueAt:     timestamppb.New(due),
+			Project:   "xmas",
+			Tags:      []string{"errands", "family"},
+		},
+		{Id: "2", Summary: "foo", CompletedAt: timestamppb.New(now.Add(-time.Hour)), Blocked: true},
+		{Id: "3", Summary: "tab\there\\new\nline", Blocked: true},
+	}
+	want := "1\tk3\toverdue\ttrue\tfalse\t2025-12-24T16:00:00Z\txmas\terrands,family\tBuy presents\n" +
+		"2\t\tcompleted\tfalse\tfalse\t\t\t\tfoo\n" +
+		"3\t\topen\tfalse\ttrue\t\t\t\ttab\\there\\\\new\\nline\n"
+	// Porcelain output is neither colored nor truncated nor translated.
+	i18n.SetLanguage("de")
+	t.Cleanup(func() { i18n.SetLanguage(i18n.English) })
+	if err := PrintTasksPorcelain(&Terminal{Writer: buf, Color: true, Width: 10}, tasks); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("want: %q; got: %q", want, got)
+	}
+}
+
+func TestPrintStatusPorcelain(t *testing.T) {
+	buf := &bytes.Buffer{}
+	status := &todopb.StatusResponse{
+		Pid:              42,
+		ApiBaseUrl:       "http://127.0.0.1:8080/api",
+		Version:          "1.2.3",
+		MinClientVersion: "1.0.0",
+		Uptime:           durationpb.New(90*time.Second + 400*time.Millisecond),
+		StorageBackend:   "memory",
+		TaskCount:        3,
+		SocketAddress:    "unix:///tmp/todo-daemon.sock",
+
+		EventSubscribers:        2,
+		DroppedEvents:           5,
+		DisconnectedSubscribers: 1,
+	}
+	want := "pid\t42\n" +
+		"version\t1.2.3\n" +
+		"min_cli_version\t1.0.0\n" +
+		"uptime\t90\n" +
+		"socket\tunix:///tmp/todo-daemon.sock\n" +
+		"http_address\t\n" +
+		"api_base_url\thttp://127.0.0.1:8080/api\n" +
+		"storage\tmemory\n" +
+		"tasks\t3\n" +
+		"event_subscribers\t2\n" +
+		"dropped_events\t5\n" +
+		"disconnected_subscribers\t1\n"
+	if err := PrintStatusPorcelain(buf, status); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("want: %q; got: %q", want, got)
+	}
+}
+
+func TestPrintStatsPorcelain(t *testing.T) {
+	buf := &bytes.Buffer{}
+	stats := &todopb.GetStatsResponse{
+		OpenCount:              3,
+		CompletedCount:         2,
+		CompletedTodayCount:    1,
+		CompletedThisWeekCount: 2,
+		OverdueCount:           1,
+		AverageCompletionTime:  durationpb.New(90*time.Minute + 400*time.Millisecond),
+		Tags: []*todopb.GroupStats{
+			{Name: "errands", OpenCount: 2, CompletedCount: 1},
+		},
+		Projects: []*todopb.GroupStats{
+			{Name: "home", OpenCount: 1},
+		},
+	}
+	want := "open\t3\n" +
+		"overdue\t1\n" +
+		"completed\t2\n" +
+		"completed_today\t1\n" +
+		"completed_this_week\t2\n" +
+		"average_completion_time\t5400\n" +
+		"tag\terrands\t2\t1\n" +
+		"project\thome\t1\t0\n"
+	if err := PrintStatsPorcelain(buf, stats); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("want: %q; got: %q", want, got)
+	}
+}
@@ -0,0 +1,218 @@
+package fmt
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/text/width"
+
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// ColorMode specifies when CLI output is colored.
+type ColorMode string
+
+// The supported color modes.
+const (
+	// ColorAuto colors the output if it is written to a terminal and the
+	// NO_COLOR environment variable is not set.
+	ColorAuto ColorMode = "auto"
+	// ColorAlways colors the output regardless of where it is written to.
+	ColorAlways ColorMode = "always"
+	// ColorNever never colors the output.
+	ColorNever ColorMode = "never"
+)
+
+// ParseColorMode parses the specified color mode: "auto", "always", or
+// "never".
+func ParseColorMode(s string) (ColorMode, error) {
+	switch m := ColorMode(s); m {
+	case ColorAuto, ColorAlways, ColorNever:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid color mode: '%s' (want auto, always, or never)", s)
+	}
+}
+
+// The ANSI escape sequences for styling the output.
+const (
+	ansiBold  = "\033[1m"
+	ansiDim   = "\033[2m"
+	ansiRed   = "\033[31m"
+	ansiReset = "\033[0m"
+)
+
+// Terminal is a writer for CLI output that knows whether the output may be
+// colored and how wide it may be. The Print functions of this package detect
+// the capabilities of other writers themselves.
+type Terminal struct {
+	io.Writer
+	// Color specifies whether the output may contain ANSI colors.
+	Color bool
+	// Width is the number of columns of the terminal. Longer task summaries
+	// are truncated. Zero means no limit, e.g. if the output is piped.
+	Width int
+	// ASCII specifies whether the output is limited to ASCII characters, e.g.
+	// for terminals without UTF-8 support. Completed tasks are marked with
+	// "x" instead of "✓" then.
+	ASCII bool
+	// Location is the time zone that times are printed in. Nil means the
+	// local time zone.
+	Location *time.Location
+}
+
+// NewTerminal creates a [Terminal] that writes to the specified writer and
+// colors the output according to the specified mode. The width, and whether
+// the terminal supports UTF-8, are detected if the writer is a terminal.
+func NewTerminal(w io.Writer, mode ColorMode) *Terminal {
+	t := &Terminal{Writer: w}
+	isTerminal := IsTerminal(w)
+	switch mode {
+	case ColorAlways:
+		t.Color = true
+	case ColorAuto:
+		t.Color = isTerminal && os.Getenv("NO_COLOR") == ""
+	}
+	if isTerminal {
+		t.Width = terminalWidth(w.(*os.File))
+		t.ASCII = !i18n.UTF8()
+	}
+	return t
+}
+
+// IsTerminal reports whether the specified reader or writer is a terminal,
+// e.g. whether the user can answer a prompt on standard input.
+func IsTerminal(rw any) bool {
+	f, ok := rw.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0 && isTerminal(f)
+}
+
+// terminalOf returns the specified writer if it is a [Terminal], or a new
+// terminal with automatic colors otherwise.
+func terminalOf(w io.Writer) *Terminal {
+	if t, ok := w.(*Terminal); ok {
+		return t
+	}
+	return NewTerminal(w, ColorAuto)
+}
+
+// Location returns the time zone that times are printed in to the specified
+// writer: the location of a [Terminal], or the local time zone otherwise.
+func Location(w io.Writer) *time.Location {
+	if t, ok := w.(*Terminal); ok && t.Location != nil {
+		return t.Location
+	}
+	return time.Local
+}
+
+// style holds the ANSI escape sequences that the output is styled with, which
+// are all empty if the output is not colored, and the symbols that mark tasks.
+type style struct {
+	bold, dim, red, reset string
+	// completed and starred mark completed and starred tasks, and ellipsis
+	// ends truncated summaries.
+	completed, starred, ellipsis string
+}
+
+// style returns the style of the terminal's output.
+func (t *Terminal) style() style {
+	st := style{completed: "✓", starred: "★", ellipsis: "…"}
+	if t.ASCII {
+		st.completed, st.starred, st.ellipsis = "x", "*", "..."
+	}
+	if t.Color {
+		st.bold, st.dim, st.red, st.reset = ansiBold, ansiDim, ansiRed, ansiReset
+	}
+	return st
+}
+
+// marker returns the symbol that the specified task status is printed as,
+// see [taskStatus].
+func (st style) marker(status rune) string {
+	if status == '✓' {
+		return st.completed
+	}
+	return string(status)
+}
+
+// textWidth returns the number of columns that the specified text takes up
+// in a terminal. East Asian wide characters and most emoji take up two
+// columns.
+func textWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			n += 2
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// truncate shortens the specified text to the specified number of columns,
+// replacing the end with the specified ellipsis, e.g. "…", if necessary. White
+// space before the ellipsis is removed.
+func truncate(s string, columns int, ellipsis string) string {
+	if textWidth(s) <= columns {
+		return s
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		w := textWidth(string(r))
+		if n+w > columns-textWidth(ellipsis) {
+			break
+		}
+		b.WriteRune(r)
+		n += w
+	}
+	return strings.TrimRight(b.String(), " ") + ellipsis
+}
+
+// pad appends spaces to the specified text up to the specified number of
+// columns.
+func pad(s string, columns int) string {
+	if n := textWidth(s); n < columns {
+		return s + strings.Repeat(" ", columns-n)
+	}
+	return s
+}
+
+// writeTable writes the specified rows as a table with aligned columns that
+// are separated by two spaces. The header row is printed in bold.
+func writeTable(w io.Writer, st style, header []string, rows [][]string) error {
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], textWidth(cell))
+		}
+	}
+	format := func(row []string) string {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			if i < len(row)-1 {
+				cell = pad(cell, widths[i]+2)
+			}
+			cells[i] = cell
+		}
+		return strings.Join(cells, "")
+	}
+	if _, err := fmt.Fprintln(w, st.bold+format(header)+st.reset); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if _, err := fmt.Fprintln(w, format(row)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+//go:build !windows
+
+package fmt
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminalWidth returns the number of columns of the specified terminal, or
+// zero if it cannot be determined.
+func terminalWidth(f *os.File) int {
+	ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0
+	}
+	return int(ws.Col)
+}
+
+// isTerminal reports whether the specified file is a terminal. Unlike other
+// character devices, e.g. /dev/null, terminals have a window size.
+func isTerminal(f *os.File) bool {
+	_, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	return err == nil
+}
@@ -0,0 +1,55 @@
+package fmt
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseColorMode(t *testing.T) {
+	for _, s := range []string{"auto", "always", "never"} {
+		if m, err := ParseColorMode(s); err != nil || string(m) != s {
+			t.Errorf("%s: want valid color mode; got: %q, %v", s, m, err)
+		}
+	}
+	if _, err := ParseColorMode("sometimes"); err == nil {
+		t.Error("want error for invalid color mode")
+	}
+}
+
+func TestNewTerminal(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tests := []struct {
+		mode ColorMode
+		want bool
+	}{
+		{ColorAuto, false},
+		{ColorAlways, true},
+		{ColorNever, false},
+	}
+	for _, tt := range tests {
+		if got := NewTerminal(buf, tt.mode); got.Color != tt.want || got.Width != 0 {
+			t.Errorf("%s: want color %t and no width; got: %+v", tt.mode, tt.want, got)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s        string
+		columns  int
+		ellipsis string
+		want     string
+	}{
+		{"foo", 3, "…", "foo"},
+		{"foobar", 4, "…", "foo…"},
+		{"Walk the dog 🐕", 15, "…", "Walk the dog 🐕"},
+		{"Walk the dog 🐕", 14, "…", "Walk the dog…"},
+		{"日本語", 5, "…", "日本…"},
+		{"foobar", 5, "...", "fo..."},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.columns, tt.ellipsis); got != tt.want {
+			t.Errorf("%q, %d: want %q; got: %q", tt.s, tt.columns, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Package logs implements the 'logs' command of the To-do Daemon CLI.
+//
+// The 'logs' command prints the recent log messages of the To-do Daemon server,
+// which the server keeps in memory, so they can be read even if the server
+// runs detached and its output goes nowhere. With --follow, it keeps printing
+// the messages logged afterwards, like 'journalctl -f'.
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/urfave/cli/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+)
+
+// Executor is used for executing the 'logs' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewClient creates the client for connecting to the To-do Daemon
+	// server.
+	NewClient client.Factory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Level is the minimum level of the log messages to print, or empty for
+	// all messages.
+	Level string
+	// Lines is the maximum number of recent log messages to print, or 0 for
+	// all messages kept by the server.
+	Lines uint32
+	// Follow specifies whether to keep printing the messages logged
+	// afterwards until the command's context is canceled.
+	Follow bool
+}
+
+// NewExecutor creates an executor for the specified 'logs' command.
+func NewExecutor(cmd *cli.Command) (*Executor, error) {
+	level := cmd.String("level")
+	if level != "" {
+		var l slog.Level
+		if err := l.UnmarshalText([]byte(level)); err != nil {
+			return nil, exitcode.NewUsageError("invalid log level: %w", err)
+		}
+	}
+	lines := cmd.Int("lines")
+	if lines < 0 {
+		return nil, exitcode.NewUsageError("--lines must not be negative")
+	}
+	return &Executor{
+		SockFile:  cmd.String("sock"),
+		Timeout:   cmd.Duration("timeout"),
+		NewClient: client.New,
+		Stdout:    cmd.Root().Writer,
+		Level:     level,
+		Lines:     uint32(min(lines, math.MaxUint32)),
+		Follow:    cmd.Bool("follow"),
+	}, nil
+}
+
+// Execute executes the 'logs' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewClient(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	stream, err := c.TailLogs(ctx, &todopb.TailLogsRequest{
+		MinLevel: e.Level,
+		Lines:    e.Lines,
+		Follow:   e.Follow,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot tail logs: %w", err)
+	}
+	for {
+		entry, err := stream.Recv()
+		if errors.Is(err, io.EOF) || status.Code(err) == codes.Canceled {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("cannot tail logs: %w", err)
+		}
+		if _, err := fmt.Fprintln(e.Stdout, entry.GetLine()); err != nil {
+			return err
+		}
+	}
+}
+
+// NewCommand creates a new 'logs' command with the specified configuration.
+func NewCommand(_ *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "logs",
+		Usage: "Print the recent log messages of the server",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "follow",
+				Aliases: []string{"f"},
+				Usage:   "keep printing the messages logged afterwards until interrupted",
+			},
+			&cli.StringFlag{
+				Name:  "level",
+				Usage: "minimum level of the messages to print (debug, info, warn, or error)",
+			},
+			&cli.IntFlag{
+				Name:    "lines",
+				Aliases: []string{"n"},
+				Usage:   "the number of recent messages to print (0 means all messages kept by the server)",
+				Value:   10,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
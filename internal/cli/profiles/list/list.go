@@ -0,0 +1,92 @@
+// Package list implements the 'list' subcommand of the To-do Daemon CLI's
+// 'profiles' command.
+//
+// The 'list' subcommand prints the known profiles, i.e. the default profile,
+// the profiles configured in the configuration file, and the profiles that
+// have a lock file, along with whether their server is running.
+package list
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/lockfile"
+)
+
+// Executor is used for executing the 'list' command.
+type Executor struct {
+	// Config is the configuration of the current profile.
+	Config *config.Config
+	// ConfigFile is the path to the configuration file holding the sections
+	// of the profiles.
+	ConfigFile string
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+}
+
+// NewExecutor creates an executor for the specified 'list' command and
+// configuration.
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	return &Executor{
+		Config:     conf,
+		ConfigFile: config.DefaultFile(),
+		Stdout:     cmd.Root().Writer,
+	}, nil
+}
+
+// Execute executes the 'list' command.
+func (e *Executor) Execute(_ context.Context) error {
+	tw := tabwriter.NewWriter(e.Stdout, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "\tPROFILE\tSTATUS\tSOCKET"); err != nil {
+		return err
+	}
+	for _, name := range e.Config.KnownProfiles() {
+		conf, err := config.Load(e.ConfigFile, name)
+		if err != nil {
+			return err
+		}
+		current := ""
+		if name == e.Config.Profile {
+			current = "*"
+		}
+		_, err = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", current, name, serverStatus(conf.LockFile), conf.SockFile)
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// serverStatus describes whether the server holding the specified lock file
+// is running.
+func serverStatus(lockFile string) string {
+	pid, err := lockfile.ReadPID(lockFile)
+	switch {
+	case err != nil:
+		return "unknown"
+	case pid == 0 || !lockfile.IsRunning(pid):
+		return "stopped"
+	default:
+		return fmt.Sprintf("running (PID %d)", pid)
+	}
+}
+
+// NewCommand creates a new 'list' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "List the profiles and whether their server is running",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd, conf)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
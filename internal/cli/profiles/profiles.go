@@ -0,0 +1,34 @@
+// Package profiles implements the 'profiles' command of the To-do Daemon CLI.
+//
+// The 'profiles' command provides subcommands for inspecting the profiles,
+// which allow running separate To-do Daemon servers side by side, e.g. one for
+// work and one for personal tasks.
+package profiles
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/profiles/list"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// NewCommand creates a new 'profiles' command with the specified
+// configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "profiles",
+		Usage: "Inspect the profiles of the To-do Daemon",
+		Commands: []*cli.Command{
+			list.NewCommand(conf),
+		},
+		CommandNotFound: func(_ context.Context, _ *cli.Command, name string) {
+			// revive:disable-next-line:unhandled-error
+			fmt.Fprintf(os.Stderr, "todo-daemon: %s: '%s'\n", i18n.Translate("invalid command"), name)
+		},
+	}
+}
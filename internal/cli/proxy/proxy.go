@@ -0,0 +1,189 @@
+// Package proxy implements the 'proxy' command of the To-do Daemon CLI.
+//
+// The 'proxy' command forwards connections to the socket of a To-do Daemon
+// server. On the host of the server, 'proxy --listen localhost:9000' accepts
+// TCP connections on a loopback port, which can be forwarded with 'ssh -L',
+// and 'proxy --stdio' forwards its standard input and output, so that SSH can
+// run it as a remote command. On the host of the client, 'proxy --listen
+// <socket> --remote ssh://host' or '--remote tcp://localhost:9000' creates a
+// socket that the CLI can connect to with --sock.
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/proxy"
+	"github.com/mwopitz/todo-daemon/internal/transport"
+)
+
+// Executor is used for executing the 'proxy' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe of the local
+	// To-do Daemon server, which is the target unless Remote is set.
+	SockFile string
+	// Listen is the address to accept connections on: a loopback TCP address
+	// like "localhost:9000" or the address of a Unix socket or named pipe.
+	// It is ignored if Stdio is set.
+	Listen string
+	// Remote is the URL of a remote To-do Daemon server to forward the
+	// connections to, see [proxy.RemoteDialer].
+	Remote string
+	// RemoteCommand is the command that runs the To-do Daemon CLI on the
+	// host of a remote server reached through SSH.
+	RemoteCommand string
+	// Stdio specifies whether to forward standard input and output instead of
+	// accepting connections.
+	Stdio bool
+	// Stdin is the reader that is forwarded to the target if Stdio is set.
+	Stdin io.Reader
+	// Stdout is the writer that the target's data is forwarded to if Stdio is
+	// set.
+	Stdout io.Writer
+	// Stderr is the writer that the SSH client prints its messages to.
+	Stderr io.Writer
+}
+
+// NewExecutor creates an executor for the specified 'proxy' command.
+func NewExecutor(cmd *cli.Command) (*Executor, error) {
+	listen, stdio, remote := cmd.String("listen"), cmd.Bool("stdio"), cmd.String("remote")
+	switch {
+	case listen == "" && !stdio:
+		return nil, exitcode.NewUsageError("no address specified, use --listen or --stdio")
+	case listen != "" && stdio:
+		return nil, exitcode.NewUsageError("--listen cannot be used with --stdio")
+	case listen == cmd.String("sock") && remote == "":
+		return nil, exitcode.NewUsageError("cannot listen on the socket of the server itself")
+	}
+	return &Executor{
+		SockFile:      cmd.String("sock"),
+		Listen:        listen,
+		Remote:        remote,
+		RemoteCommand: cmd.String("remote-command"),
+		Stdio:         stdio,
+		Stdin:         cmd.Root().Reader,
+		Stdout:        cmd.Root().Writer,
+		Stderr:        cmd.Root().ErrWriter,
+	}, nil
+}
+
+// Execute executes the 'proxy' command. It runs until the context is
+// canceled or, with --stdio, until either side closes the connection.
+func (e *Executor) Execute(ctx context.Context) error {
+	dial, target, err := e.dialer()
+	if err != nil {
+		return err
+	}
+	if e.Stdio {
+		conn, err := dial(ctx)
+		if err != nil {
+			return fmt.Errorf("cannot connect to %s: %w", target, err)
+		}
+		return proxy.Pipe(ctx, stdio{Reader: e.Stdin, Writer: e.Stdout}, conn)
+	}
+
+	ln, err := e.listen()
+	if err != nil {
+		return err
+	}
+	slog.Info("forwarding connections", "listen", ln.Addr().String(), "target", target)
+	return proxy.Serve(ctx, ln, dial)
+}
+
+// dialer returns the dialer for the target of the proxy and a description of
+// the target for messages.
+func (e *Executor) dialer() (proxy.Dialer, string, error) {
+	if e.Remote != "" {
+		dial, err := proxy.RemoteDialer(e.Remote, e.RemoteCommand, e.Stderr)
+		if err != nil {
+			return nil, "", exitcode.NewUsageError("%w", err)
+		}
+		return dial, e.Remote, nil
+	}
+	addr, err := transport.ParseAddress(e.SockFile)
+	if err != nil {
+		return nil, "", exitcode.NewUsageError("%w", err)
+	}
+	return func(ctx context.Context) (io.ReadWriteCloser, error) {
+		return transport.Dial(ctx, addr)
+	}, addr.String(), nil
+}
+
+// listen creates the listener for the connections to forward.
+func (e *Executor) listen() (net.Listener, error) {
+	if proxy.IsTCPAddress(e.Listen) {
+		ln, err := proxy.ListenTCP(e.Listen)
+		if errors.Is(err, proxy.ErrNotLoopback) {
+			return nil, exitcode.NewUsageError("%w", err)
+		}
+		return ln, err
+	}
+	addr, err := transport.ParseAddress(e.Listen)
+	if err != nil {
+		return nil, exitcode.NewUsageError("%w", err)
+	}
+	return transport.Listen(addr)
+}
+
+// stdio is the connection made up of standard input and output.
+type stdio struct {
+	io.Reader
+	io.Writer
+}
+
+// Close closes standard input and output, if they can be closed.
+func (s stdio) Close() error {
+	var errs []error
+	if c, ok := s.Reader.(io.Closer); ok {
+		errs = append(errs, c.Close())
+	}
+	if c, ok := s.Writer.(io.Closer); ok {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// NewCommand creates a new 'proxy' command with the specified configuration.
+func NewCommand(_ *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "proxy",
+		Usage: "Forward connections to a To-do Daemon server, e.g. to reach a remote server through SSH",
+		UsageText: "todo-daemon proxy --listen localhost:9000\n" +
+			"todo-daemon proxy --listen ~/.todo-remote.sock --remote ssh://user@host\n" +
+			"todo-daemon proxy --stdio",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "listen",
+				Usage: "the address to accept connections on: a loopback TCP address like localhost:9000, or a socket",
+			},
+			&cli.BoolFlag{
+				Name:  "stdio",
+				Usage: "forward standard input and output instead of accepting connections, e.g. for SSH",
+			},
+			&cli.StringFlag{
+				Name:  "remote",
+				Usage: "forward to a remote server instead of the local one: ssh://[user@]host[:port] or tcp://host:port",
+			},
+			&cli.StringFlag{
+				Name:  "remote-command",
+				Usage: "the command that runs todo-daemon on the remote host",
+				Value: proxy.DefaultRemoteCommand,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
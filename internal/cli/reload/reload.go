@@ -0,0 +1,99 @@
+// Package reload implements the 'reload' command of the To-do Daemon CLI.
+//
+// The 'reload' command makes the To-do Daemon server reload its configuration
+// file and prints which of the changed settings have been applied and which
+// require a restart of the server.
+package reload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// Executor is used for executing the 'reload' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewClient creates the client for connecting to the To-do Daemon
+	// server.
+	NewClient client.Factory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+}
+
+// NewExecutor creates an executor for the specified 'reload' command.
+func NewExecutor(cmd *cli.Command) (*Executor, error) {
+	return &Executor{
+		SockFile:  cmd.String("sock"),
+		Timeout:   cmd.Duration("timeout"),
+		NewClient: client.New,
+		Stdout:    cmd.Root().Writer,
+		Quiet:     cmd.Bool("quiet"),
+	}, nil
+}
+
+// Execute executes the 'reload' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewClient(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	resp, err := c.ReloadConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot reload configuration: %w", err)
+	}
+	if e.Quiet {
+		return nil
+	}
+	applied, restart := resp.GetApplied(), resp.GetRequiresRestart()
+	if len(applied) == 0 && len(restart) == 0 {
+		// revive:disable-next-line:unhandled-error
+		fmt.Fprintln(e.Stdout, i18n.Translate("Configuration unchanged"))
+		return nil
+	}
+	if len(applied) > 0 {
+		// revive:disable-next-line:unhandled-error
+		fmt.Fprintln(e.Stdout, i18n.Sprintf("Applied: %s", strings.Join(applied, ", ")))
+	}
+	if len(restart) > 0 {
+		// revive:disable-next-line:unhandled-error
+		fmt.Fprintln(e.Stdout, i18n.Sprintf("Requires restart: %s", strings.Join(restart, ", ")))
+	}
+	return nil
+}
+
+// NewCommand creates a new 'reload' command with the specified configuration.
+func NewCommand(_ *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "reload",
+		Usage: "Make the To-do Daemon server reload its configuration file",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
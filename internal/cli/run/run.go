@@ -8,95 +8,713 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
-	"github.com/gofrs/flock"
 	"github.com/urfave/cli/v3"
 
+	"github.com/mwopitz/todo-daemon/internal/backup"
+	"github.com/mwopitz/todo-daemon/internal/compress"
 	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/cors"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/forwarded"
+	"github.com/mwopitz/todo-daemon/internal/hardening"
+	"github.com/mwopitz/todo-daemon/internal/hook"
+	"github.com/mwopitz/todo-daemon/internal/lockfile"
+	"github.com/mwopitz/todo-daemon/internal/logging"
+	"github.com/mwopitz/todo-daemon/internal/ratelimit"
 	"github.com/mwopitz/todo-daemon/internal/server"
+	"github.com/mwopitz/todo-daemon/internal/storage"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+	"github.com/mwopitz/todo-daemon/internal/transport"
+	"github.com/mwopitz/todo-daemon/internal/webhook"
 )
 
 // ErrAlreadyRunning is returned by [Executor.Execute] when the server is
 // already running.
 var ErrAlreadyRunning = errors.New("another instance is already running")
 
+// lockGracePeriod is the maximum amount of time to wait for a lock file that
+// is held, although the server recorded in it is no longer running. The lock
+// is then usually held by the server's process in the middle of exiting.
+const lockGracePeriod = 2 * time.Second
+
+// logBufferSize is the number of recent log messages that the server keeps for
+// clients tailing its log, see the 'logs' command.
+const logBufferSize = 1000
+
 // Executor is used for executing the 'run' command.
 type Executor struct {
 	// Lock is the file lock that the executor tries to acquire before starting
 	// the server.
-	Lock *flock.Flock
-	// SockFile is the path to the Unix socket file that the server is supposed
-	// to be listening on.
-	SockFile string
+	Lock *lockfile.Lock
+	// Address is the address of the Unix socket or named pipe that the server
+	// is supposed to be listening on.
+	Address transport.Address
+	// SocketOptions configure the Unix socket that the server listens on,
+	// e.g. its mode and group.
+	SocketOptions []transport.ListenOption
+	// Database is the data source name of the storage backend that keeps the
+	// tasks, see package storage.
+	Database string
+	// LogLevel is the minimum level of the log messages to print, see the
+	// global --log-level flag.
+	LogLevel slog.Level
+	// LogFile is the path of the file that the log messages are appended to,
+	// in addition to stderr. If empty, they are only printed to stderr.
+	LogFile string
+	// HTTPAddress is the address that the server's HTTP server is supposed to
+	// be listening on.
+	HTTPAddress server.HTTPListenAddress
+	// ExternalURL is the URL that clients use to reach the server's HTTP
+	// server, e.g. behind a reverse proxy, or nil if clients reach it
+	// directly.
+	ExternalURL *url.URL
+	// TrustedProxies are the reverse proxies whose X-Forwarded-* headers
+	// are honored.
+	TrustedProxies *forwarded.Proxies
+	// ShutdownTimeout is the maximum amount of time to wait for active
+	// requests to finish when stopping the server.
+	ShutdownTimeout time.Duration
+	// Webhooks are the webhooks that the server notifies about task events.
+	Webhooks []config.Webhook
+	// Filters are the named filters that tasks can be listed by, in
+	// addition to the filters saved via the API.
+	Filters []config.Filter
+	// FiltersFile is the path of the file holding the filters saved via the
+	// API.
+	FiltersFile string
+	// TemplatesFile is the path of the file holding the task templates saved
+	// via the API.
+	TemplatesFile string
+	// RateLimit limits the requests to the server's REST API.
+	RateLimit config.RateLimit
+	// CORS specifies the cross-origin requests allowed by the server's REST
+	// API.
+	CORS *cors.Policy
+	// Compression configures the compression of the server's HTTP responses.
+	Compression config.Compression
+	// Hardening configures the request limits and security headers of the
+	// server's HTTP server.
+	Hardening config.Hardening
+	// Quotas configures the soft limits of the to-do list.
+	Quotas config.Quotas
+	// Hooks configures the hook scripts executed on task events.
+	Hooks config.Hooks
+	// Backup configures the scheduled snapshots of the tasks.
+	Backup config.Backup
+	// MaxRequestDuration is the maximum amount of time the server spends on a
+	// single request.
+	MaxRequestDuration time.Duration
+	// ReadOnly specifies whether the server rejects all requests that would
+	// modify data.
+	ReadOnly bool
+	// StrictDependencies specifies whether the server rejects completing a
+	// task that depends on tasks that are still open.
+	StrictDependencies bool
+	// UniqueSummaries specifies whether the server rejects creating a task
+	// whose summary equals the summary of an open task.
+	UniqueSummaries bool
+	// IdempotencyWindow is how long the server remembers the tasks created by
+	// requests with an idempotency key. Zero disables idempotency keys.
+	IdempotencyWindow time.Duration
+	// WatchBufferSize is the number of events buffered for each client
+	// watching the tasks.
+	WatchBufferSize int
+	// WatchOverflow specifies what happens when a client watching the tasks
+	// falls further behind.
+	WatchOverflow todo.OverflowPolicy
+	// MaxRecvMsgSize and MaxSendMsgSize are the maximum sizes of the gRPC
+	// messages that the server receives and sends, in bytes.
+	MaxRecvMsgSize int
+	MaxSendMsgSize int
+	// Location is the default time zone of the tasks, see the global
+	// --time-zone flag.
+	Location *time.Location
+	// WebUI specifies whether the server serves the web UI.
+	WebUI bool
+	// DemoData specifies whether the server adds some demo tasks to an empty
+	// to-do list.
+	DemoData bool
+	// Debug enables features for debugging the server, like gRPC server
+	// reflection.
+	Debug bool
+	// ConfigFile is the path to the configuration file that is reloaded on
+	// SIGHUP or when requested by a client.
+	ConfigFile string
+	// Takeover specifies whether to take over the listeners and tasks of a
+	// running server instead of failing with [ErrAlreadyRunning].
+	Takeover bool
+
+	// mu guards the fields below, which are used for reloading the
+	// configuration.
+	mu sync.Mutex
+	// conf is the configuration that is currently in effect.
+	conf     *config.Config
+	webhooks *webhook.Registry
+	filters  *todo.FilterRegistry
+	hooks    *hook.Runner
 }
 
-// NewExecutor creates an executor for the specified 'run' command.
-func NewExecutor(cmd *cli.Command) (*Executor, error) {
+// NewExecutor creates an executor for the specified 'run' command and
+// configuration.
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	if _, err := storage.DriverName(cmd.String("db")); err != nil {
+		return nil, exitcode.NewUsageError("unsupported database: %w", err)
+	}
+	for _, name := range conf.Hooks.Allow {
+		if !hook.IsValidName(name) {
+			return nil, fmt.Errorf("invalid hook name: '%s'", name)
+		}
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cmd.String("log-level"))); err != nil {
+		return nil, exitcode.NewUsageError("invalid log level: %w", err)
+	}
+	addr, err := transport.ParseAddress(cmd.String("sock"))
+	if err != nil {
+		return nil, exitcode.NewUsageError("%w", err)
+	}
+	socketOpts, err := socketOptions(cmd.String("socket-mode"), cmd.String("socket-group"))
+	if err != nil {
+		return nil, exitcode.NewUsageError("%w", err)
+	}
+	httpAddr, err := server.ParseHTTPListenAddress(cmd.String("http-listen"))
+	if err != nil {
+		return nil, exitcode.NewUsageError("%w", err)
+	}
+	externalURL, err := parseExternalURL(cmd.String("external-url"))
+	if err != nil {
+		return nil, exitcode.NewUsageError("%w", err)
+	}
+	trustedProxies, err := forwarded.ParseProxies(cmd.StringSlice("trusted-proxy"))
+	if err != nil {
+		return nil, exitcode.NewUsageError("%w", err)
+	}
+	if cmd.Int("watch-buffer-size") < 1 {
+		return nil, exitcode.NewUsageError("invalid watch buffer size: %d", cmd.Int("watch-buffer-size"))
+	}
+	overflow, err := todo.ParseOverflowPolicy(cmd.String("watch-overflow"))
+	if err != nil {
+		return nil, exitcode.NewUsageError("%w", err)
+	}
+	for _, name := range []string{"max-recv-msg-size", "max-send-msg-size"} {
+		if cmd.Int(name) < 1 {
+			return nil, exitcode.NewUsageError("invalid %s: %d", name, cmd.Int(name))
+		}
+	}
+	// The global --time-zone flag has been stored in the configuration.
+	loc, err := conf.Location()
+	if err != nil {
+		return nil, exitcode.NewUsageError("%w", err)
+	}
+	corsPolicy := &cors.Policy{
+		AllowedOrigins: cmd.StringSlice("cors-origin"),
+		AllowedMethods: cmd.StringSlice("cors-method"),
+		AllowedHeaders: cmd.StringSlice("cors-header"),
+		MaxAge:         time.Duration(conf.CORS.MaxAge),
+	}
+	if err := corsPolicy.Validate(); err != nil {
+		return nil, exitcode.NewUsageError("%w", err)
+	}
 	return &Executor{
-		Lock:     flock.New(cmd.String("lock")),
-		SockFile: cmd.String("sock"),
+		Lock:               lockfile.New(cmd.String("lock")),
+		Address:            addr,
+		SocketOptions:      socketOpts,
+		Database:           cmd.String("db"),
+		LogLevel:           level,
+		LogFile:            cmd.String("log-file"),
+		HTTPAddress:        httpAddr,
+		ExternalURL:        externalURL,
+		TrustedProxies:     trustedProxies,
+		ShutdownTimeout:    cmd.Duration("shutdown-timeout"),
+		Webhooks:           conf.Webhooks,
+		Filters:            conf.Filters,
+		FiltersFile:        conf.FiltersFile(),
+		TemplatesFile:      conf.TemplatesFile(),
+		RateLimit:          conf.RateLimit,
+		CORS:               corsPolicy,
+		Compression:        conf.Compression,
+		Hardening:          conf.Hardening,
+		Quotas:             conf.Quotas,
+		Hooks:              conf.Hooks,
+		Backup:             conf.Backup,
+		ReadOnly:           cmd.Bool("read-only"),
+		StrictDependencies: cmd.Bool("strict-dependencies"),
+		UniqueSummaries:    cmd.Bool("unique-summaries"),
+		IdempotencyWindow:  cmd.Duration("idempotency-window"),
+		WatchBufferSize:    cmd.Int("watch-buffer-size"),
+		WatchOverflow:      overflow,
+		MaxRecvMsgSize:     cmd.Int("max-recv-msg-size"),
+		MaxSendMsgSize:     cmd.Int("max-send-msg-size"),
+		Location:           loc,
+		WebUI:              cmd.Bool("web-ui"),
+		DemoData:           cmd.Bool("demo-data"),
+		MaxRequestDuration: cmd.Duration("max-request-duration"),
+		Debug:              cmd.Bool("debug"),
+		ConfigFile:         config.DefaultFile(),
+		Takeover:           cmd.Bool("takeover"),
+		conf:               conf,
 	}, nil
 }
 
 // Execute executes the 'run' command.
 func (e *Executor) Execute(ctx context.Context) error {
-	unlock, err := e.lock()
+	logs, closeLog, err := e.initLogging()
 	if err != nil {
 		return fmt.Errorf("cannot start server: %w", err)
 	}
-	defer unlock()
-	slog.Info("acquired file lock", "path", e.Lock.Path())
+	defer closeLog()
 
-	if err := os.MkdirAll(filepath.Dir(e.SockFile), 0o700); err != nil {
+	var took *takeover
+	var unlock func()
+	if e.Takeover {
+		unlock, took, err = e.lockOrTakeOver(ctx)
+	} else {
+		unlock, err = e.lock()
+	}
+	if err != nil {
 		return fmt.Errorf("cannot start server: %w", err)
 	}
+	defer unlock()
+
+	// The sockets handed over by the running server are still in use.
+	if took == nil && e.Address.Scheme == transport.SchemeUnix {
+		if err := removeStaleSocket(ctx, e.Address); err != nil {
+			return fmt.Errorf("cannot start server: %w", err)
+		}
+	}
+	if took == nil && e.HTTPAddress.Network == "unix" {
+		addr := transport.Address{Scheme: transport.SchemeUnix, Path: e.HTTPAddress.Address}
+		if err := removeStaleSocket(ctx, addr); err != nil {
+			return fmt.Errorf("cannot start server: %w", err)
+		}
+	}
 
-	if err := os.Remove(e.SockFile); err != nil && !os.IsNotExist(err) {
+	// A server that is taken over closes its storage before it sends the
+	// snapshot, so the storage is only opened by one process at a time.
+	store, err := storage.Open(ctx, e.Database)
+	if err != nil {
 		return fmt.Errorf("cannot start server: %w", err)
 	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			logger().Warn("cannot close storage", "cause", err)
+		}
+	}()
+	backend, _ := storage.DriverName(e.Database)
 
 	// Create the To-do Daemon server and run it in a separate goroutine, so we
 	// can wait until either the server stops or the context gets canceled.
-	srv := server.New()
+	e.webhooks = webhook.NewRegistry()
+	if err := e.webhooks.SetConfigured(webhook.NewSpecs(e.Webhooks)); err != nil {
+		return fmt.Errorf("cannot start server: %w", err)
+	}
+	filters, err := todo.NewFilters(e.Filters)
+	if err != nil {
+		return fmt.Errorf("cannot start server: %w", err)
+	}
+	if e.filters, err = todo.NewFilterRegistry(e.FiltersFile); err != nil {
+		return fmt.Errorf("cannot start server: %w", err)
+	}
+	if err := e.filters.SetConfigured(filters); err != nil {
+		return fmt.Errorf("cannot start server: %w", err)
+	}
+	templates, err := todo.NewTemplateRegistry(e.TemplatesFile)
+	if err != nil {
+		return fmt.Errorf("cannot start server: %w", err)
+	}
+	// The hook runner is always started, so hook scripts can be allowed by
+	// reloading the configuration.
+	e.hooks = &hook.Runner{
+		Dir:           e.Hooks.Dir,
+		Allow:         e.Hooks.Allow,
+		Timeout:       time.Duration(e.Hooks.Timeout),
+		MaxConcurrent: e.Hooks.MaxConcurrent,
+	}
+	if len(e.Hooks.Allow) > 0 {
+		logger().Info("enabling hook scripts", "dir", e.Hooks.Dir, "allow", e.Hooks.Allow)
+	}
+	opts := []server.Option{
+		server.WithStorage(backend, store),
+		server.WithWebhooks(e.webhooks),
+		server.WithFilters(e.filters),
+		server.WithTemplates(templates),
+		server.WithHooks(e.hooks),
+		server.WithLogs(logs),
+		server.WithMaxRequestDuration(e.MaxRequestDuration),
+		server.WithSocketOptions(e.SocketOptions...),
+		server.WithHTTPListenAddress(e.HTTPAddress),
+		server.WithExternalURL(e.ExternalURL),
+		server.WithTrustedProxies(e.TrustedProxies),
+		server.WithCORS(e.CORS),
+		server.WithHardening(&hardening.Policy{
+			MaxBodySize:     e.Hardening.MaxBodySize,
+			SecurityHeaders: e.Hardening.SecurityHeaders,
+		}),
+		server.WithRateLimit(ratelimit.New(
+			ratelimit.Limit(e.RateLimit.Global),
+			ratelimit.Limit(e.RateLimit.PerIP),
+		)),
+		server.WithConfigReloader(todo.ConfigReloaderFunc(func(_ context.Context) (*todo.ConfigReload, error) {
+			return e.reload()
+		})),
+	}
+	if e.Compression.Enabled {
+		opts = append(opts, server.WithCompression(compress.New(e.Compression.MinSize)))
+	}
+	if e.Backup.Interval > 0 {
+		logger().Info("enabling scheduled backups", "dir", e.Backup.Dir, "interval", time.Duration(e.Backup.Interval))
+		scheduler := &backup.Scheduler{
+			Dir:      e.Backup.Dir,
+			Interval: time.Duration(e.Backup.Interval),
+			Retain:   e.Backup.Retain,
+		}
+		if e.Backup.Remote.URL != "" {
+			if scheduler.Remote, err = backup.NewRemote(e.Backup.Remote); err != nil {
+				return fmt.Errorf("cannot start server: %w", err)
+			}
+			logger().Info("enabling remote backups", "url", e.Backup.Remote.URL)
+		}
+		opts = append(opts, server.WithScheduledBackups(scheduler))
+	}
+	if e.ReadOnly {
+		logger().Info("enabling read-only mode")
+		opts = append(opts, server.WithReadOnly())
+	}
+	if e.StrictDependencies {
+		opts = append(opts, server.WithStrictDependencies())
+	}
+	if e.UniqueSummaries {
+		opts = append(opts, server.WithUniqueSummaries())
+	}
+	if quotas := todo.Quotas(e.Quotas); quotas.Enabled() {
+		logger().Info("enabling quotas", "max_open_tasks", quotas.MaxOpenTasks,
+			"max_tags_per_task", quotas.MaxTagsPerTask)
+		opts = append(opts, server.WithQuotas(quotas))
+	}
+	if e.IdempotencyWindow > 0 {
+		opts = append(opts, server.WithIdempotencyWindow(e.IdempotencyWindow))
+	}
+	opts = append(opts, server.WithWatchBuffer(e.WatchBufferSize, e.WatchOverflow),
+		server.WithMaxMessageSize(e.MaxRecvMsgSize, e.MaxSendMsgSize))
+	if e.Location != nil {
+		opts = append(opts, server.WithTimeZone(e.Location))
+	}
+	if e.WebUI {
+		opts = append(opts, server.WithWebUI())
+	}
+	if e.DemoData {
+		opts = append(opts, server.WithDemoData())
+	}
+	if e.Debug {
+		logger().Info("enabling gRPC server reflection")
+		opts = append(opts, server.WithReflection())
+	}
+	if took != nil {
+		opts = append(opts, server.WithListeners(took.listeners), server.WithSnapshot(took.snapshot))
+	}
+	srv := server.New(opts...)
 	done := make(chan error, 1)
 	go func() {
-		done <- srv.Serve("unix", e.SockFile)
+		done <- srv.Serve(e.Address)
 		close(done)
 	}()
 
-	select {
-	case <-ctx.Done():
-		err := ctx.Err()
-		if errors.Is(err, context.Canceled) {
-			err = context.Cause(ctx)
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			err := ctx.Err()
+			if errors.Is(err, context.Canceled) {
+				err = context.Cause(ctx)
+			}
+			logger().Info("stopping server...", "cause", err)
+			return srv.StopGracefully(e.ShutdownTimeout)
+		case err := <-done:
+			return err
+		case <-srv.HandedOver():
+			logger().Info("stopping server after handing over to new instance...")
+			return srv.StopGracefully(e.ShutdownTimeout)
+		case <-hup:
+			if _, err := e.reload(); err != nil {
+				logger().Error("cannot reload configuration", "cause", err)
+			}
 		}
-		slog.Info("stopping server...", "cause", err)
-		return srv.StopGracefully()
-	case err := <-done:
-		return err
 	}
 }
 
-func (e *Executor) lock() (func(), error) {
-	err := os.MkdirAll(filepath.Dir(e.Lock.Path()), 0o700)
+// initLogging makes the default logger print the log messages to stderr and
+// append them to the log file, if any, and keep the most recent ones in a
+// buffer, so clients can tail them via the server. The returned function
+// closes the log file.
+func (e *Executor) initLogging() (*logging.Buffer, func(), error) {
+	logs := logging.NewBuffer(logBufferSize)
+	if e.LogFile == "" {
+		logging.Init(os.Stderr, e.LogLevel, logs)
+		return logs, func() {}, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(e.LogFile), 0o700); err != nil {
+		return nil, nil, fmt.Errorf("cannot create log file directory: %w", err)
+	}
+	f, err := os.OpenFile(e.LogFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot open log file: %w", err)
+	}
+	logging.Init(io.MultiWriter(os.Stderr, f), e.LogLevel, logs)
+	return logs, func() {
+		// Log messages written after closing the file, if any, only go to
+		// stderr.
+		logging.Init(os.Stderr, e.LogLevel, logs)
+		if err := f.Close(); err != nil {
+			logger().Warn("cannot close log file", "path", e.LogFile, "cause", err)
+		}
+	}, nil
+}
+
+// reload reloads the configuration file and applies the changed settings that
+// don't require a restart. If the configuration file is invalid, no settings
+// are changed.
+func (e *Executor) reload() (*todo.ConfigReload, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	conf, err := config.Load(e.ConfigFile, e.conf.Profile)
 	if err != nil {
 		return nil, err
 	}
-	locked, err := e.Lock.TryLock()
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(conf.LogLevel)); err != nil {
+		return nil, fmt.Errorf("invalid log level: %w", err)
+	}
+	for _, name := range conf.Hooks.Allow {
+		if !hook.IsValidName(name) {
+			return nil, fmt.Errorf("invalid hook name: '%s'", name)
+		}
+	}
+	filters, err := todo.NewFilters(conf.Filters)
+	if err != nil {
+		return nil, err
+	}
+	if err := todo.ValidateFilters(filters); err != nil {
+		return nil, err
+	}
+
+	reload := &todo.ConfigReload{}
+	for _, name := range config.Diff(e.conf, conf) {
+		if config.IsReloadable(name) {
+			reload.Applied = append(reload.Applied, name)
+		} else {
+			reload.RequiresRestart = append(reload.RequiresRestart, name)
+		}
+	}
+	// Only the settings that have changed are applied, so the values of
+	// command-line flags stay in effect until the file changes them. The
+	// webhooks go first, because they are the only settings that can still
+	// turn out to be invalid.
+	if slices.Contains(reload.Applied, "webhooks") {
+		if err := e.webhooks.SetConfigured(webhook.NewSpecs(conf.Webhooks)); err != nil {
+			return nil, err
+		}
+		e.conf.Webhooks = conf.Webhooks
+	}
+	if slices.Contains(reload.Applied, "filters") {
+		if err := e.filters.SetConfigured(filters); err != nil {
+			return nil, err
+		}
+		e.conf.Filters = conf.Filters
+	}
+	if slices.Contains(reload.Applied, "log_level") {
+		logging.SetLevel(level)
+		e.conf.LogLevel = conf.LogLevel
+	}
+	if slices.ContainsFunc(reload.Applied, isHookSetting) {
+		e.hooks.Reconfigure(conf.Hooks.Dir, conf.Hooks.Allow, time.Duration(conf.Hooks.Timeout))
+		e.conf.Hooks.Dir = conf.Hooks.Dir
+		e.conf.Hooks.Allow = conf.Hooks.Allow
+		e.conf.Hooks.Timeout = conf.Hooks.Timeout
+	}
+	logger().Info("reloaded configuration", "path", e.ConfigFile,
+		"applied", reload.Applied, "requires_restart", reload.RequiresRestart)
+	return reload, nil
+}
+
+func isHookSetting(name string) bool {
+	return strings.HasPrefix(name, "hooks.")
+}
+
+// lock acquires the lock file. If the lock is held, it finds out whether the
+// server recorded in the lock file is still running, so it can tell the user.
+func (e *Executor) lock() (func(), error) {
+	locked, prevPID, err := e.Lock.TryLock()
 	if err != nil {
 		return nil, err
 	}
 	if !locked {
-		return nil, ErrAlreadyRunning
+		pid, err := lockfile.ReadPID(e.Lock.Path())
+		if err != nil {
+			logger().Warn("cannot read PID from lock file", "cause", err)
+		}
+		if lockfile.IsRunning(pid) {
+			return nil, fmt.Errorf("%w (PID %d)", ErrAlreadyRunning, pid)
+		}
+		logger().Info("waiting for lock file held by a process that is not the recorded server",
+			"path", e.Lock.Path(), "pid", pid)
+		if locked, prevPID, err = e.waitForLock(); err != nil {
+			return nil, err
+		}
+		if !locked {
+			return nil, fmt.Errorf("%w: lock file %s is still held by another process", ErrAlreadyRunning, e.Lock.Path())
+		}
+	}
+	return e.unlocker(prevPID), nil
+}
+
+// unlocker returns the function that releases the acquired lock file. The
+// specified PID is the one recorded by the previous holder of the lock.
+func (e *Executor) unlocker(prevPID int) func() {
+	logger().Info("acquired file lock", "path", e.Lock.Path())
+	if prevPID != 0 && prevPID != os.Getpid() {
+		logger().Warn("recovered lock file of a server that did not shut down cleanly",
+			"path", e.Lock.Path(), "pid", prevPID)
 	}
 	return func() {
 		if err := e.Lock.Unlock(); err != nil {
-			slog.Warn("cannot release file lock", "cause", err)
+			logger().Warn("cannot release file lock", "cause", err)
 		}
-	}, nil
+	}
+}
+
+// lockOrTakeOver acquires the lock file if no server is running. Otherwise, it
+// takes over from the running server, and acquires the lock file as soon as
+// that server has finished its active requests and exited.
+func (e *Executor) lockOrTakeOver(ctx context.Context) (func(), *takeover, error) {
+	locked, prevPID, err := e.Lock.TryLock()
+	if err != nil {
+		return nil, nil, err
+	}
+	if locked {
+		logger().Info("no running server to take over, starting normally")
+		return e.unlocker(prevPID), nil, nil
+	}
+	took, err := e.takeOver(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lockCtx, cancel := context.WithCancel(context.Background())
+	acquired := make(chan func(), 1)
+	go func() {
+		defer close(acquired)
+		prevPID, err := e.Lock.LockContext(lockCtx)
+		if err != nil {
+			if lockCtx.Err() == nil {
+				logger().Warn("cannot acquire file lock", "path", e.Lock.Path(), "cause", err)
+			}
+			return
+		}
+		acquired <- e.unlocker(prevPID)
+	}()
+	return func() {
+		cancel()
+		if unlock, ok := <-acquired; ok {
+			unlock()
+		}
+	}, took, nil
+}
+
+// waitForLock retries acquiring the lock file for up to [lockGracePeriod].
+func (e *Executor) waitForLock() (locked bool, prevPID int, err error) {
+	deadline := time.Now().Add(lockGracePeriod)
+	for time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+		if locked, prevPID, err = e.Lock.TryLock(); err != nil || locked {
+			return locked, prevPID, err
+		}
+	}
+	return false, 0, nil
+}
+
+// removeStaleSocket removes the Unix socket file left behind by a previous
+// server, so the server can listen on it again. Since the lock file is held,
+// nothing should be listening on the socket; if something is, e.g. a server
+// that uses another lock file, the socket is left alone.
+func removeStaleSocket(ctx context.Context, addr transport.Address) error {
+	path := addr.Path
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if conn, err := transport.Dial(ctx, addr); err == nil {
+		if err := conn.Close(); err != nil {
+			logger().Warn("cannot close probe connection", "cause", err)
+		}
+		return fmt.Errorf("socket %s is in use by another process", path)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	logger().Info("removed stale socket file", "path", path)
+	return nil
+}
+
+// socketOptions returns the options of the server's Unix socket with the
+// specified octal mode and group. If a group but no mode is specified, the
+// members of the group can connect to the server, too.
+func socketOptions(mode, group string) ([]transport.ListenOption, error) {
+	var opts []transport.ListenOption
+	if group != "" {
+		gid, err := transport.LookupGroup(group)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, transport.WithSocketGroup(gid), transport.WithSocketMode(0o660))
+	}
+	if mode != "" {
+		m, err := transport.ParseSocketMode(mode)
+		if err != nil {
+			return nil, err
+		}
+		if m&0o007 != 0 {
+			logger().Warn("the socket mode allows all users to connect to the server", "mode", mode)
+		}
+		opts = append(opts, transport.WithSocketMode(m))
+	}
+	return opts, nil
+}
+
+// parseExternalURL parses the external URL of the HTTP server, which must be an
+// absolute HTTP or HTTPS URL without query. An empty string yields nil.
+func parseExternalURL(s string) (*url.URL, error) {
+	if s == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid external URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("invalid external URL '%s': want http(s)://host[:port][/path]", s)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+	return u, nil
 }
 
 // NewCommand creates a new 'run' command with the specified configuration.
@@ -109,11 +727,134 @@ func NewCommand(conf *config.Config) *cli.Command {
 				Name:      "lock",
 				Usage:     "path to the lock file",
 				Value:     conf.LockFile,
+				Sources:   cli.EnvVars(config.EnvLockFile),
 				TakesFile: true,
 			},
+			&cli.StringFlag{
+				Name:    "db",
+				Usage:   "the data source name of the database for storing the tasks, e.g. memory or eventlog:<path>",
+				Value:   conf.Database,
+				Sources: cli.EnvVars(config.EnvDatabase),
+			},
+			&cli.StringFlag{
+				Name:      "log-file",
+				Usage:     "path to a file to append the log messages to, in addition to stderr",
+				Value:     conf.LogFile,
+				TakesFile: true,
+			},
+			&cli.DurationFlag{
+				Name:    "shutdown-timeout",
+				Usage:   "maximum time to wait for active requests when stopping the server",
+				Value:   time.Duration(conf.ShutdownTimeout),
+				Sources: cli.EnvVars(config.EnvShutdownTimeout),
+			},
+			&cli.DurationFlag{
+				Name:  "max-request-duration",
+				Usage: "maximum time to spend on a single request (0 means no limit)",
+				Value: time.Duration(conf.MaxRequestDuration),
+			},
+			&cli.BoolFlag{
+				Name:    "read-only",
+				Usage:   "reject all requests that would modify the to-do list",
+				Value:   conf.ReadOnly,
+				Sources: cli.EnvVars(config.EnvReadOnly),
+			},
+			&cli.BoolFlag{
+				Name:  "strict-dependencies",
+				Usage: "reject completing tasks that depend on open tasks",
+				Value: conf.StrictDependencies,
+			},
+			&cli.BoolFlag{
+				Name:  "unique-summaries",
+				Usage: "reject adding tasks whose summary equals that of an open task, ignoring case",
+				Value: conf.UniqueSummaries,
+			},
+			&cli.DurationFlag{
+				Name:  "idempotency-window",
+				Usage: "how long to remember the tasks added by requests with an idempotency key (0 disables them)",
+				Value: time.Duration(conf.IdempotencyWindow),
+			},
+			&cli.IntFlag{
+				Name:  "watch-buffer-size",
+				Usage: "the number of events buffered for each client watching the tasks",
+				Value: conf.WatchBufferSize,
+			},
+			&cli.StringFlag{
+				Name:  "watch-overflow",
+				Usage: "what to do with clients watching the tasks that fall behind (drop-oldest or disconnect)",
+				Value: conf.WatchOverflow,
+			},
+			&cli.IntFlag{
+				Name:  "max-recv-msg-size",
+				Usage: "the maximum size of the gRPC messages that the server receives, in bytes",
+				Value: conf.MaxRecvMsgSize,
+			},
+			&cli.IntFlag{
+				Name:  "max-send-msg-size",
+				Usage: "the maximum size of the gRPC messages that the server sends, in bytes",
+				Value: conf.MaxSendMsgSize,
+			},
+			&cli.StringFlag{
+				Name:  "socket-mode",
+				Usage: "the octal file mode of the Unix socket, e.g. 0660 (default 0600, or 0660 with --socket-group)",
+				Value: conf.SocketMode,
+			},
+			&cli.StringFlag{
+				Name:  "socket-group",
+				Usage: "the name or ID of the group whose members may connect to the Unix socket",
+				Value: conf.SocketGroup,
+			},
+			&cli.StringFlag{
+				Name:    "http-listen",
+				Usage:   "the address of the HTTP server (host:port, unix:///path, or off)",
+				Value:   conf.HTTPListen,
+				Sources: cli.EnvVars(config.EnvHTTPListen),
+			},
+			&cli.StringFlag{
+				Name:  "external-url",
+				Usage: "the URL that clients use to reach the HTTP server, e.g. behind a reverse proxy",
+				Value: conf.ExternalURL,
+			},
+			&cli.StringSliceFlag{
+				Name:  "trusted-proxy",
+				Usage: "the IP address or network of a reverse proxy whose X-Forwarded-* headers are honored, or unix",
+				Value: conf.TrustedProxies,
+			},
+			&cli.StringSliceFlag{
+				Name:  "cors-origin",
+				Usage: "an origin allowed to make cross-origin requests to the REST API, or * for all origins",
+				Value: conf.CORS.AllowedOrigins,
+			},
+			&cli.StringSliceFlag{
+				Name:  "cors-method",
+				Usage: "an HTTP method allowed in cross-origin requests besides GET, HEAD, and POST",
+				Value: conf.CORS.AllowedMethods,
+			},
+			&cli.StringSliceFlag{
+				Name:  "cors-header",
+				Usage: "a request header allowed in cross-origin requests",
+				Value: conf.CORS.AllowedHeaders,
+			},
+			&cli.BoolFlag{
+				Name:  "web-ui",
+				Usage: "serve the web UI at /ui/ on the HTTP server",
+				Value: conf.WebUI,
+			},
+			&cli.BoolFlag{
+				Name:  "demo-data",
+				Usage: "add some demo tasks if the to-do list is empty",
+			},
+			&cli.BoolFlag{
+				Name:  "takeover",
+				Usage: "take over the sockets and tasks of the running server, which stops once its requests are finished",
+			},
+			&cli.BoolFlag{
+				Name:  "debug",
+				Usage: "enable debugging features like gRPC server reflection",
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
-			e, err := NewExecutor(cmd)
+			e, err := NewExecutor(cmd, conf)
 			if err != nil {
 				return err
 			}
@@ -121,3 +862,8 @@ func NewCommand(conf *config.Config) *cli.Command {
 		},
 	}
 }
+
+// logger returns the logger of the messages about the server.
+func logger() *slog.Logger {
+	return logging.Component(logging.ComponentServer)
+}
@@ -0,0 +1,55 @@
+//go:build !windows
+
+package run_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/backup"
+	"github.com/mwopitz/todo-daemon/internal/cli"
+	"github.com/mwopitz/todo-daemon/internal/config"
+)
+
+// TestScheduledBackups checks that the 'run' command passes the backup
+// configuration on to the server, which then writes snapshots periodically.
+func TestScheduledBackups(t *testing.T) {
+	dir := t.TempDir()
+	conf := config.New()
+	conf.Backup = config.Backup{
+		Dir:      filepath.Join(dir, "backups"),
+		Interval: config.Duration(50 * time.Millisecond),
+	}
+	cmd := cli.NewTodoDaemonCommand(conf)
+
+	ctx, cancel := context.WithCancelCause(t.Context())
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Run(ctx, []string{"todo-daemon", "--sock", filepath.Join(dir, "s.sock"), "--log-level", "error",
+			"run", "--lock", filepath.Join(dir, "s.lock"), "--db", "memory", "--http-listen", "localhost:0"})
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		files, err := backup.Files(conf.Backup.Dir)
+		if err == nil && len(files) > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			cancel(errors.New("test failed"))
+			t.Fatalf("want scheduled snapshot in %s; got: %v, %v", conf.Backup.Dir, files, err)
+		}
+		select {
+		case err := <-done:
+			t.Fatalf("server stopped before writing a snapshot: %v", err)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+	cancel(errors.New("test finished"))
+	if err := <-done; err != nil {
+		t.Errorf("cannot stop server: %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/handover"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+	"github.com/mwopitz/todo-daemon/internal/transport"
+)
+
+// takeoverTimeout is the maximum amount of time for the running server to hand
+// over its listeners and tasks.
+const takeoverTimeout = 30 * time.Second
+
+// takeover holds what the running server handed over.
+type takeover struct {
+	listeners handover.Listeners
+	snapshot  *todo.Snapshot
+}
+
+// takeOver asks the server running at the executor's address to hand over its
+// listeners along with a snapshot of its tasks.
+func (e *Executor) takeOver(ctx context.Context) (*takeover, error) {
+	if e.Address.Scheme != transport.SchemeUnix {
+		return nil, fmt.Errorf("cannot take over %s: %w", e.Address, handover.ErrUnsupported)
+	}
+	ctx, cancel := context.WithTimeout(ctx, takeoverTimeout)
+	defer cancel()
+
+	receiver, err := handover.Listen(e.Address.Path + ".handover")
+	if err != nil {
+		return nil, fmt.Errorf("cannot receive listeners: %w", err)
+	}
+	defer func() {
+		if err := receiver.Close(); err != nil {
+			logger().Warn("cannot close handover socket", "cause", err)
+		}
+	}()
+
+	type result struct {
+		listeners handover.Listeners
+		err       error
+	}
+	received := make(chan result, 1)
+	go func() {
+		ls, err := receiver.Receive(ctx)
+		received <- result{ls, err}
+	}()
+
+	c, err := client.New(e.Address.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			logger().Warn("cannot close client connection", "cause", err)
+		}
+	}()
+	resp, err := c.Takeover(ctx, receiver.Path())
+	if err != nil {
+		cancel()
+		if r := <-received; r.err == nil {
+			// The server failed after handing over its listeners, so it
+			// doesn't accept connections anymore. Without its tasks, there is
+			// nothing to serve them with.
+			err = errors.Join(err, r.listeners.Close())
+		}
+		return nil, err
+	}
+	r := <-received
+	if r.err != nil {
+		return nil, r.err
+	}
+	snapshot, err := todo.ReadSnapshot(bytes.NewReader(resp.GetArchive()))
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("invalid snapshot: %w", err), r.listeners.Close())
+	}
+	logger().Info("took over from running server", "pid", resp.GetPid(), "tasks", len(snapshot.Tasks))
+	return &takeover{listeners: r.listeners, snapshot: snapshot}, nil
+}
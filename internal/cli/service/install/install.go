@@ -0,0 +1,155 @@
+// Package install implements the 'install' subcommand of the To-do Daemon
+// CLI's 'service' command.
+//
+// The 'install' subcommand installs the To-do Daemon server as a service of
+// the operating system, i.e. a launchd agent on macOS, a systemd user unit on
+// Linux, or a Windows service, and starts it.
+package install
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+	"github.com/mwopitz/todo-daemon/internal/lockfile"
+	"github.com/mwopitz/todo-daemon/internal/service"
+)
+
+// Executor is used for executing the 'install' command.
+type Executor struct {
+	// Manager installs the service.
+	Manager service.Manager
+	// Spec describes the service to be installed.
+	Spec *service.Spec
+	// LockFile is the lock file of the server, which tells whether a server
+	// is already running.
+	LockFile string
+	// Start specifies whether to start the service after installing it.
+	Start bool
+	// ReadPassword reads the password of the account that the service runs
+	// as, or is nil if the service manager doesn't need it.
+	ReadPassword func(w io.Writer) (string, error)
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+}
+
+// NewExecutor creates an executor for the specified 'install' command.
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	manager, err := service.New()
+	if err != nil {
+		return nil, err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("cannot locate todo-daemon executable: %w", err)
+	}
+	configFile, err := filepath.Abs(config.DefaultFile())
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"run"}
+	if conf.Profile != config.DefaultProfile {
+		args = []string{"--profile", conf.Profile, "run"}
+	}
+	// The service gets the environment variables that configure the server
+	// now, since the service manager doesn't pass the user's environment.
+	env := map[string]string{config.EnvConfigFile: configFile}
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		if strings.HasPrefix(name, "TODO_DAEMON_") && name != config.EnvConfigFile && name != config.EnvProfile {
+			env[name] = value
+		}
+	}
+	spec := &service.Spec{
+		Name:        conf.ServiceName(),
+		Description: "To-do Daemon server (profile " + conf.Profile + ")",
+		Executable:  exe,
+		Args:        args,
+		Env:         env,
+	}
+	if conf.LogFile == "" {
+		// A configured log file is written by the server itself.
+		spec.LogFile = conf.ServiceLogFile()
+	}
+	return &Executor{
+		Manager:      manager,
+		Spec:         spec,
+		LockFile:     conf.LockFile,
+		Start:        !cmd.Bool("no-start"),
+		ReadPassword: readPassword,
+		Stdout:       cmd.Root().Writer,
+		Quiet:        cmd.Bool("quiet"),
+	}, nil
+}
+
+// Execute executes the 'install' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	if e.ReadPassword != nil && e.Spec.Password == "" {
+		password, err := e.ReadPassword(e.Stdout)
+		if err != nil {
+			return fmt.Errorf("cannot read password: %w", err)
+		}
+		e.Spec.Password = password
+	}
+	kind := i18n.Translate(e.Manager.Kind())
+	if err := e.Manager.Install(ctx, e.Spec); err != nil {
+		return fmt.Errorf("cannot install service: %w", err)
+	}
+	e.printf("Installed the %s '%s'", kind, e.Spec.Name)
+	if !e.Start {
+		return nil
+	}
+
+	// A server started by hand holds the lock, so the service would fail.
+	if pid, err := lockfile.ReadPID(e.LockFile); err == nil && pid != 0 && lockfile.IsRunning(pid) {
+		if status, err := e.Manager.Status(ctx, e.Spec.Name); err != nil || !status.Running {
+			e.printf("The server is already running (PID %d), so the service was not started; "+
+				"stop the server and run 'todo-daemon service start'", pid)
+			return nil
+		}
+	}
+	if err := e.Manager.Start(ctx, e.Spec.Name); err != nil {
+		return fmt.Errorf("cannot start service: %w", err)
+	}
+	e.printf("Started the %s '%s'", kind, e.Spec.Name)
+	return nil
+}
+
+// printf prints the translated message unless the command is quiet.
+func (e *Executor) printf(format string, args ...any) {
+	if !e.Quiet {
+		// revive:disable-next-line:unhandled-error
+		fmt.Fprintln(e.Stdout, i18n.Sprintf(format, args...))
+	}
+}
+
+// NewCommand creates a new 'install' command with the specified
+// configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "install",
+		Usage: "Install the server as a service that starts at login, and start it",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "no-start",
+				Usage: "only install the service, don't start it now",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd, conf)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package install
+
+import "io"
+
+// readPassword is nil, since only Windows services need the password of the
+// account that they run as.
+var readPassword func(w io.Writer) (string, error)
@@ -0,0 +1,44 @@
+//go:build windows
+
+package install
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"strings"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// readPassword prompts for the password of the current user, whom the Windows
+// service runs as, and reads it from stdin without echoing it.
+func readPassword(w io.Writer) (string, error) {
+	u, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+	if _, err := fmt.Fprint(w, i18n.Sprintf("Password of %s: ", u.Username)); err != nil {
+		return "", err
+	}
+	stdin := windows.Handle(os.Stdin.Fd())
+	var mode uint32
+	if err := windows.GetConsoleMode(stdin, &mode); err == nil {
+		if err := windows.SetConsoleMode(stdin, mode&^windows.ENABLE_ECHO_INPUT); err == nil {
+			defer func() {
+				_ = windows.SetConsoleMode(stdin, mode)
+				// The newline typed by the user wasn't echoed either.
+				_, _ = fmt.Fprintln(w)
+			}()
+		}
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
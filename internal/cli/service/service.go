@@ -0,0 +1,42 @@
+// Package service implements the 'service' command of the To-do Daemon CLI.
+//
+// The 'service' command provides subcommands for installing the To-do Daemon
+// server as a service of the operating system, which starts it at login, and
+// for controlling that service.
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/service/install"
+	"github.com/mwopitz/todo-daemon/internal/cli/service/start"
+	"github.com/mwopitz/todo-daemon/internal/cli/service/status"
+	"github.com/mwopitz/todo-daemon/internal/cli/service/stop"
+	"github.com/mwopitz/todo-daemon/internal/cli/service/uninstall"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// NewCommand creates a new 'service' command with the specified
+// configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "service",
+		Usage: "Run the server as a service that starts at login",
+		Commands: []*cli.Command{
+			install.NewCommand(conf),
+			uninstall.NewCommand(conf),
+			start.NewCommand(conf),
+			stop.NewCommand(conf),
+			status.NewCommand(conf),
+		},
+		CommandNotFound: func(_ context.Context, _ *cli.Command, name string) {
+			// revive:disable-next-line:unhandled-error
+			fmt.Fprintf(os.Stderr, "todo-daemon: %s: '%s'\n", i18n.Translate("invalid command"), name)
+		},
+	}
+}
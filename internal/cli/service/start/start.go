@@ -0,0 +1,84 @@
+// Package start implements the 'start' subcommand of the To-do Daemon CLI's
+// 'service' command.
+//
+// The 'start' subcommand starts the installed service running the To-do
+// Daemon server.
+package start
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+	"github.com/mwopitz/todo-daemon/internal/lockfile"
+	"github.com/mwopitz/todo-daemon/internal/service"
+)
+
+// Executor is used for executing the 'start' command.
+type Executor struct {
+	// Manager controls the service.
+	Manager service.Manager
+	// Name is the name of the service.
+	Name string
+	// LockFile is the lock file of the server, which tells whether a server
+	// is already running.
+	LockFile string
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+}
+
+// NewExecutor creates an executor for the specified 'start' command.
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	manager, err := service.New()
+	if err != nil {
+		return nil, err
+	}
+	return &Executor{
+		Manager:  manager,
+		Name:     conf.ServiceName(),
+		LockFile: conf.LockFile,
+		Stdout:   cmd.Root().Writer,
+		Quiet:    cmd.Bool("quiet"),
+	}, nil
+}
+
+// Execute executes the 'start' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	// A server started by hand holds the lock, so the service would fail.
+	if pid, err := lockfile.ReadPID(e.LockFile); err == nil && pid != 0 && lockfile.IsRunning(pid) {
+		if status, err := e.Manager.Status(ctx, e.Name); err != nil || !status.Running {
+			return fmt.Errorf("cannot start service: server is already running (PID %d)", pid)
+		}
+	}
+	if err := e.Manager.Start(ctx, e.Name); err != nil {
+		return fmt.Errorf("cannot start service: %w", err)
+	}
+	if e.Quiet {
+		return nil
+	}
+
+	// revive:disable-next-line:unhandled-error
+	fmt.Fprintln(e.Stdout, i18n.Sprintf("Started the %s '%s'", i18n.Translate(e.Manager.Kind()), e.Name))
+	return nil
+}
+
+// NewCommand creates a new 'start' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "start",
+		Usage: "Start the installed service",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd, conf)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
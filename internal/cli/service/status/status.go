@@ -0,0 +1,84 @@
+// Package status implements the 'status' subcommand of the To-do Daemon CLI's
+// 'service' command.
+//
+// The 'status' subcommand prints whether the service running the To-do Daemon
+// server is installed and running, and which file defines it.
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+	"github.com/mwopitz/todo-daemon/internal/service"
+)
+
+// Executor is used for executing the 'status' command.
+type Executor struct {
+	// Manager controls the service.
+	Manager service.Manager
+	// Name is the name of the service.
+	Name string
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+}
+
+// NewExecutor creates an executor for the specified 'status' command.
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	manager, err := service.New()
+	if err != nil {
+		return nil, err
+	}
+	return &Executor{
+		Manager: manager,
+		Name:    conf.ServiceName(),
+		Stdout:  cmd.Root().Writer,
+	}, nil
+}
+
+// Execute executes the 'status' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	kind := i18n.Translate(e.Manager.Kind())
+	status, err := e.Manager.Status(ctx, e.Name)
+	if errors.Is(err, service.ErrNotInstalled) {
+		_, err := fmt.Fprintln(e.Stdout, i18n.Sprintf("The %s '%s' is not installed", kind, e.Name))
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("cannot retrieve service status: %w", err)
+	}
+
+	var line string
+	if status.Running {
+		line = i18n.Sprintf("The %s '%s' is running (PID %d)", kind, e.Name, status.PID)
+	} else {
+		line = i18n.Sprintf("The %s '%s' is installed but not running", kind, e.Name)
+	}
+	if _, err := fmt.Fprintln(e.Stdout, line); err != nil {
+		return err
+	}
+	if status.Path != e.Name {
+		_, err = fmt.Fprintln(e.Stdout, status.Path)
+	}
+	return err
+}
+
+// NewCommand creates a new 'status' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "status",
+		Usage: "Print whether the service is installed and running",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd, conf)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
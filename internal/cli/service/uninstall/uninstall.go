@@ -0,0 +1,73 @@
+// Package uninstall implements the 'uninstall' subcommand of the To-do Daemon CLI's
+// 'service' command.
+//
+// The 'uninstall' subcommand stops the service running the To-do Daemon
+// server and removes it.
+package uninstall
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+	"github.com/mwopitz/todo-daemon/internal/service"
+)
+
+// Executor is used for executing the 'uninstall' command.
+type Executor struct {
+	// Manager controls the service.
+	Manager service.Manager
+	// Name is the name of the service.
+	Name string
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+}
+
+// NewExecutor creates an executor for the specified 'uninstall' command.
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	manager, err := service.New()
+	if err != nil {
+		return nil, err
+	}
+	return &Executor{
+		Manager: manager,
+		Name:    conf.ServiceName(),
+		Stdout:  cmd.Root().Writer,
+		Quiet:   cmd.Bool("quiet"),
+	}, nil
+}
+
+// Execute executes the 'uninstall' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	if err := e.Manager.Uninstall(ctx, e.Name); err != nil {
+		return fmt.Errorf("cannot uninstall service: %w", err)
+	}
+	if e.Quiet {
+		return nil
+	}
+
+	// revive:disable-next-line:unhandled-error
+	fmt.Fprintln(e.Stdout, i18n.Sprintf("Uninstalled the %s '%s'", i18n.Translate(e.Manager.Kind()), e.Name))
+	return nil
+}
+
+// NewCommand creates a new 'uninstall' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "uninstall",
+		Usage: "Stop the service and remove it",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd, conf)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
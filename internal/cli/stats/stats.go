@@ -0,0 +1,123 @@
+// Package stats implements the 'stats' command of the To-do Daemon CLI.
+//
+// The 'stats' command retrieves statistics about the tasks in the to-do list,
+// e.g. the number of tasks completed this week, and prints them to standard
+// output.
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+)
+
+const (
+	outputFormatText = "text"
+	outputFormatJSON = "json"
+	// outputFormatPorcelain is the stable, tab-separated format for scripts.
+	outputFormatPorcelain = "porcelain"
+)
+
+// Executor is used for executing the 'stats' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewClient creates the client for connecting to the To-do Daemon
+	// server.
+	NewClient client.Factory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// OutputFormat specifies the format for printing the statistics to
+	// standard output.
+	OutputFormat string
+}
+
+// NewExecutor creates an executor for the specified 'stats' command.
+func NewExecutor(cmd *cli.Command) (*Executor, error) {
+	format := cmd.String("format")
+	if cmd.Bool("porcelain") {
+		if cmd.IsSet("format") && format != outputFormatPorcelain {
+			return nil, exitcode.NewUsageError("--porcelain cannot be used with --format %s", format)
+		}
+		format = outputFormatPorcelain
+	}
+	return &Executor{
+		SockFile:     cmd.String("sock"),
+		Timeout:      cmd.Duration("timeout"),
+		NewClient:    client.New,
+		Stdout:       cmd.Root().Writer,
+		OutputFormat: format,
+	}, nil
+}
+
+// Execute executes the 'stats' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewClient(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	stats, err := c.GetStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch format := e.OutputFormat; format {
+	case outputFormatText:
+		return clifmt.PrintStats(e.Stdout, stats)
+	case outputFormatJSON:
+		err = json.NewEncoder(e.Stdout).Encode(stats)
+		if err != nil {
+			return fmt.Errorf("cannot print statistics: %w", err)
+		}
+		return nil
+	case outputFormatPorcelain:
+		return clifmt.PrintStatsPorcelain(e.Stdout, stats)
+	default:
+		return exitcode.NewUsageError("invalid output format: %s", format)
+	}
+}
+
+// NewCommand creates a new 'stats' command with the specified configuration.
+func NewCommand(_ *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "stats",
+		Usage: "Print statistics about the tasks in the to-do list",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "the output format (text, json, or porcelain)",
+				Value: outputFormatText,
+			},
+			&cli.BoolFlag{
+				Name:  "porcelain",
+				Usage: "short for --format porcelain",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
@@ -6,26 +6,37 @@ package status
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"io"
 	"log/slog"
-	"os"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
 	"github.com/mwopitz/todo-daemon/internal/client"
 	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
 )
 
 const (
+	outputFormatText = "text"
 	outputFormatJSON = "json"
+	// outputFormatPorcelain is the stable, tab-separated format for scripts.
+	outputFormatPorcelain = "porcelain"
 )
 
 // Executor is used for executing the 'status' command.
 type Executor struct {
-	// SockFile is the path to the Unix socket file used for connecting to the
-	// To-do Daemon server.
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
 	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewClient creates the client for connecting to the To-do Daemon
+	// server.
+	NewClient client.Factory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
 	// OutputFormat specifies the format for printing the status to standard
 	// output.
 	OutputFormat string
@@ -33,15 +44,29 @@ type Executor struct {
 
 // NewExecutor creates an executor for the specified 'status' command.
 func NewExecutor(cmd *cli.Command) (*Executor, error) {
+	// --json and --porcelain are short for the respective --format.
+	format, formatSet := cmd.String("format"), cmd.IsSet("format")
+	for _, short := range []string{outputFormatJSON, outputFormatPorcelain} {
+		if !cmd.Bool(short) {
+			continue
+		}
+		if formatSet && format != short {
+			return nil, exitcode.NewUsageError("--%s cannot be used with --format %s", short, format)
+		}
+		format, formatSet = short, true
+	}
 	return &Executor{
 		SockFile:     cmd.String("sock"),
-		OutputFormat: cmd.String("format"),
+		Timeout:      cmd.Duration("timeout"),
+		NewClient:    client.New,
+		Stdout:       cmd.Root().Writer,
+		OutputFormat: format,
 	}, nil
 }
 
 // Execute executes the 'status' command.
 func (o *Executor) Execute(ctx context.Context) error {
-	c, err := client.New("unix", o.SockFile)
+	c, err := o.NewClient(o.SockFile, client.WithTimeout(o.Timeout))
 	if err != nil {
 		return err
 	}
@@ -57,14 +82,14 @@ func (o *Executor) Execute(ctx context.Context) error {
 	}
 
 	switch format := o.OutputFormat; format {
+	case outputFormatText:
+		return clifmt.PrintStatus(o.Stdout, status)
 	case outputFormatJSON:
-		err = json.NewEncoder(os.Stdout).Encode(status)
-		if err != nil {
-			return fmt.Errorf("cannot print status: %w", err)
-		}
-		return nil
+		return clifmt.PrintStatusJSON(o.Stdout, status)
+	case outputFormatPorcelain:
+		return clifmt.PrintStatusPorcelain(o.Stdout, status)
 	default:
-		return fmt.Errorf("invalid output format: %s", format)
+		return exitcode.NewUsageError("invalid output format: %s", format)
 	}
 }
 
@@ -76,10 +101,18 @@ func NewCommand(_ *config.Config) *cli.Command {
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:      "format",
-				Usage:     "the output format",
-				Value:     outputFormatJSON,
+				Usage:     "the output format (text, json, or porcelain)",
+				Value:     outputFormatText,
 				TakesFile: true,
 			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "short for --format json",
+			},
+			&cli.BoolFlag{
+				Name:  "porcelain",
+				Usage: "short for --format porcelain",
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			e, err := NewExecutor(cmd)
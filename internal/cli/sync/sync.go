@@ -0,0 +1,215 @@
+// Package sync implements the 'sync' command of the To-do Daemon CLI.
+//
+// The 'sync' command synchronizes the to-do list with the to-do list of
+// another To-do Daemon, e.g. on another computer. It exchanges the tasks that
+// were created, updated, or deleted since the last synchronization with the
+// same peer, and reports the tasks that were changed on both sides.
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// Executor is used for executing the 'sync' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the servers.
+	Timeout time.Duration
+	// NewClient creates the client for connecting to the To-do Daemon
+	// server.
+	NewClient client.Factory
+	// NewPeer creates the client for connecting to the peer.
+	NewPeer func(address string, opts ...client.Option) (client.SyncPeer, error)
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+	// Peer is the address of the To-do Daemon to synchronize with, see
+	// [client.NewSyncPeer].
+	Peer string
+	// StateFile is the path of the file holding the state of the
+	// synchronization with each peer.
+	StateFile string
+}
+
+// peerState is the state of the synchronization with a peer. The times are
+// those returned by the servers when pulling changes, so each is according to
+// the clock of the respective server.
+type peerState struct {
+	// Local is the time up to which the changes of the local to-do list were
+	// sent to the peer.
+	Local time.Time `json:"local"`
+	// Peer is the time up to which the changes of the peer were received.
+	Peer time.Time `json:"peer"`
+}
+
+// NewExecutor creates an executor for the specified 'sync' command.
+func NewExecutor(cmd *cli.Command) (*Executor, error) {
+	return &Executor{
+		SockFile:  cmd.String("sock"),
+		Timeout:   cmd.Duration("timeout"),
+		NewClient: client.New,
+		NewPeer:   client.NewSyncPeer,
+		Stdout:    cmd.Root().Writer,
+		Quiet:     cmd.Bool("quiet"),
+		Peer:      cmd.String("peer"),
+		StateFile: cmd.String("state"),
+	}, nil
+}
+
+// Execute executes the 'sync' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	state, err := loadState(e.StateFile)
+	if err != nil {
+		return err
+	}
+	local, err := e.NewClient(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := local.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+	peer, err := e.NewPeer(e.Peer, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := peer.Close(); err != nil {
+			slog.Warn("cannot close peer connection", "cause", err)
+		}
+	}()
+
+	st := state[e.Peer]
+	localChanges, err := local.PullChanges(ctx, st.Local)
+	if err != nil {
+		return err
+	}
+	peerChanges, err := peer.PullChanges(ctx, st.Peer)
+	if err != nil {
+		return fmt.Errorf("peer %s: %w", e.Peer, err)
+	}
+	sent, err := peer.PushChanges(ctx, localChanges.GetChanges(), st.Peer)
+	if err != nil {
+		return fmt.Errorf("peer %s: %w", e.Peer, err)
+	}
+	received, err := local.PushChanges(ctx, peerChanges.GetChanges(), st.Local)
+	if err != nil {
+		return err
+	}
+	state[e.Peer] = peerState{
+		Local: localChanges.GetTime().AsTime(),
+		Peer:  peerChanges.GetTime().AsTime(),
+	}
+	if err := saveState(e.StateFile, state); err != nil {
+		return err
+	}
+	if e.Quiet {
+		return nil
+	}
+
+	// revive:disable-next-line:unhandled-error
+	fmt.Fprintln(e.Stdout, i18n.Sprintf("Synchronized with %s: %d tasks sent, %d tasks received",
+		e.Peer, sent.GetAppliedCount(), received.GetAppliedCount()))
+	for _, c := range received.GetConflicts() {
+		printConflict(e.Stdout, c)
+	}
+	return nil
+}
+
+// printConflict prints a task that was changed on both sides, and which of the
+// changes was kept.
+func printConflict(w io.Writer, c *todopb.SyncConflict) {
+	kept, loc := "local", clifmt.Location(w)
+	if c.GetResolution() == todopb.SyncConflict_RESOLUTION_REMOTE_WINS {
+		kept = "peer's"
+	}
+	// revive:disable-next-line:unhandled-error
+	fmt.Fprintf(w, "Conflict: task %s (%s) was changed on both sides (local: %s, peer: %s); kept the %s change\n",
+		c.GetTask().GetId(), c.GetTask().GetSummary(),
+		c.GetLocalChangedAt().AsTime().In(loc).Format(time.DateTime),
+		c.GetRemoteChangedAt().AsTime().In(loc).Format(time.DateTime),
+		kept)
+}
+
+// loadState reads the state of the synchronization with each peer from the
+// specified file. If the file does not exist, nothing was synchronized yet.
+func loadState(path string) (map[string]peerState, error) {
+	state := make(map[string]peerState)
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read synchronization state: %w", err)
+	}
+	if err := json.Unmarshal(b, &state); err != nil {
+		return nil, fmt.Errorf("invalid synchronization state in %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// saveState writes the state of the synchronization with each peer to the
+// specified file.
+func saveState(path string, state map[string]peerState) error {
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot write synchronization state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("cannot write synchronization state: %w", err)
+	}
+	if err := os.WriteFile(path, append(b, '\n'), 0o600); err != nil {
+		return fmt.Errorf("cannot write synchronization state: %w", err)
+	}
+	return nil
+}
+
+// NewCommand creates a new 'sync' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "sync",
+		Usage: "Synchronize the to-do list with the to-do list of another To-do Daemon",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "peer",
+				Usage:    "address of the To-do Daemon to synchronize with, i.e. its socket or the URL of its REST API",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:      "state",
+				Usage:     "path of the file holding the state of the synchronization with each peer",
+				Value:     conf.SyncStateFile(),
+				TakesFile: true,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
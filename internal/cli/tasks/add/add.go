@@ -2,42 +2,171 @@
 // command.
 //
 // The 'add' subcommend adds a new task to the to-do list, with a user-specified
-// summary.
+// summary. The due time may be written in natural language, like "next friday
+// 5pm", or describe a recurrence, like "every monday", which makes the task
+// recurring. With the summary '-' or the --file flag, it adds one task per line
+// read from stdin or from the file, respectively, and prints their IDs. With
+// the --offline flag, the tasks are queued if the server is not running, and
+// added once it is reachable again.
 package add
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/urfave/cli/v3"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
 	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
 	"github.com/mwopitz/todo-daemon/internal/client"
 	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/duedate"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+	"github.com/mwopitz/todo-daemon/internal/queue"
+	"github.com/mwopitz/todo-daemon/internal/standalone"
 )
 
 // Executor is used for executing the 'add' command.
 type Executor struct {
-	// SockFile is the path to the Unix socket file used for connecting to the
-	// To-do Daemon server and creating a new task.
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server and creating a new task.
 	SockFile string
-	// TaskSummary is the summary of the to-do list task to be created.
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// Retries is the number of times that a call creating a task is retried
+	// if the server is unavailable or doesn't respond in time.
+	Retries int
+	// NewService creates the service that the command operates on: a client
+	// connected to the To-do Daemon server or, in standalone mode, the to-do
+	// list opened in-process.
+	NewService client.TaskServiceFactory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// TaskSummary is the summary of the to-do list task to be created. It is
+	// ignored if File is set.
 	TaskSummary string
+	// File is the path to a file with one task summary per line, or "-" for
+	// reading the summaries from stdin. If set, a task is created for each
+	// non-blank line, all with the same description, due time, tags,
+	// project, assignee, and links.
+	File string
+	// TaskDescription is the optional description of the task to be created.
+	TaskDescription string
+	// TaskDueAt is the optional due time of the task to be created.
+	TaskDueAt time.Time
+	// TaskRecurrence is the optional recurrence rule of the task to be
+	// created, see [todo.ParseRecurrence].
+	TaskRecurrence string
+	// TaskTimeZone is the optional IANA name of the time zone of the task to
+	// be created. If empty, the server's default time zone is used.
+	TaskTimeZone string
+	// TaskTags are the optional tags of the task to be created.
+	TaskTags []string
+	// TaskProject is the optional project of the task to be created.
+	TaskProject string
+	// TaskAssignee is the optional user that the task to be created is
+	// assigned to.
+	TaskAssignee string
+	// TaskLinks are the optional URLs of web pages related to the task to be
+	// created.
+	TaskLinks []string
+	// AllowDuplicate specifies whether to create the task even if the server
+	// rejects tasks whose summary equals the summary of an open task.
+	AllowDuplicate bool
+	// Stdin is the reader to read the task summaries from if File is "-".
+	Stdin io.Reader
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+	// List specifies whether to print the entire to-do list instead of just
+	// the created task, or the IDs of the created tasks if File is set.
+	List bool
+	// Queue is the offline queue that the tasks are added to if the server is
+	// not running, or nil if the command fails then.
+	Queue *queue.Queue
 }
 
 // NewExecutor creates an executor for the specified 'add' command.
-func NewExecutor(cmd *cli.Command) (*Executor, error) {
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	var (
+		dueAt      time.Time
+		recurrence string
+	)
+	if due := cmd.String("due"); due != "" {
+		parser, err := duedate.NewParser(conf.Locale, cmd.String("time-zone"))
+		if err != nil {
+			return nil, exitcode.NewUsageError("%w", err)
+		}
+		r, err := parser.Parse(due)
+		if err != nil {
+			return nil, exitcode.NewUsageError("%w", err)
+		}
+		dueAt = r.Time
+		if r.Recurrence != nil {
+			recurrence = r.Recurrence.String()
+		}
+	}
+	summary, file := cmd.StringArg("summary"), cmd.String("file")
+	switch {
+	case summary == "-" && file == "":
+		file = "-"
+	case summary != "" && file != "":
+		return nil, exitcode.NewUsageError("cannot combine a summary with --file")
+	}
+	retries := cmd.Int("retries")
+	if retries < 0 {
+		return nil, exitcode.NewUsageError("--retries must not be negative")
+	}
+	var q *queue.Queue
+	if cmd.Bool("offline") {
+		q = queue.New(conf.QueueFile())
+	}
 	return &Executor{
-		SockFile:    cmd.String("sock"),
-		TaskSummary: cmd.StringArg("summary"),
+		SockFile:        cmd.String("sock"),
+		Timeout:         cmd.Duration("timeout"),
+		Retries:         retries,
+		NewService:      standalone.ServiceFactory(cmd.Bool("standalone"), conf),
+		Stdout:          cmd.Root().Writer,
+		TaskSummary:     summary,
+		File:            file,
+		TaskDescription: cmd.String("description"),
+		TaskDueAt:       dueAt,
+		TaskRecurrence:  recurrence,
+		TaskTimeZone:    cmd.String("time-zone"),
+		TaskTags:        cmd.StringSlice("tag"),
+		TaskProject:     cmd.String("project"),
+		TaskAssignee:    cmd.String("assignee"),
+		TaskLinks:       cmd.StringSlice("link"),
+		AllowDuplicate:  cmd.Bool("allow-duplicate"),
+		Stdin:           cmd.Root().Reader,
+		Quiet:           cmd.Bool("quiet"),
+		List:            cmd.Bool("list"),
+		Queue:           q,
 	}, nil
 }
 
 // Execute executes the 'add' command.
 func (e *Executor) Execute(ctx context.Context) error {
-	c, err := client.New("unix", e.SockFile)
+	var summaries []string
+	if e.File != "" {
+		var err error
+		if summaries, err = e.readSummaries(); err != nil {
+			return err
+		}
+		if len(summaries) == 0 {
+			return errors.New("no task summaries to add")
+		}
+	}
+
+	c, err := e.NewService(e.SockFile, client.WithTimeout(e.Timeout), client.WithRetries(e.Retries))
 	if err != nil {
 		return err
 	}
@@ -47,29 +176,189 @@ func (e *Executor) Execute(ctx context.Context) error {
 		}
 	}()
 
-	_, err = c.CreateTask(ctx, e.TaskSummary)
+	if summaries != nil {
+		return e.createTasks(ctx, c, summaries)
+	}
+
+	task := e.newTask(e.TaskSummary)
+	created, err := c.CreateTask(ctx, task)
+	if e.offline(err) {
+		return e.enqueue(ctx, task)
+	}
 	if err != nil {
 		return fmt.Errorf("cannot create task: %w", err)
 	}
+	switch {
+	case e.Quiet:
+		return nil
+	case !e.List:
+		return clifmt.PrintTasks(e.Stdout, []*todopb.Task{created})
+	}
+	return e.printList(ctx, c)
+}
 
+// printList prints the entire to-do list.
+func (e *Executor) printList(ctx context.Context, c client.TaskService) error {
 	tasks, err := c.ListTasks(ctx)
 	if err != nil {
 		return fmt.Errorf("cannot retrieve tasks: %w", err)
 	}
+	return clifmt.PrintTasks(e.Stdout, tasks)
+}
 
-	return clifmt.PrintTasks(os.Stdout, tasks)
+// createTasks creates a task for each of the specified summaries in a single
+// call and prints the IDs of the created tasks, one per line, or the entire
+// to-do list.
+func (e *Executor) createTasks(ctx context.Context, c client.TaskService, summaries []string) error {
+	tasks := make([]*todopb.NewTask, len(summaries))
+	for i, summary := range summaries {
+		tasks[i] = e.newTask(summary)
+	}
+	created, err := c.BatchCreateTasks(ctx, tasks)
+	if e.offline(err) {
+		return e.enqueue(ctx, tasks...)
+	}
+	if err != nil || e.Quiet {
+		return err
+	}
+	if e.List {
+		return e.printList(ctx, c)
+	}
+	for _, t := range created {
+		// revive:disable-next-line:unhandled-error
+		fmt.Fprintln(e.Stdout, t.GetId())
+	}
+	return nil
+}
+
+// offline reports whether the specified error of creating tasks means that
+// they should be added to the offline queue instead.
+func (e *Executor) offline(err error) bool {
+	return e.Queue != nil && errors.Is(err, client.ErrDaemonNotRunning)
+}
+
+// enqueue adds the specified tasks to the offline queue.
+func (e *Executor) enqueue(ctx context.Context, tasks ...*todopb.NewTask) error {
+	if err := e.Queue.Add(ctx, tasks...); err != nil {
+		return err
+	}
+	if e.Quiet {
+		return nil
+	}
+	// revive:disable-next-line:unhandled-error
+	fmt.Fprintln(e.Stdout, i18n.Sprintf(
+		"The server is not running; queued %d tasks to be added once it is reachable", len(tasks)))
+	return nil
+}
+
+// newTask creates a task with the specified summary and the description, due
+// time, recurrence, time zone, tags, project, assignee, and links of the
+// executor.
+func (e *Executor) newTask(summary string) *todopb.NewTask {
+	task := &todopb.NewTask{
+		Summary:        summary,
+		Description:    e.TaskDescription,
+		Tags:           e.TaskTags,
+		Project:        e.TaskProject,
+		Assignee:       e.TaskAssignee,
+		Links:          e.TaskLinks,
+		Recurrence:     e.TaskRecurrence,
+		TimeZone:       e.TaskTimeZone,
+		AllowDuplicate: e.AllowDuplicate,
+	}
+	if !e.TaskDueAt.IsZero() {
+		task.DueAt = timestamppb.New(e.TaskDueAt)
+	}
+	return task
+}
+
+// readSummaries reads the task summaries from the executor's file or stdin,
+// one per line. Leading and trailing white space is trimmed, and blank lines
+// are skipped.
+func (e *Executor) readSummaries() ([]string, error) {
+	r := e.Stdin
+	if e.File != "-" {
+		f, err := os.Open(e.File)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open task file: %w", err)
+		}
+		defer func() {
+			if err := f.Close(); err != nil {
+				slog.Warn("cannot close task file", "cause", err)
+			}
+		}()
+		r = f
+	}
+	var summaries []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if summary := strings.TrimSpace(scanner.Text()); summary != "" {
+			summaries = append(summaries, summary)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read task summaries: %w", err)
+	}
+	return summaries, nil
 }
 
 // NewCommand creates a new 'add' command with the specified configuration.
-func NewCommand(_ *config.Config) *cli.Command {
+func NewCommand(conf *config.Config) *cli.Command {
 	return &cli.Command{
 		Name:  "add",
-		Usage: "Add a task to the to-do list",
+		Usage: "Add a task to the to-do list, or one task per line of stdin ('-') or a file",
 		Arguments: []cli.Argument{
 			&cli.StringArg{Name: "summary"},
 		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "file",
+				Usage: "a file with one task summary per line, or '-' for stdin",
+			},
+			&cli.StringFlag{
+				Name:  "description",
+				Usage: "a more detailed description of the task",
+			},
+			&cli.StringFlag{
+				Name:  "due",
+				Usage: "the due time, e.g. '2006-01-02 15:04', 'next friday 5pm', 'in 3 days', or 'every monday'",
+			},
+			&cli.StringSliceFlag{
+				Name:  "tag",
+				Usage: "a tag of the task (can be repeated)",
+			},
+			&cli.StringFlag{
+				Name:  "project",
+				Usage: "the project the task belongs to",
+			},
+			&cli.StringFlag{
+				Name:  "assignee",
+				Usage: "the user the task is assigned to",
+			},
+			&cli.StringSliceFlag{
+				Name:  "link",
+				Usage: "the URL of a related web page, e.g. a ticket (can be repeated)",
+			},
+			&cli.BoolFlag{
+				Name:  "allow-duplicate",
+				Usage: "add the task even if the server rejects duplicates of open tasks (see run --unique-summaries)",
+			},
+			&cli.IntFlag{
+				Name:  "retries",
+				Usage: "the number of times to retry adding a task if the server is unavailable or doesn't respond in time",
+				Value: 2,
+			},
+			&cli.BoolFlag{
+				Name:  "list",
+				Usage: "print the entire to-do list instead of just the created task",
+			},
+			&cli.BoolFlag{
+				Name:  "offline",
+				Usage: "queue the task if the server is not running, and add it once the server is reachable",
+			},
+		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
-			e, err := NewExecutor(cmd)
+			e, err := NewExecutor(cmd, conf)
 			if err != nil {
 				return err
 			}
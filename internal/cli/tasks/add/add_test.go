@@ -0,0 +1,95 @@
+package add
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/clitest"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/queue"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+func TestExecute(t *testing.T) {
+	srv := clitest.NewServer(t, "Buy milk")
+	var out bytes.Buffer
+	e := &Executor{
+		SockFile:    clitest.Address,
+		NewService:  srv.NewTaskService,
+		Stdout:      &out,
+		TaskSummary: "Walk the dog",
+		TaskTags:    []string{"pets"},
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	if want := "#2 [ ] Walk the dog\n"; out.String() != want {
+		t.Errorf("want output: %q; got: %q", want, out.String())
+	}
+	task, err := srv.DB.Get(t.Context(), "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(task.Tags) != 1 || task.Tags[0] != "pets" {
+		t.Errorf("want tags: [pets]; got: %v", task.Tags)
+	}
+}
+
+func TestExecuteStdin(t *testing.T) {
+	srv := clitest.NewServer(t)
+	var out bytes.Buffer
+	e := &Executor{
+		SockFile:   clitest.Address,
+		NewService: srv.NewTaskService,
+		Stdout:     &out,
+		File:       "-",
+		Stdin:      strings.NewReader("Buy milk\n\n  Walk the dog  \n"),
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	if want := "1\n2\n"; out.String() != want {
+		t.Errorf("want output: %q; got: %q", want, out.String())
+	}
+	tasks, err := srv.DB.List(t.Context(), &todo.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 {
+		t.Errorf("want 2 tasks; got: %d", len(tasks))
+	}
+}
+
+func TestExecuteOffline(t *testing.T) {
+	dir := t.TempDir()
+	var out bytes.Buffer
+	e := &Executor{
+		SockFile:    filepath.Join(dir, "todo-daemon.sock"),
+		NewService:  client.NewTaskService,
+		Stdout:      &out,
+		TaskSummary: "Walk the dog",
+		Queue:       queue.New(filepath.Join(dir, "queue.jsonl")),
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	if !strings.Contains(out.String(), "queued 1 tasks") {
+		t.Errorf("want task queued; got output: %q", out.String())
+	}
+
+	srv := clitest.NewServer(t)
+	c, err := srv.NewTaskService(clitest.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	result, err := e.Queue.Flush(t.Context(), c)
+	if err != nil {
+		t.Fatalf("cannot flush queue: %v", err)
+	}
+	if len(result.Created) != 1 || result.Created[0].GetSummary() != "Walk the dog" {
+		t.Errorf("want queued task created; got: %v", result.Created)
+	}
+}
@@ -0,0 +1,137 @@
+// Package assign implements the 'assign' and 'unassign' subcommands of the
+// To-do Daemon CLI's 'tasks' command.
+//
+// The 'assign' subcommand assigns a task in the to-do list to a user, e.g. a
+// member of the team sharing the server. The 'unassign' subcommand removes
+// the assignment again.
+package assign
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/standalone"
+)
+
+// Executor is used for executing the 'assign' and 'unassign' commands.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewService creates the service that the command operates on: a client
+	// connected to the To-do Daemon server or, in standalone mode, the to-do
+	// list opened in-process.
+	NewService client.TaskServiceFactory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+	// TaskID is the ID or short code of the task to be assigned or
+	// unassigned.
+	TaskID string
+	// Assignee is the user to assign the task to, or empty to unassign it.
+	Assignee string
+}
+
+// NewExecutor creates an executor for the specified 'assign' or 'unassign'
+// command.
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	taskID := cmd.StringArg("id")
+	if taskID == "" {
+		return nil, exitcode.NewUsageError("no task ID specified")
+	}
+	assignee := cmd.StringArg("user")
+	if cmd.Name != "unassign" && assignee == "" {
+		return nil, exitcode.NewUsageError("no user specified")
+	}
+	return &Executor{
+		SockFile:   cmd.String("sock"),
+		Timeout:    cmd.Duration("timeout"),
+		NewService: standalone.ServiceFactory(cmd.Bool("standalone"), conf),
+		Stdout:     cmd.Root().Writer,
+		Quiet:      cmd.Bool("quiet"),
+		TaskID:     taskID,
+		Assignee:   assignee,
+	}, nil
+}
+
+// Execute executes the 'assign' or 'unassign' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	action := "assign"
+	if e.Assignee == "" {
+		action = "unassign"
+	}
+	c, err := e.NewService(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	task, err := c.ResolveTask(ctx, e.TaskID)
+	if err != nil {
+		return fmt.Errorf("cannot %s task: %w", action, err)
+	}
+	updated, err := c.SetAssignee(ctx, task.GetId(), e.Assignee)
+	if err != nil {
+		return fmt.Errorf("cannot %s task: %w", action, err)
+	}
+	if e.Quiet {
+		return nil
+	}
+	return clifmt.PrintTasks(e.Stdout, []*todopb.Task{updated})
+}
+
+// NewCommand creates a new 'assign' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:      "assign",
+		Usage:     "Assign a task to a user",
+		UsageText: "todo-daemon tasks assign <id> <user>",
+		Arguments: []cli.Argument{
+			&cli.StringArg{Name: "id"},
+			&cli.StringArg{Name: "user"},
+		},
+		Action: action(conf),
+	}
+}
+
+// NewUnassignCommand creates a new 'unassign' command with the specified
+// configuration.
+func NewUnassignCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:      "unassign",
+		Usage:     "Remove the assignment of a task to a user",
+		UsageText: "todo-daemon tasks unassign <id>",
+		Arguments: []cli.Argument{
+			&cli.StringArg{Name: "id"},
+		},
+		Action: action(conf),
+	}
+}
+
+// action returns the action of the 'assign' and 'unassign' commands.
+func action(conf *config.Config) cli.ActionFunc {
+	return func(ctx context.Context, cmd *cli.Command) error {
+		e, err := NewExecutor(cmd, conf)
+		if err != nil {
+			return err
+		}
+		return e.Execute(ctx)
+	}
+}
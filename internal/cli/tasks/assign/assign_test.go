@@ -0,0 +1,65 @@
+package assign
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/clitest"
+)
+
+func TestExecute(t *testing.T) {
+	srv := clitest.NewServer(t, "Buy milk", "Walk the dog")
+	var out bytes.Buffer
+	e := &Executor{
+		SockFile:   clitest.Address,
+		NewService: srv.NewTaskService,
+		Stdout:     &out,
+		TaskID:     "2",
+		Assignee:   "alice",
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	if want := "#2 [ ] Walk the dog @alice\n"; out.String() != want {
+		t.Errorf("want output: %q; got: %q", want, out.String())
+	}
+	task, err := srv.DB.Get(t.Context(), "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Assignee != "alice" {
+		t.Errorf("want assignee alice; got: %q", task.Assignee)
+	}
+
+	out.Reset()
+	e.Assignee = ""
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	if want := "#2 [ ] Walk the dog\n"; out.String() != want {
+		t.Errorf("want output: %q; got: %q", want, out.String())
+	}
+	if task, err = srv.DB.Get(t.Context(), "2"); err != nil {
+		t.Fatal(err)
+	}
+	if task.Assignee != "" {
+		t.Errorf("want task to be unassigned; got assignee: %q", task.Assignee)
+	}
+}
+
+func TestExecuteNotFound(t *testing.T) {
+	srv := clitest.NewServer(t, "Buy milk")
+	e := &Executor{
+		SockFile:   clitest.Address,
+		NewService: srv.NewTaskService,
+		Stdout:     &bytes.Buffer{},
+		TaskID:     "42",
+		Assignee:   "alice",
+	}
+	if err := e.Execute(t.Context()); status.Code(err) != codes.NotFound {
+		t.Errorf("want error with code %s; got: %v", codes.NotFound, err)
+	}
+}
@@ -0,0 +1,139 @@
+// Package block implements the 'block' subcommand of the To-do Daemon CLI's
+// 'tasks' command.
+//
+// The 'block' subcommand makes a task in the to-do list depend on other tasks,
+// which block it until they are completed.
+package block
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/standalone"
+)
+
+// Executor is used for executing the 'block' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewService creates the service that the command operates on: a client
+	// connected to the To-do Daemon server or, in standalone mode, the to-do
+	// list opened in-process.
+	NewService client.TaskServiceFactory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+	// TaskID is the ID or short code of the task to be blocked.
+	TaskID string
+	// BlockerIDs are the IDs or short codes of the tasks that block the task.
+	BlockerIDs []string
+	// Remove specifies whether to remove the blocking tasks from the task's
+	// dependencies instead of adding them.
+	Remove bool
+}
+
+// NewExecutor creates an executor for the specified 'block' command.
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	taskID := cmd.StringArg("id")
+	if taskID == "" {
+		return nil, exitcode.NewUsageError("no task ID specified")
+	}
+	blockers := cmd.StringSlice("on")
+	if len(blockers) == 0 {
+		return nil, exitcode.NewUsageError("no blocking task specified, use --on")
+	}
+	return &Executor{
+		SockFile:   cmd.String("sock"),
+		Timeout:    cmd.Duration("timeout"),
+		NewService: standalone.ServiceFactory(cmd.Bool("standalone"), conf),
+		Stdout:     cmd.Root().Writer,
+		Quiet:      cmd.Bool("quiet"),
+		TaskID:     taskID,
+		BlockerIDs: blockers,
+		Remove:     cmd.Bool("remove"),
+	}, nil
+}
+
+// Execute executes the 'block' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewService(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	task, err := c.ResolveTask(ctx, e.TaskID)
+	if err != nil {
+		return fmt.Errorf("cannot block task: %w", err)
+	}
+	dependsOn := slices.Clone(task.GetDependsOn())
+	for _, ref := range e.BlockerIDs {
+		blocker, err := c.ResolveTask(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("cannot block task: %w", err)
+		}
+		id := blocker.GetId()
+		switch {
+		case e.Remove:
+			dependsOn = slices.DeleteFunc(dependsOn, func(dep string) bool { return dep == id })
+		case !slices.Contains(dependsOn, id):
+			dependsOn = append(dependsOn, id)
+		}
+	}
+	updated, err := c.SetDependencies(ctx, task.GetId(), dependsOn)
+	if err != nil {
+		return fmt.Errorf("cannot block task: %w", err)
+	}
+	if e.Quiet {
+		return nil
+	}
+	return clifmt.PrintTasks(e.Stdout, []*todopb.Task{updated})
+}
+
+// NewCommand creates a new 'block' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:      "block",
+		Usage:     "Block a task until other tasks are completed",
+		UsageText: "todo-daemon tasks block <id> --on <other-id> [--on <other-id>...]",
+		Arguments: []cli.Argument{
+			&cli.StringArg{Name: "id"},
+		},
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:  "on",
+				Usage: "the ID or short code of a task that must be completed first",
+			},
+			&cli.BoolFlag{
+				Name:  "remove",
+				Usage: "unblock the task from the specified tasks instead",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd, conf)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
@@ -0,0 +1,88 @@
+package block
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/clitest"
+)
+
+func TestExecute(t *testing.T) {
+	srv := clitest.NewServer(t, "Buy milk", "Walk the dog", "Take over the world")
+	var out bytes.Buffer
+	e := &Executor{
+		SockFile:   clitest.Address,
+		NewService: srv.NewTaskService,
+		Stdout:     &out,
+		TaskID:     "3",
+		BlockerIDs: []string{"1", "2", "1"},
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	if want := "#3 [ ] Take over the world (blocked)\n"; out.String() != want {
+		t.Errorf("want output: %q; got: %q", want, out.String())
+	}
+	task, err := srv.DB.Get(t.Context(), "3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"1", "2"}; !slices.Equal(task.DependsOn, want) {
+		t.Errorf("want dependencies %q; got: %q", want, task.DependsOn)
+	}
+
+	out.Reset()
+	e.BlockerIDs, e.Remove = []string{"1"}, true
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	if task, err = srv.DB.Get(t.Context(), "3"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"2"}; !slices.Equal(task.DependsOn, want) {
+		t.Errorf("want dependencies %q; got: %q", want, task.DependsOn)
+	}
+}
+
+func TestExecuteNotFound(t *testing.T) {
+	srv := clitest.NewServer(t, "Buy milk", "Walk the dog")
+	e := &Executor{
+		SockFile:   clitest.Address,
+		NewService: srv.NewTaskService,
+		Stdout:     &bytes.Buffer{},
+		TaskID:     "2",
+		BlockerIDs: []string{"42"},
+	}
+	if err := e.Execute(t.Context()); status.Code(err) != codes.NotFound {
+		t.Errorf("want error with code %s; got: %v", codes.NotFound, err)
+	}
+	task, err := srv.DB.Get(t.Context(), "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(task.DependsOn) > 0 {
+		t.Errorf("want no dependencies if a blocker is missing; got: %q", task.DependsOn)
+	}
+}
+
+func TestExecuteCycle(t *testing.T) {
+	srv := clitest.NewServer(t, "Buy milk", "Walk the dog")
+	e := &Executor{
+		SockFile:   clitest.Address,
+		NewService: srv.NewTaskService,
+		Stdout:     &bytes.Buffer{},
+		TaskID:     "1",
+		BlockerIDs: []string{"2"},
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	e.TaskID, e.BlockerIDs = "2", []string{"1"}
+	if err := e.Execute(t.Context()); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("want error with code %s for a cycle; got: %v", codes.InvalidArgument, err)
+	}
+}
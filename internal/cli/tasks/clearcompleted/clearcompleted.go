@@ -0,0 +1,133 @@
+// Package clearcompleted implements the 'clear-completed' subcommand of the
+// To-do Daemon CLI's 'tasks' command.
+//
+// The 'clear-completed' subcommand moves all completed tasks, or only those
+// completed a while ago, from the to-do list to the trash with a single
+// request. With --archive, it also writes the removed tasks to a file in the
+// format of backups.
+package clearcompleted
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+	"github.com/mwopitz/todo-daemon/internal/standalone"
+)
+
+// Executor is used for executing the 'clear-completed' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewService creates the service that the command operates on: a client
+	// connected to the To-do Daemon server or, in standalone mode, the to-do
+	// list opened in-process.
+	NewService client.TaskServiceFactory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+	// OlderThan, if non-zero, restricts the removal to the tasks that were
+	// completed at least this long ago.
+	OlderThan time.Duration
+	// ArchivePath, if non-empty, is the path of the file to write a snapshot
+	// of the removed tasks to.
+	ArchivePath string
+}
+
+// NewExecutor creates an executor for the specified 'clear-completed'
+// command.
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	olderThan := cmd.Duration("older-than")
+	if olderThan < 0 {
+		return nil, exitcode.NewUsageError("invalid duration '%s': must not be negative", olderThan)
+	}
+	return &Executor{
+		SockFile:    cmd.String("sock"),
+		Timeout:     cmd.Duration("timeout"),
+		NewService:  standalone.ServiceFactory(cmd.Bool("standalone"), conf),
+		Stdout:      cmd.Root().Writer,
+		Quiet:       cmd.Bool("quiet"),
+		OlderThan:   olderThan,
+		ArchivePath: cmd.String("archive"),
+	}, nil
+}
+
+// Execute executes the 'clear-completed' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewService(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	resp, err := c.PurgeCompleted(ctx, e.OlderThan, e.ArchivePath != "")
+	if err != nil {
+		return err
+	}
+	removed := resp.GetTasks()
+	if e.ArchivePath != "" && len(removed) > 0 {
+		if err := os.WriteFile(e.ArchivePath, resp.GetArchive(), 0o600); err != nil {
+			return fmt.Errorf("cannot write archive, the tasks are in the trash: %w", err)
+		}
+	}
+	switch {
+	case e.Quiet:
+		return nil
+	case len(removed) == 0:
+		// revive:disable-next-line:unhandled-error
+		fmt.Fprintln(e.Stdout, i18n.Translate("No completed tasks to remove"))
+		return nil
+	}
+	if err := clifmt.PrintTasks(e.Stdout, removed); err != nil {
+		return err
+	}
+	if e.ArchivePath != "" {
+		// revive:disable-next-line:unhandled-error
+		fmt.Fprintln(e.Stdout, i18n.Sprintf("Archived %d tasks to %s", len(removed), e.ArchivePath))
+	}
+	return nil
+}
+
+// NewCommand creates a new 'clear-completed' command with the specified
+// configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "clear-completed",
+		Usage: "Moves all completed tasks from the to-do list to the trash",
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  "older-than",
+				Usage: "only remove the tasks that were completed at least this long ago, e.g. 168h",
+			},
+			&cli.StringFlag{
+				Name:  "archive",
+				Usage: "also write the removed tasks to this file, in the format of backups",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd, conf)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
@@ -0,0 +1,95 @@
+package clearcompleted
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/clitest"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// complete marks the task with the specified ID as completed at the specified
+// time.
+func complete(t *testing.T, srv *clitest.Server, id string, at time.Time) {
+	t.Helper()
+	if _, err := srv.DB.Update(t.Context(), id, &todo.TaskUpdate{CompletedAt: &at}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	srv := clitest.NewServer(t, "Buy milk", "Walk the dog", "Take over the world")
+	now := time.Now()
+	complete(t, srv, "1", now.Add(-48*time.Hour))
+	complete(t, srv, "3", now)
+	var out bytes.Buffer
+	e := &Executor{
+		SockFile:    clitest.Address,
+		NewService:  srv.NewTaskService,
+		Stdout:      &out,
+		OlderThan:   24 * time.Hour,
+		ArchivePath: filepath.Join(t.TempDir(), "archive.json"),
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	if want := "#1 [✓] Buy milk\nArchived 1 tasks to " + e.ArchivePath + "\n"; out.String() != want {
+		t.Errorf("want output: %q; got: %q", want, out.String())
+	}
+	if _, err := srv.DB.Get(t.Context(), "1"); !todo.IsTaskNotFoundError(err) {
+		t.Errorf("want task 1 to be removed; got: %v", err)
+	}
+	if _, err := srv.DB.Get(t.Context(), "3"); err != nil {
+		t.Errorf("want recently completed task 3 to be kept; got: %v", err)
+	}
+	f, err := os.Open(e.ArchivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	snapshot, err := todo.ReadSnapshot(f)
+	if err != nil {
+		t.Fatalf("invalid archive: %v", err)
+	}
+	if len(snapshot.Tasks) != 1 || snapshot.Tasks[0].Summary != "Buy milk" {
+		t.Errorf("want archive with task 1; got: %+v", snapshot.Tasks)
+	}
+}
+
+func TestExecuteNothingCompleted(t *testing.T) {
+	srv := clitest.NewServer(t, "Buy milk")
+	var out bytes.Buffer
+	e := &Executor{
+		SockFile:    clitest.Address,
+		NewService:  srv.NewTaskService,
+		Stdout:      &out,
+		ArchivePath: filepath.Join(t.TempDir(), "archive.json"),
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	if want := "No completed tasks to remove\n"; out.String() != want {
+		t.Errorf("want output: %q; got: %q", want, out.String())
+	}
+	if _, err := os.Stat(e.ArchivePath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("want no archive without removed tasks; got: %v", err)
+	}
+}
+
+func TestExecuteArchiveError(t *testing.T) {
+	srv := clitest.NewServer(t, "Buy milk")
+	complete(t, srv, "1", time.Now())
+	e := &Executor{
+		SockFile:    clitest.Address,
+		NewService:  srv.NewTaskService,
+		Stdout:      &bytes.Buffer{},
+		ArchivePath: filepath.Join(t.TempDir(), "missing", "archive.json"),
+	}
+	if err := e.Execute(t.Context()); err == nil {
+		t.Error("want error if the archive cannot be written")
+	}
+}
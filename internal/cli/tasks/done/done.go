@@ -6,42 +6,63 @@ package done
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"io"
 	"log/slog"
-	"os"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
 	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
 	"github.com/mwopitz/todo-daemon/internal/client"
 	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/standalone"
 )
 
 // Executor is used for executing the 'done' command.
 type Executor struct {
-	// SockFile is the path to the Unix socket file used for connecting to the
-	// To-do Daemon server and creating a new task.
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server and creating a new task.
 	SockFile string
-	// TaskID is the ID of the to-do list task to be completed.
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewService creates the service that the command operates on: a client
+	// connected to the To-do Daemon server or, in standalone mode, the to-do
+	// list opened in-process.
+	NewService client.TaskServiceFactory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+	// List specifies whether to print the entire to-do list instead of just
+	// the completed task.
+	List bool
+	// TaskID is the ID or short code of the to-do list task to be completed.
 	TaskID string
 }
 
 // NewExecutor creates an executor for the specified 'done' command.
-func NewExecutor(cmd *cli.Command) (*Executor, error) {
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
 	taskID := cmd.StringArg("id")
 	if taskID == "" {
-		return nil, errors.New("no task ID specified")
+		return nil, exitcode.NewUsageError("no task ID specified")
 	}
 	return &Executor{
-		SockFile: cmd.String("sock"),
-		TaskID:   taskID,
+		SockFile:   cmd.String("sock"),
+		Timeout:    cmd.Duration("timeout"),
+		NewService: standalone.ServiceFactory(cmd.Bool("standalone"), conf),
+		Stdout:     cmd.Root().Writer,
+		TaskID:     taskID,
+		Quiet:      cmd.Bool("quiet"),
+		List:       cmd.Bool("list"),
 	}, nil
 }
 
 // Execute executes the 'done' command.
 func (e *Executor) Execute(ctx context.Context) error {
-	c, err := client.New("unix", e.SockFile)
+	c, err := e.NewService(e.SockFile, client.WithTimeout(e.Timeout))
 	if err != nil {
 		return err
 	}
@@ -51,29 +72,45 @@ func (e *Executor) Execute(ctx context.Context) error {
 		}
 	}()
 
-	_, err = c.CompleteTask(ctx, e.TaskID)
+	task, err := c.ResolveTask(ctx, e.TaskID)
 	if err != nil {
 		return fmt.Errorf("cannot complete task: %w", err)
 	}
+	completed, err := c.CompleteTask(ctx, task.GetId())
+	if err != nil {
+		return fmt.Errorf("cannot complete task: %w", err)
+	}
+	switch {
+	case e.Quiet:
+		return nil
+	case !e.List:
+		return clifmt.PrintTasks(e.Stdout, []*todopb.Task{completed})
+	}
 
 	tasks, err := c.ListTasks(ctx)
 	if err != nil {
 		return fmt.Errorf("cannot retrieve tasks: %w", err)
 	}
 
-	return clifmt.PrintTasks(os.Stdout, tasks)
+	return clifmt.PrintTasks(e.Stdout, tasks)
 }
 
 // NewCommand creates a new 'done' command with the specified configuration.
-func NewCommand(_ *config.Config) *cli.Command {
+func NewCommand(conf *config.Config) *cli.Command {
 	return &cli.Command{
 		Name:  "done",
 		Usage: "Marks a task in the to-do list as done",
 		Arguments: []cli.Argument{
 			&cli.StringArg{Name: "id"},
 		},
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "list",
+				Usage: "print the entire to-do list instead of just the completed task",
+			},
+		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
-			e, err := NewExecutor(cmd)
+			e, err := NewExecutor(cmd, conf)
 			if err != nil {
 				return err
 			}
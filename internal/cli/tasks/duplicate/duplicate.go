@@ -0,0 +1,135 @@
+// Package duplicate implements the 'duplicate' subcommand of the To-do Daemon
+// CLI's 'tasks' command.
+//
+// The 'duplicate' subcommand creates copies of a task, e.g. for work that
+// recurs irregularly, optionally with a suffix appended to their summaries and
+// with their due times shifted.
+package duplicate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/standalone"
+)
+
+// Executor is used for executing the 'duplicate' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewService creates the service that the command operates on: a client
+	// connected to the To-do Daemon server or, in standalone mode, the to-do
+	// list opened in-process.
+	NewService client.TaskServiceFactory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+	// TaskID is the ID or short code of the task to be copied.
+	TaskID string
+	// Count is the number of copies to create. The server refuses to create
+	// more than 100 at once.
+	Count int
+	// SummarySuffix is appended to the summary of each copy.
+	SummarySuffix string
+	// DueShift is the time between the due times of the task and its first
+	// copy, and between the due times of consecutive copies.
+	DueShift time.Duration
+}
+
+// NewExecutor creates an executor for the specified 'duplicate' command.
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	taskID := cmd.StringArg("id")
+	if taskID == "" {
+		return nil, exitcode.NewUsageError("no task ID specified")
+	}
+	count := cmd.Int("count")
+	if count < 1 {
+		return nil, exitcode.NewUsageError("--count must be at least 1")
+	}
+	return &Executor{
+		SockFile:      cmd.String("sock"),
+		Timeout:       cmd.Duration("timeout"),
+		NewService:    standalone.ServiceFactory(cmd.Bool("standalone"), conf),
+		Stdout:        cmd.Root().Writer,
+		Quiet:         cmd.Bool("quiet"),
+		TaskID:        taskID,
+		Count:         count,
+		SummarySuffix: cmd.String("summary-suffix"),
+		DueShift:      cmd.Duration("shift"),
+	}, nil
+}
+
+// Execute executes the 'duplicate' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewService(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	task, err := c.ResolveTask(ctx, e.TaskID)
+	if err != nil {
+		return fmt.Errorf("cannot duplicate task: %w", err)
+	}
+	copies, err := c.DuplicateTask(ctx, task.GetId(), e.Count, e.SummarySuffix, e.DueShift)
+	if err != nil {
+		return err
+	}
+	if e.Quiet {
+		return nil
+	}
+	return clifmt.PrintTasks(e.Stdout, copies)
+}
+
+// NewCommand creates a new 'duplicate' command with the specified
+// configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:      "duplicate",
+		Aliases:   []string{"copy"},
+		Usage:     "Create copies of a task",
+		UsageText: "todo-daemon tasks duplicate [--count <n>] [--summary-suffix <text>] [--shift <duration>] <id>",
+		Arguments: []cli.Argument{
+			&cli.StringArg{Name: "id"},
+		},
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "count",
+				Usage: "the number of copies to create",
+				Value: 1,
+			},
+			&cli.StringFlag{
+				Name:  "summary-suffix",
+				Usage: "the text to append to the summary of each copy, e.g. ' (copy)'",
+			},
+			&cli.DurationFlag{
+				Name:  "shift",
+				Usage: "the time to shift the due time of each copy by, relative to the previous one, e.g. 168h",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd, conf)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
@@ -0,0 +1,83 @@
+package duplicate
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/clitest"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+func TestExecute(t *testing.T) {
+	srv := clitest.NewServer(t, "Water the plants")
+	due := time.Date(2030, 6, 3, 9, 0, 0, 0, time.Local)
+	if _, err := srv.DB.Update(t.Context(), "1", &todo.TaskUpdate{DueAt: &due}); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	e := &Executor{
+		SockFile:      clitest.Address,
+		NewService:    srv.NewTaskService,
+		Stdout:        &out,
+		TaskID:        "1",
+		Count:         2,
+		SummarySuffix: " (copy)",
+		DueShift:      7 * 24 * time.Hour,
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	want := "#2 [ ] Water the plants (copy) (due 2030-06-10 09:00)\n" +
+		"#3 [ ] Water the plants (copy) (due 2030-06-17 09:00)\n"
+	if out.String() != want {
+		t.Errorf("want output: %q; got: %q", want, out.String())
+	}
+	for i, id := range []string{"2", "3"} {
+		task, err := srv.DB.Get(t.Context(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wantDue := due.AddDate(0, 0, 7*(i+1)); !task.DueAt.Equal(wantDue) {
+			t.Errorf("want copy %s due at %v; got: %v", id, wantDue, task.DueAt)
+		}
+	}
+}
+
+func TestExecuteInvalidCount(t *testing.T) {
+	srv := clitest.NewServer(t, "Water the plants")
+	e := &Executor{
+		SockFile:   clitest.Address,
+		NewService: srv.NewTaskService,
+		Stdout:     &bytes.Buffer{},
+		TaskID:     "1",
+		Count:      101,
+	}
+	if err := e.Execute(t.Context()); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("want error with code %s; got: %v", codes.InvalidArgument, err)
+	}
+	tasks, err := srv.DB.List(t.Context(), &todo.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 {
+		t.Errorf("want no copies; got %d tasks", len(tasks))
+	}
+}
+
+func TestExecuteNotFound(t *testing.T) {
+	srv := clitest.NewServer(t, "Water the plants")
+	e := &Executor{
+		SockFile:   clitest.Address,
+		NewService: srv.NewTaskService,
+		Stdout:     &bytes.Buffer{},
+		TaskID:     "42",
+		Count:      1,
+	}
+	if err := e.Execute(t.Context()); status.Code(err) != codes.NotFound {
+		t.Errorf("want error with code %s; got: %v", codes.NotFound, err)
+	}
+}
@@ -2,39 +2,240 @@
 // 'tasks' command.
 //
 // The 'list' subcommand prints the tasks available in the to-do list to
-// standard output.
+// standard output. With the --watch flag, it keeps printing the changes to the
+// tasks until it is interrupted.
 package list
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
-	"os"
+	"math"
+	"slices"
+	"time"
 
 	"github.com/urfave/cli/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
 	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
 	"github.com/mwopitz/todo-daemon/internal/client"
 	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/standalone"
 )
 
+const (
+	watchModeRedraw = "redraw"
+	watchModeAppend = "append"
+)
+
+const (
+	dueToday   = "today"
+	dueWeek    = "week"
+	dueOverdue = "overdue"
+)
+
+const (
+	statusOpen      = "open"
+	statusCompleted = "completed"
+)
+
+const (
+	outputFormatText = "text"
+	// outputFormatPorcelain is the stable, tab-separated format for scripts.
+	outputFormatPorcelain = "porcelain"
+)
+
+var sortFields = map[string]todopb.ListTasksRequest_SortBy{
+	"created": todopb.ListTasksRequest_SORT_BY_UNSPECIFIED,
+	"due":     todopb.ListTasksRequest_SORT_BY_DUE,
+	"updated": todopb.ListTasksRequest_SORT_BY_UPDATED,
+	"manual":  todopb.ListTasksRequest_SORT_BY_POSITION,
+}
+
+var groupFields = []string{clifmt.GroupByTag, clifmt.GroupByProject, clifmt.GroupByDue}
+
+// clearScreen is the ANSI escape sequence for moving the cursor to the top
+// left corner and clearing the terminal.
+const clearScreen = "\033[H\033[2J"
+
 // Executor is used for executing the 'list' command.
 type Executor struct {
-	// SockFile is the path to the Unix socket file used for connecting to the
-	// To-do Daemon server and creating a new task.
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server and creating a new task.
 	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewService creates the service that the command operates on: a client
+	// connected to the To-do Daemon server or, in standalone mode, the to-do
+	// list opened in-process.
+	NewService client.TaskServiceFactory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Watch specifies whether to keep printing the changes to the tasks.
+	Watch bool
+	// WatchMode specifies how the changes are printed: "redraw" reprints the
+	// whole list, "append" prints one line per change.
+	WatchMode string
+	// Due selects the tasks to print by their due time: "today", "week", or
+	// "overdue". If empty, all tasks are printed.
+	Due string
+	// Status selects the tasks to print by their completion state: "open" or
+	// "completed". If empty, all tasks are printed.
+	Status string
+	// Tags selects the tasks to print that have all of these tags.
+	Tags []string
+	// Project selects the tasks to print that belong to this project.
+	Project string
+	// SortBy is the field that the tasks are sorted by: "created", "due",
+	// "updated", or "manual".
+	SortBy string
+	// Reverse sorts the tasks in descending order.
+	Reverse bool
+	// Offset is the number of tasks to skip before printing.
+	Offset uint32
+	// Limit is the maximum number of tasks to print. Zero means no limit.
+	Limit uint32
+	// Starred selects only the starred tasks.
+	Starred bool
+	// Assignee selects the tasks to print that are assigned to this user.
+	Assignee string
+	// Filter is the name of a saved filter that selects the tasks to print,
+	// in addition to the other criteria.
+	Filter string
+	// GroupBy is the field that the printed tasks are grouped by: "tag",
+	// "project", or "due". If empty, the tasks are not grouped.
+	GroupBy string
+	// OutputFormat specifies the format for printing the tasks: "text" or
+	// "porcelain".
+	OutputFormat string
 }
 
 // NewExecutor creates an executor for the specified 'list' command.
-func NewExecutor(cmd *cli.Command) (*Executor, error) {
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	if cmd.Bool("watch") && cmd.Bool("standalone") {
+		return nil, exitcode.NewUsageError("--watch cannot be used in standalone mode")
+	}
+	mode := cmd.String("watch-mode")
+	if mode != watchModeRedraw && mode != watchModeAppend {
+		return nil, exitcode.NewUsageError("invalid watch mode: %s", mode)
+	}
+	due := cmd.String("due")
+	if due != "" && due != dueToday && due != dueWeek && due != dueOverdue {
+		return nil, exitcode.NewUsageError("invalid due filter: %s", due)
+	}
+	status := cmd.String("status")
+	if status != "" && status != statusOpen && status != statusCompleted {
+		return nil, exitcode.NewUsageError("invalid status filter: %s", status)
+	}
+	sortBy := cmd.String("sort")
+	if _, ok := sortFields[sortBy]; !ok {
+		return nil, exitcode.NewUsageError("invalid sort field: %s", sortBy)
+	}
+	groupBy := cmd.String("group-by")
+	if groupBy != "" && !slices.Contains(groupFields, groupBy) {
+		return nil, exitcode.NewUsageError("invalid group field: %s", groupBy)
+	}
+	offset := cmd.Int("offset")
+	if offset < 0 || offset > math.MaxUint32 {
+		return nil, exitcode.NewUsageError("invalid offset: %d", offset)
+	}
+	limit := cmd.Int("limit")
+	if limit < 0 || limit > math.MaxUint32 {
+		return nil, exitcode.NewUsageError("invalid limit: %d", limit)
+	}
+	format := cmd.String("format")
+	if cmd.Bool("porcelain") {
+		if cmd.IsSet("format") && format != outputFormatPorcelain {
+			return nil, exitcode.NewUsageError("--porcelain cannot be used with --format %s", format)
+		}
+		format = outputFormatPorcelain
+	}
+	switch {
+	case format != outputFormatText && format != outputFormatPorcelain:
+		return nil, exitcode.NewUsageError("invalid output format: %s", format)
+	case format == outputFormatPorcelain && cmd.Bool("watch"):
+		return nil, exitcode.NewUsageError("--watch cannot be used with the porcelain format")
+	case format == outputFormatPorcelain && groupBy != "":
+		return nil, exitcode.NewUsageError("--group-by cannot be used with the porcelain format")
+	}
 	return &Executor{
-		SockFile: cmd.String("sock"),
+		SockFile:     cmd.String("sock"),
+		Timeout:      cmd.Duration("timeout"),
+		NewService:   standalone.ServiceFactory(cmd.Bool("standalone"), conf),
+		Stdout:       cmd.Root().Writer,
+		Watch:        cmd.Bool("watch"),
+		WatchMode:    mode,
+		Due:          due,
+		Status:       status,
+		Tags:         cmd.StringSlice("tag"),
+		Project:      cmd.String("project"),
+		SortBy:       sortBy,
+		Reverse:      cmd.Bool("reverse"),
+		Offset:       uint32(offset),
+		Limit:        uint32(limit),
+		Starred:      cmd.Bool("starred"),
+		Assignee:     cmd.String("assignee"),
+		Filter:       cmd.String("filter"),
+		GroupBy:      groupBy,
+		OutputFormat: format,
 	}, nil
 }
 
+// now returns the current time in the time zone that the tasks are printed
+// in, which is also the time zone that days begin in.
+func (e *Executor) now() time.Time {
+	return time.Now().In(clifmt.Location(e.Stdout))
+}
+
+// filter returns the filter for the tasks to print.
+func (e *Executor) filter(now time.Time) *todopb.ListTasksRequest {
+	req := &todopb.ListTasksRequest{
+		Tags:       e.Tags,
+		Project:    e.Project,
+		SortBy:     sortFields[e.SortBy],
+		Descending: e.Reverse,
+		Offset:     e.Offset,
+		Limit:      e.Limit,
+		Starred:    e.Starred,
+		Assignee:   e.Assignee,
+		Filter:     e.Filter,
+	}
+	switch e.Status {
+	case statusOpen:
+		req.Completion = todopb.ListTasksRequest_COMPLETION_OPEN
+	case statusCompleted:
+		req.Completion = todopb.ListTasksRequest_COMPLETION_COMPLETED
+	}
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch e.Due {
+	case dueToday:
+		req.DueBefore = timestamppb.New(startOfDay.AddDate(0, 0, 1))
+	case dueWeek:
+		req.DueBefore = timestamppb.New(startOfDay.AddDate(0, 0, 7))
+	case dueOverdue:
+		req.Overdue = true
+	}
+	return req
+}
+
+// filtered checks if the tasks to print are filtered, sorted, or limited in
+// any way, so changes to the tasks cannot simply be applied to the list.
+func (e *Executor) filtered() bool {
+	return e.Due != "" || e.Status != "" || len(e.Tags) > 0 || e.Project != "" || e.Starred ||
+		e.Assignee != "" || e.Filter != "" || e.SortBy != "created" || e.Reverse || e.Offset > 0 || e.Limit > 0
+}
+
 // Execute executes the 'list' command.
 func (e *Executor) Execute(ctx context.Context) error {
-	c, err := client.New("unix", e.SockFile)
+	c, err := e.NewService(e.SockFile, client.WithTimeout(e.Timeout))
 	if err != nil {
 		return err
 	}
@@ -44,21 +245,230 @@ func (e *Executor) Execute(ctx context.Context) error {
 		}
 	}()
 
-	tasks, err := c.ListTasks(ctx)
+	if !e.Watch {
+		tasks, err := c.FindTasks(ctx, e.filter(e.now()))
+		if err != nil {
+			return fmt.Errorf("cannot retrieve tasks: %w", err)
+		}
+		return e.print(tasks)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, tasks, err := e.watch(ctx, c)
+	if err != nil {
+		return err
+	}
+	if e.WatchMode == watchModeRedraw {
+		if err := e.redraw(tasks); err != nil {
+			return err
+		}
+	} else if err := e.print(tasks); err != nil {
+		return err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) || status.Code(err) == codes.Canceled {
+			return nil
+		}
+		if code := status.Code(err); code == codes.Unavailable || code == codes.ResourceExhausted {
+			// The server has handed over to a new instance, see 'run
+			// --takeover', or is gone, in which case reconnecting fails. It
+			// also ends the stream if this client has fallen behind, see 'run
+			// --watch-overflow', in which case the tasks are fetched again.
+			slog.Info("lost connection to server, reconnecting...", "cause", err)
+			if stream, tasks, err = e.watch(ctx, c); err != nil {
+				return err
+			}
+			if e.WatchMode == watchModeRedraw {
+				if err := e.redraw(tasks); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("cannot watch tasks: %w", err)
+		}
+		if e.WatchMode == watchModeAppend {
+			if err := clifmt.PrintTaskEvent(e.Stdout, event); err != nil {
+				return err
+			}
+			continue
+		}
+		// Whether a changed task matches the filter, and where it goes in the
+		// list, is up to the server, so fetch the filtered tasks again instead
+		// of applying the event.
+		if !e.filtered() {
+			tasks = applyEvent(tasks, event)
+		} else if tasks, err = c.FindTasks(ctx, e.filter(e.now())); err != nil {
+			return fmt.Errorf("cannot retrieve tasks: %w", err)
+		}
+		if err := e.redraw(tasks); err != nil {
+			return err
+		}
+	}
+}
+
+// watch starts watching the tasks and then retrieves them, so no change gets
+// lost.
+func (e *Executor) watch(
+	ctx context.Context,
+	c client.TaskService,
+) (grpc.ServerStreamingClient[todopb.TaskEvent], []*todopb.Task, error) {
+	stream, err := c.WatchTasks(ctx)
 	if err != nil {
-		return fmt.Errorf("cannot retrieve tasks: %w", err)
+		return nil, nil, fmt.Errorf("cannot watch tasks: %w", err)
 	}
+	tasks, err := c.FindTasks(ctx, e.filter(e.now()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot retrieve tasks: %w", err)
+	}
+	return stream, tasks, nil
+}
 
-	return clifmt.PrintTasks(os.Stdout, tasks)
+// applyEvent applies the change described by the specified event to the list
+// of tasks, keeping the starred tasks first like the server does.
+func applyEvent(tasks []*todopb.Task, event *todopb.TaskEvent) []*todopb.Task {
+	task := event.GetTask()
+	i := slices.IndexFunc(tasks, func(t *todopb.Task) bool {
+		return t.GetId() == task.GetId()
+	})
+	switch {
+	case event.GetType() == todopb.TaskEvent_TYPE_DELETED:
+		if i >= 0 {
+			tasks = slices.Delete(tasks, i, i+1)
+		}
+	case i >= 0:
+		tasks[i] = task
+	default:
+		tasks = append(tasks, task)
+	}
+	slices.SortStableFunc(tasks, func(a, b *todopb.Task) int {
+		if c := -compareBool(a.GetStarred(), b.GetStarred()); c != 0 {
+			return c
+		}
+		if c := a.GetCreatedAt().AsTime().Compare(b.GetCreatedAt().AsTime()); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.GetId(), b.GetId())
+	})
+	return tasks
+}
+
+// compareBool compares two booleans, false being less than true.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// print prints the specified tasks in the output format, grouped if requested.
+func (e *Executor) print(tasks []*todopb.Task) error {
+	if e.OutputFormat == outputFormatPorcelain {
+		return clifmt.PrintTasksPorcelain(e.Stdout, tasks)
+	}
+	if e.GroupBy == "" {
+		return clifmt.PrintTasks(e.Stdout, tasks)
+	}
+	groups, err := clifmt.GroupTasks(tasks, e.GroupBy, e.now())
+	if err != nil {
+		return err
+	}
+	return clifmt.PrintTaskGroups(e.Stdout, groups)
+}
+
+// redraw clears the terminal and prints the specified tasks.
+func (e *Executor) redraw(tasks []*todopb.Task) error {
+	if _, err := io.WriteString(e.Stdout, clearScreen); err != nil {
+		return err
+	}
+	return e.print(tasks)
 }
 
 // NewCommand creates a new 'list' command with the specified configuration.
-func NewCommand(_ *config.Config) *cli.Command {
+func NewCommand(conf *config.Config) *cli.Command {
 	return &cli.Command{
 		Name:  "list",
 		Usage: "Print all tasks in the to-do list",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "watch",
+				Aliases: []string{"w"},
+				Usage:   "keep printing the changes to the tasks until interrupted",
+			},
+			&cli.StringFlag{
+				Name:  "due",
+				Usage: "only print the tasks that are due (today, week, or overdue)",
+			},
+			&cli.StringFlag{
+				Name:  "status",
+				Usage: "only print the tasks with this status (open or completed)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "tag",
+				Usage: "only print the tasks with this tag (can be repeated)",
+			},
+			&cli.StringFlag{
+				Name:  "project",
+				Usage: "only print the tasks of this project",
+			},
+			&cli.BoolFlag{
+				Name:  "starred",
+				Usage: "only print the starred tasks",
+			},
+			&cli.StringFlag{
+				Name:  "assignee",
+				Usage: "only print the tasks assigned to this user",
+			},
+			&cli.StringFlag{
+				Name:  "filter",
+				Usage: "only print the tasks selected by this saved filter, see 'filters add'",
+			},
+			&cli.StringFlag{
+				Name:  "sort",
+				Usage: "the field to sort the tasks by (created, due, updated, or manual)",
+				Value: "created",
+			},
+			&cli.BoolFlag{
+				Name:  "reverse",
+				Usage: "sort the tasks in descending order",
+			},
+			&cli.StringFlag{
+				Name:  "group-by",
+				Usage: "print the tasks under headers by this field (tag, project, or due)",
+			},
+			&cli.IntFlag{
+				Name:  "offset",
+				Usage: "the number of tasks to skip, e.g. to print the next page",
+			},
+			&cli.IntFlag{
+				Name:  "limit",
+				Usage: "the maximum number of tasks to print",
+			},
+			&cli.StringFlag{
+				Name:  "watch-mode",
+				Usage: "how to print the changes (redraw or append)",
+				Value: watchModeRedraw,
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "the output format (text or porcelain)",
+				Value: outputFormatText,
+			},
+			&cli.BoolFlag{
+				Name:  "porcelain",
+				Usage: "short for --format porcelain",
+			},
+		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
-			e, err := NewExecutor(cmd)
+			e, err := NewExecutor(cmd, conf)
 			if err != nil {
 				return err
 			}
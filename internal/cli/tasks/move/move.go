@@ -0,0 +1,161 @@
+// Package move implements the 'move' subcommand of the To-do Daemon CLI's
+// 'tasks' command.
+//
+// The 'move' subcommand moves a task in the manual order of the to-do list,
+// right before or after another task. 'tasks list --sort manual' prints the
+// tasks in this order.
+package move
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/standalone"
+)
+
+// Executor is used for executing the 'move' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewService creates the service that the command operates on: a client
+	// connected to the To-do Daemon server or, in standalone mode, the to-do
+	// list opened in-process.
+	NewService client.TaskServiceFactory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+	// List specifies whether to print the entire to-do list in manual order
+	// instead of just the moved task.
+	List bool
+	// TaskID is the ID or short code of the task to be moved.
+	TaskID string
+	// Before is the ID or short code of the task to move the task before.
+	Before string
+	// After is the ID or short code of the task to move the task after.
+	After string
+}
+
+// NewExecutor creates an executor for the specified 'move' command.
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	taskID := cmd.StringArg("id")
+	if taskID == "" {
+		return nil, exitcode.NewUsageError("no task ID specified")
+	}
+	before, after := cmd.String("before"), cmd.String("after")
+	if (before == "") == (after == "") {
+		return nil, exitcode.NewUsageError("exactly one of --before and --after must be specified")
+	}
+	return &Executor{
+		SockFile:   cmd.String("sock"),
+		Timeout:    cmd.Duration("timeout"),
+		NewService: standalone.ServiceFactory(cmd.Bool("standalone"), conf),
+		Stdout:     cmd.Root().Writer,
+		TaskID:     taskID,
+		Quiet:      cmd.Bool("quiet"),
+		List:       cmd.Bool("list"),
+		Before:     before,
+		After:      after,
+	}, nil
+}
+
+// Execute executes the 'move' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewService(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	task, err := c.ResolveTask(ctx, e.TaskID)
+	if err != nil {
+		return fmt.Errorf("cannot move task: %w", err)
+	}
+	var beforeID, afterID string
+	if e.Before != "" {
+		if beforeID, err = resolveID(ctx, c, e.Before); err != nil {
+			return fmt.Errorf("cannot move task: %w", err)
+		}
+	} else {
+		if afterID, err = resolveID(ctx, c, e.After); err != nil {
+			return fmt.Errorf("cannot move task: %w", err)
+		}
+	}
+	moved, err := c.MoveTask(ctx, task.GetId(), beforeID, afterID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case e.Quiet:
+		return nil
+	case !e.List:
+		return clifmt.PrintTasks(e.Stdout, []*todopb.Task{moved})
+	}
+
+	tasks, err := c.FindTasks(ctx, &todopb.ListTasksRequest{
+		SortBy: todopb.ListTasksRequest_SORT_BY_POSITION,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot retrieve tasks: %w", err)
+	}
+
+	return clifmt.PrintTasks(e.Stdout, tasks)
+}
+
+// resolveID resolves the specified reference to a task into the task's ID.
+func resolveID(ctx context.Context, c client.TaskService, ref string) (string, error) {
+	task, err := c.ResolveTask(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return task.GetId(), nil
+}
+
+// NewCommand creates a new 'move' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "move",
+		Usage: "Move a task in the manual order of the to-do list",
+		Arguments: []cli.Argument{
+			&cli.StringArg{Name: "id"},
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "before",
+				Usage: "the ID or short code of the task to move the task before",
+			},
+			&cli.StringFlag{
+				Name:  "after",
+				Usage: "the ID or short code of the task to move the task after",
+			},
+			&cli.BoolFlag{
+				Name:  "list",
+				Usage: "print the entire to-do list in manual order instead of just the moved task",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd, conf)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
@@ -0,0 +1,129 @@
+// Package open implements the 'open' subcommand of the To-do Daemon CLI's
+// 'tasks' command.
+//
+// The 'open' subcommand opens a link of a task in the to-do list, e.g. the
+// ticket the task is about, in the default web browser. It opens the first
+// link of the task unless the --index flag selects another one.
+package open
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/browser"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+	"github.com/mwopitz/todo-daemon/internal/standalone"
+)
+
+// Executor is used for executing the 'open' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewService creates the service that the command operates on: a client
+	// connected to the To-do Daemon server or, in standalone mode, the to-do
+	// list opened in-process.
+	NewService client.TaskServiceFactory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+	// TaskID is the ID or short code of the task whose link is opened.
+	TaskID string
+	// Index is the 1-based index of the link to open among the task's links.
+	Index int
+	// Open opens the specified URL in the default web browser.
+	Open func(url string) error
+}
+
+// NewExecutor creates an executor for the specified 'open' command.
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	taskID := cmd.StringArg("id")
+	if taskID == "" {
+		return nil, exitcode.NewUsageError("no task ID specified")
+	}
+	index := cmd.Int("index")
+	if index < 1 {
+		return nil, exitcode.NewUsageError("--index must be at least 1")
+	}
+	return &Executor{
+		SockFile:   cmd.String("sock"),
+		Timeout:    cmd.Duration("timeout"),
+		NewService: standalone.ServiceFactory(cmd.Bool("standalone"), conf),
+		Stdout:     cmd.Root().Writer,
+		Quiet:      cmd.Bool("quiet"),
+		TaskID:     taskID,
+		Index:      index,
+		Open:       browser.Open,
+	}, nil
+}
+
+// Execute executes the 'open' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewService(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	task, err := c.ResolveTask(ctx, e.TaskID)
+	if err != nil {
+		return fmt.Errorf("cannot retrieve task: %w", err)
+	}
+	links := task.GetLinks()
+	switch {
+	case len(links) == 0:
+		return errors.New("task has no links")
+	case e.Index > len(links):
+		return exitcode.NewUsageError("--index must be at most %d, the number of links of the task", len(links))
+	}
+	link := links[e.Index-1]
+	if err := e.Open(link); err != nil {
+		return fmt.Errorf("cannot open link: %w", err)
+	}
+	if !e.Quiet {
+		// revive:disable-next-line:unhandled-error
+		fmt.Fprintln(e.Stdout, i18n.Sprintf("Opened %s", link))
+	}
+	return nil
+}
+
+// NewCommand creates a new 'open' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "open",
+		Usage: "Open a link of a task in the default web browser",
+		Arguments: []cli.Argument{
+			&cli.StringArg{Name: "id"},
+		},
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "index",
+				Usage: "the number of the link to open, counting from 1 in the order shown by 'tasks show'",
+				Value: 1,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd, conf)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
@@ -0,0 +1,87 @@
+package open
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/clitest"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// newServer starts a server holding a task with two links and a task without
+// links.
+func newServer(t *testing.T) *clitest.Server {
+	t.Helper()
+	srv := clitest.NewServer(t, "Read the docs", "Buy milk")
+	links := []string{"https://example.com/docs", "https://example.com/faq"}
+	if _, err := srv.DB.Update(t.Context(), "1", &todo.TaskUpdate{Links: &links}); err != nil {
+		t.Fatal(err)
+	}
+	return srv
+}
+
+func TestExecute(t *testing.T) {
+	srv := newServer(t)
+	var out bytes.Buffer
+	var opened []string
+	e := &Executor{
+		SockFile:   clitest.Address,
+		NewService: srv.NewTaskService,
+		Stdout:     &out,
+		TaskID:     "1",
+		Index:      2,
+		Open: func(url string) error {
+			opened = append(opened, url)
+			return nil
+		},
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	if want := "Opened https://example.com/faq\n"; out.String() != want {
+		t.Errorf("want output: %q; got: %q", want, out.String())
+	}
+	if len(opened) != 1 || opened[0] != "https://example.com/faq" {
+		t.Errorf("want second link to be opened; got: %q", opened)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	errBrowser := errors.New("no browser")
+	tests := []struct {
+		name    string
+		id      string
+		index   int
+		open    error
+		wantErr func(err error) bool
+	}{
+		{"NoLinks", "2", 1, nil, func(err error) bool { return err != nil }},
+		{"IndexTooLarge", "1", 3, nil, func(err error) bool {
+			var usage *exitcode.UsageError
+			return errors.As(err, &usage)
+		}},
+		{"BrowserFails", "1", 1, errBrowser, func(err error) bool { return errors.Is(err, errBrowser) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newServer(t)
+			var out bytes.Buffer
+			e := &Executor{
+				SockFile:   clitest.Address,
+				NewService: srv.NewTaskService,
+				Stdout:     &out,
+				TaskID:     tt.id,
+				Index:      tt.index,
+				Open:       func(string) error { return tt.open },
+			}
+			if err := e.Execute(t.Context()); !tt.wantErr(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if out.Len() > 0 {
+				t.Errorf("want no output; got: %q", out.String())
+			}
+		})
+	}
+}
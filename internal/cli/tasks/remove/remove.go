@@ -1,47 +1,92 @@
 // Package remove implements the 'remove' subcommand of the To-do Daemon CLI's
 // 'tasks' command.
 //
-// The 'remove' subcommand removes a task from the to-do list.
+// The 'remove' subcommand moves one or more tasks from the to-do list to the
+// trash, from which the 'restore' subcommand brings them back. If standard
+// input is a terminal, it asks for confirmation first, unless --force is
+// specified.
 package remove
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
-	"os"
+	"strings"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
 	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
 	"github.com/mwopitz/todo-daemon/internal/client"
 	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+	"github.com/mwopitz/todo-daemon/internal/standalone"
 )
 
+// ErrCanceled is returned by [Executor.Execute] if the user doesn't confirm
+// the removal.
+var ErrCanceled = errors.New("removal canceled, no tasks were removed")
+
 // Executor is used for executing the 'remove' command.
 type Executor struct {
-	// SockFile is the path to the Unix socket file used for connecting to the
-	// To-do Daemon server and creating a new task.
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server and creating a new task.
 	SockFile string
-	// TaskID is the ID of the to-do list task to be removed.
-	TaskID string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewService creates the service that the command operates on: a client
+	// connected to the To-do Daemon server or, in standalone mode, the to-do
+	// list opened in-process.
+	NewService client.TaskServiceFactory
+	// Stdin is the reader to read the answer to the confirmation prompt from.
+	Stdin io.Reader
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Stderr is the writer that the command prints the confirmation prompt
+	// to, so it doesn't end up in piped output.
+	Stderr io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+	// List specifies whether to print the remaining to-do list instead of
+	// just the removed tasks.
+	List bool
+	// Confirm specifies whether to show the tasks and ask the user for
+	// confirmation before removing them.
+	Confirm bool
+	// TaskIDs are the IDs or short codes of the to-do list tasks to be
+	// removed.
+	TaskIDs []string
 }
 
 // NewExecutor creates an executor for the specified 'remove' command.
-func NewExecutor(cmd *cli.Command) (*Executor, error) {
-	taskID := cmd.StringArg("id")
-	if taskID == "" {
-		return nil, errors.New("no task ID specified")
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	taskIDs := cmd.StringArgs("ids")
+	if len(taskIDs) == 0 {
+		return nil, exitcode.NewUsageError("no task ID specified")
 	}
+	stdin := cmd.Root().Reader
 	return &Executor{
-		SockFile: cmd.String("sock"),
-		TaskID:   taskID,
+		SockFile:   cmd.String("sock"),
+		Timeout:    cmd.Duration("timeout"),
+		NewService: standalone.ServiceFactory(cmd.Bool("standalone"), conf),
+		Stdin:      stdin,
+		Stdout:     cmd.Root().Writer,
+		Stderr:     cmd.Root().ErrWriter,
+		TaskIDs:    taskIDs,
+		Quiet:      cmd.Bool("quiet"),
+		List:       cmd.Bool("list"),
+		Confirm:    !cmd.Bool("force") && clifmt.IsTerminal(stdin),
 	}, nil
 }
 
 // Execute executes the 'remove' command.
 func (e *Executor) Execute(ctx context.Context) error {
-	c, err := client.New("unix", e.SockFile)
+	c, err := e.NewService(e.SockFile, client.WithTimeout(e.Timeout))
 	if err != nil {
 		return err
 	}
@@ -51,29 +96,96 @@ func (e *Executor) Execute(ctx context.Context) error {
 		}
 	}()
 
-	err = c.DeleteTask(ctx, e.TaskID)
-	if err != nil {
-		return fmt.Errorf("cannot delete task: %w", err)
+	// All tasks are resolved before removing any, so a typo doesn't leave
+	// the to-do list half cleaned up.
+	var tasks []*todopb.Task
+	seen := make(map[string]bool, len(e.TaskIDs))
+	for _, ref := range e.TaskIDs {
+		task, err := c.ResolveTask(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("cannot delete task: %w", err)
+		}
+		if !seen[task.GetId()] {
+			seen[task.GetId()] = true
+			tasks = append(tasks, task)
+		}
+	}
+	if e.Confirm {
+		ok, err := e.confirm(tasks)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrCanceled
+		}
+	}
+	for _, task := range tasks {
+		if err := c.DeleteTask(ctx, task.GetId()); err != nil {
+			return fmt.Errorf("cannot delete task: %w", err)
+		}
+	}
+	switch {
+	case e.Quiet:
+		return nil
+	case !e.List:
+		return clifmt.PrintTasks(e.Stdout, tasks)
 	}
 
-	tasks, err := c.ListTasks(ctx)
+	remaining, err := c.ListTasks(ctx)
 	if err != nil {
 		return fmt.Errorf("cannot retrieve tasks: %w", err)
 	}
 
-	return clifmt.PrintTasks(os.Stdout, tasks)
+	return clifmt.PrintTasks(e.Stdout, remaining)
+}
+
+// confirm shows the specified tasks and asks the user whether to remove them.
+// Only "y" and "yes", or their translations, confirm the removal.
+func (e *Executor) confirm(tasks []*todopb.Task) (bool, error) {
+	if err := clifmt.PrintTasks(e.Stderr, tasks); err != nil {
+		return false, err
+	}
+	prompt := i18n.Translate("Remove this task? [y/N] ")
+	if len(tasks) > 1 {
+		prompt = i18n.Sprintf("Remove these %d tasks? [y/N] ", len(tasks))
+	}
+	if _, err := io.WriteString(e.Stderr, prompt); err != nil {
+		return false, err
+	}
+	answer, err := bufio.NewReader(e.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("cannot read answer: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes", i18n.Translate("y"), i18n.Translate("yes"):
+		return true, nil
+	default:
+		return false, nil
+	}
 }
 
 // NewCommand creates a new 'remove' command with the specified configuration.
-func NewCommand(_ *config.Config) *cli.Command {
+func NewCommand(conf *config.Config) *cli.Command {
 	return &cli.Command{
-		Name:  "remove",
-		Usage: "Removes a task from the to-do list",
+		Name:      "remove",
+		Usage:     "Moves tasks from the to-do list to the trash",
+		UsageText: "todo-daemon tasks remove [--force] <id>...",
 		Arguments: []cli.Argument{
-			&cli.StringArg{Name: "id"},
+			&cli.StringArgs{Name: "ids", Min: 0, Max: -1},
+		},
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "list",
+				Usage: "print the remaining to-do list instead of just the removed tasks",
+			},
+			&cli.BoolFlag{
+				Name:    "force",
+				Aliases: []string{"f"},
+				Usage:   "remove the tasks without asking for confirmation",
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
-			e, err := NewExecutor(cmd)
+			e, err := NewExecutor(cmd, conf)
 			if err != nil {
 				return err
 			}
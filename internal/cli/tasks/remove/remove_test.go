@@ -0,0 +1,94 @@
+package remove
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/clitest"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+func TestExecute(t *testing.T) {
+	srv := clitest.NewServer(t, "Buy milk", "Walk the dog", "Take over the world")
+	var out bytes.Buffer
+	e := &Executor{
+		SockFile:   clitest.Address,
+		NewService: srv.NewTaskService,
+		Stdout:     &out,
+		TaskIDs:    []string{"1", "3", "1"},
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	if want := "#1 [ ] Buy milk\n#3 [ ] Take over the world\n"; out.String() != want {
+		t.Errorf("want output: %q; got: %q", want, out.String())
+	}
+	for _, id := range []string{"1", "3"} {
+		if _, err := srv.DB.Get(t.Context(), id); !todo.IsTaskNotFoundError(err) {
+			t.Errorf("want task %s to be removed; got: %v", id, err)
+		}
+	}
+}
+
+func TestExecuteConfirm(t *testing.T) {
+	tests := []struct {
+		name    string
+		answer  string
+		ids     []string
+		prompt  string
+		removed bool
+	}{
+		{"Yes", "y\n", []string{"1"}, "Remove this task? [y/N] ", true},
+		{"YesBatch", "yes\n", []string{"1", "2"}, "Remove these 2 tasks? [y/N] ", true},
+		{"No", "n\n", []string{"1"}, "Remove this task? [y/N] ", false},
+		{"Default", "\n", []string{"1", "2"}, "Remove these 2 tasks? [y/N] ", false},
+		{"EOF", "", []string{"1"}, "Remove this task? [y/N] ", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := clitest.NewServer(t, "Buy milk", "Walk the dog")
+			var stderr bytes.Buffer
+			e := &Executor{
+				SockFile:   clitest.Address,
+				NewService: srv.NewTaskService,
+				Stdin:      strings.NewReader(tt.answer),
+				Stdout:     &bytes.Buffer{},
+				Stderr:     &stderr,
+				Confirm:    true,
+				TaskIDs:    tt.ids,
+			}
+			err := e.Execute(t.Context())
+			if tt.removed && err != nil {
+				t.Fatalf("Execute(): %v", err)
+			}
+			if !tt.removed && !errors.Is(err, ErrCanceled) {
+				t.Fatalf("want ErrCanceled; got: %v", err)
+			}
+			if !strings.HasPrefix(stderr.String(), "#1 [ ] Buy milk\n") || !strings.HasSuffix(stderr.String(), tt.prompt) {
+				t.Errorf("want tasks and prompt %q; got: %q", tt.prompt, stderr.String())
+			}
+			_, err = srv.DB.Get(t.Context(), "1")
+			if removed := todo.IsTaskNotFoundError(err); removed != tt.removed {
+				t.Errorf("want task removed: %t; got: %t", tt.removed, removed)
+			}
+		})
+	}
+}
+
+func TestExecuteNotFound(t *testing.T) {
+	srv := clitest.NewServer(t, "Buy milk")
+	e := &Executor{
+		SockFile:   clitest.Address,
+		NewService: srv.NewTaskService,
+		Stdout:     &bytes.Buffer{},
+		TaskIDs:    []string{"1", "42"},
+	}
+	if err := e.Execute(t.Context()); err == nil {
+		t.Fatal("want error for missing task")
+	}
+	if _, err := srv.DB.Get(t.Context(), "1"); err != nil {
+		t.Errorf("want no task removed if one is missing; got: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+// Package restore implements the 'restore' subcommand of the To-do Daemon CLI's
+// 'tasks' command.
+//
+// The 'restore' subcommand moves tasks that were removed by mistake from the
+// trash back to the to-do list.
+package restore
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/standalone"
+)
+
+// Executor is used for executing the 'restore' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewService creates the service that the command operates on: a client
+	// connected to the To-do Daemon server or, in standalone mode, the to-do
+	// list opened in-process.
+	NewService client.TaskServiceFactory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+	// TaskIDs are the IDs of the tasks in the trash to be restored. Short
+	// codes cannot be used, since they only refer to the tasks in the to-do
+	// list.
+	TaskIDs []string
+}
+
+// NewExecutor creates an executor for the specified 'restore' command.
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	taskIDs := cmd.StringArgs("ids")
+	if len(taskIDs) == 0 {
+		return nil, exitcode.NewUsageError("no task ID specified")
+	}
+	return &Executor{
+		SockFile:   cmd.String("sock"),
+		Timeout:    cmd.Duration("timeout"),
+		NewService: standalone.ServiceFactory(cmd.Bool("standalone"), conf),
+		Stdout:     cmd.Root().Writer,
+		Quiet:      cmd.Bool("quiet"),
+		TaskIDs:    taskIDs,
+	}, nil
+}
+
+// Execute executes the 'restore' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewService(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	restored := make([]*todopb.Task, 0, len(e.TaskIDs))
+	for _, id := range e.TaskIDs {
+		task, err := c.RestoreTask(ctx, id)
+		if err != nil {
+			return err
+		}
+		restored = append(restored, task)
+	}
+	if e.Quiet {
+		return nil
+	}
+	return clifmt.PrintTasks(e.Stdout, restored)
+}
+
+// NewCommand creates a new 'restore' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:      "restore",
+		Usage:     "Moves removed tasks from the trash back to the to-do list",
+		UsageText: "todo-daemon tasks restore <id>...",
+		Arguments: []cli.Argument{
+			&cli.StringArgs{Name: "ids", Min: 0, Max: -1},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd, conf)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
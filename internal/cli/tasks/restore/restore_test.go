@@ -0,0 +1,55 @@
+package restore
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/clitest"
+)
+
+func TestExecute(t *testing.T) {
+	srv := clitest.NewServer(t, "Buy milk", "Walk the dog", "Take over the world")
+	for _, id := range []string{"1", "3"} {
+		if err := srv.DB.Delete(t.Context(), id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var out bytes.Buffer
+	e := &Executor{
+		SockFile:   clitest.Address,
+		NewService: srv.NewTaskService,
+		Stdout:     &out,
+		TaskIDs:    []string{"3", "1"},
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	if want := "#3 [ ] Take over the world\n#1 [ ] Buy milk\n"; out.String() != want {
+		t.Errorf("want output: %q; got: %q", want, out.String())
+	}
+	for _, id := range []string{"1", "3"} {
+		if _, err := srv.DB.Get(t.Context(), id); err != nil {
+			t.Errorf("want task %s to be restored; got: %v", id, err)
+		}
+	}
+}
+
+func TestExecuteNotDeleted(t *testing.T) {
+	srv := clitest.NewServer(t, "Buy milk")
+	e := &Executor{
+		SockFile:   clitest.Address,
+		NewService: srv.NewTaskService,
+		Stdout:     &bytes.Buffer{},
+		TaskIDs:    []string{"42"},
+	}
+	if err := e.Execute(t.Context()); status.Code(err) != codes.NotFound {
+		t.Errorf("want error with code %s; got: %v", codes.NotFound, err)
+	}
+	e.TaskIDs = []string{"1"}
+	if err := e.Execute(t.Context()); err == nil {
+		t.Error("want error for a task that is not in the trash")
+	}
+}
@@ -0,0 +1,112 @@
+// Package search implements the 'search' subcommand of the To-do Daemon CLI's
+// 'tasks' command.
+//
+// The 'search' subcommand performs a full-text search across the summaries and
+// descriptions of the tasks in the to-do list and prints the matching tasks,
+// most relevant first.
+package search
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/standalone"
+)
+
+// Executor is used for executing the 'search' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewService creates the service that the command operates on: a client
+	// connected to the To-do Daemon server or, in standalone mode, the to-do
+	// list opened in-process.
+	NewService client.TaskServiceFactory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Query is the search query.
+	Query string
+	// Limit is the maximum number of tasks to print. Zero means no limit.
+	Limit uint32
+}
+
+// NewExecutor creates an executor for the specified 'search' command.
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	query := cmd.StringArg("query")
+	if query == "" {
+		return nil, exitcode.NewUsageError("no search query specified")
+	}
+	limit := cmd.Int("limit")
+	if limit < 0 || limit > math.MaxUint32 {
+		return nil, exitcode.NewUsageError("invalid limit: %d", limit)
+	}
+	return &Executor{
+		SockFile:   cmd.String("sock"),
+		Timeout:    cmd.Duration("timeout"),
+		NewService: standalone.ServiceFactory(cmd.Bool("standalone"), conf),
+		Stdout:     cmd.Root().Writer,
+		Query:      query,
+		Limit:      uint32(limit),
+	}, nil
+}
+
+// Execute executes the 'search' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewService(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	results, err := c.SearchTasks(ctx, e.Query, e.Limit)
+	if err != nil {
+		return fmt.Errorf("cannot search tasks: %w", err)
+	}
+
+	tasks := make([]*todopb.Task, len(results))
+	for i, r := range results {
+		tasks[i] = r.GetTask()
+	}
+	return clifmt.PrintTasks(e.Stdout, tasks)
+}
+
+// NewCommand creates a new 'search' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "search",
+		Usage: "Search the summaries and descriptions of the tasks in the to-do list",
+		Arguments: []cli.Argument{
+			&cli.StringArg{Name: "query"},
+		},
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "limit",
+				Usage: "maximum number of tasks to print (0 means no limit)",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd, conf)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package search
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/clitest"
+	"github.com/mwopitz/todo-daemon/internal/client"
+)
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		limit uint32
+		want  string
+	}{
+		{"All", "dog", 0, "#2 [ ] Walk the dog\n#3 [ ] Buy dog food\n"},
+		{"Limit", "dog", 1, "#2 [ ] Walk the dog\n"},
+		{"NoMatch", "cat", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := clitest.NewServer(t, "Buy milk", "Walk the dog", "Buy dog food")
+			var out bytes.Buffer
+			e := &Executor{
+				SockFile:   clitest.Address,
+				NewService: srv.NewTaskService,
+				Stdout:     &out,
+				Query:      tt.query,
+				Limit:      tt.limit,
+			}
+			if err := e.Execute(t.Context()); err != nil {
+				t.Fatalf("Execute(): %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("want output: %q; got: %q", tt.want, out.String())
+			}
+		})
+	}
+}
+
+func TestExecuteUnavailable(t *testing.T) {
+	errUnavailable := errors.New("server unavailable")
+	var out bytes.Buffer
+	e := &Executor{
+		SockFile: clitest.Address,
+		NewService: func(string, ...client.Option) (client.TaskService, error) {
+			return nil, errUnavailable
+		},
+		Stdout: &out,
+		Query:  "dog",
+	}
+	if err := e.Execute(t.Context()); !errors.Is(err, errUnavailable) {
+		t.Errorf("want %v; got: %v", errUnavailable, err)
+	}
+	if out.Len() > 0 {
+		t.Errorf("want no output; got: %q", out.String())
+	}
+}
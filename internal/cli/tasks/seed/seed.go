@@ -0,0 +1,85 @@
+// Package seed implements the 'seed' subcommand of the To-do Daemon CLI's
+// 'tasks' command.
+//
+// The 'seed' subcommand adds the demo tasks to the to-do list with a single
+// request, e.g. for trying out the CLI or the web UI during development.
+package seed
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/standalone"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// Executor is used for executing the 'seed' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewService creates the service that the command operates on: a client
+	// connected to the To-do Daemon server or, in standalone mode, the to-do
+	// list opened in-process.
+	NewService client.TaskServiceFactory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+}
+
+// NewExecutor creates an executor for the specified 'seed' command.
+func NewExecutor(cmd *cli.Command, conf *config.Config) *Executor {
+	return &Executor{
+		SockFile:   cmd.String("sock"),
+		Timeout:    cmd.Duration("timeout"),
+		NewService: standalone.ServiceFactory(cmd.Bool("standalone"), conf),
+		Stdout:     cmd.Root().Writer,
+		Quiet:      cmd.Bool("quiet"),
+	}
+}
+
+// Execute executes the 'seed' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewService(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	demo := todo.DemoTasks()
+	tasks := make([]*todopb.NewTask, 0, len(demo))
+	for _, task := range demo {
+		tasks = append(tasks, &todopb.NewTask{Summary: task.Summary})
+	}
+	created, err := c.BatchCreateTasks(ctx, tasks)
+	if err != nil || e.Quiet {
+		return err
+	}
+	return clifmt.PrintTasks(e.Stdout, created)
+}
+
+// NewCommand creates a new 'seed' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "seed",
+		Usage: "Adds some demo tasks to the to-do list",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return NewExecutor(cmd, conf).Execute(ctx)
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package show
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/clitest"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+func TestExecute(t *testing.T) {
+	srv := clitest.NewServer(t, "Buy milk", "Walk the dog")
+	description := "Around the park"
+	if _, err := srv.DB.Update(t.Context(), "2", &todo.TaskUpdate{Description: &description}); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	e := &Executor{
+		SockFile:   clitest.Address,
+		NewService: srv.NewTaskService,
+		Stdout:     &out,
+		TaskID:     "d4735e3", // the short code of task 2
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	for _, want := range []string{
+		"ID:           2\n",
+		"Summary:      Walk the dog\n",
+		"Description:  Around the park\n",
+		"Status:       open\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("want output containing %q; got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestExecuteNotFound(t *testing.T) {
+	srv := clitest.NewServer(t, "Buy milk")
+	e := &Executor{
+		SockFile:   clitest.Address,
+		NewService: srv.NewTaskService,
+		Stdout:     &bytes.Buffer{},
+		TaskID:     "42",
+	}
+	if err := e.Execute(t.Context()); status.Code(err) != codes.NotFound {
+		t.Errorf("want error with code %s; got: %v", codes.NotFound, err)
+	}
+}
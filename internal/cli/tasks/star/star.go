@@ -0,0 +1,121 @@
+// Package star implements the 'star' and 'unstar' subcommands of the To-do
+// Daemon CLI's 'tasks' command.
+//
+// The 'star' subcommand stars a task in the to-do list, which lists it before
+// the other tasks. The 'unstar' subcommand removes the star again.
+package star
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/standalone"
+)
+
+// Executor is used for executing the 'star' and 'unstar' commands.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewService creates the service that the command operates on: a client
+	// connected to the To-do Daemon server or, in standalone mode, the to-do
+	// list opened in-process.
+	NewService client.TaskServiceFactory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+	// TaskID is the ID or short code of the task to be starred or unstarred.
+	TaskID string
+	// Starred specifies whether to star the task or to remove its star.
+	Starred bool
+}
+
+// NewExecutor creates an executor for the specified 'star' or 'unstar'
+// command.
+func NewExecutor(cmd *cli.Command, conf *config.Config) (*Executor, error) {
+	taskID := cmd.StringArg("id")
+	if taskID == "" {
+		return nil, exitcode.NewUsageError("no task ID specified")
+	}
+	return &Executor{
+		SockFile:   cmd.String("sock"),
+		Timeout:    cmd.Duration("timeout"),
+		NewService: standalone.ServiceFactory(cmd.Bool("standalone"), conf),
+		Stdout:     cmd.Root().Writer,
+		Quiet:      cmd.Bool("quiet"),
+		TaskID:     taskID,
+		Starred:    cmd.Name != "unstar",
+	}, nil
+}
+
+// Execute executes the 'star' or 'unstar' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	action := "star"
+	if !e.Starred {
+		action = "unstar"
+	}
+	c, err := e.NewService(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	task, err := c.ResolveTask(ctx, e.TaskID)
+	if err != nil {
+		return fmt.Errorf("cannot %s task: %w", action, err)
+	}
+	updated, err := c.SetStarred(ctx, task.GetId(), e.Starred)
+	if err != nil {
+		return fmt.Errorf("cannot %s task: %w", action, err)
+	}
+	if e.Quiet {
+		return nil
+	}
+	return clifmt.PrintTasks(e.Stdout, []*todopb.Task{updated})
+}
+
+// NewCommand creates a new 'star' command with the specified configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return newCommand(conf, "star", "Star a task, which lists it before the other tasks")
+}
+
+// NewUnstarCommand creates a new 'unstar' command with the specified
+// configuration.
+func NewUnstarCommand(conf *config.Config) *cli.Command {
+	return newCommand(conf, "unstar", "Remove the star from a task")
+}
+
+func newCommand(conf *config.Config, name, usage string) *cli.Command {
+	return &cli.Command{
+		Name:      name,
+		Usage:     usage,
+		UsageText: "todo-daemon tasks " + name + " <id>",
+		Arguments: []cli.Argument{
+			&cli.StringArg{Name: "id"},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd, conf)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
@@ -0,0 +1,84 @@
+package star
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/clitest"
+)
+
+func TestExecute(t *testing.T) {
+	srv := clitest.NewServer(t, "Buy milk", "Walk the dog")
+	var out bytes.Buffer
+	e := &Executor{
+		SockFile:   clitest.Address,
+		NewService: srv.NewTaskService,
+		Stdout:     &out,
+		TaskID:     "1",
+		Starred:    true,
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	if want := "#1 [ ] ★ Buy milk\n"; out.String() != want {
+		t.Errorf("want output: %q; got: %q", want, out.String())
+	}
+	task, err := srv.DB.Get(t.Context(), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !task.Starred {
+		t.Error("want task to be starred")
+	}
+
+	out.Reset()
+	e.Starred = false
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	if want := "#1 [ ] Buy milk\n"; out.String() != want {
+		t.Errorf("want output: %q; got: %q", want, out.String())
+	}
+	if task, err = srv.DB.Get(t.Context(), "1"); err != nil {
+		t.Fatal(err)
+	}
+	if task.Starred {
+		t.Error("want task to be unstarred")
+	}
+}
+
+func TestExecuteQuiet(t *testing.T) {
+	srv := clitest.NewServer(t, "Buy milk")
+	var out bytes.Buffer
+	e := &Executor{
+		SockFile:   clitest.Address,
+		NewService: srv.NewTaskService,
+		Stdout:     &out,
+		TaskID:     "1",
+		Starred:    true,
+		Quiet:      true,
+	}
+	if err := e.Execute(t.Context()); err != nil {
+		t.Fatalf("Execute(): %v", err)
+	}
+	if out.Len() > 0 {
+		t.Errorf("want no output; got: %q", out.String())
+	}
+}
+
+func TestExecuteNotFound(t *testing.T) {
+	srv := clitest.NewServer(t, "Buy milk")
+	e := &Executor{
+		SockFile:   clitest.Address,
+		NewService: srv.NewTaskService,
+		Stdout:     &bytes.Buffer{},
+		TaskID:     "42",
+		Starred:    true,
+	}
+	if err := e.Execute(t.Context()); status.Code(err) != codes.NotFound {
+		t.Errorf("want error with code %s; got: %v", codes.NotFound, err)
+	}
+}
@@ -1,21 +1,38 @@
 // Package tasks implements the 'tasks' command of the To-do Daemon CLI.
 //
 // The 'tasks' command provides several subcommands for managing the tasks in
-// the to-do list.
+// the to-do list. Before running a subcommand, it adds the tasks queued by
+// 'tasks add --offline' to the to-do list if the server is reachable.
 package tasks
 
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 
 	"github.com/urfave/cli/v3"
 
+	"github.com/mwopitz/todo-daemon/internal/cli/flush"
 	"github.com/mwopitz/todo-daemon/internal/cli/tasks/add"
+	"github.com/mwopitz/todo-daemon/internal/cli/tasks/assign"
+	"github.com/mwopitz/todo-daemon/internal/cli/tasks/block"
+	"github.com/mwopitz/todo-daemon/internal/cli/tasks/clearcompleted"
 	"github.com/mwopitz/todo-daemon/internal/cli/tasks/done"
+	"github.com/mwopitz/todo-daemon/internal/cli/tasks/duplicate"
 	"github.com/mwopitz/todo-daemon/internal/cli/tasks/list"
+	"github.com/mwopitz/todo-daemon/internal/cli/tasks/move"
+	"github.com/mwopitz/todo-daemon/internal/cli/tasks/open"
 	"github.com/mwopitz/todo-daemon/internal/cli/tasks/remove"
+	"github.com/mwopitz/todo-daemon/internal/cli/tasks/restore"
+	"github.com/mwopitz/todo-daemon/internal/cli/tasks/search"
+	"github.com/mwopitz/todo-daemon/internal/cli/tasks/seed"
+	"github.com/mwopitz/todo-daemon/internal/cli/tasks/show"
+	"github.com/mwopitz/todo-daemon/internal/cli/tasks/star"
 	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+	"github.com/mwopitz/todo-daemon/internal/queue"
+	"github.com/mwopitz/todo-daemon/internal/standalone"
 )
 
 // NewCommand creates a new 'tasks' command with the specified configuration.
@@ -26,12 +43,46 @@ func NewCommand(conf *config.Config) *cli.Command {
 		Commands: []*cli.Command{
 			add.NewCommand(conf),
 			list.NewCommand(conf),
+			show.NewCommand(conf),
+			open.NewCommand(conf),
 			done.NewCommand(conf),
+			move.NewCommand(conf),
+			block.NewCommand(conf),
+			star.NewCommand(conf),
+			star.NewUnstarCommand(conf),
+			assign.NewCommand(conf),
+			assign.NewUnassignCommand(conf),
+			duplicate.NewCommand(conf),
 			remove.NewCommand(conf),
+			restore.NewCommand(conf),
+			clearcompleted.NewCommand(conf),
+			search.NewCommand(conf),
+			seed.NewCommand(conf),
+		},
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "standalone",
+				Usage:   "open the to-do list in-process instead of connecting to the server, which must not be running",
+				Sources: cli.EnvVars(config.EnvStandalone),
+			},
+		},
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			e := &flush.Executor{
+				SockFile:   cmd.String("sock"),
+				Timeout:    cmd.Duration("timeout"),
+				NewService: standalone.ServiceFactory(cmd.Bool("standalone"), conf),
+				Stdout:     cmd.Root().ErrWriter,
+				Quiet:      cmd.Bool("quiet"),
+				Queue:      queue.New(conf.QueueFile()),
+			}
+			if _, err := e.Flush(ctx); err != nil {
+				slog.Warn("cannot add queued tasks", "cause", err)
+			}
+			return ctx, nil
 		},
 		CommandNotFound: func(_ context.Context, _ *cli.Command, name string) {
 			// revive:disable-next-line:unhandled-error
-			fmt.Fprintf(os.Stderr, "todo-daemon: invalid command: '%s'\n", name)
+			fmt.Fprintf(os.Stderr, "todo-daemon: %s: '%s'\n", i18n.Translate("invalid command"), name)
 		},
 	}
 }
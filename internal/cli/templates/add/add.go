@@ -0,0 +1,176 @@
+// Package add implements the 'add' subcommand of the To-do Daemon CLI's
+// 'templates' command.
+//
+// The 'add' subcommand saves a task template on the server. The tasks of the
+// template are either given with --task, sharing the other flags, or read
+// from a JSON file with --file, in the format of the REST API.
+package add
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v3"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// Executor is used for executing the 'add' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewClient creates the client for connecting to the To-do Daemon
+	// server.
+	NewClient client.Factory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+	// Template is the template to be saved.
+	Template *todopb.Template
+}
+
+// NewExecutor creates an executor for the specified 'add' command.
+func NewExecutor(cmd *cli.Command) (*Executor, error) {
+	name := cmd.StringArg("name")
+	if name == "" {
+		return nil, exitcode.NewUsageError("no template name specified")
+	}
+	summaries := cmd.StringSlice("task")
+	path := cmd.String("file")
+	switch {
+	case len(summaries) == 0 && path == "":
+		return nil, exitcode.NewUsageError("no tasks specified, use --task or --file")
+	case len(summaries) > 0 && path != "":
+		return nil, exitcode.NewUsageError("--task and --file cannot be used together")
+	}
+	template := &todopb.Template{}
+	if path != "" {
+		b, err := os.ReadFile(path) // #nosec G304 -- the path is user-specified.
+		if err != nil {
+			return nil, fmt.Errorf("cannot read template: %w", err)
+		}
+		if err := protojson.Unmarshal(b, template); err != nil {
+			return nil, fmt.Errorf("invalid template in %s: %w", path, err)
+		}
+	}
+	dueAfter := cmd.Duration("due-after")
+	if dueAfter < 0 {
+		return nil, exitcode.NewUsageError("invalid due time: %s", dueAfter)
+	}
+	for _, summary := range summaries {
+		task := &todopb.TemplateTask{
+			Summary: summary,
+			Tags:    cmd.StringSlice("tag"),
+			Project: cmd.String("project"),
+			Starred: cmd.Bool("starred"),
+		}
+		if dueAfter > 0 {
+			task.DueAfter = durationpb.New(dueAfter)
+		}
+		template.Tasks = append(template.Tasks, task)
+	}
+	template.Name = name
+	if cmd.IsSet("schedule") {
+		template.Schedule = cmd.String("schedule")
+	}
+	return &Executor{
+		SockFile:  cmd.String("sock"),
+		Timeout:   cmd.Duration("timeout"),
+		NewClient: client.New,
+		Stdout:    cmd.Root().Writer,
+		Quiet:     cmd.Bool("quiet"),
+		Template:  template,
+	}, nil
+}
+
+// Execute executes the 'add' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewClient(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	template, err := c.CreateTemplate(ctx, e.Template)
+	if err != nil {
+		return err
+	}
+	if e.Quiet {
+		return nil
+	}
+	// revive:disable-next-line:unhandled-error
+	fmt.Fprintln(e.Stdout, i18n.Sprintf("Saved template '%s' with %d tasks", template.GetName(), len(template.GetTasks())))
+	if next := template.GetNextRunAt(); next != nil {
+		// revive:disable-next-line:unhandled-error
+		fmt.Fprintln(e.Stdout, i18n.Sprintf("It is applied next at %s", next.AsTime().In(clifmt.Location(e.Stdout)).Format(time.DateTime)))
+	}
+	return nil
+}
+
+// NewCommand creates a new 'add' command with the specified configuration.
+func NewCommand(_ *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "add",
+		Usage: "Save a task template",
+		Arguments: []cli.Argument{
+			&cli.StringArg{Name: "name"},
+		},
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:  "task",
+				Usage: "the summary of a task of the template (can be repeated)",
+			},
+			&cli.StringFlag{
+				Name:      "file",
+				Usage:     "read the template from this JSON file instead",
+				TakesFile: true,
+			},
+			&cli.StringSliceFlag{
+				Name:  "tag",
+				Usage: "a tag of each task (can be repeated)",
+			},
+			&cli.StringFlag{
+				Name:  "project",
+				Usage: "the project of each task",
+			},
+			&cli.BoolFlag{
+				Name:  "starred",
+				Usage: "star each task",
+			},
+			&cli.DurationFlag{
+				Name:  "due-after",
+				Usage: "the time between creating each task and its due time, e.g. 48h",
+			},
+			&cli.StringFlag{
+				Name:  "schedule",
+				Usage: "apply the template at the start of each day selected by this rule, e.g. FREQ=WEEKLY;BYDAY=FR",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
@@ -0,0 +1,98 @@
+// Package apply implements the 'apply' subcommand of the To-do Daemon CLI's
+// 'templates' command.
+//
+// The 'apply' subcommand creates all tasks of a template at once: either all
+// of them are added to the to-do list or none.
+package apply
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// Executor is used for executing the 'apply' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewClient creates the client for connecting to the To-do Daemon
+	// server.
+	NewClient client.Factory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+	// Quiet specifies whether to print nothing if the command succeeds.
+	Quiet bool
+	// Name is the name of the template to be applied.
+	Name string
+}
+
+// NewExecutor creates an executor for the specified 'apply' command.
+func NewExecutor(cmd *cli.Command) (*Executor, error) {
+	name := cmd.StringArg("name")
+	if name == "" {
+		return nil, exitcode.NewUsageError("no template name specified")
+	}
+	return &Executor{
+		SockFile:  cmd.String("sock"),
+		Timeout:   cmd.Duration("timeout"),
+		NewClient: client.New,
+		Stdout:    cmd.Root().Writer,
+		Quiet:     cmd.Bool("quiet"),
+		Name:      name,
+	}, nil
+}
+
+// Execute executes the 'apply' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewClient(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	tasks, err := c.ApplyTemplate(ctx, e.Name)
+	if err != nil {
+		return err
+	}
+	if e.Quiet {
+		return nil
+	}
+	// revive:disable-next-line:unhandled-error
+	fmt.Fprintln(e.Stdout, i18n.Sprintf("Created %d tasks from template '%s':", len(tasks), e.Name))
+	return clifmt.PrintTasks(e.Stdout, tasks)
+}
+
+// NewCommand creates a new 'apply' command with the specified configuration.
+func NewCommand(_ *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "apply",
+		Usage: "Create all tasks of a template",
+		Arguments: []cli.Argument{
+			&cli.StringArg{Name: "name"},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
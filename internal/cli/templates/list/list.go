@@ -0,0 +1,113 @@
+// Package list implements the 'list' subcommand of the To-do Daemon CLI's
+// 'templates' command.
+//
+// The 'list' subcommand prints the task templates saved on the server, along
+// with their schedules and the summaries of their tasks.
+package list
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	clifmt "github.com/mwopitz/todo-daemon/internal/cli/fmt"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// Executor is used for executing the 'list' command.
+type Executor struct {
+	// SockFile is the address of the Unix socket or named pipe used for
+	// connecting to the To-do Daemon server.
+	SockFile string
+	// Timeout is the maximum amount of time for each call to the server.
+	Timeout time.Duration
+	// NewClient creates the client for connecting to the To-do Daemon
+	// server.
+	NewClient client.Factory
+	// Stdout is the writer that the command prints its output to.
+	Stdout io.Writer
+}
+
+// NewExecutor creates an executor for the specified 'list' command.
+func NewExecutor(cmd *cli.Command) (*Executor, error) {
+	return &Executor{
+		SockFile:  cmd.String("sock"),
+		Timeout:   cmd.Duration("timeout"),
+		NewClient: client.New,
+		Stdout:    cmd.Root().Writer,
+	}, nil
+}
+
+// Execute executes the 'list' command.
+func (e *Executor) Execute(ctx context.Context) error {
+	c, err := e.NewClient(e.SockFile, client.WithTimeout(e.Timeout))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Warn("cannot close client connection", "cause", err)
+		}
+	}()
+
+	templates, err := c.ListTemplates(ctx)
+	if err != nil {
+		return err
+	}
+	if len(templates) == 0 {
+		_, err := fmt.Fprintln(e.Stdout, i18n.Translate("No templates"))
+		return err
+	}
+	tw := tabwriter.NewWriter(e.Stdout, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "NAME\tSCHEDULE\tNEXT RUN\tTASKS"); err != nil {
+		return err
+	}
+	loc := clifmt.Location(e.Stdout)
+	for _, t := range templates {
+		schedule, next := "-", "-"
+		if t.GetSchedule() != "" {
+			schedule = t.GetSchedule()
+		}
+		if t.GetNextRunAt() != nil {
+			next = t.GetNextRunAt().AsTime().In(loc).Format(time.DateTime)
+		}
+		_, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", t.GetName(), schedule, next, summaries(t.GetTasks()))
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// summaries joins the summaries of the specified tasks.
+func summaries(tasks []*todopb.TemplateTask) string {
+	s := make([]string, len(tasks))
+	for i, t := range tasks {
+		s[i] = t.GetSummary()
+	}
+	return strings.Join(s, ", ")
+}
+
+// NewCommand creates a new 'list' command with the specified configuration.
+func NewCommand(_ *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "List the task templates",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			e, err := NewExecutor(cmd)
+			if err != nil {
+				return err
+			}
+			return e.Execute(ctx)
+		},
+	}
+}
@@ -0,0 +1,41 @@
+// Package templates implements the 'templates' command of the To-do Daemon
+// CLI.
+//
+// The 'templates' command provides subcommands for managing the task
+// templates that the server keeps, e.g. the checklist of a weekly review, and
+// for creating all tasks of a template at once.
+package templates
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mwopitz/todo-daemon/internal/cli/templates/add"
+	"github.com/mwopitz/todo-daemon/internal/cli/templates/apply"
+	"github.com/mwopitz/todo-daemon/internal/cli/templates/list"
+	"github.com/mwopitz/todo-daemon/internal/cli/templates/remove"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+)
+
+// NewCommand creates a new 'templates' command with the specified
+// configuration.
+func NewCommand(conf *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "templates",
+		Usage: "Manage task templates and create tasks from them",
+		Commands: []*cli.Command{
+			add.NewCommand(conf),
+			list.NewCommand(conf),
+			apply.NewCommand(conf),
+			remove.NewCommand(conf),
+		},
+		CommandNotFound: func(_ context.Context, _ *cli.Command, name string) {
+			// revive:disable-next-line:unhandled-error
+			fmt.Fprintf(os.Stderr, "todo-daemon: %s: '%s'\n", i18n.Translate("invalid command"), name)
+		},
+	}
+}
@@ -4,38 +4,87 @@ package client
 import (
 	"context"
 	"fmt"
+	"net"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/transport"
 )
 
 // Client is used for communicating with the To-do Daemon's gRPC server.
 type Client struct {
 	conn    *grpc.ClientConn
 	service todopb.TodoServiceClient
+	// retries is the number of times that calls creating tasks are retried.
+	retries int
 }
 
+// Factory creates a To-do Daemon client connected to the server listening on
+// the specified address. [New] is the factory used by the CLI; tests may use
+// another one, e.g. to connect to an in-process server.
+type Factory func(address string, opts ...Option) (*Client, error)
+
 // New creates a To-do Daemon client and connects it to the server listening on
-// the specified network address.
-func New(network, address string) (*Client, error) {
-	target := fmt.Sprintf("%s:%s", network, address)
-	conn, err := grpc.NewClient(
-		target,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+// the specified address. See [transport.ParseAddress] for the address format.
+// If the server is not running, the client's calls return
+// [ErrDaemonNotRunning].
+func New(address string, opts ...Option) (*Client, error) {
+	addr, err := transport.ParseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	o := options{dial: transport.Dial}
+	for _, opt := range append(defaultOptions(), opts...) {
+		opt(&o)
+	}
+	unary := []grpc.UnaryClientInterceptor{notRunningUnaryInterceptor(addr, o.dial), versionUnaryInterceptor()}
+	if o.timeout > 0 {
+		unary = append(unary, timeoutInterceptor(o.timeout))
+	}
+	dialOpts := append(
+		dialOptions(addr, o.dial),
+		grpc.WithChainUnaryInterceptor(unary...),
+		grpc.WithChainStreamInterceptor(notRunningStreamInterceptor(addr, o.dial), versionStreamInterceptor()),
+		grpc.WithDefaultCallOptions(callOptions(&o)...),
 	)
+	conn, err := grpc.NewClient(Target(addr), dialOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("cannot connect to %s: %w", target, err)
+		return nil, fmt.Errorf("cannot connect to %s: %w", addr, err)
 	}
 	return &Client{
 		conn:    conn,
 		service: todopb.NewTodoServiceClient(conn),
+		retries: o.retries,
 	}, nil
 }
 
+// Target returns the gRPC target for connecting to the specified address. It
+// must be used together with the [DialOptions] for the same address.
+func Target(addr transport.Address) string {
+	return "passthrough:///" + addr.String()
+}
+
+// DialOptions returns the gRPC dial options for connecting to the specified
+// address via the appropriate transport.
+func DialOptions(addr transport.Address) []grpc.DialOption {
+	return dialOptions(addr, transport.Dial)
+}
+
+func dialOptions(addr transport.Address, dial Dialer) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return dial(ctx, addr)
+		}),
+	}
+}
+
 // Close closes the connection to the To-do Daemon server.
 func (c *Client) Close() error {
 	if c.conn != nil {
@@ -49,25 +98,179 @@ func (c *Client) ServerStatus(ctx context.Context) (*todopb.StatusResponse, erro
 	return c.service.Status(ctx, &todopb.StatusRequest{})
 }
 
-// CreateTask creates the specified task in the to-do list.
-func (c *Client) CreateTask(ctx context.Context, summary string) (*todopb.Task, error) {
-	task := &todopb.NewTask{Summary: summary}
-	resp, err := c.service.CreateTask(ctx, &todopb.CreateTaskRequest{Task: task})
+// GetCapabilities retrieves the features enabled on the To-do Daemon server,
+// its limits, and the API versions it supports.
+func (c *Client) GetCapabilities(ctx context.Context) (*todopb.GetCapabilitiesResponse, error) {
+	resp, err := c.service.GetCapabilities(ctx, &todopb.GetCapabilitiesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot retrieve server capabilities: %w", err)
+	}
+	return resp, nil
+}
+
+// CreateTask creates the specified task in the to-do list. The request carries
+// a random idempotency key, so that it can be retried, see [WithRetries],
+// without creating the task twice.
+func (c *Client) CreateTask(ctx context.Context, task *todopb.NewTask) (*todopb.Task, error) {
+	key, err := newIdempotencyKey()
+	if err != nil {
+		return nil, err
+	}
+	req := &todopb.CreateTaskRequest{Task: task, IdempotencyKey: key}
+	var resp *todopb.CreateTaskResponse
+	err = c.retry(ctx, func() error {
+		var err error
+		resp, err = c.service.CreateTask(ctx, req)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("cannot create task: %w", err)
 	}
 	return resp.GetTask(), nil
 }
 
+// BatchCreateTasks creates the specified tasks in the to-do list in a single
+// call. It returns the created tasks in the same order.
+func (c *Client) BatchCreateTasks(ctx context.Context, tasks []*todopb.NewTask) ([]*todopb.Task, error) {
+	resp, err := c.service.BatchCreateTasks(ctx, &todopb.BatchCreateTasksRequest{Tasks: tasks})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create tasks: %w", err)
+	}
+	return resp.GetTasks(), nil
+}
+
+// ApplyBatch applies the specified operations at once: either all of them or,
+// if any of them fails, none. It returns the resulting task of each
+// operation.
+func (c *Client) ApplyBatch(ctx context.Context, ops []*todopb.BatchOperation) ([]*todopb.Task, error) {
+	resp, err := c.service.ApplyBatch(ctx, &todopb.ApplyBatchRequest{Operations: ops})
+	if err != nil {
+		return nil, fmt.Errorf("cannot apply batch: %w", err)
+	}
+	return resp.GetTasks(), nil
+}
+
 // ListTasks retrieves the list of tasks from the To-do Daemon server.
 func (c *Client) ListTasks(ctx context.Context) ([]*todopb.Task, error) {
-	resp, err := c.service.ListTasks(ctx, &todopb.ListTasksRequest{})
+	return c.FindTasks(ctx, &todopb.ListTasksRequest{})
+}
+
+// FindTasks retrieves the tasks matching the specified filter from the To-do
+// Daemon server.
+func (c *Client) FindTasks(ctx context.Context, filter *todopb.ListTasksRequest) ([]*todopb.Task, error) {
+	resp, err := c.service.ListTasks(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 	return resp.GetTasks(), nil
 }
 
+// GetTask retrieves the task with the specified ID from the To-do Daemon
+// server.
+func (c *Client) GetTask(ctx context.Context, id string) (*todopb.Task, error) {
+	resp, err := c.service.GetTask(ctx, &todopb.GetTaskRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetTask(), nil
+}
+
+// ResolveTask retrieves the task that the specified reference, i.e. its ID,
+// its short code, or a unique prefix of its short code, refers to.
+func (c *Client) ResolveTask(ctx context.Context, ref string) (*todopb.Task, error) {
+	resp, err := c.service.ResolveTask(ctx, &todopb.ResolveTaskRequest{Ref: ref})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetTask(), nil
+}
+
+// MoveTask moves the task with the specified ID in the manual order of the
+// to-do list, right before or after another task. Exactly one of beforeID and
+// afterID must be set.
+func (c *Client) MoveTask(ctx context.Context, id, beforeID, afterID string) (*todopb.Task, error) {
+	resp, err := c.service.MoveTask(ctx, &todopb.MoveTaskRequest{
+		Id:       id,
+		BeforeId: beforeID,
+		AfterId:  afterID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot move task: %w", err)
+	}
+	return resp.GetTask(), nil
+}
+
+// SearchTasks searches the summaries and descriptions of the tasks in the
+// to-do list. If limit is zero, all matching tasks are returned.
+func (c *Client) SearchTasks(ctx context.Context, query string, limit uint32) ([]*todopb.SearchResult, error) {
+	resp, err := c.service.SearchTasks(ctx, &todopb.SearchTasksRequest{Q: query, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetResults(), nil
+}
+
+// GetStats retrieves statistics about the tasks in the to-do list.
+func (c *Client) GetStats(ctx context.Context) (*todopb.GetStatsResponse, error) {
+	resp, err := c.service.GetStats(ctx, &todopb.GetStatsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot retrieve statistics: %w", err)
+	}
+	return resp, nil
+}
+
+// WatchTasks streams the changes to the tasks in the to-do list until the
+// context is canceled.
+func (c *Client) WatchTasks(ctx context.Context) (grpc.ServerStreamingClient[todopb.TaskEvent], error) {
+	return c.service.WatchTasks(ctx, &todopb.WatchTasksRequest{})
+}
+
+// CreateBackup retrieves a snapshot of the entire to-do list from the To-do
+// Daemon server.
+func (c *Client) CreateBackup(ctx context.Context) (*todopb.CreateBackupResponse, error) {
+	return c.service.CreateBackup(ctx, &todopb.CreateBackupRequest{})
+}
+
+// RestoreBackup replaces the entire to-do list with the specified snapshot
+// and returns the number of restored tasks.
+func (c *Client) RestoreBackup(ctx context.Context, archive []byte) (uint32, error) {
+	resp, err := c.service.RestoreBackup(ctx, &todopb.RestoreBackupRequest{Archive: archive})
+	if err != nil {
+		return 0, err
+	}
+	return resp.GetTaskCount(), nil
+}
+
+// ReloadConfig makes the To-do Daemon server reload its configuration file.
+func (c *Client) ReloadConfig(ctx context.Context) (*todopb.ReloadConfigResponse, error) {
+	return c.service.ReloadConfig(ctx, &todopb.ReloadConfigRequest{})
+}
+
+// Takeover asks the To-do Daemon server to hand its listeners over to the new
+// instance receiving them on the Unix domain socket at the specified path.
+func (c *Client) Takeover(ctx context.Context, handoverAddress string) (*todopb.TakeoverResponse, error) {
+	return c.service.Takeover(ctx, &todopb.TakeoverRequest{HandoverAddress: handoverAddress})
+}
+
+// ListJobs retrieves the background jobs of the To-do Daemon server.
+func (c *Client) ListJobs(ctx context.Context) ([]*todopb.BackgroundJob, error) {
+	resp, err := c.service.ListJobs(ctx, &todopb.ListJobsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list background jobs: %w", err)
+	}
+	return resp.GetJobs(), nil
+}
+
+// TailLogs streams the recent log messages of the To-do Daemon server and, if
+// the request says so, the messages logged afterwards until the context is
+// canceled.
+func (c *Client) TailLogs(
+	ctx context.Context,
+	req *todopb.TailLogsRequest,
+) (grpc.ServerStreamingClient[todopb.LogEntry], error) {
+	return c.service.TailLogs(ctx, req)
+}
+
 // CompleteTask marks the specified task as completed.
 func (c *Client) CompleteTask(ctx context.Context, id string) (*todopb.Task, error) {
 	update := &todopb.TaskUpdate{CompletedAt: timestamppb.Now()}
@@ -87,6 +290,62 @@ func (c *Client) CompleteTask(ctx context.Context, id string) (*todopb.Task, err
 	return res.GetTask(), nil
 }
 
+// SetDependencies replaces the IDs of the tasks that the specified task depends
+// on, i.e. that must be completed before it.
+func (c *Client) SetDependencies(ctx context.Context, id string, dependsOn []string) (*todopb.Task, error) {
+	update := &todopb.TaskUpdate{DependsOn: dependsOn}
+	fields, err := fieldmaskpb.New(update, "depends_on")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.service.UpdateTask(ctx, &todopb.UpdateTaskRequest{
+		Id:     id,
+		Update: update,
+		Fields: fields,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot change dependencies: %w", err)
+	}
+	return resp.GetTask(), nil
+}
+
+// SetStarred stars or unstars the specified task.
+func (c *Client) SetStarred(ctx context.Context, id string, starred bool) (*todopb.Task, error) {
+	update := &todopb.TaskUpdate{Starred: starred}
+	fields, err := fieldmaskpb.New(update, "starred")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.service.UpdateTask(ctx, &todopb.UpdateTaskRequest{
+		Id:     id,
+		Update: update,
+		Fields: fields,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot change starred state: %w", err)
+	}
+	return resp.GetTask(), nil
+}
+
+// SetAssignee assigns the specified task to the specified user, or unassigns
+// it if the user is empty.
+func (c *Client) SetAssignee(ctx context.Context, id, assignee string) (*todopb.Task, error) {
+	update := &todopb.TaskUpdate{Assignee: assignee}
+	fields, err := fieldmaskpb.New(update, "assignee")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.service.UpdateTask(ctx, &todopb.UpdateTaskRequest{
+		Id:     id,
+		Update: update,
+		Fields: fields,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot change assignee: %w", err)
+	}
+	return resp.GetTask(), nil
+}
+
 // DeleteTask removes the specified task from the to-do list.
 func (c *Client) DeleteTask(ctx context.Context, id string) error {
 	_, err := c.service.DeleteTask(ctx, &todopb.DeleteTaskRequest{Id: id})
@@ -95,3 +354,151 @@ func (c *Client) DeleteTask(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+// RestoreTask moves the specified task from the trash back to the to-do list.
+func (c *Client) RestoreTask(ctx context.Context, id string) (*todopb.Task, error) {
+	resp, err := c.service.RestoreTask(ctx, &todopb.RestoreTaskRequest{Id: id})
+	if err != nil {
+		return nil, fmt.Errorf("cannot restore task: %w", err)
+	}
+	return resp.GetTask(), nil
+}
+
+// DuplicateTask creates the specified number of copies of a task, with the
+// suffix appended to their summaries. The first copy is due dueShift after
+// the task, and each further copy dueShift after the previous one.
+func (c *Client) DuplicateTask(
+	ctx context.Context,
+	id string,
+	count int,
+	suffix string,
+	dueShift time.Duration,
+) ([]*todopb.Task, error) {
+	resp, err := c.service.DuplicateTask(ctx, &todopb.DuplicateTaskRequest{
+		Id:            id,
+		Count:         uint32(count),
+		SummarySuffix: suffix,
+		DueShift:      durationpb.New(dueShift),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot duplicate task: %w", err)
+	}
+	return resp.GetTasks(), nil
+}
+
+// PurgeCompleted moves the tasks that were completed at least the specified
+// duration ago to the trash. If archive is true, the response includes a
+// snapshot of the removed tasks.
+func (c *Client) PurgeCompleted(
+	ctx context.Context,
+	olderThan time.Duration,
+	archive bool,
+) (*todopb.PurgeCompletedResponse, error) {
+	resp, err := c.service.PurgeCompleted(ctx, &todopb.PurgeCompletedRequest{
+		OlderThan: durationpb.New(olderThan),
+		Archive:   archive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot remove completed tasks: %w", err)
+	}
+	return resp, nil
+}
+
+// PullChanges retrieves the tasks that were created, updated, or deleted after
+// the specified time, for synchronizing them with another to-do list. If since
+// is zero, all tasks are retrieved.
+func (c *Client) PullChanges(ctx context.Context, since time.Time) (*todopb.PullChangesResponse, error) {
+	resp, err := c.service.PullChanges(ctx, &todopb.PullChangesRequest{Since: optionalTimestamp(since)})
+	if err != nil {
+		return nil, fmt.Errorf("cannot retrieve changes: %w", err)
+	}
+	return resp, nil
+}
+
+// PushChanges merges the specified changes retrieved from another to-do list
+// into the to-do list. Tasks changed in both to-do lists after the specified
+// time are reported as conflicts.
+func (c *Client) PushChanges(
+	ctx context.Context,
+	changes []*todopb.TaskChange,
+	since time.Time,
+) (*todopb.PushChangesResponse, error) {
+	resp, err := c.service.PushChanges(ctx, &todopb.PushChangesRequest{
+		Changes: changes,
+		Since:   optionalTimestamp(since),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot merge changes: %w", err)
+	}
+	return resp, nil
+}
+
+// ListFilters retrieves the named filters, both those defined in the
+// configuration file and those saved via [Client.CreateFilter].
+func (c *Client) ListFilters(ctx context.Context) ([]*todopb.Filter, error) {
+	resp, err := c.service.ListFilters(ctx, &todopb.ListFiltersRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot retrieve filters: %w", err)
+	}
+	return resp.GetFilters(), nil
+}
+
+// CreateFilter saves the specified named filter on the server.
+func (c *Client) CreateFilter(ctx context.Context, filter *todopb.Filter) (*todopb.Filter, error) {
+	resp, err := c.service.CreateFilter(ctx, &todopb.CreateFilterRequest{Filter: filter})
+	if err != nil {
+		return nil, fmt.Errorf("cannot save filter: %w", err)
+	}
+	return resp.GetFilter(), nil
+}
+
+// DeleteFilter deletes the saved filter with the specified name.
+func (c *Client) DeleteFilter(ctx context.Context, name string) error {
+	if _, err := c.service.DeleteFilter(ctx, &todopb.DeleteFilterRequest{Name: name}); err != nil {
+		return fmt.Errorf("cannot delete filter: %w", err)
+	}
+	return nil
+}
+
+// ListTemplates retrieves the task templates saved on the server.
+func (c *Client) ListTemplates(ctx context.Context) ([]*todopb.Template, error) {
+	resp, err := c.service.ListTemplates(ctx, &todopb.ListTemplatesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot retrieve templates: %w", err)
+	}
+	return resp.GetTemplates(), nil
+}
+
+// CreateTemplate saves the specified task template on the server.
+func (c *Client) CreateTemplate(ctx context.Context, template *todopb.Template) (*todopb.Template, error) {
+	resp, err := c.service.CreateTemplate(ctx, &todopb.CreateTemplateRequest{Template: template})
+	if err != nil {
+		return nil, fmt.Errorf("cannot save template: %w", err)
+	}
+	return resp.GetTemplate(), nil
+}
+
+// DeleteTemplate deletes the task template with the specified name.
+func (c *Client) DeleteTemplate(ctx context.Context, name string) error {
+	if _, err := c.service.DeleteTemplate(ctx, &todopb.DeleteTemplateRequest{Name: name}); err != nil {
+		return fmt.Errorf("cannot delete template: %w", err)
+	}
+	return nil
+}
+
+// ApplyTemplate creates all tasks of the template with the specified name at
+// once. It returns the created tasks in the order of the template.
+func (c *Client) ApplyTemplate(ctx context.Context, name string) ([]*todopb.Task, error) {
+	resp, err := c.service.ApplyTemplate(ctx, &todopb.ApplyTemplateRequest{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("cannot apply template: %w", err)
+	}
+	return resp.GetTasks(), nil
+}
+
+func optionalTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// defaults holds the options applied to all clients, see [SetDefaults].
+var defaults struct {
+	mu   sync.Mutex
+	opts []Option
+}
+
+// SetDefaults sets the options that [New] applies to all clients created
+// afterwards, before the options passed to it, e.g. the settings of the
+// configuration file that apply to all commands of the CLI.
+func SetDefaults(opts ...Option) {
+	defaults.mu.Lock()
+	defer defaults.mu.Unlock()
+	defaults.opts = opts
+}
+
+// defaultOptions returns the options set with [SetDefaults].
+func defaultOptions() []Option {
+	defaults.mu.Lock()
+	defer defaults.mu.Unlock()
+	return defaults.opts
+}
+
+// WithCompression makes the client compress its requests with gzip, in which
+// case the server compresses its responses, too. This saves bandwidth if the
+// server is reached through a slow connection, e.g. with 'todo-daemon proxy',
+// at the cost of CPU time.
+func WithCompression(enabled bool) Option {
+	return func(o *options) {
+		o.compression = enabled
+	}
+}
+
+// WithMaxMessageSize limits the size of the messages that the client receives
+// and sends, in bytes. Larger messages fail with RESOURCE_EXHAUSTED. Without
+// it, gRPC's defaults apply: 4 MiB for received messages and no limit for sent
+// ones.
+func WithMaxMessageSize(recv, send int) Option {
+	return func(o *options) {
+		o.maxRecvMsgSize = recv
+		o.maxSendMsgSize = send
+	}
+}
+
+// callOptions returns the default call options for the specified options.
+func callOptions(o *options) []grpc.CallOption {
+	var opts []grpc.CallOption
+	if o.compression {
+		opts = append(opts, grpc.UseCompressor(gzip.Name))
+	}
+	if o.maxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxCallRecvMsgSize(o.maxRecvMsgSize))
+	}
+	if o.maxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxCallSendMsgSize(o.maxSendMsgSize))
+	}
+	return opts
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// InvokeJSON invokes the specified unary gRPC method with a JSON-encoded
+// request message and returns the JSON-encoded response message. The method
+// must be fully qualified, e.g. "todo.v1.TodoService/ListTasks" or
+// "todo.v1.TodoService.ListTasks".
+//
+// The method is resolved via gRPC server reflection, so it also works with
+// methods unknown to this client. If the server doesn't support reflection,
+// the method is resolved from the descriptors compiled into this program.
+func (c *Client) InvokeJSON(ctx context.Context, method string, request []byte) ([]byte, error) {
+	service, name, err := splitMethod(method)
+	if err != nil {
+		return nil, err
+	}
+	sd, err := c.resolveService(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+	md := sd.Methods().ByName(protoreflect.Name(name))
+	if md == nil {
+		return nil, fmt.Errorf("no such method: '%s/%s'", service, name)
+	}
+	if md.IsStreamingClient() || md.IsStreamingServer() {
+		return nil, fmt.Errorf("cannot invoke streaming method '%s/%s'", service, name)
+	}
+
+	in := dynamicpb.NewMessage(md.Input())
+	if len(request) > 0 {
+		if err := protojson.Unmarshal(request, in); err != nil {
+			return nil, fmt.Errorf("invalid request message: %w", err)
+		}
+	}
+	out := dynamicpb.NewMessage(md.Output())
+	if err := c.conn.Invoke(ctx, "/"+service+"/"+name, in, out); err != nil {
+		return nil, err
+	}
+	return protojson.MarshalOptions{Multiline: true}.Marshal(out)
+}
+
+func splitMethod(method string) (service, name string, err error) {
+	method = strings.TrimPrefix(method, "/")
+	i := strings.LastIndexAny(method, "/.")
+	if i <= 0 || i == len(method)-1 {
+		return "", "", fmt.Errorf("invalid method name: '%s'", method)
+	}
+	return method[:i], method[i+1:], nil
+}
+
+func (c *Client) resolveService(ctx context.Context, service string) (protoreflect.ServiceDescriptor, error) {
+	files, err := c.reflectFiles(ctx, service)
+	if status.Code(err) == codes.Unimplemented {
+		slog.Debug("server reflection not available, using local descriptors", "cause", err)
+		files = protoregistry.GlobalFiles
+	} else if err != nil {
+		return nil, err
+	}
+	d, err := files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, fmt.Errorf("no such service: '%s'", service)
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("not a service: '%s'", service)
+	}
+	return sd, nil
+}
+
+// reflectFiles retrieves the file descriptors defining the specified symbol,
+// including their dependencies, via gRPC server reflection.
+func (c *Client) reflectFiles(ctx context.Context, symbol string) (*protoregistry.Files, error) {
+	stream, err := reflectionpb.NewServerReflectionClient(c.conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot query server reflection: %w", err)
+	}
+	defer func() {
+		if err := stream.CloseSend(); err != nil {
+			slog.Warn("cannot close reflection stream", "cause", err)
+		}
+	}()
+	req := &reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{
+			FileContainingSymbol: symbol,
+		},
+	}
+	if err := stream.Send(req); err != nil {
+		return nil, fmt.Errorf("cannot query server reflection: %w", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("cannot query server reflection: %w", err)
+	}
+	if e := resp.GetErrorResponse(); e != nil {
+		return nil, fmt.Errorf("no such service: '%s': %s", symbol, e.GetErrorMessage())
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, b := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		fd := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(b, fd); err != nil {
+			return nil, fmt.Errorf("invalid file descriptor: %w", err)
+		}
+		set.File = append(set.File, fd)
+	}
+	return protodesc.NewFiles(set)
+}
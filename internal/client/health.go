@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/mwopitz/todo-daemon/internal/transport"
+)
+
+// ErrDaemonNotRunning is returned by the client's calls when there is no
+// To-do Daemon server listening on the client's address.
+var ErrDaemonNotRunning = errors.New("todo-daemon server is not running; start it with 'todo-daemon run'")
+
+// probeTimeout is the maximum amount of time for checking whether the server
+// is running after a call failed.
+const probeTimeout = time.Second
+
+// The intervals between the health checks of [Client.WaitReady] start at
+// minReadyInterval and double up to maxReadyInterval.
+const (
+	minReadyInterval = 10 * time.Millisecond
+	maxReadyInterval = 500 * time.Millisecond
+)
+
+// Option configures optional features of a [Client].
+type Option func(o *options)
+
+type options struct {
+	timeout time.Duration
+	retries int
+	dial    Dialer
+	// compression, maxRecvMsgSize, and maxSendMsgSize configure the calls,
+	// see [WithCompression] and [WithMaxMessageSize].
+	compression    bool
+	maxRecvMsgSize int
+	maxSendMsgSize int
+}
+
+// Dialer connects to the server listening on the specified address.
+type Dialer func(ctx context.Context, addr transport.Address) (net.Conn, error)
+
+// WithDialer makes the client connect to the server using the specified
+// dialer instead of [transport.Dial], e.g. to connect to an in-process server
+// in tests.
+func WithDialer(dial Dialer) Option {
+	return func(o *options) {
+		o.dial = dial
+	}
+}
+
+// WithTimeout limits the duration of each unary call to the specified timeout.
+// Streaming calls are not limited. A timeout <= 0 means that there is no
+// timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// notRunningUnaryInterceptor replaces the errors of unary calls with
+// [ErrDaemonNotRunning] if the server is not running.
+func notRunningUnaryInterceptor(addr transport.Address, dial Dialer) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		return checkRunning(addr, dial, invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// notRunningStreamInterceptor replaces the errors of starting streaming calls
+// with [ErrDaemonNotRunning] if the server is not running.
+func notRunningStreamInterceptor(addr transport.Address, dial Dialer) grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		return stream, checkRunning(addr, dial, err)
+	}
+}
+
+// checkRunning returns [ErrDaemonNotRunning] if the specified error indicates
+// that the server is unavailable and nothing is listening on the specified
+// address, which is probed using the specified dialer. Otherwise, it returns
+// the error unchanged.
+func checkRunning(addr transport.Address, dial Dialer, err error) error {
+	if status.Code(err) != codes.Unavailable {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+	conn, dialErr := dial(ctx, addr)
+	if dialErr == nil {
+		if err := conn.Close(); err != nil {
+			slog.Warn("cannot close probe connection", "cause", err)
+		}
+		return err
+	}
+	if errors.Is(dialErr, os.ErrNotExist) || errors.Is(dialErr, syscall.ECONNREFUSED) {
+		return ErrDaemonNotRunning
+	}
+	return err
+}
+
+// WaitReady waits until the server reports via the gRPC health service that
+// it is serving, e.g. right after the server has been started. It checks the
+// server's health in increasing intervals and fails with the last error once
+// the specified context is done.
+func (c *Client) WaitReady(ctx context.Context) error {
+	hc := healthpb.NewHealthClient(c.conn)
+	interval := minReadyInterval
+	for {
+		resp, err := hc.Check(ctx, &healthpb.HealthCheckRequest{})
+		if err == nil && resp.GetStatus() == healthpb.HealthCheckResponse_SERVING {
+			return nil
+		}
+		if err == nil {
+			err = fmt.Errorf("server status is %s", resp.GetStatus())
+		}
+		// Don't let the connection wait for its reconnect backoff, which
+		// is much longer than our interval, once the socket appears.
+		c.conn.ResetConnectBackoff()
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("server is not ready: %w", err)
+		case <-timer.C:
+		}
+		interval = min(2*interval, maxReadyInterval)
+	}
+}
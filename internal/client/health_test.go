@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDaemonNotRunning(t *testing.T) {
+	c, err := New(filepath.Join(t.TempDir(), "todo-daemon.sock"), WithTimeout(5*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if _, err := c.ServerStatus(context.Background()); !errors.Is(err, ErrDaemonNotRunning) {
+		t.Errorf("want error: %v; got: %v", ErrDaemonNotRunning, err)
+	}
+}
+
+func TestWaitReadyNotRunning(t *testing.T) {
+	c, err := New(filepath.Join(t.TempDir(), "todo-daemon.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+	if err := c.WaitReady(ctx); !errors.Is(err, ErrDaemonNotRunning) {
+		t.Errorf("want error: %v; got: %v", ErrDaemonNotRunning, err)
+	}
+}
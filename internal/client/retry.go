@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The intervals between the retries of failed calls start at
+// minRetryInterval and double up to maxRetryInterval.
+const (
+	minRetryInterval = 100 * time.Millisecond
+	maxRetryInterval = 2 * time.Second
+)
+
+// WithRetries makes the client retry calls that create tasks up to the
+// specified number of times if the server is unavailable or doesn't respond
+// in time. Each request carries an idempotency key, which the server uses to
+// return the task created by an earlier attempt instead of creating it again.
+func WithRetries(retries int) Option {
+	return func(o *options) {
+		o.retries = retries
+	}
+}
+
+// newIdempotencyKey generates a random idempotency key for a request.
+func newIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("cannot generate idempotency key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// retry calls the specified function until it succeeds, fails with an error
+// that is not worth retrying, or the client's retries are used up. Only calls
+// that failed because the server was unavailable or didn't respond in time
+// are retried, as long as the specified context is not done.
+func (c *Client) retry(ctx context.Context, call func() error) error {
+	interval := minRetryInterval
+	for attempt := 0; ; attempt++ {
+		err := call()
+		if err == nil || attempt >= c.retries || !retryable(err) {
+			return err
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		interval = min(2*interval, maxRetryInterval)
+	}
+}
+
+// retryable checks if a call that failed with the specified error may
+// succeed when it is retried.
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/transport"
+)
+
+// flakyService fails the first calls to create a task as unavailable and
+// records the idempotency keys of all calls.
+type flakyService struct {
+	todopb.UnimplementedTodoServiceServer
+
+	failures int
+
+	mu   sync.Mutex
+	keys []string
+}
+
+func (s *flakyService) CreateTask(
+	_ context.Context,
+	req *todopb.CreateTaskRequest,
+) (*todopb.CreateTaskResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = append(s.keys, req.GetIdempotencyKey())
+	if len(s.keys) <= s.failures {
+		return nil, status.Error(codes.Unavailable, "try again")
+	}
+	return &todopb.CreateTaskResponse{Task: &todopb.Task{Id: "1", Summary: req.GetTask().GetSummary()}}, nil
+}
+
+func newFlakyClient(t *testing.T, svc *flakyService, opts ...Option) *Client {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	todopb.RegisterTodoServiceServer(srv, svc)
+	go func() {
+		// Serve returns when the server is stopped.
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+	dial := func(ctx context.Context, _ transport.Address) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}
+	c, err := New("unix:///todo-daemon.sock", append(opts, WithDialer(dial))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return c
+}
+
+func TestCreateTaskRetries(t *testing.T) {
+	svc := &flakyService{failures: 2}
+	c := newFlakyClient(t, svc, WithRetries(2))
+	task, err := c.CreateTask(t.Context(), &todopb.NewTask{Summary: "Buy milk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.GetSummary() != "Buy milk" {
+		t.Errorf("want task 'Buy milk'; got: %v", task)
+	}
+	if len(svc.keys) != 3 || svc.keys[0] == "" || svc.keys[1] != svc.keys[0] || svc.keys[2] != svc.keys[0] {
+		t.Errorf("want 3 attempts with the same idempotency key; got: %q", svc.keys)
+	}
+
+	// Every task gets a key of its own.
+	if _, err := c.CreateTask(t.Context(), &todopb.NewTask{Summary: "Buy bread"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(svc.keys) != 4 || svc.keys[3] == svc.keys[0] {
+		t.Errorf("want new idempotency key for another task; got: %q", svc.keys)
+	}
+}
+
+func TestCreateTaskRetriesExhausted(t *testing.T) {
+	svc := &flakyService{failures: 2}
+	c := newFlakyClient(t, svc, WithRetries(1))
+	if _, err := c.CreateTask(t.Context(), &todopb.NewTask{Summary: "Buy milk"}); status.Code(err) != codes.Unavailable {
+		t.Errorf("want UNAVAILABLE error; got: %v", err)
+	}
+	if len(svc.keys) != 2 {
+		t.Errorf("want 2 attempts; got: %d", len(svc.keys))
+	}
+}
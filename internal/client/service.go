@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+)
+
+// TaskService is the part of the To-do Daemon API that the 'tasks' commands of
+// the CLI depend on. It is implemented by [Client], which calls the To-do
+// Daemon server, and by the in-process service of package standalone, which
+// operates on the to-do list directly.
+type TaskService interface {
+	// CreateTask creates the specified task in the to-do list.
+	CreateTask(ctx context.Context, task *todopb.NewTask) (*todopb.Task, error)
+	// BatchCreateTasks creates the specified tasks in the to-do list at once
+	// and returns the created tasks in the same order.
+	BatchCreateTasks(ctx context.Context, tasks []*todopb.NewTask) ([]*todopb.Task, error)
+	// ListTasks retrieves all tasks in the to-do list.
+	ListTasks(ctx context.Context) ([]*todopb.Task, error)
+	// FindTasks retrieves the tasks matching the specified filter.
+	FindTasks(ctx context.Context, filter *todopb.ListTasksRequest) ([]*todopb.Task, error)
+	// ResolveTask retrieves the task that the specified reference, i.e. its
+	// ID, its short code, or a unique prefix of its short code, refers to.
+	ResolveTask(ctx context.Context, ref string) (*todopb.Task, error)
+	// MoveTask moves the task with the specified ID in the manual order of
+	// the to-do list, right before or after another task.
+	MoveTask(ctx context.Context, id, beforeID, afterID string) (*todopb.Task, error)
+	// SearchTasks searches the summaries and descriptions of the tasks.
+	SearchTasks(ctx context.Context, query string, limit uint32) ([]*todopb.SearchResult, error)
+	// CompleteTask marks the specified task as completed.
+	CompleteTask(ctx context.Context, id string) (*todopb.Task, error)
+	// SetDependencies replaces the IDs of the tasks that the specified task
+	// depends on.
+	SetDependencies(ctx context.Context, id string, dependsOn []string) (*todopb.Task, error)
+	// SetStarred stars or unstars the specified task.
+	SetStarred(ctx context.Context, id string, starred bool) (*todopb.Task, error)
+	// SetAssignee assigns the specified task to the specified user, or
+	// unassigns it if the user is empty.
+	SetAssignee(ctx context.Context, id, assignee string) (*todopb.Task, error)
+	// DeleteTask removes the specified task from the to-do list.
+	DeleteTask(ctx context.Context, id string) error
+	// RestoreTask moves the specified task from the trash back to the to-do
+	// list.
+	RestoreTask(ctx context.Context, id string) (*todopb.Task, error)
+	// DuplicateTask creates the specified number of copies of a task, with
+	// the suffix appended to their summaries and their due times shifted.
+	DuplicateTask(ctx context.Context, id string, count int, suffix string, dueShift time.Duration) ([]*todopb.Task, error)
+	// PurgeCompleted moves the tasks that were completed at least the
+	// specified duration ago to the trash, optionally returning a snapshot of
+	// them.
+	PurgeCompleted(ctx context.Context, olderThan time.Duration, archive bool) (*todopb.PurgeCompletedResponse, error)
+	// WatchTasks streams the changes to the tasks until the context is
+	// canceled.
+	WatchTasks(ctx context.Context) (grpc.ServerStreamingClient[todopb.TaskEvent], error)
+	// Close releases the resources held by the service, e.g. the connection
+	// to the server.
+	Close() error
+}
+
+var _ TaskService = (*Client)(nil)
+
+// TaskServiceFactory creates the [TaskService] for the To-do Daemon server
+// listening on the specified address. [NewTaskService] is the factory used by
+// the CLI, unless it runs in standalone mode.
+type TaskServiceFactory func(address string, opts ...Option) (TaskService, error)
+
+// NewTaskService is the [TaskServiceFactory] that creates a [Client] with
+// [New].
+func NewTaskService(address string, opts ...Option) (TaskService, error) {
+	c, err := New(address, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
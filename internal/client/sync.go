@@ -0,0 +1,135 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/rest"
+)
+
+// SyncPeer is the part of the To-do Daemon API that is used for synchronizing
+// two to-do lists. It is implemented by [Client] and by the client of the REST
+// API, which [NewSyncPeer] creates for the To-do Daemons on other computers.
+type SyncPeer interface {
+	// PullChanges retrieves the tasks that were changed after the specified
+	// time.
+	PullChanges(ctx context.Context, since time.Time) (*todopb.PullChangesResponse, error)
+	// PushChanges merges the specified changes into the to-do list.
+	PushChanges(ctx context.Context, changes []*todopb.TaskChange, since time.Time) (*todopb.PushChangesResponse, error)
+	// Close releases the resources held by the peer.
+	Close() error
+}
+
+var _ SyncPeer = (*Client)(nil)
+
+// NewSyncPeer creates the [SyncPeer] for the To-do Daemon listening on the
+// specified address. If the address is an HTTP or HTTPS URL, e.g.
+// "http://desktop:8080", the peer uses the REST API of the To-do Daemon, which
+// is reachable from other computers. Otherwise, it is a [Client] created with
+// [New].
+func NewSyncPeer(address string, opts ...Option) (SyncPeer, error) {
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		return New(address, opts...)
+	}
+	base, err := url.Parse(strings.TrimSuffix(address, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid address '%s': %w", address, err)
+	}
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &restPeer{base: base, http: &http.Client{Timeout: o.timeout}}, nil
+}
+
+// restPeer is a [SyncPeer] using the REST API of a To-do Daemon.
+type restPeer struct {
+	base *url.URL
+	http *http.Client
+}
+
+func (p *restPeer) PullChanges(ctx context.Context, since time.Time) (*todopb.PullChangesResponse, error) {
+	u := p.base.JoinPath("/api/v1/changes")
+	if !since.IsZero() {
+		u.RawQuery = url.Values{"since": {since.UTC().Format(time.RFC3339Nano)}}.Encode()
+	}
+	resp := &todopb.PullChangesResponse{}
+	if err := p.do(ctx, http.MethodGet, u, nil, resp); err != nil {
+		return nil, fmt.Errorf("cannot retrieve changes: %w", err)
+	}
+	return resp, nil
+}
+
+func (p *restPeer) PushChanges(
+	ctx context.Context,
+	changes []*todopb.TaskChange,
+	since time.Time,
+) (*todopb.PushChangesResponse, error) {
+	req := &todopb.PushChangesRequest{Changes: changes, Since: optionalTimestamp(since)}
+	resp := &todopb.PushChangesResponse{}
+	if err := p.do(ctx, http.MethodPost, p.base.JoinPath("/api/v1/changes"), req, resp); err != nil {
+		return nil, fmt.Errorf("cannot merge changes: %w", err)
+	}
+	return resp, nil
+}
+
+// do sends an HTTP request with the specified message as JSON body, if any,
+// and decodes the JSON body of the response into the specified message. Error
+// responses are converted into errors with the detail of the problem.
+func (p *restPeer) do(ctx context.Context, method string, u *url.URL, body, out proto.Message) error {
+	var r io.Reader
+	if body != nil {
+		b, err := protojson.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return err
+	}
+	b, err := io.ReadAll(resp.Body)
+	if cerr := resp.Body.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		var problem rest.Problem
+		if json.Unmarshal(b, &problem) != nil || problem.Title == "" {
+			return fmt.Errorf("%s %s: %s", method, u, resp.Status)
+		}
+		if problem.Detail != "" {
+			return fmt.Errorf("%s: %s", problem.Title, problem.Detail)
+		}
+		return errors.New(problem.Title)
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(b, out)
+}
+
+func (p *restPeer) Close() error {
+	p.http.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/mwopitz/todo-daemon/internal/version"
+)
+
+// withVersion adds the version of the CLI to the outgoing metadata of the
+// specified context, so the server can reject unsupported versions.
+func withVersion(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, version.MetadataKey, version.Semantic())
+}
+
+// checkUnknownMethod explains the errors of calls to methods the server
+// doesn't know, which usually means that the server is older than the CLI.
+func checkUnknownMethod(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.Unimplemented {
+		return err
+	}
+	if msg := st.Message(); strings.HasPrefix(msg, "unknown method ") || strings.HasPrefix(msg, "unknown service ") {
+		return status.Errorf(codes.Unimplemented,
+			"the server does not support this command, probably because it is older than the CLI (version %s); "+
+				"restart the server to update it: %s", version.Semantic(), msg)
+	}
+	return err
+}
+
+func versionUnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		return checkUnknownMethod(invoker(withVersion(ctx), method, req, reply, cc, opts...))
+	}
+}
+
+func versionStreamInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		stream, err := streamer(withVersion(ctx), desc, cc, method, opts...)
+		return stream, checkUnknownMethod(err)
+	}
+}
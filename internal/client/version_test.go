@@ -0,0 +1,31 @@
+package client
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCheckUnknownMethod(t *testing.T) {
+	unknown := status.Error(codes.Unimplemented, "unknown method ResolveTask for service todo.v1.TodoService")
+	err := checkUnknownMethod(unknown)
+	if status.Code(err) != codes.Unimplemented || !strings.Contains(err.Error(), "older than the CLI") {
+		t.Errorf("want explanation of unknown method; got: %v", err)
+	}
+
+	// Other errors, including those of methods that the server knows but
+	// hasn't enabled, are returned unchanged.
+	for _, err := range []error{
+		nil,
+		errors.New("foo"),
+		status.Error(codes.Unimplemented, "configuration reloading is not enabled"),
+		status.Error(codes.NotFound, "unknown method"),
+	} {
+		if got := checkUnknownMethod(err); got != err {
+			t.Errorf("want error unchanged: %v; got: %v", err, got)
+		}
+	}
+}
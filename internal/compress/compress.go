@@ -0,0 +1,275 @@
+// Package compress compresses the HTTP responses of the To-do Daemon's REST
+// API with gzip or deflate, depending on the Accept-Encoding header of the
+// request, so large task lists and backups transfer faster to remote clients.
+package compress
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultMinSize is the default minimum size of the responses that are
+// compressed, in bytes.
+const DefaultMinSize = 1024
+
+// The content codings that responses are compressed with. "deflate" is the
+// zlib format, see RFC 9110, section 8.4.1.2.
+const (
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+// encoder is a compressing writer that can be reused for another response.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// Compressor compresses HTTP responses.
+type Compressor struct {
+	// minSize is the minimum size of the compressed responses in bytes.
+	minSize int
+	// pools hold the unused encoders by content coding.
+	pools map[string]*sync.Pool
+}
+
+// New creates a [Compressor] that compresses responses of at least the
+// specified size in bytes. Smaller responses are sent as is, since compressing
+// them saves little, if anything. A size <= 0 means [DefaultMinSize].
+func New(minSize int) *Compressor {
+	if minSize <= 0 {
+		minSize = DefaultMinSize
+	}
+	return &Compressor{
+		minSize: minSize,
+		pools: map[string]*sync.Pool{
+			encodingGzip: {New: func() any {
+				return gzip.NewWriter(io.Discard)
+			}},
+			encodingDeflate: {New: func() any {
+				return zlib.NewWriter(io.Discard)
+			}},
+		},
+	}
+}
+
+// Middleware returns a handler that compresses the responses of the specified
+// handler if the client accepts gzip or deflate. Responses that are small,
+// not text, partial, or already encoded are sent as is, and so are streaming
+// responses, e.g. server-sent events, that are flushed before reaching the
+// minimum size.
+func (c *Compressor) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiate(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &responseWriter{ResponseWriter: w, compressor: c, encoding: encoding}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiate returns the preferred content coding of the specified
+// Accept-Encoding header that is supported, or "" if the client accepts none.
+// Gzip is preferred if the client accepts both with the same quality.
+func negotiate(accept string) string {
+	var best string
+	var bestQ float64
+	for entry := range strings.SplitSeq(accept, ",") {
+		name, params, _ := strings.Cut(entry, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if name == "*" || name == "x-gzip" {
+			name = encodingGzip
+		}
+		if name != encodingGzip && name != encodingDeflate || q <= 0 {
+			continue
+		}
+		if q > bestQ || (q == bestQ && name == encodingGzip) {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+// compressible checks if responses with the specified content type are worth
+// compressing, i.e. if they are text.
+func compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"):
+		return mediaType != "text/event-stream"
+	case mediaType == "application/json", mediaType == "application/javascript",
+		mediaType == "application/xml", mediaType == "image/svg+xml":
+		return true
+	case strings.HasSuffix(mediaType, "+json"), strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	return false
+}
+
+// responseWriter is an [http.ResponseWriter] that holds back the response
+// until it reaches the minimum size, and then compresses it.
+type responseWriter struct {
+	http.ResponseWriter
+	compressor *Compressor
+	encoding   string
+	// code is the status code of the response, or zero if the header has not
+	// been written yet.
+	code int
+	// buf holds the beginning of the response body until it is decided
+	// whether to compress the response.
+	buf []byte
+	// decided specifies whether the header has been written, with or without
+	// compression.
+	decided bool
+	// enc is the encoder that compresses the response, or nil if it is sent
+	// as is.
+	enc encoder
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.code != 0 {
+		return
+	}
+	if code < http.StatusOK {
+		// Informational responses precede the actual response.
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.code = code
+	if !w.eligible() {
+		// revive:disable-next-line:unhandled-error
+		w.decide(false)
+	}
+}
+
+// eligible checks if the response may be compressed, judging by its status
+// code and headers.
+func (w *responseWriter) eligible() bool {
+	h := w.Header()
+	switch {
+	case w.code == http.StatusNoContent, w.code == http.StatusNotModified, w.code == http.StatusPartialContent:
+		return false
+	case h.Get("Content-Encoding") != "", h.Get("Content-Range") != "":
+		return false
+	case !compressible(h.Get("Content-Type")):
+		return false
+	}
+	if n, err := strconv.Atoi(h.Get("Content-Length")); err == nil && n < w.compressor.minSize {
+		return false
+	}
+	return true
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.code == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.decided {
+		w.buf = append(w.buf, b...)
+		if len(w.buf) < w.compressor.minSize {
+			return len(b), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	if w.enc != nil {
+		return w.enc.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// decide writes the header, compressing the response or not, followed by the
+// buffered beginning of the body.
+func (w *responseWriter) decide(compress bool) error {
+	w.decided = true
+	if compress {
+		h := w.Header()
+		h.Set("Content-Encoding", w.encoding)
+		h.Del("Content-Length")
+		w.enc = w.compressor.pools[w.encoding].Get().(encoder)
+		w.enc.Reset(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.code)
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.enc != nil {
+		_, err = w.enc.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// FlushError sends the response written so far to the client. A response that
+// is flushed before it reaches the minimum size is not compressed.
+func (w *responseWriter) FlushError() error {
+	if w.code == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.decided {
+		if err := w.decide(false); err != nil {
+			return err
+		}
+	}
+	if w.enc != nil {
+		if err := w.enc.Flush(); err != nil {
+			return err
+		}
+	}
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Flush implements [http.Flusher].
+func (w *responseWriter) Flush() {
+	// revive:disable-next-line:unhandled-error
+	w.FlushError()
+}
+
+// Unwrap returns the underlying response writer for [http.ResponseController].
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// close writes the rest of the response once the handler has returned.
+func (w *responseWriter) close() {
+	if w.code != 0 && !w.decided {
+		// The response is smaller than the minimum size.
+		// revive:disable-next-line:unhandled-error
+		w.decide(false)
+	}
+	if w.enc != nil {
+		// revive:disable-next-line:unhandled-error
+		w.enc.Close()
+		w.enc.Reset(io.Discard)
+		w.compressor.pools[w.encoding].Put(w.enc)
+		w.enc = nil
+	}
+}
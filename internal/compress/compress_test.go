@@ -0,0 +1,187 @@
+package compress
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", ""},
+		{"gzip", encodingGzip},
+		{"deflate", encodingDeflate},
+		{"deflate, gzip", encodingGzip},
+		{"gzip;q=0.5, deflate", encodingDeflate},
+		{"gzip;q=0, deflate;q=0", ""},
+		{"br", ""},
+		{"*", encodingGzip},
+		{"GZIP", encodingGzip},
+		{"x-gzip", encodingGzip},
+		{"identity", ""},
+	}
+	for _, tt := range tests {
+		if got := negotiate(tt.accept); got != tt.want {
+			t.Errorf("negotiate(%q): want: %q; got: %q", tt.accept, tt.want, got)
+		}
+	}
+}
+
+func TestCompressible(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{"application/json", true},
+		{"application/json; charset=utf-8", true},
+		{"application/problem+json", true},
+		{"text/calendar; charset=utf-8", true},
+		{"text/html", true},
+		{"text/event-stream", false},
+		{"image/png", false},
+		{"application/gzip", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := compressible(tt.contentType); got != tt.want {
+			t.Errorf("compressible(%q): want: %t; got: %t", tt.contentType, tt.want, got)
+		}
+	}
+}
+
+// serve serves a request with the specified Accept-Encoding header using a
+// handler that writes the specified body with the specified content type in
+// chunks of 100 bytes.
+func serve(t *testing.T, c *Compressor, accept, contentType, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	handler := c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		for s := body; s != ""; {
+			n := min(len(s), 100)
+			if _, err := io.WriteString(w, s[:n]); err != nil {
+				t.Error(err)
+			}
+			s = s[n:]
+		}
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/v1/tasks", nil)
+	if accept != "" {
+		req.Header.Set("Accept-Encoding", accept)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware(t *testing.T) {
+	large := strings.Repeat(`{"summary":"Get some milk"},`, 100)
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"Gzip", "gzip", "application/json", large, encodingGzip},
+		{"Deflate", "deflate", "application/json", large, encodingDeflate},
+		{"NotAccepted", "", "application/json", large, ""},
+		{"Small", "gzip", "application/json", `{"tasks":[]}`, ""},
+		{"NotText", "gzip", "image/png", large, ""},
+	}
+	c := New(1024)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, c, tt.accept, tt.contentType, tt.body)
+			if got := rec.Header().Get("Content-Encoding"); got != tt.want {
+				t.Errorf("want Content-Encoding %q; got: %q", tt.want, got)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("want Vary: Accept-Encoding; got: %q", got)
+			}
+			var r io.Reader = rec.Body
+			var err error
+			switch tt.want {
+			case encodingGzip:
+				r, err = gzip.NewReader(rec.Body)
+			case encodingDeflate:
+				r, err = zlib.NewReader(rec.Body)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(body) != tt.body {
+				t.Errorf("want body of %d bytes; got: %q", len(tt.body), body)
+			}
+		})
+	}
+}
+
+func TestMiddlewareReusesEncoders(t *testing.T) {
+	c := New(10)
+	for i := range 3 {
+		body := strings.Repeat(strconv.Itoa(i), 1000)
+		rec := serve(t, c, "gzip", "text/plain", body)
+		r, err := gzip.NewReader(rec.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != body {
+			t.Errorf("response %d: want %d bytes; got: %q", i, len(body), got)
+		}
+	}
+}
+
+func TestMiddlewareFlush(t *testing.T) {
+	handler := New(1024).Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		// revive:disable-next-line:unhandled-error
+		io.WriteString(w, "event\n")
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Error(err)
+		}
+		// revive:disable-next-line:unhandled-error
+		io.WriteString(w, strings.Repeat("x", 2000))
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/v1/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if !rec.Flushed {
+		t.Error("want flushed response")
+	}
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("want uncompressed streaming response; got Content-Encoding %q", got)
+	}
+	if want := 6 + 2000; rec.Body.Len() != want {
+		t.Errorf("want %d bytes; got: %d", want, rec.Body.Len())
+	}
+}
+
+func TestMiddlewareNoContent(t *testing.T) {
+	handler := New(1024).Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	req := httptest.NewRequest(http.MethodDelete, "/v1/tasks/1", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Content-Encoding") != "" {
+		t.Errorf("want uncompressed 204; got: %d (%s)", rec.Code, rec.Header().Get("Content-Encoding"))
+	}
+}
@@ -2,28 +2,514 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/transport"
+)
+
+// The environment variables that override the corresponding configuration
+// values, both the defaults and the values from the configuration file.
+const (
+	EnvConfigFile      = "TODO_DAEMON_CONFIG"
+	EnvProfile         = "TODO_DAEMON_PROFILE"
+	EnvLockFile        = "TODO_DAEMON_LOCK"
+	EnvSockFile        = "TODO_DAEMON_SOCK"
+	EnvDatabase        = "TODO_DAEMON_DB"
+	EnvLogLevel        = "TODO_DAEMON_LOG_LEVEL"
+	EnvShutdownTimeout = "TODO_DAEMON_SHUTDOWN_TIMEOUT"
+	EnvReadOnly        = "TODO_DAEMON_READ_ONLY"
+	EnvHTTPListen      = "TODO_DAEMON_HTTP_LISTEN"
+	EnvStandalone      = "TODO_DAEMON_STANDALONE"
+	EnvTimeZone        = "TODO_DAEMON_TIME_ZONE"
+	// EnvBackupPassphrase overrides the passphrase of the remote backups, so
+	// it doesn't need to be stored in the configuration file.
+	EnvBackupPassphrase = "TODO_DAEMON_BACKUP_PASSPHRASE"
+)
+
+// The environment variables of the AWS SDKs and CLI, which provide the
+// credentials of the remote backups if the configuration file has none.
+const (
+	EnvAWSAccessKeyID     = "AWS_ACCESS_KEY_ID"
+	EnvAWSSecretAccessKey = "AWS_SECRET_ACCESS_KEY"
 )
 
+// DatabaseMemory is the database that keeps all tasks in memory only.
+const DatabaseMemory = "memory"
+
 // Config holds the configuration of the To-do Daemon.
 type Config struct {
+	// Profile is the name of the profile that the configuration belongs to,
+	// see [Load].
+	Profile string `json:"-"`
 	// LockFile holds the path to the lock file used by the To-do Daemon server
 	// to ensure that only a single instance of the server can be running.
 	LockFile string `json:"lock_file"`
-	// SockFile holds the path to the UNIX socket file used for communication
-	// between the To-do Daemon server process and the command processes.
+	// SockFile holds the address of the Unix socket or named pipe used for
+	// communication between the To-do Daemon server process and the command
+	// processes. See [transport.ParseAddress] for the address format.
 	SockFile string `json:"sock_file"`
+	// SocketMode is the octal file mode of the Unix socket that the To-do
+	// Daemon server listens on, e.g. "0660". If empty, only the user running
+	// the server, and the members of SocketGroup if set, can connect to it.
+	SocketMode string `json:"socket_mode"`
+	// SocketGroup is the name or ID of the group owning the Unix socket that
+	// the To-do Daemon server listens on, whose members can connect to it.
+	SocketGroup string `json:"socket_group"`
+	// Database is the data source name (DSN) of the storage backend where the
+	// To-do Daemon server stores the tasks. Its scheme selects the backend,
+	// see package storage.
+	Database string `json:"database"`
+	// LogLevel is the minimum level of the log messages to print, i.e.
+	// "debug", "info", "warn", or "error".
+	LogLevel string `json:"log_level"`
+	// LogFile is the path of the file that the To-do Daemon server appends
+	// its log messages to, in addition to printing them to stderr. If empty,
+	// they are only printed, but clients can still tail them via the server.
+	LogFile string `json:"log_file"`
+	// Locale is the language of the natural-language due times accepted by
+	// the CLI, e.g. "de". If empty, it is derived from the environment.
+	Locale string `json:"locale"`
+	// TimeZone is the IANA name of the default time zone of the To-do
+	// Daemon, e.g. "Europe/Berlin". The server assigns it to new tasks without
+	// a time zone and computes day boundaries in it, and the CLI interprets
+	// and prints times in it. If empty, the local time zone is used.
+	TimeZone string `json:"time_zone"`
+	// ShutdownTimeout is the maximum amount of time the To-do Daemon server
+	// waits for active requests to finish before it forcibly stops.
+	ShutdownTimeout Duration `json:"shutdown_timeout"`
+	// MaxRequestDuration is the maximum amount of time the To-do Daemon
+	// server spends on a single request.
+	MaxRequestDuration Duration `json:"max_request_duration"`
+	// ReadOnly specifies whether the To-do Daemon server rejects all requests
+	// that would modify data.
+	ReadOnly bool `json:"read_only"`
+	// HTTPListen is the address that the To-do Daemon server's HTTP server
+	// listens on: a TCP address like "localhost:8080", a Unix domain socket
+	// address like "unix:///run/user/1000/todo-daemon-http.sock", or "off".
+	HTTPListen string `json:"http_listen"`
+	// ExternalURL is the URL that clients use to reach the To-do Daemon
+	// server's HTTP server, e.g. "https://example.com/todo" behind a reverse
+	// proxy. If empty, the URL is derived from the listen address.
+	ExternalURL string `json:"external_url"`
+	// TrustedProxies holds the addresses of the reverse proxies whose
+	// X-Forwarded-* headers the To-do Daemon server honors: IP addresses,
+	// networks like "10.0.0.0/8", or "unix" for the clients of the HTTP
+	// server's Unix domain socket.
+	TrustedProxies []string `json:"trusted_proxies"`
+	// StrictDependencies specifies whether the To-do Daemon server rejects
+	// completing a task that depends on tasks that are still open.
+	StrictDependencies bool `json:"strict_dependencies"`
+	// UniqueSummaries specifies whether the To-do Daemon server rejects
+	// creating a task whose summary equals the summary of an open task,
+	// ignoring case.
+	UniqueSummaries bool `json:"unique_summaries"`
+	// IdempotencyWindow is how long the To-do Daemon server remembers the
+	// tasks created by requests with an idempotency key, so that retried
+	// requests don't create the same task twice. Zero disables idempotency
+	// keys.
+	IdempotencyWindow Duration `json:"idempotency_window"`
+	// WatchBufferSize is the number of events the To-do Daemon server buffers
+	// for each client watching the tasks.
+	WatchBufferSize int `json:"watch_buffer_size"`
+	// WatchOverflow specifies what happens when a client watching the tasks
+	// falls further behind: "drop-oldest" drops the oldest buffered events,
+	// "disconnect" ends the client's stream.
+	WatchOverflow string `json:"watch_overflow"`
+	// MaxRecvMsgSize and MaxSendMsgSize are the maximum sizes of the gRPC
+	// messages that the To-do Daemon server receives and sends, in bytes. The
+	// CLI applies the same limits the other way round.
+	MaxRecvMsgSize int `json:"max_recv_msg_size"`
+	MaxSendMsgSize int `json:"max_send_msg_size"`
+	// GRPCCompression specifies whether the CLI compresses its gRPC messages
+	// with gzip, e.g. for a server reached through a slow connection.
+	GRPCCompression bool `json:"grpc_compression"`
+	// WebUI specifies whether the To-do Daemon server serves the web UI.
+	WebUI bool `json:"web_ui"`
+	// Webhooks holds the webhooks that the To-do Daemon server notifies about
+	// task events.
+	Webhooks []Webhook `json:"webhooks"`
+	// Filters holds the named filters that tasks can be listed by, in
+	// addition to the filters saved via the API.
+	Filters []Filter `json:"filters"`
+	// RateLimit limits the requests to the REST API of the To-do Daemon
+	// server.
+	RateLimit RateLimit `json:"rate_limit"`
+	// CORS holds the configuration of cross-origin requests to the REST API
+	// of the To-do Daemon server.
+	CORS CORS `json:"cors"`
+	// Compression holds the configuration of the compression of the HTTP
+	// responses of the To-do Daemon server.
+	Compression Compression `json:"compression"`
+	// Hardening holds the limits of the requests to the HTTP server of the
+	// To-do Daemon server and the security headers of its responses.
+	Hardening Hardening `json:"hardening"`
+	// Quotas holds the soft limits of the to-do list, which protect the
+	// storage of the To-do Daemon from misbehaving clients.
+	Quotas Quotas `json:"quotas"`
+	// Hooks holds the configuration of the hook scripts that the To-do Daemon
+	// server executes on task events.
+	Hooks Hooks `json:"hooks"`
+	// Backup holds the configuration of the scheduled snapshots of the tasks.
+	Backup Backup `json:"backup"`
+	// Profiles holds the configuration file sections of the profiles, keyed
+	// by profile name. Each section may contain any of the settings above,
+	// which override the settings for all profiles.
+	Profiles map[string]json.RawMessage `json:"profiles"`
+}
+
+// Backup holds the configuration of the scheduled snapshots.
+type Backup struct {
+	// Dir is the directory that the snapshots are written to.
+	Dir string `json:"dir"`
+	// Interval is the time between two snapshots. Zero disables the scheduled
+	// snapshots.
+	Interval Duration `json:"interval"`
+	// Retain is the number of snapshots to keep. Zero keeps all snapshots.
+	Retain int `json:"retain"`
+	// Remote holds the configuration of uploading the snapshots to
+	// S3-compatible object storage.
+	Remote BackupRemote `json:"remote"`
+}
+
+// BackupRemote holds the configuration of uploading the scheduled snapshots to
+// S3-compatible object storage, e.g. Amazon S3 or MinIO. The snapshots are
+// always encrypted before they are uploaded.
+type BackupRemote struct {
+	// URL is the bucket and the prefix of the snapshots, e.g.
+	// "s3://my-bucket/todo/". Empty disables the remote backups.
+	URL string `json:"url"`
+	// Endpoint is the base URL of the object storage, e.g.
+	// "http://localhost:9000" for MinIO. Empty means Amazon S3.
+	Endpoint string `json:"endpoint"`
+	// Region is the region of the bucket. Empty means "us-east-1".
+	Region string `json:"region"`
+	// AccessKeyID and SecretAccessKey are the credentials for the object
+	// storage. If empty, the environment variables [EnvAWSAccessKeyID] and
+	// [EnvAWSSecretAccessKey] are used.
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	// Passphrase is the passphrase that the snapshots are encrypted with. It
+	// is overridden by the environment variable [EnvBackupPassphrase].
+	Passphrase string `json:"passphrase"`
+	// Retain is the number of snapshots to keep in the bucket. Zero keeps all
+	// snapshots.
+	Retain int `json:"retain"`
+}
+
+// CORS holds the configuration of Cross-Origin Resource Sharing (CORS).
+type CORS struct {
+	// AllowedOrigins are the origins that may make cross-origin requests,
+	// e.g. "https://example.com", or "*" for all origins. If empty,
+	// cross-origin requests are not allowed.
+	AllowedOrigins []string `json:"allowed_origins"`
+	// AllowedMethods are the HTTP methods that cross-origin requests may use
+	// in addition to GET, HEAD, and POST.
+	AllowedMethods []string `json:"allowed_methods"`
+	// AllowedHeaders are the request headers that cross-origin requests may
+	// set.
+	AllowedHeaders []string `json:"allowed_headers"`
+	// MaxAge is how long browsers may cache the results of preflight
+	// requests.
+	MaxAge Duration `json:"max_age"`
+}
+
+// Compression holds the configuration of the HTTP response compression.
+type Compression struct {
+	// Enabled specifies whether responses are compressed with gzip or
+	// deflate if the client accepts it.
+	Enabled bool `json:"enabled"`
+	// MinSize is the minimum size of the compressed responses in bytes.
+	// Smaller responses are sent as is.
+	MinSize int `json:"min_size"`
+}
+
+// Hardening holds the configuration of the HTTP request limits and security
+// headers.
+type Hardening struct {
+	// MaxBodySize is the maximum size of request bodies in bytes. Larger
+	// requests are rejected.
+	MaxBodySize int64 `json:"max_body_size"`
+	// SecurityHeaders specifies whether responses carry security headers
+	// like Content-Security-Policy and X-Content-Type-Options.
+	SecurityHeaders bool `json:"security_headers"`
+}
+
+// Hooks holds the configuration of the hook scripts.
+type Hooks struct {
+	// Dir is the directory containing the hook scripts, which are named after
+	// the events that trigger them, e.g. "on-complete".
+	Dir string `json:"dir"`
+	// Allow holds the names of the hook scripts that may be executed. Scripts
+	// that are not on this list are never executed.
+	Allow []string `json:"allow"`
+	// Timeout is the maximum amount of time a hook script may run.
+	Timeout Duration `json:"timeout"`
+	// MaxConcurrent is the maximum number of hook scripts running at the same
+	// time.
+	MaxConcurrent int `json:"max_concurrent"`
+}
+
+// Quotas holds the configuration of the soft limits of the to-do list.
+type Quotas struct {
+	// MaxOpenTasks is the maximum number of open tasks. Zero means no limit.
+	MaxOpenTasks int `json:"max_open_tasks"`
+	// MaxTagsPerTask is the maximum number of tags of a task, which can only
+	// lower the built-in limit. Zero means the built-in limit.
+	MaxTagsPerTask int `json:"max_tags_per_task"`
+}
+
+// RateLimit holds the configuration of the REST API's rate limiter.
+type RateLimit struct {
+	// Global limits the requests from all clients combined.
+	Global Limit `json:"global"`
+	// PerIP limits the requests from each remote IP address.
+	PerIP Limit `json:"per_ip"`
+}
+
+// Limit holds the configuration of a single token bucket.
+type Limit struct {
+	// Rate is the number of requests per second allowed on average. A rate of
+	// zero disables the limit.
+	Rate float64 `json:"rate"`
+	// Burst is the maximum number of requests allowed at once.
+	Burst int `json:"burst"`
+}
+
+// Webhook holds the configuration of a single webhook.
+type Webhook struct {
+	// URL is the URL that the To-do Daemon server sends the events to.
+	URL string `json:"url"`
+	// Secret is the key used for signing the webhook payloads.
+	Secret string `json:"secret"`
+	// Events are the types of events that trigger the webhook. If empty, the
+	// webhook is triggered by all events.
+	Events []string `json:"events"`
+	// Assignee, if non-empty, restricts the webhook to the events of the
+	// tasks assigned to this user.
+	Assignee string `json:"assignee"`
+}
+
+// Filter holds the configuration of a single named filter.
+type Filter struct {
+	// Name is the name of the filter, e.g. "urgent-work".
+	Name string `json:"name"`
+	// Status selects tasks by their completion state: "open", "completed",
+	// or empty for all tasks.
+	Status string `json:"status"`
+	// Tags selects only tasks that have all of these tags.
+	Tags []string `json:"tags"`
+	// Project selects only tasks of this project.
+	Project string `json:"project"`
+	// Due selects tasks by their due time: "today", "week", "overdue", or
+	// empty for all tasks.
+	Due string `json:"due"`
+	// Starred selects only tasks that are starred.
+	Starred bool `json:"starred"`
+}
+
+// Duration is a [time.Duration] that is represented as string, e.g. "10s", in
+// the configuration file.
+type Duration time.Duration
+
+// MarshalJSON encodes the duration as JSON string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON decodes the duration from a JSON string.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid duration: %s", data)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
 }
 
-// New returns a configuration with default values.
+// New returns a configuration of the default profile with default values,
+// overridden by the values of the corresponding environment variables.
 func New() *Config {
-	return &Config{
-		LockFile: defaultLockFile(),
-		SockFile: defaultSockFile(),
+	return newProfile(DefaultProfile)
+}
+
+// newProfile returns a configuration of the specified profile with default
+// values, overridden by the values of the corresponding environment variables.
+func newProfile(profile string) *Config {
+	conf := &Config{
+		Profile:            profile,
+		LockFile:           defaultLockFile(profile),
+		SockFile:           defaultSockFile(profile),
+		Database:           DatabaseMemory,
+		LogLevel:           "info",
+		ShutdownTimeout:    Duration(10 * time.Second),
+		MaxRequestDuration: Duration(30 * time.Second),
+		IdempotencyWindow:  Duration(24 * time.Hour),
+		WatchBufferSize:    64,
+		WatchOverflow:      "drop-oldest",
+		MaxRecvMsgSize:     16 << 20,
+		MaxSendMsgSize:     16 << 20,
+		HTTPListen:         "localhost:0",
+		WebUI:              true,
+		RateLimit: RateLimit{
+			Global: Limit{Rate: 200, Burst: 400},
+			PerIP:  Limit{Rate: 50, Burst: 100},
+		},
+		CORS: CORS{
+			AllowedMethods: []string{"PATCH", "DELETE"},
+			AllowedHeaders: []string{
+				"Content-Type", "Idempotency-Key", "If-Match", "If-None-Match", "If-Modified-Since", "X-Request-ID",
+			},
+			MaxAge: Duration(10 * time.Minute),
+		},
+		Compression: Compression{
+			Enabled: true,
+			MinSize: 1024,
+		},
+		Hardening: Hardening{
+			MaxBodySize:     4 << 20,
+			SecurityHeaders: true,
+		},
+		Hooks: Hooks{
+			Dir:           defaultHooksDir(),
+			Timeout:       Duration(10 * time.Second),
+			MaxConcurrent: 4,
+		},
+		Backup: Backup{
+			Dir:    filepath.Join(dataDir(profile), "backups"),
+			Retain: 7,
+			Remote: BackupRemote{
+				Retain: 30,
+			},
+		},
+	}
+	conf.applyEnv()
+	return conf
+}
+
+// Location returns the default time zone of the To-do Daemon, see
+// [Config.TimeZone].
+func (c *Config) Location() (*time.Location, error) {
+	if c.TimeZone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone: %w", err)
+	}
+	return loc, nil
+}
+
+// applyEnv overrides the configuration values with the values of the
+// corresponding environment variables. Invalid values are ignored.
+func (c *Config) applyEnv() {
+	values := map[string]*string{
+		EnvLockFile:   &c.LockFile,
+		EnvSockFile:   &c.SockFile,
+		EnvDatabase:   &c.Database,
+		EnvLogLevel:   &c.LogLevel,
+		EnvHTTPListen: &c.HTTPListen,
+		EnvTimeZone:   &c.TimeZone,
+	}
+	for env, value := range values {
+		if v, ok := os.LookupEnv(env); ok {
+			*value = v
+		}
+	}
+	if v, ok := os.LookupEnv(EnvBackupPassphrase); ok {
+		c.Backup.Remote.Passphrase = v
+	}
+	if v, ok := os.LookupEnv(EnvAWSAccessKeyID); ok && c.Backup.Remote.AccessKeyID == "" {
+		c.Backup.Remote.AccessKeyID = v
+	}
+	if v, ok := os.LookupEnv(EnvAWSSecretAccessKey); ok && c.Backup.Remote.SecretAccessKey == "" {
+		c.Backup.Remote.SecretAccessKey = v
+	}
+	if v, ok := os.LookupEnv(EnvShutdownTimeout); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			slog.Warn("ignoring invalid environment variable", "name", EnvShutdownTimeout, "cause", err)
+		} else {
+			c.ShutdownTimeout = Duration(d)
+		}
+	}
+	if v, ok := os.LookupEnv(EnvReadOnly); ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			slog.Warn("ignoring invalid environment variable", "name", EnvReadOnly, "cause", err)
+		} else {
+			c.ReadOnly = b
+		}
+	}
+}
+
+// Load returns the configuration of the specified profile with default values,
+// overridden by the values in the specified JSON configuration file, which are
+// in turn overridden by the values of the corresponding environment variables.
+// The values in the profile's section of the file take precedence over the
+// other values in the file. If the file does not exist, it just returns the
+// default configuration of the profile.
+//
+// The default lock file, socket, and backup directory of each profile other
+// than [DefaultProfile] include the profile's name, so the servers of
+// different profiles can run side by side.
+func Load(path, profile string) (*Config, error) {
+	if err := ValidateProfile(profile); err != nil {
+		return nil, err
+	}
+	conf := newProfile(profile)
+	data, err := os.ReadFile(path) // #nosec G304 -- the path is user-specified.
+	if errors.Is(err, os.ErrNotExist) {
+		return conf, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read config file: %w", err)
+	}
+	if err := json.Unmarshal(data, conf); err != nil {
+		return nil, fmt.Errorf("invalid config file '%s': %w", path, err)
+	}
+	if section, ok := conf.Profiles[profile]; ok {
+		if err := json.Unmarshal(section, conf); err != nil {
+			return nil, fmt.Errorf("invalid profile '%s' in config file '%s': %w", profile, path, err)
+		}
 	}
+	conf.applyEnv()
+	return conf, nil
+}
+
+// DefaultFile returns the path to the configuration file specified by the
+// environment variable [EnvConfigFile], or the path to the default
+// configuration file in the user's configuration directory.
+func DefaultFile() string {
+	if path, ok := os.LookupEnv(EnvConfigFile); ok {
+		return path
+	}
+	return filepath.Join(configDir(), "config.json")
+}
+
+// configDir returns the To-do Daemon's directory in the user's configuration
+// directory.
+func configDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = runDir()
+	}
+	return filepath.Join(dir, "todo-daemon")
+}
+
+func defaultHooksDir() string {
+	return filepath.Join(configDir(), "hooks")
 }
 
 func runDir() string {
@@ -35,10 +521,15 @@ func runDir() string {
 	}
 }
 
-func defaultLockFile() string {
-	return filepath.Join(runDir(), "todo-daemon.lock")
+func defaultLockFile(profile string) string {
+	return filepath.Join(runDir(), "todo-daemon"+profileSuffix(profile)+".lock")
 }
 
-func defaultSockFile() string {
-	return filepath.Join(runDir(), "todo-daemon.sock")
+func defaultSockFile(profile string) string {
+	switch runtime.GOOS {
+	case "windows":
+		return transport.SchemeNamedPipe + ":////./pipe/todo-daemon" + profileSuffix(profile)
+	default:
+		return filepath.Join(runDir(), "todo-daemon"+profileSuffix(profile)+".sock")
+	}
 }
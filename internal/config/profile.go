@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultProfile is the profile used if no other profile is specified. Its
+// files have the names that the To-do Daemon used before profiles existed.
+const DefaultProfile = "default"
+
+// profileNamePattern matches valid profile names, which become part of file
+// names.
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// ValidateProfile checks that the specified profile name consists of letters,
+// digits, underscores, and hyphens only, and starts with a letter or digit.
+func ValidateProfile(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name: '%s'", name)
+	}
+	return nil
+}
+
+// KnownProfiles returns the names of the profiles that have a section in the
+// configuration or a lock file at the default location, along with the default
+// profile, in alphabetical order.
+func (c *Config) KnownProfiles() []string {
+	names := []string{DefaultProfile}
+	for name := range c.Profiles {
+		if ValidateProfile(name) == nil {
+			names = append(names, name)
+		}
+	}
+	// Glob only fails for malformed patterns.
+	paths, _ := filepath.Glob(filepath.Join(runDir(), "todo-daemon-*.lock"))
+	for _, path := range paths {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "todo-daemon-"), ".lock")
+		if ValidateProfile(name) == nil {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// profileSuffix returns the suffix that namespaces the file names of the
+// specified profile, e.g. "-work" for "todo-daemon-work.sock".
+func profileSuffix(profile string) string {
+	if profile == DefaultProfile {
+		return ""
+	}
+	return "-" + profile
+}
+
+// dataDir returns the directory holding the data of the specified profile,
+// e.g. its backups.
+func dataDir(profile string) string {
+	if profile == DefaultProfile {
+		return configDir()
+	}
+	return filepath.Join(configDir(), "profiles", profile)
+}
+
+// SyncStateFile returns the path of the file holding the state of the
+// synchronization with other To-do Daemons, i.e. up to which time the changes
+// of each peer were exchanged.
+func (c *Config) SyncStateFile() string {
+	return filepath.Join(dataDir(c.Profile), "sync.json")
+}
+
+// QueueFile returns the path of the journal of the offline queue, which holds
+// the tasks added with 'tasks add --offline' while the server is not running.
+func (c *Config) QueueFile() string {
+	return filepath.Join(dataDir(c.Profile), "queue.jsonl")
+}
+
+// FiltersFile returns the path of the file holding the named filters saved via
+// the API of the To-do Daemon server.
+func (c *Config) FiltersFile() string {
+	return filepath.Join(dataDir(c.Profile), "filters.json")
+}
+
+// TemplatesFile returns the path of the file holding the task templates saved
+// via the API of the To-do Daemon server.
+func (c *Config) TemplatesFile() string {
+	return filepath.Join(dataDir(c.Profile), "templates.json")
+}
+
+// ServiceName returns the name of the operating system's service that runs the
+// server of the profile, see 'service install', e.g. "todo-daemon-work".
+func (c *Config) ServiceName() string {
+	return "todo-daemon" + profileSuffix(c.Profile)
+}
+
+// ServiceLogFile returns the path of the file that the service writes the log
+// of the server to if no log file is configured.
+func (c *Config) ServiceLogFile() string {
+	return filepath.Join(dataDir(c.Profile), "service.log")
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{
+		"log_level": "warn",
+		"http_listen": "localhost:8080",
+		"profiles": {"work": {"http_listen": "localhost:8081"}}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	def, err := Load(path, DefaultProfile)
+	if err != nil {
+		t.Fatalf("Load(%s): %v", DefaultProfile, err)
+	}
+	work, err := Load(path, "work")
+	if err != nil {
+		t.Fatalf("Load(work): %v", err)
+	}
+	if def.HTTPListen != "localhost:8080" || work.HTTPListen != "localhost:8081" {
+		t.Errorf("want HTTP listen addresses localhost:8080 and localhost:8081; got: %s and %s",
+			def.HTTPListen, work.HTTPListen)
+	}
+	if work.LogLevel != "warn" {
+		t.Errorf("want log level of profile: warn; got: %s", work.LogLevel)
+	}
+	if work.Profile != "work" {
+		t.Errorf("want profile: work; got: %s", work.Profile)
+	}
+	for _, paths := range [][2]string{
+		{def.LockFile, work.LockFile},
+		{def.SockFile, work.SockFile},
+		{def.Backup.Dir, work.Backup.Dir},
+	} {
+		if paths[0] == paths[1] || strings.Contains(paths[0], "work") || !strings.Contains(paths[1], "work") {
+			t.Errorf("want only the path of profile work to contain its name; got: %s and %s", paths[0], paths[1])
+		}
+	}
+}
+
+func TestValidateProfile(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{DefaultProfile, true},
+		{"work", true},
+		{"my_profile-2", true},
+		{"", false},
+		{"-work", false},
+		{"../work", false},
+		{"work space", false},
+	}
+	for _, tt := range tests {
+		if err := ValidateProfile(tt.name); (err == nil) != tt.valid {
+			t.Errorf("ValidateProfile(%q): want valid: %t; got error: %v", tt.name, tt.valid, err)
+		}
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "config.json"), "../work"); err == nil {
+		t.Error("want error loading configuration of invalid profile")
+	}
+}
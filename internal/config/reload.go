@@ -0,0 +1,54 @@
+package config
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// reloadable holds the settings that the To-do Daemon server applies when it
+// reloads the configuration file. All other settings only take effect after
+// restarting the server.
+var reloadable = []string{
+	"log_level",
+	"webhooks",
+	"filters",
+	"hooks.dir",
+	"hooks.allow",
+	"hooks.timeout",
+}
+
+// IsReloadable checks if the setting with the specified name, as returned by
+// [Diff], can be changed without restarting the To-do Daemon server.
+func IsReloadable(name string) bool {
+	return slices.Contains(reloadable, name)
+}
+
+// Diff returns the names of the settings that differ between the specified
+// configurations. The names are the keys of the settings in the configuration
+// file, with nested keys joined by dots, e.g. "rate_limit.global.rate".
+// Lists like "webhooks" are compared as a whole.
+func Diff(a, b *Config) []string {
+	var names []string
+	diffStruct(reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem(), "", &names)
+	return names
+}
+
+func diffStruct(a, b reflect.Value, prefix string, names *[]string) {
+	for i := range a.NumField() {
+		field := a.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		name = prefix + name
+		fa, fb := a.Field(i), b.Field(i)
+		if fa.Kind() == reflect.Struct {
+			diffStruct(fa, fb, name+".", names)
+			continue
+		}
+		if !reflect.DeepEqual(fa.Interface(), fb.Interface()) {
+			*names = append(*names, name)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	a := New()
+	b := New()
+	if names := Diff(a, b); len(names) != 0 {
+		t.Errorf("want no differences; got: %v", names)
+	}
+
+	b.LogLevel = "debug"
+	b.SockFile = "/tmp/other.sock"
+	b.RateLimit.Global.Rate++
+	b.Hooks.Timeout = Duration(time.Minute)
+	b.Webhooks = []Webhook{{URL: "https://example.com"}}
+	want := []string{"sock_file", "log_level", "webhooks", "rate_limit.global.rate", "hooks.timeout"}
+	if got := Diff(a, b); !slices.Equal(got, want) {
+		t.Errorf("want: %v; got: %v", want, got)
+	}
+}
+
+func TestIsReloadable(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"log_level", true},
+		{"webhooks", true},
+		{"hooks.allow", true},
+		{"hooks.max_concurrent", false},
+		{"sock_file", false},
+		{"database", false},
+	}
+	for _, tt := range tests {
+		if got := IsReloadable(tt.name); got != tt.want {
+			t.Errorf("IsReloadable(%q): want: %t; got: %t", tt.name, tt.want, got)
+		}
+	}
+}
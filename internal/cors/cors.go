@@ -0,0 +1,154 @@
+// Package cors implements Cross-Origin Resource Sharing (CORS) for the REST API
+// of the To-do Daemon, so browser-based frontends served from other origins can
+// call it.
+package cors
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/rest"
+)
+
+// AnyOrigin is the allowed origin that allows requests from all origins.
+const AnyOrigin = "*"
+
+// exposedHeaders are the response headers that browsers expose to the scripts
+// making cross-origin requests, besides the CORS-safelisted ones.
+var exposedHeaders = []string{"Deprecation", "ETag", "Location", "Retry-After", "X-Request-ID"}
+
+// Policy describes which cross-origin requests are allowed.
+type Policy struct {
+	// AllowedOrigins are the origins that may make cross-origin requests,
+	// e.g. "https://example.com", or [AnyOrigin]. If empty, no cross-origin
+	// requests are allowed.
+	AllowedOrigins []string
+	// AllowedMethods are the HTTP methods that cross-origin requests may
+	// use, e.g. "PATCH". The CORS-safelisted methods GET, HEAD, and POST are
+	// always allowed.
+	AllowedMethods []string
+	// AllowedHeaders are the request headers that cross-origin requests may
+	// set, e.g. "If-Match". They are matched case-insensitively.
+	AllowedHeaders []string
+	// MaxAge is how long browsers may cache the result of a preflight
+	// request. Zero leaves it to the browser.
+	MaxAge time.Duration
+}
+
+// Validate checks that the allowed origins are either [AnyOrigin] or origins
+// like "https://example.com" or "http://localhost:3000".
+func (p *Policy) Validate() error {
+	for _, origin := range p.AllowedOrigins {
+		if origin == AnyOrigin {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+			u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+			return fmt.Errorf("invalid CORS origin '%s': want scheme://host[:port]", origin)
+		}
+	}
+	return nil
+}
+
+// Enabled checks if the policy allows any cross-origin requests.
+func (p *Policy) Enabled() bool {
+	return len(p.AllowedOrigins) > 0
+}
+
+func (p *Policy) allowsOrigin(origin string) bool {
+	return slices.Contains(p.AllowedOrigins, AnyOrigin) || slices.Contains(p.AllowedOrigins, origin)
+}
+
+func (p *Policy) allowsMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost:
+		return true
+	}
+	return slices.Contains(p.AllowedMethods, method)
+}
+
+func (p *Policy) allowsHeaders(headers []string) bool {
+	for _, h := range headers {
+		if !slices.ContainsFunc(p.AllowedHeaders, func(allowed string) bool {
+			return strings.EqualFold(allowed, h)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowOrigin returns the value of the Access-Control-Allow-Origin header for
+// the specified allowed origin.
+func (p *Policy) allowOrigin(origin string) string {
+	if slices.Contains(p.AllowedOrigins, AnyOrigin) {
+		return AnyOrigin
+	}
+	return origin
+}
+
+// Middleware returns a handler that adds the CORS headers to the responses to
+// requests from allowed origins, and answers their preflight requests itself.
+// Preflight requests from other origins, or for methods or headers that are
+// not allowed, are rejected with "403 Forbidden". Other requests from origins
+// that are not allowed are passed on without CORS headers, so browsers don't
+// expose the responses to the scripts making them.
+func (p *Policy) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Origin")
+		method := r.Header.Get("Access-Control-Request-Method")
+		if r.Method == http.MethodOptions && method != "" {
+			p.preflight(w, r, origin, method)
+			return
+		}
+		if p.allowsOrigin(origin) {
+			w.Header().Set("Access-Control-Allow-Origin", p.allowOrigin(origin))
+			w.Header().Set("Access-Control-Expose-Headers", strings.Join(exposedHeaders, ", "))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// preflight answers the specified preflight request.
+func (p *Policy) preflight(w http.ResponseWriter, r *http.Request, origin, method string) {
+	w.Header().Add("Vary", "Access-Control-Request-Method")
+	w.Header().Add("Vary", "Access-Control-Request-Headers")
+	var headers []string
+	for _, h := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			headers = append(headers, h)
+		}
+	}
+	switch {
+	case !p.allowsOrigin(origin):
+		rest.WriteError(w, r, http.StatusForbidden, "origin '%s' is not allowed", origin)
+		return
+	case !p.allowsMethod(method):
+		rest.WriteError(w, r, http.StatusForbidden, "method '%s' is not allowed for cross-origin requests", method)
+		return
+	case !p.allowsHeaders(headers):
+		rest.WriteError(w, r, http.StatusForbidden, "headers '%s' are not allowed for cross-origin requests",
+			strings.Join(headers, ", "))
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", p.allowOrigin(origin))
+	w.Header().Set("Access-Control-Allow-Methods", method)
+	if len(headers) > 0 {
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+	}
+	if p.MaxAge > 0 {
+		w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(p.MaxAge.Seconds())))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
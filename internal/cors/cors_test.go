@@ -0,0 +1,161 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	tests := []struct {
+		origin string
+		valid  bool
+	}{
+		{"*", true},
+		{"https://example.com", true},
+		{"http://localhost:3000", true},
+		{"example.com", false},
+		{"ftp://example.com", false},
+		{"https://example.com/", false},
+		{"https://example.com?q=1", false},
+	}
+	for _, tt := range tests {
+		p := &Policy{AllowedOrigins: []string{tt.origin}}
+		if err := p.Validate(); (err == nil) != tt.valid {
+			t.Errorf("Validate() with origin %q: want valid: %t; got error: %v", tt.origin, tt.valid, err)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	p := &Policy{
+		AllowedOrigins: []string{"https://example.com"},
+		AllowedMethods: []string{http.MethodPatch, http.MethodDelete},
+		AllowedHeaders: []string{"Content-Type", "If-Match"},
+		MaxAge:         10 * time.Minute,
+	}
+	h := p.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	tests := []struct {
+		name        string
+		method      string
+		headers     map[string]string
+		wantStatus  int
+		wantOrigin  string
+		wantMethods string
+		wantHeaders string
+	}{
+		{
+			name:       "SameOrigin",
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "AllowedOrigin",
+			method:     http.MethodGet,
+			headers:    map[string]string{"Origin": "https://example.com"},
+			wantStatus: http.StatusOK,
+			wantOrigin: "https://example.com",
+		},
+		{
+			name:       "OtherOrigin",
+			method:     http.MethodGet,
+			headers:    map[string]string{"Origin": "https://evil.example"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "PreflightPatch",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "https://example.com",
+				"Access-Control-Request-Method":  http.MethodPatch,
+				"Access-Control-Request-Headers": "content-type, if-match",
+			},
+			wantStatus:  http.StatusNoContent,
+			wantOrigin:  "https://example.com",
+			wantMethods: http.MethodPatch,
+			wantHeaders: "content-type, if-match",
+		},
+		{
+			name:   "PreflightDelete",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://example.com",
+				"Access-Control-Request-Method": http.MethodDelete,
+			},
+			wantStatus:  http.StatusNoContent,
+			wantOrigin:  "https://example.com",
+			wantMethods: http.MethodDelete,
+		},
+		{
+			name:   "PreflightOtherOrigin",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://evil.example",
+				"Access-Control-Request-Method": http.MethodDelete,
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "PreflightDisallowedMethod",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://example.com",
+				"Access-Control-Request-Method": http.MethodPut,
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "PreflightDisallowedHeader",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "https://example.com",
+				"Access-Control-Request-Method":  http.MethodPatch,
+				"Access-Control-Request-Headers": "Authorization",
+			},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/api/v1/tasks/1", nil)
+			for name, value := range tt.headers {
+				r.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("want status: %d; got: %d", tt.wantStatus, rec.Code)
+			}
+			want := map[string]string{
+				"Access-Control-Allow-Origin":  tt.wantOrigin,
+				"Access-Control-Allow-Methods": tt.wantMethods,
+				"Access-Control-Allow-Headers": tt.wantHeaders,
+			}
+			for name, value := range want {
+				if got := rec.Header().Get(name); got != value {
+					t.Errorf("want %s: %q; got: %q", name, value, got)
+				}
+			}
+			if tt.wantStatus == http.StatusNoContent && rec.Header().Get("Access-Control-Max-Age") != "600" {
+				t.Errorf("want Access-Control-Max-Age: 600; got: %q", rec.Header().Get("Access-Control-Max-Age"))
+			}
+		})
+	}
+}
+
+func TestMiddlewareAnyOrigin(t *testing.T) {
+	p := &Policy{AllowedOrigins: []string{AnyOrigin}}
+	h := p.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil)
+	r.Header.Set("Origin", "https://example.com")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != AnyOrigin {
+		t.Errorf("want Access-Control-Allow-Origin: %q; got: %q", AnyOrigin, got)
+	}
+}
@@ -0,0 +1,508 @@
+// Package duedate parses the due times entered on the command line, from
+// timestamps like "2006-01-02 15:04" to natural-language expressions like
+// "next friday 5pm", "in 3 days", or "every monday".
+package duedate
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// defaultLocale is the locale whose vocabulary is used if no other locale is
+// configured, and as a fallback for all other locales.
+const defaultLocale = "en"
+
+// layouts are the layouts of the absolute due times accepted in all locales,
+// in addition to date-only values.
+var layouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+// Result is a parsed due time.
+type Result struct {
+	// Time is the due time. For recurring tasks, it is the first occurrence.
+	Time time.Time
+	// Recurrence is the rule that the task repeats by, or nil if the due time
+	// doesn't recur.
+	Recurrence *todo.Recurrence
+}
+
+// Parser parses due times.
+type Parser struct {
+	// Locale is the language of natural-language due times, e.g. "de". English
+	// due times are accepted in all locales.
+	Locale string
+	// Location is the time zone that due times without an offset are in.
+	Location *time.Location
+	// Now returns the current time, which relative due times like "tomorrow"
+	// refer to. If nil, [time.Now] is used.
+	Now func() time.Time
+}
+
+// NewParser creates a parser for the specified locale and time zone. If the
+// locale is empty, it is derived from the LC_ALL, LC_TIME, and LANG environment
+// variables, falling back to English. If the time zone is empty, the system's
+// local time zone is used; otherwise it is an IANA time zone name like
+// "Europe/Berlin".
+func NewParser(locale, timeZone string) (*Parser, error) {
+	p := &Parser{Locale: defaultLocale, Location: time.Local}
+	if locale != "" {
+		l, err := ParseLocale(locale)
+		if err != nil {
+			return nil, err
+		}
+		p.Locale = l
+	} else if l, err := ParseLocale(envLocale()); err == nil {
+		p.Locale = l
+	}
+	if timeZone != "" {
+		loc, err := time.LoadLocation(timeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone '%s': %w", timeZone, err)
+		}
+		p.Location = loc
+	}
+	return p, nil
+}
+
+// Locales returns the supported locales in ascending order.
+func Locales() []string {
+	return slices.Sorted(maps.Keys(vocabularies))
+}
+
+// ParseLocale returns the supported locale that the specified POSIX locale
+// name or language tag refers to, e.g. "de" for "de_DE.UTF-8" or "de-AT". The
+// "C" and "POSIX" locales refer to English.
+func ParseLocale(name string) (string, error) {
+	lang, _, _ := strings.Cut(strings.ToLower(name), ".")
+	lang, _, _ = strings.Cut(lang, "_")
+	lang, _, _ = strings.Cut(lang, "-")
+	switch lang {
+	case "c", "posix":
+		return defaultLocale, nil
+	}
+	if _, ok := vocabularies[lang]; !ok {
+		return "", fmt.Errorf("unsupported locale '%s' (supported: %s)", name, strings.Join(Locales(), ", "))
+	}
+	return lang, nil
+}
+
+// envLocale returns the locale that the environment specifies for formatting
+// dates and times.
+func envLocale() string {
+	for _, name := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Parse parses the specified due time. It accepts:
+//
+//   - RFC 3339 timestamps, local date-times like "2006-01-02 15:04", and local
+//     dates like "2006-01-02", which refer to the end of the day;
+//   - days like "today", "tomorrow", "friday", "next friday", or "next week";
+//   - relative times like "in 3 days" or "in 2 hours";
+//   - recurrences like "every monday", "every 2 weeks", or "daily";
+//   - any of the above, except for relative times, followed by a time of day
+//     like "5pm", "5:30 pm", "17:00", "at 9", or "noon".
+//
+// A weekday refers to the next such day, or today. With "next", it refers to
+// the next such day after today. A time of day without a day refers to today,
+// or tomorrow if the time has already passed. The first occurrence of a
+// recurrence is the next one that hasn't passed yet.
+func (p *Parser) Parse(s string) (*Result, error) {
+	s = strings.TrimSpace(s)
+	loc := p.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return &Result{Time: t}, nil
+		}
+	}
+	now := time.Now()
+	if p.Now != nil {
+		now = p.Now()
+	}
+	tokens := strings.Fields(strings.ToLower(strings.ReplaceAll(s, ",", " ")))
+	if len(tokens) == 0 {
+		return nil, errors.New("invalid due time: empty")
+	}
+	locales := []string{defaultLocale}
+	if p.Locale != "" && p.Locale != defaultLocale {
+		locales = []string{p.Locale, defaultLocale}
+	}
+	var err error
+	for _, locale := range locales {
+		vocab, ok := vocabularies[locale]
+		if !ok {
+			return nil, fmt.Errorf("unsupported locale '%s'", locale)
+		}
+		ps := &parser{vocab: vocab, tokens: tokens, now: now.In(loc)}
+		var r *Result
+		if r, err = ps.parse(); err == nil {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid due time '%s': %w", s, err)
+}
+
+// parser parses a natural-language due time in a single locale.
+type parser struct {
+	vocab  *vocabulary
+	tokens []string
+	pos    int
+	now    time.Time
+
+	// date is the midnight of the day that the due time falls on, if set.
+	date time.Time
+	// hasClock specifies whether a time of day has been parsed.
+	hasClock     bool
+	hour, minute int
+	// exact is the due time specified relative to the current time, if set.
+	exact      time.Time
+	recurrence *todo.Recurrence
+}
+
+// parse parses the parser's tokens.
+func (p *parser) parse() (*Result, error) {
+	for p.pos < len(p.tokens) {
+		if err := p.parseTerm(); err != nil {
+			return nil, err
+		}
+	}
+	return p.result()
+}
+
+// parseTerm parses the term at the current position, e.g. a day, a relative
+// time, a recurrence, or a time of day.
+func (p *parser) parseTerm() error {
+	today := time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, p.now.Location())
+	switch {
+	case p.match(p.vocab.dayAfterTomorrow):
+		return p.setDate(today.AddDate(0, 0, 2))
+	case p.match(p.vocab.today):
+		return p.setDate(today)
+	case p.match(p.vocab.tomorrow):
+		return p.setDate(today.AddDate(0, 0, 1))
+	case p.match(p.vocab.next):
+		if wd, ok := p.weekday(); ok {
+			return p.setDate(nextWeekday(today.AddDate(0, 0, 1), wd))
+		}
+		u, ok := p.unit()
+		if !ok || u < unitDay {
+			return errors.New("expected a weekday or a unit of days after 'next'")
+		}
+		return p.setDate(add(today, u, 1))
+	case p.match(p.vocab.this):
+		if d, ok := p.dateToken(); ok {
+			return p.setDate(d)
+		}
+		wd, ok := p.weekday()
+		if !ok {
+			return errors.New("expected a weekday or a date")
+		}
+		return p.setDate(nextWeekday(today, wd))
+	case p.match(p.vocab.in):
+		n, ok := p.count()
+		if !ok {
+			return errors.New("expected a number after 'in'")
+		}
+		u, ok := p.unit()
+		if !ok {
+			return errors.New("expected a unit of time")
+		}
+		if u >= unitDay {
+			return p.setDate(add(today, u, n))
+		}
+		if p.hasDate() {
+			return errors.New("conflicting days")
+		}
+		p.exact = add(p.now, u, n)
+		return nil
+	case p.match(p.vocab.every):
+		return p.parseEvery()
+	case p.match(p.vocab.at):
+		if !p.clock(true) {
+			return errors.New("expected a time of day")
+		}
+		return nil
+	case p.match(p.vocab.noon):
+		return p.setClock(12, 0)
+	}
+	if wd, ok := p.weekday(); ok {
+		return p.setDate(nextWeekday(today, wd))
+	}
+	if u, ok := p.vocab.frequencies[p.tokens[p.pos]]; ok {
+		p.pos++
+		return p.setRecurrence(u, 1, nil)
+	}
+	if d, ok := p.dateToken(); ok {
+		return p.setDate(d)
+	}
+	if p.clock(false) {
+		return nil
+	}
+	return fmt.Errorf("unexpected '%s'", p.tokens[p.pos])
+}
+
+// parseEvery parses the rest of a recurrence like "every monday" or "every 2
+// weeks".
+func (p *parser) parseEvery() error {
+	if wd, ok := p.weekday(); ok {
+		return p.setRecurrence(unitWeek, 1, &wd)
+	}
+	n, ok := p.count()
+	if !ok {
+		n = 1
+	}
+	u, ok := p.unit()
+	if !ok {
+		return errors.New("expected a weekday or a unit of time")
+	}
+	return p.setRecurrence(u, n, nil)
+}
+
+// result returns the due time described by the parsed terms.
+func (p *parser) result() (*Result, error) {
+	if !p.exact.IsZero() {
+		if p.hasClock || p.recurrence != nil {
+			return nil, errors.New("cannot combine a relative time with a time of day")
+		}
+		return &Result{Time: p.exact}, nil
+	}
+	if !p.hasDate() && !p.hasClock && p.recurrence == nil {
+		return nil, errors.New("no day or time of day")
+	}
+	loc := p.now.Location()
+	date := p.date
+	if date.IsZero() {
+		date = time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, loc)
+	}
+	if r := p.recurrence; r != nil && r.HasWeekday {
+		date = nextWeekday(date, r.Weekday)
+	}
+	t := time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 0, loc)
+	if p.hasClock {
+		t = time.Date(date.Year(), date.Month(), date.Day(), p.hour, p.minute, 0, 0, loc)
+	}
+	switch {
+	case p.recurrence != nil:
+		for !t.After(p.now) {
+			t = p.recurrence.Next(t)
+		}
+	case !p.hasDate() && !t.After(p.now):
+		t = t.AddDate(0, 0, 1)
+	}
+	return &Result{Time: t, Recurrence: p.recurrence}, nil
+}
+
+// hasDate reports whether a day has been parsed.
+func (p *parser) hasDate() bool {
+	return !p.date.IsZero() || !p.exact.IsZero()
+}
+
+// setDate sets the day of the due time.
+func (p *parser) setDate(d time.Time) error {
+	if p.hasDate() || p.recurrence != nil && p.recurrence.HasWeekday {
+		return errors.New("conflicting days")
+	}
+	p.date = d
+	return nil
+}
+
+// setClock sets the time of day of the due time.
+func (p *parser) setClock(hour, minute int) error {
+	if p.hasClock {
+		return errors.New("conflicting times of day")
+	}
+	p.hasClock, p.hour, p.minute = true, hour, minute
+	return nil
+}
+
+// setRecurrence sets the recurrence of the due time.
+func (p *parser) setRecurrence(u unit, interval int, wd *time.Weekday) error {
+	if p.recurrence != nil {
+		return errors.New("conflicting recurrences")
+	}
+	r := &todo.Recurrence{Interval: interval}
+	switch u {
+	case unitDay:
+		r.Freq = todo.Daily
+	case unitWeek:
+		r.Freq = todo.Weekly
+	case unitMonth:
+		r.Freq = todo.Monthly
+	case unitYear:
+		r.Freq = todo.Yearly
+	default:
+		return errors.New("recurrences must be at least daily")
+	}
+	if wd != nil {
+		if !p.date.IsZero() {
+			return errors.New("conflicting days")
+		}
+		r.Weekday, r.HasWeekday = *wd, true
+	}
+	p.recurrence = r
+	return nil
+}
+
+// match consumes the first of the specified phrases found at the current
+// position, and reports whether there was one.
+func (p *parser) match(phrases []string) bool {
+	for _, phrase := range phrases {
+		words := strings.Fields(phrase)
+		if end := p.pos + len(words); end <= len(p.tokens) && slices.Equal(p.tokens[p.pos:end], words) {
+			p.pos = end
+			return true
+		}
+	}
+	return false
+}
+
+// peek returns the token at the current position, or "" at the end.
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// weekday consumes a weekday.
+func (p *parser) weekday() (time.Weekday, bool) {
+	wd, ok := p.vocab.weekdays[p.peek()]
+	if ok {
+		p.pos++
+	}
+	return wd, ok
+}
+
+// unit consumes a unit of time.
+func (p *parser) unit() (unit, bool) {
+	u, ok := p.vocab.units[p.peek()]
+	if ok {
+		p.pos++
+	}
+	return u, ok
+}
+
+// count consumes a positive number, written in digits or as an article.
+func (p *parser) count() (int, bool) {
+	if p.match(p.vocab.one) {
+		return 1, true
+	}
+	n, err := strconv.Atoi(p.peek())
+	if err != nil || n < 1 {
+		return 0, false
+	}
+	p.pos++
+	return n, true
+}
+
+// dateToken consumes a date like "2006-01-02".
+func (p *parser) dateToken() (time.Time, bool) {
+	for _, layout := range append([]string{time.DateOnly}, p.vocab.dateLayouts...) {
+		if d, err := time.ParseInLocation(layout, p.peek(), p.now.Location()); err == nil {
+			p.pos++
+			return d, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// clock consumes a time of day like "5pm", "5:30 pm", "17:00", or "17 uhr",
+// and reports whether there was one. A bare hour like "5" is only accepted if
+// explicit is set, e.g. after "at".
+func (p *parser) clock(explicit bool) bool {
+	tok := p.peek()
+	suffix := ""
+	if p.vocab.twelveHour {
+		for _, s := range []string{"am", "pm"} {
+			if h, ok := strings.CutSuffix(tok, s); ok && h != "" {
+				tok, suffix = h, s
+			}
+		}
+	}
+	h, m, hasMinute := strings.Cut(tok, ":")
+	if !hasMinute {
+		h, m, hasMinute = strings.Cut(tok, ".")
+	}
+	hour, err := strconv.Atoi(h)
+	if err != nil || len(h) > 2 {
+		return false
+	}
+	minute := 0
+	if hasMinute {
+		if minute, err = strconv.Atoi(m); err != nil || len(m) != 2 {
+			return false
+		}
+	}
+	end := p.pos + 1
+	if suffix == "" && p.vocab.twelveHour && end < len(p.tokens) {
+		if s := p.tokens[end]; s == "am" || s == "pm" {
+			suffix = s
+			end++
+		}
+	}
+	if suffix == "" && end < len(p.tokens) && slices.Contains(p.vocab.oclock, p.tokens[end]) {
+		explicit = true
+		end++
+	}
+	if suffix == "" && !hasMinute && !explicit {
+		return false
+	}
+	switch {
+	case suffix != "" && (hour < 1 || hour > 12):
+		return false
+	case suffix == "am" && hour == 12:
+		hour = 0
+	case suffix == "pm" && hour < 12:
+		hour += 12
+	}
+	if hour > 23 || minute > 59 {
+		return false
+	}
+	if err := p.setClock(hour, minute); err != nil {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+// nextWeekday returns the first day on or after the specified day that falls
+// on the specified weekday.
+func nextWeekday(day time.Time, wd time.Weekday) time.Time {
+	return day.AddDate(0, 0, (int(wd)-int(day.Weekday())+7)%7)
+}
+
+// add adds n units of time to t.
+func add(t time.Time, u unit, n int) time.Time {
+	switch u {
+	case unitMinute:
+		return t.Add(time.Duration(n) * time.Minute)
+	case unitHour:
+		return t.Add(time.Duration(n) * time.Hour)
+	case unitDay:
+		return t.AddDate(0, 0, n)
+	case unitWeek:
+		return t.AddDate(0, 0, 7*n)
+	case unitMonth:
+		return t.AddDate(0, n, 0)
+	default:
+		return t.AddDate(n, 0, 0)
+	}
+}
@@ -0,0 +1,135 @@
+package duedate
+
+import (
+	"testing"
+	"time"
+)
+
+// now is Wednesday, 2026-10-14, 10:00 in a fixed time zone.
+var (
+	zone = time.FixedZone("CEST", 2*60*60)
+	now  = time.Date(2026, 10, 14, 10, 0, 0, 0, zone)
+)
+
+func date(month time.Month, day, hour, minute, sec int) time.Time {
+	return time.Date(2026, month, day, hour, minute, sec, 0, zone)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		locale     string
+		in         string
+		want       time.Time
+		recurrence string
+	}{
+		{"en", "2026-10-20", date(10, 20, 23, 59, 59), ""},
+		{"en", "2026-10-20 17:30", date(10, 20, 17, 30, 0), ""},
+		{"en", "2026-10-20T17:30:00Z", time.Date(2026, 10, 20, 17, 30, 0, 0, time.UTC), ""},
+		{"en", "2026-10-20 5pm", date(10, 20, 17, 0, 0), ""},
+		{"en", "today", date(10, 14, 23, 59, 59), ""},
+		{"en", "Tomorrow at 9", date(10, 15, 9, 0, 0), ""},
+		{"en", "the day after tomorrow, noon", date(10, 16, 12, 0, 0), ""},
+		{"en", "friday", date(10, 16, 23, 59, 59), ""},
+		{"en", "wednesday", date(10, 14, 23, 59, 59), ""},
+		{"en", "next wednesday", date(10, 21, 23, 59, 59), ""},
+		{"en", "next friday 5pm", date(10, 16, 17, 0, 0), ""},
+		{"en", "on fri at 5:30 pm", date(10, 16, 17, 30, 0), ""},
+		{"en", "next week", date(10, 21, 23, 59, 59), ""},
+		{"en", "next month", date(11, 14, 23, 59, 59), ""},
+		{"en", "in 3 days", date(10, 17, 23, 59, 59), ""},
+		{"en", "in a week 12am", date(10, 21, 0, 0, 0), ""},
+		{"en", "in 2 hours", date(10, 14, 12, 0, 0), ""},
+		{"en", "9am", date(10, 15, 9, 0, 0), ""},
+		{"en", "17:00", date(10, 14, 17, 0, 0), ""},
+		{"en", "every monday", date(10, 19, 23, 59, 59), "FREQ=WEEKLY;BYDAY=MO"},
+		{"en", "every wednesday 9am", date(10, 21, 9, 0, 0), "FREQ=WEEKLY;BYDAY=WE"},
+		{"en", "every 2 weeks", date(10, 14, 23, 59, 59), "FREQ=WEEKLY;INTERVAL=2"},
+		{"en", "daily at 8", date(10, 15, 8, 0, 0), "FREQ=DAILY"},
+		{"en", "tomorrow monthly", date(10, 15, 23, 59, 59), "FREQ=MONTHLY"},
+		{"de", "morgen um 17 Uhr", date(10, 15, 17, 0, 0), ""},
+		{"de", "übermorgen", date(10, 16, 23, 59, 59), ""},
+		{"de", "nächsten Freitag 17:30", date(10, 16, 17, 30, 0), ""},
+		{"de", "am 20.10.2026", date(10, 20, 23, 59, 59), ""},
+		{"de", "in einer Woche", date(10, 21, 23, 59, 59), ""},
+		{"de", "jeden Montag", date(10, 19, 23, 59, 59), "FREQ=WEEKLY;BYDAY=MO"},
+		{"de", "alle 3 Tage", date(10, 14, 23, 59, 59), "FREQ=DAILY;INTERVAL=3"},
+		{"de", "next friday 5pm", date(10, 16, 17, 0, 0), ""},
+	}
+	for _, tt := range tests {
+		p := &Parser{Locale: tt.locale, Location: zone, Now: func() time.Time { return now }}
+		got, err := p.Parse(tt.in)
+		if err != nil {
+			t.Errorf("%s: %q: %v", tt.locale, tt.in, err)
+			continue
+		}
+		if !got.Time.Equal(tt.want) {
+			t.Errorf("%s: %q: want %v; got: %v", tt.locale, tt.in, tt.want, got.Time)
+		}
+		recurrence := ""
+		if got.Recurrence != nil {
+			recurrence = got.Recurrence.String()
+		}
+		if recurrence != tt.recurrence {
+			t.Errorf("%s: %q: want recurrence %q; got: %q", tt.locale, tt.in, tt.recurrence, recurrence)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	p := &Parser{Locale: "en", Location: zone, Now: func() time.Time { return now }}
+	for _, in := range []string{
+		"",
+		"soon",
+		"next",
+		"in days",
+		"at",
+		"5",
+		"13pm",
+		"25:00",
+		"today tomorrow",
+		"in 2 hours 5pm",
+		"friday every monday",
+		"every 2 hours",
+		"morgen",
+	} {
+		if _, err := p.Parse(in); err == nil {
+			t.Errorf("%q: want error", in)
+		}
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	tests := map[string]string{
+		"en":          "en",
+		"de_DE.UTF-8": "de",
+		"de-AT":       "de",
+		"C":           "en",
+		"POSIX":       "en",
+	}
+	for in, want := range tests {
+		if got, err := ParseLocale(in); err != nil || got != want {
+			t.Errorf("%q: want %q; got: %q, %v", in, want, got, err)
+		}
+	}
+	if _, err := ParseLocale("fr_FR"); err == nil {
+		t.Error("want error for unsupported locale")
+	}
+}
+
+func TestNewParser(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_TIME", "de_DE.UTF-8")
+	p, err := NewParser("", "UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Locale != "de" || p.Location != time.UTC {
+		t.Errorf("want locale from environment and UTC; got: %q, %v", p.Locale, p.Location)
+	}
+	if _, err := NewParser("", "Nowhere/Special"); err == nil {
+		t.Error("want error for unknown time zone")
+	}
+	if _, err := NewParser("xx", ""); err == nil {
+		t.Error("want error for unsupported locale")
+	}
+}
@@ -0,0 +1,122 @@
+package duedate
+
+import "time"
+
+// unit is a unit of time in relative due times like "in 3 days".
+type unit int
+
+const (
+	unitMinute unit = iota
+	unitHour
+	unitDay
+	unitWeek
+	unitMonth
+	unitYear
+)
+
+// vocabulary holds the words of a language that due times are written in.
+// Phrases consisting of several words are written with spaces.
+type vocabulary struct {
+	today            []string
+	tomorrow         []string
+	dayAfterTomorrow []string
+	// next and this precede weekdays and units, like "next friday".
+	next []string
+	this []string
+	// in precedes relative times, like "in 3 days".
+	in []string
+	// one replaces the number 1 in relative times, like "in a week".
+	one []string
+	// every precedes recurrences, like "every monday".
+	every []string
+	// at precedes times of day, like "at 5pm".
+	at []string
+	// oclock follows hours, like "17 uhr" in German.
+	oclock []string
+	noon   []string
+	// twelveHour specifies whether times of day may have "am" and "pm".
+	twelveHour bool
+	weekdays   map[string]time.Weekday
+	units      map[string]unit
+	// frequencies maps adverbs like "weekly" to the unit of their interval.
+	frequencies map[string]unit
+	// dateLayouts are the layouts of dates in addition to "2006-01-02".
+	dateLayouts []string
+}
+
+// vocabularies maps the supported locales to their vocabularies.
+var vocabularies = map[string]*vocabulary{
+	"en": {
+		today:            []string{"today", "tonight"},
+		tomorrow:         []string{"tomorrow"},
+		dayAfterTomorrow: []string{"day after tomorrow", "the day after tomorrow"},
+		next:             []string{"next"},
+		this:             []string{"this", "on"},
+		in:               []string{"in"},
+		one:              []string{"a", "an", "one"},
+		every:            []string{"every"},
+		at:               []string{"at"},
+		noon:             []string{"noon", "midday"},
+		twelveHour:       true,
+		weekdays: map[string]time.Weekday{
+			"sunday": time.Sunday, "sun": time.Sunday,
+			"monday": time.Monday, "mon": time.Monday,
+			"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+			"wednesday": time.Wednesday, "wed": time.Wednesday,
+			"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+			"friday": time.Friday, "fri": time.Friday,
+			"saturday": time.Saturday, "sat": time.Saturday,
+		},
+		units: map[string]unit{
+			"minute": unitMinute, "minutes": unitMinute, "min": unitMinute, "mins": unitMinute,
+			"hour": unitHour, "hours": unitHour,
+			"day": unitDay, "days": unitDay,
+			"week": unitWeek, "weeks": unitWeek,
+			"month": unitMonth, "months": unitMonth,
+			"year": unitYear, "years": unitYear,
+		},
+		frequencies: map[string]unit{
+			"daily":   unitDay,
+			"weekly":  unitWeek,
+			"monthly": unitMonth,
+			"yearly":  unitYear,
+		},
+	},
+	"de": {
+		today:            []string{"heute"},
+		tomorrow:         []string{"morgen"},
+		dayAfterTomorrow: []string{"übermorgen"},
+		next:             []string{"nächsten", "nächste", "nächster", "kommenden", "kommende", "kommender"},
+		this:             []string{"diesen", "diese", "dieser", "am"},
+		in:               []string{"in"},
+		one:              []string{"einer", "einem", "einen", "ein", "eine"},
+		every:            []string{"jeden", "jede", "jedes", "alle"},
+		at:               []string{"um"},
+		oclock:           []string{"uhr"},
+		noon:             []string{"mittag", "mittags"},
+		weekdays: map[string]time.Weekday{
+			"sonntag": time.Sunday, "so": time.Sunday,
+			"montag": time.Monday, "mo": time.Monday,
+			"dienstag": time.Tuesday, "di": time.Tuesday,
+			"mittwoch": time.Wednesday, "mi": time.Wednesday,
+			"donnerstag": time.Thursday, "do": time.Thursday,
+			"freitag": time.Friday, "fr": time.Friday,
+			"samstag": time.Saturday, "sonnabend": time.Saturday, "sa": time.Saturday,
+		},
+		units: map[string]unit{
+			"minute": unitMinute, "minuten": unitMinute,
+			"stunde": unitHour, "stunden": unitHour,
+			"tag": unitDay, "tage": unitDay, "tagen": unitDay,
+			"woche": unitWeek, "wochen": unitWeek,
+			"monat": unitMonth, "monate": unitMonth, "monaten": unitMonth,
+			"jahr": unitYear, "jahre": unitYear, "jahren": unitYear,
+		},
+		frequencies: map[string]unit{
+			"täglich":     unitDay,
+			"wöchentlich": unitWeek,
+			"monatlich":   unitMonth,
+			"jährlich":    unitYear,
+		},
+		dateLayouts: []string{"2.1.2006"},
+	},
+}
@@ -0,0 +1,169 @@
+//go:build !windows
+
+package e2etest
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+)
+
+// bin is the path of the todo-daemon executable built by TestMain.
+var bin string
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	if testing.Short() {
+		return m.Run()
+	}
+	dir, err := os.MkdirTemp("", "todo-daemon-e2e")
+	if err != nil {
+		// revive:disable-next-line:unhandled-error
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+	if bin, err = Build(dir); err != nil {
+		// revive:disable-next-line:unhandled-error
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return m.Run()
+}
+
+// newDaemon prepares a server for the test, which is skipped in short mode.
+func newDaemon(t *testing.T) *Daemon {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping end-to-end test in short mode")
+	}
+	return New(t, bin)
+}
+
+// mustRun runs the CLI with the specified arguments and fails the test unless
+// it succeeds.
+func mustRun(t *testing.T, d *Daemon, args ...string) string {
+	t.Helper()
+	res := d.Run(t, args...)
+	if res.ExitCode != exitcode.OK {
+		t.Fatalf("%s: want exit code %d; got: %d\n%s", strings.Join(args, " "), exitcode.OK, res.ExitCode, res.Stderr)
+	}
+	return res.Stdout
+}
+
+func TestTasks(t *testing.T) {
+	d := newDaemon(t)
+	d.Start(t)
+
+	if out := mustRun(t, d, "tasks", "list", "--porcelain"); out != "" {
+		t.Errorf("want empty to-do list; got: %q", out)
+	}
+	if out := mustRun(t, d, "tasks", "add", "Buy milk"); !strings.Contains(out, "Buy milk") {
+		t.Errorf("want added task to be printed; got: %q", out)
+	}
+	mustRun(t, d, "tasks", "add", "Call the plumber")
+
+	lines := strings.Split(strings.TrimSpace(mustRun(t, d, "tasks", "list", "--porcelain")), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want 2 tasks; got: %q", lines)
+	}
+	fields := strings.Split(lines[0], "\t")
+	if len(fields) < 9 || fields[0] != "1" || fields[2] != "open" || fields[8] != "Buy milk" {
+		t.Errorf("want open task 1 'Buy milk'; got: %q", fields)
+	}
+
+	mustRun(t, d, "tasks", "done", "1")
+	out := mustRun(t, d, "tasks", "list", "--porcelain", "--status", "completed")
+	if fields := strings.Split(strings.TrimSpace(out), "\t"); len(fields) < 3 || fields[0] != "1" ||
+		fields[2] != "completed" {
+		t.Errorf("want task 1 to be completed; got: %q", out)
+	}
+
+	mustRun(t, d, "tasks", "remove", "--force", "1")
+	out = mustRun(t, d, "tasks", "list", "--porcelain")
+	if strings.Contains(out, "Buy milk") || !strings.Contains(out, "Call the plumber") {
+		t.Errorf("want only task 2 to be left; got: %q", out)
+	}
+
+	if res := d.Run(t, "tasks", "done", "42"); res.ExitCode != exitcode.NotFound {
+		t.Errorf("want exit code %d for missing task; got: %d\n%s", exitcode.NotFound, res.ExitCode, res.Stderr)
+	}
+	if res := d.Run(t, "tasks", "list", "--no-such-flag"); res.ExitCode != exitcode.Usage {
+		t.Errorf("want exit code %d for invalid flag; got: %d\n%s", exitcode.Usage, res.ExitCode, res.Stderr)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	d := newDaemon(t)
+	d.Start(t)
+	mustRun(t, d, "tasks", "add", "Buy milk")
+
+	var status struct {
+		APIBaseURL string `json:"api_base_url"`
+		Storage    string `json:"storage"`
+		Tasks      int    `json:"tasks"`
+	}
+	if err := json.Unmarshal([]byte(mustRun(t, d, "status", "--json")), &status); err != nil {
+		t.Fatalf("cannot decode status: %v", err)
+	}
+	if status.APIBaseURL != d.APIBaseURL || status.Storage != "eventlog" || status.Tasks != 1 {
+		t.Errorf("want eventlog storage with 1 task at %s; got: %+v", d.APIBaseURL, status)
+	}
+}
+
+func TestREST(t *testing.T) {
+	d := newDaemon(t)
+	d.Start(t)
+	mustRun(t, d, "tasks", "add", "Buy milk")
+
+	resp, err := http.Get(d.APIBaseURL + "/v1/tasks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("want status %d; got: %d", http.StatusOK, resp.StatusCode)
+	}
+	var list struct {
+		Tasks []struct {
+			ID      string `json:"id"`
+			Summary string `json:"summary"`
+		} `json:"tasks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatalf("cannot decode tasks: %v", err)
+	}
+	if len(list.Tasks) != 1 || list.Tasks[0].Summary != "Buy milk" {
+		t.Errorf("want task added via the CLI; got: %+v", list.Tasks)
+	}
+
+	resp, err = http.Get(d.APIBaseURL + "/v1/tasks/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound || resp.Header.Get("Content-Type") != "application/problem+json" {
+		t.Errorf("want not-found problem; got: %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+}
+
+func TestNotRunning(t *testing.T) {
+	d := newDaemon(t)
+	res := d.Run(t, "tasks", "list")
+	if res.ExitCode != exitcode.NotRunning {
+		t.Errorf("want exit code %d; got: %d\n%s", exitcode.NotRunning, res.ExitCode, res.Stderr)
+	}
+	if !strings.Contains(res.Stderr, "todo-daemon run") {
+		t.Errorf("want hint to start the server; got: %q", res.Stderr)
+	}
+}
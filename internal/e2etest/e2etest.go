@@ -0,0 +1,193 @@
+//go:build !windows
+
+// Package e2etest provides a harness for testing the To-do Daemon end-to-end.
+//
+// Unlike package clitest, which connects the commands to an in-process server
+// through an in-memory pipe, it builds the todo-daemon executable, starts it
+// as a server on a socket and data directory of its own, and runs CLI
+// commands against it in separate processes:
+//
+//	d := e2etest.New(t, bin)
+//	d.Start(t)
+//	res := d.Run(t, "tasks", "add", "Buy milk")
+package e2etest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+)
+
+// startTimeout is the maximum amount of time to wait for a started server to
+// become ready.
+const startTimeout = 10 * time.Second
+
+// stopTimeout is the maximum amount of time to wait for a server to stop
+// after it has been interrupted, before it is killed.
+const stopTimeout = 5 * time.Second
+
+// Build builds the todo-daemon executable into the specified directory and
+// returns its path. It uses the go command found in the PATH, which
+// 'go test' puts there.
+func Build(dir string) (string, error) {
+	bin := filepath.Join(dir, "todo-daemon")
+	cmd := exec.Command("go", "build", "-o", bin, "github.com/mwopitz/todo-daemon")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("cannot build todo-daemon: %w\n%s", err, out)
+	}
+	return bin, nil
+}
+
+// Daemon is a To-do Daemon server running in a separate process, with its
+// socket, lock file, configuration, and data in a temporary directory.
+type Daemon struct {
+	// Bin is the path of the todo-daemon executable.
+	Bin string
+	// Dir is the directory holding the server's files.
+	Dir string
+	// Sock is the path of the server's socket.
+	Sock string
+	// APIBaseURL is the base URL of the server's REST API, which is set by
+	// Start.
+	APIBaseURL string
+
+	env  []string
+	cmd  *exec.Cmd
+	logs bytes.Buffer
+}
+
+// Result is the result of a CLI command run with [Daemon.Run].
+type Result struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// New prepares a To-do Daemon server that runs the specified executable, but
+// doesn't start it yet, so that tests can also run commands without a server.
+func New(t testing.TB, bin string) *Daemon {
+	t.Helper()
+	dir := t.TempDir()
+	d := &Daemon{Bin: bin, Dir: dir, Sock: filepath.Join(dir, "todo-daemon.sock")}
+	d.env = append(isolatedEnv(),
+		config.EnvSockFile+"="+d.Sock,
+		config.EnvLockFile+"="+filepath.Join(dir, "todo-daemon.lock"),
+		config.EnvConfigFile+"="+filepath.Join(dir, "config.json"),
+		config.EnvDatabase+"=eventlog:"+filepath.Join(dir, "tasks"),
+		config.EnvHTTPListen+"=127.0.0.1:0",
+		"HOME="+dir,
+		"XDG_CONFIG_HOME="+filepath.Join(dir, "config"),
+		"LC_ALL=C",
+		"NO_COLOR=1",
+	)
+	return d
+}
+
+// isolatedEnv returns the environment of the current process without the
+// variables that would make the server or the CLI use the user's files or
+// language.
+func isolatedEnv() []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		switch {
+		case strings.HasPrefix(name, "TODO_DAEMON_"), strings.HasPrefix(name, "LC_"),
+			strings.HasPrefix(name, "XDG_"), name == "LANG", name == "HOME":
+			continue
+		}
+		env = append(env, kv)
+	}
+	return env
+}
+
+// Start starts the server with the specified additional arguments of the
+// 'run' command and waits until it is ready. The server is stopped when the
+// test ends; if the test failed, the server's log is added to the test log.
+func (d *Daemon) Start(t testing.TB, args ...string) {
+	t.Helper()
+	d.cmd = exec.Command(d.Bin, append([]string{"run"}, args...)...)
+	d.cmd.Env = d.env
+	d.cmd.Stdout = &d.logs
+	d.cmd.Stderr = &d.logs
+	if err := d.cmd.Start(); err != nil {
+		t.Fatalf("cannot start server: %v", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		// The error only reports the exit status, which Stop doesn't care
+		// about.
+		_ = d.cmd.Wait()
+		close(exited)
+	}()
+	t.Cleanup(func() {
+		d.stop(t, exited)
+		if t.Failed() {
+			t.Logf("server log:\n%s", d.logs.String())
+		}
+	})
+
+	c, err := client.New(d.Sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(t.Context(), startTimeout)
+	defer cancel()
+	if err := c.WaitReady(ctx); err != nil {
+		t.Fatalf("cannot start server: %v", err)
+	}
+	status, err := c.ServerStatus(ctx)
+	if err != nil {
+		t.Fatalf("cannot get server status: %v", err)
+	}
+	d.APIBaseURL = status.GetApiBaseUrl()
+}
+
+// stop interrupts the server and kills it if it hasn't exited within the stop
+// timeout.
+func (d *Daemon) stop(t testing.TB, exited <-chan struct{}) {
+	if err := d.cmd.Process.Signal(os.Interrupt); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		t.Errorf("cannot stop server: %v", err)
+	}
+	select {
+	case <-exited:
+	case <-time.After(stopTimeout):
+		t.Errorf("server did not stop within %s", stopTimeout)
+		if err := d.cmd.Process.Kill(); err != nil {
+			t.Errorf("cannot kill server: %v", err)
+		}
+		<-exited
+	}
+}
+
+// Run runs the CLI with the specified arguments against the server and
+// returns its output and exit code. It fails the test if the CLI cannot be
+// run at all.
+func (d *Daemon) Run(t testing.TB, args ...string) *Result {
+	t.Helper()
+	cmd := exec.Command(d.Bin, args...)
+	cmd.Env = d.env
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		t.Fatalf("cannot run %s: %v", strings.Join(args, " "), err)
+	}
+	return &Result{Stdout: stdout.String(), Stderr: stderr.String(), ExitCode: cmd.ProcessState.ExitCode()}
+}
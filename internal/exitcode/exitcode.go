@@ -0,0 +1,58 @@
+// Package exitcode defines the exit codes of the To-do Daemon CLI, which allow
+// scripts to tell why a command failed, e.g. whether the server is not running
+// or the task to complete does not exist.
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The exit codes of the To-do Daemon CLI.
+const (
+	// OK means that the command succeeded.
+	OK = 0
+	// Failure means that the command failed for a reason without more
+	// specific exit code.
+	Failure = 1
+	// Usage means that the command-line arguments are invalid, e.g. an
+	// unknown command or flag, or a missing task ID.
+	Usage = 2
+	// NotRunning means that the To-do Daemon server is not running.
+	NotRunning = 3
+	// NotFound means that a task or another resource does not exist.
+	NotFound = 4
+	// Conflict means that the request conflicts with the state of the server,
+	// e.g. because a task was modified concurrently, the server is in
+	// read-only mode, or another server is already running or, in standalone
+	// mode, holds the lock file.
+	Conflict = 5
+	// Unavailable means that the server did not respond in time or cannot
+	// handle the request at the moment.
+	Unavailable = 6
+)
+
+// UsageError is the error returned for invalid command-line arguments.
+type UsageError struct {
+	err error
+}
+
+// NewUsageError creates a [UsageError] with a message formatted like
+// [fmt.Errorf], which means that it may wrap other errors.
+func NewUsageError(format string, args ...any) error {
+	return &UsageError{err: fmt.Errorf(format, args...)}
+}
+
+// IsUsageError checks if the provided error is a [UsageError].
+func IsUsageError(err error) bool {
+	var e *UsageError
+	return err != nil && errors.As(err, &e)
+}
+
+func (e *UsageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,21 @@
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIsUsageError(t *testing.T) {
+	cause := errors.New("flag provided but not defined: -x")
+	err := fmt.Errorf("cannot run command: %w", NewUsageError("invalid flag: %w", cause))
+	if !IsUsageError(err) {
+		t.Errorf("want usage error: %v", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("want usage error to wrap its cause: %v", err)
+	}
+	if IsUsageError(cause) || IsUsageError(nil) {
+		t.Error("want no usage error")
+	}
+}
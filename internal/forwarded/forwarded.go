@@ -0,0 +1,235 @@
+// Package forwarded determines the external URL of the To-do Daemon's HTTP
+// server, which differs from the server's own address when it runs behind a
+// reverse proxy, e.g. at "https://example.com/todo/".
+//
+// The external URL is taken from the X-Forwarded-Proto, X-Forwarded-Host, and
+// X-Forwarded-Prefix headers set by the proxy, falling back to a configured
+// external URL and then to the request's own host. Links returned by the REST
+// API are built from it, so they work for the clients of the proxy.
+//
+// Since any client can set these headers, they are only honored in requests
+// from trusted proxies, see [Proxies].
+package forwarded
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// The HTTP headers describing the original request received by a reverse
+// proxy.
+const (
+	HeaderProto  = "X-Forwarded-Proto"
+	HeaderHost   = "X-Forwarded-Host"
+	HeaderPrefix = "X-Forwarded-Prefix"
+	HeaderFor    = "X-Forwarded-For"
+)
+
+// ProxyUnix is the entry of the trusted proxies that trusts the clients
+// connected to the HTTP server's Unix domain socket.
+const ProxyUnix = "unix"
+
+// Proxies holds the addresses of the trusted reverse proxies. A nil *Proxies
+// trusts no one.
+type Proxies struct {
+	prefixes []netip.Prefix
+	unix     bool
+}
+
+// ParseProxies parses the specified trusted proxies, each of which is an IP
+// address like "127.0.0.1", a network like "10.0.0.0/8", or [ProxyUnix]. It
+// returns nil if there are no trusted proxies.
+func ParseProxies(specs []string) (*Proxies, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	p := &Proxies{}
+	for _, spec := range specs {
+		if spec == ProxyUnix {
+			p.unix = true
+			continue
+		}
+		if strings.Contains(spec, "/") {
+			prefix, err := netip.ParsePrefix(spec)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy '%s': %w", spec, err)
+			}
+			p.prefixes = append(p.prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy '%s': %w", spec, err)
+		}
+		p.prefixes = append(p.prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return p, nil
+}
+
+// trusts checks if the specified IP address, or the Unix domain socket peer
+// if ip is empty, is a trusted proxy.
+func (p *Proxies) trusts(ip string) bool {
+	if p == nil {
+		return false
+	}
+	if ip == "" {
+		return p.unix
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range p.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Trusted checks if the specified request was received from a trusted proxy.
+func (p *Proxies) Trusted(r *http.Request) bool {
+	return p.trusts(remoteIP(r))
+}
+
+// ClientIP returns the IP address of the client that sent the specified
+// request. For requests from trusted proxies, it is the last address in the
+// X-Forwarded-For header that is not a trusted proxy itself. It is empty for
+// clients connected to a Unix domain socket.
+func (p *Proxies) ClientIP(r *http.Request) string {
+	ip := remoteIP(r)
+	if !p.trusts(ip) {
+		return ip
+	}
+	hops := strings.Split(strings.Join(r.Header.Values(HeaderFor), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !p.trusts(hop) {
+			return hop
+		}
+		ip = hop
+	}
+	return ip
+}
+
+// remoteIP returns the IP address of the peer of the request's connection, or
+// an empty string for a Unix domain socket.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		// Peers of Unix domain sockets have addresses like "@" or "".
+		if _, err := netip.ParseAddr(r.RemoteAddr); err == nil {
+			return r.RemoteAddr
+		}
+		return ""
+	}
+	return host
+}
+
+type contextKey struct{}
+
+type clientIPKey struct{}
+
+// NewContext returns a copy of the specified context that holds the external
+// base URL.
+func NewContext(ctx context.Context, base *url.URL) context.Context {
+	return context.WithValue(ctx, contextKey{}, base)
+}
+
+// ClientIPFromContext returns the IP address of the client held by the
+// specified context, see [Proxies.ClientIP], and whether there is one.
+func ClientIPFromContext(ctx context.Context) (string, bool) {
+	ip, ok := ctx.Value(clientIPKey{}).(string)
+	return ip, ok
+}
+
+// FromContext returns the external base URL held by the specified context, or
+// nil if there is none.
+func FromContext(ctx context.Context) *url.URL {
+	base, _ := ctx.Value(contextKey{}).(*url.URL)
+	return base
+}
+
+// URL returns the external URL of the specified absolute path on the HTTP
+// server, e.g. "/api/v1/webhooks/1", using the base URL held by the context.
+// Without base URL, it returns the path as is.
+func URL(ctx context.Context, p string) string {
+	base := FromContext(ctx)
+	if base == nil {
+		return p
+	}
+	u := *base
+	u.Path = strings.TrimSuffix(u.Path, "/") + p
+	u.RawPath = ""
+	return u.String()
+}
+
+// BaseURL returns the external base URL of the specified request, derived from
+// the forwarded headers, the configured base URL (which may be nil), and the
+// request itself, in this order of precedence. The forwarded headers are
+// ignored unless the request was received from one of the trusted proxies.
+// The path of the returned URL never ends with a slash.
+func BaseURL(r *http.Request, configured *url.URL, proxies *Proxies) *url.URL {
+	base := &url.URL{Scheme: "http", Host: r.Host}
+	if r.TLS != nil {
+		base.Scheme = "https"
+	}
+	if configured != nil {
+		base = &url.URL{Scheme: configured.Scheme, Host: configured.Host, Path: configured.Path}
+	}
+	if !proxies.Trusted(r) {
+		base.Path = strings.TrimSuffix(base.Path, "/")
+		return base
+	}
+	if proto := strings.ToLower(firstValue(r.Header.Get(HeaderProto))); proto == "http" || proto == "https" {
+		base.Scheme = proto
+	}
+	if host := firstValue(r.Header.Get(HeaderHost)); host != "" {
+		base.Host = host
+	}
+	if prefix := firstValue(r.Header.Get(HeaderPrefix)); strings.HasPrefix(prefix, "/") {
+		base.Path = path.Clean(prefix)
+	}
+	base.Path = strings.TrimSuffix(base.Path, "/")
+	return base
+}
+
+// Middleware returns a handler that determines the external base URL and the
+// client IP address of each request, see [BaseURL] and [Proxies.ClientIP],
+// before passing the request to the next handler. They can be retrieved from
+// the request's context using [FromContext] and [ClientIPFromContext].
+//
+// If the request's path starts with the path of the base URL, e.g. because
+// the proxy passes "/todo/api/v1/tasks" on as is, the prefix is removed, so
+// the next handler sees "/api/v1/tasks" either way.
+func Middleware(next http.Handler, configured *url.URL, proxies *Proxies) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		base := BaseURL(r, configured, proxies)
+		ctx := context.WithValue(NewContext(r.Context(), base), clientIPKey{}, proxies.ClientIP(r))
+		r = r.WithContext(ctx)
+		if prefix := base.Path; prefix != "" && strings.HasPrefix(r.URL.Path, prefix+"/") {
+			u := *r.URL
+			u.Path = strings.TrimPrefix(u.Path, prefix)
+			u.RawPath = strings.TrimPrefix(u.RawPath, prefix)
+			r.URL = &u
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// firstValue returns the first of the comma-separated values of a header, which
+// is the one set by the proxy closest to the client.
+func firstValue(s string) string {
+	first, _, _ := strings.Cut(s, ",")
+	return strings.TrimSpace(first)
+}
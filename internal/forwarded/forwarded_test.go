@@ -0,0 +1,134 @@
+package forwarded
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// mustParseProxies parses the specified trusted proxies or fails the test.
+func mustParseProxies(t *testing.T, specs ...string) *Proxies {
+	t.Helper()
+	proxies, err := ParseProxies(specs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proxies
+}
+
+func TestBaseURL(t *testing.T) {
+	configured := &url.URL{Scheme: "https", Host: "example.com", Path: "/todo/"}
+	forwarded := map[string]string{
+		HeaderProto:  "https",
+		HeaderHost:   "proxy.example.com, internal",
+		HeaderPrefix: "/tasks/",
+	}
+	// httptest.NewRequest sets the remote address to 192.0.2.1:1234.
+	trusted := mustParseProxies(t, "10.0.0.1", "192.0.2.0/24")
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		configured *url.URL
+		proxies    *Proxies
+		want       string
+	}{
+		{"Request", nil, nil, trusted, "http://127.0.0.1:8080"},
+		{"Configured", nil, configured, trusted, "https://example.com/todo"},
+		{"Forwarded", forwarded, configured, trusted, "https://proxy.example.com/tasks"},
+		{"NoTrustedProxies", forwarded, configured, nil, "https://example.com/todo"},
+		{"UntrustedPeer", forwarded, nil, mustParseProxies(t, "10.0.0.1", ProxyUnix), "http://127.0.0.1:8080"},
+		{"InvalidProto", map[string]string{HeaderProto: "ftp"}, nil, trusted, "http://127.0.0.1:8080"},
+		{"RelativePrefix", map[string]string{HeaderPrefix: "todo"}, nil, trusted, "http://127.0.0.1:8080"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8080/api/v1/tasks", nil)
+			for name, value := range tt.headers {
+				r.Header.Set(name, value)
+			}
+			if got := BaseURL(r, tt.configured, tt.proxies).String(); got != tt.want {
+				t.Errorf("want: %s; got: %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var gotPath, gotURL string
+	handler := Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotURL = URL(r.Context(), "/api/v1/webhooks/1")
+	}), nil, mustParseProxies(t, "192.0.2.1"))
+
+	for _, path := range []string{"/todo/api/v1/webhooks", "/api/v1/webhooks"} {
+		r := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:8080"+path, nil)
+		r.Header.Set(HeaderPrefix, "/todo")
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+		if gotPath != "/api/v1/webhooks" {
+			t.Errorf("%s: want path: /api/v1/webhooks; got: %s", path, gotPath)
+		}
+		if want := "http://127.0.0.1:8080/todo/api/v1/webhooks/1"; gotURL != want {
+			t.Errorf("%s: want URL: %s; got: %s", path, want, gotURL)
+		}
+	}
+}
+
+func TestProxiesTrusted(t *testing.T) {
+	proxies := mustParseProxies(t, "127.0.0.1", "10.0.0.0/8", "::1", ProxyUnix)
+	tests := []struct {
+		remoteAddr string
+		want       bool
+	}{
+		{"127.0.0.1:1234", true},
+		{"[::ffff:127.0.0.1]:1234", true},
+		{"10.1.2.3:1234", true},
+		{"[::1]:1234", true},
+		{"127.0.0.2:1234", false},
+		{"192.0.2.1:1234", false},
+		{"@", true},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tt.remoteAddr
+		if got := proxies.Trusted(r); got != tt.want {
+			t.Errorf("%s: want trusted: %t; got: %t", tt.remoteAddr, tt.want, got)
+		}
+	}
+	var none *Proxies
+	if r := httptest.NewRequest(http.MethodGet, "/", nil); none.Trusted(r) {
+		t.Error("want nil proxies to trust no one")
+	}
+	for _, spec := range []string{"localhost", "10.0.0.0/33", ""} {
+		if _, err := ParseProxies([]string{spec}); err == nil {
+			t.Errorf("%q: want error", spec)
+		}
+	}
+}
+
+func TestProxiesClientIP(t *testing.T) {
+	proxies := mustParseProxies(t, "10.0.0.0/8", ProxyUnix)
+	tests := []struct {
+		remoteAddr   string
+		forwardedFor []string
+		want         string
+	}{
+		{"192.0.2.1:1234", nil, "192.0.2.1"},
+		{"192.0.2.1:1234", []string{"198.51.100.1"}, "192.0.2.1"},
+		{"10.0.0.1:1234", nil, "10.0.0.1"},
+		{"10.0.0.1:1234", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"10.0.0.1:1234", []string{"203.0.113.1, 198.51.100.1", "10.0.0.2"}, "198.51.100.1"},
+		{"10.0.0.1:1234", []string{"10.0.0.3, 10.0.0.2"}, "10.0.0.3"},
+		{"@", []string{"198.51.100.1"}, "198.51.100.1"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tt.remoteAddr
+		for _, value := range tt.forwardedFor {
+			r.Header.Add(HeaderFor, value)
+		}
+		if got := proxies.ClientIP(r); got != tt.want {
+			t.Errorf("%s %q: want: %s; got: %s", tt.remoteAddr, tt.forwardedFor, tt.want, got)
+		}
+	}
+}
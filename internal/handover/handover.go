@@ -0,0 +1,67 @@
+// Package handover passes the listeners of a running To-do Daemon server to
+// the server taking over from it, see 'todo-daemon run --takeover'.
+//
+// The new server listens on a Unix domain socket with [Listen] and asks the
+// running server to connect to it. The running server then sends the file
+// descriptors of its listeners with [Send], so the new server accepts the
+// connections on the very same sockets and no connection attempt fails while
+// the servers change places.
+package handover
+
+import (
+	"errors"
+	"net"
+)
+
+// ErrUnsupported is returned on platforms that cannot pass file descriptors
+// between processes, i.e. Windows.
+var ErrUnsupported = errors.New("handing over listeners is not supported on this platform")
+
+// Listeners are the listeners of a To-do Daemon server.
+type Listeners struct {
+	// GRPC is the listener of the gRPC server.
+	GRPC net.Listener
+	// HTTP is the listener of the HTTP server, or nil if the HTTP server is
+	// disabled.
+	HTTP net.Listener
+}
+
+// Close closes all listeners.
+func (l Listeners) Close() error {
+	var errs []error
+	for _, listener := range []net.Listener{l.GRPC, l.HTTP} {
+		if listener != nil {
+			errs = append(errs, listener.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// The names of the listeners in the message that carries their file
+// descriptors, in the same order as the descriptors.
+const (
+	nameGRPC = "grpc"
+	nameHTTP = "http"
+)
+
+// message is the data sent along with the file descriptors.
+type message struct {
+	Listeners []string `json:"listeners"`
+}
+
+// Receiver receives the listeners handed over by a running server.
+type Receiver struct {
+	listener *net.UnixListener
+}
+
+// Path returns the path of the Unix domain socket that the receiver listens
+// on, which the running server is supposed to connect to.
+func (r *Receiver) Path() string {
+	return r.listener.Addr().String()
+}
+
+// Close stops listening and removes the receiver's socket file. The listeners
+// received before are not affected.
+func (r *Receiver) Close() error {
+	return r.listener.Close()
+}
@@ -0,0 +1,180 @@
+//go:build !windows
+
+package handover
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+// maxListeners is the maximum number of file descriptors in a handover.
+const maxListeners = 2
+
+// Listen creates a [Receiver] listening on the Unix domain socket at the
+// specified path. A socket file left behind at the path is removed.
+func Listen(path string) (*Receiver, error) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("cannot remove stale handover socket: %w", err)
+	}
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	return &Receiver{listener: l}, nil
+}
+
+// Receive waits for the running server to connect and send its listeners, or
+// until the context is canceled.
+func (r *Receiver) Receive(ctx context.Context) (Listeners, error) {
+	stop := context.AfterFunc(ctx, func() {
+		_ = r.listener.SetDeadline(time.Now())
+	})
+	defer stop()
+	conn, err := r.listener.AcceptUnix()
+	if err != nil {
+		if ctx.Err() != nil {
+			return Listeners{}, ctx.Err()
+		}
+		return Listeners{}, err
+	}
+	defer conn.Close()
+	stopConn := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+	defer stopConn()
+
+	buf := make([]byte, 4096)
+	oob := make([]byte, syscall.CmsgSpace(maxListeners*4))
+	n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		return Listeners{}, fmt.Errorf("cannot receive listeners: %w", err)
+	}
+	files, err := parseRights(oob[:oobn])
+	if err != nil {
+		return Listeners{}, fmt.Errorf("cannot receive listeners: %w", err)
+	}
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+
+	var msg message
+	if err := json.Unmarshal(buf[:n], &msg); err != nil {
+		return Listeners{}, fmt.Errorf("invalid handover message: %w", err)
+	}
+	if len(msg.Listeners) != len(files) {
+		return Listeners{}, fmt.Errorf("invalid handover message: %d listeners but %d file descriptors",
+			len(msg.Listeners), len(files))
+	}
+	var ls Listeners
+	for i, name := range msg.Listeners {
+		l, err := net.FileListener(files[i])
+		if err != nil {
+			return Listeners{}, errors.Join(fmt.Errorf("cannot restore %s listener: %w", name, err), ls.Close())
+		}
+		switch name {
+		case nameGRPC:
+			ls.GRPC = l
+		case nameHTTP:
+			ls.HTTP = l
+		default:
+			return Listeners{}, errors.Join(fmt.Errorf("unknown listener: '%s'", name), l.Close(), ls.Close())
+		}
+	}
+	if ls.GRPC == nil {
+		return Listeners{}, errors.Join(errors.New("no gRPC listener handed over"), ls.Close())
+	}
+	return ls, nil
+}
+
+// parseRights returns the files of the file descriptors in the specified
+// socket control messages.
+func parseRights(oob []byte) ([]*os.File, error) {
+	msgs, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return nil, err
+	}
+	var files []*os.File
+	for i := range msgs {
+		fds, err := syscall.ParseUnixRights(&msgs[i])
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			files = append(files, os.NewFile(uintptr(fd), "listener"))
+		}
+	}
+	return files, nil
+}
+
+// Send connects to the [Receiver] listening on the Unix domain socket at the
+// specified path and sends it the file descriptors of the specified
+// listeners. The listeners keep working, so the sender has to stop accepting
+// connections on them itself.
+func Send(ctx context.Context, path string, ls Listeners) error {
+	var d net.Dialer
+	c, err := d.DialContext(ctx, "unix", path)
+	if err != nil {
+		return fmt.Errorf("cannot connect to handover socket: %w", err)
+	}
+	conn, ok := c.(*net.UnixConn)
+	if !ok {
+		_ = c.Close()
+		return fmt.Errorf("cannot connect to handover socket: not a Unix domain socket: %s", path)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	var msg message
+	var fds []int
+	var files []*os.File
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+	for _, l := range []struct {
+		name     string
+		listener net.Listener
+	}{
+		{nameGRPC, ls.GRPC},
+		{nameHTTP, ls.HTTP},
+	} {
+		if l.listener == nil {
+			continue
+		}
+		f, err := file(l.listener)
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+		fds = append(fds, int(f.Fd()))
+		msg.Listeners = append(msg.Listeners, l.name)
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, _, err := conn.WriteMsgUnix(data, syscall.UnixRights(fds...), nil); err != nil {
+		return fmt.Errorf("cannot send listeners: %w", err)
+	}
+	return nil
+}
+
+// file returns a duplicate of the file descriptor of the specified listener.
+func file(l net.Listener) (*os.File, error) {
+	f, ok := l.(interface{ File() (*os.File, error) })
+	if !ok {
+		return nil, fmt.Errorf("cannot hand over listener on %s: no file descriptor", l.Addr())
+	}
+	return f.File()
+}
@@ -0,0 +1,90 @@
+//go:build !windows
+
+package handover
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+func TestSendReceive(t *testing.T) {
+	dir := t.TempDir()
+	grpcListener, err := net.Listen("unix", filepath.Join(dir, "grpc.sock"))
+	if err != nil {
+		t.Fatalf("cannot listen: %v", err)
+	}
+	defer grpcListener.Close()
+	httpListener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("cannot listen: %v", err)
+	}
+	defer httpListener.Close()
+
+	r, err := Listen(filepath.Join(dir, "handover.sock"))
+	if err != nil {
+		t.Fatalf("Listen() failed: %v", err)
+	}
+	defer r.Close()
+
+	sent := make(chan error, 1)
+	go func() {
+		sent <- Send(t.Context(), r.Path(), Listeners{GRPC: grpcListener, HTTP: httpListener})
+	}()
+	ls, err := r.Receive(t.Context())
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	defer ls.Close()
+	if err := <-sent; err != nil {
+		t.Fatalf("Send() failed: %v", err)
+	}
+
+	if got, want := ls.GRPC.Addr().String(), grpcListener.Addr().String(); got != want {
+		t.Errorf("received gRPC listener on %s, want %s", got, want)
+	}
+	if got, want := ls.HTTP.Addr().String(), httpListener.Addr().String(); got != want {
+		t.Errorf("received HTTP listener on %s, want %s", got, want)
+	}
+
+	// The sender stops accepting, so connections end up at the receiver.
+	if err := httpListener.Close(); err != nil {
+		t.Fatalf("cannot close listener: %v", err)
+	}
+	conn, err := net.Dial("tcp", ls.HTTP.Addr().String())
+	if err != nil {
+		t.Fatalf("cannot connect to received listener: %v", err)
+	}
+	defer conn.Close()
+	accepted, err := ls.HTTP.Accept()
+	if err != nil {
+		t.Fatalf("received listener cannot accept: %v", err)
+	}
+	accepted.Close()
+}
+
+func TestReceiveWithoutHTTP(t *testing.T) {
+	dir := t.TempDir()
+	grpcListener, err := net.Listen("unix", filepath.Join(dir, "grpc.sock"))
+	if err != nil {
+		t.Fatalf("cannot listen: %v", err)
+	}
+	defer grpcListener.Close()
+	r, err := Listen(filepath.Join(dir, "handover.sock"))
+	if err != nil {
+		t.Fatalf("Listen() failed: %v", err)
+	}
+	defer r.Close()
+
+	go func() {
+		_ = Send(t.Context(), r.Path(), Listeners{GRPC: grpcListener})
+	}()
+	ls, err := r.Receive(t.Context())
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	defer ls.Close()
+	if ls.HTTP != nil {
+		t.Errorf("received HTTP listener on %s, want none", ls.HTTP.Addr())
+	}
+}
@@ -0,0 +1,20 @@
+//go:build windows
+
+package handover
+
+import "context"
+
+// Listen always returns [ErrUnsupported].
+func Listen(string) (*Receiver, error) {
+	return nil, ErrUnsupported
+}
+
+// Receive always returns [ErrUnsupported].
+func (*Receiver) Receive(context.Context) (Listeners, error) {
+	return Listeners{}, ErrUnsupported
+}
+
+// Send always returns [ErrUnsupported].
+func Send(context.Context, string, Listeners) error {
+	return ErrUnsupported
+}
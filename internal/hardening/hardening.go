@@ -0,0 +1,125 @@
+// Package hardening protects the HTTP server of the To-do Daemon against
+// oversized and malformed requests, and instructs browsers to apply their
+// security mechanisms to its responses.
+package hardening
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/mwopitz/todo-daemon/internal/forwarded"
+	"github.com/mwopitz/todo-daemon/internal/rest"
+)
+
+// DefaultMaxBodySize is the default maximum size of request bodies, in bytes.
+const DefaultMaxBodySize = 4 << 20
+
+// contentSecurityPolicy only allows the web UI to load its own resources and
+// forbids embedding any page in frames.
+const contentSecurityPolicy = "default-src 'self'; base-uri 'none'; form-action 'self'; frame-ancestors 'none'"
+
+// securityHeaders are the headers added to all responses if security headers
+// are enabled.
+var securityHeaders = map[string]string{
+	"Content-Security-Policy": contentSecurityPolicy,
+	"X-Content-Type-Options":  "nosniff",
+	"X-Frame-Options":         "DENY",
+	"Referrer-Policy":         "no-referrer",
+}
+
+// Policy describes which requests the HTTP server accepts and which security
+// headers it sends.
+type Policy struct {
+	// MaxBodySize is the maximum size of request bodies in bytes. A size <= 0
+	// means [DefaultMaxBodySize].
+	MaxBodySize int64
+	// SecurityHeaders specifies whether responses carry headers like
+	// X-Content-Type-Options and Content-Security-Policy, and
+	// Strict-Transport-Security if they are sent over HTTPS.
+	SecurityHeaders bool
+}
+
+// BodyLimit returns the maximum size of request bodies in bytes.
+func (p *Policy) BodyLimit() int64 {
+	if p.MaxBodySize <= 0 {
+		return DefaultMaxBodySize
+	}
+	return p.MaxBodySize
+}
+
+// Middleware returns a handler that rejects requests whose bodies exceed the
+// maximum size with "413 Request Entity Too Large", and requests with a body that is
+// not JSON with "415 Unsupported Media Type". Requiring a JSON content type
+// also keeps browsers from sending requests to the REST API from HTML forms
+// of other origins. Bodies of unknown length are read up to the maximum size
+// before the request is passed on.
+func (p *Policy) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p.SecurityHeaders {
+			p.addSecurityHeaders(w, r)
+		}
+		if !hasBody(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !isJSON(r.Header.Get("Content-Type")) {
+			rest.WriteError(w, r, http.StatusUnsupportedMediaType,
+				"unsupported content type '%s': want application/json", r.Header.Get("Content-Type"))
+			return
+		}
+		limit := p.BodyLimit()
+		if r.ContentLength > limit {
+			rest.WriteError(w, r, http.StatusRequestEntityTooLarge, "request body exceeds %d bytes", limit)
+			return
+		}
+		if r.ContentLength < 0 {
+			b, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+			if err != nil {
+				rest.WriteError(w, r, http.StatusBadRequest, "cannot read request body: %v", err)
+				return
+			}
+			if int64(len(b)) > limit {
+				rest.WriteError(w, r, http.StatusRequestEntityTooLarge, "request body exceeds %d bytes", limit)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(b))
+			r.ContentLength = int64(len(b))
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// addSecurityHeaders adds the security headers to the response to the
+// specified request.
+func (*Policy) addSecurityHeaders(w http.ResponseWriter, r *http.Request) {
+	for name, value := range securityHeaders {
+		w.Header().Set(name, value)
+	}
+	if base := forwarded.FromContext(r.Context()); r.TLS != nil || (base != nil && base.Scheme == "https") {
+		w.Header().Set("Strict-Transport-Security", "max-age=31536000")
+	}
+}
+
+// hasBody checks if the specified request has a body. Bodies of GET, HEAD,
+// DELETE, and OPTIONS requests are ignored by the server anyway.
+func hasBody(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete, http.MethodOptions:
+		return false
+	}
+	return r.ContentLength != 0 && r.Body != nil && r.Body != http.NoBody
+}
+
+// isJSON checks if the specified content type is application/json or a
+// structured syntax with the +json suffix.
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,101 @@
+package hardening
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	p := &Policy{MaxBodySize: 16}
+	h := p.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	tests := []struct {
+		name        string
+		method      string
+		body        string
+		contentType string
+		chunked     bool
+		wantStatus  int
+	}{
+		{"Get", http.MethodGet, "", "", false, http.StatusOK},
+		{"EmptyPost", http.MethodPost, "", "", false, http.StatusOK},
+		{"JSON", http.MethodPost, `{"summary":"a"}`, "application/json", false, http.StatusOK},
+		{"JSONCharset", http.MethodPatch, `{}`, "application/json; charset=utf-8", false, http.StatusOK},
+		{"MergePatch", http.MethodPatch, `{}`, "application/merge-patch+json", false, http.StatusOK},
+		{"Form", http.MethodPost, `summary=a`, "application/x-www-form-urlencoded", false, http.StatusUnsupportedMediaType},
+		{"NoContentType", http.MethodPost, `{}`, "", false, http.StatusUnsupportedMediaType},
+		{"TooLarge", http.MethodPost, `{"summary":"abcdefgh"}`, "application/json", false, http.StatusRequestEntityTooLarge},
+		{"ChunkedJSON", http.MethodPost, `{"summary":"a"}`, "application/json", true, http.StatusOK},
+		{"ChunkedTooLarge", http.MethodPost, `{"summary":"abcdefgh"}`, "application/json", true, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req := httptest.NewRequest(tt.method, "/api/v1/tasks", body)
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("want status %d; got: %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestMiddlewareSecurityHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		https    bool
+		wantHSTS bool
+	}{
+		{"Disabled", false, true, false},
+		{"HTTP", true, false, false},
+		{"HTTPS", true, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{SecurityHeaders: tt.enabled}
+			h := p.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			target := "http://localhost/api/v1/tasks"
+			if tt.https {
+				target = "https://localhost/api/v1/tasks"
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+			if got := rec.Header().Get("X-Content-Type-Options") == "nosniff"; got != tt.enabled {
+				t.Errorf("want security headers: %t; got: %v", tt.enabled, rec.Header())
+			}
+			if got := rec.Header().Get("Strict-Transport-Security") != ""; got != tt.wantHSTS {
+				t.Errorf("want Strict-Transport-Security: %t; got: %v", tt.wantHSTS, rec.Header())
+			}
+		})
+	}
+}
+
+func TestPolicyBodyLimit(t *testing.T) {
+	if got := (&Policy{}).BodyLimit(); got != DefaultMaxBodySize {
+		t.Errorf("want default limit %d; got: %d", DefaultMaxBodySize, got)
+	}
+	if got := (&Policy{MaxBodySize: 10}).BodyLimit(); got != 10 {
+		t.Errorf("want limit 10; got: %d", got)
+	}
+}
@@ -0,0 +1,225 @@
+// Package hook implements the hook scripts of the To-do Daemon, which are
+// executed on task events with a JSON payload on standard input.
+//
+// The hook scripts reside in a single directory and are named after the
+// events that trigger them, e.g. "on-complete" for [todo.EventTaskCompleted].
+// Only the scripts on an allowlist are ever executed.
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/logging"
+	"github.com/mwopitz/todo-daemon/internal/rest"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// EventEnv is the environment variable holding the type of the event that
+// triggered the hook script.
+const EventEnv = "TODO_DAEMON_EVENT"
+
+// maxOutput is the maximum number of bytes of a hook script's output that are
+// kept and logged. The rest of the output is discarded.
+const maxOutput = 4096
+
+// waitDelay is the maximum amount of time to wait for the output of a hook
+// script after it has been killed, since processes it started in the
+// background may keep its stdout and stderr open.
+const waitDelay = time.Second
+
+// names maps the event types to the names of the hook scripts.
+var names = map[todo.EventType]string{
+	todo.EventTaskCreated:   "on-create",
+	todo.EventTaskUpdated:   "on-update",
+	todo.EventTaskCompleted: "on-complete",
+	todo.EventTaskDeleted:   "on-delete",
+	todo.EventTaskAssigned:  "on-assign",
+}
+
+// Name returns the name of the hook script triggered by the specified event
+// type.
+func Name(t todo.EventType) (string, bool) {
+	name, ok := names[t]
+	return name, ok
+}
+
+// IsValidName checks if the specified name is the name of a hook script, e.g.
+// "on-complete".
+func IsValidName(name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// payload is the JSON document that is written to the hook script's standard
+// input.
+type payload struct {
+	Type todo.EventType `json:"type"`
+	Time time.Time      `json:"time"`
+	Task *rest.Task     `json:"task"`
+}
+
+// Runner executes the hook scripts for task events.
+//
+// While the runner is running, its fields must only be changed using
+// [Runner.Reconfigure].
+type Runner struct {
+	mu sync.RWMutex
+
+	// Dir is the directory containing the hook scripts.
+	Dir string
+	// Allow holds the names of the hook scripts that may be executed.
+	Allow []string
+	// Timeout is the maximum amount of time a hook script may run before it is
+	// killed. A timeout <= 0 means that there is no timeout.
+	Timeout time.Duration
+	// MaxConcurrent is the maximum number of hook scripts running at the same
+	// time. If MaxConcurrent <= 0, it defaults to 1.
+	MaxConcurrent int
+}
+
+// Reconfigure changes the directory, allowlist, and timeout of the hook
+// scripts. It can be called while the runner is running; the changes apply to
+// the scripts started afterwards.
+func (r *Runner) Reconfigure(dir string, allow []string, timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Dir = dir
+	r.Allow = slices.Clone(allow)
+	r.Timeout = timeout
+}
+
+// Enabled reports whether any hook scripts are allowed to be executed.
+func (r *Runner) Enabled() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.Allow) > 0
+}
+
+// Run executes the hook scripts for the events received from the specified
+// channel until the channel is closed or the context is canceled. It waits
+// for all running hook scripts to finish before returning.
+func (r *Runner) Run(ctx context.Context, events <-chan todo.Event) {
+	sem := make(chan struct{}, max(r.MaxConcurrent, 1))
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			path, timeout, ok := r.script(e.Type)
+			if !ok {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case sem <- struct{}{}:
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				r.exec(ctx, path, timeout, &e)
+			}()
+		}
+	}
+}
+
+// script returns the path to the hook script for the specified event type, and
+// its timeout, if the script is allowed and exists.
+func (r *Runner) script(t todo.EventType) (string, time.Duration, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	name, ok := Name(t)
+	if !ok || !slices.Contains(r.Allow, name) {
+		return "", 0, false
+	}
+	path := filepath.Join(r.Dir, name)
+	info, err := os.Stat(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger().Warn("cannot access hook script", "path", path, "cause", err)
+		}
+		return "", 0, false
+	}
+	return path, r.Timeout, info.Mode().IsRegular()
+}
+
+func (r *Runner) exec(ctx context.Context, path string, timeout time.Duration, e *todo.Event) {
+	body, err := json.Marshal(&payload{
+		Type: e.Type,
+		Time: e.Time,
+		Task: rest.NewTask(&e.Task),
+	})
+	if err != nil {
+		logger().Error("cannot encode hook payload", "path", path, "cause", err)
+		return
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// #nosec G204 -- only allowlisted scripts from the hook directory are run.
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Dir = filepath.Dir(path)
+	cmd.Env = append(os.Environ(), EventEnv+"="+string(e.Type))
+	cmd.Stdin = bytes.NewReader(body)
+	output := &limitedBuffer{max: maxOutput}
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.WaitDelay = waitDelay
+
+	start := time.Now()
+	err = cmd.Run()
+	duration := time.Since(start)
+	out := output.buf.Bytes()
+	if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		logger().Warn("hook script failed", "path", path, "event", e.Type, "duration", duration,
+			"cause", err, "output", string(out))
+		return
+	}
+	logger().Debug("hook script finished", "path", path, "event", e.Type, "duration", duration)
+}
+
+// limitedBuffer is a writer that keeps the first max bytes written to it and
+// discards the rest, so a hook script cannot use up the memory of the server.
+type limitedBuffer struct {
+	buf bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if n := b.max - b.buf.Len(); n > 0 {
+		b.buf.Write(p[:min(n, len(p))])
+	}
+	// The script must not fail because its output is discarded.
+	return len(p), nil
+}
+
+// logger returns the logger of the messages about the hook scripts.
+func logger() *slog.Logger {
+	return logging.Component(logging.ComponentHook)
+}
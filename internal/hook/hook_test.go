@@ -0,0 +1,77 @@
+//go:build !windows
+
+package hook
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+func TestRunnerExecutesAllowedScripts(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	script := "#!/bin/sh\nprintf '%s ' \"$" + EventEnv + "\" >> " + out + "\ncat >> " + out + "\n"
+	for _, name := range []string{"on-complete", "on-delete"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := &Runner{Dir: dir, Allow: []string{"on-complete"}, Timeout: 5 * time.Second}
+	events := make(chan todo.Event, 2)
+	events <- todo.Event{Type: todo.EventTaskDeleted, Task: todo.Task{ID: "1"}}
+	events <- todo.Event{Type: todo.EventTaskCompleted, Task: todo.Task{ID: "2", Summary: "foo"}}
+	close(events)
+	r.Run(context.Background(), events)
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	if !strings.HasPrefix(got, "task.completed {") || !strings.Contains(got, `"summary":"foo"`) {
+		t.Errorf("unexpected hook output: %q", got)
+	}
+	if strings.Contains(got, "task.deleted") {
+		t.Errorf("want on-delete not to be executed; got output: %q", got)
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	b := &limitedBuffer{max: 5}
+	for _, s := range []string{"abc", "defg", "hij"} {
+		if n, err := b.Write([]byte(s)); n != len(s) || err != nil {
+			t.Errorf("want %d bytes written; got: %d, %v", len(s), n, err)
+		}
+	}
+	if got := b.buf.String(); got != "abcde" {
+		t.Errorf("want output: abcde; got: %s", got)
+	}
+}
+
+// TestRunnerKillsScriptsAfterTimeout checks that a hook script is killed after
+// its timeout, even if it left a process in the background that keeps its
+// output open.
+func TestRunnerKillsScriptsAfterTimeout(t *testing.T) {
+	dir := t.TempDir()
+	script := "#!/bin/sh\nsleep 30 &\nyes\n"
+	if err := os.WriteFile(filepath.Join(dir, "on-create"), []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Runner{Dir: dir, Allow: []string{"on-create"}, Timeout: 100 * time.Millisecond}
+	events := make(chan todo.Event, 1)
+	events <- todo.Event{Type: todo.EventTaskCreated, Task: todo.Task{ID: "1"}}
+	close(events)
+	start := time.Now()
+	r.Run(context.Background(), events)
+	if d := time.Since(start); d > 10*time.Second {
+		t.Errorf("want hook script to be killed after its timeout; took: %v", d)
+	}
+}
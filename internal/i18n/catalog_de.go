@@ -0,0 +1,455 @@
+package i18n
+
+// german is the German message catalog.
+var german = map[string]string{
+	// Layouts of dates and times, see package time.
+	"2006-01-02 15:04":    "02.01.2006 15:04",
+	"2006-01-02 15:04:05": "02.01.2006 15:04:05",
+
+	// Sections of the help text.
+	"NAME:":                       "NAME:",
+	"USAGE:":                      "AUFRUF:",
+	"VERSION:":                    "VERSION:",
+	"DESCRIPTION:":                "BESCHREIBUNG:",
+	"CATEGORY:":                   "KATEGORIE:",
+	"COMMANDS:":                   "BEFEHLE:",
+	"OPTIONS:":                    "OPTIONEN:",
+	"GLOBAL OPTIONS:":             "GLOBALE OPTIONEN:",
+	"COPYRIGHT:":                  "COPYRIGHT:",
+	"[global options]":            "[globale Optionen]",
+	"[command [command options]]": "[Befehl [Befehlsoptionen]]",
+	"[arguments...]":              "[Argumente...]",
+	"default":                     "Standard",
+	"Shows a list of commands or help for one command": "Eine Liste der Befehle oder die Hilfe zu einem " +
+		"Befehl anzeigen",
+	"show help":         "Hilfe anzeigen",
+	"print the version": "Version ausgeben",
+
+	// Commands.
+	"A daemon for managing a to-do list": "Ein Daemon zum Verwalten einer To-do-Liste",
+	"Add a task to the to-do list, or one task per line of stdin ('-') or a file": "Eine Aufgabe zur " +
+		"To-do-Liste hinzufügen, oder eine Aufgabe pro Zeile der Standardeingabe ('-') oder einer Datei",
+	"Add the tasks queued with 'tasks add --offline' to the to-do list": "Die mit 'tasks add --offline' " +
+		"vorgemerkten Aufgaben zur To-do-Liste hinzufügen",
+	"Adds some demo tasks to the to-do list":           "Fügt einige Beispielaufgaben zur To-do-Liste hinzu",
+	"Assign a task to a user":                          "Eine Aufgabe einem Benutzer zuweisen",
+	"Back up and restore the to-do list":               "Die To-do-Liste sichern und wiederherstellen",
+	"Block a task until other tasks are completed":     "Eine Aufgabe blockieren, bis andere Aufgaben erledigt sind",
+	"Check the setup of the To-do Daemon for problems": "Die Einrichtung des To-do Daemons auf Probleme prüfen",
+	"Create all tasks of a template":                   "Alle Aufgaben einer Vorlage anlegen",
+	"Create copies of a task":                          "Kopien einer Aufgabe anlegen",
+	"Debug the To-do Daemon":                           "Den To-do Daemon debuggen",
+	"Forward connections to a To-do Daemon server, e.g. to reach a remote server through SSH": "Verbindungen " +
+		"an einen To-do-Daemon-Server weiterleiten, z. B. um einen entfernten Server über SSH zu erreichen",
+	"Inspect the profiles of the To-do Daemon": "Die Profile des To-do Daemons untersuchen",
+	"Invoke a gRPC method with a JSON request": "Eine gRPC-Methode mit einer JSON-Anfrage aufrufen",
+	"List the background jobs of the server with their last and next runs": "Die Hintergrundjobs des Servers " +
+		"mit ihrer letzten und nächsten Ausführung auflisten",
+	"List the named filters":                                "Die benannten Filter auflisten",
+	"List the scheduled snapshots":                          "Die geplanten Sicherungen auflisten",
+	"List the profiles and whether their server is running": "Die Profile auflisten und ob ihr Server läuft",
+	"List the task templates":                               "Die Aufgabenvorlagen auflisten",
+	"Make the To-do Daemon server reload its configuration file": "Den To-do-Daemon-Server seine " +
+		"Konfigurationsdatei neu laden lassen",
+	"Manage task templates and create tasks from them": "Aufgabenvorlagen verwalten und Aufgaben daraus anlegen",
+	"Manage tasks in the to-do list":                   "Aufgaben in der To-do-Liste verwalten",
+	"Manage the named filters for listing tasks": "Die benannten Filter zum Auflisten von Aufgaben " +
+		"verwalten",
+	"Marks a task in the to-do list as done": "Markiert eine Aufgabe in der To-do-Liste als erledigt",
+	"Move a task in the manual order of the to-do list": "Eine Aufgabe in der manuellen Reihenfolge der " +
+		"To-do-Liste verschieben",
+	"Moves all completed tasks from the to-do list to the trash": "Verschiebt alle erledigten Aufgaben in den Papierkorb",
+	"Moves removed tasks from the trash back to the to-do list": "Verschiebt entfernte Aufgaben aus dem Papierkorb " +
+		"zurück in die To-do-Liste",
+	"Moves tasks from the to-do list to the trash":       "Verschiebt Aufgaben aus der To-do-Liste in den Papierkorb",
+	"Open a link of a task in the default web browser":   "Einen Link einer Aufgabe im Standard-Webbrowser öffnen",
+	"Print all tasks in the to-do list":                  "Alle Aufgaben der To-do-Liste ausgeben",
+	"Print statistics about the tasks in the to-do list": "Statistiken über die Aufgaben der To-do-Liste ausgeben",
+	"Print the details of a task in the to-do list":      "Die Details einer Aufgabe der To-do-Liste ausgeben",
+	"Print the recent log messages of the server":        "Die letzten Log-Meldungen des Servers ausgeben",
+	"Print the status of the To-do Daemon server":        "Den Status des To-do-Daemon-Servers ausgeben",
+	"Remove a saved filter":                              "Einen gespeicherten Filter entfernen",
+	"Remove a task template":                             "Eine Aufgabenvorlage entfernen",
+	"Remove the assignment of a task to a user":          "Die Zuweisung einer Aufgabe an einen Benutzer aufheben",
+	"Remove the star from a task":                        "Den Stern von einer Aufgabe entfernen",
+	"Replace the to-do list with the content of a snapshot file": "Die To-do-Liste durch den Inhalt " +
+		"einer Sicherungsdatei ersetzen",
+	"Run the To-do Daemon server":           "Den To-do-Daemon-Server ausführen",
+	"Save a named filter for listing tasks": "Einen benannten Filter zum Auflisten von Aufgaben speichern",
+	"Save a task template":                  "Eine Aufgabenvorlage speichern",
+	"Search the summaries and descriptions of the tasks in the to-do list": "Die Titel und Beschreibungen " +
+		"der Aufgaben in der To-do-Liste durchsuchen",
+	"Star a task, which lists it before the other tasks": "Eine Aufgabe mit einem Stern markieren, " +
+		"wodurch sie vor den anderen Aufgaben aufgelistet wird",
+	"Synchronize the to-do list with the to-do list of another To-do Daemon": "Die To-do-Liste mit der " +
+		"To-do-Liste eines anderen To-do Daemons synchronisieren",
+	"Write a snapshot of the to-do list to a file": "Eine Sicherung der To-do-Liste in eine Datei schreiben",
+	"Run the server as a service that starts at login": "Den Server als Dienst ausführen, der bei der " +
+		"Anmeldung startet",
+	"Install the server as a service that starts at login, and start it": "Den Server als Dienst " +
+		"installieren, der bei der Anmeldung startet, und ihn starten",
+	"Print whether the service is installed and running": "Ausgeben, ob der Dienst installiert ist und " +
+		"läuft",
+	"Stop the service until the next login": "Den Dienst bis zur nächsten Anmeldung beenden",
+	"Start the installed service":           "Den installierten Dienst starten",
+	"Stop the service and remove it":        "Den Dienst beenden und entfernen",
+
+	// Flags.
+	"a file with one task summary per line, or '-' for stdin": "eine Datei mit einem Aufgabentitel pro Zeile, " +
+		"oder '-' für die Standardeingabe",
+	"a more detailed description of the task":           "eine ausführlichere Beschreibung der Aufgabe",
+	"a request header allowed in cross-origin requests": "ein in Cross-Origin-Anfragen erlaubter Anfrage-Header",
+	"a tag of each task (can be repeated)":              "ein Schlagwort jeder Aufgabe (wiederholbar)",
+	"a tag of the task (can be repeated)":               "ein Schlagwort der Aufgabe (wiederholbar)",
+	"address of the To-do Daemon to synchronize with, i.e. its socket or the URL of its REST API": "Adresse " +
+		"des To-do Daemons, mit dem synchronisiert wird, d. h. sein Socket oder die URL seiner REST-API",
+	"add some demo tasks if the to-do list is empty": "einige Beispielaufgaben hinzufügen, wenn die To-do-Liste leer ist",
+	"address of the socket or named pipe":            "Adresse des Sockets oder der Named Pipe",
+	"add the task even if the server rejects duplicates of open tasks (see run --unique-summaries)": "die " +
+		"Aufgabe auch dann hinzufügen, wenn der Server Duplikate offener Aufgaben ablehnt (siehe run --unique-summaries)",
+	"also write the removed tasks to this file, in the format of backups": "die entfernten Aufgaben zusätzlich " +
+		"im Format von Sicherungen in diese Datei schreiben",
+	"forward standard input and output instead of accepting connections, e.g. for SSH": "Standardein- und " +
+		"-ausgabe weiterleiten, statt Verbindungen anzunehmen, z. B. für SSH",
+	"forward to a remote server instead of the local one: ssh://[user@]host[:port] or tcp://host:port": "an " +
+		"einen entfernten statt den lokalen Server weiterleiten: ssh://[user@]host[:port] oder tcp://host:port",
+	"the address to accept connections on: a loopback TCP address like localhost:9000, or a socket": "die " +
+		"Adresse, an der Verbindungen angenommen werden: eine Loopback-TCP-Adresse wie localhost:9000 oder ein Socket",
+	"the command that runs todo-daemon on the remote host": "der Befehl, der todo-daemon auf dem " +
+		"entfernten Host ausführt",
+	"an HTTP method allowed in cross-origin requests besides GET, HEAD, and POST": "eine neben GET, HEAD " +
+		"und POST in Cross-Origin-Anfragen erlaubte HTTP-Methode",
+	"an origin allowed to make cross-origin requests to the REST API, or * for all origins": "ein Origin, " +
+		"der Cross-Origin-Anfragen an die REST-API stellen darf, oder * für alle",
+	"apply the template at the start of each day selected by this rule, e.g. FREQ=WEEKLY;BYDAY=FR": "die " +
+		"Vorlage zu Beginn jedes durch diese Regel ausgewählten Tages anwenden, z. B. FREQ=WEEKLY;BYDAY=FR",
+	"download the snapshot from object storage, e.g. s3://bucket/key": "die Sicherung aus dem " +
+		"Objektspeicher herunterladen, z. B. s3://bucket/key",
+	"don't print the results of commands that modify the to-do list, e.g. for scripts": "die Ergebnisse " +
+		"von Befehlen, die die To-do-Liste ändern, nicht ausgeben, z. B. für Skripte",
+	"enable debugging features like gRPC server reflection": "Debugging-Funktionen wie gRPC Server " +
+		"Reflection aktivieren",
+	"how long to remember the tasks added by requests with an idempotency key (0 disables them)": "wie lange " +
+		"die von Anfragen mit Idempotenzschlüssel hinzugefügten Aufgaben gespeichert werden (0 deaktiviert sie)",
+	"how to print the changes (redraw or append)": "wie die Änderungen ausgegeben werden (redraw oder append)",
+	"list the encrypted snapshots in the object storage instead": "stattdessen die verschlüsselten " +
+		"Sicherungen im Objektspeicher auflisten",
+	"keep printing the messages logged afterwards until interrupted": "die danach geloggten Meldungen bis " +
+		"zum Abbruch weiter ausgeben",
+	"keep printing the changes to the tasks until interrupted": "die Änderungen an den Aufgaben bis zum " +
+		"Abbruch fortlaufend ausgeben",
+	"maximum number of tasks to print (0 means no limit)": "maximale Anzahl auszugebender Aufgaben " +
+		"(0 bedeutet keine Begrenzung)",
+	"maximum time to spend on a single request (0 means no limit)": "maximale Dauer einer einzelnen " +
+		"Anfrage (0 bedeutet keine Begrenzung)",
+	"maximum time to wait for active requests when stopping the server": "maximale Wartezeit auf aktive " +
+		"Anfragen beim Beenden des Servers",
+	"maximum time to wait for each response of the server (0 means no timeout)": "maximale Wartezeit auf " +
+		"jede Antwort des Servers (0 bedeutet kein Timeout)",
+	"minimum level of the messages to print (debug, info, warn, or error)": "minimale Stufe der " +
+		"auszugebenden Meldungen (debug, info, warn oder error)",
+	"minimum level of log messages (debug, info, warn, or error)": "minimale Stufe der Log-Meldungen " +
+		"(debug, info, warn oder error)",
+	"only print the tasks assigned to this user": "nur die diesem Benutzer zugewiesenen Aufgaben ausgeben",
+	"only print the starred tasks":               "nur die mit einem Stern markierten Aufgaben ausgeben",
+	"only print the tasks of this project":       "nur die Aufgaben dieses Projekts ausgeben",
+	"only print the tasks selected by this saved filter, see 'filters add'": "nur die von diesem " +
+		"gespeicherten Filter ausgewählten Aufgaben ausgeben, siehe 'filters add'",
+	"only print the tasks that are due (today, week, or overdue)": "nur die fälligen Aufgaben ausgeben " +
+		"(today, week oder overdue)",
+	"only print the tasks with this status (open or completed)": "nur die Aufgaben mit diesem Status " +
+		"ausgeben (open oder completed)",
+	"only print the tasks with this tag (can be repeated)": "nur die Aufgaben mit diesem Schlagwort " +
+		"ausgeben (wiederholbar)",
+	"only remove the tasks that were completed at least this long ago, e.g. 168h": "nur die Aufgaben " +
+		"entfernen, die vor mindestens dieser Zeit erledigt wurden, z. B. 168h",
+	"open the to-do list in-process instead of connecting to the server, which must not be running": "die " +
+		"To-do-Liste im Prozess öffnen, statt sich mit dem Server zu verbinden, der nicht laufen darf",
+	"path of the file holding the state of the synchronization with each peer": "Pfad der Datei mit dem " +
+		"Stand der Synchronisation mit jeder Gegenstelle",
+	"path to the lock file": "Pfad zur Sperrdatei",
+	"path to a file to append the log messages to, in addition to stderr": "Pfad zu einer Datei, an die " +
+		"die Log-Meldungen zusätzlich zu stderr angehängt werden",
+	"print the entire to-do list in manual order instead of just the moved task": "die gesamte To-do-Liste " +
+		"in manueller Reihenfolge statt nur der verschobenen Aufgabe ausgeben",
+	"print the entire to-do list instead of just the completed task": "die gesamte To-do-Liste statt nur " +
+		"der erledigten Aufgabe ausgeben",
+	"print the entire to-do list instead of just the created task": "die gesamte To-do-Liste statt nur " +
+		"der angelegten Aufgabe ausgeben",
+	"print the remaining to-do list instead of just the removed tasks": "die verbleibende To-do-Liste " +
+		"statt nur der entfernten Aufgaben ausgeben",
+	"print the tasks under headers by this field (tag, project, or due)": "die Aufgaben unter Überschriften " +
+		"nach diesem Feld ausgeben (tag, project oder due)",
+	"queue the task if the server is not running, and add it once the server is reachable": "die Aufgabe " +
+		"vormerken, wenn der Server nicht läuft, und sie hinzufügen, sobald er erreichbar ist",
+	"read the template from this JSON file instead": "die Vorlage stattdessen aus dieser JSON-Datei lesen",
+	"reject all requests that would modify the to-do list": "alle Anfragen ablehnen, die die To-do-Liste " +
+		"ändern würden",
+	"reject adding tasks whose summary equals that of an open task, ignoring case": "das Hinzufügen von " +
+		"Aufgaben ablehnen, deren Titel dem einer offenen Aufgabe gleicht, ohne Beachtung der Groß-/Kleinschreibung",
+	"reject completing tasks that depend on open tasks": "das Erledigen von Aufgaben ablehnen, die von " +
+		"offenen Aufgaben abhängen",
+	"repair a corrupt event log of a stopped server, keeping the original next to it": "ein beschädigtes " +
+		"Ereignisprotokoll eines gestoppten Servers reparieren und das Original daneben behalten",
+	"remove the tasks without asking for confirmation": "die Aufgaben ohne Rückfrage entfernen",
+	"select the starred tasks":                         "die mit einem Stern markierten Aufgaben auswählen",
+	"select the tasks of this project":                 "die Aufgaben dieses Projekts auswählen",
+	"select the tasks that are due (today, week, or overdue)": "die fälligen Aufgaben auswählen " +
+		"(today, week oder overdue)",
+	"select the tasks with this status (open or completed)": "die Aufgaben mit diesem Status auswählen " +
+		"(open oder completed)",
+	"select the tasks with this tag (can be repeated)": "die Aufgaben mit diesem Schlagwort auswählen " +
+		"(wiederholbar)",
+	"serve the web UI at /ui/ on the HTTP server": "die Weboberfläche unter /ui/ auf dem HTTP-Server bereitstellen",
+	"sort the tasks in descending order":          "die Aufgaben absteigend sortieren",
+	"star each task":                              "jede Aufgabe mit einem Stern markieren",
+	"take over the sockets and tasks of the running server, which stops once its requests are finished": "die " +
+		"Sockets und Aufgaben des laufenden Servers übernehmen, der sich nach seinen Anfragen beendet",
+	"the IANA time zone to interpret and print times in, e.g. Europe/Berlin (default: local)": "die " +
+		"IANA-Zeitzone, in der Zeiten gelesen und ausgegeben werden, z. B. Europe/Berlin (Standard: lokal)",
+	"the ID or short code of a task that must be completed first": "die ID oder der Kurzcode einer Aufgabe, " +
+		"die zuerst erledigt werden muss",
+	"the ID or short code of the task to move the task after": "die ID oder der Kurzcode der Aufgabe, " +
+		"hinter die die Aufgabe verschoben wird",
+	"the ID or short code of the task to move the task before": "die ID oder der Kurzcode der Aufgabe, " +
+		"vor die die Aufgabe verschoben wird",
+	"the URL of a related web page, e.g. a ticket (can be repeated)": "die URL einer zugehörigen Webseite, " +
+		"z. B. eines Tickets (wiederholbar)",
+	"the URL that clients use to reach the HTTP server, e.g. behind a reverse proxy": "die URL, unter der " +
+		"Clients den HTTP-Server erreichen, z. B. hinter einem Reverse Proxy",
+	"the address of the HTTP server (host:port, unix:///path, or off)": "die Adresse des HTTP-Servers " +
+		"(host:port, unix:///pfad oder off)",
+	"the data source name of the database for storing the tasks, e.g. memory or eventlog:<path>": "der " +
+		"Data Source Name der Datenbank für die Aufgaben, z. B. memory oder eventlog:<pfad>",
+	"the due time, e.g. '2006-01-02 15:04', 'next friday 5pm', 'in 3 days', or 'every monday'": "die " +
+		"Fälligkeit, z. B. '2006-01-02 15:04', 'nächsten Freitag 17 Uhr', 'in 3 Tagen' oder 'jeden Montag'",
+	"the field to sort the tasks by (created, due, updated, or manual)": "das Feld, nach dem die Aufgaben " +
+		"sortiert werden (created, due, updated oder manual)",
+	"the maximum number of tasks to print": "die maximale Anzahl auszugebender Aufgaben",
+	"the number of copies to create":       "die Anzahl anzulegender Kopien",
+	"the number of the link to open, counting from 1 in the order shown by 'tasks show'": "die Nummer des " +
+		"zu öffnenden Links, gezählt ab 1 in der von 'tasks show' angezeigten Reihenfolge",
+	"the name or ID of the group whose members may connect to the Unix socket": "der Name oder die ID der " +
+		"Gruppe, deren Mitglieder sich mit dem Unix-Socket verbinden dürfen",
+	"the number of times to retry adding a task if the server is unavailable or doesn't respond in time": "wie " +
+		"oft das Hinzufügen einer Aufgabe wiederholt wird, wenn der Server nicht erreichbar ist oder nicht " +
+		"rechtzeitig antwortet",
+	"the number of recent messages to print (0 means all messages kept by the server)": "die Anzahl " +
+		"auszugebender letzter Meldungen (0 bedeutet alle vom Server vorgehaltenen Meldungen)",
+	"the number of tasks to skip, e.g. to print the next page": "die Anzahl zu überspringender Aufgaben, " +
+		"z. B. für die nächste Seite",
+	"the maximum size of the gRPC messages that the server receives, in bytes": "die maximale Größe der " +
+		"gRPC-Nachrichten, die der Server empfängt, in Bytes",
+	"the maximum size of the gRPC messages that the server sends, in bytes": "die maximale Größe der " +
+		"gRPC-Nachrichten, die der Server sendet, in Bytes",
+	"the number of events buffered for each client watching the tasks": "die Anzahl der Ereignisse, die " +
+		"für jeden Client gepuffert werden, der die Aufgaben beobachtet",
+	"the octal file mode of the Unix socket, e.g. 0660 (default 0600, or 0660 with --socket-group)": "die " +
+		"oktalen Dateirechte des Unix-Sockets, z. B. 0660 (Standard: 0600, oder 0660 mit --socket-group)",
+	"the output format (text, json, or porcelain)": "das Ausgabeformat (text, json oder porcelain)",
+	"the output format (text or porcelain)":        "das Ausgabeformat (text oder porcelain)",
+	"short for --format json":                      "Kurzform von --format json",
+	"short for --format porcelain":                 "Kurzform von --format porcelain",
+	"the profile, which namespaces the lock file, socket, data, and configuration": "das Profil, das " +
+		"Sperrdatei, Socket, Daten und Konfiguration trennt",
+	"the project of each task":         "das Projekt jeder Aufgabe",
+	"the project the task belongs to":  "das Projekt, zu dem die Aufgabe gehört",
+	"the user the task is assigned to": "der Benutzer, dem die Aufgabe zugewiesen ist",
+	"the text to append to the summary of each copy, e.g. ' (copy)'": "der Text, der an den Titel " +
+		"jeder Kopie angehängt wird, z. B. ' (Kopie)'",
+	"the time to shift the due time of each copy by, relative to the previous one, e.g. 168h": "die Zeit, " +
+		"um die die Fälligkeit jeder Kopie gegenüber der vorherigen verschoben wird, z. B. 168h",
+	"the summary of a task of the template (can be repeated)": "der Titel einer Aufgabe der Vorlage " +
+		"(wiederholbar)",
+	"the time between creating each task and its due time, e.g. 48h": "die Zeit zwischen dem Anlegen " +
+		"jeder Aufgabe und ihrer Fälligkeit, z. B. 48h",
+	"unblock the task from the specified tasks instead": "die Aufgabe stattdessen von den angegebenen " +
+		"Aufgaben lösen",
+	"the language of the CLI output (default: from LC_ALL, LC_MESSAGES, or LANG)": "die Sprache der " +
+		"CLI-Ausgabe (Standard: aus LC_ALL, LC_MESSAGES oder LANG)",
+	"what to do with clients watching the tasks that fall behind (drop-oldest or disconnect)": "was mit " +
+		"Clients geschieht, die die Aufgaben beobachten und zurückfallen (drop-oldest oder disconnect)",
+	"compress the messages to and from the server with gzip, e.g. for a remote server": "die Nachrichten " +
+		"an den und vom Server mit gzip komprimieren, z. B. für einen entfernten Server",
+	"when to color the output: auto (if it is a terminal and NO_COLOR is unset), always, or never": "wann " +
+		"die Ausgabe eingefärbt wird: auto (wenn sie ein Terminal ist und NO_COLOR nicht gesetzt ist), " +
+		"always oder never",
+	"only install the service, don't start it now": "den Dienst nur installieren, nicht jetzt starten",
+	"the IP address or network of a reverse proxy whose X-Forwarded-* headers are honored, or unix": "die " +
+		"IP-Adresse oder das Netz eines Reverse-Proxys, dessen X-Forwarded-*-Header beachtet werden, oder unix",
+
+	// Output.
+	"(due %s)":               "(fällig %s)",
+	"(blocked)":              "(blockiert)",
+	"ID":                     "ID",
+	"Short code":             "Kurzcode",
+	"Summary":                "Titel",
+	"Description":            "Beschreibung",
+	"Project":                "Projekt",
+	"Tags":                   "Schlagwörter",
+	"Depends on":             "Hängt ab von",
+	"Status":                 "Status",
+	"Starred":                "Stern",
+	"Assignee":               "Zugewiesen an",
+	"Links":                  "Links",
+	"Created":                "Angelegt",
+	"Updated":                "Geändert",
+	"Completed":              "Erledigt",
+	"Due":                    "Fällig",
+	"Recurrence":             "Wiederholung",
+	"Time zone":              "Zeitzone",
+	"Version":                "Version",
+	"completed":              "erledigt",
+	"overdue":                "überfällig",
+	"overdue, blocked by %s": "überfällig, blockiert durch %s",
+	"blocked by %s":          "blockiert durch %s",
+	"open":                   "offen",
+	"yes":                    "ja",
+	"no":                     "nein",
+	"created":                "angelegt",
+	"updated":                "geändert",
+	"changed":                "geändert",
+	"assigned":               "zugewiesen",
+	"deleted":                "gelöscht",
+	"PID":                    "PID",
+	"Min. CLI version":       "Min. CLI-Version",
+	"Uptime":                 "Laufzeit",
+	"Socket":                 "Socket",
+	"HTTP address":           "HTTP-Adresse",
+	"API base URL":           "API-Basis-URL",
+	"Storage":                "Speicher",
+	"Tasks":                  "Aufgaben",
+	"Subscribers":            "Abonnenten",
+	"Dropped events":         "Verworfene Ereignisse",
+	"Disconnections":         "Trennungen",
+	"Open":                   "Offen",
+	"Overdue":                "Überfällig",
+	"Completed today":        "Heute erledigt",
+	"Completed this week":    "Diese Woche erledigt",
+	"Avg. completion time":   "Mittlere Bearbeitungszeit",
+	"Projects":               "Projekte",
+	"%d open":                "%d offen",
+	"%d completed":           "%d erledigt",
+	"Today":                  "Heute",
+	"This week":              "Diese Woche",
+	"Later":                  "Später",
+	"No due date":            "Ohne Fälligkeit",
+	"No tag":                 "Ohne Schlagwort",
+	"No project":             "Ohne Projekt",
+	"NAME":                   "NAME",
+	"INTERVAL":               "INTERVALL",
+	"RUNS":                   "LÄUFE",
+	"FAILURES":               "FEHLER",
+	"LAST RUN":               "LETZTER LAUF",
+	"DURATION":               "DAUER",
+	"NEXT RUN":               "NÄCHSTER LAUF",
+	"LAST ERROR":             "LETZTER FEHLER",
+	"running":                "läuft",
+
+	// Messages of commands.
+	"Remove this task? [y/N] ":      "Diese Aufgabe entfernen? [j/N] ",
+	"Remove these %d tasks? [y/N] ": "Diese %d Aufgaben entfernen? [j/N] ",
+	"y":                             "j",
+	"No completed tasks to remove":  "Keine erledigten Aufgaben zum Entfernen",
+	"Archived %d tasks to %s":       "%d Aufgaben in %s archiviert",
+	"Added %d queued tasks:":        "%d vorgemerkte Aufgaben hinzugefügt:",
+	"No queued tasks":               "Keine vorgemerkten Aufgaben",
+	"Cannot add the task '%s' queued at %s: %s": "Die am %[2]s vorgemerkte Aufgabe '%[1]s' kann nicht " +
+		"hinzugefügt werden: %[3]s",
+	"The server is not running; queued %d tasks to be added once it is reachable": "Der Server läuft nicht; " +
+		"%d Aufgaben wurden vorgemerkt und werden hinzugefügt, sobald er erreichbar ist",
+	"Configuration unchanged":              "Konfiguration unverändert",
+	"Applied: %s":                          "Übernommen: %s",
+	"Requires restart: %s":                 "Neustart erforderlich: %s",
+	"Backed up %d tasks to %s":             "%d Aufgaben in %s gesichert",
+	"Restored %d tasks from %s":            "%d Aufgaben aus %s wiederhergestellt",
+	"Saved template '%s' with %d tasks":    "Vorlage '%s' mit %d Aufgaben gespeichert",
+	"It is applied next at %s":             "Sie wird als Nächstes am %s angewendet",
+	"Created %d tasks from template '%s':": "%d Aufgaben aus der Vorlage '%s' angelegt:",
+	"Removed template '%s'":                "Vorlage '%s' entfernt",
+	"No templates":                         "Keine Vorlagen",
+	"Saved filter '%s'; list its tasks with 'tasks list --filter %s'": "Filter '%s' gespeichert; seine " +
+		"Aufgaben listet 'tasks list --filter %s' auf",
+	"Removed filter '%s'": "Filter '%s' entfernt",
+	"Opened %s":           "%s geöffnet",
+	"No filters":          "Keine Filter",
+	"No backups":          "Keine Sicherungen",
+	"Synchronized with %s: %d tasks sent, %d tasks received": "Mit %s synchronisiert: %d Aufgaben gesendet, " +
+		"%d Aufgaben empfangen",
+	"The server is already running (PID %d), so the service was not started; stop the server and run " +
+		"'todo-daemon service start'": "Der Server läuft bereits (PID %d), daher wurde der Dienst nicht " +
+		"gestartet; beenden Sie den Server und führen Sie 'todo-daemon service start' aus",
+	"systemd user unit":                        "systemd-Benutzer-Unit",
+	"launchd agent":                            "launchd-Agent",
+	"Windows service":                          "Windows-Dienst",
+	"Installed the %s '%s'":                    "%s '%s' installiert",
+	"Uninstalled the %s '%s'":                  "%s '%s' entfernt",
+	"Started the %s '%s'":                      "%s '%s' gestartet",
+	"Stopped the %s '%s'":                      "%s '%s' beendet",
+	"The %s '%s' is running (PID %d)":          "%s '%s' läuft (PID %d)",
+	"The %s '%s' is installed but not running": "%s '%s' ist installiert, läuft aber nicht",
+	"The %s '%s' is not installed":             "%s '%s' ist nicht installiert",
+	"Password of %s: ":                         "Passwort von %s: ",
+
+	// Errors, each of them a part of a chain of messages separated by ": ".
+	"todo-daemon server is not running; start it with 'todo-daemon run'": "der todo-daemon-Server läuft " +
+		"nicht; starten Sie ihn mit 'todo-daemon run'",
+	"another instance is already running":         "eine andere Instanz läuft bereits",
+	"the to-do list is in use by another process": "die To-do-Liste wird von einem anderen Prozess verwendet",
+	"removal canceled, no tasks were removed":     "Entfernen abgebrochen, keine Aufgaben wurden entfernt",
+	"no task ID specified":                        "keine Aufgaben-ID angegeben",
+	"no user specified":                           "kein Benutzer angegeben",
+	"no template name specified":                  "kein Vorlagenname angegeben",
+	"no filter name specified":                    "kein Filtername angegeben",
+	"no search query specified":                   "keine Suchanfrage angegeben",
+	"no backup file specified":                    "keine Sicherungsdatei angegeben",
+	"no task summaries to add":                    "keine Aufgabentitel zum Hinzufügen",
+	"task has no links":                           "Aufgabe hat keine Links",
+	"no blocking task specified, use --on":        "keine blockierende Aufgabe angegeben, verwenden Sie --on",
+	"no tasks specified, use --task or --file":    "keine Aufgaben angegeben, verwenden Sie --task oder --file",
+	"invalid command":                             "ungültiger Befehl",
+	"invalid log level":                           "ungültige Log-Stufe",
+	"invalid time zone":                           "ungültige Zeitzone",
+	"invalid due time":                            "ungültige Fälligkeit",
+	"invalid output format":                       "ungültiges Ausgabeformat",
+	"cannot create task":                          "Aufgabe kann nicht angelegt werden",
+	"cannot create tasks":                         "Aufgaben können nicht angelegt werden",
+	"cannot retrieve tasks":                       "Aufgaben können nicht abgerufen werden",
+	"cannot complete task":                        "Aufgabe kann nicht erledigt werden",
+	"cannot delete task":                          "Aufgabe kann nicht gelöscht werden",
+	"cannot restore task":                         "Aufgabe kann nicht wiederhergestellt werden",
+	"cannot duplicate task":                       "Aufgabe kann nicht kopiert werden",
+	"cannot move task":                            "Aufgabe kann nicht verschoben werden",
+	"cannot block task":                           "Aufgabe kann nicht blockiert werden",
+	"cannot star task":                            "Aufgabe kann nicht mit einem Stern markiert werden",
+	"cannot unstar task":                          "Stern kann nicht von der Aufgabe entfernt werden",
+	"cannot assign task":                          "Aufgabe kann nicht zugewiesen werden",
+	"cannot unassign task":                        "Zuweisung der Aufgabe kann nicht aufgehoben werden",
+	"cannot change assignee":                      "Zuweisung kann nicht geändert werden",
+	"cannot open link":                            "Link kann nicht geöffnet werden",
+	"cannot watch tasks":                          "Aufgaben können nicht beobachtet werden",
+	"cannot remove completed tasks":               "erledigte Aufgaben können nicht entfernt werden",
+	"cannot start server":                         "Server kann nicht gestartet werden",
+	"cannot write backup":                         "Sicherung kann nicht geschrieben werden",
+	"cannot read backup":                          "Sicherung kann nicht gelesen werden",
+	"cannot list backups":                         "Sicherungen können nicht aufgelistet werden",
+	"remote backups are not configured":           "entfernte Sicherungen sind nicht konfiguriert",
+	"no such task":                                "keine solche Aufgabe",
+	"no such filter":                              "kein solcher Filter",
+	"no such template":                            "keine solche Vorlage",
+	"rpc error":                                   "RPC-Fehler",
+	"cannot restore both a backup file and --from": "eine Sicherungsdatei und --from können nicht " +
+		"gleichzeitig wiederhergestellt werden",
+	"no passphrase for encrypting remote backups specified": "keine Passphrase zum Verschlüsseln " +
+		"entfernter Sicherungen angegeben",
+	"services are not supported on this operating system": "Dienste werden auf diesem Betriebssystem " +
+		"nicht unterstützt",
+	"cannot install service":               "Dienst kann nicht installiert werden",
+	"cannot uninstall service":             "Dienst kann nicht entfernt werden",
+	"cannot start service":                 "Dienst kann nicht gestartet werden",
+	"cannot stop service":                  "Dienst kann nicht beendet werden",
+	"cannot retrieve service status":       "Status des Dienstes kann nicht abgerufen werden",
+	"cannot read password":                 "Passwort kann nicht gelesen werden",
+	"cannot locate todo-daemon executable": "Programmdatei von todo-daemon kann nicht gefunden werden",
+	"service is not installed":             "Dienst ist nicht installiert",
+}
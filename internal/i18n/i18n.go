@@ -0,0 +1,134 @@
+// Package i18n translates the output of the To-do Daemon CLI, i.e. its help
+// text, the labels of its output, and its error messages, into the language of
+// the user.
+//
+// The messages are looked up in message catalogs by their English text, which
+// is used as is if there is no translation. The layouts of dates and times are
+// messages, too, e.g. "2006-01-02 15:04" becomes "02.01.2006 15:04" in German.
+package i18n
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// English is the language of the messages in the source code, which is used
+// if no other language is selected.
+const English = "en"
+
+// catalogs holds the translations of the messages by language. English needs
+// no catalog.
+var catalogs = map[string]map[string]string{
+	English: nil,
+	"de":    german,
+}
+
+// current is the catalog of the selected language.
+var current atomic.Pointer[map[string]string]
+
+// Languages returns the supported languages in ascending order.
+func Languages() []string {
+	return slices.Sorted(maps.Keys(catalogs))
+}
+
+// ParseLanguage returns the supported language that the specified POSIX
+// locale name or language tag refers to, e.g. "de" for "de_DE.UTF-8" or
+// "de-AT". The "C" and "POSIX" locales refer to English.
+func ParseLanguage(name string) (string, error) {
+	lang, _, _ := strings.Cut(strings.ToLower(name), ".")
+	lang, _, _ = strings.Cut(lang, "_")
+	lang, _, _ = strings.Cut(lang, "-")
+	switch lang {
+	case "c", "posix":
+		return English, nil
+	}
+	if _, ok := catalogs[lang]; !ok {
+		return "", fmt.Errorf("unsupported language '%s' (supported: %s)", name, strings.Join(Languages(), ", "))
+	}
+	return lang, nil
+}
+
+// EnvLanguage returns the language that the LC_ALL, LC_MESSAGES, and LANG
+// environment variables specify for messages, or English if they specify an
+// unsupported one.
+func EnvLanguage() string {
+	lang, err := ParseLanguage(envLocale("LC_MESSAGES"))
+	if err != nil {
+		return English
+	}
+	return lang
+}
+
+// SetLanguage selects the language that messages are translated into. An
+// unsupported language selects English.
+func SetLanguage(lang string) {
+	catalog := catalogs[lang]
+	current.Store(&catalog)
+}
+
+// Translate returns the translation of the specified message into the
+// selected language, or the message itself if there is none.
+func Translate(msg string) string {
+	if catalog := current.Load(); catalog != nil {
+		if s, ok := (*catalog)[msg]; ok {
+			return s
+		}
+	}
+	return msg
+}
+
+// Sprintf formats the translation of the specified format like
+// [fmt.Sprintf].
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(Translate(format), args...)
+}
+
+// Error returns the message of the specified error in the selected language.
+// Error messages are chains of messages separated by ": ", e.g. "cannot
+// delete task: no such task: '9'", so each message of the chain is translated
+// on its own. For errors returned by gRPC calls, the description of the status
+// is translated.
+func Error(err error) string {
+	parts := strings.Split(err.Error(), ": ")
+	for i, part := range parts {
+		if prefix, desc, ok := strings.Cut(part, " desc = "); ok {
+			parts[i] = prefix + " desc = " + Translate(desc)
+			continue
+		}
+		parts[i] = Translate(part)
+	}
+	return strings.Join(parts, ": ")
+}
+
+// UTF8 reports whether the LC_ALL, LC_CTYPE, and LANG environment variables
+// specify UTF-8 as the character encoding of the terminal. Without these
+// variables, UTF-8 is assumed, as in all current terminals; the "C" and
+// "POSIX" locales only support ASCII.
+func UTF8() bool {
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	locale := envLocale("LC_CTYPE")
+	if locale == "" {
+		return true
+	}
+	_, charset, _ := strings.Cut(strings.ToLower(locale), ".")
+	charset, _, _ = strings.Cut(charset, "@")
+	return charset == "utf-8" || charset == "utf8"
+}
+
+// envLocale returns the locale that the environment specifies for the
+// specified category, e.g. LC_MESSAGES.
+func envLocale(category string) string {
+	for _, name := range []string{"LC_ALL", category, "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
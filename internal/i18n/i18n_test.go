@@ -0,0 +1,138 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"en", English},
+		{"en_US.UTF-8", English},
+		{"C", English},
+		{"POSIX", English},
+		{"de", "de"},
+		{"de_DE.UTF-8", "de"},
+		{"de-AT", "de"},
+		{"DE_ch", "de"},
+	}
+	for _, tt := range tests {
+		got, err := ParseLanguage(tt.name)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: want %s; got: %s", tt.name, tt.want, got)
+		}
+	}
+	if _, err := ParseLanguage("fr_FR.UTF-8"); err == nil {
+		t.Error("want error for unsupported language")
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	if got, want := Languages(), []string{"de", "en"}; !slices.Equal(got, want) {
+		t.Errorf("want %v; got: %v", want, got)
+	}
+}
+
+func TestEnvLanguage(t *testing.T) {
+	tests := []struct {
+		lcAll, lcMessages, lang string
+		want                    string
+	}{
+		{"", "", "", English},
+		{"", "", "de_DE.UTF-8", "de"},
+		{"", "en_US.UTF-8", "de_DE.UTF-8", English},
+		{"de_DE.UTF-8", "en_US.UTF-8", "", "de"},
+		{"", "", "fr_FR.UTF-8", English},
+	}
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_MESSAGES", tt.lcMessages)
+		t.Setenv("LANG", tt.lang)
+		if got := EnvLanguage(); got != tt.want {
+			t.Errorf("LC_ALL=%q LC_MESSAGES=%q LANG=%q: want %s; got: %s",
+				tt.lcAll, tt.lcMessages, tt.lang, tt.want, got)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	t.Cleanup(func() { SetLanguage(English) })
+
+	SetLanguage(English)
+	if got := Translate("Due"); got != "Due" {
+		t.Errorf("want English message; got: %s", got)
+	}
+	SetLanguage("de")
+	if got, want := Translate("Due"), "Fällig"; got != want {
+		t.Errorf("want %s; got: %s", want, got)
+	}
+	if got, want := Translate("untranslated"), "untranslated"; got != want {
+		t.Errorf("want %s; got: %s", want, got)
+	}
+	if got, want := Sprintf("%d open", 3), "3 offen"; got != want {
+		t.Errorf("want %s; got: %s", want, got)
+	}
+}
+
+func TestError(t *testing.T) {
+	t.Cleanup(func() { SetLanguage(English) })
+	SetLanguage("de")
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{
+			fmt.Errorf("cannot delete task: %w", errors.New("no such task: '9'")),
+			"Aufgabe kann nicht gelöscht werden: keine solche Aufgabe: '9'",
+		},
+		{
+			fmt.Errorf("cannot delete task: %w",
+				errors.New("rpc error: code = NotFound desc = no such task: '9'")),
+			"Aufgabe kann nicht gelöscht werden: RPC-Fehler: code = NotFound desc = keine solche Aufgabe: '9'",
+		},
+		{errors.New("something else"), "something else"},
+	}
+	for _, tt := range tests {
+		if got := Error(tt.err); got != tt.want {
+			t.Errorf("want %q; got: %q", tt.want, got)
+		}
+	}
+}
+
+func TestUTF8(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the console of Windows always supports UTF-8")
+	}
+	tests := []struct {
+		lcAll, lcCtype, lang string
+		want                 bool
+	}{
+		{"", "", "", true},
+		{"", "", "de_DE.UTF-8", true},
+		{"", "", "en_US.utf8", true},
+		{"", "", "C", false},
+		{"C", "", "en_US.UTF-8", false},
+		{"", "en_US.ISO-8859-1", "en_US.UTF-8", false},
+		{"", "", "de_DE.UTF-8@euro", true},
+	}
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_CTYPE", tt.lcCtype)
+		t.Setenv("LANG", tt.lang)
+		if got := UTF8(); got != tt.want {
+			t.Errorf("LC_ALL=%q LC_CTYPE=%q LANG=%q: want %t; got: %t",
+				tt.lcAll, tt.lcCtype, tt.lang, tt.want, got)
+		}
+	}
+}
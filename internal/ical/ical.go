@@ -0,0 +1,157 @@
+// Package ical provides an encoder for iCalendar data as specified in RFC 5545,
+// limited to the VTODO components needed for publishing the to-do list.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// ContentType is the MIME type of iCalendar data.
+	ContentType = "text/calendar; charset=utf-8"
+	// maxLineLength is the maximum length of a content line in octets,
+	// excluding the line break.
+	maxLineLength = 75
+	// dateTimeFormat is the format of DATE-TIME values in UTC.
+	dateTimeFormat = "20060102T150405Z"
+)
+
+// Status is the status of a to-do.
+type Status string
+
+const (
+	// StatusNeedsAction indicates that the to-do needs action.
+	StatusNeedsAction Status = "NEEDS-ACTION"
+	// StatusCompleted indicates that the to-do was completed.
+	StatusCompleted Status = "COMPLETED"
+)
+
+// Todo is a VTODO calendar component. Zero times are omitted.
+type Todo struct {
+	UID          string
+	Summary      string
+	Description  string
+	Status       Status
+	Created      time.Time
+	LastModified time.Time
+	Due          time.Time
+	Completed    time.Time
+}
+
+// Calendar is a VCALENDAR object containing to-dos.
+type Calendar struct {
+	// ProductID identifies the product that created the calendar.
+	ProductID string
+	// Name is the display name of the calendar.
+	Name string
+	// Todos are the to-dos in the calendar.
+	Todos []Todo
+}
+
+// Encoder writes iCalendar objects to an output stream.
+type Encoder struct {
+	w   *bufio.Writer
+	err error
+	now func() time.Time
+}
+
+// NewEncoder creates an [Encoder] that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		w:   bufio.NewWriter(w),
+		now: time.Now,
+	}
+}
+
+// Encode writes the specified calendar to the output stream. All times are
+// converted to UTC, so that the output doesn't depend on time zone
+// definitions.
+func (e *Encoder) Encode(cal *Calendar) error {
+	stamp := e.now()
+	e.line("BEGIN", "VCALENDAR")
+	e.line("VERSION", "2.0")
+	e.line("PRODID", cal.ProductID)
+	e.line("CALSCALE", "GREGORIAN")
+	if cal.Name != "" {
+		e.line("X-WR-CALNAME", escapeText(cal.Name))
+	}
+	for i := range cal.Todos {
+		e.todo(&cal.Todos[i], stamp)
+	}
+	e.line("END", "VCALENDAR")
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+func (e *Encoder) todo(t *Todo, stamp time.Time) {
+	e.line("BEGIN", "VTODO")
+	e.line("UID", escapeText(t.UID))
+	e.line("DTSTAMP", formatTime(stamp))
+	e.timeLine("CREATED", t.Created)
+	e.timeLine("LAST-MODIFIED", t.LastModified)
+	e.line("SUMMARY", escapeText(t.Summary))
+	if t.Description != "" {
+		e.line("DESCRIPTION", escapeText(t.Description))
+	}
+	e.timeLine("DUE", t.Due)
+	if t.Status != "" {
+		e.line("STATUS", string(t.Status))
+	}
+	e.timeLine("COMPLETED", t.Completed)
+	e.line("END", "VTODO")
+}
+
+func (e *Encoder) timeLine(name string, t time.Time) {
+	if !t.IsZero() {
+		e.line(name, formatTime(t))
+	}
+}
+
+// line writes a content line, folding it as required by RFC 5545.
+func (e *Encoder) line(name, value string) {
+	line := name + ":" + value
+	limit := maxLineLength
+	for len(line) > limit {
+		// Don't split multi-byte UTF-8 sequences.
+		n := limit
+		for n > 0 && !utf8.RuneStart(line[n]) {
+			n--
+		}
+		e.write(line[:n] + "\r\n ")
+		line = line[n:]
+		// Continuation lines start with a space, which counts towards the
+		// maximum line length.
+		limit = maxLineLength - 1
+	}
+	e.write(line + "\r\n")
+}
+
+// write writes the specified string, unless a previous write failed. The first
+// error is returned by [Encoder.Encode].
+func (e *Encoder) write(s string) {
+	if e.err == nil {
+		_, e.err = e.w.WriteString(s)
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
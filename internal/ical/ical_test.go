@@ -0,0 +1,104 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncode(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+	cal := &Calendar{
+		ProductID: "-//test//EN",
+		Name:      "To-do list",
+		Todos: []Todo{
+			{
+				UID:     "1@test",
+				Summary: "Milk, eggs; bread",
+				Status:  StatusNeedsAction,
+				Created: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+				// Daylight saving time in Berlin (UTC+2).
+				Due: time.Date(2025, 7, 1, 18, 30, 0, 0, berlin),
+			},
+			{
+				UID:         "2@test",
+				Summary:     "Pay rent",
+				Description: "line 1\nline 2",
+				Status:      StatusCompleted,
+				Created:     time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+				// Standard time in New York (UTC-5), crossing the date line
+				// when converted to UTC.
+				Due:       time.Date(2025, 1, 31, 22, 0, 0, 0, newYork),
+				Completed: time.Date(2025, 1, 30, 12, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	buf := &bytes.Buffer{}
+	enc := NewEncoder(buf)
+	enc.now = func() time.Time {
+		return time.Date(2025, 2, 1, 0, 0, 0, 0, berlin)
+	}
+	if err := enc.Encode(cal); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//test//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:To-do list",
+		"BEGIN:VTODO",
+		"UID:1@test",
+		"DTSTAMP:20250131T230000Z",
+		"CREATED:20250101T090000Z",
+		`SUMMARY:Milk\, eggs\; bread`,
+		"DUE:20250701T163000Z",
+		"STATUS:NEEDS-ACTION",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:2@test",
+		"DTSTAMP:20250131T230000Z",
+		"CREATED:20250101T090000Z",
+		"SUMMARY:Pay rent",
+		`DESCRIPTION:line 1\nline 2`,
+		"DUE:20250201T030000Z",
+		"STATUS:COMPLETED",
+		"COMPLETED:20250130T120000Z",
+		"END:VTODO",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	if got := buf.String(); got != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestEncodeFoldsLongLines(t *testing.T) {
+	summary := strings.Repeat("ä", 100)
+	buf := &bytes.Buffer{}
+	if err := NewEncoder(buf).Encode(&Calendar{Todos: []Todo{{UID: "1", Summary: summary}}}); err != nil {
+		t.Fatal(err)
+	}
+	var unfolded strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > maxLineLength {
+			t.Errorf("line exceeds %d octets: %q", maxLineLength, line)
+		}
+		if strings.HasPrefix(line, " ") {
+			unfolded.WriteString(line[1:])
+			continue
+		}
+		unfolded.WriteString("\n" + line)
+	}
+	if !strings.Contains(unfolded.String(), "\nSUMMARY:"+summary+"\n") {
+		t.Errorf("folded summary doesn't unfold to the original: %q", unfolded.String())
+	}
+}
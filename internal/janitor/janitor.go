@@ -0,0 +1,211 @@
+// Package janitor implements the scheduler of the periodic background jobs of
+// the To-do Daemon server, e.g. the scheduled backups.
+//
+// Each [Job] runs in its own goroutine. The time between two runs is the job's
+// interval, randomly shortened or lengthened by up to the job's jitter, so
+// jobs with the same interval don't all run at the same time. Each run has a
+// deadline, after which its context is canceled.
+package janitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/logging"
+)
+
+// Job is a periodic background job.
+type Job struct {
+	// Name identifies the job, e.g. in the logs.
+	Name string
+	// Interval is the time between the end of a run and the start of the
+	// next run.
+	Interval time.Duration
+	// Jitter is the maximum amount of time that each interval is randomly
+	// shortened or lengthened by. It must be less than the interval.
+	Jitter time.Duration
+	// Timeout is the maximum duration of a run. If Timeout <= 0, the interval
+	// is used, so a run doesn't take longer than the pause until the next.
+	Timeout time.Duration
+	// Run performs the job's work. It should return once the context is
+	// canceled, i.e. when the run's deadline expires or the scheduler stops.
+	Run func(ctx context.Context) error
+}
+
+// Status holds the state and the metrics of a registered job.
+type Status struct {
+	// Name is the name of the job.
+	Name string
+	// Interval is the job's interval without jitter.
+	Interval time.Duration
+	// Running reports whether the job is running right now.
+	Running bool
+	// Runs is the number of finished runs.
+	Runs int
+	// Failures is the number of finished runs that returned an error.
+	Failures int
+	// TotalDuration is the total duration of all finished runs.
+	TotalDuration time.Duration
+	// LastRun is the time that the last run started at, or the zero time if
+	// the job hasn't run yet.
+	LastRun time.Time
+	// LastDuration is the duration of the last finished run.
+	LastDuration time.Duration
+	// LastError is the error returned by the last finished run, if any.
+	LastError error
+	// NextRun is the time that the next run is scheduled for, or the zero time
+	// if the job isn't scheduled, e.g. while it is running.
+	NextRun time.Time
+}
+
+// entry is a registered job along with its status.
+type entry struct {
+	job    Job
+	status Status
+}
+
+// Scheduler runs the registered jobs periodically. The zero value is an empty
+// scheduler ready to use.
+type Scheduler struct {
+	mu      sync.Mutex
+	entries []*entry
+	started bool
+}
+
+// Register adds the specified job to the scheduler. Jobs must be registered
+// before the scheduler is started.
+func (s *Scheduler) Register(job Job) error {
+	switch {
+	case job.Name == "":
+		return errors.New("job has no name")
+	case job.Interval <= 0:
+		return fmt.Errorf("invalid interval of job '%s': %s", job.Name, job.Interval)
+	case job.Jitter < 0 || job.Jitter >= job.Interval:
+		return fmt.Errorf("invalid jitter of job '%s': %s", job.Name, job.Jitter)
+	case job.Run == nil:
+		return fmt.Errorf("job '%s' has no Run function", job.Name)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return fmt.Errorf("cannot register job '%s': scheduler already started", job.Name)
+	}
+	for _, e := range s.entries {
+		if e.job.Name == job.Name {
+			return fmt.Errorf("job '%s' already registered", job.Name)
+		}
+	}
+	s.entries = append(s.entries, &entry{job: job, status: Status{Name: job.Name, Interval: job.Interval}})
+	return nil
+}
+
+// Run runs the registered jobs until the context is canceled, which also
+// cancels the running jobs. It returns once all jobs have returned.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	s.started = true
+	entries := slices.Clone(s.entries)
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, e := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, e)
+		}()
+	}
+	wg.Wait()
+}
+
+// loop runs the job of the specified entry after each interval until the
+// context is canceled.
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	for {
+		delay := e.job.Interval
+		if e.job.Jitter > 0 {
+			delay += time.Duration(rand.Int64N(int64(2*e.job.Jitter)+1)) - e.job.Jitter
+		}
+		s.mu.Lock()
+		e.status.NextRun = time.Now().Add(delay)
+		s.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.mu.Lock()
+			e.status.NextRun = time.Time{}
+			s.mu.Unlock()
+			return
+		case <-timer.C:
+		}
+		s.run(ctx, e)
+	}
+}
+
+// run runs the job of the specified entry once and records its metrics.
+func (s *Scheduler) run(ctx context.Context, e *entry) {
+	timeout := e.job.Timeout
+	if timeout <= 0 {
+		timeout = e.job.Interval
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	s.mu.Lock()
+	e.status.Running = true
+	e.status.LastRun = start
+	e.status.NextRun = time.Time{}
+	s.mu.Unlock()
+
+	err := e.job.Run(ctx)
+	duration := time.Since(start)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("deadline of %s exceeded: %w", timeout, err)
+	}
+
+	s.mu.Lock()
+	e.status.Running = false
+	e.status.Runs++
+	e.status.TotalDuration += duration
+	e.status.LastDuration = duration
+	e.status.LastError = err
+	if err != nil {
+		e.status.Failures++
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		logger().Error("background job failed", "job", e.job.Name, "duration", duration, "cause", err)
+		return
+	}
+	logger().Debug("background job finished", "job", e.job.Name, "duration", duration)
+}
+
+// Jobs returns the status of the registered jobs, sorted by name.
+func (s *Scheduler) Jobs() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]Status, 0, len(s.entries))
+	for _, e := range s.entries {
+		jobs = append(jobs, e.status)
+	}
+	slices.SortFunc(jobs, func(a, b Status) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return jobs
+}
+
+// logger returns the logger of the messages about the background jobs.
+func logger() *slog.Logger {
+	return logging.Component(logging.ComponentJanitor)
+}
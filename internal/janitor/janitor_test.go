@@ -0,0 +1,110 @@
+package janitor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRegister(t *testing.T) {
+	run := func(context.Context) error { return nil }
+	var s Scheduler
+	if err := s.Register(Job{Name: "purge", Interval: time.Hour, Jitter: time.Minute, Run: run}); err != nil {
+		t.Fatal(err)
+	}
+	invalid := map[string]Job{
+		"no name":         {Interval: time.Hour, Run: run},
+		"no interval":     {Name: "a", Run: run},
+		"too much jitter": {Name: "b", Interval: time.Minute, Jitter: time.Minute, Run: run},
+		"no run":          {Name: "c", Interval: time.Hour},
+		"duplicate":       {Name: "purge", Interval: time.Hour, Run: run},
+	}
+	for name, job := range invalid {
+		if err := s.Register(job); err == nil {
+			t.Errorf("%s: want error", name)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		runs int
+	)
+	errBroken := errors.New("broken")
+	var s Scheduler
+	jobs := []Job{
+		{
+			Name:     "count",
+			Interval: 10 * time.Millisecond,
+			Jitter:   5 * time.Millisecond,
+			Run: func(context.Context) error {
+				mu.Lock()
+				defer mu.Unlock()
+				runs++
+				return nil
+			},
+		},
+		{
+			Name:     "fail",
+			Interval: 10 * time.Millisecond,
+			Run:      func(context.Context) error { return errBroken },
+		},
+		{
+			Name:     "hang",
+			Interval: 10 * time.Millisecond,
+			Timeout:  20 * time.Millisecond,
+			Run: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		},
+	}
+	for _, job := range jobs {
+		if err := s.Register(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	time.Sleep(150 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("want scheduler to stop once the context is canceled")
+	}
+
+	if err := s.Register(Job{Name: "late", Interval: time.Hour, Run: jobs[0].Run}); err == nil {
+		t.Error("want error for job registered after start")
+	}
+
+	status := s.Jobs()
+	if len(status) != 3 || status[0].Name != "count" || status[1].Name != "fail" || status[2].Name != "hang" {
+		t.Fatalf("want jobs sorted by name; got: %v", status)
+	}
+	count, fail, hang := status[0], status[1], status[2]
+	mu.Lock()
+	if count.Runs == 0 || count.Runs != runs || count.Failures != 0 || count.LastError != nil {
+		t.Errorf("want %d successful runs; got: %+v", runs, count)
+	}
+	mu.Unlock()
+	if fail.Runs == 0 || fail.Failures != fail.Runs || !errors.Is(fail.LastError, errBroken) {
+		t.Errorf("want only failed runs; got: %+v", fail)
+	}
+	if hang.Runs == 0 || !errors.Is(hang.LastError, context.DeadlineExceeded) && !errors.Is(hang.LastError, context.Canceled) {
+		t.Errorf("want runs canceled at their deadline; got: %+v", hang)
+	}
+	for _, st := range status {
+		if st.Running || !st.NextRun.IsZero() || st.LastRun.IsZero() {
+			t.Errorf("want stopped job with a last run; got: %+v", st)
+		}
+	}
+}
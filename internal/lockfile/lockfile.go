@@ -0,0 +1,111 @@
+// Package lockfile implements the lock file of the To-do Daemon server, which
+// ensures that only a single instance of the server is running.
+//
+// While holding the lock, the server records its PID in the lock file and
+// clears it when it releases the lock. A PID left in an unlocked lock file
+// therefore means that the previous server did not shut down cleanly.
+package lockfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// retryDelay is the time between two attempts to acquire a lock that is held.
+const retryDelay = 100 * time.Millisecond
+
+// Lock is a lock file that records the PID of its holder.
+type Lock struct {
+	flock *flock.Flock
+}
+
+// New creates a lock for the lock file at the specified path. The file is
+// created when the lock is acquired.
+func New(path string) *Lock {
+	return &Lock{flock: flock.New(path)}
+}
+
+// Path returns the path to the lock file.
+func (l *Lock) Path() string {
+	return l.flock.Path()
+}
+
+// TryLock tries to acquire the lock without blocking and reports whether it
+// succeeded. If so, it records the PID of the current process in the lock
+// file and returns the PID recorded by the previous holder, which is zero if
+// the previous holder released the lock properly.
+func (l *Lock) TryLock() (locked bool, prevPID int, err error) {
+	if err := os.MkdirAll(filepath.Dir(l.Path()), 0o700); err != nil {
+		return false, 0, err
+	}
+	locked, err = l.flock.TryLock()
+	if err != nil || !locked {
+		return false, 0, err
+	}
+	return true, l.recordPID(), nil
+}
+
+// LockContext acquires the lock like [Lock.TryLock], but waits until the lock
+// is released by its holder or the context is canceled.
+func (l *Lock) LockContext(ctx context.Context) (prevPID int, err error) {
+	if err := os.MkdirAll(filepath.Dir(l.Path()), 0o700); err != nil {
+		return 0, err
+	}
+	locked, err := l.flock.TryLockContext(ctx, retryDelay)
+	if err != nil {
+		return 0, err
+	}
+	if !locked {
+		return 0, ctx.Err()
+	}
+	return l.recordPID(), nil
+}
+
+// recordPID records the PID of the current process in the acquired lock file
+// and returns the PID recorded by the previous holder.
+func (l *Lock) recordPID() int {
+	// The previous holder is gone, so an unreadable PID is no reason to fail.
+	prevPID, _ := ReadPID(l.Path())
+	// Recording the PID is best-effort, e.g. on Windows, where the lock keeps
+	// other handles from writing to the file.
+	_ = os.WriteFile(l.Path(), []byte(strconv.Itoa(os.Getpid())+"\n"), 0o600)
+	return prevPID
+}
+
+// Unlock clears the recorded PID and releases the lock.
+func (l *Lock) Unlock() error {
+	// Clearing the PID is best-effort like recording it. The lock file is
+	// never removed, since another process might be waiting for the lock on
+	// the existing file.
+	_ = os.Truncate(l.Path(), 0)
+	return l.flock.Unlock()
+}
+
+// ReadPID returns the PID recorded in the lock file at the specified path, or
+// zero if the file is empty or does not exist.
+func ReadPID(path string) (int, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- the path is user-specified.
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	s := strings.TrimSpace(string(data))
+	if s == "" {
+		return 0, nil
+	}
+	pid, err := strconv.Atoi(s)
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("invalid PID in lock file %s: '%s'", path, s)
+	}
+	return pid, nil
+}
@@ -0,0 +1,113 @@
+package lockfile
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "todo-daemon.lock")
+	lock := New(path)
+	locked, prevPID, err := lock.TryLock()
+	if err != nil || !locked {
+		t.Fatalf("want lock to be acquired; got: %t, %v", locked, err)
+	}
+	if prevPID != 0 {
+		t.Errorf("want no previous PID; got: %d", prevPID)
+	}
+	if pid, err := ReadPID(path); err != nil || pid != os.Getpid() {
+		t.Errorf("want recorded PID: %d; got: %d, %v", os.Getpid(), pid, err)
+	}
+
+	if locked, _, err := New(path).TryLock(); err != nil || locked {
+		t.Errorf("want second lock to fail; got: %t, %v", locked, err)
+	}
+
+	if err := lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if pid, err := ReadPID(path); err != nil || pid != 0 {
+		t.Errorf("want PID to be cleared; got: %d, %v", pid, err)
+	}
+}
+
+func TestLockContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo-daemon.lock")
+	holder := New(path)
+	if locked, _, err := holder.TryLock(); err != nil || !locked {
+		t.Fatalf("want lock to be acquired; got: %t, %v", locked, err)
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := New(path).LockContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want waiting for held lock to time out; got: %v", err)
+	}
+
+	time.AfterFunc(200*time.Millisecond, func() {
+		if err := holder.Unlock(); err != nil {
+			t.Error(err)
+		}
+	})
+	lock := New(path)
+	if _, err := lock.LockContext(t.Context()); err != nil {
+		t.Fatalf("want lock to be acquired once released; got: %v", err)
+	}
+	if pid, err := ReadPID(path); err != nil || pid != os.Getpid() {
+		t.Errorf("want recorded PID: %d; got: %d, %v", os.Getpid(), pid, err)
+	}
+	if err := lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLockAfterCrash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo-daemon.lock")
+	// A server that crashed leaves its PID behind without holding the lock.
+	if err := os.WriteFile(path, []byte("4242\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	lock := New(path)
+	locked, prevPID, err := lock.TryLock()
+	if err != nil || !locked {
+		t.Fatalf("want lock to be acquired; got: %t, %v", locked, err)
+	}
+	defer func() {
+		if err := lock.Unlock(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if prevPID != 4242 {
+		t.Errorf("want previous PID: 4242; got: %d", prevPID)
+	}
+	if pid, err := ReadPID(path); err != nil || pid != os.Getpid() {
+		t.Errorf("want recorded PID: %d; got: %d, %v", os.Getpid(), pid, err)
+	}
+}
+
+func TestReadPID(t *testing.T) {
+	dir := t.TempDir()
+	if pid, err := ReadPID(filepath.Join(dir, "missing.lock")); err != nil || pid != 0 {
+		t.Errorf("want no PID for missing file; got: %d, %v", pid, err)
+	}
+	invalid := filepath.Join(dir, "invalid.lock")
+	if err := os.WriteFile(invalid, []byte("foo"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadPID(invalid); err == nil {
+		t.Error("want error for invalid PID")
+	}
+}
+
+func TestIsRunning(t *testing.T) {
+	if !IsRunning(os.Getpid()) {
+		t.Error("want current process to be running")
+	}
+	if IsRunning(0) {
+		t.Error("want PID 0 not to be running")
+	}
+}
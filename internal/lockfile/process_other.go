@@ -0,0 +1,19 @@
+//go:build !windows
+
+package lockfile
+
+import (
+	"errors"
+	"syscall"
+)
+
+// IsRunning checks if a process with the specified PID is running.
+func IsRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	// Signal 0 only checks whether the process exists. EPERM means that it
+	// exists, but belongs to another user.
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
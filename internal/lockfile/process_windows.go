@@ -0,0 +1,19 @@
+//go:build windows
+
+package lockfile
+
+import "os"
+
+// IsRunning checks if a process with the specified PID is running.
+func IsRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	// On Windows, finding a process fails if it doesn't exist.
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
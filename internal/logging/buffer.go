@@ -0,0 +1,181 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// subscriberBufferSize is the number of entries buffered for each subscriber
+// of a [Buffer]. Entries are dropped for subscribers that fall further behind.
+const subscriberBufferSize = 256
+
+// Entry is a log message kept in a [Buffer].
+type Entry struct {
+	// Time is the time the message was logged.
+	Time time.Time
+	// Level is the level of the message.
+	Level slog.Level
+	// Line is the message formatted like in the log output, without the
+	// trailing newline.
+	Line string
+}
+
+// Buffer is a ring buffer of the most recent log messages, which the server
+// keeps so that clients can tail its log without knowing where it goes.
+type Buffer struct {
+	mu sync.Mutex
+	// entries holds the messages in a ring, the oldest at index start.
+	entries []Entry
+	start   int
+	subs    map[chan Entry]struct{}
+}
+
+// NewBuffer creates a [Buffer] that keeps the specified number of messages.
+func NewBuffer(size int) *Buffer {
+	return &Buffer{
+		entries: make([]Entry, 0, max(size, 1)),
+		subs:    make(map[chan Entry]struct{}),
+	}
+}
+
+// add appends the specified entry, replacing the oldest one if the buffer is
+// full, and sends it to the subscribers without blocking.
+func (b *Buffer) add(e Entry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.entries) < cap(b.entries) {
+		b.entries = append(b.entries, e)
+	} else {
+		b.entries[b.start] = e
+		b.start = (b.start + 1) % len(b.entries)
+	}
+	for ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+			// Logging the dropped entry would add another one, so it is
+			// dropped silently.
+		}
+	}
+}
+
+// Recent returns the messages in the buffer, oldest first.
+func (b *Buffer) Recent() []Entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.recent()
+}
+
+// recent returns the messages in the buffer. The caller must hold the lock.
+func (b *Buffer) recent() []Entry {
+	entries := make([]Entry, 0, len(b.entries))
+	entries = append(entries, b.entries[b.start:]...)
+	return append(entries, b.entries[:b.start]...)
+}
+
+// Subscribe returns the messages in the buffer, oldest first, and a channel
+// receiving the messages logged afterwards, so that none are missed or
+// repeated in between. The returned function unsubscribes and closes the
+// channel.
+func (b *Buffer) Subscribe() ([]Entry, <-chan Entry, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ch := make(chan Entry, subscriberBufferSize)
+	b.subs[ch] = struct{}{}
+	var once sync.Once
+	return b.recent(), ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// bufferHandler is a [slog.Handler] that formats the log records as text and
+// adds them to a [Buffer].
+type bufferHandler struct {
+	slog.Handler
+	out *bufferWriter
+}
+
+// bufferWriter captures the text of a single log record, which is written by
+// a text handler while the lock is held, and adds it to a [Buffer].
+type bufferWriter struct {
+	mu   sync.Mutex
+	line bytes.Buffer
+	buf  *Buffer
+}
+
+func (w *bufferWriter) Write(p []byte) (int, error) {
+	return w.line.Write(p)
+}
+
+func newBufferHandler(buf *Buffer, opts *slog.HandlerOptions) *bufferHandler {
+	out := &bufferWriter{buf: buf}
+	return &bufferHandler{Handler: slog.NewTextHandler(out, opts), out: out}
+}
+
+func (h *bufferHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.out.mu.Lock()
+	defer h.out.mu.Unlock()
+	h.out.line.Reset()
+	if err := h.Handler.Handle(ctx, r); err != nil {
+		return err
+	}
+	h.out.buf.add(Entry{Time: r.Time, Level: r.Level, Line: strings.TrimSuffix(h.out.line.String(), "\n")})
+	return nil
+}
+
+func (h *bufferHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &bufferHandler{Handler: h.Handler.WithAttrs(attrs), out: h.out}
+}
+
+func (h *bufferHandler) WithGroup(name string) slog.Handler {
+	return &bufferHandler{Handler: h.Handler.WithGroup(name), out: h.out}
+}
+
+// multiHandler is a [slog.Handler] that passes the log records on to several
+// handlers.
+type multiHandler []slog.Handler
+
+func (h multiHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	for _, handler := range h {
+		if handler.Enabled(ctx, l) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, handler := range h {
+		if handler.Enabled(ctx, r.Level) {
+			errs = append(errs, handler.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(multiHandler, len(h))
+	for i, handler := range h {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (h multiHandler) WithGroup(name string) slog.Handler {
+	handlers := make(multiHandler, len(h))
+	for i, handler := range h {
+		handlers[i] = handler.WithGroup(name)
+	}
+	return handlers
+}
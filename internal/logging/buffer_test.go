@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestBuffer(t *testing.T) {
+	b := NewBuffer(3)
+	for i := range 5 {
+		b.add(Entry{Line: fmt.Sprint(i)})
+	}
+	var lines []string
+	for _, e := range b.Recent() {
+		lines = append(lines, e.Line)
+	}
+	if got := strings.Join(lines, " "); got != "2 3 4" {
+		t.Errorf("want the 3 most recent entries; got: %s", got)
+	}
+
+	recent, ch, unsubscribe := b.Subscribe()
+	if len(recent) != 3 {
+		t.Errorf("want 3 recent entries; got: %d", len(recent))
+	}
+	b.add(Entry{Line: "5"})
+	if e := <-ch; e.Line != "5" {
+		t.Errorf("want entry 5; got: %s", e.Line)
+	}
+	unsubscribe()
+	b.add(Entry{Line: "6"})
+	if _, ok := <-ch; ok {
+		t.Error("want channel to be closed after unsubscribing")
+	}
+}
+
+func TestInitBuffer(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() {
+		slog.SetDefault(defaultLogger)
+		SetLevel(slog.LevelInfo)
+	})
+	var out bytes.Buffer
+	b := NewBuffer(10)
+	Init(&out, slog.LevelInfo, b)
+
+	Component(ComponentJanitor).Debug("hidden")
+	Component(ComponentJanitor).Warn("disk almost full", "free", "1%")
+	entries := b.Recent()
+	if len(entries) != 1 {
+		t.Fatalf("want 1 entry; got: %v", entries)
+	}
+	e := entries[0]
+	if e.Level != slog.LevelWarn || e.Line != strings.TrimSuffix(out.String(), "\n") {
+		t.Errorf("want warning printed like in the log %q; got: %+v", out.String(), e)
+	}
+	if !strings.Contains(e.Line, "component=janitor") || !strings.Contains(e.Line, "free=1%") {
+		t.Errorf("want attributes in line: %s", e.Line)
+	}
+}
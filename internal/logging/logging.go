@@ -0,0 +1,148 @@
+// Package logging sets up the structured logging of the To-do Daemon.
+//
+// Every log message of a request carries the request's ID, as long as the
+// message is logged with the request's context, e.g. via [slog.InfoContext].
+// The servers also put a logger into the context of each request, see
+// [FromContext], which adds the peer and the method of the request as well.
+//
+// The messages of the server also carry the component that logged them, see
+// [Component], so they can be filtered, e.g. with grep 'component=janitor'.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/mwopitz/todo-daemon/internal/requestid"
+)
+
+// requestIDKey is the key of the request ID in log records.
+const requestIDKey = "request_id"
+
+// componentKey is the key of the component in log records.
+const componentKey = "component"
+
+// The components of the server, see [Component].
+const (
+	// ComponentServer logs the starting and stopping of the server.
+	ComponentServer = "server"
+	// ComponentGRPC logs the requests to the gRPC server.
+	ComponentGRPC = "grpc"
+	// ComponentHTTP logs the requests to the HTTP server.
+	ComponentHTTP = "http"
+	// ComponentRepo logs the storage of the tasks.
+	ComponentRepo = "repo"
+	// ComponentJanitor logs the background jobs.
+	ComponentJanitor = "janitor"
+	// ComponentWebhook logs the deliveries of webhooks.
+	ComponentWebhook = "webhook"
+	// ComponentHook logs the executions of hook scripts.
+	ComponentHook = "hook"
+	// ComponentBackup logs the scheduled backups.
+	ComponentBackup = "backup"
+)
+
+type contextKey struct{}
+
+// level is the minimum level of the log messages printed by the default
+// logger. It can be changed while the program is running.
+var level slog.LevelVar
+
+// Init makes a logger writing text to the specified writer the default logger
+// and sets the minimum level of the log messages to print. The printed
+// messages are also kept in the specified buffers, if any, e.g. for the
+// server's TailLogs RPC.
+func Init(w io.Writer, l slog.Level, buffers ...*Buffer) {
+	level.Set(l)
+	opts := &slog.HandlerOptions{Level: &level}
+	var handler slog.Handler = slog.NewTextHandler(w, opts)
+	if len(buffers) > 0 {
+		handlers := multiHandler{handler}
+		for _, buf := range buffers {
+			handlers = append(handlers, newBufferHandler(buf, opts))
+		}
+		handler = handlers
+	}
+	slog.SetDefault(slog.New(&contextHandler{Handler: handler}))
+}
+
+// SetLevel changes the minimum level of the log messages to print.
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
+
+// NewContext returns a copy of the specified context that holds the logger.
+func NewContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger held by the specified context, or the
+// default logger if there is none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// Component derives a logger from the default logger whose messages carry the
+// specified component, e.g. [ComponentJanitor]. It must be called after
+// [Init], since it captures the default logger at that time.
+func Component(name string) *slog.Logger {
+	return WithComponent(slog.Default(), name)
+}
+
+// WithComponent derives a logger from the specified logger whose messages
+// carry the specified component, like [Component] does for the default logger.
+func WithComponent(l *slog.Logger, name string) *slog.Logger {
+	return l.With(componentKey, name)
+}
+
+// NewRequestLogger derives a logger from the specified logger whose messages
+// carry the specified component, request ID, peer address, and method.
+func NewRequestLogger(l *slog.Logger, component, id, peer, method string) *slog.Logger {
+	return WithComponent(l, component).With(requestIDKey, id, "peer", peer, "method", method)
+}
+
+// Middleware returns a handler that puts a logger derived from the specified
+// logger into the context of each request, see [NewRequestLogger]. The
+// component is [ComponentHTTP], and the method is the HTTP method followed by
+// the path, e.g. "GET /api/v1/tasks". It must be wrapped by
+// [requestid.Middleware], which assigns the request IDs.
+func Middleware(next http.Handler, l *slog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l := NewRequestLogger(l, ComponentHTTP, requestid.FromContext(r.Context()), r.RemoteAddr,
+			r.Method+" "+r.URL.Path)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), l)))
+	})
+}
+
+// contextHandler is a [slog.Handler] that adds the request ID held by the
+// context to each log record, unless the logger carries it already.
+type contextHandler struct {
+	slog.Handler
+	// hasRequestID specifies whether the logger's attributes include the
+	// request ID, e.g. because it was created by [NewRequestLogger].
+	hasRequestID bool
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := requestid.FromContext(ctx); id != "" && !h.hasRequestID {
+		r.AddAttrs(slog.String(requestIDKey, id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	hasRequestID := h.hasRequestID
+	for _, a := range attrs {
+		hasRequestID = hasRequestID || a.Key == requestIDKey
+	}
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs), hasRequestID: hasRequestID}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name), hasRequestID: h.hasRequestID}
+}
@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mwopitz/todo-daemon/internal/requestid"
+)
+
+func TestContextHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(&contextHandler{Handler: slog.NewTextHandler(&buf, nil)}).With("component", "test")
+
+	ctx := requestid.NewContext(context.Background(), "abc123")
+	logger.InfoContext(ctx, "with request")
+	logger.Info("without request")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want 2 lines; got: %q", lines)
+	}
+	if !strings.Contains(lines[0], "component=test") || !strings.Contains(lines[0], "request_id=abc123") {
+		t.Errorf("want request ID in line: %s", lines[0])
+	}
+	if strings.Contains(lines[1], "request_id") {
+		t.Errorf("want no request ID in line: %s", lines[1])
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(&contextHandler{Handler: slog.NewTextHandler(&buf, nil)}))
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	handler := requestid.Middleware(Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).InfoContext(r.Context(), "handling request")
+	}), slog.Default()))
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil)
+	req.Header.Set(requestid.Header, "abc123")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := strings.TrimSpace(buf.String())
+	for _, want := range []string{
+		"component=http", "request_id=abc123", "peer=192.0.2.1:1234", `method="GET /api/v1/tasks"`,
+	} {
+		if !strings.Contains(line, want) {
+			t.Errorf("want %s in line: %s", want, line)
+		}
+	}
+	if n := strings.Count(line, "request_id="); n != 1 {
+		t.Errorf("want request ID once; got %d times in line: %s", n, line)
+	}
+}
+
+func TestFromContextDefault(t *testing.T) {
+	if got := FromContext(context.Background()); got != slog.Default() {
+		t.Errorf("want default logger; got: %v", got)
+	}
+}
+
+func TestComponent(t *testing.T) {
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(&contextHandler{Handler: slog.NewTextHandler(&buf, nil)}))
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	Component(ComponentJanitor).Info("background job finished")
+	NewRequestLogger(slog.Default(), ComponentGRPC, "abc123", "@", "/todo.v1.TodoService/ListTasks").Info("finished call")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want 2 lines; got: %q", lines)
+	}
+	if !strings.Contains(lines[0], "component=janitor") {
+		t.Errorf("want janitor component in line: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], "component=grpc") || !strings.Contains(lines[1], "request_id=abc123") {
+		t.Errorf("want gRPC component and request ID in line: %s", lines[1])
+	}
+}
@@ -0,0 +1,233 @@
+// Package proxy forwards raw connections to the socket of a To-do Daemon
+// server, so that a remote server can be reached through SSH without enabling
+// TCP on the server itself.
+//
+// The proxy doesn't interpret the gRPC traffic; it copies bytes between each
+// accepted connection and a new connection to its target. The target is
+// either the local socket of the server or a remote server, reached through a
+// TCP port, e.g. the local end of an SSH port forwarding, or through an SSH
+// command that runs the proxy in stdio mode on the remote host.
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+const (
+	// SchemeTCP is the URL scheme of remote servers reached through a TCP
+	// port, e.g. "tcp://localhost:9000".
+	SchemeTCP = "tcp"
+	// SchemeSSH is the URL scheme of remote servers reached through SSH, e.g.
+	// "ssh://user@host:22".
+	SchemeSSH = "ssh"
+)
+
+// DefaultRemoteCommand is the command that the SSH dialer runs on the remote
+// host, followed by "proxy --stdio".
+const DefaultRemoteCommand = "todo-daemon"
+
+// ErrNotLoopback is returned by [ListenTCP] for addresses that other hosts
+// can connect to, since the proxy bypasses the permissions of the socket.
+var ErrNotLoopback = errors.New("only loopback addresses are allowed")
+
+// Dialer opens a new connection to the target of the proxy.
+type Dialer func(ctx context.Context) (io.ReadWriteCloser, error)
+
+// ListenTCP creates a TCP listener for the specified address, which must be
+// a loopback address like "localhost:9000" or "127.0.0.1:9000".
+func ListenTCP(address string) (net.Listener, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if !isLoopback(host) {
+		return nil, fmt.Errorf("cannot listen on %s: %w", address, ErrNotLoopback)
+	}
+	return net.Listen("tcp", address)
+}
+
+// isLoopback checks if the specified host only refers to the local host.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// IsTCPAddress checks if the specified listen address is a TCP address like
+// "localhost:9000", as opposed to a socket address like
+// "unix:///tmp/todo.sock" or "/tmp/todo.sock".
+func IsTCPAddress(address string) bool {
+	if strings.Contains(address, "/") || strings.Contains(address, `\`) {
+		return false
+	}
+	_, _, err := net.SplitHostPort(address)
+	return err == nil
+}
+
+// RemoteDialer returns a [Dialer] for the specified remote server: either a
+// URL with the scheme [SchemeTCP], which is dialed directly, or with the
+// scheme [SchemeSSH], for which an SSH client runs the specified command with
+// the arguments "proxy --stdio" on the remote host. The standard error of the
+// SSH client, e.g. its password prompts, goes to the specified writer.
+func RemoteDialer(remote, command string, stderr io.Writer) (Dialer, error) {
+	u, err := url.Parse(remote)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote '%s': %w", remote, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid remote '%s': no host", remote)
+	}
+	switch u.Scheme {
+	case SchemeTCP:
+		return func(ctx context.Context) (io.ReadWriteCloser, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "tcp", u.Host)
+		}, nil
+	case SchemeSSH:
+		args := sshArgs(u, command)
+		return func(ctx context.Context) (io.ReadWriteCloser, error) {
+			return dialSSH(ctx, args, stderr)
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid remote '%s': unsupported scheme '%s' (want tcp or ssh)", remote, u.Scheme)
+	}
+}
+
+// sshArgs returns the arguments of the SSH client for running the proxy in
+// stdio mode on the host of the specified URL.
+func sshArgs(u *url.URL, command string) []string {
+	var args []string
+	if port := u.Port(); port != "" {
+		args = append(args, "-p", port)
+	}
+	if user := u.User.Username(); user != "" {
+		args = append(args, "-l", user)
+	}
+	if command == "" {
+		command = DefaultRemoteCommand
+	}
+	// The host is separated from the options, so it cannot be taken for one.
+	return append(args, "--", u.Hostname(), command+" proxy --stdio")
+}
+
+// sshConn is a connection through the standard input and output of an SSH
+// client.
+type sshConn struct {
+	io.Reader
+	io.WriteCloser
+	cmd *exec.Cmd
+}
+
+// dialSSH starts an SSH client with the specified arguments.
+func dialSSH(ctx context.Context, args []string, stderr io.Writer) (io.ReadWriteCloser, error) {
+	cmd := exec.CommandContext(ctx, "ssh", args...)
+	cmd.Stderr = stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("cannot start SSH client: %w", err)
+	}
+	return &sshConn{Reader: stdout, WriteCloser: stdin, cmd: cmd}, nil
+}
+
+// Close closes the standard input of the SSH client and stops it.
+func (c *sshConn) Close() error {
+	err := c.WriteCloser.Close()
+	if c.cmd.ProcessState == nil {
+		// revive:disable-next-line:unhandled-error
+		c.cmd.Process.Kill()
+		// The client has been killed, so its exit status tells nothing.
+		// revive:disable-next-line:unhandled-error
+		c.cmd.Wait()
+	}
+	return err
+}
+
+// Serve accepts connections on the specified listener and forwards each of
+// them to a new connection opened with the specified dialer, until the
+// context is canceled. It closes the listener and waits for the forwarded
+// connections to end before it returns.
+func Serve(ctx context.Context, ln net.Listener, dial Dialer) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		// revive:disable-next-line:unhandled-error
+		ln.Close()
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("cannot accept connection: %w", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			target, err := dial(ctx)
+			if err != nil {
+				slog.Warn("cannot connect to proxy target", "cause", err)
+				// revive:disable-next-line:unhandled-error
+				conn.Close()
+				return
+			}
+			slog.Debug("forwarding connection", "remote", conn.RemoteAddr())
+			if err := Pipe(ctx, conn, target); err != nil {
+				slog.Debug("forwarded connection failed", "cause", err)
+			}
+		}()
+	}
+}
+
+// Pipe copies data between the specified connections in both directions until
+// one of them is closed or the context is canceled. Then it closes both
+// connections, which ends the copy in the other direction, too.
+func Pipe(ctx context.Context, a, b io.ReadWriteCloser) error {
+	// The second copy isn't waited for, since it may be stuck reading from
+	// standard input, which closing doesn't interrupt. The buffered channel
+	// lets it finish whenever its read returns.
+	done := make(chan error, 2)
+	copyTo := func(dst io.Writer, src io.Reader) {
+		_, err := io.Copy(dst, src)
+		done <- err
+	}
+	go copyTo(a, b)
+	go copyTo(b, a)
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+	}
+	// revive:disable-next-line:unhandled-error
+	a.Close()
+	// revive:disable-next-line:unhandled-error
+	b.Close()
+	if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
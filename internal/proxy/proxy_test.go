@@ -0,0 +1,152 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// echo accepts connections on the specified listener and writes every line
+// back to its sender.
+func echo(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			r := bufio.NewReader(conn)
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if _, err := io.WriteString(conn, line); err != nil {
+					return
+				}
+			}
+		}()
+	}
+}
+
+func TestServe(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "target.sock")
+	target, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer target.Close()
+	go echo(target)
+
+	ln, err := ListenTCP("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve(ctx, ln, func(ctx context.Context) (io.ReadWriteCloser, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", sock)
+		})
+	}()
+
+	for range 2 {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(conn, "hello\n"); err != nil {
+			t.Fatal(err)
+		}
+		got, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != "hello\n" {
+			t.Errorf("want: %q; got: %q", "hello\n", got)
+		}
+		conn.Close()
+	}
+
+	cancel()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("want nil error after cancellation; got: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after cancellation")
+	}
+}
+
+func TestListenTCPNotLoopback(t *testing.T) {
+	for _, address := range []string{"0.0.0.0:0", ":0", "192.0.2.1:9000"} {
+		ln, err := ListenTCP(address)
+		if !errors.Is(err, ErrNotLoopback) {
+			t.Errorf("%s: want %v; got: %v", address, ErrNotLoopback, err)
+		}
+		if ln != nil {
+			ln.Close()
+		}
+	}
+}
+
+func TestIsTCPAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{"localhost:9000", true},
+		{"127.0.0.1:9000", true},
+		{"[::1]:9000", true},
+		{"/tmp/todo.sock", false},
+		{"unix:///tmp/todo.sock", false},
+		{"todo.sock", false},
+	}
+	for _, tt := range tests {
+		if got := IsTCPAddress(tt.address); got != tt.want {
+			t.Errorf("%s: want %t; got: %t", tt.address, tt.want, got)
+		}
+	}
+}
+
+func TestSSHArgs(t *testing.T) {
+	tests := []struct {
+		remote  string
+		command string
+		want    []string
+	}{
+		{"ssh://host", "", []string{"--", "host", "todo-daemon proxy --stdio"}},
+		{
+			"ssh://alice@host:2222",
+			"todo-daemon --profile work",
+			[]string{"-p", "2222", "-l", "alice", "--", "host", "todo-daemon --profile work proxy --stdio"},
+		},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.remote)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := sshArgs(u, tt.command); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: want %q; got: %q", tt.remote, tt.want, got)
+		}
+	}
+}
+
+func TestRemoteDialerInvalid(t *testing.T) {
+	for _, remote := range []string{"host", "http://host", "ssh://", "tcp:///path"} {
+		if _, err := RemoteDialer(remote, "", io.Discard); err == nil {
+			t.Errorf("%s: want error; got nil", remote)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+// Package queue implements the offline queue of the To-do Daemon CLI, which
+// holds the modifications made while the server is not running, e.g. on a
+// laptop whose server runs only while the user is logged in.
+//
+// The queue is a journal file with one JSON document per line. Each
+// operation is appended to it while holding a lock file next to it, and
+// [Queue.Flush] replays the operations in order once the server is reachable
+// again. An operation is applied at least once: if the CLI is interrupted
+// after applying an operation but before removing it from the journal, the
+// operation is applied again by the next flush.
+package queue
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/lockfile"
+)
+
+// OperationType identifies the kind of a queued operation.
+type OperationType string
+
+// OperationCreate creates the task of the operation.
+const OperationCreate OperationType = "create"
+
+// Operation is a line of the journal of a [Queue], which is a JSON document.
+type Operation struct {
+	// Time is the time when the operation was queued.
+	Time time.Time `json:"time"`
+	// Type is the kind of operation.
+	Type OperationType `json:"type"`
+	// Task is the JSON representation of the [todopb.NewTask] to be created.
+	Task json.RawMessage `json:"task,omitempty"`
+}
+
+// Summary returns the summary of the task of the operation, or an empty
+// string if it cannot be decoded.
+func (op *Operation) Summary() string {
+	task, err := op.newTask()
+	if err != nil {
+		return ""
+	}
+	return task.GetSummary()
+}
+
+func (op *Operation) newTask() (*todopb.NewTask, error) {
+	task := &todopb.NewTask{}
+	if err := protojson.Unmarshal(op.Task, task); err != nil {
+		return nil, fmt.Errorf("invalid queued task: %w", err)
+	}
+	return task, nil
+}
+
+// Failure is a queued operation that the server rejected. It was removed from
+// the queue, since replaying it again would fail again.
+type Failure struct {
+	Operation Operation
+	Err       error
+}
+
+// Result is the result of [Queue.Flush].
+type Result struct {
+	// Created are the tasks created by the replayed operations.
+	Created []*todopb.Task
+	// Failed are the operations that the server rejected.
+	Failed []Failure
+	// Pending is the number of operations still in the queue, because the
+	// server stopped running while flushing the queue.
+	Pending int
+}
+
+// Queue is the offline queue of the To-do Daemon CLI.
+type Queue struct {
+	path string
+	lock *lockfile.Lock
+}
+
+// New creates a queue whose journal is the file at the specified path. The
+// file is created when the first operation is queued.
+func New(path string) *Queue {
+	return &Queue{path: path, lock: lockfile.New(path + ".lock")}
+}
+
+// Path returns the path to the journal file.
+func (q *Queue) Path() string {
+	return q.path
+}
+
+// Add appends an operation creating each of the specified tasks to the
+// journal.
+func (q *Queue) Add(ctx context.Context, tasks ...*todopb.NewTask) error {
+	var buf []byte
+	now := time.Now()
+	for _, task := range tasks {
+		b, err := protojson.Marshal(task)
+		if err != nil {
+			return fmt.Errorf("cannot queue task: %w", err)
+		}
+		line, err := json.Marshal(Operation{Time: now, Type: OperationCreate, Task: b})
+		if err != nil {
+			return fmt.Errorf("cannot queue task: %w", err)
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+	if _, err := q.lock.LockContext(ctx); err != nil {
+		return fmt.Errorf("cannot lock offline queue: %w", err)
+	}
+	// revive:disable-next-line:unhandled-error
+	defer q.lock.Unlock()
+	f, err := os.OpenFile(q.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("cannot queue task: %w", err)
+	}
+	if _, err := f.Write(buf); err != nil {
+		return errors.Join(fmt.Errorf("cannot queue task: %w", err), f.Close())
+	}
+	if err := f.Sync(); err != nil {
+		return errors.Join(fmt.Errorf("cannot queue task: %w", err), f.Close())
+	}
+	return f.Close()
+}
+
+// Empty reports whether no operations are queued. It does not lock the
+// queue, so it is only a cheap check before calling [Queue.Flush].
+func (q *Queue) Empty() bool {
+	info, err := os.Stat(q.path)
+	return err != nil || info.Size() == 0
+}
+
+// Flush replays the queued operations on the specified service in the order
+// they were queued, and removes them from the queue. If the server stops
+// running, the remaining operations stay in the queue.
+func (q *Queue) Flush(ctx context.Context, svc client.TaskService) (*Result, error) {
+	if _, err := q.lock.LockContext(ctx); err != nil {
+		return nil, fmt.Errorf("cannot lock offline queue: %w", err)
+	}
+	// revive:disable-next-line:unhandled-error
+	defer q.lock.Unlock()
+	ops, err := q.read()
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{}
+	i := 0
+	for ; i < len(ops); i++ {
+		task, err := ops[i].apply(ctx, svc)
+		if errors.Is(err, client.ErrDaemonNotRunning) || ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, Failure{Operation: ops[i], Err: err})
+			continue
+		}
+		result.Created = append(result.Created, task)
+	}
+	result.Pending = len(ops) - i
+	if i > 0 {
+		if err := q.write(ops[i:]); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// apply applies the operation on the specified service.
+func (op *Operation) apply(ctx context.Context, svc client.TaskService) (*todopb.Task, error) {
+	switch op.Type {
+	case OperationCreate:
+		task, err := op.newTask()
+		if err != nil {
+			return nil, err
+		}
+		return svc.CreateTask(ctx, task)
+	default:
+		return nil, fmt.Errorf("unknown operation type '%s'", op.Type)
+	}
+}
+
+// read reads the queued operations from the journal. The caller must hold the
+// lock.
+func (q *Queue) read() ([]Operation, error) {
+	f, err := os.Open(q.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read offline queue: %w", err)
+	}
+	// revive:disable-next-line:unhandled-error
+	defer f.Close()
+	var ops []Operation
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		var op Operation
+		if err := json.Unmarshal(scanner.Bytes(), &op); err != nil {
+			return nil, fmt.Errorf("invalid offline queue %s: line %d: %w", q.path, line, err)
+		}
+		ops = append(ops, op)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read offline queue: %w", err)
+	}
+	return ops, nil
+}
+
+// write replaces the queued operations in the journal with the specified
+// ones, removing the journal if there are none. The caller must hold the
+// lock.
+func (q *Queue) write(ops []Operation) error {
+	if len(ops) == 0 {
+		if err := os.Remove(q.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("cannot update offline queue: %w", err)
+		}
+		return nil
+	}
+	var buf []byte
+	for _, op := range ops {
+		line, err := json.Marshal(op)
+		if err != nil {
+			return fmt.Errorf("cannot update offline queue: %w", err)
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+	tmp := filepath.Join(filepath.Dir(q.path), "."+filepath.Base(q.path)+".tmp")
+	if err := os.WriteFile(tmp, buf, 0o600); err != nil {
+		return fmt.Errorf("cannot update offline queue: %w", err)
+	}
+	if err := os.Rename(tmp, q.path); err != nil {
+		return fmt.Errorf("cannot update offline queue: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package queue
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/cli/clitest"
+	"github.com/mwopitz/todo-daemon/internal/client"
+)
+
+func TestFlush(t *testing.T) {
+	ctx := t.Context()
+	q := New(filepath.Join(t.TempDir(), "queue.jsonl"))
+	if !q.Empty() {
+		t.Error("want new queue to be empty")
+	}
+	err := q.Add(ctx, &todopb.NewTask{Summary: "Buy milk"}, &todopb.NewTask{}, &todopb.NewTask{Summary: "Walk the dog"})
+	if err != nil {
+		t.Fatalf("cannot queue tasks: %v", err)
+	}
+
+	srv := clitest.NewServer(t)
+	c, err := srv.NewTaskService(clitest.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	result, err := q.Flush(ctx, c)
+	if err != nil {
+		t.Fatalf("cannot flush queue: %v", err)
+	}
+	if len(result.Created) != 2 || result.Created[1].GetSummary() != "Walk the dog" {
+		t.Errorf("want 2 tasks created in order; got: %v", result.Created)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].Operation.Summary() != "" || result.Pending != 0 {
+		t.Errorf("want task without summary rejected; got: %+v", result)
+	}
+	if _, err := os.Stat(q.Path()); !os.IsNotExist(err) || !q.Empty() {
+		t.Errorf("want journal removed after flushing; got: %v", err)
+	}
+}
+
+func TestFlushNotRunning(t *testing.T) {
+	ctx := t.Context()
+	dir := t.TempDir()
+	q := New(filepath.Join(dir, "queue.jsonl"))
+	if err := q.Add(ctx, &todopb.NewTask{Summary: "Buy milk"}); err != nil {
+		t.Fatalf("cannot queue task: %v", err)
+	}
+	c, err := client.NewTaskService(filepath.Join(dir, "todo-daemon.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	result, err := q.Flush(ctx, c)
+	if err != nil {
+		t.Fatalf("cannot flush queue: %v", err)
+	}
+	if result.Pending != 1 || len(result.Created) != 0 || len(result.Failed) != 0 {
+		t.Errorf("want task kept in queue; got: %+v", result)
+	}
+	if q.Empty() {
+		t.Error("want queue not to be empty")
+	}
+}
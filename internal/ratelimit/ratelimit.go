@@ -0,0 +1,184 @@
+// Package ratelimit provides a token bucket rate limiter for HTTP servers,
+// which limits the requests both per remote IP address and globally. Behind
+// trusted reverse proxies, the remote IP address is taken from the
+// X-Forwarded-For header, see package forwarded.
+package ratelimit
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/forwarded"
+	"github.com/mwopitz/todo-daemon/internal/rest"
+)
+
+// sweepInterval is the interval in which the buckets of idle remote IPs are
+// removed.
+const sweepInterval = time.Minute
+
+// Limit describes a token bucket.
+type Limit struct {
+	// Rate is the number of requests per second that are allowed on average.
+	// A rate <= 0 means that there is no limit.
+	Rate float64
+	// Burst is the maximum number of requests that are allowed at once. If
+	// Burst < 1, it defaults to the rate, but at least 1.
+	Burst int
+}
+
+// Unlimited checks if the limit allows an unlimited number of requests.
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0
+}
+
+func (l Limit) capacity() float64 {
+	if l.Burst >= 1 {
+		return float64(l.Burst)
+	}
+	return math.Max(l.Rate, 1)
+}
+
+// bucket is a token bucket, which holds up to capacity tokens and is refilled
+// at a constant rate.
+type bucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(limit Limit, now time.Time) *bucket {
+	return &bucket{limit: limit, tokens: limit.capacity(), last: now}
+}
+
+// refill adds the tokens accumulated since the last refill.
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(b.limit.capacity(), b.tokens+elapsed*b.limit.Rate)
+		b.last = now
+	}
+}
+
+// wait returns how long it takes until the bucket holds a token again.
+func (b *bucket) wait() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.limit.Rate * float64(time.Second))
+}
+
+// full checks if the bucket holds its maximum number of tokens.
+func (b *bucket) full() bool {
+	return b.tokens >= b.limit.capacity()
+}
+
+// Limiter limits requests with a global token bucket and a token bucket per
+// remote IP address. A request is only allowed if both buckets hold a token.
+type Limiter struct {
+	global Limit
+	perIP  Limit
+	now    func() time.Time
+
+	mu        sync.Mutex
+	globalB   *bucket
+	ips       map[string]*bucket
+	lastSweep time.Time
+}
+
+// New creates a [Limiter] with the specified global and per-IP limits.
+func New(global, perIP Limit) *Limiter {
+	return &Limiter{
+		global: global,
+		perIP:  perIP,
+		now:    time.Now,
+		ips:    make(map[string]*bucket),
+	}
+}
+
+// Allow checks if a request from the specified IP address is allowed, and if
+// so, takes a token from the respective buckets. Otherwise, it returns how
+// long the client should wait before retrying.
+func (l *Limiter) Allow(ip string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.sweep(now)
+
+	var buckets []*bucket
+	if !l.global.Unlimited() {
+		if l.globalB == nil {
+			l.globalB = newBucket(l.global, now)
+		}
+		buckets = append(buckets, l.globalB)
+	}
+	if !l.perIP.Unlimited() {
+		b, ok := l.ips[ip]
+		if !ok {
+			b = newBucket(l.perIP, now)
+			l.ips[ip] = b
+		}
+		buckets = append(buckets, b)
+	}
+
+	var wait time.Duration
+	for _, b := range buckets {
+		b.refill(now)
+		wait = max(wait, b.wait())
+	}
+	if wait > 0 {
+		return false, wait
+	}
+	for _, b := range buckets {
+		b.tokens--
+	}
+	return true, 0
+}
+
+// sweep removes the buckets of remote IPs that have been idle long enough for
+// their buckets to be full again.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for ip, b := range l.ips {
+		b.refill(now)
+		if b.full() {
+			delete(l.ips, ip)
+		}
+	}
+}
+
+// Middleware returns a handler that rejects requests exceeding the limits with
+// "429 Too Many Requests" and passes all other requests to the next handler.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ok, wait := l.Allow(remoteIP(r))
+		if !ok {
+			seconds := int(math.Ceil(wait.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+			rest.WriteError(w, r, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// remoteIP returns the IP address of the client that sent the request. Behind
+// trusted reverse proxies, it is the address determined by the
+// [forwarded.Middleware] rather than the address of the proxy, so that the
+// clients of the proxy don't share a bucket.
+func remoteIP(r *http.Request) string {
+	if ip, ok := forwarded.ClientIPFromContext(r.Context()); ok {
+		return ip
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/forwarded"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := New(Limit{Rate: 10, Burst: 3}, Limit{Rate: 1, Burst: 2})
+	l.now = func() time.Time { return now }
+
+	for i := range 2 {
+		if ok, _ := l.Allow("10.0.0.1"); !ok {
+			t.Fatalf("request %d from 10.0.0.1 was rejected", i+1)
+		}
+	}
+	ok, wait := l.Allow("10.0.0.1")
+	if ok {
+		t.Fatal("want per-IP limit to reject request")
+	}
+	if wait != time.Second {
+		t.Errorf("want wait: %v; got: %v", time.Second, wait)
+	}
+
+	// The global bucket holds one more token.
+	if ok, _ := l.Allow("10.0.0.2"); !ok {
+		t.Fatal("request from 10.0.0.2 was rejected")
+	}
+	if ok, _ := l.Allow("10.0.0.3"); ok {
+		t.Fatal("want global limit to reject request")
+	}
+
+	now = now.Add(time.Second)
+	if ok, _ := l.Allow("10.0.0.1"); !ok {
+		t.Fatal("want bucket of 10.0.0.1 to be refilled")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	l := New(Limit{}, Limit{Rate: 0.5, Burst: 1})
+	h := l.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for _, want := range []int{http.StatusNoContent, http.StatusTooManyRequests} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != want {
+			t.Fatalf("want status: %d; got: %d", want, rec.Code)
+		}
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("want Retry-After: 2; got: %q", got)
+	}
+}
+
+// TestMiddlewareBehindProxy checks that the clients of a trusted proxy get
+// buckets of their own, while the X-Forwarded-For header of other clients is
+// ignored.
+func TestMiddlewareBehindProxy(t *testing.T) {
+	proxies, err := forwarded.ParseProxies([]string{"10.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := New(Limit{}, Limit{Rate: 0.5, Burst: 1})
+	h := forwarded.Middleware(l.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})), nil, proxies)
+
+	tests := []struct {
+		remoteAddr, forwardedFor string
+		want                     int
+	}{
+		{"10.0.0.1:1234", "192.0.2.1", http.StatusNoContent},
+		{"10.0.0.1:1234", "192.0.2.2", http.StatusNoContent},
+		{"10.0.0.1:1234", "192.0.2.1", http.StatusTooManyRequests},
+		// A client can prepend addresses, but not replace its own.
+		{"10.0.0.1:1234", "198.51.100.1, 192.0.2.2", http.StatusTooManyRequests},
+		{"192.0.2.3:1234", "198.51.100.2", http.StatusNoContent},
+		{"192.0.2.3:1234", "198.51.100.3", http.StatusTooManyRequests},
+	}
+	for i, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tt.remoteAddr
+		r.Header.Set(forwarded.HeaderFor, tt.forwardedFor)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		if rec.Code != tt.want {
+			t.Errorf("request %d: want status: %d; got: %d", i+1, tt.want, rec.Code)
+		}
+	}
+}
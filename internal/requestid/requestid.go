@@ -0,0 +1,67 @@
+// Package requestid implements the request IDs of the To-do Daemon, which
+// correlate the log messages and error responses of a single request across
+// the HTTP server, the gRPC gateway, and the gRPC server.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// Header is the HTTP header holding the ID of a request. Clients may set it;
+// otherwise the server generates an ID. Either way, the server sends the ID
+// back in the same header.
+const Header = "X-Request-ID"
+
+// MetadataKey is the gRPC metadata key holding the ID of a request.
+const MetadataKey = "x-request-id"
+
+// maxLength is the maximum length of a request ID chosen by a client. Longer
+// IDs are replaced with generated ones.
+const maxLength = 128
+
+type contextKey struct{}
+
+// New generates a random request ID.
+func New() string {
+	b := make([]byte, 8)
+	// rand.Read never returns an error.
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// NewContext returns a copy of the specified context that holds the request
+// ID.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID held by the specified context, or an
+// empty string if there is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Valid returns the specified request ID chosen by a client if it is
+// acceptable, or a newly generated ID otherwise.
+func Valid(id string) string {
+	if id == "" || len(id) > maxLength {
+		return New()
+	}
+	return id
+}
+
+// Middleware returns a handler that assigns an ID to each request, taken from
+// the [Header] or generated randomly, before passing it to the next handler.
+// The ID is sent back in the [Header] and can be retrieved from the request's
+// context using [FromContext].
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := Valid(r.Header.Get(Header))
+		w.Header().Set(Header, id)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+	})
+}
@@ -0,0 +1,108 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mwopitz/todo-daemon/internal/logging"
+	"github.com/mwopitz/todo-daemon/internal/requestid"
+)
+
+// ProblemContentType is the media type of the error responses of the REST
+// API, as specified by RFC 7807.
+const ProblemContentType = "application/problem+json"
+
+// The types of the problems reported by the REST API. Clients should rely on
+// these types rather than the human-readable titles and details.
+const (
+	ProblemInvalidRequest     = "urn:todo-daemon:problem:invalid-request"
+	ProblemNotFound           = "urn:todo-daemon:problem:not-found"
+	ProblemMethodNotAllowed   = "urn:todo-daemon:problem:method-not-allowed"
+	ProblemReadOnly           = "urn:todo-daemon:problem:read-only"
+	ProblemConflict           = "urn:todo-daemon:problem:conflict"
+	ProblemPreconditionFailed = "urn:todo-daemon:problem:precondition-failed"
+	ProblemTooLarge           = "urn:todo-daemon:problem:too-large"
+	ProblemUnsupportedMedia   = "urn:todo-daemon:problem:unsupported-media-type"
+	ProblemRateLimited        = "urn:todo-daemon:problem:rate-limited"
+	ProblemTimeout            = "urn:todo-daemon:problem:timeout"
+	ProblemUnavailable        = "urn:todo-daemon:problem:unavailable"
+	ProblemNotImplemented     = "urn:todo-daemon:problem:not-implemented"
+	ProblemInternal           = "urn:todo-daemon:problem:internal"
+)
+
+// problemTypes maps HTTP status codes to the types of problems they indicate.
+// Status codes that are not listed result in the type "about:blank".
+var problemTypes = map[int]string{
+	http.StatusBadRequest:            ProblemInvalidRequest,
+	http.StatusNotFound:              ProblemNotFound,
+	http.StatusMethodNotAllowed:      ProblemMethodNotAllowed,
+	http.StatusConflict:              ProblemConflict,
+	http.StatusPreconditionFailed:    ProblemPreconditionFailed,
+	http.StatusRequestEntityTooLarge: ProblemTooLarge,
+	http.StatusUnsupportedMediaType:  ProblemUnsupportedMedia,
+	http.StatusTooManyRequests:       ProblemRateLimited,
+	http.StatusInternalServerError:   ProblemInternal,
+	http.StatusNotImplemented:        ProblemNotImplemented,
+	http.StatusServiceUnavailable:    ProblemUnavailable,
+	http.StatusGatewayTimeout:        ProblemTimeout,
+}
+
+// Problem is the JSON representation of an error returned by the REST API, as
+// specified by RFC 7807.
+type Problem struct {
+	// Type identifies the category of the problem, e.g. [ProblemNotFound].
+	Type string `json:"type"`
+	// Title is a short, human-readable summary of the problem type.
+	Title string `json:"title"`
+	// Status is the HTTP status code of the response.
+	Status int `json:"status"`
+	// Detail is a human-readable explanation of this occurrence of the
+	// problem.
+	Detail string `json:"detail,omitempty"`
+	// RequestID is the ID of the request that caused the problem.
+	RequestID string `json:"requestId,omitempty"`
+	// InvalidParams describe the invalid fields of an invalid request.
+	InvalidParams []InvalidParam `json:"invalidParams,omitempty"`
+}
+
+// InvalidParam describes why a single field of a request is invalid.
+type InvalidParam struct {
+	// Name is the path of the invalid field, e.g. "task.summary".
+	Name string `json:"name"`
+	// Reason tells why the field is invalid.
+	Reason string `json:"reason"`
+}
+
+// NewProblem creates a problem with the specified HTTP status code and detail.
+// The type and title of the problem are derived from the status code.
+func NewProblem(status int, detail string) *Problem {
+	typ, ok := problemTypes[status]
+	if !ok {
+		typ = "about:blank"
+	}
+	return &Problem{
+		Type:   typ,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// WriteProblem writes the specified problem as response to the given request.
+func WriteProblem(w http.ResponseWriter, r *http.Request, p *Problem) {
+	if p.RequestID == "" {
+		p.RequestID = requestid.FromContext(r.Context())
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		logging.FromContext(r.Context()).WarnContext(r.Context(), "cannot write problem response", "cause", err)
+	}
+}
+
+// WriteError writes a problem response with the given HTTP status code and a
+// formatted detail message.
+func WriteError(w http.ResponseWriter, r *http.Request, status int, format string, args ...any) {
+	WriteProblem(w, r, NewProblem(status, fmt.Sprintf(format, args...)))
+}
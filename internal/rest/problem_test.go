@@ -0,0 +1,59 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mwopitz/todo-daemon/internal/requestid"
+)
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		status    int
+		wantType  string
+	}{
+		{"NotFound", "", http.StatusNotFound, ProblemNotFound},
+		{"RateLimited", "abc123", http.StatusTooManyRequests, ProblemRateLimited},
+		{"Unlisted", "", http.StatusTeapot, "about:blank"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := requestid.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				WriteError(w, r, tt.status, "no such task: '%s'", "42")
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/42", nil)
+			if tt.requestID != "" {
+				req.Header.Set(requestid.Header, tt.requestID)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("want status: %d; got: %d", tt.status, rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); got != ProblemContentType {
+				t.Errorf("want content type: %s; got: %s", ProblemContentType, got)
+			}
+			var p Problem
+			if err := json.NewDecoder(rec.Body).Decode(&p); err != nil {
+				t.Fatal(err)
+			}
+			if p.Type != tt.wantType || p.Status != tt.status || p.Title != http.StatusText(tt.status) {
+				t.Errorf("unexpected problem: %+v", p)
+			}
+			if p.Detail != "no such task: '42'" {
+				t.Errorf("unexpected detail: %q", p.Detail)
+			}
+			if p.RequestID == "" || p.RequestID != rec.Header().Get(requestid.Header) {
+				t.Errorf("want request ID %q in problem; got: %q", rec.Header().Get(requestid.Header), p.RequestID)
+			}
+			if tt.requestID != "" && p.RequestID != tt.requestID {
+				t.Errorf("want client's request ID: %q; got: %q", tt.requestID, p.RequestID)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Package rest provides helpers for the REST API endpoints of the To-do Daemon
+// that are implemented natively rather than through the gRPC gateway.
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/logging"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// WriteJSON writes the specified value as JSON response with the given HTTP
+// status code.
+func WriteJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logging.Component(logging.ComponentHTTP).Warn("cannot write JSON response", "cause", err)
+	}
+}
+
+// DecodeJSON decodes the JSON body of the specified request into v. Fields
+// that v doesn't have are rejected, so that typos don't go unnoticed.
+func DecodeJSON(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+// Task is the JSON representation of a task in the REST API. It uses the same
+// field names as the gRPC gateway.
+type Task struct {
+	ID          string     `json:"id"`
+	Summary     string     `json:"summary"`
+	Description string     `json:"description,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	Completed   bool       `json:"completed"`
+	DueAt       *time.Time `json:"dueAt,omitempty"`
+	Version     uint64     `json:"version"`
+	ShortCode   string     `json:"shortCode"`
+	Tags        []string   `json:"tags,omitempty"`
+	Project     string     `json:"project,omitempty"`
+	Position    int64      `json:"position"`
+	Assignee    string     `json:"assignee,omitempty"`
+	Links       []string   `json:"links,omitempty"`
+}
+
+// NewTask converts the specified task into its JSON representation.
+func NewTask(t *todo.Task) *Task {
+	return &Task{
+		ID:          t.ID,
+		Summary:     t.Summary,
+		Description: t.Description,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   optionalTime(t.UpdatedAt),
+		CompletedAt: optionalTime(t.CompletedAt),
+		Completed:   !t.CompletedAt.IsZero(),
+		DueAt:       optionalTime(t.DueAt),
+		Version:     t.Version,
+		ShortCode:   todo.ShortCode(t.ID),
+		Tags:        t.Tags,
+		Project:     t.Project,
+		Position:    t.Position,
+		Assignee:    t.Assignee,
+		Links:       t.Links,
+	}
+}
+
+// Event is the JSON representation of a task event in the REST API.
+type Event struct {
+	ID   string         `json:"id"`
+	Type todo.EventType `json:"type"`
+	Time time.Time      `json:"time"`
+	Task *Task          `json:"task"`
+}
+
+// NewEvent converts the specified event into its JSON representation. The ID
+// of the event is its sequence number.
+func NewEvent(e *todo.Event) *Event {
+	return &Event{
+		ID:   strconv.FormatUint(e.Seq, 10),
+		Type: e.Type,
+		Time: e.Time,
+		Task: NewTask(&e.Task),
+	}
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,138 @@
+// Package search provides a simple full-text index for ranking tasks by their
+// relevance to a search query.
+//
+// The index tokenizes text into lower-case words and ranks documents with the
+// Okapi BM25 function. Query terms also match words they are a prefix of, with
+// a lower weight than exact matches, so that "mil" finds "milk".
+package search
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	// BM25 parameters.
+	k1 = 1.2
+	b  = 0.75
+	// prefixWeight is the weight of prefix matches relative to exact matches.
+	prefixWeight = 0.5
+)
+
+// Field is a weighted piece of text of an indexed document.
+type Field struct {
+	// Text is the text of the field.
+	Text string
+	// Weight is the factor applied to the term frequencies of the field.
+	Weight float64
+}
+
+// Hit is a document matching a search query.
+type Hit struct {
+	// ID is the ID of the matching document.
+	ID string
+	// Score is the relevance of the document; higher is more relevant.
+	Score float64
+}
+
+// Index is an inverted index of documents. It is not safe for concurrent use.
+type Index struct {
+	// postings maps terms to the weighted term frequencies per document.
+	postings map[string]map[string]float64
+	// lengths holds the weighted number of terms per document.
+	lengths map[string]float64
+	total   float64
+}
+
+// NewIndex creates an empty [Index].
+func NewIndex() *Index {
+	return &Index{
+		postings: make(map[string]map[string]float64),
+		lengths:  make(map[string]float64),
+	}
+}
+
+// Put adds the document with the specified ID and fields to the index,
+// replacing any previous document with the same ID.
+func (ix *Index) Put(id string, fields ...Field) {
+	ix.Remove(id)
+	var length float64
+	for _, f := range fields {
+		for _, term := range Tokenize(f.Text) {
+			docs, ok := ix.postings[term]
+			if !ok {
+				docs = make(map[string]float64)
+				ix.postings[term] = docs
+			}
+			docs[id] += f.Weight
+			length += f.Weight
+		}
+	}
+	ix.lengths[id] = length
+	ix.total += length
+}
+
+// Remove removes the document with the specified ID from the index.
+func (ix *Index) Remove(id string) {
+	length, ok := ix.lengths[id]
+	if !ok {
+		return
+	}
+	for term, docs := range ix.postings {
+		delete(docs, id)
+		if len(docs) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+	delete(ix.lengths, id)
+	ix.total -= length
+}
+
+// Search returns the documents matching any of the terms in the query, ordered
+// by descending relevance. Documents with the same score are ordered by ID.
+func (ix *Index) Search(query string) []Hit {
+	n := float64(len(ix.lengths))
+	if n == 0 {
+		return nil
+	}
+	avg := ix.total / n
+	scores := make(map[string]float64)
+	for _, q := range slices.Compact(slices.Sorted(slices.Values(Tokenize(query)))) {
+		for term, docs := range ix.postings {
+			weight := 1.0
+			switch {
+			case term == q:
+			case strings.HasPrefix(term, q):
+				weight = prefixWeight
+			default:
+				continue
+			}
+			idf := math.Log(1 + (n-float64(len(docs))+0.5)/(float64(len(docs))+0.5))
+			for id, tf := range docs {
+				norm := tf + k1*(1-b+b*ix.lengths[id]/avg)
+				scores[id] += weight * idf * tf * (k1 + 1) / norm
+			}
+		}
+	}
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{ID: id, Score: score})
+	}
+	slices.SortFunc(hits, func(a, b Hit) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return hits
+}
+
+// Tokenize splits the specified text into lower-case words.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,42 @@
+package search
+
+import (
+	"slices"
+	"testing"
+)
+
+func hitIDs(hits []Hit) []string {
+	ids := make([]string, len(hits))
+	for i, h := range hits {
+		ids[i] = h.ID
+	}
+	return ids
+}
+
+func TestIndexSearch(t *testing.T) {
+	ix := NewIndex()
+	ix.Put("1", Field{Text: "Get some milk", Weight: 2}, Field{Text: "From the shop", Weight: 1})
+	ix.Put("2", Field{Text: "Walk the dog", Weight: 2}, Field{Text: "Don't forget the milk for the dog", Weight: 1})
+	ix.Put("3", Field{Text: "Take over the world!", Weight: 2})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "milk", want: []string{"1", "2"}},
+		{query: "MILK dog", want: []string{"2", "1"}},
+		{query: "wor", want: []string{"3"}},
+		{query: "cat", want: []string{}},
+	}
+	for _, tt := range tests {
+		if got := hitIDs(ix.Search(tt.query)); !slices.Equal(got, tt.want) {
+			t.Errorf("%q: want: %v; got: %v", tt.query, tt.want, got)
+		}
+	}
+
+	ix.Put("1", Field{Text: "Get some bread", Weight: 2})
+	ix.Remove("2")
+	if got := hitIDs(ix.Search("milk")); len(got) != 0 {
+		t.Errorf("want no hits after update; got: %v", got)
+	}
+}
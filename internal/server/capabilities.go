@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"math"
+	"slices"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// The optional features that GetCapabilities reports when they are enabled.
+const (
+	FeatureBatch              = "batch"
+	FeatureCompression        = "compression"
+	FeatureConfigReload       = "config_reload"
+	FeatureCORS               = "cors"
+	FeatureFilters            = "filters"
+	FeatureHooks              = "hooks"
+	FeatureIdempotency        = "idempotency"
+	FeatureQuotas             = "quotas"
+	FeatureRateLimit          = "rate_limit"
+	FeatureReflection         = "reflection"
+	FeatureREST               = "rest"
+	FeatureScheduledBackups   = "scheduled_backups"
+	FeatureStrictDependencies = "strict_dependencies"
+	FeatureSync               = "sync"
+	FeatureTemplates          = "templates"
+	FeatureUniqueSummaries    = "unique_summaries"
+	FeatureWebhooks           = "webhooks"
+	FeatureWebUI              = "web_ui"
+)
+
+// apiVersions are the versions of the API that the server supports.
+var apiVersions = []string{"v1", "v2"}
+
+// GetCapabilities handles gRPC requests to retrieve the features enabled on
+// the server, its limits, and the API versions it supports.
+func (c *controller) GetCapabilities(
+	_ context.Context,
+	_ *todopb.GetCapabilitiesRequest,
+) (*todopb.GetCapabilitiesResponse, error) {
+	return &todopb.GetCapabilitiesResponse{
+		Features: c.server.features(),
+		Limits: &todopb.Limits{
+			MaxSummaryLength:     todo.MaxSummaryLength,
+			MaxDescriptionLength: todo.MaxDescriptionLength,
+			MaxProjectLength:     todo.MaxProjectLength,
+			MaxTagLength:         todo.MaxTagLength,
+			MaxTags:              c.server.maxTags(),
+			MaxNameLength:        todo.MaxNameLength,
+			MaxTemplateTasks:     todo.MaxTemplateTasks,
+			DefaultPageSize:      todo.DefaultPageSize,
+			MaxPageSize:          todo.MaxPageSize,
+			MaxAssigneeLength:    todo.MaxAssigneeLength,
+			MaxBodySize:          c.server.maxBodySize(),
+			MaxLinks:             todo.MaxLinks,
+			MaxLinkLength:        todo.MaxLinkLength,
+			MaxOpenTasks:         uint32(min(max(c.server.quotas.MaxOpenTasks, 0), math.MaxUint32)),
+		},
+		ApiVersions:    slices.Clone(apiVersions),
+		StorageBackend: c.server.backend,
+		ReadOnly:       c.server.readOnly.enabled.Load(),
+	}, nil
+}
+
+// maxBodySize returns the maximum size of the bodies of HTTP requests, or zero
+// if the HTTP server is disabled.
+func (s *Server) maxBodySize() uint32 {
+	if s.httpListener == nil {
+		return 0
+	}
+	return uint32(min(s.hardening.BodyLimit(), math.MaxUint32))
+}
+
+// maxTags returns the maximum number of tags of a task, which the quotas may
+// lower.
+func (s *Server) maxTags() uint32 {
+	if n := s.quotas.MaxTagsPerTask; n > 0 && n < todo.MaxTags {
+		return uint32(n)
+	}
+	return todo.MaxTags
+}
+
+// features returns the names of the optional features enabled on the server,
+// sorted. It must not be called before Serve has set up the storage.
+func (s *Server) features() []string {
+	var features []string
+	add := func(name string, enabled bool) {
+		if enabled {
+			features = append(features, name)
+		}
+	}
+	_, batch := s.tasks.(todo.BatchRepository)
+	_, sync := s.tasks.(todo.SyncRepository)
+	add(FeatureBatch, batch)
+	add(FeatureCompression, s.compressor != nil)
+	add(FeatureConfigReload, s.config != nil)
+	add(FeatureCORS, s.cors != nil && s.cors.Enabled())
+	add(FeatureFilters, s.filters != nil)
+	add(FeatureHooks, s.hooks != nil && s.hooks.Enabled())
+	add(FeatureIdempotency, s.idempotencyWindow > 0)
+	add(FeatureQuotas, s.quotas.Enabled())
+	add(FeatureRateLimit, s.limiter != nil)
+	add(FeatureReflection, s.reflection)
+	add(FeatureREST, s.httpListener != nil)
+	add(FeatureScheduledBackups, s.backups != nil)
+	add(FeatureStrictDependencies, s.strictDependencies)
+	add(FeatureSync, sync)
+	add(FeatureTemplates, s.templates != nil)
+	add(FeatureUniqueSummaries, s.uniqueSummaries)
+	// Without the REST API, webhooks can only be defined in the configuration
+	// file.
+	add(FeatureWebhooks, s.httpListener != nil || len(s.webhooks.List()) > 0)
+	add(FeatureWebUI, s.webUI && s.httpListener != nil)
+	slices.Sort(features)
+	return features
+}
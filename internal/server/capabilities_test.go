@@ -0,0 +1,53 @@
+package server
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+func TestGetCapabilities(t *testing.T) {
+	filters, err := todo.NewFilterRegistry("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(
+		WithStorage("memory", todo.NewInMemoryTaskDB()),
+		WithFilters(filters),
+		WithStrictDependencies(),
+		WithUniqueSummaries(),
+		WithIdempotencyWindow(time.Hour),
+		WithQuotas(todo.Quotas{MaxOpenTasks: 100, MaxTagsPerTask: 5}),
+		WithReadOnly(),
+	)
+	c := &controller{server: s}
+	resp, err := c.GetCapabilities(t.Context(), &todopb.GetCapabilitiesRequest{})
+	if err != nil {
+		t.Fatalf("GetCapabilities() failed: %v", err)
+	}
+	want := []string{
+		FeatureBatch, FeatureFilters, FeatureIdempotency, FeatureQuotas, FeatureStrictDependencies, FeatureSync,
+		FeatureUniqueSummaries,
+	}
+	if got := resp.GetFeatures(); !slices.Equal(got, want) {
+		t.Errorf("want features %v; got: %v", want, got)
+	}
+	if got := resp.GetLimits().GetMaxSummaryLength(); got != todo.MaxSummaryLength {
+		t.Errorf("want max summary length %d; got: %d", todo.MaxSummaryLength, got)
+	}
+	if got := resp.GetLimits().GetMaxTags(); got != 5 {
+		t.Errorf("want max tags 5 from the quotas; got: %d", got)
+	}
+	if got := resp.GetLimits().GetMaxOpenTasks(); got != 100 {
+		t.Errorf("want max open tasks 100; got: %d", got)
+	}
+	if got := resp.GetApiVersions(); !slices.Equal(got, []string{"v1", "v2"}) {
+		t.Errorf("want API versions v1 and v2; got: %v", got)
+	}
+	if resp.GetStorageBackend() != "memory" || !resp.GetReadOnly() {
+		t.Errorf("want read-only memory storage; got: %v", resp)
+	}
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// conditionalMiddleware answers conditional GET and HEAD requests with "304
+// Not Modified" if the response would carry the entity tag given in the
+// If-None-Match header or, without that header, would not have been modified
+// since the time given in the If-Modified-Since header. The response's ETag
+// and Last-Modified headers are set by the handler, e.g. the gateway, which
+// forwards them from the gRPC server.
+func conditionalMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditional := r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != ""
+		if !conditional || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(&notModifiedWriter{ResponseWriter: w, req: r}, r)
+	})
+}
+
+// notModifiedWriter is an [http.ResponseWriter] that replaces a successful
+// response with "304 Not Modified" if the response matches the conditions of
+// the request, and discards the response body in that case.
+type notModifiedWriter struct {
+	http.ResponseWriter
+	req         *http.Request
+	wroteHeader bool
+	discard     bool
+}
+
+func (w *notModifiedWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if code == http.StatusOK && notModified(w.req, w.Header()) {
+		h := w.Header()
+		h.Del("Content-Type")
+		h.Del("Content-Length")
+		w.discard = true
+		code = http.StatusNotModified
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *notModifiedWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.discard {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying response writer for [http.ResponseController].
+func (w *notModifiedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// notModified checks if a response with the specified headers matches the
+// conditions of the specified request, so the client's copy is still valid.
+// If-None-Match takes precedence over If-Modified-Since, see RFC 9110, section
+// 13.2.2.
+func notModified(r *http.Request, h http.Header) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		etag := h.Get("ETag")
+		return etag != "" && matchesETag(inm, etag)
+	}
+	lastModified, err := http.ParseTime(h.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	// HTTP dates have a resolution of one second.
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// matchesETag checks if the specified entity tag is in the specified list of
+// entity tags of an If-None-Match header, using the weak comparison.
+func matchesETag(list, etag string) bool {
+	if strings.TrimSpace(list) == "*" {
+		return true
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for candidate := range strings.SplitSeq(list, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
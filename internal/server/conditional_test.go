@@ -0,0 +1,76 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestConditionalMiddleware(t *testing.T) {
+	modifiedAt := time.Date(2025, 12, 24, 18, 0, 0, 500, time.UTC)
+	handler := conditionalMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("ETag", `"3-abc"`)
+		w.Header().Set("Last-Modified", modifiedAt.Format(http.TimeFormat))
+		w.Header().Set("Content-Type", "application/json")
+		// revive:disable-next-line:unhandled-error
+		w.Write([]byte(`{"tasks":[]}`))
+	}))
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		want    int
+	}{
+		{"Unconditional", http.MethodGet, nil, http.StatusOK},
+		{"MatchingETag", http.MethodGet, map[string]string{"If-None-Match": `"3-abc"`}, http.StatusNotModified},
+		{"WeakETag", http.MethodGet, map[string]string{"If-None-Match": `W/"3-abc"`}, http.StatusNotModified},
+		{"ETagInList", http.MethodGet, map[string]string{"If-None-Match": `"2-abc", "3-abc"`}, http.StatusNotModified},
+		{"AnyETag", http.MethodGet, map[string]string{"If-None-Match": "*"}, http.StatusNotModified},
+		{"OtherETag", http.MethodGet, map[string]string{"If-None-Match": `"2-abc"`}, http.StatusOK},
+		{"NotModifiedSince", http.MethodGet, map[string]string{
+			"If-Modified-Since": modifiedAt.Format(http.TimeFormat),
+		}, http.StatusNotModified},
+		{"ModifiedSince", http.MethodGet, map[string]string{
+			"If-Modified-Since": modifiedAt.Add(-time.Second).Format(http.TimeFormat),
+		}, http.StatusOK},
+		{"ETagTakesPrecedence", http.MethodGet, map[string]string{
+			"If-None-Match":     `"2-abc"`,
+			"If-Modified-Since": modifiedAt.Format(http.TimeFormat),
+		}, http.StatusOK},
+		{"NotGet", http.MethodPatch, map[string]string{"If-None-Match": `"3-abc"`}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/v1/tasks", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("want status %d; got: %d", tt.want, rec.Code)
+			}
+			if rec.Header().Get("ETag") != `"3-abc"` {
+				t.Errorf("want ETag header; got: %q", rec.Header().Get("ETag"))
+			}
+			if tt.want == http.StatusNotModified && (rec.Body.Len() > 0 || rec.Header().Get("Content-Type") != "") {
+				t.Errorf("want no content; got: %q (%s)", rec.Body.String(), rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestConditionalMiddlewareError(t *testing.T) {
+	handler := conditionalMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("ETag", `"3-abc"`)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/v1/tasks/42", nil)
+	req.Header.Set("If-None-Match", `"3-abc"`)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("want status %d; got: %d", http.StatusNotFound, rec.Code)
+	}
+}
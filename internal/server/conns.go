@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+)
+
+// activeCall describes a gRPC call that is currently being handled.
+type activeCall struct {
+	method  string
+	peer    string
+	started time.Time
+}
+
+// connTracker keeps track of the active HTTP connections and gRPC calls, so
+// the server can report which of them get cut when it is stopped forcibly.
+type connTracker struct {
+	mu    sync.Mutex
+	conns map[net.Conn]http.ConnState
+	calls map[*activeCall]struct{}
+}
+
+func newConnTracker() *connTracker {
+	return &connTracker{
+		conns: make(map[net.Conn]http.ConnState),
+		calls: make(map[*activeCall]struct{}),
+	}
+}
+
+// trackConn can be used as [http.Server.ConnState] hook.
+func (t *connTracker) trackConn(conn net.Conn, state http.ConnState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch state {
+	case http.StateClosed, http.StateHijacked:
+		delete(t.conns, conn)
+	default:
+		t.conns[conn] = state
+	}
+}
+
+func (t *connTracker) beginCall(ctx context.Context, method string) func() {
+	call := &activeCall{
+		method:  method,
+		started: time.Now(),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		call.peer = p.Addr.String()
+	}
+	t.mu.Lock()
+	t.calls[call] = struct{}{}
+	t.mu.Unlock()
+	return func() {
+		t.mu.Lock()
+		delete(t.calls, call)
+		t.mu.Unlock()
+	}
+}
+
+func (t *connTracker) unaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		defer t.beginCall(ctx, info.FullMethod)()
+		return handler(ctx, req)
+	}
+}
+
+func (t *connTracker) streamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		defer t.beginCall(ss.Context(), info.FullMethod)()
+		return handler(srv, ss)
+	}
+}
+
+// logActive logs all HTTP connections and gRPC calls that are still active
+// with the specified logger.
+func (t *connTracker) logActive(l *slog.Logger) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	for conn, state := range t.conns {
+		l.Warn("cutting HTTP connection", "remote_addr", conn.RemoteAddr().String(), "state", state.String())
+	}
+	for call := range t.calls {
+		l.Warn("cutting gRPC call", "method", call.method, "peer", call.peer, "duration", now.Sub(call.started))
+	}
+}
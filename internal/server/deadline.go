@@ -0,0 +1,29 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// deadlineLimiter limits the deadline of unary RPCs, so a hung storage backend
+// cannot block a request forever, even if the client didn't set a deadline.
+type deadlineLimiter struct {
+	max time.Duration
+}
+
+func (l *deadlineLimiter) unaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if l.max <= 0 {
+			return handler(ctx, req)
+		}
+		deadline := time.Now().Add(l.max)
+		if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+			return handler(ctx, req)
+		}
+		ctx, cancel := context.WithDeadline(ctx, deadline)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
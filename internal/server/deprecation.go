@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/logging"
+)
+
+// deprecationMetadataKey is the outgoing gRPC header metadata key that marks
+// responses of deprecated methods. The gateway forwards it as Deprecation
+// header, see RFC 9745.
+const deprecationMetadataKey = "deprecation"
+
+// v1DeprecatedAt is the time when the task RPCs of todo.v1.TodoService were
+// deprecated in favor of todo.v2.TaskService.
+var v1DeprecatedAt = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+
+// deprecatedMethods holds the full names of the gRPC methods that have a
+// successor in a newer version of the API.
+var deprecatedMethods = map[string]bool{
+	todopb.TodoService_CreateTask_FullMethodName: true,
+	todopb.TodoService_ListTasks_FullMethodName:  true,
+	todopb.TodoService_GetTask_FullMethodName:    true,
+	todopb.TodoService_UpdateTask_FullMethodName: true,
+	todopb.TodoService_DeleteTask_FullMethodName: true,
+}
+
+// deprecationUnaryInterceptor marks the responses of deprecated unary RPCs
+// with the time of their deprecation as structured field date, e.g.
+// "@1792281600", so clients can notice that they should migrate.
+func deprecationUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if deprecatedMethods[info.FullMethod] {
+			md := metadata.Pairs(deprecationMetadataKey, "@"+strconv.FormatInt(v1DeprecatedAt.Unix(), 10))
+			if err := grpc.SetHeader(ctx, md); err != nil {
+				logging.FromContext(ctx).WarnContext(ctx, "cannot send deprecation header", "cause", err)
+			}
+		}
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/logging"
+	"github.com/mwopitz/todo-daemon/internal/rest"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// eventStreamHeartbeat is the interval of the comments sent on idle event
+// streams, so clients and proxies don't consider the connection dead.
+const eventStreamHeartbeat = 15 * time.Second
+
+// eventReset is the type of the event sent to a client resuming an event
+// stream if some of the events it missed are no longer available. The client
+// should fetch the tasks again.
+const eventReset = "reset"
+
+// eventFilter selects the events sent on an event stream.
+type eventFilter struct {
+	types     []todo.EventType
+	tasks     []string
+	assignees []string
+}
+
+// newEventFilter creates a filter from the "type", "task", and "assignee"
+// query parameters of the specified request. All of them can be repeated; an
+// empty filter selects all events.
+func newEventFilter(r *http.Request) (*eventFilter, error) {
+	query := r.URL.Query()
+	f := &eventFilter{tasks: query["task"], assignees: query["assignee"]}
+	for _, t := range query["type"] {
+		eventType := todo.EventType(t)
+		if !eventType.IsValid() {
+			return nil, fmt.Errorf("invalid event type: '%s'", t)
+		}
+		f.types = append(f.types, eventType)
+	}
+	return f, nil
+}
+
+func (f *eventFilter) matches(e *todo.Event) bool {
+	return (len(f.types) == 0 || slices.Contains(f.types, e.Type)) &&
+		(len(f.tasks) == 0 || slices.Contains(f.tasks, e.Task.ID)) &&
+		(len(f.assignees) == 0 || slices.Contains(f.assignees, e.Task.Assignee))
+}
+
+// newEventStreamHandler creates an HTTP handler that streams the changes to
+// the tasks as Server-Sent Events, i.e. the same events as the WatchTasks RPC.
+// Clients can resume a stream by sending the ID of the last event they
+// received in the Last-Event-ID header. Each stream buffers the specified
+// number of events; a client that falls further behind is handled according to
+// the overflow policy. The streams end when done is closed.
+func newEventStreamHandler(
+	bus *todo.EventBus,
+	bufferSize int,
+	overflow todo.OverflowPolicy,
+	done <-chan struct{},
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := newEventFilter(r)
+		if err != nil {
+			rest.WriteError(w, r, http.StatusBadRequest, "%v", err)
+			return
+		}
+		var events <-chan todo.Event
+		var unsubscribe func()
+		complete := true
+		if lastID := r.Header.Get("Last-Event-ID"); lastID != "" {
+			seq, err := strconv.ParseUint(lastID, 10, 64)
+			if err != nil {
+				rest.WriteError(w, r, http.StatusBadRequest, "invalid Last-Event-ID: '%s'", lastID)
+				return
+			}
+			events, unsubscribe, complete = bus.SubscribeAfter(seq, bufferSize, todo.WithOverflowPolicy(overflow))
+		} else {
+			events, unsubscribe = bus.Subscribe(bufferSize, todo.WithOverflowPolicy(overflow))
+		}
+		defer unsubscribe()
+
+		// The stream is long-lived, so the write timeout of the server must not
+		// apply to it.
+		rc := http.NewResponseController(w)
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+			logging.FromContext(r.Context()).WarnContext(r.Context(), "cannot disable write timeout for event stream",
+				"cause", err)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		if !complete {
+			// revive:disable-next-line:unhandled-error
+			fmt.Fprintf(w, "event: %s\ndata: {}\n\n", eventReset)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(eventStreamHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-done:
+				return
+			case <-heartbeat.C:
+				if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+					return
+				}
+			case e, ok := <-events:
+				if !ok {
+					return
+				}
+				if !filter.matches(&e) {
+					continue
+				}
+				if err := writeEvent(w, &e); err != nil {
+					logging.FromContext(r.Context()).DebugContext(r.Context(), "cannot write event", "cause", err)
+					return
+				}
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeEvent writes the specified event in the Server-Sent Events format.
+func writeEvent(w io.Writer, e *todo.Event) error {
+	data, err := json.Marshal(rest.NewEvent(e))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.Seq, e.Type, data)
+	return err
+}
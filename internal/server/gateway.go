@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/requestid"
+	"github.com/mwopitz/todo-daemon/internal/rest"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+	"github.com/mwopitz/todo-daemon/internal/transport"
+)
+
+// gatewayOptions returns the options of the gRPC gateway's HTTP mux.
+func gatewayOptions() []runtime.ServeMuxOption {
+	return []runtime.ServeMuxOption{
+		runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+		runtime.WithMetadata(requestIDMetadata),
+		runtime.WithErrorHandler(errorHandler),
+		runtime.WithRoutingErrorHandler(routingErrorHandler),
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.HTTPBodyMarshaler{
+			Marshaler: &runtime.JSONPb{
+				MarshalOptions: protojson.MarshalOptions{EmitUnpopulated: true},
+				// Unknown fields are rejected like in the natively
+				// implemented endpoints, so that typos don't go unnoticed.
+				UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: false},
+			},
+		}),
+	}
+}
+
+// gatewayDialOptions returns the options of the gRPC gateway's connection to
+// the gRPC server listening on the specified address. The gateway's messages
+// are limited like those of other clients, so its limits mirror the server's.
+func (s *Server) gatewayDialOptions(addr transport.Address) []grpc.DialOption {
+	return append(client.DialOptions(addr), grpc.WithDefaultCallOptions(
+		grpc.MaxCallRecvMsgSize(s.maxSendMsgSize),
+		grpc.MaxCallSendMsgSize(s.maxRecvMsgSize),
+	))
+}
+
+// incomingHeaderMatcher forwards the Idempotency-Key header of requests to
+// create a task as idempotency key. Other headers are forwarded like with the
+// gateway's default header matcher.
+func incomingHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, "Idempotency-Key") {
+		return todo.IdempotencyKeyMetadataKey, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// outgoingHeaderMatcher forwards the entity tag and modification time of tasks
+// as ETag and Last-Modified headers, and the deprecation of methods as
+// Deprecation header. The request ID is not forwarded, because the HTTP
+// server already sends it.
+func outgoingHeaderMatcher(key string) (string, bool) {
+	switch {
+	case strings.EqualFold(key, todo.ETagMetadataKey):
+		return "ETag", true
+	case strings.EqualFold(key, todo.LastModifiedMetadataKey):
+		return "Last-Modified", true
+	case strings.EqualFold(key, deprecationMetadataKey):
+		return "Deprecation", true
+	case strings.EqualFold(key, requestid.MetadataKey):
+		return "", false
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// requestIDMetadata forwards the ID of the HTTP request to the gRPC server, so
+// the log messages of both servers can be correlated.
+func requestIDMetadata(_ context.Context, r *http.Request) metadata.MD {
+	id := requestid.FromContext(r.Context())
+	if id == "" {
+		return nil
+	}
+	return metadata.Pairs(requestid.MetadataKey, id)
+}
+
+// errorHandler writes the errors of the gateway as RFC 7807 problems. The HTTP
+// status codes are the same as with the gateway's default error handler,
+// except that failed preconditions of updates, i.e. ABORTED errors, result in
+// "412 Precondition Failed" instead of "409 Conflict", and exceeded quotas,
+// i.e. RESOURCE_EXHAUSTED errors, result in "413 Request Entity Too Large"
+// instead of "429 Too Many Requests", which would invite clients to retry.
+// The field violations of invalid requests are listed as invalid parameters.
+func errorHandler(
+	_ context.Context,
+	_ *runtime.ServeMux,
+	_ runtime.Marshaler,
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+	rest.WriteProblem(w, r, problemFromError(err))
+}
+
+// routingErrorHandler writes the routing errors of the gateway as RFC 7807
+// problems. Unlike the gateway's default routing error handler, which converts
+// them into gRPC errors first, it keeps their HTTP status codes, so that a
+// wrong method results in "405 Method Not Allowed" instead of "501 Not
+// Implemented".
+func routingErrorHandler(
+	_ context.Context,
+	_ *runtime.ServeMux,
+	_ runtime.Marshaler,
+	w http.ResponseWriter,
+	r *http.Request,
+	httpStatus int,
+) {
+	switch httpStatus {
+	case http.StatusNotFound:
+		rest.WriteError(w, r, httpStatus, "no such endpoint")
+	case http.StatusMethodNotAllowed:
+		rest.WriteError(w, r, httpStatus, "method %s is not allowed", r.Method)
+	default:
+		rest.WriteError(w, r, httpStatus, "%s", http.StatusText(httpStatus))
+	}
+}
+
+// problemFromError maps the specified gRPC or gateway error to a problem.
+func problemFromError(err error) *rest.Problem {
+	var httpErr *runtime.HTTPStatusError
+	if errors.As(err, &httpErr) {
+		return rest.NewProblem(httpErr.HTTPStatus, status.Convert(httpErr.Err).Message())
+	}
+	st := status.Convert(err)
+	code := runtime.HTTPStatusFromCode(st.Code())
+	switch st.Code() {
+	case codes.Aborted:
+		code = http.StatusPreconditionFailed
+	case codes.ResourceExhausted:
+		code = http.StatusRequestEntityTooLarge
+	}
+	p := rest.NewProblem(code, st.Message())
+	for _, detail := range st.Details() {
+		if br, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				p.InvalidParams = append(p.InvalidParams, rest.InvalidParam{Name: v.GetField(), Reason: v.GetDescription()})
+			}
+		}
+	}
+	return p
+}
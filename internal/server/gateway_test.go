@@ -0,0 +1,116 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/mwopitz/todo-daemon/internal/rest"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+func TestProblemFromError(t *testing.T) {
+	invalid, err := status.New(codes.InvalidArgument, "invalid task").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "task.summary", Description: "must not be empty"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantType   string
+		wantDetail string
+	}{
+		{"NotFound", status.Error(codes.NotFound, "no such task: '3'"), http.StatusNotFound, rest.ProblemNotFound, "no such task: '3'"},
+		{"InvalidArgument", invalid.Err(), http.StatusBadRequest, rest.ProblemInvalidRequest, "invalid task"},
+		{"Aborted", status.Error(codes.Aborted, "task was modified"), http.StatusPreconditionFailed, rest.ProblemPreconditionFailed, "task was modified"},
+		{"ResourceExhausted", status.Error(codes.ResourceExhausted, "quota exceeded"), http.StatusRequestEntityTooLarge, rest.ProblemTooLarge, "quota exceeded"},
+		{"Unavailable", status.Error(codes.Unavailable, "read-only"), http.StatusServiceUnavailable, rest.ProblemUnavailable, "read-only"},
+		{"Internal", errors.New("boom"), http.StatusInternalServerError, rest.ProblemInternal, "boom"},
+		{
+			"Routing",
+			&runtime.HTTPStatusError{HTTPStatus: http.StatusMethodNotAllowed, Err: status.Error(codes.Unimplemented, "Method Not Allowed")},
+			http.StatusMethodNotAllowed, rest.ProblemMethodNotAllowed, "Method Not Allowed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := problemFromError(tt.err)
+			if p.Status != tt.wantStatus || p.Type != tt.wantType || p.Detail != tt.wantDetail {
+				t.Errorf("want problem %d %s %q; got: %d %s %q",
+					tt.wantStatus, tt.wantType, tt.wantDetail, p.Status, p.Type, p.Detail)
+			}
+		})
+	}
+
+	p := problemFromError(invalid.Err())
+	want := rest.InvalidParam{Name: "task.summary", Reason: "must not be empty"}
+	if len(p.InvalidParams) != 1 || p.InvalidParams[0] != want {
+		t.Errorf("want invalid params [%v]; got: %v", want, p.InvalidParams)
+	}
+}
+
+func TestErrorHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/3", nil)
+	errorHandler(t.Context(), nil, nil, rec, req, status.Error(codes.NotFound, "no such task: '3'"))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("want status %d; got: %d", http.StatusNotFound, rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != rest.ProblemContentType {
+		t.Errorf("want content type %s; got: %s", rest.ProblemContentType, got)
+	}
+	var p rest.Problem
+	if err := json.NewDecoder(rec.Body).Decode(&p); err != nil {
+		t.Fatalf("cannot decode problem: %v", err)
+	}
+	if p.Type != rest.ProblemNotFound || p.Title != "Not Found" || p.Detail != "no such task: '3'" {
+		t.Errorf("want not-found problem; got: %+v", p)
+	}
+}
+
+func TestRoutingErrorHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/stats", nil)
+	routingErrorHandler(t.Context(), nil, nil, rec, req, http.StatusMethodNotAllowed)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("want status %d; got: %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+	var p rest.Problem
+	if err := json.NewDecoder(rec.Body).Decode(&p); err != nil {
+		t.Fatalf("cannot decode problem: %v", err)
+	}
+	if p.Type != rest.ProblemMethodNotAllowed || p.Detail != "method PUT is not allowed" {
+		t.Errorf("want method-not-allowed problem; got: %+v", p)
+	}
+}
+
+func TestIncomingHeaderMatcher(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+		wantOK bool
+	}{
+		{"Idempotency-Key", todo.IdempotencyKeyMetadataKey, true},
+		{"idempotency-key", todo.IdempotencyKeyMetadataKey, true},
+		{"Accept", runtime.MetadataPrefix + "Accept", true},
+		{"X-Custom", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := incomingHeaderMatcher(tt.header)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("incomingHeaderMatcher(%q) = %q, %t; want: %q, %t", tt.header, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
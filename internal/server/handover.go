@@ -0,0 +1,186 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"os"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/handover"
+	"github.com/mwopitz/todo-daemon/internal/logging"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// errHandedOver is the cause of the streaming RPCs being canceled when the
+// server hands over to a new instance.
+var errHandedOver = errors.New("server handed over to a new instance")
+
+// detachableListener is a listener that can stop accepting connections without
+// closing the socket, which has been handed over to another process. Once
+// detached, Accept blocks until the listener is closed, so the gRPC and HTTP
+// servers keep serving their active connections until they are stopped.
+type detachableListener struct {
+	net.Listener
+	detached  chan struct{}
+	closed    chan struct{}
+	detachErr error
+	detach    sync.Once
+	close     sync.Once
+}
+
+func newDetachableListener(l net.Listener) *detachableListener {
+	return &detachableListener{
+		Listener: l,
+		detached: make(chan struct{}),
+		closed:   make(chan struct{}),
+	}
+}
+
+func (l *detachableListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		return conn, nil
+	}
+	select {
+	case <-l.detached:
+		<-l.closed
+		return nil, net.ErrClosed
+	default:
+		return nil, err
+	}
+}
+
+// Detach stops accepting connections. It closes the listener's file
+// descriptor, but keeps the socket file of a Unix domain socket, which is still
+// used by the process the socket has been handed over to.
+func (l *detachableListener) Detach() error {
+	l.detach.Do(func() {
+		if ul, ok := l.Listener.(*net.UnixListener); ok {
+			ul.SetUnlinkOnClose(false)
+		}
+		close(l.detached)
+		l.detachErr = l.Listener.Close()
+	})
+	return l.detachErr
+}
+
+func (l *detachableListener) Close() error {
+	var err error
+	l.close.Do(func() {
+		close(l.closed)
+		select {
+		case <-l.detached:
+		default:
+			err = l.Listener.Close()
+		}
+	})
+	return err
+}
+
+// controller adds the Takeover RPC, which needs access to the server's
+// listeners, to the RPCs handled by [todo.Controller].
+type controller struct {
+	*todo.Controller
+	server *Server
+}
+
+// Takeover handles gRPC requests to hand the server's listeners over to a new
+// instance.
+func (c *controller) Takeover(ctx context.Context, req *todopb.TakeoverRequest) (*todopb.TakeoverResponse, error) {
+	if req.GetHandoverAddress() == "" {
+		return nil, status.Error(codes.InvalidArgument, "no handover address specified")
+	}
+	archive, err := c.server.handOver(ctx, req.GetHandoverAddress())
+	if err != nil {
+		return nil, err
+	}
+	pid := os.Getpid()
+	if pid < 0 || pid > math.MaxUint32 {
+		return nil, status.Errorf(codes.Internal, "invalid server PID: %d", pid)
+	}
+	return &todopb.TakeoverResponse{Archive: archive, Pid: uint32(pid)}, nil
+}
+
+// handOver switches the server to read-only mode, sends its listeners to the
+// receiver listening on the specified Unix domain socket, and stops accepting
+// connections. It returns a snapshot of the tasks, which don't change anymore
+// in read-only mode. If the listeners cannot be sent, the server keeps
+// running as before.
+//
+// Once the listeners are sent, the repository is closed if it is an
+// [io.Closer], e.g. a store of package storage, so it has written its pending
+// changes and stops writing to its files before the new instance, which
+// receives the snapshot only afterwards, opens them.
+func (s *Server) handOver(ctx context.Context, path string) ([]byte, error) {
+	s.handoverMu.Lock()
+	defer s.handoverMu.Unlock()
+	select {
+	case <-s.handedOver:
+		return nil, status.Error(codes.FailedPrecondition, "server already handed over to a new instance")
+	default:
+	}
+
+	wasReadOnly := s.readOnly.enabled.Swap(true)
+	restore := func() {
+		if !wasReadOnly {
+			s.readOnly.enabled.Store(false)
+		}
+	}
+	// The snapshot includes the trash, like the backups.
+	tasks, err := s.db.List(ctx, &todo.ListOptions{IncludeDeleted: true})
+	if err != nil {
+		restore()
+		return nil, status.Errorf(codes.Internal, "cannot retrieve tasks: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := todo.WriteSnapshot(&buf, todo.NewSnapshot(tasks)); err != nil {
+		restore()
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	listeners := handover.Listeners{GRPC: s.grpcListener.Listener}
+	if s.httpListener != nil {
+		listeners.HTTP = s.httpListener.Listener
+	}
+	if err := handover.Send(ctx, path, listeners); err != nil {
+		restore()
+		if errors.Is(err, handover.ErrUnsupported) {
+			return nil, status.Error(codes.Unimplemented, err.Error())
+		}
+		return nil, status.Errorf(codes.Unavailable, "cannot hand over listeners: %v", err)
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "handed over listeners to new instance", "tasks", len(tasks))
+
+	for _, l := range []*detachableListener{s.grpcListener, s.httpListener} {
+		if l == nil {
+			continue
+		}
+		if err := l.Detach(); err != nil {
+			logging.FromContext(ctx).WarnContext(ctx, "cannot detach listener", "addr", l.Addr().String(), "cause", err)
+		}
+	}
+	s.streams.cancelAll(errHandedOver)
+	if closer, ok := s.tasks.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			// The snapshot holds the changes anyway.
+			logging.FromContext(ctx).WarnContext(ctx, "cannot close storage", "cause", err)
+		}
+	}
+	close(s.handedOver)
+	return buf.Bytes(), nil
+}
+
+// HandedOver returns a channel that is closed once the server has handed its
+// listeners over to a new instance. The server should then be stopped with
+// [Server.StopGracefully], which lets it finish its active requests.
+func (s *Server) HandedOver() <-chan struct{} {
+	return s.handedOver
+}
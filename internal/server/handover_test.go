@@ -0,0 +1,112 @@
+//go:build !windows
+
+package server
+
+import (
+	"bytes"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/handover"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+	"github.com/mwopitz/todo-daemon/internal/transport"
+)
+
+// closingStore is a repository that records whether it has been closed.
+type closingStore struct {
+	*todo.InMemoryTaskDB
+	closed atomic.Bool
+}
+
+func (s *closingStore) Close() error {
+	s.closed.Store(true)
+	return nil
+}
+
+func TestTakeover(t *testing.T) {
+	dir := t.TempDir()
+	addr := transport.Address{Scheme: transport.SchemeUnix, Path: filepath.Join(dir, "todo-daemon.sock")}
+
+	store := &closingStore{InMemoryTaskDB: todo.NewInMemoryTaskDB()}
+	old := New(WithHTTPListenAddress(HTTPListenAddress{}), WithStorage("test", store))
+	oldDone := make(chan error, 1)
+	go func() { oldDone <- old.Serve(addr) }()
+
+	c, err := client.New(addr.String(), client.WithTimeout(5*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := c.WaitReady(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	created, err := c.CreateTask(t.Context(), &todopb.NewTask{Summary: "Survive the upgrade"})
+	if err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+
+	receiver, err := handover.Listen(filepath.Join(dir, "handover.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer receiver.Close()
+	received := make(chan handover.Listeners, 1)
+	go func() {
+		ls, err := receiver.Receive(t.Context())
+		if err != nil {
+			t.Errorf("cannot receive listeners: %v", err)
+		}
+		received <- ls
+	}()
+	resp, err := c.Takeover(t.Context(), receiver.Path())
+	if err != nil {
+		t.Fatalf("Takeover() failed: %v", err)
+	}
+	// The new server must not open the storage while the old one may still
+	// write to it.
+	if !store.closed.Load() {
+		t.Error("want old server to close its storage before returning the snapshot")
+	}
+	ls := <-received
+	if ls.HTTP != nil {
+		t.Errorf("want no HTTP listener; got: %s", ls.HTTP.Addr())
+	}
+
+	select {
+	case <-old.HandedOver():
+	default:
+		t.Error("want old server to report the handover")
+	}
+	if _, err := c.CreateTask(t.Context(), &todopb.NewTask{Summary: "Get lost"}); err == nil {
+		t.Error("want old server to reject changes after the handover")
+	}
+	if err := old.StopGracefully(time.Second); err != nil {
+		t.Errorf("cannot stop old server: %v", err)
+	}
+	<-oldDone
+
+	snapshot, err := todo.ReadSnapshot(bytes.NewReader(resp.GetArchive()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := New(WithListeners(ls), WithSnapshot(snapshot))
+	go func() { _ = srv.Serve(addr) }()
+	defer func() { _ = srv.StopGracefully(time.Second) }()
+
+	// The client connects to the same socket, which is now served by the new
+	// server.
+	task, err := c.ResolveTask(t.Context(), created.GetId())
+	if err != nil {
+		t.Fatalf("want task to be handed over; got: %v", err)
+	}
+	if task.GetSummary() != created.GetSummary() {
+		t.Errorf("want summary: %s; got: %s", created.GetSummary(), task.GetSummary())
+	}
+	if _, err := c.CreateTask(t.Context(), &todopb.NewTask{Summary: "Welcome"}); err != nil {
+		t.Errorf("want new server to accept changes; got: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/mwopitz/todo-daemon/internal/rest"
+)
+
+// registerHealthHandlers registers the liveness probe at /healthz, which
+// succeeds as long as the HTTP server responds, and the readiness probe at
+// /readyz, which succeeds while the specified gRPC health server reports that
+// the server is serving.
+func registerHealthHandlers(mux *http.ServeMux, hs *health.Server) {
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeHealthy(w)
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		resp, err := hs.Check(r.Context(), &healthpb.HealthCheckRequest{})
+		if err != nil || resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			rest.WriteError(w, r, http.StatusServiceUnavailable, "server is not ready")
+			return
+		}
+		writeHealthy(w)
+	})
+}
+
+// writeHealthy writes the response of a successful probe.
+func writeHealthy(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	// revive:disable-next-line:unhandled-error
+	fmt.Fprintln(w, "ok")
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealthHandlers(t *testing.T) {
+	hs := health.NewServer()
+	mux := http.NewServeMux()
+	registerHealthHandlers(mux, hs)
+	probe := func(path string) int {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	if got := probe("/healthz"); got != http.StatusOK {
+		t.Errorf("want /healthz status %d while starting; got: %d", http.StatusOK, got)
+	}
+	if got := probe("/readyz"); got != http.StatusServiceUnavailable {
+		t.Errorf("want /readyz status %d while starting; got: %d", http.StatusServiceUnavailable, got)
+	}
+
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	if got := probe("/readyz"); got != http.StatusOK {
+		t.Errorf("want /readyz status %d while serving; got: %d", http.StatusOK, got)
+	}
+
+	hs.Shutdown()
+	if got := probe("/readyz"); got != http.StatusServiceUnavailable {
+		t.Errorf("want /readyz status %d after shutdown; got: %d", http.StatusServiceUnavailable, got)
+	}
+}
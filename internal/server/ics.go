@@ -0,0 +1,50 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/mwopitz/todo-daemon/internal/ical"
+	"github.com/mwopitz/todo-daemon/internal/logging"
+	"github.com/mwopitz/todo-daemon/internal/rest"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// newICSHandler creates an HTTP handler that serves the tasks with a due date
+// as iCalendar feed, so calendar applications can subscribe to the to-do list.
+func newICSHandler(tasks todo.TaskRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		all, err := tasks.List(r.Context(), &todo.ListOptions{})
+		if err != nil {
+			rest.WriteError(w, r, http.StatusInternalServerError, "cannot retrieve tasks: %v", err)
+			return
+		}
+		cal := &ical.Calendar{
+			ProductID: "-//mwopitz//To-do Daemon//EN",
+			Name:      "To-do Daemon",
+		}
+		for i := range all {
+			t := &all[i]
+			if t.DueAt.IsZero() {
+				continue
+			}
+			vtodo := ical.Todo{
+				UID:          t.ID + "@todo-daemon",
+				Summary:      t.Summary,
+				Description:  t.Description,
+				Status:       ical.StatusNeedsAction,
+				Created:      t.CreatedAt,
+				LastModified: t.UpdatedAt,
+				Due:          t.DueAt,
+			}
+			if !t.CompletedAt.IsZero() {
+				vtodo.Status = ical.StatusCompleted
+				vtodo.Completed = t.CompletedAt
+			}
+			cal.Todos = append(cal.Todos, vtodo)
+		}
+		w.Header().Set("Content-Type", ical.ContentType)
+		if err := ical.NewEncoder(w).Encode(cal); err != nil {
+			logging.FromContext(r.Context()).WarnContext(r.Context(), "cannot write iCalendar feed", "cause", err)
+		}
+	}
+}
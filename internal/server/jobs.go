@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"math"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/janitor"
+)
+
+// ListJobs handles gRPC requests to list the server's background jobs.
+func (c *controller) ListJobs(_ context.Context, _ *todopb.ListJobsRequest) (*todopb.ListJobsResponse, error) {
+	jobs := c.server.janitor.Jobs()
+	resp := &todopb.ListJobsResponse{Jobs: make([]*todopb.BackgroundJob, 0, len(jobs))}
+	for _, job := range jobs {
+		resp.Jobs = append(resp.Jobs, backgroundJobToPB(job))
+	}
+	return resp, nil
+}
+
+func backgroundJobToPB(job janitor.Status) *todopb.BackgroundJob {
+	pb := &todopb.BackgroundJob{
+		Name:          job.Name,
+		Interval:      durationpb.New(job.Interval),
+		Running:       job.Running,
+		Runs:          count32(job.Runs),
+		Failures:      count32(job.Failures),
+		TotalDuration: durationpb.New(job.TotalDuration),
+		LastDuration:  durationpb.New(job.LastDuration),
+	}
+	if !job.LastRun.IsZero() {
+		pb.LastRunAt = timestamppb.New(job.LastRun)
+	}
+	if job.LastError != nil {
+		pb.LastError = job.LastError.Error()
+	}
+	if !job.NextRun.IsZero() {
+		pb.NextRunAt = timestamppb.New(job.NextRun)
+	}
+	return pb
+}
+
+// count32 converts the specified number of runs into a uint32, capping it at
+// the largest uint32.
+func count32(n int) uint32 {
+	return uint32(min(max(n, 0), math.MaxUint32))
+}
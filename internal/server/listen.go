@@ -0,0 +1,88 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mwopitz/todo-daemon/internal/transport"
+)
+
+// HTTPListenOff is the HTTP listen address that disables the HTTP server.
+const HTTPListenOff = "off"
+
+// HTTPListenAddress is the address that the HTTP server listens on: either a
+// TCP address or a Unix domain socket. The zero value disables the HTTP server.
+type HTTPListenAddress struct {
+	// Network is either "tcp" or "unix", or empty if the HTTP server is
+	// disabled.
+	Network string
+	// Address is the host and port of a TCP address, e.g. "localhost:8080",
+	// or the path of a Unix domain socket.
+	Address string
+}
+
+// ParseHTTPListenAddress parses the specified HTTP listen address, which is
+// either a TCP address like "localhost:8080" or ":8080", a Unix domain socket
+// address like "unix:///run/user/1000/todo-daemon-http.sock", or
+// [HTTPListenOff]. Port 0 chooses a random free port.
+func ParseHTTPListenAddress(s string) (HTTPListenAddress, error) {
+	if s == HTTPListenOff {
+		return HTTPListenAddress{}, nil
+	}
+	if strings.HasPrefix(s, transport.SchemeUnix+"://") {
+		addr, err := transport.ParseAddress(s)
+		if err != nil {
+			return HTTPListenAddress{}, err
+		}
+		return HTTPListenAddress{Network: "unix", Address: addr.Path}, nil
+	}
+	_, port, err := net.SplitHostPort(s)
+	if err != nil {
+		return HTTPListenAddress{}, fmt.Errorf("invalid HTTP listen address '%s': %w", s, err)
+	}
+	if n, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return HTTPListenAddress{}, fmt.Errorf("invalid HTTP listen address '%s': invalid port '%s'", s, port)
+	} else if n == 0 && port != "0" {
+		return HTTPListenAddress{}, fmt.Errorf("invalid HTTP listen address '%s': invalid port '%s'", s, port)
+	}
+	return HTTPListenAddress{Network: "tcp", Address: s}, nil
+}
+
+// Enabled checks if the address enables the HTTP server.
+func (a HTTPListenAddress) Enabled() bool {
+	return a.Network != ""
+}
+
+// String returns the address in the form accepted by
+// [ParseHTTPListenAddress].
+func (a HTTPListenAddress) String() string {
+	switch a.Network {
+	case "":
+		return HTTPListenOff
+	case "unix":
+		return transport.Address{Scheme: transport.SchemeUnix, Path: a.Address}.String()
+	default:
+		return a.Address
+	}
+}
+
+// httpAddresses returns the address that the specified listener of the HTTP
+// server listens on, and the base URL of the REST API. The host of the base URL
+// of a Unix domain socket is just "localhost", e.g. for curl's --unix-socket.
+func httpAddresses(l net.Listener) (string, string) {
+	network := l.Addr().Network()
+	host := l.Addr().String()
+	listening := HTTPListenAddress{Network: network, Address: host}.String()
+	if network == "unix" {
+		host = "localhost"
+	}
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+		Path:   "/api",
+	}
+	return listening, u.String()
+}
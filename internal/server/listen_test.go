@@ -0,0 +1,31 @@
+package server
+
+import "testing"
+
+func TestParseHTTPListenAddress(t *testing.T) {
+	tests := []struct {
+		in   string
+		want HTTPListenAddress
+	}{
+		{"off", HTTPListenAddress{}},
+		{"localhost:8080", HTTPListenAddress{Network: "tcp", Address: "localhost:8080"}},
+		{":0", HTTPListenAddress{Network: "tcp", Address: ":0"}},
+		{"[::1]:80", HTTPListenAddress{Network: "tcp", Address: "[::1]:80"}},
+		{"unix:///tmp/http.sock", HTTPListenAddress{Network: "unix", Address: "/tmp/http.sock"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseHTTPListenAddress(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: want: %+v; got: %+v, %v", tt.in, tt.want, got, err)
+		}
+		if s := got.String(); s != tt.in {
+			t.Errorf("%s: want string: %s; got: %s", tt.in, tt.in, s)
+		}
+	}
+
+	for _, in := range []string{"", "localhost", "localhost:http", "localhost:65536", "localhost:00", "unix://"} {
+		if _, err := ParseHTTPListenAddress(in); err == nil {
+			t.Errorf("%s: want error", in)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+
+	middleware "github.com/grpc-ecosystem/go-grpc-middleware/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	"github.com/mwopitz/todo-daemon/internal/logging"
+	"github.com/mwopitz/todo-daemon/internal/requestid"
+)
+
+// newRPCLogger derives the logger of an RPC from the specified logger and
+// puts it into the specified context, which must hold the RPC's request ID
+// already.
+func newRPCLogger(ctx context.Context, l *slog.Logger, method string) context.Context {
+	var addr string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		addr = p.Addr.String()
+	}
+	l = logging.NewRequestLogger(l, logging.ComponentGRPC, requestid.FromContext(ctx), addr, method)
+	return logging.NewContext(ctx, l)
+}
+
+// loggerUnaryInterceptor puts a logger derived from the specified logger into
+// the context of each unary RPC, see [logging.FromContext].
+func loggerUnaryInterceptor(l *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(newRPCLogger(ctx, l, info.FullMethod), req)
+	}
+}
+
+// loggerStreamInterceptor puts a logger derived from the specified logger into
+// the context of each streaming RPC, see [logging.FromContext].
+func loggerStreamInterceptor(l *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		wrapped := middleware.WrapServerStream(ss)
+		wrapped.WrappedContext = newRPCLogger(ss.Context(), l, info.FullMethod)
+		return handler(srv, wrapped)
+	}
+}
+
+// logger returns the logger of the messages about the server.
+func (s *Server) logger() *slog.Logger {
+	return logging.WithComponent(s.log, logging.ComponentServer)
+}
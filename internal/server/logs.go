@@ -0,0 +1,78 @@
+package server
+
+import (
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/logging"
+)
+
+// TailLogs handles gRPC requests to stream the server's recent log messages
+// and, if requested, the messages logged afterwards. The stream ends when the
+// client cancels the request.
+func (c *controller) TailLogs(req *todopb.TailLogsRequest, stream grpc.ServerStreamingServer[todopb.LogEntry]) error {
+	if c.server.logs == nil {
+		return status.Error(codes.Unimplemented, "the server keeps no log messages")
+	}
+	minLevel := slog.LevelDebug
+	if req.GetMinLevel() != "" {
+		if err := minLevel.UnmarshalText([]byte(req.GetMinLevel())); err != nil {
+			return status.Errorf(codes.InvalidArgument, "min_level: %v", err)
+		}
+	}
+
+	var recent []logging.Entry
+	var entries <-chan logging.Entry
+	if req.GetFollow() {
+		var unsubscribe func()
+		recent, entries, unsubscribe = c.server.logs.Subscribe()
+		defer unsubscribe()
+	} else {
+		recent = c.server.logs.Recent()
+	}
+	filtered := recent[:0]
+	for _, e := range recent {
+		if e.Level >= minLevel {
+			filtered = append(filtered, e)
+		}
+	}
+	if n := int(req.GetLines()); n > 0 && len(filtered) > n {
+		filtered = filtered[len(filtered)-n:]
+	}
+	for _, e := range filtered {
+		if err := stream.Send(logEntryToPB(e)); err != nil {
+			return err
+		}
+	}
+	if entries == nil {
+		return nil
+	}
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case e := <-entries:
+			if e.Level < minLevel {
+				continue
+			}
+			if err := stream.Send(logEntryToPB(e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func logEntryToPB(e logging.Entry) *todopb.LogEntry {
+	return &todopb.LogEntry{
+		Time:  timestamppb.New(e.Time),
+		Level: e.Level.String(),
+		Line:  e.Line,
+	}
+}
@@ -0,0 +1,115 @@
+//go:build !windows
+
+package server
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/logging"
+	"github.com/mwopitz/todo-daemon/internal/transport"
+)
+
+func TestTailLogs(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	buf := logging.NewBuffer(100)
+	logging.Init(io.Discard, slog.LevelInfo, buf)
+	slog.Warn("first warning")
+	slog.Error("first error")
+
+	addr := transport.Address{Scheme: transport.SchemeUnix, Path: filepath.Join(t.TempDir(), "todo-daemon.sock")}
+	srv := New(WithHTTPListenAddress(HTTPListenAddress{}), WithLogs(buf))
+	go func() { _ = srv.Serve(addr) }()
+	defer func() { _ = srv.StopGracefully(time.Second) }()
+	c, err := client.New(addr.String(), client.WithTimeout(5*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := c.WaitReady(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The server logs the calls at level INFO, which are filtered out.
+	stream, err := c.TailLogs(t.Context(), &todopb.TailLogsRequest{MinLevel: "warn", Lines: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for {
+		e, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, e.GetLine())
+	}
+	if len(lines) != 1 || !strings.Contains(lines[0], "first error") {
+		t.Errorf("want only the last error; got: %q", lines)
+	}
+
+	stream, err = c.TailLogs(t.Context(), &todopb.TailLogsRequest{MinLevel: "loud"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("want INVALID_ARGUMENT for an invalid level; got: %v", err)
+	}
+
+	stream, err = c.TailLogs(t.Context(), &todopb.TailLogsRequest{MinLevel: "warn", Lines: 1, Follow: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, err := stream.Recv(); err != nil || !strings.Contains(e.GetLine(), "first error") {
+		t.Fatalf("want the last error first; got: %v, %v", e, err)
+	}
+	slog.Warn("second warning")
+	if e, err := stream.Recv(); err != nil || e.GetLevel() != "WARN" || !strings.Contains(e.GetLine(), "second warning") {
+		t.Errorf("want the new warning; got: %v, %v", e, err)
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	addr := transport.Address{Scheme: transport.SchemeUnix, Path: filepath.Join(t.TempDir(), "todo-daemon.sock")}
+	srv := New(WithHTTPListenAddress(HTTPListenAddress{}), WithLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+	go func() { _ = srv.Serve(addr) }()
+	c, err := client.New(addr.String(), client.WithTimeout(5*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := c.WaitReady(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	stream, err := c.TailLogs(t.Context(), &todopb.TailLogsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Unimplemented {
+		t.Errorf("want UNIMPLEMENTED without logs; got: %v", err)
+	}
+	if err := srv.StopGracefully(time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, `msg="gRPC server listening on" component=server`) {
+		t.Errorf("want the server's messages in the logger; got:\n%s", out)
+	}
+	if !strings.Contains(out, "component=grpc") || !strings.Contains(out, "TailLogs") {
+		t.Errorf("want the RPC's messages in the logger; got:\n%s", out)
+	}
+}
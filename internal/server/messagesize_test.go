@@ -0,0 +1,54 @@
+//go:build !windows
+
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/transport"
+)
+
+func TestMaxMessageSize(t *testing.T) {
+	addr := transport.Address{Scheme: transport.SchemeUnix, Path: filepath.Join(t.TempDir(), "todo-daemon.sock")}
+	srv := New(WithHTTPListenAddress(HTTPListenAddress{}), WithMaxMessageSize(32<<10, 4<<10))
+	go func() { _ = srv.Serve(addr) }()
+	defer func() { _ = srv.StopGracefully(time.Second) }()
+	c, err := client.New(addr.String(), client.WithTimeout(5*time.Second), client.WithCompression(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := c.WaitReady(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Received messages are limited after decompressing them.
+	if _, err := c.RestoreBackup(t.Context(), make([]byte, 64<<10)); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("want RESOURCE_EXHAUSTED for a request that is too large; got: %v", err)
+	}
+	// Sent messages are limited after compressing them, so the descriptions
+	// must not compress well. Each task alone stays below the limit even if
+	// its response isn't compressed.
+	for range 4 {
+		b := make([]byte, 1500)
+		if _, err := rand.Read(b); err != nil {
+			t.Fatal(err)
+		}
+		task := &todopb.NewTask{Summary: "a", Description: hex.EncodeToString(b)}
+		if _, err := c.CreateTask(t.Context(), task); err != nil {
+			t.Fatalf("cannot create task: %v", err)
+		}
+	}
+	if _, err := c.FindTasks(t.Context(), &todopb.ListTasksRequest{}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("want RESOURCE_EXHAUSTED for a response that is too large; got: %v", err)
+	}
+}
@@ -0,0 +1,314 @@
+package server
+
+import (
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/backup"
+	"github.com/mwopitz/todo-daemon/internal/compress"
+	"github.com/mwopitz/todo-daemon/internal/cors"
+	"github.com/mwopitz/todo-daemon/internal/forwarded"
+	"github.com/mwopitz/todo-daemon/internal/handover"
+	"github.com/mwopitz/todo-daemon/internal/hardening"
+	"github.com/mwopitz/todo-daemon/internal/hook"
+	"github.com/mwopitz/todo-daemon/internal/janitor"
+	"github.com/mwopitz/todo-daemon/internal/logging"
+	"github.com/mwopitz/todo-daemon/internal/ratelimit"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+	"github.com/mwopitz/todo-daemon/internal/transport"
+	"github.com/mwopitz/todo-daemon/internal/webhook"
+)
+
+// Option configures optional features of a [Server].
+type Option func(s *Server)
+
+// WithWebhooks configures the server to notify the webhooks in the specified
+// registry about task events. Webhooks registered via the REST API are added
+// to the same registry.
+func WithWebhooks(registry *webhook.Registry) Option {
+	return func(s *Server) {
+		s.webhooks = registry
+	}
+}
+
+// WithFilters configures the server to list tasks by the named filters in the
+// specified registry. Filters saved via the API are added to the same
+// registry.
+func WithFilters(registry *todo.FilterRegistry) Option {
+	return func(s *Server) {
+		s.filters = registry
+	}
+}
+
+// WithTemplates configures the server to create tasks from the templates in
+// the specified registry, both on request and on their schedules.
+func WithTemplates(registry *todo.TemplateRegistry) Option {
+	return func(s *Server) {
+		s.templates = registry
+	}
+}
+
+// WithReflection enables the gRPC server reflection service, which allows
+// tools like grpcurl to discover and invoke the server's methods.
+func WithReflection() Option {
+	return func(s *Server) {
+		s.reflection = true
+	}
+}
+
+// WithLogs configures the server to stream the log messages kept in the
+// specified buffer to the clients of the TailLogs RPC. Without it, the RPC
+// fails with UNIMPLEMENTED.
+func WithLogs(buf *logging.Buffer) Option {
+	return func(s *Server) {
+		s.logs = buf
+	}
+}
+
+// WithLogger sets the logger of the messages about the server and its RPCs and
+// HTTP requests. Without it, the default logger is used. The components that
+// the server runs, e.g. its background jobs and webhooks, keep logging with
+// the default logger, which is also the only one whose messages the server
+// keeps for [WithLogs].
+func WithLogger(l *slog.Logger) Option {
+	return func(s *Server) {
+		s.log = l
+	}
+}
+
+// WithRateLimit configures the server to reject HTTP requests exceeding the
+// limits of the specified limiter with "429 Too Many Requests".
+func WithRateLimit(limiter *ratelimit.Limiter) Option {
+	return func(s *Server) {
+		s.limiter = limiter
+	}
+}
+
+// WithHooks configures the server to execute hook scripts on task events
+// using the specified runner.
+func WithHooks(runner *hook.Runner) Option {
+	return func(s *Server) {
+		s.hooks = runner
+	}
+}
+
+// WithReadOnly puts the server into read-only mode, in which all requests that
+// would modify the to-do list or the webhooks are rejected. Listing the tasks
+// and querying the server status still work.
+func WithReadOnly() Option {
+	return func(s *Server) {
+		s.readOnly.enabled.Store(true)
+	}
+}
+
+// WithWebUI configures the server to serve the web UI at /ui/ on the HTTP
+// server.
+func WithWebUI() Option {
+	return func(s *Server) {
+		s.webUI = true
+	}
+}
+
+// WithDemoData configures the server to add some demo tasks to the to-do list
+// when it starts with an empty to-do list.
+func WithDemoData() Option {
+	return func(s *Server) {
+		s.demoData = true
+	}
+}
+
+// WithHTTPListenAddress configures the address that the HTTP server listens
+// on. The zero value disables the HTTP server, and with it the REST API and the
+// web UI.
+func WithHTTPListenAddress(addr HTTPListenAddress) Option {
+	return func(s *Server) {
+		s.httpAddr = addr
+	}
+}
+
+// WithSocketOptions configures the Unix domain socket that the gRPC server
+// listens on, e.g. its mode and group. Without it, only the user running the
+// server can connect to it.
+func WithSocketOptions(opts ...transport.ListenOption) Option {
+	return func(s *Server) {
+		s.socketOpts = append(s.socketOpts, opts...)
+	}
+}
+
+// WithExternalURL configures the URL that clients use to reach the HTTP server,
+// e.g. behind a reverse proxy. It is reported as the base of the API URL in the
+// server status, and used for the links returned by the REST API unless the
+// proxy sets X-Forwarded-* headers.
+func WithExternalURL(u *url.URL) Option {
+	return func(s *Server) {
+		s.externalURL = u
+	}
+}
+
+// WithTrustedProxies configures the reverse proxies whose X-Forwarded-*
+// headers determine the external URL of the HTTP server. The headers of all
+// other clients are ignored.
+func WithTrustedProxies(p *forwarded.Proxies) Option {
+	return func(s *Server) {
+		s.proxies = p
+	}
+}
+
+// WithCORS allows the cross-origin requests to the REST API described by the
+// specified policy. Without it, or if the policy doesn't allow any origins,
+// browsers block cross-origin requests.
+func WithCORS(p *cors.Policy) Option {
+	return func(s *Server) {
+		s.cors = p
+	}
+}
+
+// WithCompression configures the server to compress the HTTP responses using
+// the specified compressor if the client accepts it.
+func WithCompression(c *compress.Compressor) Option {
+	return func(s *Server) {
+		s.compressor = c
+	}
+}
+
+// WithHardening configures the limits of the requests to the HTTP server and
+// the security headers of its responses. Without it, request bodies are
+// limited to [hardening.DefaultMaxBodySize] and security headers are sent.
+func WithHardening(p *hardening.Policy) Option {
+	return func(s *Server) {
+		s.hardening = p
+	}
+}
+
+// WithMaxRequestDuration limits the duration of unary RPCs, including those
+// made on behalf of REST API requests, to the specified duration. The limit is
+// propagated to the storage backend via the context's deadline.
+func WithMaxRequestDuration(d time.Duration) Option {
+	return func(s *Server) {
+		s.deadlines.max = d
+	}
+}
+
+// WithScheduledBackups configures the server to periodically write snapshots
+// of its tasks using the specified scheduler. The scheduler's task repository
+// is set by the server.
+func WithScheduledBackups(scheduler *backup.Scheduler) Option {
+	return func(s *Server) {
+		s.backups = scheduler
+	}
+}
+
+// WithStrictDependencies makes the server reject requests to complete a task
+// that depends on tasks that are still open.
+func WithStrictDependencies() Option {
+	return func(s *Server) {
+		s.strictDependencies = true
+	}
+}
+
+// WithIdempotencyWindow makes the server remember the tasks created by
+// requests with an idempotency key for the specified window, so that retried
+// requests don't create the same task twice. A window of zero disables it.
+func WithIdempotencyWindow(window time.Duration) Option {
+	return func(s *Server) {
+		s.idempotencyWindow = window
+	}
+}
+
+// WithWatchBuffer sets the number of events buffered for each client of the
+// WatchTasks RPC and the event stream, and what happens when a client falls
+// further behind. Publishing events never waits for slow clients, so they
+// cannot block the modifications of the tasks. The default is
+// [todo.DefaultWatchBufferSize] and [todo.OverflowDropOldest].
+func WithWatchBuffer(size int, policy todo.OverflowPolicy) Option {
+	return func(s *Server) {
+		s.watchBufferSize = size
+		s.watchOverflow = policy
+	}
+}
+
+// WithMaxMessageSize limits the size of the gRPC messages that the server
+// receives and sends, in bytes. Larger messages fail with RESOURCE_EXHAUSTED.
+// The default for both is [DefaultMaxMessageSize].
+func WithMaxMessageSize(recv, send int) Option {
+	return func(s *Server) {
+		s.maxRecvMsgSize = recv
+		s.maxSendMsgSize = send
+	}
+}
+
+// WithQuotas limits the number of open tasks and the number of tags per task,
+// so that a misbehaving client cannot fill up the storage. Requests exceeding
+// the quotas fail with RESOURCE_EXHAUSTED, or "413 Request Entity Too Large"
+// in the REST API.
+func WithQuotas(quotas todo.Quotas) Option {
+	return func(s *Server) {
+		s.quotas = quotas
+	}
+}
+
+// WithUniqueSummaries makes the server reject requests to create a task whose
+// summary equals the summary of an open task, ignoring case, unless the request
+// allows duplicates.
+func WithUniqueSummaries() Option {
+	return func(s *Server) {
+		s.uniqueSummaries = true
+	}
+}
+
+// WithTimeZone sets the default time zone of the tasks, which is assigned to
+// new tasks without a time zone and used for computing day boundaries. Without
+// it, the local time zone is used.
+func WithTimeZone(loc *time.Location) Option {
+	return func(s *Server) {
+		s.location = loc
+	}
+}
+
+// WithJobs registers the specified periodic background jobs, which the server
+// runs until it stops.
+func WithJobs(jobs ...janitor.Job) Option {
+	return func(s *Server) {
+		s.jobs = append(s.jobs, jobs...)
+	}
+}
+
+// WithConfigReloader enables the ReloadConfig RPC, which reloads the server's
+// configuration using the specified reloader.
+func WithConfigReloader(reloader todo.ConfigReloader) Option {
+	return func(s *Server) {
+		s.config = reloader
+	}
+}
+
+// WithListeners configures the server to accept connections on the specified
+// listeners, which have been handed over by another instance, instead of
+// listening on its addresses. The HTTP server is disabled if the HTTP
+// listener is nil, regardless of [WithHTTPListenAddress].
+func WithListeners(ls handover.Listeners) Option {
+	return func(s *Server) {
+		s.inherited = &ls
+	}
+}
+
+// WithSnapshot configures the server to start with the tasks of the specified
+// snapshot, e.g. one handed over by another instance, instead of the tasks
+// in its storage.
+func WithSnapshot(snapshot *todo.Snapshot) Option {
+	return func(s *Server) {
+		s.snapshot = snapshot
+	}
+}
+
+// WithStorage configures the server to keep its tasks in the specified
+// repository, e.g. a store opened with package storage, which is reported as
+// the specified storage backend in the server status. Without it, the server
+// keeps its tasks in memory. The server does not close the repository, unless
+// it hands over to a new instance, see [Server.HandedOver].
+func WithStorage(backend string, tasks todo.TaskRepository) Option {
+	return func(s *Server) {
+		s.backend = backend
+		s.tasks = tasks
+	}
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	todov2pb "github.com/mwopitz/todo-daemon/api/todo/v2"
+	"github.com/mwopitz/todo-daemon/internal/rest"
+)
+
+// mutatingMethods holds the full names of the gRPC methods that modify the
+// to-do list.
+var mutatingMethods = map[string]bool{
+	todopb.TodoService_CreateTask_FullMethodName:       true,
+	todopb.TodoService_BatchCreateTasks_FullMethodName: true,
+	todopb.TodoService_ApplyBatch_FullMethodName:       true,
+	todopb.TodoService_UpdateTask_FullMethodName:       true,
+	todopb.TodoService_MoveTask_FullMethodName:         true,
+	todopb.TodoService_DeleteTask_FullMethodName:       true,
+	todopb.TodoService_RestoreTask_FullMethodName:      true,
+	todopb.TodoService_DuplicateTask_FullMethodName:    true,
+	todopb.TodoService_PurgeCompleted_FullMethodName:   true,
+	todopb.TodoService_RestoreBackup_FullMethodName:    true,
+	todopb.TodoService_PushChanges_FullMethodName:      true,
+	todopb.TodoService_CreateFilter_FullMethodName:     true,
+	todopb.TodoService_DeleteFilter_FullMethodName:     true,
+	todopb.TodoService_CreateTemplate_FullMethodName:   true,
+	todopb.TodoService_DeleteTemplate_FullMethodName:   true,
+	todopb.TodoService_ApplyTemplate_FullMethodName:    true,
+	todov2pb.TaskService_CreateTask_FullMethodName:     true,
+	todov2pb.TaskService_UpdateTask_FullMethodName:     true,
+	todov2pb.TaskService_DeleteTask_FullMethodName:     true,
+}
+
+// readOnlyGuard rejects all requests that would modify data while the server
+// is in read-only mode. The server switches to read-only mode at runtime when
+// it hands over to a new instance.
+type readOnlyGuard struct {
+	enabled atomic.Bool
+}
+
+func (g *readOnlyGuard) unaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if g.enabled.Load() && mutatingMethods[info.FullMethod] {
+			return nil, status.Errorf(codes.FailedPrecondition, "server is in read-only mode")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// middleware returns a handler that rejects all HTTP requests except GET,
+// HEAD, and OPTIONS requests with "405 Method Not Allowed" while the server is
+// in read-only mode.
+func (g *readOnlyGuard) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			if g.enabled.Load() {
+				w.Header().Set("Allow", "GET, HEAD, OPTIONS")
+				p := rest.NewProblem(http.StatusMethodNotAllowed, "server is in read-only mode")
+				p.Type = rest.ProblemReadOnly
+				rest.WriteProblem(w, r, p)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+
+	middleware "github.com/grpc-ecosystem/go-grpc-middleware/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/mwopitz/todo-daemon/internal/requestid"
+)
+
+// incomingRequestID returns the request ID from the incoming metadata of the
+// specified context, e.g. the ID forwarded by the gRPC gateway, or a newly
+// generated ID.
+func incomingRequestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var id string
+	if values := md.Get(requestid.MetadataKey); len(values) > 0 {
+		id = values[0]
+	}
+	return requestid.Valid(id)
+}
+
+// requestIDUnaryInterceptor assigns an ID to each unary RPC, which is added to
+// the RPC's context and sent back as header metadata.
+func requestIDUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		id := incomingRequestID(ctx)
+		// The ID is just informational, so failing to send it is no reason to
+		// fail the RPC.
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestid.MetadataKey, id))
+		return handler(requestid.NewContext(ctx, id), req)
+	}
+}
+
+// requestIDStreamInterceptor assigns an ID to each streaming RPC, which is
+// added to the stream's context and sent back as header metadata.
+func requestIDStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id := incomingRequestID(ss.Context())
+		_ = ss.SetHeader(metadata.Pairs(requestid.MetadataKey, id))
+		wrapped := middleware.WrapServerStream(ss)
+		wrapped.WrappedContext = requestid.NewContext(ss.Context(), id)
+		return handler(srv, wrapped)
+	}
+}
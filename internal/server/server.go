@@ -10,19 +10,42 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"sync"
 	"time"
 
-	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
+	grpclogging "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	// The gzip compressor is registered for clients that compress their
+	// requests.
+	_ "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 
 	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	todov2pb "github.com/mwopitz/todo-daemon/api/todo/v2"
+	"github.com/mwopitz/todo-daemon/internal/backup"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/compress"
+	"github.com/mwopitz/todo-daemon/internal/cors"
+	"github.com/mwopitz/todo-daemon/internal/forwarded"
+	"github.com/mwopitz/todo-daemon/internal/handover"
+	"github.com/mwopitz/todo-daemon/internal/hardening"
+	"github.com/mwopitz/todo-daemon/internal/hook"
+	"github.com/mwopitz/todo-daemon/internal/janitor"
+	"github.com/mwopitz/todo-daemon/internal/logging"
+	"github.com/mwopitz/todo-daemon/internal/ratelimit"
+	"github.com/mwopitz/todo-daemon/internal/requestid"
 	"github.com/mwopitz/todo-daemon/internal/todo"
+	"github.com/mwopitz/todo-daemon/internal/transport"
+	"github.com/mwopitz/todo-daemon/internal/version"
+	"github.com/mwopitz/todo-daemon/internal/webhook"
+	"github.com/mwopitz/todo-daemon/internal/webui"
 )
 
-func newInterceptorLoggerFunc(l *slog.Logger) logging.LoggerFunc {
-	return func(ctx context.Context, lvl logging.Level, msg string, fields ...any) {
+func newInterceptorLoggerFunc(l *slog.Logger) grpclogging.LoggerFunc {
+	return func(ctx context.Context, lvl grpclogging.Level, msg string, fields ...any) {
 		l.Log(ctx, slog.Level(lvl), msg, fields...)
 	}
 }
@@ -31,27 +54,94 @@ func newInterceptorLoggerFunc(l *slog.Logger) logging.LoggerFunc {
 // which provides a REST API to external applications, as well as a gRPC Server,
 // which is used for internal communication between the To-do Daemon processes.
 type Server struct {
-	grpcServer *grpc.Server
-	httpServer *http.Server
+	grpcServer  *grpc.Server
+	httpServer  *http.Server
+	health      *health.Server
+	conns       *connTracker
+	streams     *streamCanceler
+	readOnly    *readOnlyGuard
+	deadlines   *deadlineLimiter
+	events      *todo.EventBus
+	webhooks    *webhook.Registry
+	filters     *todo.FilterRegistry
+	templates   *todo.TemplateRegistry
+	limiter     *ratelimit.Limiter
+	cors        *cors.Policy
+	compressor  *compress.Compressor
+	hardening   *hardening.Policy
+	hooks       *hook.Runner
+	backups     *backup.Scheduler
+	jobs        []janitor.Job
+	janitor     *janitor.Scheduler
+	config      todo.ConfigReloader
+	logs        *logging.Buffer
+	log         *slog.Logger
+	reflection  bool
+	webUI       bool
+	demoData    bool
+	httpAddr    HTTPListenAddress
+	socketOpts  []transport.ListenOption
+	externalURL *url.URL
+	proxies     *forwarded.Proxies
+	inherited   *handover.Listeners
+	snapshot    *todo.Snapshot
+	tasks       todo.TaskRepository
+	backend     string
+
+	// strictDependencies rejects completing tasks that depend on open tasks.
+	strictDependencies bool
+	// uniqueSummaries rejects creating tasks whose summary equals the summary
+	// of an open task.
+	uniqueSummaries bool
+	// idempotencyWindow is how long the tasks created by requests with an
+	// idempotency key are remembered; zero disables idempotency keys.
+	idempotencyWindow time.Duration
+	// quotas are the soft limits of the to-do list.
+	quotas todo.Quotas
+	// watchBufferSize and watchOverflow configure the subscriptions of the
+	// clients watching the tasks, see [WithWatchBuffer].
+	watchBufferSize int
+	watchOverflow   todo.OverflowPolicy
+	// maxRecvMsgSize and maxSendMsgSize limit the size of the gRPC messages,
+	// see [WithMaxMessageSize].
+	maxRecvMsgSize int
+	maxSendMsgSize int
+	// location is the default time zone of the tasks.
+	location *time.Location
+
+	// db is the repository of the tasks, which is set by Serve.
+	db todo.TaskRepository
+	// grpcListener and httpListener are the listeners of the servers, which
+	// are set by Serve and can be handed over to a new instance. The HTTP
+	// listener is nil if the HTTP server is disabled.
+	grpcListener *detachableListener
+	httpListener *detachableListener
+	// handedOver is closed once the listeners have been handed over.
+	handedOver chan struct{}
+	handoverMu sync.Mutex
+
+	// ctx is canceled when the server stops, which stops all background
+	// goroutines tracked by wg.
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
-// New creates a new To-do Daemon server with an optional logger. If no
-// logger is provided, it the server uses [slog.Default].
-func New() *Server {
-	logger := slog.Default()
-	loggingOpts := []logging.Option{
-		logging.WithLogOnEvents(logging.StartCall, logging.FinishCall),
-	}
-	loggerFunc := newInterceptorLoggerFunc(logger)
+// DefaultMaxMessageSize is the default maximum size of the gRPC messages that
+// the server receives and sends, in bytes, see [WithMaxMessageSize]. It is
+// higher than gRPC's default of 4 MiB, so that backups of large to-do lists
+// fit.
+const DefaultMaxMessageSize = 16 << 20
 
-	grpcServer := grpc.NewServer(
-		grpc.ChainUnaryInterceptor(
-			logging.UnaryServerInterceptor(loggerFunc, loggingOpts...),
-		),
-		grpc.ChainStreamInterceptor(
-			logging.StreamServerInterceptor(loggerFunc, loggingOpts...),
-		),
-	)
+// New creates a new To-do Daemon server with the specified options.
+func New(opts ...Option) *Server {
+	loggingOpts := []grpclogging.Option{
+		grpclogging.WithLogOnEvents(grpclogging.StartCall, grpclogging.FinishCall),
+	}
+	conns := newConnTracker()
+	streams := newStreamCanceler()
+	readOnly := &readOnlyGuard{}
+	deadlines := &deadlineLimiter{}
 
 	httpServer := &http.Server{
 		Handler:           http.NewServeMux(),
@@ -59,101 +149,391 @@ func New() *Server {
 		ReadHeaderTimeout: 2 * time.Second,
 		WriteTimeout:      10 * time.Second,
 		IdleTimeout:       60 * time.Second,
+		ConnState:         conns.trackConn,
 	}
 
-	return &Server{
-		grpcServer: grpcServer,
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
 		httpServer: httpServer,
+		conns:      conns,
+		streams:    streams,
+		readOnly:   readOnly,
+		deadlines:  deadlines,
+		events:     todo.NewEventBus(),
+		webhooks:   webhook.NewRegistry(),
+		hardening:  &hardening.Policy{SecurityHeaders: true},
+		log:        slog.Default(),
+		httpAddr:   HTTPListenAddress{Network: "tcp", Address: "localhost:0"},
+		janitor:    &janitor.Scheduler{},
+		handedOver: make(chan struct{}),
+		ctx:        ctx,
+		cancel:     cancel,
+
+		watchBufferSize: todo.DefaultWatchBufferSize,
+		watchOverflow:   todo.OverflowDropOldest,
+		maxRecvMsgSize:  DefaultMaxMessageSize,
+		maxSendMsgSize:  DefaultMaxMessageSize,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	// The gRPC server is created after applying the options, which configure
+	// some of its settings. Gzip-compressed requests are answered with
+	// gzip-compressed responses, see package encoding/gzip.
+	loggerFunc := newInterceptorLoggerFunc(logging.WithComponent(s.log, logging.ComponentGRPC))
+	s.grpcServer = grpc.NewServer(
+		grpc.MaxRecvMsgSize(s.maxRecvMsgSize),
+		grpc.MaxSendMsgSize(s.maxSendMsgSize),
+		grpc.ChainUnaryInterceptor(
+			conns.unaryInterceptor(),
+			requestIDUnaryInterceptor(),
+			loggerUnaryInterceptor(s.log),
+			grpclogging.UnaryServerInterceptor(loggerFunc, loggingOpts...),
+			versionUnaryInterceptor(),
+			readOnly.unaryInterceptor(),
+			deadlines.unaryInterceptor(),
+			deprecationUnaryInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			conns.streamInterceptor(),
+			requestIDStreamInterceptor(),
+			loggerStreamInterceptor(s.log),
+			grpclogging.StreamServerInterceptor(loggerFunc, loggingOpts...),
+			versionStreamInterceptor(),
+			streams.streamInterceptor(),
+		),
+	)
+
+	// The server reports that it is serving once Serve has started the gRPC
+	// and HTTP servers.
+	s.health = health.NewServer()
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(s.grpcServer, s.health)
+	if s.reflection {
+		reflection.Register(s.grpcServer)
+	}
+	return s
 }
 
 // Serve starts both the underlying HTTP server and gRPC server. The specified
-// network and address arguments are only used for the gRPC server; the HTTP
-// server always listens on IPv4 localhost + a random free port.
-func (s *Server) Serve(network, address string) error {
-	db := todo.NewInMemoryTaskDB()
-	// Add some demo data...
-	tasks := []todo.TaskCreate{
-		{Summary: "Get some milk 🥛"},
-		{Summary: "Walk the dog 🐕"},
-		{Summary: "Take over the world! 🌍"},
-	}
+// address is only used for the gRPC server; the HTTP server listens on the
+// address set with [WithHTTPListenAddress], which defaults to localhost and a
+// random free port.
+func (s *Server) Serve(addr transport.Address) error {
 	ctx := context.Background()
-	for _, task := range tasks {
-		if _, err := db.Create(ctx, &task); err != nil {
+	if s.tasks == nil {
+		s.tasks, s.backend = todo.NewInMemoryTaskDB(), "memory"
+	}
+	tasks, backend := s.tasks, s.backend
+	if s.snapshot != nil {
+		if err := tasks.Replace(ctx, s.snapshot.TaskList()); err != nil {
+			return err
+		}
+	} else if s.demoData {
+		if err := addDemoTasks(ctx, tasks); err != nil {
 			return err
 		}
 	}
-
-	mux := runtime.NewServeMux()
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	// Tasks changed by other programs, e.g. in a file edited by hand, are
+	// published like the changes made through the server.
+	todo.PublishReloads(tasks, s.events)
+	if s.quotas.Enabled() {
+		tasks = todo.NewQuotaRepository(tasks, s.quotas)
+	}
+	db := todo.NewPublishingRepository(tasks, s.events)
+	s.db = db
+	if err := s.registerJobs(db); err != nil {
+		return err
 	}
+
+	mux := runtime.NewServeMux(gatewayOptions()...)
 	if err := todopb.RegisterTodoServiceHandlerFromEndpoint(
 		ctx,
 		mux,
-		fmt.Sprintf("%s:%s", network, address),
-		opts,
+		client.Target(addr),
+		s.gatewayDialOptions(addr),
+	); err != nil {
+		return fmt.Errorf("cannot start gRPC gateway: %w", err)
+	}
+	if err := todov2pb.RegisterTaskServiceHandlerFromEndpoint(
+		ctx,
+		mux,
+		client.Target(addr),
+		s.gatewayDialOptions(addr),
 	); err != nil {
 		return fmt.Errorf("cannot start gRPC gateway: %w", err)
 	}
-	s.httpServer.Handler.(*http.ServeMux).Handle("/api/", http.StripPrefix("/api", mux))
+	httpMux := s.httpServer.Handler.(*http.ServeMux)
+	httpMux.Handle("/api/", http.StripPrefix("/api", conditionalMiddleware(mux)))
+	httpMux.Handle("GET /api/v1/tasks.ics", newICSHandler(db))
+	httpMux.Handle("GET /api/v1/events", newEventStreamHandler(s.events, s.watchBufferSize, s.watchOverflow,
+		s.streams.done()))
+	webhook.NewHandler(s.webhooks).Register(httpMux, "/api/v1")
+	registerHealthHandlers(httpMux, s.health)
+	if s.webUI {
+		httpMux.Handle("GET /ui/", http.StripPrefix("/ui", webui.Handler()))
+	}
+	var handler http.Handler = httpMux
+	handler = s.readOnly.middleware(handler)
+	if s.compressor != nil {
+		handler = s.compressor.Middleware(handler)
+	}
+	if s.limiter != nil {
+		handler = s.limiter.Middleware(handler)
+	}
+	handler = s.hardening.Middleware(handler)
+	// Preflight requests are answered before they count against the rate
+	// limit, and rejected requests carry CORS headers, so that browsers
+	// expose the errors to the scripts making the requests.
+	if s.cors != nil && s.cors.Enabled() {
+		handler = s.cors.Middleware(handler)
+	}
+	handler = logging.Middleware(handler, s.log)
+	handler = requestid.Middleware(handler)
+	handler = forwarded.Middleware(handler, s.externalURL, s.proxies)
+	s.httpServer.Handler = handler
 
-	grpcListener, err := net.Listen(network, address)
+	grpcListener, httpListener, err := s.listen(addr)
 	if err != nil {
-		return fmt.Errorf("cannot start gRPC server: %w", err)
+		return err
 	}
+	s.grpcListener = newDetachableListener(grpcListener)
 
-	grpcAddr := grpcListener.Addr().String()
-	slog.Info("gRPC server listening on", "addr", grpcAddr)
+	s.logger().Info("gRPC server listening on", "addr", addr.String())
 
-	httpListener, err := net.Listen("tcp", "localhost:0")
-	if err != nil {
-		return fmt.Errorf("cannot start HTTP server: %w", err)
+	var httpAddr, apiBaseURL string
+	if httpListener != nil {
+		s.httpListener = newDetachableListener(httpListener)
+		httpAddr, apiBaseURL = httpAddresses(httpListener)
+		s.logger().Info("HTTP server listening on", "addr", httpAddr)
+		if s.externalURL != nil {
+			apiBaseURL = s.externalURL.JoinPath("api").String()
+			s.logger().Info("HTTP server reachable at", "url", s.externalURL.String())
+		}
+	} else {
+		s.logger().Info("HTTP server disabled")
 	}
 
-	httpAddr := httpListener.Addr().String()
-	slog.Info("HTTP server listening on", "addr", httpAddr)
-
-	status := func(_ context.Context) (*todo.ServerStatus, error) {
-		u := url.URL{
-			Scheme: "http",
-			Host:   httpAddr,
-			Path:   "/api",
+	startedAt := time.Now()
+	status := func(ctx context.Context) (*todo.ServerStatus, error) {
+		tasks, err := db.List(ctx, &todo.ListOptions{})
+		if err != nil {
+			return nil, err
 		}
 		return &todo.ServerStatus{
-			PID:        os.Getpid(),
-			APIBaseURL: u.String(),
+			PID:              os.Getpid(),
+			APIBaseURL:       apiBaseURL,
+			Version:          version.Semantic(),
+			MinClientVersion: version.MinClient.String(),
+			Uptime:           time.Since(startedAt),
+			StorageBackend:   backend,
+			TaskCount:        len(tasks),
+			SocketAddress:    addr.String(),
+			HTTPAddress:      httpAddr,
+			Events:           s.events.Stats(),
 		}, nil
 	}
 
+	s.startWebhookDispatcher()
+	if s.hooks != nil {
+		s.startHookRunner()
+	}
+	s.startJanitor()
+
 	// Connect the gRPC server to the controller.
-	ctrl := todo.NewController(todo.ServerStatusProviderFunc(status), db)
-	todopb.RegisterTodoServiceServer(s.grpcServer, ctrl)
+	ctrlOpts := []todo.ControllerOption{
+		todo.WithStrictDependencies(s.strictDependencies),
+		todo.WithUniqueSummaries(s.uniqueSummaries),
+	}
+	if s.idempotencyWindow > 0 {
+		ctrlOpts = append(ctrlOpts, todo.WithIdempotency(todo.NewIdempotencyCache(s.idempotencyWindow)))
+	}
+	if s.location != nil {
+		ctrlOpts = append(ctrlOpts, todo.WithTimeZone(s.location))
+	}
+	if s.filters != nil {
+		ctrlOpts = append(ctrlOpts, todo.WithFilters(s.filters))
+	}
+	if s.templates != nil {
+		ctrlOpts = append(ctrlOpts, todo.WithTemplates(s.templates))
+	}
+	ctrlOpts = append(ctrlOpts, todo.WithWatchBuffer(s.watchBufferSize, s.watchOverflow))
+	ctrl := todo.NewController(todo.ServerStatusProviderFunc(status), s.config, db, s.events, ctrlOpts...)
+	todopb.RegisterTodoServiceServer(s.grpcServer, &controller{Controller: ctrl, server: s})
+	todov2pb.RegisterTaskServiceServer(s.grpcServer, todo.NewControllerV2(ctrl))
 
 	grpcDone := make(chan error, 1)
 	go func() {
-		grpcDone <- s.grpcServer.Serve(grpcListener)
+		grpcDone <- s.grpcServer.Serve(s.grpcListener)
 		close(grpcDone)
 	}()
 
 	httpDone := make(chan error, 1)
-	go func() {
-		httpDone <- s.httpServer.Serve(httpListener)
+	if s.httpListener != nil {
+		go func() {
+			httpDone <- s.httpServer.Serve(s.httpListener)
+			close(httpDone)
+		}()
+	} else {
 		close(httpDone)
-	}()
+	}
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
 
 	return errors.Join(<-grpcDone, <-httpDone)
 }
 
+// addDemoTasks adds the demo tasks to the specified repository if it is
+// empty, i.e. if the tasks are kept in memory or the storage is new.
+func addDemoTasks(ctx context.Context, tasks todo.TaskRepository) error {
+	existing, err := tasks.List(ctx, &todo.ListOptions{IncludeDeleted: true})
+	if err != nil || len(existing) > 0 {
+		return err
+	}
+	for _, task := range todo.DemoTasks() {
+		if _, err := tasks.Create(ctx, &task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listen creates the listeners of the gRPC and HTTP servers, unless they have
+// been handed over by another instance. The HTTP listener is nil if the HTTP
+// server is disabled.
+func (s *Server) listen(addr transport.Address) (net.Listener, net.Listener, error) {
+	if s.inherited != nil {
+		// Listeners created from file descriptors don't remove their socket
+		// files when closed, unlike those created by the previous instance.
+		for _, l := range []net.Listener{s.inherited.GRPC, s.inherited.HTTP} {
+			if ul, ok := l.(*net.UnixListener); ok {
+				ul.SetUnlinkOnClose(true)
+			}
+		}
+		return s.inherited.GRPC, s.inherited.HTTP, nil
+	}
+	grpcListener, err := transport.Listen(addr, s.socketOpts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot start gRPC server: %w", err)
+	}
+	if !s.httpAddr.Enabled() {
+		return grpcListener, nil, nil
+	}
+	httpListener, err := net.Listen(s.httpAddr.Network, s.httpAddr.Address)
+	if err != nil {
+		return nil, nil, errors.Join(fmt.Errorf("cannot start HTTP server: %w", err), grpcListener.Close())
+	}
+	return grpcListener, httpListener, nil
+}
+
+func (s *Server) startWebhookDispatcher() {
+	events, unsubscribe := s.events.Subscribe(64)
+	dispatcher := webhook.NewDispatcher(s.webhooks)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer unsubscribe()
+		dispatcher.Run(s.ctx, events)
+	}()
+}
+
+func (s *Server) startHookRunner() {
+	events, unsubscribe := s.events.Subscribe(64)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer unsubscribe()
+		s.hooks.Run(s.ctx, events)
+	}()
+}
+
+// registerJobs registers the periodic background jobs with the janitor,
+// including the scheduled backups of the specified repository and the
+// scheduled templates applied to it.
+func (s *Server) registerJobs(tasks todo.TaskRepository) error {
+	jobs := s.jobs
+	if s.backups != nil {
+		s.backups.Tasks = tasks
+		jobs = append(jobs, janitor.Job{
+			Name:     "backup",
+			Interval: s.backups.Interval,
+			Jitter:   s.backups.Interval / 10,
+			Run:      s.backups.Backup,
+		})
+	}
+	if s.templates != nil {
+		jobs = append(jobs, janitor.Job{
+			Name:     "templates",
+			Interval: time.Minute,
+			Jitter:   5 * time.Second,
+			Run: func(ctx context.Context) error {
+				// A server in read-only mode is handing over to a new
+				// instance, which applies the templates instead.
+				if s.readOnly.enabled.Load() {
+					return nil
+				}
+				loc := s.location
+				if loc == nil {
+					loc = time.Local
+				}
+				return s.templates.ApplyDue(ctx, tasks, time.Now().In(loc))
+			},
+		})
+	}
+	for _, job := range jobs {
+		if err := s.janitor.Register(job); err != nil {
+			return fmt.Errorf("cannot schedule background job: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Server) startJanitor() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.janitor.Run(s.ctx)
+	}()
+}
+
 // StopGracefully stops both the HTTP server and the gRPC server. It waits until
-// all active RPCs and HTTP requests are finished.
-func (s *Server) StopGracefully() error {
-	if s.grpcServer != nil {
+// all active RPCs and HTTP requests are finished, but at most for the specified
+// timeout. If the timeout expires, it stops both servers forcibly, cutting all
+// remaining connections. A timeout <= 0 means that there is no timeout.
+func (s *Server) StopGracefully(timeout time.Duration) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	defer s.wg.Wait()
+	defer s.cancel()
+
+	// Clients waiting for the server to become ready keep waiting for the
+	// next server instead.
+	s.health.Shutdown()
+	// Streaming RPCs and event streams don't finish on their own, so cancel
+	// them right away.
+	s.streams.cancelAll(nil)
+	grpcStopped := make(chan struct{})
+	go func() {
 		s.grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+
+	httpErr := s.httpServer.Shutdown(ctx)
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
 	}
-	if s.httpServer != nil {
-		return s.httpServer.Shutdown(context.Background())
+	if ctx.Err() == nil {
+		return httpErr
 	}
-	return nil
+
+	s.logger().Warn("graceful stop timed out, stopping server forcibly", "timeout", timeout)
+	s.conns.logActive(s.logger())
+	s.grpcServer.Stop()
+	<-grpcStopped
+	return s.httpServer.Close()
 }
@@ -0,0 +1,83 @@
+//go:build !windows
+
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+	"github.com/mwopitz/todo-daemon/internal/transport"
+)
+
+// hangingStore is a repository whose tasks cannot be listed via the
+// ListTasks RPC: the call hangs until it is canceled.
+type hangingStore struct {
+	*todo.InMemoryTaskDB
+	listing chan struct{}
+}
+
+func (s *hangingStore) List(ctx context.Context, opts *todo.ListOptions) (todo.Tasks, error) {
+	if method, _ := grpc.Method(ctx); method != todopb.TodoService_ListTasks_FullMethodName {
+		return s.InMemoryTaskDB.List(ctx, opts)
+	}
+	close(s.listing)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestStopGracefullyTimeout(t *testing.T) {
+	addr := transport.Address{Scheme: transport.SchemeUnix, Path: filepath.Join(t.TempDir(), "todo-daemon.sock")}
+	store := &hangingStore{InMemoryTaskDB: todo.NewInMemoryTaskDB(), listing: make(chan struct{})}
+	srv := New(WithHTTPListenAddress(HTTPListenAddress{}), WithStorage("test", store))
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(addr) }()
+
+	c, err := client.New(addr.String(), client.WithTimeout(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := c.WaitReady(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	listed := make(chan error, 1)
+	go func() {
+		_, err := c.FindTasks(t.Context(), &todopb.ListTasksRequest{})
+		listed <- err
+	}()
+	<-store.listing
+
+	// The call keeps its connection open past the timeout, so the server is
+	// stopped forcibly, which cancels the call.
+	const timeout = 100 * time.Millisecond
+	start := time.Now()
+	stopped := make(chan error, 1)
+	go func() { stopped <- srv.StopGracefully(timeout) }()
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Errorf("StopGracefully(): %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < timeout {
+			t.Errorf("want StopGracefully to wait for the timeout; returned after %v", elapsed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("want StopGracefully to return after the timeout")
+	}
+	select {
+	case err := <-listed:
+		if err == nil {
+			t.Error("want the hanging call to fail")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("want the hanging call to be cut")
+	}
+	<-served
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	middleware "github.com/grpc-ecosystem/go-grpc-middleware/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// streamCanceler cancels all active streaming RPCs and event streams when the
+// server stops, so long-lived streams, e.g. those of watching clients, don't
+// delay the graceful stop of the gRPC and HTTP servers.
+type streamCanceler struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+}
+
+func newStreamCanceler() *streamCanceler {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	return &streamCanceler{ctx: ctx, cancel: cancel}
+}
+
+func (c *streamCanceler) streamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := context.WithCancel(ss.Context())
+		defer cancel()
+		stop := context.AfterFunc(c.ctx, cancel)
+		defer stop()
+		wrapped := middleware.WrapServerStream(ss)
+		wrapped.WrappedContext = ctx
+		err := handler(srv, wrapped)
+		if err != nil && errors.Is(context.Cause(c.ctx), errHandedOver) {
+			// Clients reconnect to the new instance on this status code.
+			return status.Error(codes.Unavailable, errHandedOver.Error())
+		}
+		return err
+	}
+}
+
+// done returns a channel that is closed when the streams are canceled.
+func (c *streamCanceler) done() <-chan struct{} {
+	return c.ctx.Done()
+}
+
+// cancelAll cancels all active and future streaming RPCs. If cause is
+// [errHandedOver], the streaming RPCs fail with [codes.Unavailable].
+func (c *streamCanceler) cancelAll(cause error) {
+	c.cancel(cause)
+}
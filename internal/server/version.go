@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/logging"
+	"github.com/mwopitz/todo-daemon/internal/version"
+)
+
+// checkClientVersion rejects calls from CLIs older than [version.MinClient],
+// which might not understand the server's responses. Calls without version,
+// e.g. those of the gRPC gateway or grpcurl, are accepted. The Status and
+// GetCapabilities RPCs are always accepted, so even rejected CLIs can query the
+// server's version and capabilities.
+func checkClientVersion(ctx context.Context, method string) error {
+	switch method {
+	case todopb.TodoService_Status_FullMethodName, todopb.TodoService_GetCapabilities_FullMethodName:
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(version.MetadataKey)
+	if len(values) == 0 {
+		return nil
+	}
+	client, err := version.Parse(values[0])
+	if err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "ignoring invalid client version", "cause", err)
+		return nil
+	}
+	server := version.Current()
+	switch {
+	case client.Compare(version.MinClient) < 0:
+		return status.Errorf(codes.FailedPrecondition,
+			"CLI version %s is not supported by the server version %s, which requires at least CLI version %s; "+
+				"use the CLI of the same installation as the server",
+			client, server, version.MinClient)
+	case client.Compare(server) > 0:
+		logging.FromContext(ctx).WarnContext(ctx, "CLI is newer than the server; restart the server to use the new version",
+			"client_version", client, "server_version", server)
+	}
+	return nil
+}
+
+// versionUnaryInterceptor rejects unary RPCs of unsupported CLI versions.
+func versionUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := checkClientVersion(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// versionStreamInterceptor rejects streaming RPCs of unsupported CLI
+// versions.
+func versionStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkClientVersion(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
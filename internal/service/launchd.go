@@ -0,0 +1,221 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// launchdLabelPrefix is prepended to the names of the services to make the
+// labels of the launchd agents unique.
+const launchdLabelPrefix = "com.github.mwopitz."
+
+// launchd manages launchd agents, which are started when the user logs in.
+type launchd struct {
+	// dir is the directory of the agents' property lists, i.e.
+	// ~/Library/LaunchAgents.
+	dir string
+	// domain is the launchd domain of the user's agents, e.g. "gui/501".
+	domain string
+	run    runner
+}
+
+// newLaunchd creates the manager of the launchd agents of the current user.
+func newLaunchd() (*launchd, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return &launchd{
+		dir:    filepath.Join(home, "Library", "LaunchAgents"),
+		domain: "gui/" + strconv.Itoa(os.Getuid()),
+		run:    runCommand,
+	}, nil
+}
+
+func (*launchd) Kind() string {
+	return "launchd agent"
+}
+
+// path returns the path to the property list of the service.
+func (m *launchd) path(name string) string {
+	return filepath.Join(m.dir, launchdLabelPrefix+name+".plist")
+}
+
+// target returns the service target of the agent for launchctl, e.g.
+// "gui/501/com.github.mwopitz.todo-daemon".
+func (m *launchd) target(name string) string {
+	return m.domain + "/" + launchdLabelPrefix + name
+}
+
+func (m *launchd) Install(ctx context.Context, spec *Spec) error {
+	if err := os.MkdirAll(m.dir, 0o700); err != nil {
+		return err
+	}
+	if spec.LogFile != "" {
+		if err := os.MkdirAll(filepath.Dir(spec.LogFile), 0o700); err != nil {
+			return err
+		}
+	}
+	// An agent that is already loaded keeps its old definition until it is
+	// loaded again, so it's unloaded first.
+	if m.loaded(ctx, spec.Name) {
+		if _, err := m.run(ctx, "launchctl", "bootout", m.target(spec.Name)); err != nil {
+			return err
+		}
+	}
+	// The property list may hold secrets in the environment variables.
+	return os.WriteFile(m.path(spec.Name), propertyList(spec), 0o600)
+}
+
+func (m *launchd) Uninstall(ctx context.Context, name string) error {
+	if err := m.checkInstalled(name); err != nil {
+		return err
+	}
+	if m.loaded(ctx, name) {
+		if _, err := m.run(ctx, "launchctl", "bootout", m.target(name)); err != nil {
+			return err
+		}
+	}
+	return os.Remove(m.path(name))
+}
+
+// Start loads the agent, which starts it, or restarts it if it is already
+// loaded.
+func (m *launchd) Start(ctx context.Context, name string) error {
+	if err := m.checkInstalled(name); err != nil {
+		return err
+	}
+	if m.loaded(ctx, name) {
+		_, err := m.run(ctx, "launchctl", "kickstart", m.target(name))
+		return err
+	}
+	_, err := m.run(ctx, "launchctl", "bootstrap", m.domain, m.path(name))
+	return err
+}
+
+// Stop unloads the agent, since launchd would restart it otherwise. It is
+// loaded again at the next login.
+func (m *launchd) Stop(ctx context.Context, name string) error {
+	if err := m.checkInstalled(name); err != nil {
+		return err
+	}
+	if !m.loaded(ctx, name) {
+		return nil
+	}
+	_, err := m.run(ctx, "launchctl", "bootout", m.target(name))
+	return err
+}
+
+func (m *launchd) Status(ctx context.Context, name string) (*Status, error) {
+	if err := m.checkInstalled(name); err != nil {
+		return nil, err
+	}
+	status := &Status{Path: m.path(name)}
+	out, err := m.run(ctx, "launchctl", "print", m.target(name))
+	if err != nil {
+		// The agent is not loaded.
+		return status, nil
+	}
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(s.Text()), " = ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "state":
+			status.Running = value == "running"
+		case "pid":
+			status.PID, _ = strconv.Atoi(value)
+		}
+	}
+	return status, s.Err()
+}
+
+// loaded checks if the agent is loaded.
+func (m *launchd) loaded(ctx context.Context, name string) bool {
+	_, err := m.run(ctx, "launchctl", "print", m.target(name))
+	return err == nil
+}
+
+// checkInstalled returns [ErrNotInstalled] if the property list of the agent
+// doesn't exist.
+func (m *launchd) checkInstalled(name string) error {
+	if _, err := os.Stat(m.path(name)); errors.Is(err, os.ErrNotExist) {
+		return ErrNotInstalled
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// propertyList returns the content of the property list of the agent. The
+// agent is started at login and restarted if the server exits with an error.
+func propertyList(spec *Spec) []byte {
+	var b bytes.Buffer
+	str := func(s string) {
+		b.WriteString("<string>")
+		// Writing to a bytes.Buffer never fails.
+		_ = xml.EscapeText(&b, []byte(s))
+		b.WriteString("</string>")
+	}
+	key := func(s string) {
+		b.WriteString("<key>")
+		_ = xml.EscapeText(&b, []byte(s))
+		b.WriteString("</key>")
+	}
+	b.WriteString(xml.Header)
+	b.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	b.WriteString("<plist version=\"1.0\">\n<dict>\n")
+	b.WriteString("\t")
+	key("Label")
+	str(launchdLabelPrefix + spec.Name)
+	b.WriteString("\n\t")
+	key("ProgramArguments")
+	b.WriteString("\n\t<array>\n")
+	for _, arg := range append([]string{spec.Executable}, spec.Args...) {
+		b.WriteString("\t\t")
+		str(arg)
+		b.WriteString("\n")
+	}
+	b.WriteString("\t</array>\n")
+	if len(spec.Env) > 0 {
+		b.WriteString("\t")
+		key("EnvironmentVariables")
+		b.WriteString("\n\t<dict>\n")
+		names := make([]string, 0, len(spec.Env))
+		for name := range spec.Env {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			b.WriteString("\t\t")
+			key(name)
+			str(spec.Env[name])
+			b.WriteString("\n")
+		}
+		b.WriteString("\t</dict>\n")
+	}
+	if spec.LogFile != "" {
+		b.WriteString("\t")
+		key("StandardOutPath")
+		str(spec.LogFile)
+		b.WriteString("\n\t")
+		key("StandardErrorPath")
+		str(spec.LogFile)
+		b.WriteString("\n")
+	}
+	b.WriteString("\t<key>RunAtLoad</key><true/>\n")
+	b.WriteString("\t<key>KeepAlive</key><dict><key>SuccessfulExit</key><false/></dict>\n")
+	b.WriteString("\t<key>ProcessType</key><string>Background</string>\n")
+	b.WriteString("</dict>\n</plist>\n")
+	return b.Bytes()
+}
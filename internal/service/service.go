@@ -0,0 +1,101 @@
+// Package service installs the To-do Daemon server as a service of the
+// operating system, so that it starts at login without writing unit files by
+// hand: a launchd agent on macOS, a systemd user unit on Linux, and a Windows
+// service on Windows.
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ErrUnsupported is returned by [New] on operating systems without a
+// supported service manager.
+var ErrUnsupported = errors.New("services are not supported on this operating system")
+
+// ErrNotInstalled is returned by the [Manager] for services that are not
+// installed.
+var ErrNotInstalled = errors.New("service is not installed")
+
+// Spec describes a service running the To-do Daemon server.
+type Spec struct {
+	// Name is the name of the service, e.g. "todo-daemon" or
+	// "todo-daemon-work" for the profile "work".
+	Name string
+	// Description is the human-readable description of the service.
+	Description string
+	// Executable is the absolute path to the todo-daemon binary.
+	Executable string
+	// Args are the arguments the binary is run with, e.g. ["run"].
+	Args []string
+	// Env holds the environment variables of the service.
+	Env map[string]string
+	// LogFile is the file that the log of the server is written to if the
+	// service manager doesn't keep it, like systemd does in its journal. On
+	// Windows, it is passed to the server with --log-file, so Args must end
+	// with the 'run' command. If empty, the log is discarded.
+	LogFile string
+	// Password is the password of the user account that the service runs
+	// as. It is only needed for Windows services.
+	Password string
+}
+
+// Status is the status of an installed service.
+type Status struct {
+	// Path is the file defining the service, e.g. the unit file, or the
+	// name of the service on Windows.
+	Path string
+	// Running specifies whether the service is running.
+	Running bool
+	// PID is the process ID of the running service, or zero.
+	PID int
+}
+
+// Manager installs and controls services.
+type Manager interface {
+	// Kind describes the kind of services, e.g. "systemd user unit".
+	Kind() string
+	// Install installs the service, or updates it if it is already
+	// installed, and enables it, so it starts at login. It doesn't start
+	// the service.
+	Install(ctx context.Context, spec *Spec) error
+	// Uninstall stops the service and removes it.
+	Uninstall(ctx context.Context, name string) error
+	// Start starts the service.
+	Start(ctx context.Context, name string) error
+	// Stop stops the service. It keeps being started at login.
+	Stop(ctx context.Context, name string) error
+	// Status returns the status of the service, or [ErrNotInstalled].
+	Status(ctx context.Context, name string) (*Status, error)
+}
+
+// New returns the [Manager] of the services of the current operating system,
+// or [ErrUnsupported].
+func New() (Manager, error) {
+	return newManager()
+}
+
+// runner runs a command of the service manager, e.g. systemctl, and returns
+// its output. The tests replace it to record the commands.
+type runner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+// runCommand is the [runner] that runs the command. If the command fails,
+// the error includes the command and its error output.
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...) // #nosec G204 -- the commands are fixed.
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return out, fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+		}
+		return out, fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, msg)
+	}
+	return out, nil
+}
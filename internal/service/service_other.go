@@ -0,0 +1,25 @@
+//go:build !windows
+
+package service
+
+import (
+	"context"
+	"runtime"
+)
+
+func newManager() (Manager, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return newLaunchd()
+	case "linux":
+		return newSystemd()
+	default:
+		return nil, ErrUnsupported
+	}
+}
+
+// Control does nothing, since only Windows services need to be connected to
+// the service manager; the others are stopped by signals.
+func Control(string, context.CancelCauseFunc) func(error) {
+	return func(error) {}
+}
@@ -0,0 +1,227 @@
+//go:build !windows
+
+package service
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeRunner records the commands it runs and returns the output configured
+// for them.
+type fakeRunner struct {
+	commands []string
+	outputs  map[string]string
+	failures map[string]bool
+}
+
+func (r *fakeRunner) run(_ context.Context, name string, args ...string) ([]byte, error) {
+	cmd := strings.Join(append([]string{name}, args...), " ")
+	r.commands = append(r.commands, cmd)
+	if r.failures[cmd] {
+		return nil, errors.New("exit status 1")
+	}
+	return []byte(r.outputs[cmd]), nil
+}
+
+func testSpec() *Spec {
+	return &Spec{
+		Name:        "todo-daemon",
+		Description: "To-do Daemon server (profile default)",
+		Executable:  "/opt/todo daemon/todo-daemon",
+		Args:        []string{"run"},
+		Env:         map[string]string{"TODO_DAEMON_CONFIG": "/home/me/config.json", "TODO_DAEMON_TOKEN": "100%"},
+		LogFile:     "/home/me/.local/share/todo-daemon/service.log",
+	}
+}
+
+func TestSystemdQuote(t *testing.T) {
+	tests := map[string]string{
+		"run":           "run",
+		"/opt/todo":     "/opt/todo",
+		"":              `""`,
+		"a b":           `"a b"`,
+		`say "hi"`:      `"say \"hi\""`,
+		"100%":          "100%%",
+		"$HOME/x":       "$$HOME/x",
+		"A=line1\nend":  `"A=line1\nend"`,
+		`C:\todo; rm x`: `"C:\\todo; rm x"`,
+	}
+	for arg, want := range tests {
+		if got := systemdQuote(arg); got != want {
+			t.Errorf("%q: want: %s; got: %s", arg, want, got)
+		}
+	}
+}
+
+func TestUnitFile(t *testing.T) {
+	want := `[Unit]
+Description=To-do Daemon server (profile default)
+Documentation=https://github.com/mwopitz/todo-daemon
+
+[Service]
+ExecStart="/opt/todo daemon/todo-daemon" run
+ExecReload=/bin/kill -HUP $MAINPID
+Environment=TODO_DAEMON_CONFIG=/home/me/config.json
+Environment=TODO_DAEMON_TOKEN=100%%
+Restart=on-failure
+RestartSec=5
+
+[Install]
+WantedBy=default.target
+`
+	if got := unitFile(testSpec()); got != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestSystemd(t *testing.T) {
+	r := &fakeRunner{outputs: map[string]string{
+		"systemctl --user show todo-daemon.service --property=ActiveState --property=MainPID": "ActiveState=active\nMainPID=4242\n",
+	}}
+	m := &systemd{dir: filepath.Join(t.TempDir(), "systemd", "user"), run: r.run}
+	ctx := context.Background()
+
+	if _, err := m.Status(ctx, "todo-daemon"); !errors.Is(err, ErrNotInstalled) {
+		t.Fatalf("want: %v; got: %v", ErrNotInstalled, err)
+	}
+	if err := m.Install(ctx, testSpec()); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(m.path("todo-daemon"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("want permissions 0600; got: %#o", perm)
+	}
+	if err := m.Start(ctx, "todo-daemon"); err != nil {
+		t.Fatal(err)
+	}
+	status, err := m.Status(ctx, "todo-daemon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Status{Path: m.path("todo-daemon"), Running: true, PID: 4242}
+	if *status != want {
+		t.Errorf("want: %+v; got: %+v", want, *status)
+	}
+	if err := m.Uninstall(ctx, "todo-daemon"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(m.path("todo-daemon")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("unit file not removed: %v", err)
+	}
+	if err := m.Stop(ctx, "todo-daemon"); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("want: %v; got: %v", ErrNotInstalled, err)
+	}
+
+	wantCommands := []string{
+		"systemctl --user daemon-reload",
+		"systemctl --user enable todo-daemon.service",
+		"systemctl --user start todo-daemon.service",
+		"systemctl --user show todo-daemon.service --property=ActiveState --property=MainPID",
+		"systemctl --user disable --now todo-daemon.service",
+		"systemctl --user daemon-reload",
+	}
+	if !slices.Equal(r.commands, wantCommands) {
+		t.Errorf("want: %q; got: %q", wantCommands, r.commands)
+	}
+}
+
+func TestPropertyList(t *testing.T) {
+	spec := testSpec()
+	spec.Args = []string{"--profile", "<work & play>", "run"}
+	data := propertyList(spec)
+
+	// The property list must be well-formed, so the arguments are escaped.
+	var plist struct {
+		Dict struct {
+			Keys    []string `xml:"key"`
+			Strings []string `xml:"string"`
+			Array   struct {
+				Strings []string `xml:"string"`
+			} `xml:"array"`
+		} `xml:"dict"`
+	}
+	d := xml.NewDecoder(strings.NewReader(string(data)))
+	d.Strict = false
+	if err := d.Decode(&plist); err != nil {
+		t.Fatalf("invalid property list: %v\n%s", err, data)
+	}
+	wantArgs := []string{"/opt/todo daemon/todo-daemon", "--profile", "<work & play>", "run"}
+	if !slices.Equal(plist.Dict.Array.Strings, wantArgs) {
+		t.Errorf("want arguments: %q; got: %q", wantArgs, plist.Dict.Array.Strings)
+	}
+	wantKeys := []string{"Label", "ProgramArguments", "EnvironmentVariables", "StandardOutPath",
+		"StandardErrorPath", "RunAtLoad", "KeepAlive", "ProcessType"}
+	if !slices.Equal(plist.Dict.Keys, wantKeys) {
+		t.Errorf("want keys: %q; got: %q", wantKeys, plist.Dict.Keys)
+	}
+	if plist.Dict.Strings[0] != "com.github.mwopitz.todo-daemon" {
+		t.Errorf("want label: com.github.mwopitz.todo-daemon; got: %s", plist.Dict.Strings[0])
+	}
+}
+
+func TestLaunchd(t *testing.T) {
+	target := "gui/501/com.github.mwopitz.todo-daemon"
+	r := &fakeRunner{
+		outputs: map[string]string{
+			"launchctl print " + target: "com.github.mwopitz.todo-daemon = {\n\tstate = running\n\tpid = 4242\n}\n",
+		},
+		// The agent isn't loaded until it is started.
+		failures: map[string]bool{"launchctl print " + target: true},
+	}
+	m := &launchd{dir: t.TempDir(), domain: "gui/501", run: r.run}
+	ctx := context.Background()
+
+	spec := testSpec()
+	spec.LogFile = filepath.Join(t.TempDir(), "logs", "service.log")
+	if err := m.Install(ctx, spec); err != nil {
+		t.Fatal(err)
+	}
+	status, err := m.Status(ctx, "todo-daemon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Running {
+		t.Error("agent running before it was started")
+	}
+	if err := m.Start(ctx, "todo-daemon"); err != nil {
+		t.Fatal(err)
+	}
+	delete(r.failures, "launchctl print "+target)
+	status, err = m.Status(ctx, "todo-daemon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Status{Path: m.path("todo-daemon"), Running: true, PID: 4242}
+	if *status != want {
+		t.Errorf("want: %+v; got: %+v", want, *status)
+	}
+	if err := m.Uninstall(ctx, "todo-daemon"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Status(ctx, "todo-daemon"); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("want: %v; got: %v", ErrNotInstalled, err)
+	}
+
+	wantCommands := []string{
+		"launchctl print " + target, // Install
+		"launchctl print " + target, // Status
+		"launchctl print " + target, // Start
+		"launchctl bootstrap gui/501 " + m.path("todo-daemon"),
+		"launchctl print " + target, // Status
+		"launchctl print " + target, // Uninstall
+		"launchctl bootout " + target,
+	}
+	if !slices.Equal(r.commands, wantCommands) {
+		t.Errorf("want: %q; got: %q", wantCommands, r.commands)
+	}
+}
@@ -0,0 +1,382 @@
+//go:build windows
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/user"
+	"slices"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// stopTimeout is the maximum time to wait for a service to stop.
+const stopTimeout = 30 * time.Second
+
+func newManager() (Manager, error) {
+	return &windowsManager{}, nil
+}
+
+// windowsManager manages Windows services. They run as the user who installed
+// them, since only the owner of the server's named pipe may connect to it, and
+// are started at boot. Installing, starting, and stopping them requires an
+// elevated prompt.
+type windowsManager struct{}
+
+func (*windowsManager) Kind() string {
+	return "Windows service"
+}
+
+// connect connects to the service control manager with full access.
+func connect() (*mgr.Mgr, error) {
+	m, err := mgr.Connect()
+	if errors.Is(err, windows.ERROR_ACCESS_DENIED) {
+		return nil, fmt.Errorf("cannot connect to the service control manager, run as administrator: %w", err)
+	}
+	return m, err
+}
+
+// open opens the service with full access, or returns [ErrNotInstalled].
+func open(m *mgr.Mgr, name string) (*mgr.Service, error) {
+	s, err := m.OpenService(name)
+	if errors.Is(err, windows.ERROR_SERVICE_DOES_NOT_EXIST) {
+		return nil, ErrNotInstalled
+	}
+	return s, err
+}
+
+func (*windowsManager) Install(_ context.Context, spec *Spec) error {
+	u, err := user.Current()
+	if err != nil {
+		return err
+	}
+	sid, err := windows.StringToSid(u.Uid)
+	if err != nil {
+		return err
+	}
+	m, err := connect()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = m.Disconnect() }()
+	// Services can only run as users with the right to log on as a service,
+	// which the Services console grants implicitly, but the API doesn't.
+	if err := grantServiceLogon(sid); err != nil {
+		return fmt.Errorf("cannot grant the right to log on as a service: %w", err)
+	}
+
+	conf := mgr.Config{
+		DisplayName:      "To-do Daemon (" + spec.Name + ")",
+		Description:      spec.Description,
+		StartType:        mgr.StartAutomatic,
+		DelayedAutoStart: true,
+		ServiceStartName: u.Username,
+		Password:         spec.Password,
+	}
+	args := spec.Args
+	if spec.LogFile != "" {
+		// Services have no stderr, so the server writes its log itself.
+		args = append(slices.Clip(args), "--log-file", spec.LogFile)
+	}
+	s, err := open(m, spec.Name)
+	switch {
+	case errors.Is(err, ErrNotInstalled):
+		s, err = m.CreateService(spec.Name, spec.Executable, conf, args...)
+		if err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		conf.BinaryPathName = commandLine(spec.Executable, args)
+		// UpdateConfig replaces all settings, so the unchanged ones are
+		// copied.
+		old, err := s.Config()
+		if err != nil {
+			_ = s.Close()
+			return err
+		}
+		conf.ServiceType, conf.ErrorControl = old.ServiceType, old.ErrorControl
+		if err := s.UpdateConfig(conf); err != nil {
+			_ = s.Close()
+			return err
+		}
+	}
+	defer func() { _ = s.Close() }()
+
+	// Restart the server if it exits with an error, like the other service
+	// managers do.
+	if err := s.SetRecoveryActions([]mgr.RecoveryAction{
+		{Type: mgr.ServiceRestart, Delay: 5 * time.Second},
+	}, uint32((24 * time.Hour).Seconds())); err != nil {
+		return err
+	}
+	return setEnvironment(spec.Name, spec.Env)
+}
+
+func (*windowsManager) Uninstall(ctx context.Context, name string) error {
+	m, err := connect()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = m.Disconnect() }()
+	s, err := open(m, name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = s.Close() }()
+	if err := stop(ctx, s); err != nil {
+		return err
+	}
+	return s.Delete()
+}
+
+func (*windowsManager) Start(_ context.Context, name string) error {
+	m, err := connect()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = m.Disconnect() }()
+	s, err := open(m, name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = s.Close() }()
+	err = s.Start()
+	if errors.Is(err, windows.ERROR_SERVICE_ALREADY_RUNNING) {
+		return nil
+	}
+	return err
+}
+
+func (*windowsManager) Stop(ctx context.Context, name string) error {
+	m, err := connect()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = m.Disconnect() }()
+	s, err := open(m, name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = s.Close() }()
+	return stop(ctx, s)
+}
+
+// Status queries the service with the access that all users have, so it
+// doesn't require an elevated prompt.
+func (*windowsManager) Status(_ context.Context, name string) (*Status, error) {
+	scm, err := windows.OpenSCManager(nil, nil, windows.SC_MANAGER_CONNECT)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = windows.CloseServiceHandle(scm) }()
+	namep, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+	h, err := windows.OpenService(scm, namep, windows.SERVICE_QUERY_STATUS)
+	if errors.Is(err, windows.ERROR_SERVICE_DOES_NOT_EXIST) {
+		return nil, ErrNotInstalled
+	}
+	if err != nil {
+		return nil, err
+	}
+	s := &mgr.Service{Name: name, Handle: h}
+	defer func() { _ = s.Close() }()
+	st, err := s.Query()
+	if err != nil {
+		return nil, err
+	}
+	return &Status{Path: name, Running: st.State == svc.Running, PID: int(st.ProcessId)}, nil
+}
+
+// stop stops the service and waits until it has stopped.
+func stop(ctx context.Context, s *mgr.Service) error {
+	st, err := s.Query()
+	if err != nil {
+		return err
+	}
+	if st.State == svc.Stopped {
+		return nil
+	}
+	if st.State != svc.StopPending {
+		if st, err = s.Control(svc.Stop); err != nil {
+			return err
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, stopTimeout)
+	defer cancel()
+	for st.State != svc.Stopped {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("service did not stop: %w", ctx.Err())
+		case <-time.After(300 * time.Millisecond):
+		}
+		if st, err = s.Query(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commandLine returns the command line of the executable with the specified
+// arguments, quoted like [mgr.Mgr.CreateService] does.
+func commandLine(exe string, args []string) string {
+	s := syscall.EscapeArg(exe)
+	for _, arg := range args {
+		s += " " + syscall.EscapeArg(arg)
+	}
+	return s
+}
+
+// setEnvironment sets the environment variables of the service, which the
+// service control manager reads from the service's registry key.
+func setEnvironment(name string, env map[string]string) error {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, `SYSTEM\CurrentControlSet\Services\`+name, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = k.Close() }()
+	if len(env) == 0 {
+		err := k.DeleteValue("Environment")
+		if errors.Is(err, windows.ERROR_FILE_NOT_FOUND) {
+			return nil
+		}
+		return err
+	}
+	vars := make([]string, 0, len(env))
+	for name, value := range env {
+		vars = append(vars, name+"="+value)
+	}
+	slices.Sort(vars)
+	return k.SetStringsValue("Environment", vars)
+}
+
+var (
+	advapi32                  = windows.NewLazySystemDLL("advapi32.dll")
+	procLsaOpenPolicy         = advapi32.NewProc("LsaOpenPolicy")
+	procLsaAddAccountRights   = advapi32.NewProc("LsaAddAccountRights")
+	procLsaClose              = advapi32.NewProc("LsaClose")
+	procLsaNtStatusToWinError = advapi32.NewProc("LsaNtStatusToWinError")
+)
+
+// lsaUnicodeString is the LSA_UNICODE_STRING structure.
+type lsaUnicodeString struct {
+	Length        uint16
+	MaximumLength uint16
+	Buffer        *uint16
+}
+
+// lsaObjectAttributes is the LSA_OBJECT_ATTRIBUTES structure, which is
+// reserved and must be zero except for its length.
+type lsaObjectAttributes struct {
+	Length                   uint32
+	RootDirectory            windows.Handle
+	ObjectName               *lsaUnicodeString
+	Attributes               uint32
+	SecurityDescriptor       uintptr
+	SecurityQualityOfService uintptr
+}
+
+// grantServiceLogon grants the account with the specified SID the right to
+// log on as a service. Granting it again has no effect.
+func grantServiceLogon(sid *windows.SID) error {
+	const policyCreateAccount, policyLookupNames = 0x10, 0x800
+	attrs := lsaObjectAttributes{}
+	attrs.Length = uint32(unsafe.Sizeof(attrs))
+	var policy windows.Handle
+	status, _, _ := procLsaOpenPolicy.Call(0, uintptr(unsafe.Pointer(&attrs)),
+		policyCreateAccount|policyLookupNames, uintptr(unsafe.Pointer(&policy)))
+	if status != 0 {
+		return ntStatusError(status)
+	}
+	defer func() {
+		_, _, _ = procLsaClose.Call(uintptr(policy))
+	}()
+	right, err := windows.UTF16FromString("SeServiceLogonRight")
+	if err != nil {
+		return err
+	}
+	s := lsaUnicodeString{
+		Length:        uint16((len(right) - 1) * 2),
+		MaximumLength: uint16(len(right) * 2),
+		Buffer:        &right[0],
+	}
+	status, _, _ = procLsaAddAccountRights.Call(uintptr(policy), uintptr(unsafe.Pointer(sid)),
+		uintptr(unsafe.Pointer(&s)), 1)
+	if status != 0 {
+		return ntStatusError(status)
+	}
+	return nil
+}
+
+// ntStatusError converts an NTSTATUS code returned by the LSA functions to an
+// error.
+func ntStatusError(status uintptr) error {
+	code, _, _ := procLsaNtStatusToWinError.Call(status)
+	return syscall.Errno(code)
+}
+
+// Control connects the process to the Windows service control manager if it
+// has been started as a service, so that stopping the service cancels the
+// context like a signal does. The process must call the returned function
+// with the result of the server before it exits, so the service manager
+// learns whether the service failed.
+func Control(name string, cancel context.CancelCauseFunc) func(error) {
+	isService, err := svc.IsWindowsService()
+	if err != nil || !isService {
+		return func(error) {}
+	}
+	h := &handler{cancel: cancel, exited: make(chan error, 1)}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		if err := svc.Run(name, h); err != nil {
+			slog.Error("cannot run as Windows service", "cause", err)
+		}
+	}()
+	return func(err error) {
+		h.exited <- err
+		<-stopped
+	}
+}
+
+// handler is the [svc.Handler] of the To-do Daemon server.
+type handler struct {
+	cancel context.CancelCauseFunc
+	exited chan error
+}
+
+func (h *handler) Execute(_ []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	for {
+		select {
+		case err := <-h.exited:
+			changes <- svc.Status{State: svc.StopPending}
+			if err != nil {
+				// A service-specific exit code makes the service manager
+				// apply the recovery actions.
+				return true, 1
+			}
+			return false, 0
+		case r := <-requests:
+			switch r.Cmd {
+			case svc.Interrogate:
+				changes <- r.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				changes <- svc.Status{State: svc.StopPending}
+				h.cancel(errors.New("stopped by the service control manager"))
+			}
+		}
+	}
+}
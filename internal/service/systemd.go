@@ -0,0 +1,157 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// systemd manages systemd user units, which are started when the user logs in
+// and stopped when the user's last session ends.
+type systemd struct {
+	// dir is the directory of the user units, e.g. ~/.config/systemd/user.
+	dir string
+	run runner
+}
+
+// newSystemd creates the manager of the systemd user units in the user's
+// configuration directory.
+func newSystemd() (*systemd, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return &systemd{dir: filepath.Join(dir, "systemd", "user"), run: runCommand}, nil
+}
+
+func (*systemd) Kind() string {
+	return "systemd user unit"
+}
+
+// path returns the path to the unit file of the service.
+func (m *systemd) path(name string) string {
+	return filepath.Join(m.dir, name+".service")
+}
+
+func (m *systemd) systemctl(ctx context.Context, args ...string) ([]byte, error) {
+	return m.run(ctx, "systemctl", append([]string{"--user"}, args...)...)
+}
+
+func (m *systemd) Install(ctx context.Context, spec *Spec) error {
+	if err := os.MkdirAll(m.dir, 0o700); err != nil {
+		return err
+	}
+	// The unit file may hold secrets in the environment variables.
+	if err := os.WriteFile(m.path(spec.Name), []byte(unitFile(spec)), 0o600); err != nil {
+		return err
+	}
+	if _, err := m.systemctl(ctx, "daemon-reload"); err != nil {
+		return err
+	}
+	_, err := m.systemctl(ctx, "enable", spec.Name+".service")
+	return err
+}
+
+func (m *systemd) Uninstall(ctx context.Context, name string) error {
+	if err := m.checkInstalled(name); err != nil {
+		return err
+	}
+	if _, err := m.systemctl(ctx, "disable", "--now", name+".service"); err != nil {
+		return err
+	}
+	if err := os.Remove(m.path(name)); err != nil {
+		return err
+	}
+	_, err := m.systemctl(ctx, "daemon-reload")
+	return err
+}
+
+func (m *systemd) Start(ctx context.Context, name string) error {
+	if err := m.checkInstalled(name); err != nil {
+		return err
+	}
+	_, err := m.systemctl(ctx, "start", name+".service")
+	return err
+}
+
+func (m *systemd) Stop(ctx context.Context, name string) error {
+	if err := m.checkInstalled(name); err != nil {
+		return err
+	}
+	_, err := m.systemctl(ctx, "stop", name+".service")
+	return err
+}
+
+func (m *systemd) Status(ctx context.Context, name string) (*Status, error) {
+	if err := m.checkInstalled(name); err != nil {
+		return nil, err
+	}
+	out, err := m.systemctl(ctx, "show", name+".service", "--property=ActiveState", "--property=MainPID")
+	if err != nil {
+		return nil, err
+	}
+	status := &Status{Path: m.path(name)}
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		key, value, _ := strings.Cut(s.Text(), "=")
+		switch key {
+		case "ActiveState":
+			status.Running = value == "active" || value == "reloading"
+		case "MainPID":
+			status.PID, _ = strconv.Atoi(value)
+		}
+	}
+	return status, s.Err()
+}
+
+// checkInstalled returns [ErrNotInstalled] if the unit file of the service
+// doesn't exist.
+func (m *systemd) checkInstalled(name string) error {
+	if _, err := os.Stat(m.path(name)); errors.Is(err, os.ErrNotExist) {
+		return ErrNotInstalled
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// unitFile returns the content of the unit file of the service.
+func unitFile(spec *Spec) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Unit]\nDescription=%s\nDocumentation=https://github.com/mwopitz/todo-daemon\n\n", spec.Description)
+	b.WriteString("[Service]\n")
+	args := make([]string, 0, len(spec.Args)+1)
+	for _, arg := range append([]string{spec.Executable}, spec.Args...) {
+		args = append(args, systemdQuote(arg))
+	}
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(args, " "))
+	b.WriteString("ExecReload=/bin/kill -HUP $MAINPID\n")
+	names := make([]string, 0, len(spec.Env))
+	for name := range spec.Env {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "Environment=%s\n", systemdQuote(name+"="+spec.Env[name]))
+	}
+	b.WriteString("Restart=on-failure\nRestartSec=5\n\n")
+	b.WriteString("[Install]\nWantedBy=default.target\n")
+	return b.String()
+}
+
+// systemdQuote quotes the specified argument for a unit file if needed, and
+// escapes the specifiers and variables, which systemd would expand otherwise.
+func systemdQuote(s string) string {
+	s = strings.NewReplacer("%", "%%", "$", "$$").Replace(s)
+	if s != "" && !strings.ContainsAny(s, " \t\n\"'\\;") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,349 @@
+// Package standalone implements the standalone mode of the To-do Daemon CLI,
+// in which the 'tasks' commands operate on the to-do list in-process instead
+// of calling a To-do Daemon server.
+//
+// A standalone command acquires the same lock file as the server, so it fails
+// while the server, or another standalone command, is running. Since no
+// server is involved, neither webhooks nor hooks are triggered by changes.
+package standalone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/client"
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/lockfile"
+	"github.com/mwopitz/todo-daemon/internal/storage"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// ErrLocked is returned by [Open] if the lock file is held by another
+// process, usually a running To-do Daemon server.
+var ErrLocked = errors.New("the to-do list is in use by another process")
+
+// ErrWatchUnsupported is returned by [Service.WatchTasks], since nothing else
+// can change the to-do list while a standalone command holds the lock.
+var ErrWatchUnsupported = errors.New("watching tasks is not supported in standalone mode")
+
+// Service is a [client.TaskService] that executes the operations on the task
+// repository in-process, through the same controller as the server's gRPC
+// API, so that validation and errors are the same in both modes.
+type Service struct {
+	lock  *lockfile.Lock
+	store storage.Store
+	ctrl  *todo.Controller
+}
+
+var _ client.TaskService = (*Service)(nil)
+
+// Open acquires the lock file of the specified configuration and opens its
+// database. The lock is held until the service is closed.
+func Open(conf *config.Config) (*Service, error) {
+	backend, err := storage.DriverName(conf.Database)
+	if err != nil {
+		return nil, err
+	}
+	lock := lockfile.New(conf.LockFile)
+	locked, _, err := lock.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("cannot acquire lock file: %w", err)
+	}
+	if !locked {
+		pid, err := lockfile.ReadPID(lock.Path())
+		if err != nil || pid == 0 {
+			return nil, fmt.Errorf("%w: lock file %s is held", ErrLocked, lock.Path())
+		}
+		return nil, fmt.Errorf("%w (PID %d)", ErrLocked, pid)
+	}
+	store, err := storage.Open(context.Background(), conf.Database)
+	if err != nil {
+		return nil, errors.Join(err, lock.Unlock())
+	}
+	if backend == storage.Memory {
+		slog.Warn("the in-memory database does not keep tasks between standalone commands")
+	}
+	filters, err := openFilters(conf)
+	if err != nil {
+		return nil, errors.Join(err, store.Close(), lock.Unlock())
+	}
+	loc, err := conf.Location()
+	if err != nil {
+		return nil, errors.Join(err, store.Close(), lock.Unlock())
+	}
+	return &Service{
+		lock:  lock,
+		store: store,
+		ctrl: todo.NewController(nil, nil, withQuotas(store, todo.Quotas(conf.Quotas)), todo.NewEventBus(),
+			todo.WithStrictDependencies(conf.StrictDependencies), todo.WithUniqueSummaries(conf.UniqueSummaries),
+			todo.WithTimeZone(loc), todo.WithFilters(filters)),
+	}, nil
+}
+
+// withQuotas wraps the specified repository, so that it enforces the specified
+// quotas like the server does, if any.
+func withQuotas(tasks todo.TaskRepository, quotas todo.Quotas) todo.TaskRepository {
+	if !quotas.Enabled() {
+		return tasks
+	}
+	return todo.NewQuotaRepository(tasks, quotas)
+}
+
+// openFilters returns the registry of the named filters of the specified
+// configuration, i.e. those in the configuration file and those saved via the
+// server's API.
+func openFilters(conf *config.Config) (*todo.FilterRegistry, error) {
+	filters, err := todo.NewFilters(conf.Filters)
+	if err != nil {
+		return nil, err
+	}
+	r, err := todo.NewFilterRegistry(conf.FiltersFile())
+	if err != nil {
+		return nil, err
+	}
+	if err := r.SetConfigured(filters); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Factory returns a [client.TaskServiceFactory] that opens the to-do list of
+// the specified configuration with [Open]. The server address and the client
+// options passed to the factory are ignored.
+func Factory(conf *config.Config) client.TaskServiceFactory {
+	return func(string, ...client.Option) (client.TaskService, error) {
+		s, err := Open(conf)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+}
+
+// Close closes the database and releases the lock file.
+func (s *Service) Close() error {
+	return errors.Join(s.store.Close(), s.lock.Unlock())
+}
+
+// CreateTask creates the specified task in the to-do list.
+func (s *Service) CreateTask(ctx context.Context, task *todopb.NewTask) (*todopb.Task, error) {
+	resp, err := s.ctrl.CreateTask(ctx, &todopb.CreateTaskRequest{Task: task})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create task: %w", err)
+	}
+	return resp.GetTask(), nil
+}
+
+// BatchCreateTasks creates the specified tasks in the to-do list at once. It
+// returns the created tasks in the same order.
+func (s *Service) BatchCreateTasks(ctx context.Context, tasks []*todopb.NewTask) ([]*todopb.Task, error) {
+	resp, err := s.ctrl.BatchCreateTasks(ctx, &todopb.BatchCreateTasksRequest{Tasks: tasks})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create tasks: %w", err)
+	}
+	return resp.GetTasks(), nil
+}
+
+// ListTasks retrieves all tasks in the to-do list.
+func (s *Service) ListTasks(ctx context.Context) ([]*todopb.Task, error) {
+	return s.FindTasks(ctx, &todopb.ListTasksRequest{})
+}
+
+// FindTasks retrieves the tasks matching the specified filter.
+func (s *Service) FindTasks(ctx context.Context, filter *todopb.ListTasksRequest) ([]*todopb.Task, error) {
+	resp, err := s.ctrl.ListTasks(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetTasks(), nil
+}
+
+// ResolveTask retrieves the task that the specified reference, i.e. its ID,
+// its short code, or a unique prefix of its short code, refers to.
+func (s *Service) ResolveTask(ctx context.Context, ref string) (*todopb.Task, error) {
+	resp, err := s.ctrl.ResolveTask(ctx, &todopb.ResolveTaskRequest{Ref: ref})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetTask(), nil
+}
+
+// MoveTask moves the task with the specified ID in the manual order of the
+// to-do list, right before or after another task.
+func (s *Service) MoveTask(ctx context.Context, id, beforeID, afterID string) (*todopb.Task, error) {
+	resp, err := s.ctrl.MoveTask(ctx, &todopb.MoveTaskRequest{
+		Id:       id,
+		BeforeId: beforeID,
+		AfterId:  afterID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot move task: %w", err)
+	}
+	return resp.GetTask(), nil
+}
+
+// SearchTasks searches the summaries and descriptions of the tasks in the
+// to-do list. If limit is zero, all matching tasks are returned.
+func (s *Service) SearchTasks(ctx context.Context, query string, limit uint32) ([]*todopb.SearchResult, error) {
+	resp, err := s.ctrl.SearchTasks(ctx, &todopb.SearchTasksRequest{Q: query, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetResults(), nil
+}
+
+// CompleteTask marks the specified task as completed.
+func (s *Service) CompleteTask(ctx context.Context, id string) (*todopb.Task, error) {
+	update := &todopb.TaskUpdate{CompletedAt: timestamppb.Now()}
+	fields, err := fieldmaskpb.New(update, "completed_at")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.ctrl.UpdateTask(ctx, &todopb.UpdateTaskRequest{
+		Id:     id,
+		Update: update,
+		Fields: fields,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetTask(), nil
+}
+
+// SetDependencies replaces the IDs of the tasks that the specified task depends
+// on.
+func (s *Service) SetDependencies(ctx context.Context, id string, dependsOn []string) (*todopb.Task, error) {
+	update := &todopb.TaskUpdate{DependsOn: dependsOn}
+	fields, err := fieldmaskpb.New(update, "depends_on")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.ctrl.UpdateTask(ctx, &todopb.UpdateTaskRequest{
+		Id:     id,
+		Update: update,
+		Fields: fields,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetTask(), nil
+}
+
+// SetStarred stars or unstars the specified task.
+func (s *Service) SetStarred(ctx context.Context, id string, starred bool) (*todopb.Task, error) {
+	update := &todopb.TaskUpdate{Starred: starred}
+	fields, err := fieldmaskpb.New(update, "starred")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.ctrl.UpdateTask(ctx, &todopb.UpdateTaskRequest{
+		Id:     id,
+		Update: update,
+		Fields: fields,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetTask(), nil
+}
+
+// SetAssignee assigns the specified task to the specified user, or unassigns
+// it if the user is empty.
+func (s *Service) SetAssignee(ctx context.Context, id, assignee string) (*todopb.Task, error) {
+	update := &todopb.TaskUpdate{Assignee: assignee}
+	fields, err := fieldmaskpb.New(update, "assignee")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.ctrl.UpdateTask(ctx, &todopb.UpdateTaskRequest{
+		Id:     id,
+		Update: update,
+		Fields: fields,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetTask(), nil
+}
+
+// DeleteTask removes the specified task from the to-do list.
+func (s *Service) DeleteTask(ctx context.Context, id string) error {
+	_, err := s.ctrl.DeleteTask(ctx, &todopb.DeleteTaskRequest{Id: id})
+	if err != nil {
+		return fmt.Errorf("cannot delete task: %w", err)
+	}
+	return nil
+}
+
+// RestoreTask moves the specified task from the trash back to the to-do list.
+func (s *Service) RestoreTask(ctx context.Context, id string) (*todopb.Task, error) {
+	resp, err := s.ctrl.RestoreTask(ctx, &todopb.RestoreTaskRequest{Id: id})
+	if err != nil {
+		return nil, fmt.Errorf("cannot restore task: %w", err)
+	}
+	return resp.GetTask(), nil
+}
+
+// DuplicateTask creates the specified number of copies of a task, with the
+// suffix appended to their summaries. The first copy is due dueShift after
+// the task, and each further copy dueShift after the previous one.
+func (s *Service) DuplicateTask(
+	ctx context.Context,
+	id string,
+	count int,
+	suffix string,
+	dueShift time.Duration,
+) ([]*todopb.Task, error) {
+	resp, err := s.ctrl.DuplicateTask(ctx, &todopb.DuplicateTaskRequest{
+		Id:            id,
+		Count:         uint32(count),
+		SummarySuffix: suffix,
+		DueShift:      durationpb.New(dueShift),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot duplicate task: %w", err)
+	}
+	return resp.GetTasks(), nil
+}
+
+// PurgeCompleted moves the tasks that were completed at least the specified
+// duration ago to the trash. If archive is true, the response includes a
+// snapshot of the removed tasks.
+func (s *Service) PurgeCompleted(
+	ctx context.Context,
+	olderThan time.Duration,
+	archive bool,
+) (*todopb.PurgeCompletedResponse, error) {
+	resp, err := s.ctrl.PurgeCompleted(ctx, &todopb.PurgeCompletedRequest{
+		OlderThan: durationpb.New(olderThan),
+		Archive:   archive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot remove completed tasks: %w", err)
+	}
+	return resp, nil
+}
+
+// WatchTasks always returns [ErrWatchUnsupported].
+func (*Service) WatchTasks(context.Context) (grpc.ServerStreamingClient[todopb.TaskEvent], error) {
+	return nil, ErrWatchUnsupported
+}
+
+// ServiceFactory returns the [client.TaskServiceFactory] of the 'tasks'
+// commands: [Factory] for the specified configuration if standalone mode is
+// enabled, and [client.NewTaskService] otherwise.
+func ServiceFactory(enabled bool, conf *config.Config) client.TaskServiceFactory {
+	if enabled {
+		return Factory(conf)
+	}
+	return client.NewTaskService
+}
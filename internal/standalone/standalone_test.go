@@ -0,0 +1,105 @@
+package standalone
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/config"
+)
+
+func newConfig(t *testing.T) *config.Config {
+	t.Helper()
+	conf := config.New()
+	conf.LockFile = filepath.Join(t.TempDir(), "todo-daemon.lock")
+	return conf
+}
+
+func open(t *testing.T, conf *config.Config) *Service {
+	t.Helper()
+	s, err := Open(conf)
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := s.Close(); err != nil {
+			t.Errorf("Close() failed: %v", err)
+		}
+	})
+	return s
+}
+
+func TestOpenLocked(t *testing.T) {
+	conf := newConfig(t)
+	open(t, conf)
+
+	if _, err := Open(conf); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Open() while locked returned %v, want %v", err, ErrLocked)
+	}
+}
+
+func TestOpenAfterClose(t *testing.T) {
+	conf := newConfig(t)
+	s, err := Open(conf)
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	open(t, conf)
+}
+
+func TestOpenUnsupportedDatabase(t *testing.T) {
+	conf := newConfig(t)
+	conf.Database = "postgres"
+
+	if _, err := Open(conf); err == nil {
+		t.Fatal("Open() succeeded for an unsupported database")
+	}
+}
+
+func TestService(t *testing.T) {
+	s := open(t, newConfig(t))
+	ctx := t.Context()
+
+	created, err := s.CreateTask(ctx, &todopb.NewTask{Summary: "Buy milk"})
+	if err != nil {
+		t.Fatalf("CreateTask() failed: %v", err)
+	}
+	resolved, err := s.ResolveTask(ctx, created.GetShortCode())
+	if err != nil {
+		t.Fatalf("ResolveTask() failed: %v", err)
+	}
+	if resolved.GetId() != created.GetId() {
+		t.Errorf("ResolveTask() returned task %s, want %s", resolved.GetId(), created.GetId())
+	}
+
+	completed, err := s.CompleteTask(ctx, created.GetId())
+	if err != nil {
+		t.Fatalf("CompleteTask() failed: %v", err)
+	}
+	if completed.GetCompletedAt() == nil {
+		t.Error("CompleteTask() returned a task that is not completed")
+	}
+
+	if err := s.DeleteTask(ctx, created.GetId()); err != nil {
+		t.Fatalf("DeleteTask() failed: %v", err)
+	}
+	tasks, err := s.ListTasks(ctx)
+	if err != nil {
+		t.Fatalf("ListTasks() failed: %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Errorf("ListTasks() returned %d tasks, want 0", len(tasks))
+	}
+
+	_, err = s.ResolveTask(ctx, created.GetId())
+	if got := status.Code(err); got != codes.NotFound {
+		t.Errorf("ResolveTask() of deleted task returned code %s, want %s", got, codes.NotFound)
+	}
+}
@@ -0,0 +1,539 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/logging"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// EventLog is the name of the driver that appends each change to the tasks as
+// an event to a log file, e.g. "eventlog:/var/lib/todo-daemon/tasks.log" or
+// "eventlog:///var/lib/todo-daemon/tasks.log". The current tasks are a
+// projection of the events, which is rebuilt in memory when the store is
+// opened, so the log also records the history of each task.
+const EventLog = "eventlog"
+
+// compactThreshold is the minimum number of records of an event log before it
+// is compacted when it is opened. Smaller logs are replayed quickly anyway.
+const compactThreshold = 1000
+
+func init() {
+	Register(EventLog, DriverFunc(openEventLog))
+}
+
+// RecordType identifies the kind of a record in an event log.
+type RecordType string
+
+const (
+	// RecordSnapshot replaces all tasks with the tasks of the record. It is
+	// written when the tasks are replaced and when the log is compacted.
+	RecordSnapshot RecordType = "snapshot"
+	// RecordCreated adds the task of the record.
+	RecordCreated RecordType = "created"
+	// RecordUpdated replaces the task with the task of the record.
+	RecordUpdated RecordType = "updated"
+	// RecordMoved moves the task of the record as specified by its move, and
+	// then replaces the task with the task of the record.
+	RecordMoved RecordType = "moved"
+	// RecordDeleted moves the task with the ID of the record to the trash at
+	// the time of the record.
+	RecordDeleted RecordType = "deleted"
+	// RecordBatch adds or replaces all tasks of the record, which are the
+	// tasks created, updated, or deleted by a batch. A single record keeps the
+	// batch atomic, since a partial record is dropped on replay.
+	RecordBatch RecordType = "batch"
+)
+
+// Record is a line of an event log, which is a JSON document.
+type Record struct {
+	// Seq is the sequence number of the record, starting at 1. It is not reset
+	// when the log is compacted.
+	Seq uint64 `json:"seq"`
+	// Time is the time when the change happened.
+	Time time.Time `json:"time"`
+	// Type is the kind of change.
+	Type RecordType `json:"type"`
+	// ID is the ID of a deleted task.
+	ID string `json:"id,omitempty"`
+	// Task is the state of the task after the change.
+	Task *todo.SnapshotTask `json:"task,omitempty"`
+	// Move is the move of a task in the manual order.
+	Move *RecordMove `json:"move,omitempty"`
+	// Tasks are all tasks of a snapshot.
+	Tasks []todo.SnapshotTask `json:"tasks,omitempty"`
+}
+
+// RecordMove is the representation of a [todo.TaskMove] in a [Record].
+type RecordMove struct {
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// eventLogStore is a [todo.InMemoryTaskDB] holding the projection of an event
+// log, which each modification is appended to.
+type eventLogStore struct {
+	*todo.InMemoryTaskDB
+	// mu serializes the modifications, so the records are appended in the
+	// order of the modifications.
+	mu   sync.Mutex
+	path string
+	file *os.File
+	// seq is the sequence number of the last record.
+	seq uint64
+	// records is the number of records in the log.
+	records int
+	// err is the error of a failed append. The log no longer matches the
+	// projection then, so all further modifications fail.
+	err error
+}
+
+// EventLogPath returns the path of the log file of the specified DSN of the
+// [EventLog] driver.
+func EventLogPath(dsn string) (string, error) {
+	return filePath(EventLog, dsn)
+}
+
+func openEventLog(_ context.Context, dsn string) (Store, error) {
+	path, err := EventLogPath(dsn)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	s := &eventLogStore{InMemoryTaskDB: todo.NewInMemoryTaskDB(), path: path, file: file}
+	tasks, err := s.replay()
+	if err != nil {
+		return nil, errors.Join(err, file.Close())
+	}
+	if err := s.InMemoryTaskDB.Replace(context.Background(), tasks); err != nil {
+		return nil, errors.Join(err, file.Close())
+	}
+	if slices.ContainsFunc(tasks, func(t todo.Task) bool { return t.UID == "" }) {
+		// The projection assigned UIDs to the tasks of an older log, which
+		// must not change when it is replayed again.
+		all, err := s.all(context.Background())
+		if err == nil {
+			err = s.compact(all)
+		}
+		if err != nil {
+			return nil, errors.Join(err, file.Close())
+		}
+		return s, nil
+	}
+	s.compactIfGrown(tasks)
+	return s, nil
+}
+
+// replay reads all records of the log file and returns the tasks that result
+// from them. A trailing partial record, e.g. from a crash while appending it,
+// is removed from the file.
+func (s *eventLogStore) replay() (todo.Tasks, error) {
+	tasks := make(map[string]todo.Task)
+	r := bufio.NewReader(s.file)
+	var offset int64
+	for line := 1; ; line++ {
+		b, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(bytes.TrimSpace(b)) > 0 {
+				logger().Warn("removing partial record from event log", "path", s.path, "line", line)
+				if err := s.file.Truncate(offset); err != nil {
+					return nil, fmt.Errorf("cannot remove partial record from event log: %w", err)
+				}
+			}
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read event log: %w", err)
+		}
+		offset += int64(len(b))
+		var rec Record
+		if err := json.Unmarshal(b, &rec); err != nil {
+			return nil, fmt.Errorf("invalid event log: line %d: %w", line, err)
+		}
+		if err := applyRecord(tasks, &rec); err != nil {
+			return nil, fmt.Errorf("invalid event log: line %d: %w", line, err)
+		}
+		s.seq = rec.Seq
+		s.records++
+	}
+	if _, err := s.file.Seek(offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("cannot read event log: %w", err)
+	}
+	return slices.Collect(maps.Values(tasks)), nil
+}
+
+// applyRecord applies the change described by the specified record to the
+// specified tasks.
+func applyRecord(tasks map[string]todo.Task, rec *Record) error {
+	switch rec.Type {
+	case RecordSnapshot:
+		clear(tasks)
+		for i := range rec.Tasks {
+			tasks[rec.Tasks[i].ID] = rec.Tasks[i].Task()
+		}
+		return nil
+	case RecordBatch:
+		for i := range rec.Tasks {
+			tasks[rec.Tasks[i].ID] = rec.Tasks[i].Task()
+		}
+		return nil
+	case RecordDeleted:
+		if rec.ID == "" {
+			return errors.New("deleted record without ID")
+		}
+		t, ok := tasks[rec.ID]
+		if !ok {
+			return fmt.Errorf("deleted record for unknown task %s", rec.ID)
+		}
+		t.DeletedAt = rec.Time
+		tasks[rec.ID] = t
+		return nil
+	}
+	if rec.Task == nil || rec.Task.ID == "" {
+		return fmt.Errorf("%s record without task", rec.Type)
+	}
+	id := rec.Task.ID
+	switch rec.Type {
+	case RecordCreated, RecordUpdated:
+		tasks[id] = rec.Task.Task()
+	case RecordMoved:
+		if rec.Move == nil {
+			return errors.New("moved record without move")
+		}
+		move := &todo.TaskMove{Before: rec.Move.Before, After: rec.Move.After}
+		// Like the repository, the move only renumbers the tasks that are
+		// not in the trash.
+		var active todo.Tasks
+		for _, t := range tasks {
+			if t.DeletedAt.IsZero() {
+				active = append(active, t)
+			}
+		}
+		positions, err := move.Apply(active, id)
+		if err != nil {
+			return err
+		}
+		for id, position := range positions {
+			t := tasks[id]
+			t.Position = position
+			tasks[id] = t
+		}
+		tasks[id] = rec.Task.Task()
+	default:
+		return fmt.Errorf("unknown record type '%s'", rec.Type)
+	}
+	return nil
+}
+
+// appendRecord appends the specified record to the log file and syncs it to
+// the disk. The caller must hold the lock.
+func (s *eventLogStore) appendRecord(rec Record) error {
+	rec.Seq = s.seq + 1
+	rec.Time = time.Now().UTC()
+	b, err := json.Marshal(rec)
+	if err == nil {
+		_, err = s.file.Write(append(b, '\n'))
+	}
+	if err == nil {
+		err = s.file.Sync()
+	}
+	if err != nil {
+		s.err = fmt.Errorf("cannot append to event log: %w", err)
+		return s.err
+	}
+	s.seq = rec.Seq
+	s.records++
+	return nil
+}
+
+// taskRecord returns a record of the specified type for the specified task.
+func taskRecord(t RecordType, task *todo.Task) Record {
+	st := todo.NewSnapshotTask(task)
+	return Record{Type: t, Task: &st}
+}
+
+func (s *eventLogStore) Create(ctx context.Context, task *todo.TaskCreate) (*todo.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	created, err := s.InMemoryTaskDB.Create(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.appendRecord(taskRecord(RecordCreated, created)); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *eventLogStore) CreateAll(ctx context.Context, tasks []*todo.TaskCreate) (todo.Tasks, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	created, err := s.InMemoryTaskDB.CreateAll(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.appendRecord(batchRecord(created)); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *eventLogStore) ApplyBatch(ctx context.Context, ops []todo.BatchOperation) (todo.Tasks, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	results, err := s.InMemoryTaskDB.ApplyBatch(ctx, ops)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return results, nil
+	}
+	// A task modified by several operations is recorded in its final state.
+	indexes := make(map[string]int, len(results))
+	var modified todo.Tasks
+	for _, t := range results {
+		if i, ok := indexes[t.ID]; ok {
+			modified[i] = t
+			continue
+		}
+		indexes[t.ID] = len(modified)
+		modified = append(modified, t)
+	}
+	if err := s.appendRecord(batchRecord(modified)); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// batchRecord returns a batch record for the specified tasks.
+func batchRecord(tasks todo.Tasks) Record {
+	return Record{Type: RecordBatch, Tasks: todo.NewSnapshot(tasks).Tasks}
+}
+
+func (s *eventLogStore) Update(ctx context.Context, id string, update *todo.TaskUpdate) (*todo.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	updated, err := s.InMemoryTaskDB.Update(ctx, id, update)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.appendRecord(taskRecord(RecordUpdated, updated)); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *eventLogStore) Move(ctx context.Context, id string, move *todo.TaskMove) (*todo.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	moved, err := s.InMemoryTaskDB.Move(ctx, id, move)
+	if err != nil {
+		return nil, err
+	}
+	rec := taskRecord(RecordMoved, moved)
+	rec.Move = &RecordMove{Before: move.Before, After: move.After}
+	if err := s.appendRecord(rec); err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
+
+func (s *eventLogStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.InMemoryTaskDB.Delete(ctx, id); err != nil {
+		return err
+	}
+	return s.appendRecord(Record{Type: RecordDeleted, ID: id})
+}
+
+func (s *eventLogStore) Restore(ctx context.Context, id string) (*todo.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	restored, err := s.InMemoryTaskDB.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.appendRecord(taskRecord(RecordUpdated, restored)); err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+func (s *eventLogStore) Replace(ctx context.Context, tasks todo.Tasks) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.InMemoryTaskDB.Replace(ctx, tasks); err != nil {
+		return err
+	}
+	// The projection assigns positions to the tasks without one.
+	replaced, err := s.all(ctx)
+	if err != nil {
+		return err
+	}
+	return s.appendRecord(Record{Type: RecordSnapshot, Tasks: todo.NewSnapshot(replaced).Tasks})
+}
+
+func (s *eventLogStore) Merge(ctx context.Context, tasks todo.Tasks, since time.Time) (*todo.MergeResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	result, err := s.InMemoryTaskDB.Merge(ctx, tasks, since)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result.Applied {
+		e := &result.Applied[i]
+		var rec Record
+		switch e.Type {
+		case todo.EventTaskCreated:
+			rec = taskRecord(RecordCreated, &e.Task)
+		case todo.EventTaskUpdated, todo.EventTaskDeleted:
+			// The merged tasks replace the local ones with the times of the
+			// remote changes, so they are recorded with their full state.
+			rec = taskRecord(RecordUpdated, &e.Task)
+		default:
+			continue
+		}
+		if err := s.appendRecord(rec); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// all returns all tasks of the projection. The caller must hold the lock.
+func (s *eventLogStore) all(ctx context.Context) (todo.Tasks, error) {
+	return s.InMemoryTaskDB.List(ctx, &todo.ListOptions{IncludeDeleted: true, SortBy: todo.SortByPosition})
+}
+
+// compactIfGrown compacts the log if it has many more records than the
+// specified current tasks, so it is replayed quickly next time.
+func (s *eventLogStore) compactIfGrown(tasks todo.Tasks) {
+	if s.records < compactThreshold || s.records <= 2*len(tasks) {
+		return
+	}
+	if err := s.compact(tasks); err != nil {
+		logger().Warn("cannot compact event log", "path", s.path, "cause", err)
+	}
+}
+
+// compact replaces the records of the log with a single snapshot of the
+// specified tasks, discarding their history. It writes the snapshot to a new
+// log file, which then atomically replaces the log file, so a crash leaves
+// either the old or the new log behind. The caller must hold the lock.
+func (s *eventLogStore) compact(tasks todo.Tasks) error {
+	rec := Record{
+		Seq:   s.seq + 1,
+		Time:  time.Now().UTC(),
+		Type:  RecordSnapshot,
+		Tasks: todo.NewSnapshot(tasks).Tasks,
+	}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("cannot compact event log: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := writeFileSync(tmp, append(b, '\n')); err != nil {
+		return fmt.Errorf("cannot compact event log: %w", err)
+	}
+	file, err := os.OpenFile(tmp, os.O_RDWR|os.O_APPEND, 0)
+	if err != nil {
+		return fmt.Errorf("cannot compact event log: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return errors.Join(fmt.Errorf("cannot compact event log: %w", err), file.Close())
+	}
+	// Without syncing the directory, the records appended to the new log
+	// could end up in a file that a crash removes again. The new log is in
+	// place anyway, so it must be used from now on.
+	if err := syncDir(filepath.Dir(s.path)); err != nil {
+		logger().Warn("cannot sync event log directory", "path", s.path, "cause", err)
+	}
+	// revive:disable-next-line:unhandled-error
+	s.file.Close()
+	s.file = file
+	s.seq = rec.Seq
+	s.records = 1
+	return nil
+}
+
+// writeFileSync writes the specified data to a new file and syncs it to the
+// disk.
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		return errors.Join(err, f.Close())
+	}
+	if err := f.Sync(); err != nil {
+		return errors.Join(err, f.Close())
+	}
+	return f.Close()
+}
+
+// Close closes the log file without compacting it, since a server taking over
+// the tasks opens the log right afterwards. The tasks can still be read, but
+// all modifications fail. Closing the store again has no effect.
+func (s *eventLogStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	if s.err == nil {
+		s.err = errStoreClosed
+	}
+	return err
+}
+
+// logger returns the logger of the messages about the storage.
+func logger() *slog.Logger {
+	return logging.Component(logging.ComponentRepo)
+}
@@ -0,0 +1,392 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/todo"
+	"github.com/mwopitz/todo-daemon/internal/todo/todotest"
+)
+
+func openTestEventLog(t *testing.T, path string) Store {
+	t.Helper()
+	store, err := Open(t.Context(), "eventlog:"+path)
+	if err != nil {
+		t.Fatalf("cannot open event log: %v", err)
+	}
+	return store
+}
+
+func TestEventLog(t *testing.T) {
+	todotest.RunRepositoryTests(t, func(t *testing.T) todo.TaskRepository {
+		store := openTestEventLog(t, filepath.Join(t.TempDir(), "tasks.log"))
+		t.Cleanup(func() {
+			if err := store.Close(); err != nil {
+				t.Errorf("cannot close event log: %v", err)
+			}
+		})
+		return store
+	})
+}
+
+func TestEventLogReplay(t *testing.T) {
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), "tasks.log")
+	store := openTestEventLog(t, path)
+	for _, summary := range []string{"a", "b", "c", "d"} {
+		if _, err := store.Create(ctx, &todo.TaskCreate{Summary: summary}); err != nil {
+			t.Fatalf("cannot create task: %v", err)
+		}
+	}
+	done := time.Date(2025, 12, 24, 18, 0, 0, 0, time.UTC)
+	if _, err := store.Update(ctx, "1", &todo.TaskUpdate{CompletedAt: &done}); err != nil {
+		t.Fatalf("cannot update task: %v", err)
+	}
+	if _, err := store.Move(ctx, "4", &todo.TaskMove{Before: "2"}); err != nil {
+		t.Fatalf("cannot move task: %v", err)
+	}
+	for _, id := range []string{"3", "2"} {
+		if err := store.Delete(ctx, id); err != nil {
+			t.Fatalf("cannot delete task: %v", err)
+		}
+	}
+	if _, err := store.Restore(ctx, "2"); err != nil {
+		t.Fatalf("cannot restore task: %v", err)
+	}
+	opts := &todo.ListOptions{SortBy: todo.SortByPosition}
+	want, err := store.List(ctx, opts)
+	if err != nil {
+		t.Fatalf("cannot list tasks: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("cannot close event log: %v", err)
+	}
+
+	store = openTestEventLog(t, path)
+	defer store.Close()
+	got, err := store.List(ctx, opts)
+	if err != nil {
+		t.Fatalf("cannot list tasks: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("want %d tasks; got: %d", len(want), len(got))
+	}
+	for i := range want {
+		w, g := &want[i], &got[i]
+		if g.ID != w.ID || g.Version != w.Version || g.Position != w.Position ||
+			!g.CompletedAt.Equal(w.CompletedAt) || !g.UpdatedAt.Equal(w.UpdatedAt) {
+			t.Errorf("want task: %+v; got: %+v", *w, *g)
+		}
+	}
+}
+
+// TestEventLogReplayMoveWhileDeleted checks that replaying a move ignores the
+// tasks in the trash, like the move itself did.
+func TestEventLogReplayMoveWhileDeleted(t *testing.T) {
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), "tasks.log")
+	store := openTestEventLog(t, path)
+	for _, summary := range []string{"a", "b", "c"} {
+		if _, err := store.Create(ctx, &todo.TaskCreate{Summary: summary}); err != nil {
+			t.Fatalf("cannot create task: %v", err)
+		}
+	}
+	if err := store.Delete(ctx, "1"); err != nil {
+		t.Fatalf("cannot delete task: %v", err)
+	}
+	if _, err := store.Move(ctx, "3", &todo.TaskMove{Before: "2"}); err != nil {
+		t.Fatalf("cannot move task: %v", err)
+	}
+	if _, err := store.Restore(ctx, "1"); err != nil {
+		t.Fatalf("cannot restore task: %v", err)
+	}
+	want := make(map[string]int64)
+	for _, id := range []string{"1", "2", "3"} {
+		task, err := store.Get(ctx, id)
+		if err != nil {
+			t.Fatalf("cannot get task: %v", err)
+		}
+		want[id] = task.Position
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("cannot close event log: %v", err)
+	}
+
+	store = openTestEventLog(t, path)
+	defer store.Close()
+	for id, position := range want {
+		task, err := store.Get(ctx, id)
+		if err != nil {
+			t.Fatalf("cannot get task: %v", err)
+		}
+		if task.Position != position {
+			t.Errorf("want position of task %s: %d; got: %d", id, position, task.Position)
+		}
+	}
+}
+
+func TestEventLogClose(t *testing.T) {
+	ctx := t.Context()
+	store := openTestEventLog(t, filepath.Join(t.TempDir(), "tasks.log"))
+	if _, err := store.Create(ctx, &todo.TaskCreate{Summary: "a"}); err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("cannot close event log: %v", err)
+	}
+	if _, err := store.Get(ctx, "1"); err != nil {
+		t.Errorf("want tasks to be readable after closing; got: %v", err)
+	}
+	if _, err := store.Create(ctx, &todo.TaskCreate{Summary: "b"}); !errors.Is(err, errStoreClosed) {
+		t.Errorf("want %v for modifications after closing; got: %v", errStoreClosed, err)
+	}
+	if err := store.Close(); err != nil {
+		t.Errorf("want closing again to have no effect; got: %v", err)
+	}
+}
+
+func TestEventLogReplayBatch(t *testing.T) {
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), "tasks.log")
+	store := openTestEventLog(t, path)
+	batch, ok := store.(todo.BatchRepository)
+	if !ok {
+		t.Fatal("want event log to support batches")
+	}
+	if _, err := store.Create(ctx, &todo.TaskCreate{Summary: "a"}); err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+	creates := []*todo.TaskCreate{{Summary: "b", DependsOn: []string{"1"}}, {Summary: "c"}}
+	if _, err := batch.CreateAll(ctx, creates); err != nil {
+		t.Fatalf("cannot create tasks: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("cannot close event log: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := bytes.Count(data, []byte("\n")); n != 2 {
+		t.Errorf("want batch written as a single record; got %d records", n)
+	}
+
+	store = openTestEventLog(t, path)
+	defer store.Close()
+	got, err := store.List(ctx, &todo.ListOptions{SortBy: todo.SortByPosition})
+	if err != nil {
+		t.Fatalf("cannot list tasks: %v", err)
+	}
+	if len(got) != 3 || got[1].Summary != "b" || !got[1].IsBlocked() || got[2].Summary != "c" {
+		t.Errorf("want tasks a, b, and c, b blocked by a; got: %+v", got)
+	}
+}
+
+func TestEventLogReplayApplyBatch(t *testing.T) {
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), "tasks.log")
+	store := openTestEventLog(t, path)
+	for _, summary := range []string{"a", "b"} {
+		if _, err := store.Create(ctx, &todo.TaskCreate{Summary: summary}); err != nil {
+			t.Fatalf("cannot create task: %v", err)
+		}
+	}
+	summary := "renamed"
+	ops := []todo.BatchOperation{
+		{Create: &todo.TaskCreate{Summary: "c"}},
+		{ID: "3", Update: &todo.TaskUpdate{Summary: &summary}},
+		{ID: "1", Delete: true},
+	}
+	if _, err := store.(todo.BatchRepository).ApplyBatch(ctx, ops); err != nil {
+		t.Fatalf("cannot apply batch: %v", err)
+	}
+	opts := &todo.ListOptions{IncludeDeleted: true}
+	want, err := store.List(ctx, opts)
+	if err != nil {
+		t.Fatalf("cannot list tasks: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("cannot close event log: %v", err)
+	}
+
+	store = openTestEventLog(t, path)
+	defer store.Close()
+	got, err := store.List(ctx, opts)
+	if err != nil {
+		t.Fatalf("cannot list tasks: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("want %d tasks; got: %d", len(want), len(got))
+	}
+	for i := range want {
+		w, g := &want[i], &got[i]
+		if g.ID != w.ID || g.Summary != w.Summary || g.Version != w.Version || !g.DeletedAt.Equal(w.DeletedAt) {
+			t.Errorf("want task: %+v; got: %+v", *w, *g)
+		}
+	}
+}
+
+func TestEventLogPartialRecord(t *testing.T) {
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), "tasks.log")
+	store := openTestEventLog(t, path)
+	if _, err := store.Create(ctx, &todo.TaskCreate{Summary: "a"}); err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("cannot close event log: %v", err)
+	}
+	complete, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, append(slices.Clone(complete), `{"seq":2,"type":"cre`...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	store = openTestEventLog(t, path)
+	if _, err := store.Get(ctx, "1"); err != nil {
+		t.Errorf("want task of complete record: %v", err)
+	}
+	if _, err := store.Create(ctx, &todo.TaskCreate{Summary: "b"}); err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("cannot close event log: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, complete) || bytes.Count(data, []byte("\n")) != 2 {
+		t.Errorf("want partial record replaced; got:\n%s", data)
+	}
+}
+
+func TestEventLogCompact(t *testing.T) {
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), "tasks.log")
+	store := openTestEventLog(t, path)
+	if _, err := store.Create(ctx, &todo.TaskCreate{Summary: "a"}); err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+	for i := range compactThreshold {
+		starred := i%2 == 0
+		if _, err := store.Update(ctx, "1", &todo.TaskUpdate{Starred: &starred}); err != nil {
+			t.Fatalf("cannot update task: %v", err)
+		}
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("cannot close event log: %v", err)
+	}
+
+	store = openTestEventLog(t, path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := bytes.Count(data, []byte("\n")); n != 1 {
+		t.Errorf("want 1 record after compaction; got: %d", n)
+	}
+	if _, err := store.Create(ctx, &todo.TaskCreate{Summary: "b"}); err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("cannot close event log: %v", err)
+	}
+
+	store = openTestEventLog(t, path)
+	defer store.Close()
+	if _, err := store.Get(ctx, "2"); err != nil {
+		t.Errorf("want task created after compaction: %v", err)
+	}
+	task, err := store.Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("cannot get task: %v", err)
+	}
+	if want := uint64(compactThreshold + 1); task.Version != want || task.Starred {
+		t.Errorf("want version %d, not starred; got: version %d, starred %t", want, task.Version, task.Starred)
+	}
+}
+
+func TestEventLogInvalid(t *testing.T) {
+	if _, err := Open(t.Context(), "eventlog:"); err == nil {
+		t.Error("want DSN without path to fail")
+	}
+	path := filepath.Join(t.TempDir(), "tasks.log")
+	if err := os.WriteFile(path, []byte("{\"seq\":1,\"type\":\"unknown\"}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(t.Context(), "eventlog:"+path); err == nil {
+		t.Error("want invalid record to fail")
+	}
+}
+
+func TestEventLogMerge(t *testing.T) {
+	ctx := t.Context()
+	peer := todo.NewInMemoryTaskDB()
+	for _, summary := range []string{"a", "b"} {
+		if _, err := peer.Create(ctx, &todo.TaskCreate{Summary: summary}); err != nil {
+			t.Fatalf("cannot create task: %v", err)
+		}
+	}
+	if err := peer.Delete(ctx, "2"); err != nil {
+		t.Fatalf("cannot delete task: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "tasks.log")
+	store := openTestEventLog(t, path)
+	syncRepo, ok := store.(todo.SyncRepository)
+	if !ok {
+		t.Fatal("want event log to support synchronization")
+	}
+	changes, since, err := peer.Changes(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("cannot retrieve changes: %v", err)
+	}
+	if _, err := syncRepo.Merge(ctx, changes[:1], time.Time{}); err != nil {
+		t.Fatalf("cannot merge changes: %v", err)
+	}
+	if _, err := store.Create(ctx, &todo.TaskCreate{Summary: "c"}); err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+	if err := peer.Delete(ctx, "1"); err != nil {
+		t.Fatalf("cannot delete task: %v", err)
+	}
+	if changes, _, err = peer.Changes(ctx, since); err != nil {
+		t.Fatalf("cannot retrieve changes: %v", err)
+	}
+	if _, err := syncRepo.Merge(ctx, changes, since); err != nil {
+		t.Fatalf("cannot merge changes: %v", err)
+	}
+	want, err := store.List(ctx, &todo.ListOptions{IncludeDeleted: true})
+	if err != nil {
+		t.Fatalf("cannot list tasks: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("cannot close event log: %v", err)
+	}
+
+	store = openTestEventLog(t, path)
+	defer store.Close()
+	got, err := store.List(ctx, &todo.ListOptions{IncludeDeleted: true})
+	if err != nil {
+		t.Fatalf("cannot list tasks: %v", err)
+	}
+	if len(got) != 2 || len(want) != 2 {
+		t.Fatalf("want 2 tasks; got: %+v", got)
+	}
+	for i := range want {
+		w, g := &want[i], &got[i]
+		if g.ID != w.ID || g.UID != w.UID || !g.DeletedAt.Equal(w.DeletedAt) || !g.UpdatedAt.Equal(w.UpdatedAt) {
+			t.Errorf("want task: %+v; got: %+v", *w, *g)
+		}
+	}
+	if _, err := store.Get(ctx, "1"); !todo.IsTaskNotFoundError(err) {
+		t.Errorf("want merged deletion replayed; got: %v", err)
+	}
+}
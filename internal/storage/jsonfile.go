@@ -0,0 +1,472 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// JSONFile is the name of the driver that keeps the tasks in a JSON file,
+// e.g. "jsonfile:/home/alice/Sync/tasks.json", which users can edit by hand or
+// synchronize between their devices, e.g. with Syncthing. The file holds a
+// snapshot of all tasks, like a backup but uncompressed and indented, and is
+// reloaded when it is modified by another program.
+const JSONFile = "jsonfile"
+
+// jsonFileSaveDelay is the time between the first unsaved change to the tasks
+// of a JSON file and saving them, so that a burst of changes, e.g. by a
+// script, is saved at once.
+const jsonFileSaveDelay = 200 * time.Millisecond
+
+// jsonFilePollInterval is the interval for checking whether a JSON file has
+// been modified by another program.
+const jsonFilePollInterval = time.Second
+
+func init() {
+	Register(JSONFile, DriverFunc(openJSONFile))
+}
+
+// jsonFileStore is a [todo.InMemoryTaskDB] whose tasks are saved to a JSON
+// file shortly after each modification, and reloaded from the file when
+// another program modifies it.
+type jsonFileStore struct {
+	*todo.InMemoryTaskDB
+	path string
+
+	// mu serializes the modifications, saving, and reloading.
+	mu sync.Mutex
+	// hash is the hash of the content of the file as last written or read,
+	// so that saving the tasks is not mistaken for a modification by another
+	// program.
+	hash [sha256.Size]byte
+	// modTime and size are the file's modification time and size as last
+	// seen, which are checked before reading the entire file.
+	modTime time.Time
+	size    int64
+	// dirty specifies whether there are unsaved changes, which are saved once
+	// the timer fires.
+	dirty bool
+	timer *time.Timer
+	// invalid specifies whether another program has written an invalid file,
+	// e.g. while it is being edited. Saving is postponed until the file is
+	// valid again, so the edits are not overwritten.
+	invalid bool
+	// closed specifies whether the store has been closed.
+	closed bool
+	// onReload is called after the tasks have been reloaded, see
+	// [jsonFileStore.OnReload].
+	onReload func(before, after todo.Tasks)
+
+	// stop is closed when the store is closed, which stops the goroutine
+	// watching the file; done is closed once it has stopped.
+	stop chan struct{}
+	done chan struct{}
+}
+
+func openJSONFile(ctx context.Context, dsn string) (Store, error) {
+	path, err := filePath(JSONFile, dsn)
+	if err != nil {
+		return nil, err
+	}
+	s := &jsonFileStore{
+		InMemoryTaskDB: todo.NewInMemoryTaskDB(),
+		path:           path,
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		// Create the file right away, so an invalid path fails now and users
+		// see where their tasks go.
+		err = s.save(ctx)
+	case err == nil:
+		err = s.load(ctx, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	go s.watch()
+	return s, nil
+}
+
+// parseJSONFile parses the content of a JSON file. Tasks added by hand may
+// lack an ID, a creation time, and a version, which are assigned to them; the
+// returned flag specifies whether the tasks should be saved for that reason.
+func parseJSONFile(data []byte) (todo.Tasks, bool, error) {
+	var snapshot todo.Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, false, fmt.Errorf("invalid tasks file: %w", err)
+	}
+	if snapshot.Version < 0 || snapshot.Version > todo.SnapshotVersion {
+		return nil, false, fmt.Errorf("invalid tasks file: unsupported version: %d", snapshot.Version)
+	}
+	var seq uint64
+	ids := make(map[string]bool, len(snapshot.Tasks))
+	for _, t := range snapshot.Tasks {
+		if t.ID == "" {
+			continue
+		}
+		if ids[t.ID] {
+			return nil, false, fmt.Errorf("invalid tasks file: duplicate task ID '%s'", t.ID)
+		}
+		ids[t.ID] = true
+		n, _ := strconv.ParseUint(t.ID, 10, 64)
+		seq = max(seq, t.Seq, n)
+	}
+	normalized := snapshot.Version == 0
+	now := time.Now().UTC()
+	tasks := make(todo.Tasks, len(snapshot.Tasks))
+	for i := range snapshot.Tasks {
+		t := snapshot.Tasks[i].Task()
+		if t.ID == "" {
+			seq++
+			t.ID, t.Seq = strconv.FormatUint(seq, 10), seq
+		}
+		if t.CreatedAt.IsZero() {
+			t.CreatedAt = now
+		}
+		normalized = normalized || snapshot.Tasks[i].ID == "" || snapshot.Tasks[i].CreatedAt.IsZero() ||
+			snapshot.Tasks[i].UID == "" || snapshot.Tasks[i].Position == 0 || snapshot.Tasks[i].Version == 0
+		tasks[i] = t
+	}
+	return tasks, normalized, nil
+}
+
+// load replaces the tasks with the tasks of the specified file content. Tasks
+// edited by hand get a new version, so that clients notice the change. If
+// tasks had to be normalized for these reasons, they are saved soon. The
+// caller must hold the lock, unless the store is being opened.
+func (s *jsonFileStore) load(ctx context.Context, data []byte) error {
+	tasks, normalized, err := parseJSONFile(data)
+	if err != nil {
+		return err
+	}
+	opts := &todo.ListOptions{IncludeDeleted: true, SortBy: todo.SortByPosition}
+	before, err := s.InMemoryTaskDB.List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	old := make(map[string]*todo.Task, len(before))
+	for i := range before {
+		old[before[i].ID] = &before[i]
+	}
+	now := time.Now().UTC()
+	for i := range tasks {
+		prev, ok := old[tasks[i].ID]
+		if ok && tasks[i].Version <= prev.Version && !tasks[i].SameContent(prev) {
+			tasks[i].Version, tasks[i].UpdatedAt = prev.Version+1, now
+			normalized = true
+		}
+	}
+	if err := s.InMemoryTaskDB.Replace(ctx, tasks); err != nil {
+		return err
+	}
+	if s.onReload != nil {
+		after, err := s.InMemoryTaskDB.List(ctx, opts)
+		if err != nil {
+			return err
+		}
+		s.onReload(before, after)
+	}
+	s.hash = sha256.Sum256(data)
+	s.invalid = false
+	if err := s.stat(); err != nil {
+		return err
+	}
+	if normalized {
+		s.modified()
+	}
+	return nil
+}
+
+// stat records the modification time and size of the file. The caller must
+// hold the lock.
+func (s *jsonFileStore) stat() error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return fmt.Errorf("cannot read tasks file: %w", err)
+	}
+	s.modTime, s.size = info.ModTime(), info.Size()
+	return nil
+}
+
+// save writes all tasks to the file atomically. The caller must hold the
+// lock, unless the store is being opened.
+func (s *jsonFileStore) save(ctx context.Context) error {
+	tasks, err := s.InMemoryTaskDB.List(ctx, &todo.ListOptions{IncludeDeleted: true, SortBy: todo.SortByPosition})
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(todo.NewSnapshot(tasks), "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot save tasks file: %w", err)
+	}
+	data = append(data, '\n')
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("cannot save tasks file: %w", err)
+	}
+	s.hash = sha256.Sum256(data)
+	s.dirty = false
+	return s.stat()
+}
+
+// modified schedules saving the tasks, unless it is already scheduled. The
+// caller must hold the lock.
+func (s *jsonFileStore) modified() {
+	s.dirty = true
+	if s.timer == nil && !s.closed {
+		s.timer = time.AfterFunc(jsonFileSaveDelay, s.saveScheduled)
+	}
+}
+
+// saveScheduled saves the tasks when the timer fires. If saving fails, it is
+// retried after the same delay.
+func (s *jsonFileStore) saveScheduled() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timer = nil
+	if !s.dirty || s.closed {
+		return
+	}
+	if s.invalid {
+		logger().Warn("not saving tasks while the tasks file is invalid", "path", s.path)
+		return
+	}
+	if err := s.save(context.Background()); err != nil {
+		logger().Error("cannot save tasks", "path", s.path, "cause", err)
+		s.modified()
+	}
+}
+
+// watch checks periodically whether another program has modified the file
+// until the store is closed.
+func (s *jsonFileStore) watch() {
+	defer close(s.done)
+	ticker := time.NewTicker(jsonFilePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if err := s.reloadIfModified(context.Background()); err != nil {
+				logger().Warn("cannot reload tasks file", "path", s.path, "cause", err)
+			}
+		}
+	}
+}
+
+// reloadIfModified reloads the tasks if another program has modified the
+// file. The modification wins over unsaved changes, which are discarded. If
+// the file is invalid, the tasks are kept, and saving them is postponed until
+// the file is valid again.
+func (s *jsonFileStore) reloadIfModified(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, err := os.Stat(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		// The file is being replaced, or it was removed, in which case the
+		// next change recreates it.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return nil
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	s.modTime, s.size = info.ModTime(), info.Size()
+	if sha256.Sum256(data) == s.hash {
+		return nil
+	}
+	if _, _, err := parseJSONFile(data); err != nil {
+		if !s.invalid {
+			logger().Warn("ignoring invalid tasks file until it is fixed", "path", s.path, "cause", err)
+		}
+		s.invalid = true
+		return nil
+	}
+	if s.dirty {
+		logger().Warn("discarding unsaved changes, since the tasks file was modified", "path", s.path)
+		s.dirty = false
+	}
+	logger().Info("reloading modified tasks file", "path", s.path)
+	return s.load(ctx, data)
+}
+
+func (s *jsonFileStore) Create(ctx context.Context, task *todo.TaskCreate) (*todo.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, errStoreClosed
+	}
+	created, err := s.InMemoryTaskDB.Create(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	s.modified()
+	return created, nil
+}
+
+func (s *jsonFileStore) CreateAll(ctx context.Context, tasks []*todo.TaskCreate) (todo.Tasks, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, errStoreClosed
+	}
+	created, err := s.InMemoryTaskDB.CreateAll(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	s.modified()
+	return created, nil
+}
+
+func (s *jsonFileStore) ApplyBatch(ctx context.Context, ops []todo.BatchOperation) (todo.Tasks, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, errStoreClosed
+	}
+	results, err := s.InMemoryTaskDB.ApplyBatch(ctx, ops)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) > 0 {
+		s.modified()
+	}
+	return results, nil
+}
+
+func (s *jsonFileStore) Update(ctx context.Context, id string, update *todo.TaskUpdate) (*todo.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, errStoreClosed
+	}
+	updated, err := s.InMemoryTaskDB.Update(ctx, id, update)
+	if err != nil {
+		return nil, err
+	}
+	s.modified()
+	return updated, nil
+}
+
+func (s *jsonFileStore) Move(ctx context.Context, id string, move *todo.TaskMove) (*todo.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, errStoreClosed
+	}
+	moved, err := s.InMemoryTaskDB.Move(ctx, id, move)
+	if err != nil {
+		return nil, err
+	}
+	s.modified()
+	return moved, nil
+}
+
+func (s *jsonFileStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errStoreClosed
+	}
+	if err := s.InMemoryTaskDB.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.modified()
+	return nil
+}
+
+func (s *jsonFileStore) Restore(ctx context.Context, id string) (*todo.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, errStoreClosed
+	}
+	restored, err := s.InMemoryTaskDB.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.modified()
+	return restored, nil
+}
+
+func (s *jsonFileStore) Replace(ctx context.Context, tasks todo.Tasks) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errStoreClosed
+	}
+	if err := s.InMemoryTaskDB.Replace(ctx, tasks); err != nil {
+		return err
+	}
+	s.modified()
+	return nil
+}
+
+func (s *jsonFileStore) Merge(ctx context.Context, tasks todo.Tasks, since time.Time) (*todo.MergeResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, errStoreClosed
+	}
+	result, err := s.InMemoryTaskDB.Merge(ctx, tasks, since)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Applied) > 0 {
+		s.modified()
+	}
+	return result, nil
+}
+
+// OnReload registers the function that is called after the tasks have been
+// reloaded from the file, which implements [todo.ReloadingRepository].
+func (s *jsonFileStore) OnReload(fn func(before, after todo.Tasks)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onReload = fn
+}
+
+// Close stops watching the file and saves the unsaved changes, if any. The
+// tasks can still be read afterwards, but all modifications fail.
+func (s *jsonFileStore) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.stop)
+	s.mu.Unlock()
+	<-s.done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if !s.dirty {
+		return nil
+	}
+	if s.invalid {
+		return fmt.Errorf("cannot save tasks: tasks file %s is invalid", s.path)
+	}
+	return s.save(context.Background())
+}
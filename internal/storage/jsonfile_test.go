@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/todo"
+	"github.com/mwopitz/todo-daemon/internal/todo/todotest"
+)
+
+func openTestJSONFile(t *testing.T, path string) *jsonFileStore {
+	t.Helper()
+	store, err := Open(t.Context(), "jsonfile:"+path)
+	if err != nil {
+		t.Fatalf("cannot open JSON file: %v", err)
+	}
+	return store.(*jsonFileStore)
+}
+
+// writeExternal writes the specified content to the file like another program
+// would, with a modification time that differs from the store's last write.
+func writeExternal(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJSONFile(t *testing.T) {
+	todotest.RunRepositoryTests(t, func(t *testing.T) todo.TaskRepository {
+		store := openTestJSONFile(t, filepath.Join(t.TempDir(), "tasks.json"))
+		t.Cleanup(func() {
+			if err := store.Close(); err != nil {
+				t.Errorf("cannot close JSON file: %v", err)
+			}
+		})
+		return store
+	})
+}
+
+func TestJSONFileSave(t *testing.T) {
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	store := openTestJSONFile(t, path)
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("want file to be created when opening: %v", err)
+	}
+	for _, summary := range []string{"a", "b"} {
+		if _, err := store.Create(ctx, &todo.TaskCreate{Summary: summary}); err != nil {
+			t.Fatalf("cannot create task: %v", err)
+		}
+	}
+	if err := store.Delete(ctx, "1"); err != nil {
+		t.Fatalf("cannot delete task: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("cannot close JSON file: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, []byte("\n  \"tasks\": [\n")) {
+		t.Errorf("want indented JSON; got:\n%s", data)
+	}
+
+	store = openTestJSONFile(t, path)
+	defer store.Close()
+	tasks, err := store.List(ctx, &todo.ListOptions{IncludeDeleted: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 || tasks[0].DeletedAt.IsZero() || tasks[1].Summary != "b" {
+		t.Errorf("want deleted task a and task b; got: %+v", tasks)
+	}
+}
+
+func TestJSONFileClose(t *testing.T) {
+	ctx := t.Context()
+	store := openTestJSONFile(t, filepath.Join(t.TempDir(), "tasks.json"))
+	task, err := store.Create(ctx, &todo.TaskCreate{Summary: "a"})
+	if err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("cannot close JSON file: %v", err)
+	}
+	if _, err := store.Get(ctx, task.ID); err != nil {
+		t.Errorf("want tasks to be readable after closing; got: %v", err)
+	}
+	if _, err := store.Create(ctx, &todo.TaskCreate{Summary: "b"}); !errors.Is(err, errStoreClosed) {
+		t.Errorf("want %v for creating after closing; got: %v", errStoreClosed, err)
+	}
+	summary := "c"
+	if _, err := store.Update(ctx, task.ID, &todo.TaskUpdate{Summary: &summary}); !errors.Is(err, errStoreClosed) {
+		t.Errorf("want %v for updating after closing; got: %v", errStoreClosed, err)
+	}
+	if err := store.Delete(ctx, task.ID); !errors.Is(err, errStoreClosed) {
+		t.Errorf("want %v for deleting after closing; got: %v", errStoreClosed, err)
+	}
+}
+
+func TestJSONFileReload(t *testing.T) {
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	store := openTestJSONFile(t, path)
+	defer store.Close()
+	if _, err := store.Create(ctx, &todo.TaskCreate{Summary: "a"}); err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+
+	// A task added by hand gets an ID, which is saved. The modification wins
+	// over the unsaved task a.
+	writeExternal(t, path, `{"tasks": [{"id": "7", "summary": "b"}, {"summary": "c"}]}`)
+	if err := store.reloadIfModified(ctx); err != nil {
+		t.Fatalf("cannot reload JSON file: %v", err)
+	}
+	tasks, err := store.List(ctx, &todo.ListOptions{SortBy: todo.SortByPosition})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 || tasks[0].ID != "7" || tasks[1].ID != "8" || tasks[1].Summary != "c" {
+		t.Fatalf("want tasks 7 and 8 from the file; got: %+v", tasks)
+	}
+	store.mu.Lock()
+	dirty := store.dirty
+	store.mu.Unlock()
+	if !dirty {
+		t.Error("want normalized tasks to be saved")
+	}
+
+	// An invalid file is ignored, and not overwritten, until it is fixed.
+	writeExternal(t, path, `{"tasks": [`)
+	if err := store.reloadIfModified(ctx); err != nil {
+		t.Fatalf("cannot reload JSON file: %v", err)
+	}
+	if _, err := store.Get(ctx, "7"); err != nil {
+		t.Errorf("want tasks to be kept: %v", err)
+	}
+	if _, err := store.Create(ctx, &todo.TaskCreate{Summary: "d"}); err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+	if err := store.Close(); err == nil {
+		t.Error("want saving to fail while the file is invalid")
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"tasks": [` {
+		t.Errorf("want invalid file to be kept; got:\n%s", data)
+	}
+}
+
+func TestJSONFileOnReload(t *testing.T) {
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	store := openTestJSONFile(t, path)
+	defer store.Close()
+	task, err := store.Create(ctx, &todo.TaskCreate{Summary: "a"})
+	if err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("cannot close JSON file: %v", err)
+	}
+	store = openTestJSONFile(t, path)
+	defer store.Close()
+	var before, after todo.Tasks
+	store.OnReload(func(b, a todo.Tasks) {
+		before, after = b, a
+	})
+
+	// The summary is edited by hand without incrementing the version, which
+	// the reload does, so that clients notice the change.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeExternal(t, path, string(bytes.Replace(data, []byte(`"summary": "a"`), []byte(`"summary": "b"`), 1)))
+	if err := store.reloadIfModified(ctx); err != nil {
+		t.Fatalf("cannot reload JSON file: %v", err)
+	}
+	if len(before) != 1 || before[0].Summary != "a" || len(after) != 1 || after[0].Summary != "b" {
+		t.Fatalf("want task a before and task b after the reload; got: %+v, %+v", before, after)
+	}
+	if after[0].Version != task.Version+1 {
+		t.Errorf("want version %d; got: %d", task.Version+1, after[0].Version)
+	}
+	events := todo.ChangeEvents(before, after, time.Now())
+	if len(events) != 1 || events[0].Type != todo.EventTaskUpdated {
+		t.Errorf("want a single update event; got: %+v", events)
+	}
+}
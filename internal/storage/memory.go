@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// Memory is the name of the driver that keeps all tasks in memory only, so
+// they are lost when the store is closed. Its only DSN is "memory".
+const Memory = "memory"
+
+func init() {
+	Register(Memory, DriverFunc(openMemory))
+}
+
+// memoryStore is a [todo.InMemoryTaskDB] that can be closed.
+type memoryStore struct {
+	*todo.InMemoryTaskDB
+}
+
+func openMemory(_ context.Context, dsn string) (Store, error) {
+	if dsn != Memory && dsn != Memory+":" {
+		return nil, fmt.Errorf("invalid DSN '%s': want '%s'", dsn, Memory)
+	}
+	return memoryStore{todo.NewInMemoryTaskDB()}, nil
+}
+
+// Close does nothing, since the tasks are only dropped with the store.
+func (memoryStore) Close() error {
+	return nil
+}
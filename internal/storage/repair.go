@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// EventLogCheck is the result of [CheckEventLog].
+type EventLogCheck struct {
+	// Records is the number of valid records at the beginning of the log.
+	Records int
+	// Partial specifies whether the log ends with a partial record, e.g. from
+	// a crash while appending it. Opening the log removes it.
+	Partial bool
+	// Line is the line number of the first invalid record, or 0 if all
+	// records are valid.
+	Line int
+	// Err describes why the record at Line is invalid.
+	Err error
+}
+
+// Valid checks if the log can be opened, i.e. it has no invalid records.
+func (c *EventLogCheck) Valid() bool {
+	return c.Line == 0
+}
+
+// CheckEventLog replays the event log of the specified DSN, like opening it
+// does, but without modifying it, and reports up to which record the log is
+// valid. The log must not be open while it is checked.
+func CheckEventLog(dsn string) (*EventLogCheck, error) {
+	path, err := EventLogPath(dsn)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read event log: %w", err)
+	}
+	check, _ := checkEventLog(data)
+	return check, nil
+}
+
+// checkEventLog replays the specified content of an event log and returns the
+// result along with the length of the valid records at its beginning.
+func checkEventLog(data []byte) (*EventLogCheck, int) {
+	check := &EventLogCheck{}
+	tasks := make(map[string]todo.Task)
+	offset := 0
+	for line := 1; offset < len(data); line++ {
+		end := bytes.IndexByte(data[offset:], '\n')
+		if end < 0 {
+			check.Partial = len(bytes.TrimSpace(data[offset:])) > 0
+			break
+		}
+		var rec Record
+		err := json.Unmarshal(data[offset:offset+end], &rec)
+		if err == nil {
+			err = applyRecord(tasks, &rec)
+		}
+		if err != nil {
+			check.Line, check.Err = line, err
+			break
+		}
+		offset += end + 1
+		check.Records++
+	}
+	return check, offset
+}
+
+// RepairEventLog repairs the event log of the specified DSN by dropping its
+// first invalid record and all records after it, which cannot be replayed
+// without it, as well as a trailing partial record. The original log is kept
+// next to it with the suffix ".corrupt-" and the current time, whose path is
+// returned. The repaired log atomically replaces the original one, so a crash
+// while repairing leaves the original log behind. If the log is valid, it is
+// left as it is and the returned path is empty. The log must not be open
+// while it is repaired.
+func RepairEventLog(dsn string) (*EventLogCheck, string, error) {
+	path, err := EventLogPath(dsn)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot read event log: %w", err)
+	}
+	check, valid := checkEventLog(data)
+	if check.Valid() && !check.Partial {
+		return check, "", nil
+	}
+	backup := path + ".corrupt-" + time.Now().UTC().Format("20060102T150405Z")
+	if err := writeFileSync(backup, data); err != nil {
+		return nil, "", fmt.Errorf("cannot back up event log: %w", err)
+	}
+	if err := writeFileAtomic(path, data[:valid]); err != nil {
+		return nil, "", fmt.Errorf("cannot repair event log: %w", err)
+	}
+	logger().Warn("repaired event log", "path", path, "records", check.Records, "invalid_line", check.Line,
+		"backup", backup)
+	return check, backup, nil
+}
+
+// writeFileAtomic replaces the file at the specified path with a file holding
+// the specified data: it writes the data to a temporary file, syncs it to the
+// disk, and renames it, so a crash leaves either the old or the new file
+// behind.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := writeFileSync(tmp, data); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Join(err, os.Remove(tmp))
+	}
+	return syncDir(filepath.Dir(path))
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+func TestRepairEventLog(t *testing.T) {
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), "tasks.log")
+	dsn := "eventlog:" + path
+	store := openTestEventLog(t, path)
+	for _, summary := range []string{"a", "b"} {
+		if _, err := store.Create(ctx, &todo.TaskCreate{Summary: summary}); err != nil {
+			t.Fatalf("cannot create task: %v", err)
+		}
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("cannot close event log: %v", err)
+	}
+	valid, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check, backup, err := RepairEventLog(dsn); err != nil || !check.Valid() || backup != "" {
+		t.Errorf("want valid log to be left as it is; got: %+v, %q, %v", check, backup, err)
+	}
+
+	// A record that cannot be replayed makes opening the log fail, and the
+	// records after it cannot be replayed without it.
+	corrupt := slices.Concat(valid, []byte("{\"seq\":3,\"type\":\"deleted\",\"id\":\"42\"}\n"),
+		[]byte("{\"seq\":4,\"type\":\"deleted\",\"id\":\"1\"}\n"), []byte(`{"seq":5,"ty`))
+	if err := os.WriteFile(path, corrupt, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(ctx, dsn); err == nil {
+		t.Fatal("want corrupt log to fail")
+	}
+	check, err := CheckEventLog(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check.Valid() || check.Records != 2 || check.Line != 3 {
+		t.Errorf("want 2 valid records and invalid line 3; got: %+v", check)
+	}
+	if data, _ := os.ReadFile(path); !bytes.Equal(data, corrupt) {
+		t.Error("want check to leave the log as it is")
+	}
+
+	check, backup, err := RepairEventLog(dsn)
+	if err != nil {
+		t.Fatalf("cannot repair event log: %v", err)
+	}
+	if check.Records != 2 {
+		t.Errorf("want 2 records kept; got: %d", check.Records)
+	}
+	if data, _ := os.ReadFile(backup); !bytes.Equal(data, corrupt) {
+		t.Errorf("want original log at %s", backup)
+	}
+	if data, _ := os.ReadFile(path); !bytes.Equal(data, valid) {
+		t.Errorf("want valid records to be kept; got:\n%s", data)
+	}
+	store = openTestEventLog(t, path)
+	defer store.Close()
+	if tasks, err := store.List(ctx, &todo.ListOptions{}); err != nil || len(tasks) != 2 {
+		t.Errorf("want 2 tasks after repair; got: %d, %v", len(tasks), err)
+	}
+}
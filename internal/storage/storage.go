@@ -0,0 +1,123 @@
+// Package storage provides the storage backends of the To-do Daemon, which
+// keep the tasks of the to-do list.
+//
+// Each backend is implemented by a [Driver] that is registered under a name
+// with [Register], usually in an init function of the driver's package, like
+// the drivers of package database/sql. The configured database is a data
+// source name (DSN) like "memory" or "sqlite:///var/lib/todo-daemon/tasks.db",
+// whose scheme selects the driver; [Open] passes the entire DSN to the driver.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// ErrUnknownDriver is returned for a DSN whose scheme is not the name of a
+// registered driver.
+var ErrUnknownDriver = errors.New("unknown storage driver")
+
+// errStoreClosed is returned for modifications of a closed store.
+var errStoreClosed = errors.New("storage is closed")
+
+// Store is an opened storage backend.
+type Store interface {
+	todo.TaskRepository
+	// Close releases the resources held by the store, e.g. its database
+	// connection. The store must not be used afterwards.
+	Close() error
+}
+
+// Driver opens the stores of a storage backend.
+type Driver interface {
+	// Open opens the store described by the specified DSN, whose scheme is
+	// the name that the driver is registered under.
+	Open(ctx context.Context, dsn string) (Store, error)
+}
+
+// DriverFunc is a function that implements [Driver].
+type DriverFunc func(ctx context.Context, dsn string) (Store, error)
+
+// Open calls f(ctx, dsn).
+func (f DriverFunc) Open(ctx context.Context, dsn string) (Store, error) {
+	return f(ctx, dsn)
+}
+
+var (
+	driversMu sync.RWMutex
+	drivers   = make(map[string]Driver)
+)
+
+// Register makes the specified driver available under the specified name,
+// which is the scheme of the DSNs the driver opens. It panics if the driver is
+// nil or a driver is already registered under the name.
+func Register(name string, driver Driver) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	if driver == nil {
+		panic("storage: Register driver is nil")
+	}
+	if _, ok := drivers[name]; ok {
+		panic("storage: Register called twice for driver " + name)
+	}
+	drivers[name] = driver
+}
+
+// Drivers returns the sorted names of the registered drivers.
+func Drivers() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// DriverName returns the name of the registered driver selected by the
+// specified DSN, i.e. the DSN's scheme, or the entire DSN if it has no scheme.
+// It returns [ErrUnknownDriver] if no such driver is registered.
+func DriverName(dsn string) (string, error) {
+	name, _, _ := strings.Cut(dsn, ":")
+	driversMu.RLock()
+	_, ok := drivers[name]
+	driversMu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("%w: '%s' (available: %s)", ErrUnknownDriver, name, strings.Join(Drivers(), ", "))
+	}
+	return name, nil
+}
+
+// filePath returns the path of the file of the specified DSN of a driver
+// keeping the tasks in a file, e.g. "eventlog:/path" or "eventlog:///path".
+func filePath(driver, dsn string) (string, error) {
+	path := strings.TrimPrefix(strings.TrimPrefix(dsn, driver+":"), "//")
+	if path == "" {
+		return "", fmt.Errorf("invalid DSN '%s': want '%s:<path>'", dsn, driver)
+	}
+	return path, nil
+}
+
+// Open opens the store described by the specified DSN with the driver selected
+// by the DSN's scheme.
+func Open(ctx context.Context, dsn string) (Store, error) {
+	name, err := DriverName(dsn)
+	if err != nil {
+		return nil, err
+	}
+	driversMu.RLock()
+	driver := drivers[name]
+	driversMu.RUnlock()
+	store, err := driver.Open(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open %s storage: %w", name, err)
+	}
+	return store, nil
+}
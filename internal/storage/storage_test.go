@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	driver := DriverFunc(func(context.Context, string) (Store, error) {
+		return nil, errors.New("broken")
+	})
+	Register("test-broken", driver)
+	defer func() {
+		driversMu.Lock()
+		delete(drivers, "test-broken")
+		driversMu.Unlock()
+	}()
+
+	if !slices.Contains(Drivers(), "test-broken") {
+		t.Errorf("want registered driver in %v", Drivers())
+	}
+	if _, err := Open(t.Context(), "test-broken:whatever"); err == nil {
+		t.Error("want error of broken driver")
+	}
+
+	for name, driver := range map[string]Driver{"test-broken": driver, "test-nil": nil} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("want Register(%q) to panic", name)
+				}
+			}()
+			Register(name, driver)
+		}()
+	}
+}
+
+func TestOpen(t *testing.T) {
+	for _, dsn := range []string{"memory", "memory:"} {
+		store, err := Open(t.Context(), dsn)
+		if err != nil {
+			t.Errorf("cannot open %q: %v", dsn, err)
+			continue
+		}
+		if err := store.Close(); err != nil {
+			t.Errorf("cannot close %q: %v", dsn, err)
+		}
+	}
+
+	if _, err := Open(t.Context(), "memory:/tmp/tasks"); err == nil {
+		t.Error("want invalid memory DSN to fail")
+	}
+	if _, err := Open(t.Context(), "nosql://localhost"); !errors.Is(err, ErrUnknownDriver) {
+		t.Errorf("want error: %v; got: %v", ErrUnknownDriver, err)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package storage
+
+import (
+	"errors"
+	"os"
+)
+
+// syncDir syncs the specified directory to the disk, so that a file renamed
+// into it stays renamed after a crash.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	return errors.Join(d.Sync(), d.Close())
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package storage
+
+// syncDir does nothing, since directories cannot be synced on Windows, where
+// the file system journals renames itself.
+func syncDir(string) error {
+	return nil
+}
@@ -0,0 +1,51 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BatchOperation is an operation of a batch applied with
+// [BatchRepository.ApplyBatch]. It either creates a task, updates a task, or
+// deletes a task.
+type BatchOperation struct {
+	// Create is the task to create, if the operation creates a task.
+	Create *TaskCreate
+	// ID is the ID of the task to update or delete.
+	ID string
+	// Update is the update to apply to the task, if the operation updates a
+	// task.
+	Update *TaskUpdate
+	// Delete specifies whether the operation moves the task to the trash.
+	Delete bool
+}
+
+// BatchOperationError is returned by [BatchRepository.ApplyBatch] when one of
+// the operations fails, so none of them was applied.
+type BatchOperationError struct {
+	// Index is the index of the failed operation in the batch.
+	Index int
+	// Err is the error of the failed operation.
+	Err error
+}
+
+// NewBatchOperationError creates a [BatchOperationError] for the operation at
+// the specified index that failed with the specified error.
+func NewBatchOperationError(index int, err error) *BatchOperationError {
+	return &BatchOperationError{Index: index, Err: err}
+}
+
+// IsBatchOperationError checks if the provided error is a
+// [BatchOperationError].
+func IsBatchOperationError(err error) bool {
+	var e *BatchOperationError
+	return err != nil && errors.As(err, &e)
+}
+
+func (e *BatchOperationError) Error() string {
+	return fmt.Sprintf("operations[%d]: %v", e.Index, e.Err)
+}
+
+func (e *BatchOperationError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,88 @@
+package todo
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+)
+
+func TestApplyBatch(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryTaskDB()
+	bus := NewEventBus()
+	events, unsubscribe := bus.Subscribe(8)
+	defer unsubscribe()
+	ctrl := NewController(nil, nil, NewPublishingRepository(db, bus), bus)
+	for _, summary := range []string{"a", "b"} {
+		if _, err := db.Create(ctx, &TaskCreate{Summary: summary}); err != nil {
+			t.Fatalf("cannot create task: %v", err)
+		}
+	}
+
+	complete := &todopb.UpdateTaskRequest{
+		Id:     "1",
+		Update: &todopb.TaskUpdate{CompletedAt: timestamppb.New(time.Now())},
+		Fields: &fieldmaskpb.FieldMask{Paths: []string{"completed_at"}},
+	}
+	req := &todopb.ApplyBatchRequest{Operations: []*todopb.BatchOperation{
+		{Operation: &todopb.BatchOperation_Create{Create: &todopb.NewTask{Summary: "c"}}},
+		{Operation: &todopb.BatchOperation_Update{Update: complete}},
+		{Operation: &todopb.BatchOperation_Delete{Delete: &todopb.DeleteTaskRequest{Id: "2"}}},
+	}}
+	resp, err := ctrl.ApplyBatch(ctx, req)
+	if err != nil {
+		t.Fatalf("cannot apply batch: %v", err)
+	}
+	if got := resp.GetTasks(); len(got) != 3 || got[0].GetId() != "3" || got[1].GetCompletedAt() == nil {
+		t.Errorf("want created and completed tasks; got: %v", got)
+	}
+	var types []EventType
+	for range 4 {
+		types = append(types, (<-events).Type)
+	}
+	want := []EventType{EventTaskCreated, EventTaskUpdated, EventTaskCompleted, EventTaskDeleted}
+	if !slices.Equal(types, want) {
+		t.Errorf("want events %v; got: %v", want, types)
+	}
+
+	tests := []struct {
+		name string
+		op   *todopb.BatchOperation
+		want codes.Code
+	}{
+		{"Empty", &todopb.BatchOperation{}, codes.InvalidArgument},
+		{"InvalidTask", &todopb.BatchOperation{
+			Operation: &todopb.BatchOperation_Create{Create: &todopb.NewTask{}},
+		}, codes.InvalidArgument},
+		{"InvalidDependency", &todopb.BatchOperation{
+			Operation: &todopb.BatchOperation_Create{Create: &todopb.NewTask{Summary: "d", DependsOn: []string{"9"}}},
+		}, codes.InvalidArgument},
+		{"NotFound", &todopb.BatchOperation{
+			Operation: &todopb.BatchOperation_Delete{Delete: &todopb.DeleteTaskRequest{Id: "2"}},
+		}, codes.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			create := &todopb.BatchOperation{Operation: &todopb.BatchOperation_Create{Create: &todopb.NewTask{Summary: "e"}}}
+			req := &todopb.ApplyBatchRequest{Operations: []*todopb.BatchOperation{create, tt.op}}
+			if _, err := ctrl.ApplyBatch(ctx, req); status.Code(err) != tt.want {
+				t.Errorf("want %v; got: %v", tt.want, err)
+			}
+		})
+	}
+	tasks, err := db.List(ctx, &ListOptions{})
+	if err != nil {
+		t.Fatalf("cannot list tasks: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Errorf("want no tasks created by failed batches; got: %+v", tasks)
+	}
+}
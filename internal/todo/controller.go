@@ -1,28 +1,143 @@
 package todo
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"math"
+	"strings"
+	"time"
 
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/logging"
 )
 
 // Controller handles requests to the gRPC API endpoints.
 type Controller struct {
 	todopb.UnimplementedTodoServiceServer
 	server ServerStatusProvider
+	config ConfigReloader
 	tasks  TaskRepository
+	events *EventBus
+	// strictDependencies rejects completing tasks that are blocked by open
+	// tasks.
+	strictDependencies bool
+	// uniqueSummaries rejects creating tasks whose summary equals the summary
+	// of an open task, ignoring case.
+	uniqueSummaries bool
+	// idempotency remembers the tasks created by requests with idempotency
+	// keys, or is nil if the keys are ignored.
+	idempotency *IdempotencyCache
+	// location is the time zone that day boundaries are computed in, and
+	// timeZone its IANA name assigned to new tasks without a time zone.
+	location *time.Location
+	timeZone string
+	// filters holds the named filters that tasks can be listed by.
+	filters *FilterRegistry
+	// templates holds the templates that tasks can be created from.
+	templates *TemplateRegistry
+	// watchBufferSize and watchOverflow configure the subscriptions of the
+	// clients watching the tasks.
+	watchBufferSize int
+	watchOverflow   OverflowPolicy
 }
 
-// NewController creates a [Controller] with the given providers.
-func NewController(server ServerStatusProvider, tasks TaskRepository) *Controller {
-	return &Controller{
-		server: server,
-		tasks:  tasks,
+// ControllerOption configures a [Controller].
+type ControllerOption func(c *Controller)
+
+// WithStrictDependencies makes the controller reject requests to complete a
+// task that depends on tasks that are still open, see [Task.IsBlocked].
+func WithStrictDependencies(strict bool) ControllerOption {
+	return func(c *Controller) {
+		c.strictDependencies = strict
+	}
+}
+
+// WithUniqueSummaries makes the controller reject requests to create a task
+// whose summary equals the summary of an open task, ignoring case, unless the
+// request allows duplicates. This protects against adding the same task twice,
+// e.g. from several devices.
+func WithUniqueSummaries(unique bool) ControllerOption {
+	return func(c *Controller) {
+		c.uniqueSummaries = unique
+	}
+}
+
+// WithIdempotency makes the controller remember the tasks created by requests
+// with idempotency keys in the specified cache, so that a retried request
+// doesn't create the task again. Without it, the keys are ignored.
+func WithIdempotency(cache *IdempotencyCache) ControllerOption {
+	return func(c *Controller) {
+		c.idempotency = cache
+	}
+}
+
+// WithTimeZone sets the default time zone of the controller, which new tasks
+// without a time zone are assigned, and which day boundaries, e.g. of the tasks
+// completed today, are computed in. The default is the local time zone.
+func WithTimeZone(loc *time.Location) ControllerOption {
+	return func(c *Controller) {
+		c.location = loc
+		c.timeZone = timeZoneName(loc)
+	}
+}
+
+// WithFilters sets the registry of the named filters that tasks can be listed
+// by. Without it, listing tasks by a named filter fails.
+func WithFilters(r *FilterRegistry) ControllerOption {
+	return func(c *Controller) {
+		c.filters = r
+	}
+}
+
+// WithTemplates sets the registry of the templates that tasks can be created
+// from. Without it, the template endpoints are not supported.
+func WithTemplates(r *TemplateRegistry) ControllerOption {
+	return func(c *Controller) {
+		c.templates = r
+	}
+}
+
+// WithWatchBuffer sets the number of events buffered for each client watching
+// the tasks, and what happens when a client falls further behind. The default
+// is [DefaultWatchBufferSize] and [OverflowDropOldest].
+func WithWatchBuffer(size int, policy OverflowPolicy) ControllerOption {
+	return func(c *Controller) {
+		c.watchBufferSize = size
+		c.watchOverflow = policy
+	}
+}
+
+// NewController creates a [Controller] with the given providers. The events
+// published on the specified bus are streamed to watching clients. If config
+// is nil, reloading the configuration is not supported.
+func NewController(
+	server ServerStatusProvider,
+	config ConfigReloader,
+	tasks TaskRepository,
+	events *EventBus,
+	opts ...ControllerOption,
+) *Controller {
+	c := &Controller{
+		server:          server,
+		config:          config,
+		tasks:           tasks,
+		events:          events,
+		location:        time.Local,
+		watchBufferSize: DefaultWatchBufferSize,
+		watchOverflow:   OverflowDropOldest,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 // Status handles gRPC requests to retrieve the server status.
@@ -38,9 +153,27 @@ func (c *Controller) Status(ctx context.Context, _ *todopb.StatusRequest) (*todo
 	if pid < 0 || pid > math.MaxUint32 {
 		return nil, status.Errorf(codes.Internal, "invalid server PID: %d", pid)
 	}
+	count := srv.TaskCount
+	if count < 0 || count > math.MaxUint32 {
+		return nil, status.Errorf(codes.Internal, "invalid task count: %d", count)
+	}
+	subscribers := srv.Events.Subscribers
+	if subscribers < 0 || subscribers > math.MaxUint32 {
+		return nil, status.Errorf(codes.Internal, "invalid number of event subscribers: %d", subscribers)
+	}
 	return &todopb.StatusResponse{
-		Pid:        uint32(pid),
-		ApiBaseUrl: srv.APIBaseURL,
+		Pid:                     uint32(pid),
+		ApiBaseUrl:              srv.APIBaseURL,
+		Version:                 srv.Version,
+		MinClientVersion:        srv.MinClientVersion,
+		Uptime:                  durationpb.New(srv.Uptime),
+		StorageBackend:          srv.StorageBackend,
+		TaskCount:               uint32(count),
+		SocketAddress:           srv.SocketAddress,
+		HttpAddress:             srv.HTTPAddress,
+		EventSubscribers:        uint32(subscribers),
+		DroppedEvents:           srv.Events.Dropped,
+		DisconnectedSubscribers: srv.Events.Disconnected,
 	}, nil
 }
 
@@ -52,26 +185,312 @@ func (c *Controller) CreateTask(
 	if c.tasks == nil {
 		return nil, status.Errorf(codes.Internal, "no task repository provided")
 	}
-	task := newTaskCreateFromProto(req.GetTask())
-	created, err := c.tasks.Create(ctx, task)
+	key, err := idempotencyKey(ctx, req.GetIdempotencyKey())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot create task: %v", err)
+		return nil, err
+	}
+	task, err := c.newTaskCreate(req.GetTask(), "task")
+	if err != nil {
+		return nil, err
+	}
+	created, err := c.createOnce(ctx, key, task)
+	if err != nil {
+		return nil, err
 	}
 	return &todopb.CreateTaskResponse{Task: created.toProto()}, nil
 }
 
+// createOnce creates the specified validated task if its summary is unique,
+// see [WithUniqueSummaries]. If the request has an idempotency key and the
+// controller remembers the keys, see [WithIdempotency], a request with the
+// same key creates the task only once.
+func (c *Controller) createOnce(ctx context.Context, key string, task *TaskCreate) (*Task, error) {
+	create := func() (*Task, error) {
+		if err := c.checkUniqueSummaries(ctx, []*TaskCreate{task}, func(int) string { return "task" }); err != nil {
+			return nil, err
+		}
+		return c.create(ctx, task)
+	}
+	if key == "" || c.idempotency == nil {
+		return create()
+	}
+	created, err := c.idempotency.Do(ctx, key, task, create)
+	if errors.Is(err, ErrIdempotencyKeyReused) {
+		return nil, status.Errorf(codes.InvalidArgument, "idempotency_key: %v", err)
+	}
+	return created, err
+}
+
+// create creates the specified validated task and sends its entity tag.
+func (c *Controller) create(ctx context.Context, task *TaskCreate) (*Task, error) {
+	created, err := c.tasks.Create(ctx, task)
+	if err != nil {
+		if IsTaskNotFoundError(err) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid dependency: %v", err)
+		}
+		return nil, repositoryError(err, "cannot create task")
+	}
+	if err := setETag(ctx, created); err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "cannot send entity tag", "cause", err)
+	}
+	return created, nil
+}
+
+// BatchCreateTasks handles gRPC requests to create several new tasks in the
+// to-do list. All tasks are validated first, but then created one after
+// another, so if one of them cannot be created, the ones before it remain in
+// the to-do list.
+func (c *Controller) BatchCreateTasks(
+	ctx context.Context,
+	req *todopb.BatchCreateTasksRequest,
+) (*todopb.BatchCreateTasksResponse, error) {
+	if c.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	creates := make([]*TaskCreate, len(req.GetTasks()))
+	for i, proto := range req.GetTasks() {
+		create, err := c.newTaskCreate(proto, fmt.Sprintf("tasks[%d]", i))
+		if err != nil {
+			return nil, err
+		}
+		creates[i] = create
+	}
+	path := func(i int) string { return fmt.Sprintf("tasks[%d]", i) }
+	if err := c.checkUniqueSummaries(ctx, creates, path); err != nil {
+		return nil, err
+	}
+	created := make(Tasks, 0, len(creates))
+	for i, create := range creates {
+		task, err := c.tasks.Create(ctx, create)
+		if err != nil {
+			if IsTaskNotFoundError(err) {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid dependency of task %d of %d: %v",
+					i+1, len(req.GetTasks()), err)
+			}
+			return nil, repositoryError(err, "cannot create task %d of %d", i+1, len(req.GetTasks()))
+		}
+		created = append(created, *task)
+	}
+	return &todopb.BatchCreateTasksResponse{Tasks: created.toProtos()}, nil
+}
+
+// ApplyBatch handles gRPC requests to apply several operations creating,
+// updating, or deleting tasks at once. All operations are validated first, and
+// then the repository applies either all of them or none.
+func (c *Controller) ApplyBatch(
+	ctx context.Context,
+	req *todopb.ApplyBatchRequest,
+) (*todopb.ApplyBatchResponse, error) {
+	if c.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	batch, ok := c.tasks.(BatchRepository)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, ErrBatchUnsupported.Error())
+	}
+	ops := make([]BatchOperation, len(req.GetOperations()))
+	for i, proto := range req.GetOperations() {
+		path := fmt.Sprintf("operations[%d]", i)
+		switch op := proto.GetOperation().(type) {
+		case *todopb.BatchOperation_Create:
+			create, err := c.newTaskCreate(op.Create, path+".create")
+			if err != nil {
+				return nil, err
+			}
+			ops[i] = BatchOperation{Create: create}
+		case *todopb.BatchOperation_Update:
+			update := newTaskUpdateFromProto(op.Update.GetUpdate(), op.Update.GetFields())
+			update.ExpectedVersion = op.Update.GetExpectedVersion()
+			if err := update.Validate(); err != nil {
+				return nil, invalidArgument(err, path+".update")
+			}
+			ops[i] = BatchOperation{ID: op.Update.GetId(), Update: update}
+		case *todopb.BatchOperation_Delete:
+			ops[i] = BatchOperation{ID: op.Delete.GetId(), Delete: true}
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "%s: must create, update, or delete a task", path)
+		}
+	}
+	creates := make([]*TaskCreate, len(ops))
+	for i, op := range ops {
+		creates[i] = op.Create
+	}
+	path := func(i int) string { return fmt.Sprintf("operations[%d].create", i) }
+	if err := c.checkUniqueSummaries(ctx, creates, path); err != nil {
+		return nil, err
+	}
+	before, err := c.completing(ctx, ops)
+	if err != nil {
+		return nil, err
+	}
+	results, err := batch.ApplyBatch(ctx, ops)
+	if err != nil {
+		return nil, batchError(err, ops)
+	}
+	for i, t := range before {
+		if t.CompletedAt.IsZero() && results[i].Recurrence != "" {
+			c.addNextOccurrence(ctx, &results[i])
+		}
+	}
+	return &todopb.ApplyBatchResponse{Tasks: results.toProtos()}, nil
+}
+
+// completing returns the current state of the existing tasks that the
+// specified operations complete, by the index of the operation. Like
+// [Controller.update], it rejects completing blocked tasks if dependencies are
+// strict.
+func (c *Controller) completing(ctx context.Context, ops []BatchOperation) (map[int]*Task, error) {
+	before := make(map[int]*Task)
+	for i, op := range ops {
+		if op.Update == nil || op.Update.CompletedAt == nil || op.Update.CompletedAt.IsZero() {
+			continue
+		}
+		task, err := c.tasks.Get(ctx, op.ID)
+		if IsTaskNotFoundError(err) {
+			// The task may be created by an earlier operation. Otherwise,
+			// the batch fails anyway.
+			continue
+		}
+		if err != nil {
+			return nil, repositoryError(err, "cannot retrieve task '%s'", op.ID)
+		}
+		if c.strictDependencies && task.IsBlocked() {
+			return nil, status.Errorf(codes.FailedPrecondition, "operations[%d]: task '%s' is blocked by open tasks: '%s'",
+				i, op.ID, strings.Join(task.BlockedBy, "', '"))
+		}
+		before[i] = task
+	}
+	return before, nil
+}
+
+// batchError converts an error returned by [BatchRepository.ApplyBatch] for
+// the specified operations to a gRPC status error.
+func batchError(err error, ops []BatchOperation) error {
+	var e *BatchOperationError
+	switch {
+	case errors.Is(err, ErrBatchUnsupported):
+		return status.Error(codes.Unimplemented, err.Error())
+	case !errors.As(err, &e):
+		return repositoryError(err, "cannot apply batch")
+	case IsTaskNotFoundError(err) && ops[e.Index].Create != nil:
+		return status.Errorf(codes.InvalidArgument, "operations[%d]: invalid dependency: %v", e.Index, e.Err)
+	case IsTaskNotFoundError(err):
+		return status.Error(codes.NotFound, err.Error())
+	case IsTaskConflictError(err):
+		return status.Error(codes.Aborted, err.Error())
+	case IsDependencyCycleError(err):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return repositoryError(e.Err, "cannot apply operations[%d]", e.Index)
+}
+
 // ListTasks handles gRPC requests to retrieve tasks from the to-do list.
-func (c *Controller) ListTasks(ctx context.Context, _ *todopb.ListTasksRequest) (*todopb.ListTasksResponse, error) {
+func (c *Controller) ListTasks(ctx context.Context, req *todopb.ListTasksRequest) (*todopb.ListTasksResponse, error) {
 	if c.tasks == nil {
 		return nil, status.Errorf(codes.Internal, "no task repository provided")
 	}
-	tasks, err := c.tasks.All(ctx)
+	opts := newListOptionsFromProto(req)
+	if name := req.GetFilter(); name != "" {
+		f, err := c.filter(name)
+		if err != nil {
+			return nil, err
+		}
+		f.Apply(opts, time.Now().In(c.location))
+	}
+	tasks, err := c.list(ctx, opts)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot retrieve tasks: %v", err)
+		return nil, err
 	}
 	return &todopb.ListTasksResponse{Tasks: tasks.toProtos()}, nil
 }
 
+// list retrieves the tasks selected by the specified options and sends the
+// entity tag of the repository's revision.
+func (c *Controller) list(ctx context.Context, opts *ListOptions) (Tasks, error) {
+	// The revision is retrieved first, so that it is never newer than the
+	// tasks. The list of overdue tasks changes over time without any
+	// modification, so it has no entity tag.
+	rev, err := c.tasks.Revision(ctx)
+	if err != nil {
+		return nil, repositoryError(err, "cannot retrieve tasks")
+	}
+	tasks, err := c.tasks.List(ctx, opts)
+	if err != nil {
+		return nil, repositoryError(err, "cannot retrieve tasks")
+	}
+	if !opts.Overdue {
+		if err := setRevision(ctx, rev); err != nil {
+			logging.FromContext(ctx).WarnContext(ctx, "cannot send entity tag", "cause", err)
+		}
+	}
+	return tasks, nil
+}
+
+// GetStats handles gRPC requests to aggregate statistics about the tasks in
+// the to-do list.
+func (c *Controller) GetStats(ctx context.Context, _ *todopb.GetStatsRequest) (*todopb.GetStatsResponse, error) {
+	if c.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	stats, err := c.tasks.Stats(ctx, time.Now().In(c.location))
+	if err != nil {
+		return nil, repositoryError(err, "cannot aggregate task statistics")
+	}
+	return stats.toProto(), nil
+}
+
+// GetTask handles gRPC requests to retrieve a single task from the to-do list.
+func (c *Controller) GetTask(ctx context.Context, req *todopb.GetTaskRequest) (*todopb.GetTaskResponse, error) {
+	if c.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	task, err := c.get(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &todopb.GetTaskResponse{Task: task.toProto()}, nil
+}
+
+// get retrieves the task with the specified ID and sends its entity tag.
+func (c *Controller) get(ctx context.Context, id string) (*Task, error) {
+	task, err := c.tasks.Get(ctx, id)
+	if err != nil {
+		if IsTaskNotFoundError(err) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, repositoryError(err, "cannot retrieve task '%s'", id)
+	}
+	if err := setETag(ctx, task); err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "cannot send entity tag", "cause", err)
+	}
+	return task, nil
+}
+
+// ResolveTask handles gRPC requests to resolve a reference to a task, e.g. a
+// short code entered on the command line.
+func (c *Controller) ResolveTask(
+	ctx context.Context,
+	req *todopb.ResolveTaskRequest,
+) (*todopb.ResolveTaskResponse, error) {
+	if c.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	tasks, err := c.tasks.List(ctx, &ListOptions{})
+	if err != nil {
+		return nil, repositoryError(err, "cannot retrieve tasks")
+	}
+	task, err := ResolveTask(tasks, req.GetRef())
+	switch {
+	case IsTaskNotFoundError(err):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case IsAmbiguousTaskReferenceError(err):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &todopb.ResolveTaskResponse{Task: task.toProto()}, nil
+}
+
 // UpdateTask handles gRPC requests to update a task in the to-do list.
 func (c *Controller) UpdateTask(
 	ctx context.Context,
@@ -80,16 +499,263 @@ func (c *Controller) UpdateTask(
 	if c.tasks == nil {
 		return nil, status.Errorf(codes.Internal, "no task repository provided")
 	}
-	id := req.GetId()
 	update := newTaskUpdateFromProto(req.GetUpdate(), req.GetFields())
+	update.ExpectedVersion = req.GetExpectedVersion()
+	if err := update.Validate(); err != nil {
+		return nil, invalidArgument(err, "update")
+	}
+	task, err := c.update(ctx, req.GetId(), update)
+	if err != nil {
+		return nil, err
+	}
+	return &todopb.UpdateTaskResponse{Task: task.toProto()}, nil
+}
+
+// update applies the specified validated update to the task with the
+// specified ID and sends its entity tag. Without expected version, the
+// version of the If-Match header forwarded by the gateway is expected, if any.
+func (c *Controller) update(ctx context.Context, id string, update *TaskUpdate) (*Task, error) {
+	if update.ExpectedVersion == 0 {
+		version, err := ifMatchVersion(ctx)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		update.ExpectedVersion = version
+	}
+	// Completing a task depends on its previous state: blocked tasks may be
+	// rejected, and only open recurring tasks get a next occurrence.
+	var before *Task
+	completing := update.CompletedAt != nil && !update.CompletedAt.IsZero()
+	if completing {
+		var err error
+		if before, err = c.tasks.Get(ctx, id); err != nil {
+			if IsTaskNotFoundError(err) {
+				return nil, status.Error(codes.NotFound, err.Error())
+			}
+			return nil, repositoryError(err, "cannot retrieve task '%s'", id)
+		}
+		if c.strictDependencies && before.IsBlocked() {
+			return nil, status.Errorf(codes.FailedPrecondition, "task '%s' is blocked by open tasks: '%s'",
+				id, strings.Join(before.BlockedBy, "', '"))
+		}
+	}
 	task, err := c.tasks.Update(ctx, id, update)
 	if err != nil {
 		if IsTaskNotFoundError(err) {
 			return nil, status.Error(codes.NotFound, err.Error())
 		}
-		return nil, status.Errorf(codes.Internal, "cannot update task '%s': %v", id, err)
+		if IsTaskConflictError(err) {
+			return nil, status.Error(codes.Aborted, err.Error())
+		}
+		if IsDependencyCycleError(err) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, repositoryError(err, "cannot update task '%s'", id)
 	}
-	return &todopb.UpdateTaskResponse{Task: task.toProto()}, nil
+	if completing && before.CompletedAt.IsZero() && task.Recurrence != "" {
+		c.addNextOccurrence(ctx, task)
+	}
+	if err := setETag(ctx, task); err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "cannot send entity tag", "cause", err)
+	}
+	return task, nil
+}
+
+// addNextOccurrence adds the next occurrence of the specified recurring task,
+// which has just been completed, as a new task that is due at the first
+// occurrence after both its due time and the current time. Since the task is
+// completed anyway, failures are only logged.
+func (c *Controller) addNextOccurrence(ctx context.Context, task *Task) {
+	logger := logging.FromContext(ctx)
+	r, err := ParseRecurrence(task.Recurrence)
+	if err != nil {
+		logger.WarnContext(ctx, "cannot add next occurrence of task", "id", task.ID, "cause", err)
+		return
+	}
+	now := time.Now()
+	dueAt := task.DueAt
+	if dueAt.IsZero() {
+		dueAt = task.CompletedAt
+	}
+	dueAt = r.Next(dueAt)
+	for !dueAt.After(now) {
+		dueAt = r.Next(dueAt)
+	}
+	next, err := c.tasks.Create(ctx, &TaskCreate{
+		Summary:     task.Summary,
+		Description: task.Description,
+		DueAt:       dueAt,
+		Tags:        task.Tags,
+		Project:     task.Project,
+		Recurrence:  task.Recurrence,
+		TimeZone:    task.TimeZone,
+		Starred:     task.Starred,
+		Assignee:    task.Assignee,
+		Links:       task.Links,
+	})
+	if err != nil {
+		logger.WarnContext(ctx, "cannot add next occurrence of task", "id", task.ID, "cause", err)
+		return
+	}
+	logger.InfoContext(ctx, "added next occurrence of recurring task", "id", task.ID, "next", next.ID, "due_at", dueAt)
+}
+
+// newTaskCreate converts the specified new task at the specified path of the
+// request into a [TaskCreate] after validating it. Tasks without a time zone
+// are assigned the controller's default time zone.
+func (c *Controller) newTaskCreate(proto *todopb.NewTask, path string) (*TaskCreate, error) {
+	task := newTaskCreateFromProto(proto)
+	if err := task.Validate(); err != nil {
+		return nil, invalidArgument(err, path)
+	}
+	if task.TimeZone == "" {
+		task.TimeZone = c.timeZone
+	}
+	return task, nil
+}
+
+// MoveTask handles gRPC requests to move a task in the manual order of the
+// to-do list.
+func (c *Controller) MoveTask(ctx context.Context, req *todopb.MoveTaskRequest) (*todopb.MoveTaskResponse, error) {
+	if c.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	id := req.GetId()
+	move := newTaskMoveFromProto(req)
+	if err := move.Validate(id); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	task, err := c.tasks.Move(ctx, id, move)
+	if err != nil {
+		if IsTaskNotFoundError(err) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, repositoryError(err, "cannot move task '%s'", id)
+	}
+	if err := setETag(ctx, task); err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "cannot send entity tag", "cause", err)
+	}
+	return &todopb.MoveTaskResponse{Task: task.toProto()}, nil
+}
+
+func (c *Controller) SearchTasks(
+	ctx context.Context,
+	req *todopb.SearchTasksRequest,
+) (*todopb.SearchTasksResponse, error) {
+	if c.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	results, err := c.tasks.Search(ctx, req.GetQ())
+	if err != nil {
+		return nil, repositoryError(err, "cannot search tasks")
+	}
+	if limit := int(req.GetLimit()); limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	protos := make([]*todopb.SearchResult, len(results))
+	for i := range results {
+		protos[i] = results[i].toProto()
+	}
+	return &todopb.SearchTasksResponse{Results: protos}, nil
+}
+
+// WatchTasks handles gRPC requests to stream the changes to the tasks in the
+// to-do list. The stream ends when the client cancels the request, or with
+// RESOURCE_EXHAUSTED if the client falls behind and the overflow policy is
+// [OverflowDisconnect].
+func (c *Controller) WatchTasks(
+	_ *todopb.WatchTasksRequest,
+	stream grpc.ServerStreamingServer[todopb.TaskEvent],
+) error {
+	if c.events == nil {
+		return status.Errorf(codes.Internal, "no event bus provided")
+	}
+	events, unsubscribe := c.events.Subscribe(c.watchBufferSize, WithOverflowPolicy(c.watchOverflow))
+	defer unsubscribe()
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case e, ok := <-events:
+			if !ok {
+				return status.Errorf(codes.ResourceExhausted, "too many events: client fell behind")
+			}
+			if err := stream.Send(e.toProto()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// CreateBackup handles gRPC requests to create a snapshot of the to-do list.
+func (c *Controller) CreateBackup(
+	ctx context.Context,
+	_ *todopb.CreateBackupRequest,
+) (*todopb.CreateBackupResponse, error) {
+	if c.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	// The snapshot includes the trash, so restoring it doesn't lose anything.
+	tasks, err := c.tasks.List(ctx, &ListOptions{IncludeDeleted: true})
+	if err != nil {
+		return nil, repositoryError(err, "cannot retrieve tasks")
+	}
+	count := len(tasks)
+	if count > math.MaxUint32 {
+		return nil, status.Errorf(codes.Internal, "too many tasks: %d", count)
+	}
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, NewSnapshot(tasks)); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &todopb.CreateBackupResponse{
+		Archive:   buf.Bytes(),
+		TaskCount: uint32(count),
+	}, nil
+}
+
+// RestoreBackup handles gRPC requests to replace the to-do list with the
+// content of a snapshot.
+func (c *Controller) RestoreBackup(
+	ctx context.Context,
+	req *todopb.RestoreBackupRequest,
+) (*todopb.RestoreBackupResponse, error) {
+	if c.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	snapshot, err := ReadSnapshot(bytes.NewReader(req.GetArchive()))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	tasks := snapshot.TaskList()
+	count := len(tasks)
+	if count > math.MaxUint32 {
+		return nil, status.Errorf(codes.InvalidArgument, "too many tasks: %d", count)
+	}
+	if err := c.tasks.Replace(ctx, tasks); err != nil {
+		return nil, repositoryError(err, "cannot restore tasks")
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "restored backup", "created_at", snapshot.CreatedAt, "tasks", count)
+	return &todopb.RestoreBackupResponse{TaskCount: uint32(count)}, nil
+}
+
+// ReloadConfig handles gRPC requests to reload the server's configuration.
+func (c *Controller) ReloadConfig(
+	ctx context.Context,
+	_ *todopb.ReloadConfigRequest,
+) (*todopb.ReloadConfigResponse, error) {
+	if c.config == nil {
+		return nil, status.Errorf(codes.Unimplemented, "reloading the configuration is not supported")
+	}
+	reload, err := c.config.ReloadConfig(ctx)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return &todopb.ReloadConfigResponse{
+		Applied:         reload.Applied,
+		RequiresRestart: reload.RequiresRestart,
+	}, nil
 }
 
 // DeleteTask handles gRPC requests to delete a task from the to-do list.
@@ -100,12 +766,339 @@ func (c *Controller) DeleteTask(
 	if c.tasks == nil {
 		return nil, status.Errorf(codes.Internal, "no task repository provided")
 	}
-	id := req.GetId()
+	if err := c.delete(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	return &todopb.DeleteTaskResponse{}, nil
+}
+
+// delete deletes the task with the specified ID.
+func (c *Controller) delete(ctx context.Context, id string) error {
 	if err := c.tasks.Delete(ctx, id); err != nil {
 		if IsTaskNotFoundError(err) {
+			return status.Error(codes.NotFound, err.Error())
+		}
+		return repositoryError(err, "cannot delete task '%s'", id)
+	}
+	return nil
+}
+
+// RestoreTask handles gRPC requests to move a task from the trash back to the
+// to-do list.
+func (c *Controller) RestoreTask(
+	ctx context.Context,
+	req *todopb.RestoreTaskRequest,
+) (*todopb.RestoreTaskResponse, error) {
+	if c.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	task, err := c.tasks.Restore(ctx, req.GetId())
+	if err != nil {
+		if IsTaskNotFoundError(err) {
+			return nil, status.Errorf(codes.NotFound, "no task '%s' in the trash", req.GetId())
+		}
+		return nil, repositoryError(err, "cannot restore task '%s'", req.GetId())
+	}
+	return &todopb.RestoreTaskResponse{Task: task.toProto()}, nil
+}
+
+// DuplicateTask handles gRPC requests to create copies of a task.
+func (c *Controller) DuplicateTask(
+	ctx context.Context,
+	req *todopb.DuplicateTaskRequest,
+) (*todopb.DuplicateTaskResponse, error) {
+	if c.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	opts := DuplicateOptions{
+		Count:         int(max(req.GetCount(), 1)),
+		SummarySuffix: req.GetSummarySuffix(),
+		DueShift:      req.GetDueShift().AsDuration(),
+	}
+	copies, err := DuplicateTask(ctx, c.tasks, req.GetId(), opts)
+	if err != nil {
+		switch {
+		case IsValidationError(err):
+			return nil, invalidArgument(err, "")
+		case IsTaskNotFoundError(err):
 			return nil, status.Error(codes.NotFound, err.Error())
 		}
-		return nil, status.Errorf(codes.Internal, "cannot delete task '%s': %v", id, err)
+		return nil, repositoryError(err, "cannot duplicate task '%s'", req.GetId())
 	}
-	return &todopb.DeleteTaskResponse{}, nil
+	logging.FromContext(ctx).InfoContext(ctx, "duplicated task", "id", req.GetId(), "copies", len(copies))
+	return &todopb.DuplicateTaskResponse{Tasks: copies.toProtos()}, nil
+}
+
+// PurgeCompleted handles gRPC requests to move all completed tasks to the
+// trash at once.
+func (c *Controller) PurgeCompleted(
+	ctx context.Context,
+	req *todopb.PurgeCompletedRequest,
+) (*todopb.PurgeCompletedResponse, error) {
+	if c.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	olderThan := req.GetOlderThan().AsDuration()
+	if olderThan < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "older_than: must not be negative")
+	}
+	purged, err := PurgeCompleted(ctx, c.tasks, time.Now().Add(-olderThan))
+	if err != nil {
+		if IsTaskNotFoundError(err) || IsBatchOperationError(err) {
+			return nil, status.Errorf(codes.Aborted, "cannot remove completed tasks: %v", err)
+		}
+		return nil, repositoryError(err, "cannot remove completed tasks")
+	}
+	resp := &todopb.PurgeCompletedResponse{Tasks: purged.toProtos()}
+	if req.GetArchive() {
+		var buf bytes.Buffer
+		if err := WriteSnapshot(&buf, NewSnapshot(purged)); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		resp.Archive = buf.Bytes()
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "removed completed tasks", "tasks", len(purged))
+	return resp, nil
+}
+
+// ListFilters handles gRPC requests to retrieve the named filters.
+func (c *Controller) ListFilters(context.Context, *todopb.ListFiltersRequest) (*todopb.ListFiltersResponse, error) {
+	if c.filters == nil {
+		return &todopb.ListFiltersResponse{}, nil
+	}
+	filters := c.filters.List()
+	protos := make([]*todopb.Filter, len(filters))
+	for i := range filters {
+		protos[i] = filters[i].toProto()
+	}
+	return &todopb.ListFiltersResponse{Filters: protos}, nil
+}
+
+// CreateFilter handles gRPC requests to save a named filter.
+func (c *Controller) CreateFilter(
+	ctx context.Context,
+	req *todopb.CreateFilterRequest,
+) (*todopb.CreateFilterResponse, error) {
+	if c.filters == nil {
+		return nil, status.Errorf(codes.Unimplemented, "saving filters is not supported")
+	}
+	f := NewFilterFromProto(req.GetFilter())
+	if err := c.filters.Add(f); err != nil {
+		var verr *ValidationError
+		switch {
+		case errors.As(err, &verr):
+			return nil, invalidArgument(err, "filter")
+		case IsFilterExistsError(err):
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "cannot save filter: %v", err)
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "saved filter", "name", f.Name)
+	return &todopb.CreateFilterResponse{Filter: f.toProto()}, nil
+}
+
+// DeleteFilter handles gRPC requests to delete a saved filter.
+func (c *Controller) DeleteFilter(
+	ctx context.Context,
+	req *todopb.DeleteFilterRequest,
+) (*todopb.DeleteFilterResponse, error) {
+	if c.filters == nil {
+		return nil, status.Error(codes.NotFound, NewFilterNotFoundError(req.GetName()).Error())
+	}
+	if err := c.filters.Remove(req.GetName()); err != nil {
+		switch {
+		case IsFilterNotFoundError(err):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, ErrFilterConfigured):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "cannot delete filter: %v", err)
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "deleted filter", "name", req.GetName())
+	return &todopb.DeleteFilterResponse{}, nil
+}
+
+// filter returns the named filter with the specified name.
+func (c *Controller) filter(name string) (*Filter, error) {
+	if c.filters == nil {
+		return nil, status.Error(codes.NotFound, NewFilterNotFoundError(name).Error())
+	}
+	f, err := c.filters.Get(name)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return f, nil
+}
+
+// ListTemplates handles gRPC requests to retrieve the templates.
+func (c *Controller) ListTemplates(
+	context.Context,
+	*todopb.ListTemplatesRequest,
+) (*todopb.ListTemplatesResponse, error) {
+	if c.templates == nil {
+		return &todopb.ListTemplatesResponse{}, nil
+	}
+	templates := c.templates.List()
+	protos := make([]*todopb.Template, len(templates))
+	for i := range templates {
+		protos[i] = templates[i].toProto()
+	}
+	return &todopb.ListTemplatesResponse{Templates: protos}, nil
+}
+
+// CreateTemplate handles gRPC requests to save a template.
+func (c *Controller) CreateTemplate(
+	ctx context.Context,
+	req *todopb.CreateTemplateRequest,
+) (*todopb.CreateTemplateResponse, error) {
+	if c.templates == nil {
+		return nil, status.Errorf(codes.Unimplemented, "saving templates is not supported")
+	}
+	t := NewTemplateFromProto(req.GetTemplate())
+	if err := c.templates.Add(t, time.Now().In(c.location)); err != nil {
+		var verr *ValidationError
+		switch {
+		case errors.As(err, &verr):
+			return nil, invalidArgument(err, "template")
+		case IsTemplateExistsError(err):
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "cannot save template: %v", err)
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "saved template", "name", t.Name, "tasks", len(t.Tasks))
+	return &todopb.CreateTemplateResponse{Template: t.toProto()}, nil
+}
+
+// DeleteTemplate handles gRPC requests to delete a template.
+func (c *Controller) DeleteTemplate(
+	ctx context.Context,
+	req *todopb.DeleteTemplateRequest,
+) (*todopb.DeleteTemplateResponse, error) {
+	if c.templates == nil {
+		return nil, status.Error(codes.NotFound, NewTemplateNotFoundError(req.GetName()).Error())
+	}
+	if err := c.templates.Remove(req.GetName()); err != nil {
+		if IsTemplateNotFoundError(err) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "cannot delete template: %v", err)
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "deleted template", "name", req.GetName())
+	return &todopb.DeleteTemplateResponse{}, nil
+}
+
+// ApplyTemplate handles gRPC requests to create all tasks of a template at
+// once.
+func (c *Controller) ApplyTemplate(
+	ctx context.Context,
+	req *todopb.ApplyTemplateRequest,
+) (*todopb.ApplyTemplateResponse, error) {
+	if c.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	if c.templates == nil {
+		return nil, status.Error(codes.NotFound, NewTemplateNotFoundError(req.GetName()).Error())
+	}
+	created, err := c.templates.Apply(ctx, c.tasks, req.GetName(), time.Now().In(c.location))
+	if err != nil {
+		switch {
+		case IsTemplateNotFoundError(err):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, ErrBatchUnsupported):
+			return nil, status.Error(codes.Unimplemented, err.Error())
+		}
+		return nil, repositoryError(err, "cannot apply template '%s'", req.GetName())
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "applied template", "name", req.GetName(), "tasks", len(created))
+	return &todopb.ApplyTemplateResponse{Tasks: created.toProtos()}, nil
+}
+
+// PullChanges handles gRPC requests to retrieve the changes to the to-do list
+// for synchronizing it with the to-do list of another server.
+func (c *Controller) PullChanges(
+	ctx context.Context,
+	req *todopb.PullChangesRequest,
+) (*todopb.PullChangesResponse, error) {
+	tasks, err := c.syncRepository()
+	if err != nil {
+		return nil, err
+	}
+	changes, now, err := tasks.Changes(ctx, optionalTime(req.GetSince()))
+	if err != nil {
+		return nil, syncError(err, "cannot retrieve changes")
+	}
+	protos := make([]*todopb.TaskChange, len(changes))
+	for i := range changes {
+		protos[i] = newTaskChangeProto(&changes[i])
+	}
+	return &todopb.PullChangesResponse{Changes: protos, Time: timestamppb.New(now)}, nil
+}
+
+// PushChanges handles gRPC requests to merge the changes to the to-do list of
+// another server into the to-do list.
+func (c *Controller) PushChanges(
+	ctx context.Context,
+	req *todopb.PushChangesRequest,
+) (*todopb.PushChangesResponse, error) {
+	tasks, err := c.syncRepository()
+	if err != nil {
+		return nil, err
+	}
+	changes, err := newTasksFromChangeProtos(req.GetChanges())
+	if err != nil {
+		return nil, invalidArgument(err, "")
+	}
+	result, err := tasks.Merge(ctx, changes, optionalTime(req.GetSince()))
+	if err != nil {
+		return nil, syncError(err, "cannot merge changes")
+	}
+	var applied uint32
+	for _, e := range result.Applied {
+		if e.Type != EventTaskCompleted {
+			applied++
+		}
+	}
+	conflicts := make([]*todopb.SyncConflict, len(result.Conflicts))
+	for i := range result.Conflicts {
+		conflicts[i] = result.Conflicts[i].toProto()
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "merged changes",
+		"received", len(changes), "applied", applied, "conflicts", len(conflicts))
+	return &todopb.PushChangesResponse{AppliedCount: applied, Conflicts: conflicts}, nil
+}
+
+// syncRepository returns the task repository if it can be synchronized with
+// another to-do list.
+func (c *Controller) syncRepository() (SyncRepository, error) {
+	if c.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	tasks, ok := c.tasks.(SyncRepository)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, ErrSyncUnsupported.Error())
+	}
+	return tasks, nil
+}
+
+// syncError converts an error returned by a [SyncRepository] into a gRPC
+// status error like [repositoryError].
+func syncError(err error, format string, args ...any) error {
+	if errors.Is(err, ErrSyncUnsupported) {
+		return status.Error(codes.Unimplemented, err.Error())
+	}
+	return repositoryError(err, format, args...)
+}
+
+// repositoryError converts an error returned by the task repository into a
+// gRPC status error. Context errors keep their meaning, i.e. they result in
+// CANCELED or DEADLINE_EXCEEDED, and exceeded quotas, see [Quotas], result in
+// RESOURCE_EXHAUSTED; all other errors are internal errors.
+func repositoryError(err error, format string, args ...any) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	if IsQuotaExceededError(err) {
+		return status.Errorf(codes.ResourceExhausted, "%s: %v", fmt.Sprintf(format, args...), err)
+	}
+	return status.Errorf(codes.Internal, "%s: %v", fmt.Sprintf(format, args...), err)
 }
@@ -0,0 +1,125 @@
+package todo
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	todov2pb "github.com/mwopitz/todo-daemon/api/todo/v2"
+)
+
+// ControllerV2 handles requests to version 2 of the gRPC API's task service.
+// It converts the requests and responses, and shares everything else with the
+// [Controller] of version 1, so both versions can be served side by side.
+type ControllerV2 struct {
+	todov2pb.UnimplementedTaskServiceServer
+	ctrl *Controller
+}
+
+// NewControllerV2 creates a [ControllerV2] that uses the repository and the
+// options of the specified controller.
+func NewControllerV2(ctrl *Controller) *ControllerV2 {
+	return &ControllerV2{ctrl: ctrl}
+}
+
+// CreateTask handles gRPC requests to create a new task in the to-do list.
+func (c *ControllerV2) CreateTask(ctx context.Context, req *todov2pb.CreateTaskRequest) (*todov2pb.Task, error) {
+	if c.ctrl.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	key, err := idempotencyKey(ctx, req.GetIdempotencyKey())
+	if err != nil {
+		return nil, err
+	}
+	task := newTaskCreateFromProtoV2(req.GetTask())
+	if err := task.Validate(); err != nil {
+		return nil, invalidArgument(renameFieldsV2(err), "task")
+	}
+	task.AllowDuplicate = req.GetAllowDuplicate()
+	if task.TimeZone == "" {
+		task.TimeZone = c.ctrl.timeZone
+	}
+	created, err := c.ctrl.createOnce(ctx, key, task)
+	if err != nil {
+		return nil, err
+	}
+	return created.toProtoV2(), nil
+}
+
+// ListTasks handles gRPC requests to retrieve a page of tasks from the to-do
+// list.
+func (c *ControllerV2) ListTasks(
+	ctx context.Context,
+	req *todov2pb.ListTasksRequest,
+) (*todov2pb.ListTasksResponse, error) {
+	if c.ctrl.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	opts, err := newListOptionsFromProtoV2(req)
+	if err != nil {
+		return nil, invalidArgument(err, "")
+	}
+	// One more task than requested tells whether there is a next page.
+	pageSize := opts.Limit
+	opts.Limit++
+	tasks, err := c.ctrl.list(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	resp := &todov2pb.ListTasksResponse{}
+	if len(tasks) > pageSize {
+		tasks = tasks[:pageSize]
+		if resp.NextPageToken, err = pageToken(NewCursor(&tasks[pageSize-1])); err != nil {
+			return nil, status.Errorf(codes.Internal, "%v", err)
+		}
+	}
+	resp.Tasks = tasks.toProtosV2()
+	return resp, nil
+}
+
+// GetTask handles gRPC requests to retrieve a single task from the to-do list.
+func (c *ControllerV2) GetTask(ctx context.Context, req *todov2pb.GetTaskRequest) (*todov2pb.Task, error) {
+	if c.ctrl.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	task, err := c.ctrl.get(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return task.toProtoV2(), nil
+}
+
+// UpdateTask handles gRPC requests to update the fields of a task listed in
+// the update mask.
+func (c *ControllerV2) UpdateTask(ctx context.Context, req *todov2pb.UpdateTaskRequest) (*todov2pb.Task, error) {
+	if c.ctrl.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	update, err := newTaskUpdateFromProtoV2(req.GetTask(), req.GetUpdateMask(), time.Now())
+	if err != nil {
+		return nil, invalidArgument(err, "")
+	}
+	if err := update.Validate(); err != nil {
+		return nil, invalidArgument(renameFieldsV2(err), "task")
+	}
+	update.ExpectedVersion = req.GetExpectedVersion()
+	task, err := c.ctrl.update(ctx, req.GetTask().GetId(), update)
+	if err != nil {
+		return nil, err
+	}
+	return task.toProtoV2(), nil
+}
+
+// DeleteTask handles gRPC requests to delete a task from the to-do list.
+func (c *ControllerV2) DeleteTask(ctx context.Context, req *todov2pb.DeleteTaskRequest) (*emptypb.Empty, error) {
+	if c.ctrl.tasks == nil {
+		return nil, status.Errorf(codes.Internal, "no task repository provided")
+	}
+	if err := c.ctrl.delete(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
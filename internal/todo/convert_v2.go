@@ -0,0 +1,284 @@
+package todo
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	todov2pb "github.com/mwopitz/todo-daemon/api/todo/v2"
+)
+
+// The sizes of the pages of tasks returned by [ControllerV2.ListTasks]: the
+// default page size, and the maximum page size that larger requests are capped
+// at.
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+)
+
+// v2FieldNames maps the names of the fields of tasks that were renamed in
+// version 2 of the API from their names in version 1 and in [FieldViolation].
+var v2FieldNames = map[string]string{
+	"due_at":       "due_time",
+	"completed_at": "complete_time",
+}
+
+// v2OutputOnlyFields are the fields of version 2 tasks that cannot be updated.
+// They are ignored in update masks, so clients can send back entire tasks.
+var v2OutputOnlyFields = map[string]bool{
+	"id":             true,
+	"create_time":    true,
+	"update_time":    true,
+	"complete_time":  true,
+	"due_time_local": true,
+	"blocked_by":     true,
+	"position":       true,
+	"version":        true,
+	"short_code":     true,
+	"uid":            true,
+}
+
+// v2SortFields maps the fields that version 2 lists of tasks can be ordered
+// by to the corresponding [SortBy] values.
+var v2SortFields = map[string]SortBy{
+	"create_time": SortByCreated,
+	"due_time":    SortByDue,
+	"update_time": SortByUpdated,
+	"position":    SortByPosition,
+}
+
+func (t *Task) toProtoV2() *todov2pb.Task {
+	state := todov2pb.Task_STATE_OPEN
+	if !t.CompletedAt.IsZero() {
+		state = todov2pb.Task_STATE_COMPLETED
+	}
+	return &todov2pb.Task{
+		Id:           t.ID,
+		Summary:      t.Summary,
+		Description:  t.Description,
+		State:        state,
+		CreateTime:   timestamppb.New(t.CreatedAt),
+		UpdateTime:   optionalTimestamp(t.UpdatedAt),
+		CompleteTime: optionalTimestamp(t.CompletedAt),
+		DueTime:      optionalTimestamp(t.DueAt),
+		DueTimeLocal: optionalRFC3339(t.DueAt),
+		TimeZone:     t.TimeZone,
+		Recurrence:   t.Recurrence,
+		Tags:         t.Tags,
+		Project:      t.Project,
+		Starred:      t.Starred,
+		DependsOn:    t.DependsOn,
+		BlockedBy:    t.BlockedBy,
+		Position:     t.Position,
+		Version:      t.Version,
+		ShortCode:    ShortCode(t.ID),
+		Uid:          t.UID,
+		Assignee:     t.Assignee,
+		Links:        t.Links,
+	}
+}
+
+func (ts Tasks) toProtosV2() []*todov2pb.Task {
+	protos := make([]*todov2pb.Task, len(ts))
+	for i := range ts {
+		protos[i] = ts[i].toProtoV2()
+	}
+	return protos
+}
+
+// newTaskCreateFromProtoV2 converts a version 2 task into a [TaskCreate]. The
+// output only fields and the state are ignored, since new tasks are open.
+func newTaskCreateFromProtoV2(proto *todov2pb.Task) *TaskCreate {
+	return &TaskCreate{
+		Summary:     proto.GetSummary(),
+		Description: proto.GetDescription(),
+		DueAt:       optionalTime(proto.GetDueTime()),
+		Tags:        proto.GetTags(),
+		Project:     proto.GetProject(),
+		DependsOn:   proto.GetDependsOn(),
+		Recurrence:  proto.GetRecurrence(),
+		TimeZone:    proto.GetTimeZone(),
+		Starred:     proto.GetStarred(),
+		Assignee:    proto.GetAssignee(),
+		Links:       proto.GetLinks(),
+	}
+}
+
+// newTaskUpdateFromProtoV2 converts the fields of a version 2 task listed in
+// the specified update mask into a [TaskUpdate]. Completing the task sets its
+// completion time to now. The mask "*" updates all fields that can be
+// updated. If the mask is empty or contains unknown fields, or if the state is
+// unspecified, it returns a [ValidationError] for the fields of the request.
+func newTaskUpdateFromProtoV2(proto *todov2pb.Task, mask *fieldmaskpb.FieldMask, now time.Time) (*TaskUpdate, error) {
+	v := validator{subject: "update"}
+	paths := mask.GetPaths()
+	if len(paths) == 0 {
+		v.addf("update_mask", "must not be empty")
+	}
+	if len(paths) == 1 && paths[0] == "*" {
+		paths = []string{
+			"summary", "description", "state", "due_time", "time_zone", "recurrence",
+			"tags", "project", "starred", "depends_on", "assignee", "links",
+		}
+	}
+	u := &TaskUpdate{}
+	for i, path := range paths {
+		switch path {
+		case "summary":
+			summary := proto.GetSummary()
+			u.Summary = &summary
+		case "description":
+			description := proto.GetDescription()
+			u.Description = &description
+		case "state":
+			var completedAt time.Time
+			switch proto.GetState() {
+			case todov2pb.Task_STATE_OPEN:
+			case todov2pb.Task_STATE_COMPLETED:
+				completedAt = now
+			default:
+				v.addf("task.state", "must be STATE_OPEN or STATE_COMPLETED")
+			}
+			u.CompletedAt = &completedAt
+		case "due_time":
+			dueAt := optionalTime(proto.GetDueTime())
+			u.DueAt = &dueAt
+		case "time_zone":
+			timeZone := proto.GetTimeZone()
+			u.TimeZone = &timeZone
+		case "recurrence":
+			recurrence := proto.GetRecurrence()
+			u.Recurrence = &recurrence
+		case "tags":
+			tags := proto.GetTags()
+			u.Tags = &tags
+		case "project":
+			project := proto.GetProject()
+			u.Project = &project
+		case "starred":
+			starred := proto.GetStarred()
+			u.Starred = &starred
+		case "depends_on":
+			dependsOn := proto.GetDependsOn()
+			u.DependsOn = &dependsOn
+		case "assignee":
+			assignee := proto.GetAssignee()
+			u.Assignee = &assignee
+		case "links":
+			links := proto.GetLinks()
+			u.Links = &links
+		default:
+			if !v2OutputOnlyFields[path] {
+				v.addf(fmt.Sprintf("update_mask.paths[%d]", i), "unknown field '%s'", path)
+			}
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// newListOptionsFromProtoV2 converts a version 2 request to list tasks into
+// [ListOptions]. The limit is the size of the requested page, and the offset
+// is decoded from the page token. If the order or the page is invalid, it
+// returns a [ValidationError].
+func newListOptionsFromProtoV2(req *todov2pb.ListTasksRequest) (*ListOptions, error) {
+	opts := &ListOptions{
+		Tags:      req.GetTags(),
+		Project:   req.GetProject(),
+		Starred:   req.GetStarred(),
+		Assignee:  req.GetAssignee(),
+		DueAfter:  optionalTime(req.GetDueAfter()),
+		DueBefore: optionalTime(req.GetDueBefore()),
+		Overdue:   req.GetOverdue(),
+		Limit:     DefaultPageSize,
+	}
+	switch req.GetState() {
+	case todov2pb.Task_STATE_OPEN:
+		opts.Completion = CompletionOpen
+	case todov2pb.Task_STATE_COMPLETED:
+		opts.Completion = CompletionCompleted
+	}
+	v := validator{subject: "list request"}
+	if orderBy := strings.TrimSpace(req.GetOrderBy()); orderBy != "" {
+		field, direction, _ := strings.Cut(orderBy, " ")
+		sortBy, ok := v2SortFields[field]
+		switch direction = strings.TrimSpace(direction); {
+		case !ok:
+			v.addf("order_by", "unknown field '%s' (want create_time, due_time, update_time, or position)", field)
+		case direction != "" && direction != "asc" && direction != "desc":
+			v.addf("order_by", "unknown direction '%s' (want asc or desc)", direction)
+		}
+		opts.SortBy, opts.Descending = sortBy, direction == "desc"
+	}
+	switch size := req.GetPageSize(); {
+	case size < 0:
+		v.addf("page_size", "must not be negative, got %d", size)
+	case size > 0:
+		opts.Limit = min(int(size), MaxPageSize)
+	}
+	if token := req.GetPageToken(); token != "" {
+		cursor, offset, err := parsePageToken(token)
+		if err != nil {
+			v.addf("page_token", "%v", err)
+		}
+		opts.After, opts.Offset = cursor, offset
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// pageToken returns the token of the page of tasks starting after the
+// specified cursor. Clients must treat the token as opaque.
+func pageToken(cursor *Cursor) (string, error) {
+	b, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("cannot create page token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// parsePageToken returns the cursor of the page with the specified token
+// created by [pageToken], or the offset of the page if the token was created
+// by an older server, which paginated by offset.
+func parsePageToken(token string) (*Cursor, int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, 0, errors.New("invalid page token")
+	}
+	if offset, err := strconv.Atoi(string(b)); err == nil && offset >= 0 {
+		return nil, offset, nil
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(b, &cursor); err != nil || cursor.ID == "" {
+		return nil, 0, errors.New("invalid page token")
+	}
+	return &cursor, 0, nil
+}
+
+// renameFieldsV2 renames the fields of the specified validation error to their
+// names in version 2 of the API.
+func renameFieldsV2(err error) error {
+	var e *ValidationError
+	if !errors.As(err, &e) {
+		return err
+	}
+	violations := make([]FieldViolation, len(e.Violations))
+	for i, v := range e.Violations {
+		if name, ok := v2FieldNames[v.Field]; ok {
+			v.Field = name
+		}
+		violations[i] = v
+	}
+	return &ValidationError{Violations: violations, subject: e.subject}
+}
@@ -0,0 +1,228 @@
+package todo
+
+import (
+	"context"
+	"encoding/base64"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	todov2pb "github.com/mwopitz/todo-daemon/api/todo/v2"
+)
+
+func TestNewTaskUpdateFromProtoV2(t *testing.T) {
+	now := time.Date(2025, 12, 24, 18, 0, 0, 0, time.UTC)
+	task := &todov2pb.Task{
+		Id:      "1",
+		Summary: "Buy presents",
+		State:   todov2pb.Task_STATE_COMPLETED,
+		Starred: true,
+		Version: 3,
+	}
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"id", "summary", "state", "version"}}
+	update, err := newTaskUpdateFromProtoV2(task, mask, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if update.Summary == nil || *update.Summary != "Buy presents" {
+		t.Errorf("want summary update; got: %v", update.Summary)
+	}
+	if update.CompletedAt == nil || !update.CompletedAt.Equal(now) {
+		t.Errorf("want completion at %s; got: %v", now, update.CompletedAt)
+	}
+	if update.Starred != nil || update.Description != nil {
+		t.Errorf("want only masked fields to be updated; got: %+v", update)
+	}
+
+	task.State = todov2pb.Task_STATE_OPEN
+	update, err = newTaskUpdateFromProtoV2(task, &fieldmaskpb.FieldMask{Paths: []string{"*"}}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if update.CompletedAt == nil || !update.CompletedAt.IsZero() {
+		t.Errorf("want reopening; got: %v", update.CompletedAt)
+	}
+	if update.Starred == nil || !*update.Starred {
+		t.Errorf("want starred update; got: %v", update.Starred)
+	}
+}
+
+func TestNewTaskUpdateFromProtoV2Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		task  *todov2pb.Task
+		paths []string
+		want  []string
+	}{
+		{"NoPaths", &todov2pb.Task{}, nil, []string{"update_mask"}},
+		{"UnknownPath", &todov2pb.Task{}, []string{"summary", "due_at"}, []string{"update_mask.paths[1]"}},
+		{"UnspecifiedState", &todov2pb.Task{}, []string{"state"}, []string{"task.state"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTaskUpdateFromProtoV2(tt.task, &fieldmaskpb.FieldMask{Paths: tt.paths}, time.Now())
+			if got := violatedFields(t, err); !slices.Equal(got, tt.want) {
+				t.Errorf("want violated fields: %v; got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewListOptionsFromProtoV2(t *testing.T) {
+	opts, err := newListOptionsFromProtoV2(&todov2pb.ListTasksRequest{
+		State:    todov2pb.Task_STATE_OPEN,
+		OrderBy:  "due_time desc",
+		PageSize: 5000,
+		// Older servers created tokens holding offsets.
+		PageToken: base64.RawURLEncoding.EncodeToString([]byte("20")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ListOptions{
+		Completion: CompletionOpen,
+		SortBy:     SortByDue,
+		Descending: true,
+		Offset:     20,
+		Limit:      MaxPageSize,
+	}
+	if opts.Completion != want.Completion || opts.SortBy != want.SortBy || opts.Descending != want.Descending ||
+		opts.Offset != want.Offset || opts.Limit != want.Limit {
+		t.Errorf("want options: %+v; got: %+v", want, *opts)
+	}
+
+	_, err = newListOptionsFromProtoV2(&todov2pb.ListTasksRequest{
+		OrderBy:   "due_time sideways",
+		PageSize:  -1,
+		PageToken: "not a token",
+	})
+	if got, want := violatedFields(t, err), []string{"order_by", "page_size", "page_token"}; !slices.Equal(got, want) {
+		t.Errorf("want violated fields: %v; got: %v", want, got)
+	}
+
+	cursor := &Cursor{ID: "3", Seq: 3, CreatedAt: time.Date(2025, 12, 24, 18, 0, 0, 0, time.UTC)}
+	token, err := pageToken(cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err = newListOptionsFromProtoV2(&todov2pb.ListTasksRequest{PageToken: token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.After == nil || *opts.After != *cursor || opts.Offset != 0 {
+		t.Errorf("want cursor %+v; got: %+v, offset %d", cursor, opts.After, opts.Offset)
+	}
+}
+
+func TestControllerV2ListTasksPages(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryTaskDB()
+	for _, summary := range []string{"a", "b", "c", "d", "e"} {
+		if _, err := db.Create(ctx, &TaskCreate{Summary: summary}); err != nil {
+			t.Fatalf("cannot create task: %v", err)
+		}
+	}
+	ctrl := NewControllerV2(NewController(nil, nil, db, NewEventBus()))
+	var summaries []string
+	req := &todov2pb.ListTasksRequest{PageSize: 2}
+	for pages := 1; ; pages++ {
+		resp, err := ctrl.ListTasks(ctx, req)
+		if err != nil {
+			t.Fatalf("cannot list tasks: %v", err)
+		}
+		for _, task := range resp.GetTasks() {
+			summaries = append(summaries, task.GetSummary())
+		}
+		if pages == 1 {
+			// Removing a task from the first page must not shift the
+			// second one.
+			if err := db.Delete(ctx, resp.GetTasks()[0].GetId()); err != nil {
+				t.Fatalf("cannot delete task: %v", err)
+			}
+		}
+		if resp.GetNextPageToken() == "" {
+			if pages != 3 {
+				t.Errorf("want 3 pages; got: %d", pages)
+			}
+			break
+		}
+		req.PageToken = resp.GetNextPageToken()
+	}
+	if want := []string{"a", "b", "c", "d", "e"}; !slices.Equal(summaries, want) {
+		t.Errorf("want tasks: %v; got: %v", want, summaries)
+	}
+}
+
+func TestControllerV2CreateTaskInvalid(t *testing.T) {
+	ctrl := NewControllerV2(NewController(nil, nil, NewInMemoryTaskDB(), NewEventBus()))
+	_, err := ctrl.CreateTask(context.Background(), &todov2pb.CreateTaskRequest{Task: &todov2pb.Task{
+		Summary: "a",
+		DueTime: optionalTimestamp(time.Date(1969, 1, 1, 0, 0, 0, 0, time.UTC)),
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("want INVALID_ARGUMENT; got: %v", err)
+	}
+	if want := "invalid task: task.due_time:"; !strings.HasPrefix(status.Convert(err).Message(), want) {
+		t.Errorf("want message starting with %q; got: %q", want, status.Convert(err).Message())
+	}
+}
+
+// FuzzNewTaskUpdateFromProtoV2 decodes request bodies to update version 2
+// tasks like the gRPC gateway does, and checks that an update is only
+// accepted with a non-empty mask of known fields and only sets those fields.
+func FuzzNewTaskUpdateFromProtoV2(f *testing.F) {
+	f.Add(`{"task":{"id":"1","summary":"Buy milk"},"updateMask":"summary"}`)
+	f.Add(`{"task":{"id":"1","state":"STATE_COMPLETED"},"updateMask":"state,state"}`)
+	f.Add(`{"task":{"id":"1","state":"STATE_UNSPECIFIED"},"updateMask":"*"}`)
+	f.Add(`{"task":{"id":"1","summary":"Buy milk"},"updateMask":""}`)
+	f.Add(`{"task":{"id":"1","summary":"Buy milk"},"updateMask":"id,version,unknown"}`)
+	f.Add(`{"task":{"id":"1"},"updateMask":"*,summary"}`)
+	v2Fields := map[string]string{
+		"summary": "summary", "description": "description", "state": "completed_at", "due_time": "due_at",
+		"time_zone": "time_zone", "recurrence": "recurrence", "tags": "tags", "project": "project",
+		"starred": "starred", "depends_on": "depends_on", "assignee": "assignee", "links": "links",
+	}
+	now := time.Date(2025, 12, 24, 18, 0, 0, 0, time.UTC)
+	f.Fuzz(func(t *testing.T, body string) {
+		var req todov2pb.UpdateTaskRequest
+		if err := protojson.Unmarshal([]byte(body), &req); err != nil {
+			return
+		}
+		paths := req.GetUpdateMask().GetPaths()
+		update, err := newTaskUpdateFromProtoV2(req.GetTask(), req.GetUpdateMask(), now)
+		if err != nil {
+			if !IsValidationError(err) {
+				t.Errorf("want validation error; got: %v", err)
+			}
+			return
+		}
+		if len(paths) == 0 {
+			t.Fatal("want empty update mask to be rejected")
+		}
+		if len(paths) == 1 && paths[0] == "*" {
+			paths = slices.Collect(maps.Keys(v2Fields))
+		}
+		var want []string
+		for _, path := range paths {
+			field, ok := v2Fields[path]
+			if !ok && !v2OutputOnlyFields[path] {
+				t.Fatalf("want unknown field '%s' to be rejected", path)
+			}
+			if ok && !slices.Contains(want, field) {
+				want = append(want, field)
+			}
+		}
+		slices.Sort(want)
+		if got := updatedFields(update); !slices.Equal(got, want) {
+			t.Errorf("want update of fields %v; got: %v", want, got)
+		}
+		_ = update.Validate()
+	})
+}
@@ -0,0 +1,11 @@
+package todo
+
+// DemoTasks returns the tasks that 'run --demo-data' and 'tasks seed' add to
+// the to-do list for trying out the To-do Daemon.
+func DemoTasks() []TaskCreate {
+	return []TaskCreate{
+		{Summary: "Get some milk 🥛"},
+		{Summary: "Walk the dog 🐕"},
+		{Summary: "Take over the world! 🌍"},
+	}
+}
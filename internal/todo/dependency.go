@@ -0,0 +1,63 @@
+package todo
+
+// CheckDependencies checks if the task with the specified ID can depend on the
+// tasks with the specified IDs, given a function that looks up the tasks of a
+// repository by ID. It returns a [TaskNotFoundError] if a dependency doesn't
+// exist or has been deleted, and a [DependencyCycleError] if the dependencies
+// would form a cycle, including a task depending on itself. The ID of a task
+// that is about to be created is empty, since no task can depend on it yet.
+// Repositories use it to implement [TaskRepository.Create] and
+// [TaskRepository.Update].
+func CheckDependencies(id string, dependsOn []string, lookup func(id string) (*Task, bool)) error {
+	for _, dep := range dependsOn {
+		if t, ok := lookup(dep); !ok || !t.DeletedAt.IsZero() {
+			return NewTaskNotFoundError(dep)
+		}
+	}
+	if id == "" {
+		return nil
+	}
+	// Search the dependency graph depth-first for a path from the task back
+	// to itself, using the new dependencies instead of the task's current
+	// ones.
+	visited := make(map[string]bool)
+	var path []string
+	var visit func(current string, deps []string) bool
+	visit = func(current string, deps []string) bool {
+		path = append(path, current)
+		for _, dep := range deps {
+			if dep == id {
+				path = append(path, id)
+				return true
+			}
+			if visited[dep] {
+				continue
+			}
+			visited[dep] = true
+			if t, ok := lookup(dep); ok && visit(dep, t.DependsOn) {
+				return true
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+	if visit(id, dependsOn) {
+		return NewDependencyCycleError(path)
+	}
+	return nil
+}
+
+// OpenDependencies returns the IDs of the tasks that the specified task depends
+// on and that have neither been completed nor deleted, given a function that
+// looks up the tasks of a repository by ID. Dependencies that don't exist
+// anymore don't block the task. Repositories use it to compute
+// [Task.BlockedBy].
+func OpenDependencies(t *Task, lookup func(id string) (*Task, bool)) []string {
+	var open []string
+	for _, dep := range t.DependsOn {
+		if d, ok := lookup(dep); ok && d.CompletedAt.IsZero() && d.DeletedAt.IsZero() {
+			open = append(open, dep)
+		}
+	}
+	return open
+}
@@ -0,0 +1,68 @@
+package todo
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCheckDependencies(t *testing.T) {
+	tasks := map[string]Task{
+		"1": {ID: "1"},
+		"2": {ID: "2", DependsOn: []string{"1"}},
+		"3": {ID: "3", DependsOn: []string{"2", "1"}},
+		"4": {ID: "4", DeletedAt: time.Now()},
+	}
+	lookup := func(id string) (*Task, bool) {
+		t, ok := tasks[id]
+		return &t, ok
+	}
+	tests := []struct {
+		id        string
+		dependsOn []string
+		cycle     []string
+		missing   string
+	}{
+		{id: "", dependsOn: []string{"3"}},
+		{id: "3", dependsOn: []string{"1"}},
+		{id: "1", dependsOn: []string{"1"}, cycle: []string{"1", "1"}},
+		{id: "1", dependsOn: []string{"3"}, cycle: []string{"1", "3", "2", "1"}},
+		{id: "2", dependsOn: []string{"3"}, cycle: []string{"2", "3", "2"}},
+		{id: "1", dependsOn: []string{"5"}, missing: "5"},
+		{id: "1", dependsOn: []string{"4"}, missing: "4"},
+	}
+	for _, tt := range tests {
+		err := CheckDependencies(tt.id, tt.dependsOn, lookup)
+		var cycleErr *DependencyCycleError
+		var notFoundErr *TaskNotFoundError
+		switch {
+		case tt.cycle != nil:
+			if !errors.As(err, &cycleErr) || !slices.Equal(cycleErr.Cycle, tt.cycle) {
+				t.Errorf("%s -> %v: want cycle: %v; got: %v", tt.id, tt.dependsOn, tt.cycle, err)
+			}
+		case tt.missing != "":
+			if !errors.As(err, &notFoundErr) || notFoundErr.ID != tt.missing {
+				t.Errorf("%s -> %v: want missing task: %s; got: %v", tt.id, tt.dependsOn, tt.missing, err)
+			}
+		case err != nil:
+			t.Errorf("%s -> %v: want no error; got: %v", tt.id, tt.dependsOn, err)
+		}
+	}
+}
+
+func TestOpenDependencies(t *testing.T) {
+	tasks := map[string]Task{
+		"1": {ID: "1"},
+		"2": {ID: "2", CompletedAt: time.Now()},
+		"3": {ID: "3", DeletedAt: time.Now()},
+	}
+	lookup := func(id string) (*Task, bool) {
+		t, ok := tasks[id]
+		return &t, ok
+	}
+	task := &Task{ID: "4", DependsOn: []string{"1", "2", "3", "gone"}}
+	if got := OpenDependencies(task, lookup); !slices.Equal(got, []string{"1"}) {
+		t.Errorf("want open dependencies: [1]; got: %v", got)
+	}
+}
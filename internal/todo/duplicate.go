@@ -0,0 +1,91 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+// MaxDuplicates is the maximum number of copies of a task that
+// [DuplicateTask] creates at once.
+const MaxDuplicates = 100
+
+// DuplicateOptions specifies how [DuplicateTask] copies a task.
+type DuplicateOptions struct {
+	// Count is the number of copies to create, at least 1 and at most
+	// [MaxDuplicates].
+	Count int
+	// SummarySuffix is appended to the summary of each copy, e.g. " (copy)".
+	SummarySuffix string
+	// DueShift is the time between the due times of the task and its first
+	// copy, and between the due times of consecutive copies. It is ignored
+	// if the task has no due time.
+	DueShift time.Duration
+}
+
+// Validate checks that the number of copies is within the limits.
+func (o *DuplicateOptions) Validate() error {
+	v := validator{subject: "duplicate request"}
+	if o.Count < 1 || o.Count > MaxDuplicates {
+		v.addf("count", "must be between 1 and %d, got %d", MaxDuplicates, o.Count)
+	}
+	return v.err()
+}
+
+// DuplicateTask creates copies of the task with the specified ID in the
+// repository. The copies have the summary, description, tags, project, star,
+// assignee, links, time zone, and due time of the task, but neither its
+// dependencies nor its recurrence, and they are open even if the task is
+// completed. It returns the created copies.
+//
+// If the repository supports batches, either all copies are created or none.
+// Otherwise, the copies are created one by one, and the copies created before
+// an error stay in the to-do list.
+func DuplicateTask(ctx context.Context, tasks TaskRepository, id string, opts DuplicateOptions) (Tasks, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	task, err := tasks.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	creates := make([]*TaskCreate, opts.Count)
+	for i := range creates {
+		create := &TaskCreate{
+			Summary:     task.Summary + opts.SummarySuffix,
+			Description: task.Description,
+			Tags:        slices.Clone(task.Tags),
+			Project:     task.Project,
+			TimeZone:    task.TimeZone,
+			Starred:     task.Starred,
+			Assignee:    task.Assignee,
+			Links:       slices.Clone(task.Links),
+		}
+		if !task.DueAt.IsZero() {
+			create.DueAt = task.DueAt.Add(time.Duration(i+1) * opts.DueShift)
+		}
+		if err := create.Validate(); err != nil {
+			return nil, err
+		}
+		creates[i] = create
+	}
+	if batch, ok := tasks.(BatchRepository); ok {
+		created, err := batch.CreateAll(ctx, creates)
+		switch {
+		case err == nil:
+			return created, nil
+		case !errors.Is(err, ErrBatchUnsupported):
+			return nil, err
+		}
+	}
+	created := make(Tasks, 0, len(creates))
+	for _, create := range creates {
+		task, err := tasks.Create(ctx, create)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, *task)
+	}
+	return created, nil
+}
@@ -0,0 +1,129 @@
+package todo
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+)
+
+func TestDuplicateTask(t *testing.T) {
+	due := time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		batch bool
+		due   time.Time
+		opts  DuplicateOptions
+		want  []string
+		dues  []time.Time
+	}{
+		{"Once", true, due, DuplicateOptions{Count: 1}, []string{"report"}, []time.Time{due}},
+		{
+			"Shifted", true, due,
+			DuplicateOptions{Count: 2, SummarySuffix: " (copy)", DueShift: 24 * time.Hour},
+			[]string{"report (copy)", "report (copy)"},
+			[]time.Time{due.Add(24 * time.Hour), due.Add(48 * time.Hour)},
+		},
+		{
+			"NoDueTime", true, time.Time{},
+			DuplicateOptions{Count: 2, DueShift: time.Hour},
+			[]string{"report", "report"},
+			[]time.Time{{}, {}},
+		},
+		{
+			"Unbatched", false, due,
+			DuplicateOptions{Count: 2, DueShift: time.Hour},
+			[]string{"report", "report"},
+			[]time.Time{due.Add(time.Hour), due.Add(2 * time.Hour)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := NewInMemoryTaskDB()
+			var repo TaskRepository = db
+			if !tt.batch {
+				repo = unbatchedRepository{db}
+			}
+			task, err := db.Create(ctx, &TaskCreate{
+				Summary: "report",
+				DueAt:   tt.due,
+				Tags:    []string{"work"},
+				Starred: true,
+			})
+			if err != nil {
+				t.Fatalf("cannot create task: %v", err)
+			}
+			completedAt := time.Now()
+			if _, err := db.Update(ctx, task.ID, &TaskUpdate{CompletedAt: &completedAt}); err != nil {
+				t.Fatalf("cannot complete task: %v", err)
+			}
+
+			copies, err := DuplicateTask(ctx, repo, task.ID, tt.opts)
+			if err != nil {
+				t.Fatalf("cannot duplicate task: %v", err)
+			}
+			if got := summaries(copies); !slices.Equal(got, tt.want) {
+				t.Errorf("want summaries %v; got: %v", tt.want, got)
+			}
+			for i := range copies {
+				c := &copies[i]
+				if c.ID == task.ID || !c.CompletedAt.IsZero() || !c.Starred || !slices.Equal(c.Tags, task.Tags) {
+					t.Errorf("want open, starred copy with tags %v; got: %+v", task.Tags, c)
+				}
+				if i < len(tt.dues) && !c.DueAt.Equal(tt.dues[i]) {
+					t.Errorf("want copy %d due at %v; got: %v", i, tt.dues[i], c.DueAt)
+				}
+			}
+		})
+	}
+}
+
+func TestDuplicateTaskInvalid(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryTaskDB()
+	task, err := db.Create(ctx, &TaskCreate{Summary: "report"})
+	if err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+	for _, count := range []int{0, MaxDuplicates + 1} {
+		if _, err := DuplicateTask(ctx, db, task.ID, DuplicateOptions{Count: count}); !IsValidationError(err) {
+			t.Errorf("want validation error for count %d; got: %v", count, err)
+		}
+	}
+	if _, err := DuplicateTask(ctx, db, "missing", DuplicateOptions{Count: 1}); !IsTaskNotFoundError(err) {
+		t.Errorf("want task not found error; got: %v", err)
+	}
+}
+
+func TestControllerDuplicateTask(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryTaskDB()
+	ctrl := NewController(nil, nil, db, NewEventBus())
+	task, err := db.Create(ctx, &TaskCreate{Summary: "report"})
+	if err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+
+	resp, err := ctrl.DuplicateTask(ctx, &todopb.DuplicateTaskRequest{Id: task.ID})
+	if err != nil {
+		t.Fatalf("cannot duplicate task: %v", err)
+	}
+	if got := resp.GetTasks(); len(got) != 1 || got[0].GetSummary() != "report" {
+		t.Errorf("want one copy for zero count; got: %v", got)
+	}
+
+	req := &todopb.DuplicateTaskRequest{Id: task.ID, Count: MaxDuplicates + 1, DueShift: durationpb.New(time.Hour)}
+	if _, err := ctrl.DuplicateTask(ctx, req); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("want %v for too many copies; got: %v", codes.InvalidArgument, err)
+	}
+	if _, err := ctrl.DuplicateTask(ctx, &todopb.DuplicateTaskRequest{Id: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("want %v for missing task; got: %v", codes.NotFound, err)
+	}
+}
@@ -3,6 +3,7 @@ package todo
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // TaskNotFoundError should be returned by [TaskRepository.Update] and
@@ -27,3 +28,86 @@ func IsTaskNotFoundError(err error) bool {
 func (e *TaskNotFoundError) Error() string {
 	return fmt.Sprintf("no such task: '%s'", e.ID)
 }
+
+// TaskConflictError should be returned by [TaskRepository.Update] when the
+// update's precondition fails, i.e. when the task's version doesn't match the
+// expected version.
+type TaskConflictError struct {
+	// ID is the ID of the task that was not updated.
+	ID string
+	// Expected is the version that the task was expected to have.
+	Expected uint64
+	// Actual is the current version of the task.
+	Actual uint64
+}
+
+// NewTaskConflictError creates a [TaskConflictError] for the task with the
+// specified ID and versions.
+func NewTaskConflictError(id string, expected, actual uint64) *TaskConflictError {
+	return &TaskConflictError{ID: id, Expected: expected, Actual: actual}
+}
+
+// IsTaskConflictError checks if the provided error is a [TaskConflictError].
+func IsTaskConflictError(err error) bool {
+	var e *TaskConflictError
+	return err != nil && errors.As(err, &e)
+}
+
+func (e *TaskConflictError) Error() string {
+	return fmt.Sprintf("task '%s' was modified concurrently: expected version %d, got version %d",
+		e.ID, e.Expected, e.Actual)
+}
+
+// AmbiguousTaskReferenceError is returned by [ResolveTask] when a reference
+// matches several tasks.
+type AmbiguousTaskReferenceError struct {
+	// Ref is the ambiguous reference.
+	Ref string
+	// IDs are the IDs of the tasks matching the reference.
+	IDs []string
+}
+
+// NewAmbiguousTaskReferenceError creates an [AmbiguousTaskReferenceError] for
+// the specified reference and the IDs of the matching tasks.
+func NewAmbiguousTaskReferenceError(ref string, ids []string) *AmbiguousTaskReferenceError {
+	return &AmbiguousTaskReferenceError{Ref: ref, IDs: ids}
+}
+
+// IsAmbiguousTaskReferenceError checks if the provided error is an
+// [AmbiguousTaskReferenceError].
+func IsAmbiguousTaskReferenceError(err error) bool {
+	var e *AmbiguousTaskReferenceError
+	return err != nil && errors.As(err, &e)
+}
+
+func (e *AmbiguousTaskReferenceError) Error() string {
+	return fmt.Sprintf("ambiguous task reference '%s': matches tasks '%s'",
+		e.Ref, strings.Join(e.IDs, "', '"))
+}
+
+// DependencyCycleError is returned by [TaskRepository.Create] and
+// [TaskRepository.Update] when the dependencies of a task would form a cycle,
+// so none of the tasks in the cycle could ever be completed.
+type DependencyCycleError struct {
+	// Cycle holds the IDs of the tasks in the cycle, starting and ending with
+	// the task whose dependencies were to be changed. Each task depends on
+	// the next one.
+	Cycle []string
+}
+
+// NewDependencyCycleError creates a [DependencyCycleError] for the specified
+// cycle of task IDs.
+func NewDependencyCycleError(cycle []string) *DependencyCycleError {
+	return &DependencyCycleError{Cycle: cycle}
+}
+
+// IsDependencyCycleError checks if the provided error is a
+// [DependencyCycleError].
+func IsDependencyCycleError(err error) bool {
+	var e *DependencyCycleError
+	return err != nil && errors.As(err, &e)
+}
+
+func (e *DependencyCycleError) Error() string {
+	return fmt.Sprintf("dependency cycle: '%s'", strings.Join(e.Cycle, "' -> '"))
+}
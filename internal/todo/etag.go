@@ -0,0 +1,88 @@
+package todo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// ifMatchMetadataKey is the incoming gRPC metadata key under which the
+	// gateway forwards the If-Match header of REST requests.
+	ifMatchMetadataKey = "grpcgateway-if-match"
+	// ETagMetadataKey is the outgoing gRPC header metadata key that holds the
+	// entity tag of the returned task or, for lists of tasks, of the
+	// repository's revision.
+	ETagMetadataKey = "etag"
+	// LastModifiedMetadataKey is the outgoing gRPC header metadata key that
+	// holds the time of the last modification of the returned task or tasks
+	// as HTTP date.
+	LastModifiedMetadataKey = "last-modified"
+)
+
+// ETag formats the specified task version as HTTP entity tag.
+func ETag(version uint64) string {
+	return strconv.Quote(strconv.FormatUint(version, 10))
+}
+
+// ParseETag parses an HTTP entity tag created by [ETag]. Weak entity tags are
+// accepted as well.
+func ParseETag(etag string) (uint64, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+	unquoted, err := strconv.Unquote(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid entity tag: %s", etag)
+	}
+	version, err := strconv.ParseUint(unquoted, 10, 64)
+	if err != nil || version == 0 {
+		return 0, fmt.Errorf("invalid entity tag: %s", etag)
+	}
+	return version, nil
+}
+
+// ifMatchVersion returns the task version from the If-Match header forwarded
+// by the gateway, or zero if there is no such header.
+func ifMatchVersion(ctx context.Context) (uint64, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0, nil
+	}
+	values := md.Get(ifMatchMetadataKey)
+	if len(values) == 0 || strings.TrimSpace(values[0]) == "*" {
+		return 0, nil
+	}
+	return ParseETag(values[0])
+}
+
+// setETag sends the entity tag and the time of the last update of the
+// specified task as gRPC header. Outside of a gRPC call, e.g. if the
+// controller is used in-process, there is no header to send the entity tag in.
+func setETag(ctx context.Context, t *Task) error {
+	return setValidators(ctx, ETag(t.Version), lastUpdate(t))
+}
+
+// setRevision sends the entity tag and the modification time of the specified
+// revision of the repository as gRPC header, see [setETag].
+func setRevision(ctx context.Context, r *Revision) error {
+	return setValidators(ctx, r.ETag(), r.ModifiedAt)
+}
+
+// setValidators sends the specified entity tag and modification time as gRPC
+// header, which the gateway forwards as ETag and Last-Modified headers, so
+// REST clients can make conditional requests.
+func setValidators(ctx context.Context, etag string, modifiedAt time.Time) error {
+	if grpc.ServerTransportStreamFromContext(ctx) == nil {
+		return nil
+	}
+	md := metadata.Pairs(
+		ETagMetadataKey, etag,
+		LastModifiedMetadataKey, modifiedAt.UTC().Format(http.TimeFormat),
+	)
+	return grpc.SetHeader(ctx, md)
+}
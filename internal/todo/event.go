@@ -0,0 +1,532 @@
+package todo
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/logging"
+)
+
+// EventType identifies the kind of change that happened to a task.
+type EventType string
+
+const (
+	// EventTaskCreated is published when a task was created.
+	EventTaskCreated EventType = "task.created"
+	// EventTaskUpdated is published when a task was updated.
+	EventTaskUpdated EventType = "task.updated"
+	// EventTaskCompleted is published when a task was marked as completed, in
+	// addition to [EventTaskUpdated].
+	EventTaskCompleted EventType = "task.completed"
+	// EventTaskDeleted is published when a task was deleted. Only the ID of the
+	// event's task is set.
+	EventTaskDeleted EventType = "task.deleted"
+	// EventTaskAssigned is published when a task was created with an assignee
+	// or an update set its assignee, in addition to [EventTaskCreated] or
+	// [EventTaskUpdated].
+	EventTaskAssigned EventType = "task.assigned"
+)
+
+// IsValid checks if the event type is one of the types above.
+func (t EventType) IsValid() bool {
+	switch t {
+	case EventTaskCreated, EventTaskUpdated, EventTaskCompleted, EventTaskDeleted, EventTaskAssigned:
+		return true
+	default:
+		return false
+	}
+}
+
+// eventHistorySize is the number of recent events an [EventBus] keeps for
+// subscribers resuming with [EventBus.SubscribeAfter].
+const eventHistorySize = 256
+
+// DefaultWatchBufferSize is the default number of events buffered for each
+// client watching the tasks.
+const DefaultWatchBufferSize = 64
+
+// OverflowPolicy specifies what happens when a subscriber of an [EventBus]
+// falls so far behind that its buffer is full. Publishing never waits for
+// slow subscribers, so they cannot block the modifications of the tasks.
+type OverflowPolicy string
+
+// The supported overflow policies.
+const (
+	// OverflowDropOldest drops the oldest buffered event to make room for the
+	// new one, so the subscriber eventually catches up with the most recent
+	// changes. This is the default.
+	OverflowDropOldest OverflowPolicy = "drop-oldest"
+	// OverflowDisconnect closes the subscriber's channel, so it knows that it
+	// missed events, e.g. to reconnect and fetch the tasks again.
+	OverflowDisconnect OverflowPolicy = "disconnect"
+)
+
+// ParseOverflowPolicy parses the specified overflow policy: "drop-oldest" or
+// "disconnect".
+func ParseOverflowPolicy(s string) (OverflowPolicy, error) {
+	switch p := OverflowPolicy(s); p {
+	case OverflowDropOldest, OverflowDisconnect:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid overflow policy: '%s' (want drop-oldest or disconnect)", s)
+	}
+}
+
+// Event describes a change to a task in the to-do list.
+type Event struct {
+	// Seq is the sequence number of the event. The [EventBus] numbers the
+	// events it publishes consecutively, starting at 1.
+	Seq uint64
+	// Type is the kind of change.
+	Type EventType
+	// Task is the state of the task after the change.
+	Task Task
+	// Time is the time when the change happened.
+	Time time.Time
+}
+
+func (e *Event) toProto() *todopb.TaskEvent {
+	var t todopb.TaskEvent_Type
+	switch e.Type {
+	case EventTaskCreated:
+		t = todopb.TaskEvent_TYPE_CREATED
+	case EventTaskUpdated:
+		t = todopb.TaskEvent_TYPE_UPDATED
+	case EventTaskCompleted:
+		t = todopb.TaskEvent_TYPE_COMPLETED
+	case EventTaskDeleted:
+		t = todopb.TaskEvent_TYPE_DELETED
+	case EventTaskAssigned:
+		t = todopb.TaskEvent_TYPE_ASSIGNED
+	}
+	return &todopb.TaskEvent{
+		Type:     t,
+		Task:     e.Task.toProto(),
+		Time:     timestamppb.New(e.Time),
+		Sequence: e.Seq,
+	}
+}
+
+// EventBusStats holds the counters of an [EventBus], which show whether its
+// subscribers keep up with the events.
+type EventBusStats struct {
+	// Subscribers is the number of current subscribers.
+	Subscribers int
+	// Dropped is the number of events that were dropped for subscribers that
+	// fell behind, since the bus was created.
+	Dropped uint64
+	// Disconnected is the number of subscribers that were disconnected for
+	// falling behind, since the bus was created.
+	Disconnected uint64
+}
+
+// EventBus distributes [Event]s to all of its subscribers. It keeps the most
+// recent events, so subscribers can catch up on the events they missed.
+type EventBus struct {
+	mu      sync.Mutex
+	subs    map[chan Event]*subscriber
+	seq     uint64
+	history []Event
+	// dropped and disconnected count the events dropped and the subscribers
+	// disconnected due to their overflow policy.
+	dropped      uint64
+	disconnected uint64
+}
+
+// subscriber holds the state of a subscription to an [EventBus].
+type subscriber struct {
+	policy OverflowPolicy
+	// overflowing specifies whether events are being dropped for the
+	// subscriber, which is only logged once until it catches up.
+	overflowing bool
+}
+
+// SubscribeOption configures a subscription to an [EventBus].
+type SubscribeOption func(s *subscriber)
+
+// WithOverflowPolicy sets what happens when the subscriber's buffer is full.
+// The default is [OverflowDropOldest].
+func WithOverflowPolicy(policy OverflowPolicy) SubscribeOption {
+	return func(s *subscriber) {
+		s.policy = policy
+	}
+}
+
+// NewEventBus creates an [EventBus] without subscribers.
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subs: make(map[chan Event]*subscriber),
+	}
+}
+
+// Subscribe registers a new subscriber, which receives all events published
+// after subscribing on the returned channel. The channel buffers up to size
+// events; what happens if the subscriber falls further behind depends on its
+// [OverflowPolicy]. The returned function must be called to unsubscribe, which
+// closes the channel unless the subscriber has been disconnected already.
+func (b *EventBus) Subscribe(size int, opts ...SubscribeOption) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.subscribe(make(chan Event, max(size, 1)), opts)
+}
+
+// SubscribeAfter is like [EventBus.Subscribe], but the returned channel first
+// receives the events published after the event with the specified sequence
+// number. If some of these events are no longer available, e.g. because the
+// subscriber has been gone for too long or the sequence number is from before
+// a restart, the returned bool is false and only the new events are received.
+func (b *EventBus) SubscribeAfter(seq uint64, size int, opts ...SubscribeOption) (<-chan Event, func(), bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if seq > b.seq || b.seq-seq > uint64(len(b.history)) {
+		ch, unsubscribe := b.subscribe(make(chan Event, max(size, 1)), opts)
+		return ch, unsubscribe, false
+	}
+	missed := b.history[len(b.history)-int(b.seq-seq):]
+	ch := make(chan Event, len(missed)+max(size, 1))
+	for _, e := range missed {
+		ch <- e
+	}
+	_, unsubscribe := b.subscribe(ch, opts)
+	return ch, unsubscribe, true
+}
+
+// subscribe registers the specified channel as subscriber. The caller must
+// hold the lock.
+func (b *EventBus) subscribe(ch chan Event, opts []SubscribeOption) (<-chan Event, func()) {
+	sub := &subscriber{policy: OverflowDropOldest}
+	for _, opt := range opts {
+		opt(sub)
+	}
+	b.subs[ch] = sub
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		// A disconnected subscriber's channel is closed already.
+		if _, ok := b.subs[ch]; ok {
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Stats returns the current counters of the bus.
+func (b *EventBus) Stats() EventBusStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return EventBusStats{
+		Subscribers:  len(b.subs),
+		Dropped:      b.dropped,
+		Disconnected: b.disconnected,
+	}
+}
+
+// Publish assigns the next sequence number to the specified event and sends it
+// to all subscribers without blocking. Subscribers whose buffer is full are
+// handled according to their [OverflowPolicy].
+func (b *EventBus) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.seq++
+	e.Seq = b.seq
+	if len(b.history) == eventHistorySize {
+		b.history = slices.Delete(b.history, 0, 1)
+	}
+	b.history = append(b.history, e)
+	for ch, sub := range b.subs {
+		select {
+		case ch <- e:
+			if len(ch) <= 1 {
+				// The subscriber has caught up.
+				sub.overflowing = false
+			}
+			continue
+		default:
+		}
+		if sub.policy == OverflowDisconnect {
+			logger().Warn("disconnecting slow subscriber", "buffer_size", cap(ch))
+			delete(b.subs, ch)
+			close(ch)
+			b.disconnected++
+			continue
+		}
+		// Only the publisher sends on the channel while holding the lock, so
+		// there is room for the event after dropping the oldest one, unless
+		// the subscriber has received it in the meantime, which makes room,
+		// too.
+		select {
+		case <-ch:
+			b.dropped++
+		default:
+		}
+		ch <- e
+		if !sub.overflowing {
+			logger().Warn("dropping oldest events for slow subscriber", "buffer_size", cap(ch))
+			sub.overflowing = true
+		}
+	}
+}
+
+// ReloadingRepository is a [TaskRepository] whose tasks may be changed by
+// other programs, e.g. one keeping them in a file that users edit by hand,
+// which it notices and reloads the tasks.
+type ReloadingRepository interface {
+	TaskRepository
+	// OnReload registers the function that is called after each reload with
+	// all tasks, including the trash, before and after the reload. The
+	// function must not call the repository.
+	OnReload(fn func(before, after Tasks))
+}
+
+// PublishReloads publishes the changes of each reload of the specified
+// repository as [Event]s on the specified bus, see [ChangeEvents], if it is a
+// [ReloadingRepository]. The repository must not be wrapped, e.g. by
+// [NewPublishingRepository], since the wrappers hide the reloads.
+func PublishReloads(tasks TaskRepository, bus *EventBus) {
+	r, ok := tasks.(ReloadingRepository)
+	if !ok {
+		return
+	}
+	r.OnReload(func(before, after Tasks) {
+		events := ChangeEvents(before, after, time.Now())
+		for _, e := range events {
+			bus.Publish(e)
+		}
+		logger().Info("published changes of reloaded tasks", "events", len(events))
+	})
+}
+
+// ChangeEvents returns the events that describe the changes between the
+// specified tasks, e.g. the tasks of a repository before and after they were
+// replaced. Tasks that appear or return from the trash are created, like by
+// [TaskRepository.Restore], and tasks that disappear or move to the trash are
+// deleted. The events are ordered like the tasks after the change, followed by
+// the deletions of the tasks that disappeared.
+func ChangeEvents(before, after Tasks, now time.Time) []Event {
+	old := make(map[string]*Task, len(before))
+	for i := range before {
+		old[before[i].ID] = &before[i]
+	}
+	var events []Event
+	for _, t := range after {
+		prev, existed := old[t.ID]
+		delete(old, t.ID)
+		wasActive := existed && prev.DeletedAt.IsZero()
+		switch {
+		case !t.DeletedAt.IsZero():
+			if wasActive {
+				events = append(events, Event{Type: EventTaskDeleted, Task: Task{ID: t.ID}, Time: now})
+			}
+		case !wasActive:
+			events = append(events, Event{Type: EventTaskCreated, Task: t, Time: now})
+			if t.Assignee != "" {
+				events = append(events, Event{Type: EventTaskAssigned, Task: t, Time: now})
+			}
+		case !t.SameContent(prev) || t.Version != prev.Version:
+			events = append(events, Event{Type: EventTaskUpdated, Task: t, Time: now})
+			if t.Assignee != "" && t.Assignee != prev.Assignee {
+				events = append(events, Event{Type: EventTaskAssigned, Task: t, Time: now})
+			}
+			if !t.CompletedAt.IsZero() && prev.CompletedAt.IsZero() {
+				events = append(events, Event{Type: EventTaskCompleted, Task: t, Time: now})
+			}
+		}
+	}
+	for _, t := range before {
+		if _, gone := old[t.ID]; gone && t.DeletedAt.IsZero() {
+			events = append(events, Event{Type: EventTaskDeleted, Task: Task{ID: t.ID}, Time: now})
+		}
+	}
+	return events
+}
+
+// publishingRepository is a [TaskRepository] that publishes an [Event] for
+// each successful modification of the underlying repository.
+type publishingRepository struct {
+	TaskRepository
+	bus *EventBus
+}
+
+// NewPublishingRepository wraps the specified repository, so that all
+// modifications are published as [Event]s on the specified bus.
+func NewPublishingRepository(tasks TaskRepository, bus *EventBus) TaskRepository {
+	return &publishingRepository{
+		TaskRepository: tasks,
+		bus:            bus,
+	}
+}
+
+// publish publishes the specified event, logging it with the logger of the
+// request that caused it.
+func (r *publishingRepository) publish(ctx context.Context, e Event) {
+	r.bus.Publish(e)
+	logging.FromContext(ctx).DebugContext(ctx, "published task event", "type", e.Type, "task", e.Task.ID)
+}
+
+func (r *publishingRepository) Create(ctx context.Context, task *TaskCreate) (*Task, error) {
+	created, err := r.TaskRepository.Create(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	r.publish(ctx, Event{Type: EventTaskCreated, Task: *created, Time: now})
+	if created.Assignee != "" {
+		r.publish(ctx, Event{Type: EventTaskAssigned, Task: *created, Time: now})
+	}
+	return created, nil
+}
+
+func (r *publishingRepository) CreateAll(ctx context.Context, tasks []*TaskCreate) (Tasks, error) {
+	batch, ok := r.TaskRepository.(BatchRepository)
+	if !ok {
+		return nil, ErrBatchUnsupported
+	}
+	created, err := batch.CreateAll(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, t := range created {
+		r.publish(ctx, Event{Type: EventTaskCreated, Task: t, Time: now})
+		if t.Assignee != "" {
+			r.publish(ctx, Event{Type: EventTaskAssigned, Task: t, Time: now})
+		}
+	}
+	return created, nil
+}
+
+func (r *publishingRepository) ApplyBatch(ctx context.Context, ops []BatchOperation) (Tasks, error) {
+	batch, ok := r.TaskRepository.(BatchRepository)
+	if !ok {
+		return nil, ErrBatchUnsupported
+	}
+	results, err := batch.ApplyBatch(ctx, ops)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i, t := range results {
+		switch op := &ops[i]; {
+		case op.Create != nil:
+			r.publish(ctx, Event{Type: EventTaskCreated, Task: t, Time: now})
+			if t.Assignee != "" {
+				r.publish(ctx, Event{Type: EventTaskAssigned, Task: t, Time: now})
+			}
+		case op.Delete:
+			r.publish(ctx, Event{Type: EventTaskDeleted, Task: Task{ID: t.ID}, Time: now})
+		default:
+			r.publish(ctx, Event{Type: EventTaskUpdated, Task: t, Time: now})
+			if op.Update.Assignee != nil && *op.Update.Assignee != "" {
+				r.publish(ctx, Event{Type: EventTaskAssigned, Task: t, Time: now})
+			}
+			if op.Update.CompletedAt != nil && !op.Update.CompletedAt.IsZero() {
+				r.publish(ctx, Event{Type: EventTaskCompleted, Task: t, Time: now})
+			}
+		}
+	}
+	return results, nil
+}
+
+func (r *publishingRepository) Update(ctx context.Context, id string, update *TaskUpdate) (*Task, error) {
+	updated, err := r.TaskRepository.Update(ctx, id, update)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	r.publish(ctx, Event{Type: EventTaskUpdated, Task: *updated, Time: now})
+	if update.Assignee != nil && *update.Assignee != "" {
+		r.publish(ctx, Event{Type: EventTaskAssigned, Task: *updated, Time: now})
+	}
+	if update.CompletedAt != nil && !update.CompletedAt.IsZero() {
+		r.publish(ctx, Event{Type: EventTaskCompleted, Task: *updated, Time: now})
+	}
+	return updated, nil
+}
+
+func (r *publishingRepository) Move(ctx context.Context, id string, move *TaskMove) (*Task, error) {
+	moved, err := r.TaskRepository.Move(ctx, id, move)
+	if err != nil {
+		return nil, err
+	}
+	r.publish(ctx, Event{Type: EventTaskUpdated, Task: *moved, Time: time.Now()})
+	return moved, nil
+}
+
+func (r *publishingRepository) Delete(ctx context.Context, id string) error {
+	if err := r.TaskRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.publish(ctx, Event{Type: EventTaskDeleted, Task: Task{ID: id}, Time: time.Now()})
+	return nil
+}
+
+func (r *publishingRepository) Restore(ctx context.Context, id string) (*Task, error) {
+	restored, err := r.TaskRepository.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// The task reappears in the to-do list, like a new one.
+	r.publish(ctx, Event{Type: EventTaskCreated, Task: *restored, Time: time.Now()})
+	return restored, nil
+}
+
+// Replace publishes the changes between the tasks before and after replacing
+// them, see [ChangeEvents], e.g. when a backup is restored.
+func (r *publishingRepository) Replace(ctx context.Context, tasks Tasks) error {
+	opts := &ListOptions{IncludeDeleted: true, SortBy: SortByPosition}
+	before, err := r.TaskRepository.List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if err := r.TaskRepository.Replace(ctx, tasks); err != nil {
+		return err
+	}
+	after, err := r.TaskRepository.List(ctx, opts)
+	if err != nil {
+		// The tasks have been replaced anyway, so only the events are lost.
+		logging.FromContext(ctx).WarnContext(ctx, "cannot publish changes of replaced tasks", "cause", err)
+		return nil
+	}
+	for _, e := range ChangeEvents(before, after, time.Now()) {
+		r.publish(ctx, e)
+	}
+	return nil
+}
+
+func (r *publishingRepository) Changes(ctx context.Context, since time.Time) (Tasks, time.Time, error) {
+	tasks, ok := r.TaskRepository.(SyncRepository)
+	if !ok {
+		return nil, time.Time{}, ErrSyncUnsupported
+	}
+	return tasks.Changes(ctx, since)
+}
+
+func (r *publishingRepository) Merge(ctx context.Context, changes Tasks, since time.Time) (*MergeResult, error) {
+	tasks, ok := r.TaskRepository.(SyncRepository)
+	if !ok {
+		return nil, ErrSyncUnsupported
+	}
+	result, err := tasks.Merge(ctx, changes, since)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range result.Applied {
+		if e.Type == EventTaskDeleted {
+			e.Task = Task{ID: e.Task.ID}
+		}
+		r.publish(ctx, e)
+	}
+	return result, nil
+}
+
+// logger returns the logger of the messages about the event bus.
+func logger() *slog.Logger {
+	return logging.Component(logging.ComponentServer)
+}
@@ -0,0 +1,208 @@
+package todo_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+func publish(bus *todo.EventBus, ids ...string) {
+	for _, id := range ids {
+		bus.Publish(todo.Event{Type: todo.EventTaskCreated, Task: todo.Task{ID: id}})
+	}
+}
+
+// receive returns the IDs of the tasks of the events buffered by the channel.
+func receive(events <-chan todo.Event) []string {
+	var ids []string
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return ids
+			}
+			ids = append(ids, e.Task.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func TestEventBusSequence(t *testing.T) {
+	bus := todo.NewEventBus()
+	events, unsubscribe := bus.Subscribe(8)
+	defer unsubscribe()
+	publish(bus, "1", "2", "3")
+	for want := uint64(1); want <= 3; want++ {
+		if e := <-events; e.Seq != want {
+			t.Errorf("want sequence number: %d; got: %d", want, e.Seq)
+		}
+	}
+}
+
+func TestEventBusSubscribeAfter(t *testing.T) {
+	tests := []struct {
+		name         string
+		seq          uint64
+		wantComplete bool
+		want         []string
+	}{
+		{"Missed", 1, true, []string{"2", "3", "4"}},
+		{"UpToDate", 3, true, []string{"4"}},
+		{"FromStart", 0, true, []string{"1", "2", "3", "4"}},
+		{"Future", 10, false, []string{"4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := todo.NewEventBus()
+			publish(bus, "1", "2", "3")
+			events, unsubscribe, complete := bus.SubscribeAfter(tt.seq, 8)
+			defer unsubscribe()
+			publish(bus, "4")
+			if complete != tt.wantComplete {
+				t.Errorf("want complete: %t; got: %t", tt.wantComplete, complete)
+			}
+			got := receive(events)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("want events: %v; got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEventBusSubscribeAfterExpired(t *testing.T) {
+	bus := todo.NewEventBus()
+	for range 1000 {
+		publish(bus, "old")
+	}
+	events, unsubscribe, complete := bus.SubscribeAfter(1, 8)
+	defer unsubscribe()
+	if complete {
+		t.Error("want expired events to be reported")
+	}
+	if got := receive(events); len(got) != 0 {
+		t.Errorf("want no replayed events; got: %d", len(got))
+	}
+}
+
+func TestEventBusOverflow(t *testing.T) {
+	bus := todo.NewEventBus()
+	oldest, unsubscribeOldest := bus.Subscribe(2)
+	defer unsubscribeOldest()
+	disconnected, unsubscribeDisconnected := bus.Subscribe(2, todo.WithOverflowPolicy(todo.OverflowDisconnect))
+	publish(bus, "1", "2", "3", "4")
+
+	if got, want := receive(oldest), []string{"3", "4"}; !slices.Equal(got, want) {
+		t.Errorf("want most recent events: %v; got: %v", want, got)
+	}
+	if got, want := receive(disconnected), []string{"1", "2"}; !slices.Equal(got, want) {
+		t.Errorf("want events before disconnecting: %v; got: %v", want, got)
+	}
+	if _, ok := <-disconnected; ok {
+		t.Error("want channel of disconnected subscriber to be closed")
+	}
+	unsubscribeDisconnected()
+	want := todo.EventBusStats{Subscribers: 1, Dropped: 2, Disconnected: 1}
+	if got := bus.Stats(); got != want {
+		t.Errorf("want stats: %+v; got: %+v", want, got)
+	}
+}
+
+func TestPublishingRepositoryAssigned(t *testing.T) {
+	ctx := context.Background()
+	bus := todo.NewEventBus()
+	repo := todo.NewPublishingRepository(todo.NewInMemoryTaskDB(), bus)
+	events, unsubscribe := bus.Subscribe(16)
+	defer unsubscribe()
+
+	a, err := repo.Create(ctx, &todo.TaskCreate{Summary: "a", Assignee: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := repo.Create(ctx, &todo.TaskCreate{Summary: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, nobody, now := "bob", "", time.Now()
+	for _, u := range []struct {
+		id     string
+		update *todo.TaskUpdate
+	}{
+		{b.ID, &todo.TaskUpdate{Assignee: &bob}},
+		{a.ID, &todo.TaskUpdate{Assignee: &nobody}},
+		{b.ID, &todo.TaskUpdate{CompletedAt: &now}},
+	} {
+		if _, err := repo.Update(ctx, u.id, u.update); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	for len(events) > 0 {
+		e := <-events
+		got = append(got, string(e.Type)+" "+e.Task.Summary+" "+e.Task.Assignee)
+	}
+	want := []string{
+		"task.created a alice",
+		"task.assigned a alice",
+		"task.created b ",
+		"task.updated b bob",
+		"task.assigned b bob",
+		"task.updated a ",
+		"task.updated b bob",
+		"task.completed b bob",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("want events:\n%q\ngot:\n%q", want, got)
+	}
+}
+
+func TestPublishingRepositoryReplace(t *testing.T) {
+	ctx := context.Background()
+	bus := todo.NewEventBus()
+	repo := todo.NewPublishingRepository(todo.NewInMemoryTaskDB(), bus)
+	for _, summary := range []string{"a", "b", "c", "d"} {
+		if _, err := repo.Create(ctx, &todo.TaskCreate{Summary: summary}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.Delete(ctx, "3"); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := repo.List(ctx, &todo.ListOptions{IncludeDeleted: true, SortBy: todo.SortByPosition})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, unsubscribe := bus.Subscribe(16)
+	defer unsubscribe()
+
+	// Like a backup taken before task c was deleted and after task a was
+	// edited, completed, and task b moved to the trash, without task d.
+	now := time.Now()
+	tasks[0].Summary, tasks[0].CompletedAt, tasks[0].Assignee = "A", now, "alice"
+	tasks[1].DeletedAt = now
+	tasks[2].DeletedAt = time.Time{}
+	if err := repo.Replace(ctx, tasks[:3]); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for len(events) > 0 {
+		e := <-events
+		got = append(got, string(e.Type)+" "+e.Task.ID+" "+e.Task.Summary)
+	}
+	want := []string{
+		"task.updated 1 A",
+		"task.assigned 1 A",
+		"task.completed 1 A",
+		"task.deleted 2 ",
+		"task.created 3 c",
+		"task.deleted 4 ",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("want events:\n%q\ngot:\n%q", want, got)
+	}
+}
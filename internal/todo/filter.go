@@ -0,0 +1,401 @@
+package todo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/config"
+)
+
+// ErrFilterConfigured is returned by [FilterRegistry.Remove] for the filters
+// defined in the configuration file.
+var ErrFilterConfigured = errors.New("the filter is defined in the configuration file")
+
+// DueWindow selects tasks by their due time, relative to the time when the
+// tasks are listed.
+type DueWindow string
+
+// The due windows that tasks can be selected by.
+const (
+	// DueAny selects tasks with any due time, or none.
+	DueAny DueWindow = ""
+	// DueToday selects the tasks due before the end of the day.
+	DueToday DueWindow = "today"
+	// DueWeek selects the tasks due before the end of the sixth day after
+	// today.
+	DueWeek DueWindow = "week"
+	// DueOverdue selects the tasks that are overdue, see [Task.IsOverdue].
+	DueOverdue DueWindow = "overdue"
+)
+
+// Filter is a named set of criteria for selecting tasks, also known as a smart
+// list. The criteria are the same as those of [ListOptions], but the due time
+// is relative to the time when the tasks are listed.
+type Filter struct {
+	// Name is the unique name of the filter, e.g. "urgent-work".
+	Name string `json:"name"`
+	// Completion selects tasks by their completion state.
+	Completion Completion `json:"completion,omitempty"`
+	// Tags, if non-empty, selects only tasks that have all of these tags.
+	Tags []string `json:"tags,omitempty"`
+	// Project, if non-empty, selects only tasks of this project.
+	Project string `json:"project,omitempty"`
+	// Due selects tasks by their due time.
+	Due DueWindow `json:"due,omitempty"`
+	// Starred selects only tasks that are starred.
+	Starred bool `json:"starred,omitempty"`
+	// Configured specifies whether the filter is defined in the
+	// configuration file rather than saved via the API.
+	Configured bool `json:"-"`
+}
+
+// Validate checks if the filter has a valid name and criteria.
+func (f *Filter) Validate() error {
+	v := validator{subject: "filter"}
+	v.name(f.Name)
+	if f.Completion < CompletionAny || f.Completion > CompletionCompleted {
+		v.addf("completion", "invalid completion state %d", f.Completion)
+	}
+	v.tags(f.Tags)
+	v.project(f.Project)
+	switch f.Due {
+	case DueAny, DueToday, DueWeek, DueOverdue:
+	default:
+		v.addf("due", "must be 'today', 'week', or 'overdue', got '%s'", f.Due)
+	}
+	return v.err()
+}
+
+// Apply adds the criteria of the filter to the specified list options at the
+// specified time, whose location is the time zone that days start in. The
+// tags are combined with the tags of the options; the other criteria apply
+// unless the options already set them.
+func (f *Filter) Apply(opts *ListOptions, now time.Time) {
+	for _, tag := range f.Tags {
+		if !slices.Contains(opts.Tags, tag) {
+			opts.Tags = append(opts.Tags, tag)
+		}
+	}
+	if opts.Completion == CompletionAny {
+		opts.Completion = f.Completion
+	}
+	if opts.Project == "" {
+		opts.Project = f.Project
+	}
+	opts.Starred = opts.Starred || f.Starred
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var dueBefore time.Time
+	switch f.Due {
+	case DueToday:
+		dueBefore = startOfDay.AddDate(0, 0, 1)
+	case DueWeek:
+		dueBefore = startOfDay.AddDate(0, 0, 7)
+	case DueOverdue:
+		opts.Overdue = true
+	}
+	if opts.DueBefore.IsZero() {
+		opts.DueBefore = dueBefore
+	}
+}
+
+// NewFilters converts the named filters of the configuration file.
+func NewFilters(filters []config.Filter) ([]Filter, error) {
+	result := make([]Filter, len(filters))
+	for i, f := range filters {
+		result[i] = Filter{Name: f.Name, Tags: f.Tags, Project: f.Project, Due: DueWindow(f.Due), Starred: f.Starred}
+		switch f.Status {
+		case "":
+		case "open":
+			result[i].Completion = CompletionOpen
+		case "completed":
+			result[i].Completion = CompletionCompleted
+		default:
+			return nil, fmt.Errorf("invalid status of filter '%s': want 'open' or 'completed', got '%s'",
+				f.Name, f.Status)
+		}
+	}
+	return result, nil
+}
+
+// NewFilterFromProto converts the protobuf representation of a filter.
+func NewFilterFromProto(p *todopb.Filter) *Filter {
+	f := &Filter{
+		Name:    p.GetName(),
+		Tags:    p.GetTags(),
+		Project: p.GetProject(),
+		Starred: p.GetStarred(),
+	}
+	switch p.GetCompletion() {
+	case todopb.ListTasksRequest_COMPLETION_OPEN:
+		f.Completion = CompletionOpen
+	case todopb.ListTasksRequest_COMPLETION_COMPLETED:
+		f.Completion = CompletionCompleted
+	}
+	switch p.GetDue() {
+	case todopb.Filter_DUE_TODAY:
+		f.Due = DueToday
+	case todopb.Filter_DUE_WEEK:
+		f.Due = DueWeek
+	case todopb.Filter_DUE_OVERDUE:
+		f.Due = DueOverdue
+	}
+	return f
+}
+
+func (f *Filter) toProto() *todopb.Filter {
+	p := &todopb.Filter{
+		Name:       f.Name,
+		Tags:       f.Tags,
+		Project:    f.Project,
+		Starred:    f.Starred,
+		Configured: f.Configured,
+	}
+	switch f.Completion {
+	case CompletionOpen:
+		p.Completion = todopb.ListTasksRequest_COMPLETION_OPEN
+	case CompletionCompleted:
+		p.Completion = todopb.ListTasksRequest_COMPLETION_COMPLETED
+	}
+	switch f.Due {
+	case DueToday:
+		p.Due = todopb.Filter_DUE_TODAY
+	case DueWeek:
+		p.Due = todopb.Filter_DUE_WEEK
+	case DueOverdue:
+		p.Due = todopb.Filter_DUE_OVERDUE
+	}
+	return p
+}
+
+// FilterNotFoundError is returned by the [FilterRegistry] when there is no
+// filter with the specified name.
+type FilterNotFoundError struct {
+	// Name is the name of the filter that was not found.
+	Name string
+}
+
+// NewFilterNotFoundError creates a [FilterNotFoundError] for the filter with
+// the specified name.
+func NewFilterNotFoundError(name string) *FilterNotFoundError {
+	return &FilterNotFoundError{Name: name}
+}
+
+// IsFilterNotFoundError checks if the provided error is a
+// [FilterNotFoundError].
+func IsFilterNotFoundError(err error) bool {
+	var e *FilterNotFoundError
+	return err != nil && errors.As(err, &e)
+}
+
+func (e *FilterNotFoundError) Error() string {
+	return fmt.Sprintf("no such filter: '%s'", e.Name)
+}
+
+// FilterExistsError is returned by [FilterRegistry.Add] when there is already
+// a filter with the same name.
+type FilterExistsError struct {
+	// Name is the name of the existing filter.
+	Name string
+}
+
+// NewFilterExistsError creates a [FilterExistsError] for the filter with the
+// specified name.
+func NewFilterExistsError(name string) *FilterExistsError {
+	return &FilterExistsError{Name: name}
+}
+
+// IsFilterExistsError checks if the provided error is a [FilterExistsError].
+func IsFilterExistsError(err error) bool {
+	var e *FilterExistsError
+	return err != nil && errors.As(err, &e)
+}
+
+func (e *FilterExistsError) Error() string {
+	return fmt.Sprintf("filter '%s' already exists", e.Name)
+}
+
+// FilterRegistry holds the named filters, both those defined in the
+// configuration file and those saved via the API. The saved filters are kept
+// in a JSON file, so they survive restarts of the server. A configured filter
+// takes precedence over a saved filter with the same name.
+type FilterRegistry struct {
+	mu         sync.Mutex
+	path       string
+	configured map[string]Filter
+	saved      map[string]Filter
+}
+
+// NewFilterRegistry creates a filter registry that keeps the saved filters in
+// the file at the specified path, and loads the filters saved in it before.
+// If the path is empty, the saved filters are kept in memory only.
+func NewFilterRegistry(path string) (*FilterRegistry, error) {
+	r := &FilterRegistry{path: path, configured: map[string]Filter{}, saved: map[string]Filter{}}
+	if path == "" {
+		return r, nil
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read filters: %w", err)
+	}
+	var filters []Filter
+	if err := json.Unmarshal(b, &filters); err != nil {
+		return nil, fmt.Errorf("invalid filters in %s: %w", path, err)
+	}
+	for _, f := range filters {
+		if err := f.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid filters in %s: %w", path, err)
+		}
+		r.saved[f.Name] = f
+	}
+	return r, nil
+}
+
+// ValidateFilters checks if the specified filters are valid and have unique
+// names.
+func ValidateFilters(filters []Filter) error {
+	names := make(map[string]bool, len(filters))
+	for _, f := range filters {
+		if err := f.Validate(); err != nil {
+			return err
+		}
+		if names[f.Name] {
+			return fmt.Errorf("duplicate filter: '%s'", f.Name)
+		}
+		names[f.Name] = true
+	}
+	return nil
+}
+
+// SetConfigured replaces the filters defined in the configuration file with
+// the specified ones. If any of them is invalid, nothing is changed.
+func (r *FilterRegistry) SetConfigured(filters []Filter) error {
+	if err := ValidateFilters(filters); err != nil {
+		return err
+	}
+	configured := make(map[string]Filter, len(filters))
+	for _, f := range filters {
+		f.Configured = true
+		configured[f.Name] = f
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configured = configured
+	return nil
+}
+
+// Get returns the filter with the specified name.
+func (r *FilterRegistry) Get(name string) (*Filter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.configured[name]; ok {
+		return &f, nil
+	}
+	if f, ok := r.saved[name]; ok {
+		return &f, nil
+	}
+	return nil, NewFilterNotFoundError(name)
+}
+
+// List returns all filters ordered by name.
+func (r *FilterRegistry) List() []Filter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	filters := make([]Filter, 0, len(r.configured)+len(r.saved))
+	for _, f := range r.configured {
+		filters = append(filters, f)
+	}
+	for name, f := range r.saved {
+		if _, ok := r.configured[name]; !ok {
+			filters = append(filters, f)
+		}
+	}
+	slices.SortFunc(filters, func(a, b Filter) int { return strings.Compare(a.Name, b.Name) })
+	return filters
+}
+
+// Add saves the specified filter.
+func (r *FilterRegistry) Add(f *Filter) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, configured := r.configured[f.Name]
+	_, saved := r.saved[f.Name]
+	if configured || saved {
+		return NewFilterExistsError(f.Name)
+	}
+	added := *f
+	added.Configured = false
+	r.saved[f.Name] = added
+	if err := r.write(); err != nil {
+		delete(r.saved, f.Name)
+		return err
+	}
+	return nil
+}
+
+// Remove deletes the saved filter with the specified name.
+func (r *FilterRegistry) Remove(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.configured[name]; ok {
+		return fmt.Errorf("cannot delete filter '%s': %w", name, ErrFilterConfigured)
+	}
+	f, ok := r.saved[name]
+	if !ok {
+		return NewFilterNotFoundError(name)
+	}
+	delete(r.saved, name)
+	if err := r.write(); err != nil {
+		r.saved[name] = f
+		return err
+	}
+	return nil
+}
+
+// write writes the saved filters to the file of the registry, if any. The
+// caller must hold the lock.
+func (r *FilterRegistry) write() error {
+	if r.path == "" {
+		return nil
+	}
+	filters := make([]Filter, 0, len(r.saved))
+	for _, f := range r.saved {
+		filters = append(filters, f)
+	}
+	slices.SortFunc(filters, func(a, b Filter) int { return strings.Compare(a.Name, b.Name) })
+	if err := writeJSONFile(r.path, filters); err != nil {
+		return fmt.Errorf("cannot save filters: %w", err)
+	}
+	return nil
+}
+
+// writeJSONFile writes the JSON representation of the specified value to the
+// file at the specified path, replacing the file atomically.
+func writeJSONFile(path string, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,143 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+)
+
+func TestFilterValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"Valid", Filter{Name: "urgent-work", Tags: []string{"work"}, Due: DueWeek, Completion: CompletionOpen}, nil},
+		{"EmptyName", Filter{}, []string{"name"}},
+		{"InvalidName", Filter{Name: "-urgent work"}, []string{"name"}},
+		{"InvalidTag", Filter{Name: "a", Tags: []string{"with space"}}, []string{"tags[0]"}},
+		{"InvalidDue", Filter{Name: "a", Due: "tomorrow"}, []string{"due"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := violatedFields(t, tt.filter.Validate()); !slices.Equal(got, tt.want) {
+				t.Errorf("want violated fields %v; got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFilterApply(t *testing.T) {
+	now := time.Date(2025, 3, 12, 15, 30, 0, 0, time.UTC)
+	f := Filter{Name: "urgent-work", Tags: []string{"work"}, Project: "acme", Due: DueWeek, Starred: true}
+	opts := &ListOptions{Tags: []string{"urgent"}, Completion: CompletionOpen}
+	f.Apply(opts, now)
+	if want := []string{"urgent", "work"}; !slices.Equal(opts.Tags, want) {
+		t.Errorf("want tags %v; got: %v", want, opts.Tags)
+	}
+	if opts.Project != "acme" || !opts.Starred || opts.Completion != CompletionOpen {
+		t.Errorf("want criteria of filter and options combined; got: %+v", opts)
+	}
+	if want := time.Date(2025, 3, 19, 0, 0, 0, 0, time.UTC); !opts.DueBefore.Equal(want) {
+		t.Errorf("want due before %v; got: %v", want, opts.DueBefore)
+	}
+
+	opts = &ListOptions{Project: "other"}
+	(&Filter{Name: "overdue", Project: "acme", Due: DueOverdue}).Apply(opts, now)
+	if opts.Project != "other" || !opts.Overdue || !opts.DueBefore.IsZero() {
+		t.Errorf("want project of options kept and overdue tasks selected; got: %+v", opts)
+	}
+}
+
+func TestFilterRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "filters.json")
+	r, err := NewFilterRegistry(path)
+	if err != nil {
+		t.Fatalf("cannot create registry: %v", err)
+	}
+	if err := r.Add(&Filter{Name: "work", Tags: []string{"work"}}); err != nil {
+		t.Fatalf("cannot add filter: %v", err)
+	}
+	if err := r.Add(&Filter{Name: "today", Due: DueToday}); err != nil {
+		t.Fatalf("cannot add filter: %v", err)
+	}
+	if err := r.Add(&Filter{Name: "work"}); !IsFilterExistsError(err) {
+		t.Errorf("want FilterExistsError; got: %v", err)
+	}
+	if err := r.SetConfigured([]Filter{{Name: "today", Due: DueOverdue}}); err != nil {
+		t.Fatalf("cannot set configured filters: %v", err)
+	}
+	if f, err := r.Get("today"); err != nil || f.Due != DueOverdue || !f.Configured {
+		t.Errorf("want configured filter to take precedence; got: %+v, %v", f, err)
+	}
+	if got := r.List(); len(got) != 2 || got[0].Name != "today" || got[1].Name != "work" {
+		t.Errorf("want filters today and work; got: %+v", got)
+	}
+	if err := r.Remove("today"); !errors.Is(err, ErrFilterConfigured) {
+		t.Errorf("want ErrFilterConfigured; got: %v", err)
+	}
+	if err := r.Remove("work"); err != nil {
+		t.Fatalf("cannot remove filter: %v", err)
+	}
+	if err := r.Remove("work"); !IsFilterNotFoundError(err) {
+		t.Errorf("want FilterNotFoundError; got: %v", err)
+	}
+
+	// The saved filters survive restarts, the configured ones don't.
+	r, err = NewFilterRegistry(path)
+	if err != nil {
+		t.Fatalf("cannot reopen registry: %v", err)
+	}
+	if got := r.List(); len(got) != 1 || got[0].Name != "today" || got[0].Due != DueToday || got[0].Configured {
+		t.Errorf("want only saved filter today; got: %+v", got)
+	}
+}
+
+func TestListTasksWithFilter(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryTaskDB()
+	for _, task := range []TaskCreate{
+		{Summary: "a", Tags: []string{"work"}},
+		{Summary: "b", Tags: []string{"home"}},
+		{Summary: "c", Tags: []string{"work"}, Starred: true},
+	} {
+		if _, err := db.Create(ctx, &task); err != nil {
+			t.Fatalf("cannot create task: %v", err)
+		}
+	}
+	r, err := NewFilterRegistry("")
+	if err != nil {
+		t.Fatalf("cannot create registry: %v", err)
+	}
+	ctrl := NewController(nil, nil, db, NewEventBus(), WithFilters(r))
+	filter := &todopb.Filter{Name: "work", Tags: []string{"work"}}
+	if _, err := ctrl.CreateFilter(ctx, &todopb.CreateFilterRequest{Filter: filter}); err != nil {
+		t.Fatalf("cannot create filter: %v", err)
+	}
+
+	resp, err := ctrl.ListTasks(ctx, &todopb.ListTasksRequest{Filter: "work"})
+	if err != nil {
+		t.Fatalf("cannot list tasks: %v", err)
+	}
+	if got := len(resp.GetTasks()); got != 2 {
+		t.Errorf("want 2 tasks tagged work; got: %d", got)
+	}
+	resp, err = ctrl.ListTasks(ctx, &todopb.ListTasksRequest{Filter: "work", Starred: true})
+	if err != nil {
+		t.Fatalf("cannot list tasks: %v", err)
+	}
+	if got := resp.GetTasks(); len(got) != 1 || got[0].GetSummary() != "c" {
+		t.Errorf("want only starred task c; got: %v", got)
+	}
+	if _, err := ctrl.ListTasks(ctx, &todopb.ListTasksRequest{Filter: "nope"}); status.Code(err) != codes.NotFound {
+		t.Errorf("want NotFound for unknown filter; got: %v", err)
+	}
+}
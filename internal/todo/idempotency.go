@@ -0,0 +1,182 @@
+package todo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// IdempotencyKeyMetadataKey is the incoming gRPC metadata key that holds the
+// idempotency key of a request to create a task, if the request message has
+// none. The gateway forwards the Idempotency-Key header of REST requests under
+// this key.
+const IdempotencyKeyMetadataKey = "idempotency-key"
+
+// MaxIdempotencyKeyLength is the maximum length of an idempotency key.
+const MaxIdempotencyKeyLength = 255
+
+// maxIdempotencyKeys is the maximum number of keys that an
+// [IdempotencyCache] remembers. Once it is reached, the oldest keys are
+// forgotten first, even before their window has passed.
+const maxIdempotencyKeys = 10000
+
+// ErrIdempotencyKeyReused is returned by [IdempotencyCache.Do] if the key was
+// used before for a request to create a different task.
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different task")
+
+// IdempotencyCache remembers the tasks created by requests with idempotency
+// keys for a limited time, so that a retried request returns the task created
+// by the original request instead of creating the task again.
+type IdempotencyCache struct {
+	window time.Duration
+	// now returns the current time; it is replaced in tests.
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*idempotencyEntry
+}
+
+// idempotencyEntry is the state of a request with an idempotency key.
+type idempotencyEntry struct {
+	// fingerprint identifies the task to be created by the request.
+	fingerprint [sha256.Size]byte
+	// done is closed once the request has finished.
+	done chan struct{}
+	// task is the created task, which is only set once done is closed and if
+	// the request succeeded.
+	task *Task
+	// expires is the time when the key is forgotten, which is only set once
+	// done is closed.
+	expires time.Time
+}
+
+// NewIdempotencyCache creates an [IdempotencyCache] that remembers each key
+// for the specified window after the task was created.
+func NewIdempotencyCache(window time.Duration) *IdempotencyCache {
+	return &IdempotencyCache{
+		window:  window,
+		now:     time.Now,
+		entries: make(map[string]*idempotencyEntry),
+	}
+}
+
+// Do creates the specified task with the specified function, unless a request
+// with the same key has already created it, in which case it returns the task
+// created back then. If a request with the same key is still in progress, it
+// waits for that request. If the function fails, the key is not remembered, so
+// that the request can be retried. If the key was used for a different task,
+// it returns [ErrIdempotencyKeyReused].
+func (c *IdempotencyCache) Do(
+	ctx context.Context,
+	key string,
+	task *TaskCreate,
+	create func() (*Task, error),
+) (*Task, error) {
+	fingerprint, err := taskFingerprint(task)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		c.mu.Lock()
+		c.expire()
+		e, ok := c.entries[key]
+		if !ok {
+			e = &idempotencyEntry{fingerprint: fingerprint, done: make(chan struct{})}
+			c.entries[key] = e
+			c.mu.Unlock()
+			return c.run(key, e, create)
+		}
+		c.mu.Unlock()
+		if e.fingerprint != fingerprint {
+			return nil, ErrIdempotencyKeyReused
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-e.done:
+		}
+		if e.task != nil {
+			t := *e.task
+			return &t, nil
+		}
+		// The request failed and its key was forgotten, so try again.
+	}
+}
+
+// run creates the task of the specified entry and remembers it, or forgets
+// the entry if the task cannot be created.
+func (c *IdempotencyCache) run(key string, e *idempotencyEntry, create func() (*Task, error)) (*Task, error) {
+	task, err := create()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer close(e.done)
+	if err != nil {
+		delete(c.entries, key)
+		return nil, err
+	}
+	t := *task
+	e.task = &t
+	e.expires = c.now().Add(c.window)
+	return task, nil
+}
+
+// expire forgets the keys whose window has passed and, if there are too many
+// keys, the keys that expire first. The caller must hold the lock.
+func (c *IdempotencyCache) expire() {
+	now := c.now()
+	var oldest string
+	for key, e := range c.entries {
+		if e.task == nil {
+			continue
+		}
+		if !now.Before(e.expires) {
+			delete(c.entries, key)
+		} else if oldest == "" || e.expires.Before(c.entries[oldest].expires) {
+			oldest = key
+		}
+	}
+	if len(c.entries) >= maxIdempotencyKeys && oldest != "" {
+		delete(c.entries, oldest)
+	}
+}
+
+// taskFingerprint returns a hash of the fields of the specified task, which
+// identifies the task to be created independently of the API version.
+func taskFingerprint(task *TaskCreate) ([sha256.Size]byte, error) {
+	b, err := json.Marshal(task)
+	if err != nil {
+		return [sha256.Size]byte{}, fmt.Errorf("cannot compute task fingerprint: %w", err)
+	}
+	return sha256.Sum256(b), nil
+}
+
+// idempotencyKey returns the idempotency key of a request to create a task:
+// the specified key of the request message or, if it is empty, the key from
+// the incoming metadata, e.g. from the Idempotency-Key header of a REST
+// request. If the key is invalid, it returns an INVALID_ARGUMENT status.
+func idempotencyKey(ctx context.Context, key string) (string, error) {
+	if key == "" {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(IdempotencyKeyMetadataKey); len(values) > 0 {
+				key = values[0]
+			}
+		}
+	}
+	if len(key) > MaxIdempotencyKeyLength {
+		return "", status.Errorf(codes.InvalidArgument, "idempotency_key: must be at most %d characters long, got %d",
+			MaxIdempotencyKeyLength, len(key))
+	}
+	if strings.IndexFunc(key, func(r rune) bool { return r < ' ' || r > '~' }) >= 0 {
+		return "", status.Errorf(codes.InvalidArgument, "idempotency_key: must only contain printable ASCII characters")
+	}
+	return key, nil
+}
@@ -0,0 +1,133 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	todov2pb "github.com/mwopitz/todo-daemon/api/todo/v2"
+)
+
+func TestIdempotencyCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewIdempotencyCache(time.Hour)
+	now := time.Date(2025, 12, 24, 18, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	db := NewInMemoryTaskDB()
+	create := func(task *TaskCreate) func() (*Task, error) {
+		return func() (*Task, error) { return db.Create(ctx, task) }
+	}
+	milk := &TaskCreate{Summary: "Buy milk"}
+
+	first, err := cache.Do(ctx, "a", milk, create(milk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	retried, err := cache.Do(ctx, "a", &TaskCreate{Summary: "Buy milk"}, create(milk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retried.ID != first.ID {
+		t.Errorf("want task %s for retried request; got: %s", first.ID, retried.ID)
+	}
+	bread := &TaskCreate{Summary: "Buy bread"}
+	if _, err := cache.Do(ctx, "a", bread, create(bread)); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("want ErrIdempotencyKeyReused; got: %v", err)
+	}
+
+	failed := errors.New("boom")
+	if _, err := cache.Do(ctx, "b", bread, func() (*Task, error) { return nil, failed }); !errors.Is(err, failed) {
+		t.Errorf("want error of failed request; got: %v", err)
+	}
+	if _, err := cache.Do(ctx, "b", bread, create(bread)); err != nil {
+		t.Errorf("want failed request to be retried; got: %v", err)
+	}
+
+	now = now.Add(time.Hour)
+	expired, err := cache.Do(ctx, "a", milk, create(milk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expired.ID == first.ID {
+		t.Errorf("want new task after the window has passed; got: %s", expired.ID)
+	}
+}
+
+func TestIdempotencyCacheConcurrent(t *testing.T) {
+	ctx := context.Background()
+	cache := NewIdempotencyCache(time.Hour)
+	db := NewInMemoryTaskDB()
+	task := &TaskCreate{Summary: "Buy milk"}
+	var wg sync.WaitGroup
+	ids := make([]string, 10)
+	for i := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			created, err := cache.Do(ctx, "a", task, func() (*Task, error) {
+				time.Sleep(10 * time.Millisecond)
+				return db.Create(ctx, task)
+			})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			ids[i] = created.ID
+		}()
+	}
+	wg.Wait()
+	for _, id := range ids {
+		if id != ids[0] {
+			t.Errorf("want all requests to return task %s; got: %v", ids[0], ids)
+			break
+		}
+	}
+	if tasks, err := db.List(ctx, &ListOptions{}); err != nil || len(tasks) != 1 {
+		t.Errorf("want 1 task; got: %d, %v", len(tasks), err)
+	}
+}
+
+func TestCreateTaskIdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryTaskDB()
+	ctrl := NewController(nil, nil, db, NewEventBus(), WithIdempotency(NewIdempotencyCache(time.Hour)))
+	req := &todopb.CreateTaskRequest{Task: &todopb.NewTask{Summary: "Buy milk"}, IdempotencyKey: "a"}
+	first, err := ctrl.CreateTask(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The key from the metadata, e.g. the Idempotency-Key header, is used
+	// if the request has none, and the task is the same in version 2.
+	mdCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(IdempotencyKeyMetadataKey, "a"))
+	v2, err := NewControllerV2(ctrl).CreateTask(mdCtx, &todov2pb.CreateTaskRequest{
+		Task: &todov2pb.Task{Summary: "Buy milk"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v2.GetId() != first.GetTask().GetId() {
+		t.Errorf("want task %s; got: %s", first.GetTask().GetId(), v2.GetId())
+	}
+
+	req.Task.Summary = "Buy bread"
+	if _, err := ctrl.CreateTask(ctx, req); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("want reused key to be rejected; got: %v", err)
+	}
+	for _, key := range []string{strings.Repeat("x", MaxIdempotencyKeyLength+1), "a\nb", "schlüssel"} {
+		req := &todopb.CreateTaskRequest{Task: &todopb.NewTask{Summary: "Buy milk"}, IdempotencyKey: key}
+		if _, err := ctrl.CreateTask(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("want invalid key %q to be rejected; got: %v", key, err)
+		}
+	}
+	if tasks, err := db.List(ctx, &ListOptions{}); err != nil || len(tasks) != 1 {
+		t.Errorf("want 1 task; got: %d, %v", len(tasks), err)
+	}
+}
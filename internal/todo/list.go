@@ -0,0 +1,294 @@
+package todo
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"time"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+)
+
+// Completion selects tasks by their completion state.
+type Completion int
+
+// The completion states that tasks can be selected by.
+const (
+	// CompletionAny selects both open and completed tasks.
+	CompletionAny Completion = iota
+	// CompletionOpen selects the tasks that have not been completed yet.
+	CompletionOpen
+	// CompletionCompleted selects the completed tasks.
+	CompletionCompleted
+)
+
+// SortBy is the field that tasks are sorted by.
+type SortBy int
+
+// The fields that tasks can be sorted by.
+const (
+	// SortByCreated sorts tasks by their creation time. Starred tasks come
+	// first, regardless of the sort direction.
+	SortByCreated SortBy = iota
+	// SortByDue sorts tasks by their due time. Tasks without due time come
+	// last, regardless of the sort direction.
+	SortByDue
+	// SortByUpdated sorts tasks by the time of their last update.
+	SortByUpdated
+	// SortByPosition sorts tasks by their position in the manual order, see
+	// [TaskRepository.Move].
+	SortByPosition
+)
+
+// ListOptions selects, sorts, and paginates the tasks returned by
+// [TaskRepository.List]. The zero value selects all tasks that have not been
+// deleted, ordered by creation time with the starred tasks first.
+type ListOptions struct {
+	// Completion selects tasks by their completion state.
+	Completion Completion
+	// Tags, if non-empty, selects only tasks that have all of these tags.
+	Tags []string
+	// Project, if non-empty, selects only tasks of this project.
+	Project string
+	// DueAfter, if non-zero, selects only tasks that are due at or after this
+	// time.
+	DueAfter time.Time
+	// DueBefore, if non-zero, selects only tasks that are due before this
+	// time.
+	DueBefore time.Time
+	// Overdue selects only tasks that are overdue, see [Task.IsOverdue].
+	Overdue bool
+	// Starred selects only tasks that are starred.
+	Starred bool
+	// Assignee, if non-empty, selects only tasks assigned to this user.
+	Assignee string
+	// IncludeDeleted also selects tasks that have been moved to the trash,
+	// i.e. that have a deletion time. Repositories that delete tasks
+	// permanently don't have such tasks.
+	IncludeDeleted bool
+	// SortBy is the field that the tasks are sorted by.
+	SortBy SortBy
+	// Descending sorts the tasks in descending instead of ascending order.
+	Descending bool
+	// After, if set, selects only tasks that come after the cursor in the
+	// sort order. Unlike Offset, it still selects the right tasks if tasks
+	// before the cursor are added or removed between two pages.
+	After *Cursor
+	// Offset is the number of matching tasks to skip.
+	Offset int
+	// Limit is the maximum number of tasks to return. Zero means no limit.
+	Limit int
+}
+
+func newListOptionsFromProto(req *todopb.ListTasksRequest) *ListOptions {
+	opts := &ListOptions{
+		Tags:       req.GetTags(),
+		Project:    req.GetProject(),
+		DueAfter:   optionalTime(req.GetDueAfter()),
+		DueBefore:  optionalTime(req.GetDueBefore()),
+		Overdue:    req.GetOverdue(),
+		Starred:    req.GetStarred(),
+		Assignee:   req.GetAssignee(),
+		Descending: req.GetDescending(),
+		Offset:     int(req.GetOffset()),
+		Limit:      int(req.GetLimit()),
+	}
+	switch req.GetCompletion() {
+	case todopb.ListTasksRequest_COMPLETION_OPEN:
+		opts.Completion = CompletionOpen
+	case todopb.ListTasksRequest_COMPLETION_COMPLETED:
+		opts.Completion = CompletionCompleted
+	}
+	switch req.GetSortBy() {
+	case todopb.ListTasksRequest_SORT_BY_DUE:
+		opts.SortBy = SortByDue
+	case todopb.ListTasksRequest_SORT_BY_UPDATED:
+		opts.SortBy = SortByUpdated
+	case todopb.ListTasksRequest_SORT_BY_POSITION:
+		opts.SortBy = SortByPosition
+	}
+	return opts
+}
+
+// Matches checks if the specified task is selected by the options at the
+// given time.
+func (o *ListOptions) Matches(t *Task, now time.Time) bool {
+	switch {
+	case !o.IncludeDeleted && !t.DeletedAt.IsZero():
+		return false
+	case o.Completion == CompletionOpen && !t.CompletedAt.IsZero():
+		return false
+	case o.Completion == CompletionCompleted && t.CompletedAt.IsZero():
+		return false
+	case o.Project != "" && t.Project != o.Project:
+		return false
+	case !o.DueAfter.IsZero() && (t.DueAt.IsZero() || t.DueAt.Before(o.DueAfter)):
+		return false
+	case !o.DueBefore.IsZero() && (t.DueAt.IsZero() || !t.DueAt.Before(o.DueBefore)):
+		return false
+	case o.Overdue && !t.IsOverdue(now):
+		return false
+	case o.Starred && !t.Starred:
+		return false
+	case o.Assignee != "" && t.Assignee != o.Assignee:
+		return false
+	}
+	for _, tag := range o.Tags {
+		if !slices.Contains(t.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// Cursor is the position of a task in a sorted list of tasks, which a page of
+// tasks can start after. It holds the fields of the task that tasks are sorted
+// by, including the sequence number that breaks ties.
+type Cursor struct {
+	ID        string    `json:"id"`
+	Seq       uint64    `json:"seq"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+	DueAt     time.Time `json:"due_at,omitzero"`
+	Position  int64     `json:"position,omitempty"`
+	Starred   bool      `json:"starred,omitempty"`
+}
+
+// NewCursor returns the cursor of the specified task.
+func NewCursor(t *Task) *Cursor {
+	return &Cursor{
+		ID:        t.ID,
+		Seq:       t.Seq,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+		DueAt:     t.DueAt,
+		Position:  t.Position,
+		Starred:   t.Starred,
+	}
+}
+
+// task returns a task with the sort fields of the cursor, which tasks can be
+// compared to.
+func (c *Cursor) task() *Task {
+	return &Task{
+		ID:        c.ID,
+		Seq:       c.Seq,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+		DueAt:     c.DueAt,
+		Position:  c.Position,
+		Starred:   c.Starred,
+	}
+}
+
+// after checks if the specified task comes after the cursor of the options,
+// if any.
+func (o *ListOptions) after(t *Task, cursor *Task) bool {
+	return cursor == nil || o.compare(t, cursor) > 0
+}
+
+// cursorTask returns the task with the sort fields of the cursor of the
+// options, or nil if the options have no cursor.
+func (o *ListOptions) cursorTask() *Task {
+	if o.After == nil {
+		return nil
+	}
+	return o.After.task()
+}
+
+// Apply selects, sorts, and paginates the specified tasks according to the
+// options. It modifies the given slice. Repositories without native support
+// for the options can use it to implement [TaskRepository.List].
+func (o *ListOptions) Apply(tasks Tasks, now time.Time) Tasks {
+	cursor := o.cursorTask()
+	tasks = slices.DeleteFunc(tasks, func(t Task) bool {
+		return !o.Matches(&t, now) || !o.after(&t, cursor)
+	})
+	slices.SortStableFunc(tasks, func(a, b Task) int {
+		return o.compare(&a, &b)
+	})
+	if o.Offset > 0 {
+		tasks = tasks[min(o.Offset, len(tasks)):]
+	}
+	if o.Limit > 0 && o.Limit < len(tasks) {
+		tasks = tasks[:o.Limit]
+	}
+	return tasks
+}
+
+// Paginate selects the tasks of the specified sequence, which must already be
+// sorted according to the options, and paginates them. Unlike
+// [ListOptions.Apply], it stops as soon as the page is full, so repositories
+// that keep their tasks in sorted indexes need not copy and sort all tasks to
+// list some of them. It copies the tasks, so the sequence may reuse them.
+func (o *ListOptions) Paginate(sorted iter.Seq[*Task], now time.Time) Tasks {
+	tasks := Tasks{}
+	skip := o.Offset
+	cursor := o.cursorTask()
+	for t := range sorted {
+		switch {
+		case !o.Matches(t, now) || !o.after(t, cursor):
+			continue
+		case skip > 0:
+			skip--
+			continue
+		}
+		tasks = append(tasks, *t)
+		if o.Limit > 0 && len(tasks) == o.Limit {
+			break
+		}
+	}
+	return tasks
+}
+
+// compare compares two tasks by the field to sort by, and by creation time,
+// sequence number, and ID if the field is equal, so the order is
+// deterministic. When sorting by
+// creation time, starred tasks come first in either direction.
+func (o *ListOptions) compare(a, b *Task) int {
+	var c int
+	switch o.SortBy {
+	case SortByCreated:
+		if a.Starred != b.Starred {
+			if a.Starred {
+				return -1
+			}
+			return 1
+		}
+	case SortByDue:
+		// Tasks without due time come last in either direction.
+		if a.DueAt.IsZero() != b.DueAt.IsZero() {
+			if a.DueAt.IsZero() {
+				return 1
+			}
+			return -1
+		}
+		c = a.DueAt.Compare(b.DueAt)
+	case SortByUpdated:
+		c = lastUpdate(a).Compare(lastUpdate(b))
+	case SortByPosition:
+		c = cmp.Compare(a.Position, b.Position)
+	}
+	if c == 0 {
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	}
+	if c == 0 {
+		c = cmp.Compare(a.Seq, b.Seq)
+	}
+	if c == 0 {
+		c = cmp.Compare(a.ID, b.ID)
+	}
+	if o.Descending {
+		return -c
+	}
+	return c
+}
+
+// lastUpdate returns the time of the last update of the task, or its creation
+// time if it has never been updated.
+func lastUpdate(t *Task) time.Time {
+	if t.UpdatedAt.IsZero() {
+		return t.CreatedAt
+	}
+	return t.UpdatedAt
+}
@@ -0,0 +1,79 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+)
+
+// TaskMove describes where to move a task in the manual order of the to-do
+// list, see [TaskRepository.Move]. Exactly one of Before and After must be set.
+type TaskMove struct {
+	// Before is the ID of the task to move the task right before.
+	Before string
+	// After is the ID of the task to move the task right after.
+	After string
+}
+
+func newTaskMoveFromProto(req *todopb.MoveTaskRequest) *TaskMove {
+	return &TaskMove{
+		Before: req.GetBeforeId(),
+		After:  req.GetAfterId(),
+	}
+}
+
+// Validate checks if the task with the specified ID can be moved as described.
+func (m *TaskMove) Validate(id string) error {
+	switch {
+	case (m.Before == "") == (m.After == ""):
+		return errors.New("exactly one task to move the task before or after must be specified")
+	case m.Before == id || m.After == id:
+		return fmt.Errorf("cannot move task '%s' relative to itself", id)
+	}
+	return nil
+}
+
+// Apply moves the task with the specified ID within the manual order of the
+// specified tasks, and returns the new positions of the tasks whose position
+// changes. The positions are renumbered starting at 1. If the task to move or
+// the task to move it before or after is missing, it returns a
+// [TaskNotFoundError]. It modifies the given slice. Repositories without native
+// support for reordering tasks can use it to implement [TaskRepository.Move].
+func (m *TaskMove) Apply(tasks Tasks, id string) (map[string]int64, error) {
+	if err := m.Validate(id); err != nil {
+		return nil, err
+	}
+	order := &ListOptions{SortBy: SortByPosition}
+	slices.SortStableFunc(tasks, func(a, b Task) int {
+		return order.compare(&a, &b)
+	})
+	i := slices.IndexFunc(tasks, func(t Task) bool { return t.ID == id })
+	if i < 0 {
+		return nil, NewTaskNotFoundError(id)
+	}
+	moved := tasks[i]
+	tasks = slices.Delete(tasks, i, i+1)
+
+	target := m.Before
+	if target == "" {
+		target = m.After
+	}
+	j := slices.IndexFunc(tasks, func(t Task) bool { return t.ID == target })
+	if j < 0 {
+		return nil, NewTaskNotFoundError(target)
+	}
+	if m.After != "" {
+		j++
+	}
+	tasks = slices.Insert(tasks, j, moved)
+
+	positions := make(map[string]int64)
+	for k := range tasks {
+		if position := int64(k + 1); tasks[k].Position != position {
+			positions[tasks[k].ID] = position
+		}
+	}
+	return positions, nil
+}
@@ -1,6 +1,9 @@
 package todo
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // ServerStatus holds the status of the To-do Daemon server.
 type ServerStatus struct {
@@ -8,6 +11,26 @@ type ServerStatus struct {
 	PID int
 	// APIBaseURL is the base URL of the To-do Daemon's REST API.
 	APIBaseURL string
+	// Version is the semantic version of the To-do Daemon server.
+	Version string
+	// MinClientVersion is the minimum version of the CLI that the To-do Daemon
+	// server supports.
+	MinClientVersion string
+	// Uptime is the time elapsed since the To-do Daemon server was started.
+	Uptime time.Duration
+	// StorageBackend is the name of the storage backend used for persisting
+	// tasks.
+	StorageBackend string
+	// TaskCount is the number of tasks in the to-do list.
+	TaskCount int
+	// SocketAddress is the address of the socket or named pipe the gRPC server
+	// is listening on.
+	SocketAddress string
+	// HTTPAddress is the address the HTTP server is listening on.
+	HTTPAddress string
+	// Events holds the counters of the event bus, which show whether the
+	// clients watching the tasks keep up.
+	Events EventBusStats
 }
 
 // ServerStatusProvider is used to query the status of the To-do Daemon server.
@@ -23,3 +46,30 @@ type ServerStatusProviderFunc func(ctx context.Context) (*ServerStatus, error)
 func (f ServerStatusProviderFunc) Status(ctx context.Context) (*ServerStatus, error) {
 	return f(ctx)
 }
+
+// ConfigReload describes the outcome of reloading the configuration file of
+// the To-do Daemon server.
+type ConfigReload struct {
+	// Applied holds the names of the changed settings that have been applied,
+	// e.g. "log_level".
+	Applied []string
+	// RequiresRestart holds the names of the changed settings that only take
+	// effect after restarting the server, e.g. "sock_file".
+	RequiresRestart []string
+}
+
+// ConfigReloader is used to reload the configuration of the To-do Daemon
+// server.
+type ConfigReloader interface {
+	// ReloadConfig reloads the configuration file and applies the changed
+	// settings that don't require a restart.
+	ReloadConfig(ctx context.Context) (*ConfigReload, error)
+}
+
+// ConfigReloaderFunc is a function that implements [ConfigReloader].
+type ConfigReloaderFunc func(ctx context.Context) (*ConfigReload, error)
+
+// ReloadConfig reloads the configuration of the To-do Daemon server.
+func (f ConfigReloaderFunc) ReloadConfig(ctx context.Context) (*ConfigReload, error) {
+	return f(ctx)
+}
@@ -0,0 +1,47 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+// PurgeCompleted moves all tasks in the repository that were completed before
+// the specified time to the trash. It returns the removed tasks as they were
+// before their removal.
+//
+// If the repository supports batches, either all tasks are removed or none.
+// Otherwise, the tasks are removed one by one, and the tasks removed before
+// an error stay in the trash.
+func PurgeCompleted(ctx context.Context, tasks TaskRepository, before time.Time) (Tasks, error) {
+	completed, err := tasks.List(ctx, &ListOptions{Completion: CompletionCompleted})
+	if err != nil {
+		return nil, err
+	}
+	completed = slices.DeleteFunc(completed, func(t Task) bool {
+		return !t.CompletedAt.Before(before)
+	})
+	if len(completed) == 0 {
+		return nil, nil
+	}
+	if batch, ok := tasks.(BatchRepository); ok {
+		ops := make([]BatchOperation, len(completed))
+		for i := range completed {
+			ops[i] = BatchOperation{ID: completed[i].ID, Delete: true}
+		}
+		_, err := batch.ApplyBatch(ctx, ops)
+		switch {
+		case err == nil:
+			return completed, nil
+		case !errors.Is(err, ErrBatchUnsupported):
+			return nil, err
+		}
+	}
+	for i := range completed {
+		if err := tasks.Delete(ctx, completed[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return completed, nil
+}
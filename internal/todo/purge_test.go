@@ -0,0 +1,117 @@
+package todo
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+)
+
+// unbatchedRepository hides the batch support of a repository.
+type unbatchedRepository struct {
+	TaskRepository
+}
+
+// newPurgeTestDB creates a repository with an open task "a" and tasks "b",
+// "c", and "d" that were completed one hour, one day, and one week ago.
+func newPurgeTestDB(t *testing.T) *InMemoryTaskDB {
+	t.Helper()
+	db := NewInMemoryTaskDB()
+	now := time.Now()
+	ages := map[string]time.Duration{"b": time.Hour, "c": 24 * time.Hour, "d": 7 * 24 * time.Hour}
+	for _, summary := range []string{"a", "b", "c", "d"} {
+		created, err := db.Create(context.Background(), &TaskCreate{Summary: summary})
+		if err != nil {
+			t.Fatalf("cannot create task: %v", err)
+		}
+		if age, ok := ages[summary]; ok {
+			completedAt := now.Add(-age)
+			if _, err := db.Update(context.Background(), created.ID, &TaskUpdate{CompletedAt: &completedAt}); err != nil {
+				t.Fatalf("cannot complete task: %v", err)
+			}
+		}
+	}
+	return db
+}
+
+func TestPurgeCompleted(t *testing.T) {
+	tests := []struct {
+		name    string
+		batch   bool
+		before  time.Duration
+		removed []string
+		kept    []string
+	}{
+		{"All", true, 0, []string{"b", "c", "d"}, []string{"a"}},
+		{"OlderThan", true, 12 * time.Hour, []string{"c", "d"}, []string{"a", "b"}},
+		{"None", true, 30 * 24 * time.Hour, nil, []string{"a", "b", "c", "d"}},
+		{"Unbatched", false, 12 * time.Hour, []string{"c", "d"}, []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := newPurgeTestDB(t)
+			var repo TaskRepository = db
+			if !tt.batch {
+				repo = unbatchedRepository{db}
+			}
+			removed, err := PurgeCompleted(ctx, repo, time.Now().Add(-tt.before))
+			if err != nil {
+				t.Fatalf("cannot purge completed tasks: %v", err)
+			}
+			if got := summaries(removed); !slices.Equal(got, tt.removed) {
+				t.Errorf("want removed tasks %v; got: %v", tt.removed, got)
+			}
+			kept, err := db.List(ctx, &ListOptions{})
+			if err != nil {
+				t.Fatalf("cannot list tasks: %v", err)
+			}
+			if got := summaries(kept); !slices.Equal(got, tt.kept) {
+				t.Errorf("want remaining tasks %v; got: %v", tt.kept, got)
+			}
+		})
+	}
+}
+
+func TestControllerPurgeCompleted(t *testing.T) {
+	ctx := context.Background()
+	db := newPurgeTestDB(t)
+	ctrl := NewController(nil, nil, db, NewEventBus())
+
+	req := &todopb.PurgeCompletedRequest{OlderThan: durationpb.New(-time.Hour)}
+	if _, err := ctrl.PurgeCompleted(ctx, req); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("want %v for negative duration; got: %v", codes.InvalidArgument, err)
+	}
+
+	req = &todopb.PurgeCompletedRequest{OlderThan: durationpb.New(12 * time.Hour), Archive: true}
+	resp, err := ctrl.PurgeCompleted(ctx, req)
+	if err != nil {
+		t.Fatalf("cannot purge completed tasks: %v", err)
+	}
+	if got := resp.GetTasks(); len(got) != 2 || got[0].GetSummary() != "c" || got[1].GetSummary() != "d" {
+		t.Errorf("want tasks c and d; got: %v", got)
+	}
+	snapshot, err := ReadSnapshot(bytes.NewReader(resp.GetArchive()))
+	if err != nil {
+		t.Fatalf("cannot read archive: %v", err)
+	}
+	if len(snapshot.Tasks) != 2 || !snapshot.Tasks[0].DeletedAt.IsZero() {
+		t.Errorf("want archive of the tasks before their removal; got: %+v", snapshot.Tasks)
+	}
+}
+
+// summaries returns the summaries of the specified tasks.
+func summaries(tasks Tasks) []string {
+	var s []string
+	for i := range tasks {
+		s = append(s, tasks[i].Summary)
+	}
+	return s
+}
@@ -0,0 +1,255 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Quotas holds the soft limits of a to-do list, which protect the storage from
+// clients that add tasks in an endless loop, e.g. due to a bug in an
+// integration. Zero values mean no limit.
+type Quotas struct {
+	// MaxOpenTasks is the maximum number of open tasks in the to-do list.
+	MaxOpenTasks int
+	// MaxTagsPerTask is the maximum number of tags of a task. It only has an
+	// effect if it is lower than [MaxTags], which always applies.
+	MaxTagsPerTask int
+}
+
+// Enabled checks if any of the quotas limits the to-do list.
+func (q Quotas) Enabled() bool {
+	return q.MaxOpenTasks > 0 || q.MaxTagsPerTask > 0
+}
+
+// QuotaExceededError is returned by the repositories returned by
+// [NewQuotaRepository] when a modification would exceed one of the [Quotas].
+type QuotaExceededError struct {
+	// Quota is the name of the exceeded quota, e.g. "max_open_tasks".
+	Quota string
+	// Limit is the value of the exceeded quota.
+	Limit int
+	// Got is the value that the modification would have resulted in.
+	Got int
+}
+
+// NewQuotaExceededError creates a [QuotaExceededError] for the specified quota,
+// its limit, and the value that exceeds it.
+func NewQuotaExceededError(quota string, limit, got int) *QuotaExceededError {
+	return &QuotaExceededError{Quota: quota, Limit: limit, Got: got}
+}
+
+// IsQuotaExceededError checks if the provided error is a
+// [QuotaExceededError].
+func IsQuotaExceededError(err error) bool {
+	var e *QuotaExceededError
+	return err != nil && errors.As(err, &e)
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("quota exceeded: %s is %d, got %d", e.Quota, e.Limit, e.Got)
+}
+
+// quotaRepository is a [TaskRepository] that rejects the modifications that
+// would exceed its quotas.
+type quotaRepository struct {
+	TaskRepository
+	quotas Quotas
+}
+
+// NewQuotaRepository wraps the specified repository, so that creating,
+// reopening, restoring, and tagging tasks fails with a [QuotaExceededError]
+// if the to-do list would exceed the specified quotas. Replacing and merging
+// tasks, e.g. when restoring a backup or synchronizing, is not limited, so
+// that no tasks get lost. Like the other soft limits, the quotas are checked
+// before the modification, so concurrent requests may exceed them slightly.
+func NewQuotaRepository(tasks TaskRepository, quotas Quotas) TaskRepository {
+	return &quotaRepository{
+		TaskRepository: tasks,
+		quotas:         quotas,
+	}
+}
+
+// checkTags checks the number of tags of a task against the quota.
+func (r *quotaRepository) checkTags(tags []string) error {
+	if r.quotas.MaxTagsPerTask > 0 && len(tags) > r.quotas.MaxTagsPerTask {
+		return NewQuotaExceededError("max_tags_per_task", r.quotas.MaxTagsPerTask, len(tags))
+	}
+	return nil
+}
+
+// openTasks returns the open tasks by ID, including those in the trash if
+// requested, or nil if the number of open tasks is not limited.
+func (r *quotaRepository) openTasks(ctx context.Context, includeDeleted bool) (map[string]*Task, error) {
+	if r.quotas.MaxOpenTasks <= 0 {
+		return nil, nil
+	}
+	tasks, err := r.TaskRepository.List(ctx, &ListOptions{Completion: CompletionOpen, IncludeDeleted: includeDeleted})
+	if err != nil {
+		return nil, err
+	}
+	open := make(map[string]*Task, len(tasks))
+	for i := range tasks {
+		open[tasks[i].ID] = &tasks[i]
+	}
+	return open, nil
+}
+
+// checkOpen checks the specified number of open tasks against the quota.
+func (r *quotaRepository) checkOpen(open int) error {
+	if r.quotas.MaxOpenTasks > 0 && open > r.quotas.MaxOpenTasks {
+		return NewQuotaExceededError("max_open_tasks", r.quotas.MaxOpenTasks, open)
+	}
+	return nil
+}
+
+func (r *quotaRepository) Create(ctx context.Context, task *TaskCreate) (*Task, error) {
+	if err := r.checkTags(task.Tags); err != nil {
+		return nil, err
+	}
+	open, err := r.openTasks(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkOpen(len(open) + 1); err != nil {
+		return nil, err
+	}
+	return r.TaskRepository.Create(ctx, task)
+}
+
+func (r *quotaRepository) CreateAll(ctx context.Context, tasks []*TaskCreate) (Tasks, error) {
+	batch, ok := r.TaskRepository.(BatchRepository)
+	if !ok {
+		return nil, ErrBatchUnsupported
+	}
+	for _, task := range tasks {
+		if err := r.checkTags(task.Tags); err != nil {
+			return nil, err
+		}
+	}
+	open, err := r.openTasks(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkOpen(len(open) + len(tasks)); err != nil {
+		return nil, err
+	}
+	return batch.CreateAll(ctx, tasks)
+}
+
+// ApplyBatch checks the quotas against the state of the to-do list after all
+// operations, so a batch may delete or complete tasks to make room for new
+// ones. A batch that doesn't increase the number of open tasks is never
+// rejected, even if there are already too many, e.g. after the quota has been
+// lowered.
+func (r *quotaRepository) ApplyBatch(ctx context.Context, ops []BatchOperation) (Tasks, error) {
+	batch, ok := r.TaskRepository.(BatchRepository)
+	if !ok {
+		return nil, ErrBatchUnsupported
+	}
+	for i, op := range ops {
+		var err error
+		switch {
+		case op.Create != nil:
+			err = r.checkTags(op.Create.Tags)
+		case op.Update != nil && op.Update.Tags != nil:
+			err = r.checkTags(*op.Update.Tags)
+		}
+		if err != nil {
+			return nil, NewBatchOperationError(i, err)
+		}
+	}
+	open, err := r.openTasks(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil {
+		before := len(open)
+		// The IDs of created tasks are unknown, so they are counted
+		// separately; they cannot be updated or deleted by later operations
+		// anyway.
+		created, last := 0, -1
+		for i, op := range ops {
+			switch {
+			case op.Create != nil:
+				created++
+				last = i
+			case op.Delete:
+				delete(open, op.ID)
+			case op.Update != nil && op.Update.CompletedAt != nil:
+				if op.Update.CompletedAt.IsZero() {
+					if _, ok := open[op.ID]; !ok {
+						open[op.ID] = nil
+						last = i
+					}
+				} else {
+					delete(open, op.ID)
+				}
+			}
+		}
+		if after := len(open) + created; after > before {
+			if err := r.checkOpen(after); err != nil {
+				return nil, NewBatchOperationError(last, err)
+			}
+		}
+	}
+	return batch.ApplyBatch(ctx, ops)
+}
+
+func (r *quotaRepository) Update(ctx context.Context, id string, update *TaskUpdate) (*Task, error) {
+	if update.Tags != nil {
+		if err := r.checkTags(*update.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if update.CompletedAt != nil && update.CompletedAt.IsZero() && r.quotas.MaxOpenTasks > 0 {
+		// Only reopening a completed task adds an open task; errors like a
+		// missing task are left to the underlying repository.
+		if task, err := r.TaskRepository.Get(ctx, id); err == nil && !task.CompletedAt.IsZero() {
+			open, err := r.openTasks(ctx, false)
+			if err != nil {
+				return nil, err
+			}
+			if err := r.checkOpen(len(open) + 1); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return r.TaskRepository.Update(ctx, id, update)
+}
+
+func (r *quotaRepository) Restore(ctx context.Context, id string) (*Task, error) {
+	open, err := r.openTasks(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	if task, ok := open[id]; ok && !task.DeletedAt.IsZero() {
+		n := 0
+		for _, t := range open {
+			if t.DeletedAt.IsZero() {
+				n++
+			}
+		}
+		if err := r.checkOpen(n + 1); err != nil {
+			return nil, err
+		}
+	}
+	return r.TaskRepository.Restore(ctx, id)
+}
+
+func (r *quotaRepository) Changes(ctx context.Context, since time.Time) (Tasks, time.Time, error) {
+	tasks, ok := r.TaskRepository.(SyncRepository)
+	if !ok {
+		return nil, time.Time{}, ErrSyncUnsupported
+	}
+	return tasks.Changes(ctx, since)
+}
+
+func (r *quotaRepository) Merge(ctx context.Context, changes Tasks, since time.Time) (*MergeResult, error) {
+	tasks, ok := r.TaskRepository.(SyncRepository)
+	if !ok {
+		return nil, ErrSyncUnsupported
+	}
+	return tasks.Merge(ctx, changes, since)
+}
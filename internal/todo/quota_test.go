@@ -0,0 +1,115 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+)
+
+func TestQuotaRepositoryOpenTasks(t *testing.T) {
+	ctx := context.Background()
+	repo := NewQuotaRepository(NewInMemoryTaskDB(), Quotas{MaxOpenTasks: 2})
+	milk, err := repo.Create(ctx, &TaskCreate{Summary: "Buy milk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bread, err := repo.Create(ctx, &TaskCreate{Summary: "Buy bread"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Create(ctx, &TaskCreate{Summary: "Buy eggs"}); !IsQuotaExceededError(err) {
+		t.Errorf("want QuotaExceededError for third open task; got: %v", err)
+	}
+
+	// Completed tasks don't count, but reopening them does.
+	completedAt := time.Now()
+	if _, err := repo.Update(ctx, milk.ID, &TaskUpdate{CompletedAt: &completedAt}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Create(ctx, &TaskCreate{Summary: "Buy eggs"}); err != nil {
+		t.Errorf("want task to be created after completing another one; got: %v", err)
+	}
+	var reopen time.Time
+	if _, err := repo.Update(ctx, milk.ID, &TaskUpdate{CompletedAt: &reopen}); !IsQuotaExceededError(err) {
+		t.Errorf("want QuotaExceededError for reopening a task; got: %v", err)
+	}
+	if _, err := repo.Update(ctx, "42", &TaskUpdate{CompletedAt: &reopen}); !IsTaskNotFoundError(err) {
+		t.Errorf("want TaskNotFoundError for reopening a missing task; got: %v", err)
+	}
+
+	// Restoring an open task from the trash counts, too.
+	if err := repo.Delete(ctx, bread.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Create(ctx, &TaskCreate{Summary: "Call the plumber"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Restore(ctx, bread.ID); !IsQuotaExceededError(err) {
+		t.Errorf("want QuotaExceededError for restoring an open task; got: %v", err)
+	}
+}
+
+func TestQuotaRepositoryBatch(t *testing.T) {
+	ctx := context.Background()
+	repo := NewQuotaRepository(NewInMemoryTaskDB(), Quotas{MaxOpenTasks: 2}).(BatchRepository)
+	_, err := repo.CreateAll(ctx, []*TaskCreate{{Summary: "a"}, {Summary: "b"}, {Summary: "c"}})
+	if !IsQuotaExceededError(err) {
+		t.Errorf("want QuotaExceededError for creating 3 tasks; got: %v", err)
+	}
+	created, err := repo.CreateAll(ctx, []*TaskCreate{{Summary: "a"}, {Summary: "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A batch may delete tasks to make room for new ones, but only for as
+	// many as it deletes.
+	_, err = repo.ApplyBatch(ctx, []BatchOperation{
+		{Create: &TaskCreate{Summary: "c"}},
+		{Create: &TaskCreate{Summary: "d"}},
+		{ID: created[0].ID, Delete: true},
+	})
+	var e *BatchOperationError
+	if !IsQuotaExceededError(err) || !errors.As(err, &e) || e.Index != 1 {
+		t.Errorf("want QuotaExceededError for operations[1]; got: %v", err)
+	}
+	if _, err := repo.ApplyBatch(ctx, []BatchOperation{
+		{Create: &TaskCreate{Summary: "c"}},
+		{ID: created[0].ID, Delete: true},
+	}); err != nil {
+		t.Errorf("want batch replacing a task to succeed; got: %v", err)
+	}
+}
+
+func TestQuotaRepositoryTags(t *testing.T) {
+	ctx := context.Background()
+	repo := NewQuotaRepository(NewInMemoryTaskDB(), Quotas{MaxTagsPerTask: 2})
+	if _, err := repo.Create(ctx, &TaskCreate{Summary: "a", Tags: []string{"x", "y", "z"}}); !IsQuotaExceededError(err) {
+		t.Errorf("want QuotaExceededError for 3 tags; got: %v", err)
+	}
+	task, err := repo.Create(ctx, &TaskCreate{Summary: "a", Tags: []string{"x", "y"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags := []string{"x", "y", "z"}
+	if _, err := repo.Update(ctx, task.ID, &TaskUpdate{Tags: &tags}); !IsQuotaExceededError(err) {
+		t.Errorf("want QuotaExceededError for adding a third tag; got: %v", err)
+	}
+}
+
+func TestCreateTaskQuotaExceeded(t *testing.T) {
+	repo := NewQuotaRepository(NewInMemoryTaskDB(), Quotas{MaxOpenTasks: 1})
+	ctrl := NewController(nil, nil, repo, NewEventBus())
+	req := &todopb.CreateTaskRequest{Task: &todopb.NewTask{Summary: "Buy milk"}}
+	if _, err := ctrl.CreateTask(t.Context(), req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctrl.CreateTask(t.Context(), req); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("want RESOURCE_EXHAUSTED; got: %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package todo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the unit of the interval between the occurrences of a
+// recurring task.
+type Frequency string
+
+// The frequencies that tasks can recur with.
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// weekdayCodes are the codes of the weekdays in recurrence rules.
+var weekdayCodes = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Recurrence is the rule that a recurring task repeats by. When a recurring
+// task is completed, its next occurrence is created as a new task. The rule is
+// written like the recurrence rules of iCalendar (RFC 5545), of which it
+// supports the FREQ, INTERVAL, and BYDAY parts, e.g. "FREQ=WEEKLY;BYDAY=MO".
+type Recurrence struct {
+	// Freq is the unit of the interval between two occurrences.
+	Freq Frequency
+	// Interval is the number of units between two occurrences, at least 1.
+	Interval int
+	// Weekday is the day of the week that weekly occurrences fall on, if
+	// HasWeekday is set.
+	Weekday time.Weekday
+	// HasWeekday specifies whether the occurrences fall on Weekday.
+	HasWeekday bool
+}
+
+// ParseRecurrence parses a recurrence rule like "FREQ=WEEKLY;BYDAY=MO".
+func ParseRecurrence(rule string) (*Recurrence, error) {
+	r := &Recurrence{Interval: 1}
+	for part := range strings.SplitSeq(rule, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid recurrence rule '%s': malformed part '%s'", rule, part)
+		}
+		switch name {
+		case "FREQ":
+			switch f := Frequency(value); f {
+			case Daily, Weekly, Monthly, Yearly:
+				r.Freq = f
+			default:
+				return nil, fmt.Errorf("invalid recurrence rule '%s': unsupported frequency '%s'", rule, value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid recurrence rule '%s': invalid interval '%s'", rule, value)
+			}
+			r.Interval = n
+		case "BYDAY":
+			r.HasWeekday = false
+			for i, code := range weekdayCodes {
+				if value == code {
+					r.Weekday, r.HasWeekday = time.Weekday(i), true
+				}
+			}
+			if !r.HasWeekday {
+				return nil, fmt.Errorf("invalid recurrence rule '%s': unsupported weekday '%s'", rule, value)
+			}
+		default:
+			return nil, fmt.Errorf("invalid recurrence rule '%s': unsupported part '%s'", rule, name)
+		}
+	}
+	if r.Freq == "" {
+		return nil, fmt.Errorf("invalid recurrence rule '%s': no frequency", rule)
+	}
+	if r.HasWeekday && r.Freq != Weekly {
+		return nil, fmt.Errorf("invalid recurrence rule '%s': BYDAY requires weekly frequency", rule)
+	}
+	return r, nil
+}
+
+// String returns the recurrence rule in the format accepted by
+// [ParseRecurrence].
+func (r *Recurrence) String() string {
+	rule := "FREQ=" + string(r.Freq)
+	if r.Interval > 1 {
+		rule += ";INTERVAL=" + strconv.Itoa(r.Interval)
+	}
+	if r.HasWeekday {
+		rule += ";BYDAY=" + weekdayCodes[r.Weekday]
+	}
+	return rule
+}
+
+// Next returns the first occurrence after the specified time, at the same time
+// of day. If the occurrences fall on a weekday and t is on another day, the
+// next occurrence is the first such weekday after t, plus the interval minus
+// one week.
+func (r *Recurrence) Next(t time.Time) time.Time {
+	switch r.Freq {
+	case Daily:
+		return t.AddDate(0, 0, r.Interval)
+	case Weekly:
+		days := 7 * r.Interval
+		if r.HasWeekday && t.Weekday() != r.Weekday {
+			days = (int(r.Weekday)-int(t.Weekday())+7)%7 + 7*(r.Interval-1)
+		}
+		return t.AddDate(0, 0, days)
+	case Monthly:
+		return t.AddDate(0, r.Interval, 0)
+	default:
+		return t.AddDate(r.Interval, 0, 0)
+	}
+}
@@ -0,0 +1,68 @@
+package todo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	valid := map[string]string{
+		"FREQ=DAILY":                      "FREQ=DAILY",
+		"FREQ=WEEKLY;BYDAY=MO":            "FREQ=WEEKLY;BYDAY=MO",
+		"INTERVAL=2;FREQ=MONTHLY":         "FREQ=MONTHLY;INTERVAL=2",
+		"FREQ=YEARLY;INTERVAL=1":          "FREQ=YEARLY",
+		"FREQ=WEEKLY;INTERVAL=3;BYDAY=SU": "FREQ=WEEKLY;INTERVAL=3;BYDAY=SU",
+	}
+	for rule, want := range valid {
+		r, err := ParseRecurrence(rule)
+		if err != nil {
+			t.Errorf("%s: %v", rule, err)
+			continue
+		}
+		if got := r.String(); got != want {
+			t.Errorf("%s: want %s; got: %s", rule, want, got)
+		}
+	}
+	for _, rule := range []string{
+		"",
+		"DAILY",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;COUNT=3",
+		"INTERVAL=2",
+	} {
+		if _, err := ParseRecurrence(rule); err == nil {
+			t.Errorf("%q: want error", rule)
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	// 2026-10-14 is a Wednesday.
+	wed := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		rule string
+		want time.Time
+	}{
+		{"FREQ=DAILY", wed.AddDate(0, 0, 1)},
+		{"FREQ=DAILY;INTERVAL=3", wed.AddDate(0, 0, 3)},
+		{"FREQ=WEEKLY", wed.AddDate(0, 0, 7)},
+		{"FREQ=WEEKLY;BYDAY=WE", wed.AddDate(0, 0, 7)},
+		{"FREQ=WEEKLY;BYDAY=FR", wed.AddDate(0, 0, 2)},
+		{"FREQ=WEEKLY;BYDAY=MO", wed.AddDate(0, 0, 5)},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=FR", wed.AddDate(0, 0, 9)},
+		{"FREQ=MONTHLY", wed.AddDate(0, 1, 0)},
+		{"FREQ=YEARLY;INTERVAL=2", wed.AddDate(2, 0, 0)},
+	}
+	for _, tt := range tests {
+		r, err := ParseRecurrence(tt.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := r.Next(wed); !got.Equal(tt.want) {
+			t.Errorf("%s: want %v; got: %v", tt.rule, tt.want, got)
+		}
+	}
+}
@@ -1,104 +1,888 @@
 package todo
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"iter"
 	"maps"
 	"slices"
 	"strconv"
 	"sync"
 	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/search"
 )
 
 // TaskRepository defines functions for querying and persisting [Task]s.
+//
+// All functions that return tasks compute their [Task.BlockedBy] from the
+// current state of the tasks they depend on.
+//
+// All functions must honor the context: if the context is canceled or its
+// deadline is exceeded, they must return promptly with an error wrapping
+// [context.Context.Err], without modifying the repository. The conformance
+// test kit in package todotest checks this and all other behavior that is
+// expected of an implementation.
 type TaskRepository interface {
-	// All retrieves all tasks from the repository.
-	All(ctx context.Context) (Tasks, error)
-	// Create adds a new task to the repository.
+	// List retrieves the tasks selected by the specified options from the
+	// repository, sorted and paginated as specified. With the zero value of
+	// [ListOptions], it retrieves all tasks that have not been deleted,
+	// ordered by creation time.
+	List(ctx context.Context, opts *ListOptions) (Tasks, error)
+	// Get retrieves a single task from the repository. If the task does not
+	// exist, it returns a [TaskNotFoundError].
+	Get(ctx context.Context, id string) (*Task, error)
+	// Create adds a new task to the repository. If one of the tasks that the
+	// new task depends on does not exist, it returns a [TaskNotFoundError].
 	Create(ctx context.Context, task *TaskCreate) (*Task, error)
 	// Update modifies an existing task in the repository. If the task does not
-	// exist, it returns a [TaskNotFoundError].
+	// exist, it returns a [TaskNotFoundError]. If the task's version doesn't
+	// match the update's expected version, it returns a [TaskConflictError].
+	// If the update changes the task's dependencies, it returns a
+	// [TaskNotFoundError] for a dependency that does not exist and a
+	// [DependencyCycleError] if the dependencies would form a cycle, see
+	// [CheckDependencies].
 	Update(ctx context.Context, id string, update *TaskUpdate) (*Task, error)
-	// Delete removes an existing task from the repository. If the task does not
-	// exist, it returns a [TaskNotFoundError].
+	// Move moves an existing task in the manual order of the repository, see
+	// [TaskMove.Apply]. The moved task's version is incremented, whereas the
+	// other tasks keep their versions even if their positions change. If the
+	// task or the task to move it before or after does not exist, it returns
+	// a [TaskNotFoundError].
+	Move(ctx context.Context, id string, move *TaskMove) (*Task, error)
+	// Delete moves an existing task to the trash of the repository, setting
+	// its [Task.DeletedAt]. Tasks in the trash are only returned by List with
+	// [ListOptions.IncludeDeleted]; all other functions treat them as if they
+	// did not exist. If the task does not exist, it returns a
+	// [TaskNotFoundError].
 	Delete(ctx context.Context, id string) error
+	// Restore moves a task from the trash of the repository back to the
+	// to-do list, clearing its [Task.DeletedAt] and incrementing its version.
+	// If there is no task with the ID in the trash, it returns a
+	// [TaskNotFoundError].
+	Restore(ctx context.Context, id string) (*Task, error)
+	// Replace removes all tasks from the repository and adds the specified
+	// tasks instead, keeping their IDs and positions, e.g. for restoring a
+	// [Snapshot]. Tasks without position come last, in the given order.
+	Replace(ctx context.Context, tasks Tasks) error
+	// Search performs a full-text search across the summaries and descriptions
+	// of all tasks in the repository. It returns the matching tasks ordered by
+	// descending relevance.
+	Search(ctx context.Context, query string) ([]SearchResult, error)
+	// Stats aggregates statistics about the tasks in the repository at the
+	// specified time, see [ComputeStats].
+	Stats(ctx context.Context, now time.Time) (*Stats, error)
+	// Revision returns the current revision of the repository, which changes
+	// with each successful call of Create, Update, Move, Delete, Restore, or
+	// Replace.
+	Revision(ctx context.Context) (*Revision, error)
+}
+
+// ErrBatchUnsupported is returned by the repositories that cannot create
+// several tasks at once.
+var ErrBatchUnsupported = errors.New("the storage backend does not support creating several tasks at once")
+
+// BatchRepository is a [TaskRepository] that can create several tasks
+// atomically, e.g. all tasks of a [Template].
+type BatchRepository interface {
+	TaskRepository
+	// CreateAll adds the specified new tasks to the repository in the given
+	// order, either all of them or, if any of them cannot be created, none.
+	// If one of the tasks that a new task depends on does not exist, it
+	// returns a [TaskNotFoundError].
+	CreateAll(ctx context.Context, tasks []*TaskCreate) (Tasks, error)
+	// ApplyBatch applies the specified operations in the given order, either
+	// all of them or, if any of them fails, none. It returns the resulting
+	// task of each operation; a deleted task has its [Task.DeletedAt] set. The
+	// error of a failed operation is wrapped in a [BatchOperationError].
+	ApplyBatch(ctx context.Context, ops []BatchOperation) (Tasks, error)
 }
 
-// InMemoryTaskDB is an in-memory implementation of [TaskRepository]. It just
-// stores tasks in a map.
+// InMemoryTaskDB is an in-memory implementation of [SyncRepository]. It stores
+// tasks in a map, along with a full-text index for searching them and sorted
+// indexes for listing them page by page without sorting all tasks each time.
 type InMemoryTaskDB struct {
 	mu    sync.Mutex
 	tasks map[string]Task
+	index *search.Index
+	// byCreation holds the tasks in the map ordered by creation time and ID.
+	byCreation []creationKey
+	// byPosition holds the IDs of the tasks in the map in the manual order,
+	// unless positionsChanged is true; then it is rebuilt when needed.
+	byPosition       []string
+	positionsChanged bool
+	// position is the highest position of all tasks in the map.
+	position int64
+	// seq is the highest sequence number of all tasks in the map. The IDs of
+	// new tasks are their sequence numbers.
+	seq uint64
+	// revision is the current revision of the task map, see [Revision].
+	revision Revision
+}
+
+// creationKey is the key of a task in the index of the tasks ordered by
+// creation time.
+type creationKey struct {
+	createdAt time.Time
+	seq       uint64
+	id        string
+}
+
+func newCreationKey(t *Task) creationKey {
+	return creationKey{createdAt: t.CreatedAt, seq: t.Seq, id: t.ID}
+}
+
+func compareCreationKeys(a, b creationKey) int {
+	if c := a.createdAt.Compare(b.createdAt); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.seq, b.seq); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.id, b.id)
 }
 
 // NewInMemoryTaskDB creates a new instance of [InMemoryTaskDB] with an empty
 // map of tasks.
 func NewInMemoryTaskDB() *InMemoryTaskDB {
 	return &InMemoryTaskDB{
-		tasks: make(map[string]Task),
+		tasks:    make(map[string]Task),
+		index:    search.NewIndex(),
+		revision: Revision{ModifiedAt: time.Now()},
 	}
 }
 
-// All returns all tasks stored in the task map.
-func (db *InMemoryTaskDB) All(_ context.Context) (Tasks, error) {
+// List returns the tasks in the task map that are selected by the specified
+// options. Tasks sorted by creation time or by position are taken from the
+// sorted indexes, stopping once the page is full. Otherwise, only the
+// selected tasks are copied and sorted.
+func (db *InMemoryTaskDB) List(ctx context.Context, opts *ListOptions) (Tasks, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	now := time.Now()
 	db.mu.Lock()
 	defer db.mu.Unlock()
-	tasks := slices.Collect(maps.Values(db.tasks))
-	// Sort by creation time in ascending order.
-	slices.SortFunc(tasks, func(a, b Task) int {
-		return a.CreatedAt.Compare(b.CreatedAt)
-	})
+	tasks := opts.Paginate(db.sorted(opts, now), now)
+	for i := range tasks {
+		tasks[i] = db.withBlockedBy(tasks[i])
+	}
 	return tasks, nil
 }
 
+// sorted returns the tasks in the task map in the order specified by the
+// options. The caller must hold the lock while iterating.
+func (db *InMemoryTaskDB) sorted(opts *ListOptions, now time.Time) iter.Seq[*Task] {
+	switch opts.SortBy {
+	case SortByCreated:
+		// Starred tasks come first in either direction. The yielded task is
+		// reused, so it is copied only once per iteration, not once per task.
+		return func(yield func(*Task) bool) {
+			var t Task
+			for _, starred := range []bool{true, false} {
+				if opts.Starred && !starred {
+					return
+				}
+				for _, key := range inOrder(db.byCreation, opts.Descending) {
+					t = db.tasks[key.id]
+					if t.Starred == starred && !yield(&t) {
+						return
+					}
+				}
+			}
+		}
+	case SortByPosition:
+		if db.positionsChanged {
+			db.sortByPosition()
+		}
+		return func(yield func(*Task) bool) {
+			var t Task
+			for _, id := range inOrder(db.byPosition, opts.Descending) {
+				t = db.tasks[id]
+				if !yield(&t) {
+					return
+				}
+			}
+		}
+	}
+	// There is no index for the other orders, so sort the matching tasks.
+	// Sorting pointers is much faster than sorting the tasks themselves.
+	matching := make(Tasks, 0, len(db.tasks))
+	for _, t := range db.tasks {
+		if opts.Matches(&t, now) {
+			matching = append(matching, t)
+		}
+	}
+	ptrs := make([]*Task, len(matching))
+	for i := range matching {
+		ptrs[i] = &matching[i]
+	}
+	slices.SortFunc(ptrs, opts.compare)
+	return slices.Values(ptrs)
+}
+
+// inOrder returns an iterator over the specified slice in ascending or
+// descending order.
+func inOrder[S ~[]E, E any](s S, descending bool) iter.Seq2[int, E] {
+	if descending {
+		return slices.Backward(s)
+	}
+	return slices.All(s)
+}
+
+// Stats aggregates the statistics of the tasks in the task map.
+func (db *InMemoryTaskDB) Stats(ctx context.Context, now time.Time) (*Stats, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	db.mu.Lock()
+	tasks := slices.Collect(maps.Values(db.tasks))
+	db.mu.Unlock()
+	return ComputeStats(tasks, now), nil
+}
+
+// Revision returns the current revision of the task map.
+func (db *InMemoryTaskDB) Revision(ctx context.Context) (*Revision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	r := db.revision
+	return &r, nil
+}
+
+// Get returns the task with the specified ID from the task map.
+func (db *InMemoryTaskDB) Get(ctx context.Context, id string) (*Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	t, ok := db.active(id)
+	if !ok {
+		return nil, NewTaskNotFoundError(id)
+	}
+	t = db.withBlockedBy(t)
+	return &t, nil
+}
+
 // Create adds a new task to the task map.
-func (db *InMemoryTaskDB) Create(_ context.Context, task *TaskCreate) (*Task, error) {
+func (db *InMemoryTaskDB) Create(ctx context.Context, task *TaskCreate) (*Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if task == nil {
 		return nil, errors.New("task cannot be nil")
 	}
 	db.mu.Lock()
 	defer db.mu.Unlock()
-	t := Task{
-		ID:        strconv.Itoa(len(db.tasks) + 1),
-		Summary:   task.Summary,
-		CreatedAt: time.Now(),
+	if err := CheckDependencies("", task.DependsOn, db.lookup); err != nil {
+		return nil, err
 	}
-	db.tasks[t.ID] = t
+	t := db.create(task)
+	db.modified()
+	t = db.withBlockedBy(t)
 	return &t, nil
 }
 
+// CreateAll adds the specified new tasks to the task map at once. If any of
+// them depends on a task that does not exist, none of them is added.
+func (db *InMemoryTaskDB) CreateAll(ctx context.Context, tasks []*TaskCreate) (Tasks, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if slices.Contains(tasks, nil) {
+		return nil, errors.New("task cannot be nil")
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for _, task := range tasks {
+		if err := CheckDependencies("", task.DependsOn, db.lookup); err != nil {
+			return nil, err
+		}
+	}
+	created := make(Tasks, len(tasks))
+	for i, task := range tasks {
+		created[i] = db.create(task)
+	}
+	db.modified()
+	for i := range created {
+		created[i] = db.withBlockedBy(created[i])
+	}
+	return created, nil
+}
+
+// create adds the specified new task, whose dependencies have been checked,
+// to the task map. The caller must hold the lock.
+func (db *InMemoryTaskDB) create(task *TaskCreate) Task {
+	db.position++
+	db.seq++
+	t := newTask(db.seq, db.position, task, time.Now())
+	db.put(t)
+	return t
+}
+
+// newTask returns the task with the specified sequence number, which is also
+// its ID, and position that the specified new task becomes when it is created
+// at the specified time.
+func newTask(seq uint64, position int64, task *TaskCreate, now time.Time) Task {
+	return Task{
+		ID:          strconv.FormatUint(seq, 10),
+		Seq:         seq,
+		UID:         NewUID(),
+		Summary:     task.Summary,
+		Description: task.Description,
+		CreatedAt:   now,
+		DueAt:       InTimeZone(task.DueAt, task.TimeZone),
+		Version:     1,
+		Tags:        slices.Clone(task.Tags),
+		Project:     task.Project,
+		Position:    position,
+		DependsOn:   slices.Clone(task.DependsOn),
+		Recurrence:  task.Recurrence,
+		TimeZone:    task.TimeZone,
+		Starred:     task.Starred,
+		Assignee:    task.Assignee,
+		Links:       slices.Clone(task.Links),
+	}
+}
+
+// idSeq returns the sequence number that the specified ID stands for, or zero
+// if the ID is not a number.
+func idSeq(id string) uint64 {
+	seq, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return seq
+}
+
+// ApplyBatch applies the specified operations to a staged copy of the tasks
+// they modify, and only puts the staged tasks into the task map once all
+// operations have succeeded. Each operation sees the changes of the ones
+// before it.
+func (db *InMemoryTaskDB) ApplyBatch(ctx context.Context, ops []BatchOperation) (Tasks, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	staged := make(map[string]Task)
+	var order []string
+	lookup := func(id string) (*Task, bool) {
+		if t, ok := staged[id]; ok {
+			return &t, true
+		}
+		return db.lookup(id)
+	}
+	seq, position := db.seq, db.position
+	now := time.Now()
+	results := make(Tasks, len(ops))
+	for i, op := range ops {
+		var t Task
+		if op.Create != nil {
+			if err := CheckDependencies("", op.Create.DependsOn, lookup); err != nil {
+				return nil, NewBatchOperationError(i, err)
+			}
+			seq++
+			position++
+			t = newTask(seq, position, op.Create, now)
+		} else {
+			current, ok := lookup(op.ID)
+			if !ok || !current.DeletedAt.IsZero() {
+				return nil, NewBatchOperationError(i, NewTaskNotFoundError(op.ID))
+			}
+			var err error
+			if t, err = applyOperation(*current, &op, lookup, now); err != nil {
+				return nil, NewBatchOperationError(i, err)
+			}
+		}
+		if _, ok := staged[t.ID]; !ok {
+			order = append(order, t.ID)
+		}
+		staged[t.ID] = t
+		results[i] = t
+	}
+	for _, id := range order {
+		db.put(staged[id])
+	}
+	db.seq, db.position = seq, position
+	if len(ops) > 0 {
+		db.modified()
+	}
+	for i := range results {
+		results[i] = db.withBlockedBy(results[i])
+	}
+	return results, nil
+}
+
+// applyOperation returns the specified existing task after applying the
+// specified update or delete operation at the specified time, given a
+// function that looks up the tasks it may depend on.
+func applyOperation(t Task, op *BatchOperation, lookup func(id string) (*Task, bool), now time.Time) (Task, error) {
+	switch {
+	case op.Delete:
+		t.DeletedAt = now
+		return t, nil
+	case op.Update != nil:
+		update := op.Update
+		if update.ExpectedVersion != 0 && update.ExpectedVersion != t.Version {
+			return Task{}, NewTaskConflictError(t.ID, update.ExpectedVersion, t.Version)
+		}
+		if update.DependsOn != nil {
+			if err := CheckDependencies(t.ID, *update.DependsOn, lookup); err != nil {
+				return Task{}, err
+			}
+		}
+		return update.apply(t, now), nil
+	default:
+		return Task{}, errors.New("operation must create, update, or delete a task")
+	}
+}
+
 // Update modifies an existing task in the task map
-func (db *InMemoryTaskDB) Update(_ context.Context, id string, update *TaskUpdate) (*Task, error) {
+func (db *InMemoryTaskDB) Update(ctx context.Context, id string, update *TaskUpdate) (*Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if update == nil {
 		return nil, errors.New("update cannot be nil")
 	}
 	db.mu.Lock()
 	defer db.mu.Unlock()
-	t, ok := db.tasks[id]
+	t, ok := db.active(id)
 	if !ok {
 		return nil, NewTaskNotFoundError(id)
 	}
-	now := time.Now()
-	if update.Summary != nil {
-		t.Summary = *update.Summary
+	if update.ExpectedVersion != 0 && update.ExpectedVersion != t.Version {
+		return nil, NewTaskConflictError(id, update.ExpectedVersion, t.Version)
+	}
+	if update.DependsOn != nil {
+		if err := CheckDependencies(id, *update.DependsOn, db.lookup); err != nil {
+			return nil, err
+		}
+	}
+	t = update.apply(t, time.Now())
+	db.put(t)
+	db.modified()
+	t = db.withBlockedBy(t)
+	return &t, nil
+}
+
+// apply returns the specified task with the update applied at the specified
+// time, incrementing its version. The update's precondition and dependencies
+// must have been checked.
+func (u *TaskUpdate) apply(t Task, now time.Time) Task {
+	if u.Summary != nil {
+		t.Summary = *u.Summary
 		t.UpdatedAt = now
 	}
-	if update.CompletedAt != nil {
-		t.CompletedAt = *update.CompletedAt
+	if u.Description != nil {
+		t.Description = *u.Description
 		t.UpdatedAt = now
 	}
-	db.tasks[t.ID] = t
+	if u.CompletedAt != nil {
+		t.CompletedAt = *u.CompletedAt
+		t.UpdatedAt = now
+	}
+	if u.DueAt != nil {
+		t.DueAt = *u.DueAt
+		t.UpdatedAt = now
+	}
+	if u.Tags != nil {
+		t.Tags = slices.Clone(*u.Tags)
+		t.UpdatedAt = now
+	}
+	if u.Project != nil {
+		t.Project = *u.Project
+		t.UpdatedAt = now
+	}
+	if u.DependsOn != nil {
+		t.DependsOn = slices.Clone(*u.DependsOn)
+		t.UpdatedAt = now
+	}
+	if u.Recurrence != nil {
+		t.Recurrence = *u.Recurrence
+		t.UpdatedAt = now
+	}
+	if u.TimeZone != nil {
+		t.TimeZone = *u.TimeZone
+		t.UpdatedAt = now
+	}
+	if u.Starred != nil {
+		t.Starred = *u.Starred
+		t.UpdatedAt = now
+	}
+	if u.Assignee != nil {
+		t.Assignee = *u.Assignee
+		t.UpdatedAt = now
+	}
+	if u.Links != nil {
+		t.Links = slices.Clone(*u.Links)
+		t.UpdatedAt = now
+	}
+	t.DueAt = InTimeZone(t.DueAt, t.TimeZone)
+	t.Version++
+	return t
+}
+
+// Move moves a task in the manual order of the task map.
+func (db *InMemoryTaskDB) Move(ctx context.Context, id string, move *TaskMove) (*Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if move == nil {
+		return nil, errors.New("move cannot be nil")
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	var tasks Tasks
+	for _, t := range db.tasks {
+		if t.DeletedAt.IsZero() {
+			tasks = append(tasks, t)
+		}
+	}
+	positions, err := move.Apply(tasks, id)
+	if err != nil {
+		return nil, err
+	}
+	for id, position := range positions {
+		t := db.tasks[id]
+		t.Position = position
+		db.tasks[id] = t
+	}
+	db.positionsChanged = true
+	t := db.tasks[id]
+	t.UpdatedAt = time.Now()
+	t.Version++
+	db.tasks[id] = t
+	db.modified()
+	t = db.withBlockedBy(t)
 	return &t, nil
 }
 
-// Delete removes a task from the task map by its ID.
-func (db *InMemoryTaskDB) Delete(_ context.Context, id string) error {
+// Delete moves a task in the task map to the trash by its ID. The task is kept
+// as a tombstone, so its deletion can be synchronized.
+func (db *InMemoryTaskDB) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	db.mu.Lock()
 	defer db.mu.Unlock()
-	_, ok := db.tasks[id]
+	t, ok := db.active(id)
 	if !ok {
 		return NewTaskNotFoundError(id)
 	}
-	delete(db.tasks, id)
+	t.DeletedAt = time.Now()
+	db.put(t)
+	db.modified()
 	return nil
 }
+
+// Restore moves a task in the task map out of the trash by its ID.
+func (db *InMemoryTaskDB) Restore(ctx context.Context, id string) (*Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	t, ok := db.tasks[id]
+	if !ok || t.DeletedAt.IsZero() {
+		return nil, NewTaskNotFoundError(id)
+	}
+	t.DeletedAt = time.Time{}
+	t.UpdatedAt = time.Now()
+	t.Version++
+	// Moving tasks while the task was in the trash renumbers the other tasks,
+	// so its old position may be taken. It is moved to the end then.
+	for _, other := range db.tasks {
+		if other.ID != id && other.DeletedAt.IsZero() && other.Position == t.Position {
+			db.position++
+			t.Position = db.position
+			break
+		}
+	}
+	db.put(t)
+	db.modified()
+	t = db.withBlockedBy(t)
+	return &t, nil
+}
+
+// Replace replaces the task map with the specified tasks.
+func (db *InMemoryTaskDB) Replace(ctx context.Context, tasks Tasks) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.tasks = make(map[string]Task, len(tasks))
+	db.index = search.NewIndex()
+	db.byCreation = make([]creationKey, 0, len(tasks))
+	db.byPosition = nil
+	db.positionsChanged = true
+	db.position, db.seq = 0, 0
+	for _, t := range tasks {
+		db.position = max(db.position, t.Position)
+		db.seq = max(db.seq, t.Seq, idSeq(t.ID))
+	}
+	// Tasks of older repositories have no sequence numbers. Numeric IDs were
+	// assigned in the order of creation, so they are used instead.
+	byCreation := slices.SortedStableFunc(slices.Values(tasks), func(a, b Task) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	for _, t := range byCreation {
+		if t.Seq == 0 {
+			if t.Seq = idSeq(t.ID); t.Seq == 0 {
+				db.seq++
+				t.Seq = db.seq
+			}
+		}
+		if t.Position == 0 {
+			db.position++
+			t.Position = db.position
+		}
+		if t.UID == "" {
+			t.UID = NewUID()
+		}
+		t.BlockedBy = nil
+		t.DueAt = InTimeZone(t.DueAt, t.TimeZone)
+		db.tasks[t.ID] = t
+		db.indexTask(&t)
+	}
+	for _, t := range db.tasks {
+		db.byCreation = append(db.byCreation, newCreationKey(&t))
+	}
+	slices.SortFunc(db.byCreation, compareCreationKeys)
+	db.revision = Revision{ModifiedAt: time.Now()}
+	return nil
+}
+
+// Changes returns the tasks in the task map, including the trash, that were
+// changed after the specified time, ordered by creation time.
+func (db *InMemoryTaskDB) Changes(ctx context.Context, since time.Time) (Tasks, time.Time, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, time.Time{}, err
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	now := time.Now()
+	var tasks Tasks
+	for _, key := range db.byCreation {
+		t := db.tasks[key.id]
+		if !ChangedAt(&t).After(since) {
+			continue
+		}
+		uids := make([]string, 0, len(t.DependsOn))
+		for _, id := range t.DependsOn {
+			if dep, ok := db.tasks[id]; ok {
+				uids = append(uids, dep.UID)
+			}
+		}
+		t.DependsOn = uids
+		tasks = append(tasks, t)
+	}
+	return tasks, now, nil
+}
+
+// Merge merges the specified tasks of another to-do list into the task map.
+// New tasks come last in the manual order. The dependencies on tasks that are
+// in neither to-do list are dropped.
+func (db *InMemoryTaskDB) Merge(ctx context.Context, tasks Tasks, since time.Time) (*MergeResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		if tasks[i].UID == "" {
+			return nil, errors.New("task without UID")
+		}
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	ids := make(map[string]string, len(db.tasks))
+	for id, t := range db.tasks {
+		ids[t.UID] = id
+	}
+	// Assign IDs to the new tasks first, so the dependencies on them can be
+	// resolved. Tasks deleted before they were ever synchronized are skipped.
+	created := make(map[string]bool)
+	for i := range tasks {
+		r := &tasks[i]
+		if _, ok := ids[r.UID]; ok || !r.DeletedAt.IsZero() {
+			continue
+		}
+		db.seq++
+		id := strconv.FormatUint(db.seq, 10)
+		ids[r.UID] = id
+		created[id] = true
+	}
+	now := time.Now()
+	result := &MergeResult{}
+	for i := range tasks {
+		remote := tasks[i]
+		id, ok := ids[remote.UID]
+		if !ok {
+			continue
+		}
+		deps := make([]string, 0, len(remote.DependsOn))
+		for _, uid := range remote.DependsOn {
+			if dep, ok := ids[uid]; ok {
+				deps = append(deps, dep)
+			}
+		}
+		remote.DependsOn = deps
+		if created[id] {
+			db.position++
+			t := Task{ID: id, Seq: idSeq(id), UID: remote.UID, CreatedAt: remote.CreatedAt, Version: 1, Position: db.position}
+			takeRemote(&t, &remote)
+			db.put(t)
+			t = db.withBlockedBy(t)
+			result.Applied = append(result.Applied, Event{Type: EventTaskCreated, Task: t, Time: now})
+			if t.Assignee != "" {
+				result.Applied = append(result.Applied, Event{Type: EventTaskAssigned, Task: t, Time: now})
+			}
+			continue
+		}
+		old := db.tasks[id]
+		merged := old
+		remoteWins, conflict := resolve(&old, &remote, since)
+		if remoteWins {
+			takeRemote(&merged, &remote)
+			merged.Version++
+			db.put(merged)
+			merged = db.withBlockedBy(merged)
+			result.Applied = append(result.Applied, mergeEvents(&old, &merged, now)...)
+		}
+		if conflict {
+			result.Conflicts = append(result.Conflicts, MergeConflict{
+				Task:            db.withBlockedBy(merged),
+				RemoteWins:      remoteWins,
+				LocalChangedAt:  ChangedAt(&old),
+				RemoteChangedAt: ChangedAt(&remote),
+			})
+		}
+	}
+	if len(result.Applied) > 0 {
+		db.modified()
+	}
+	return result, nil
+}
+
+// Search performs a full-text search using the task map's index.
+func (db *InMemoryTaskDB) Search(ctx context.Context, query string) ([]SearchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	hits := db.index.Search(query)
+	results := make([]SearchResult, len(hits))
+	for i, hit := range hits {
+		results[i] = SearchResult{
+			Task:  db.withBlockedBy(db.tasks[hit.ID]),
+			Score: hit.Score,
+		}
+	}
+	return results, nil
+}
+
+// put stores the specified task in the task map and updates the indexes. The
+// caller must hold the lock.
+func (db *InMemoryTaskDB) put(t Task) {
+	old, exists := db.tasks[t.ID]
+	if exists && (!old.CreatedAt.Equal(t.CreatedAt) || old.Seq != t.Seq || old.Position != t.Position) {
+		db.remove(t.ID)
+		exists = false
+	}
+	db.tasks[t.ID] = t
+	db.indexTask(&t)
+	if exists {
+		return
+	}
+	key := newCreationKey(&t)
+	i, _ := slices.BinarySearchFunc(db.byCreation, key, compareCreationKeys)
+	db.byCreation = slices.Insert(db.byCreation, i, key)
+	// New tasks usually come last in the manual order.
+	n := len(db.byPosition)
+	if db.positionsChanged || n > 0 && db.tasks[db.byPosition[n-1]].Position >= t.Position {
+		db.positionsChanged = true
+		return
+	}
+	db.byPosition = append(db.byPosition, t.ID)
+}
+
+// remove removes the task with the specified ID from the task map and the
+// indexes. The caller must hold the lock.
+func (db *InMemoryTaskDB) remove(id string) {
+	t, ok := db.tasks[id]
+	if !ok {
+		return
+	}
+	key := newCreationKey(&t)
+	if i, found := slices.BinarySearchFunc(db.byCreation, key, compareCreationKeys); found {
+		db.byCreation = slices.Delete(db.byCreation, i, i+1)
+	}
+	if !db.positionsChanged {
+		if i := slices.Index(db.byPosition, id); i >= 0 {
+			db.byPosition = slices.Delete(db.byPosition, i, i+1)
+		}
+	}
+	delete(db.tasks, id)
+	db.index.Remove(id)
+}
+
+// sortByPosition rebuilds the index of the tasks in the manual order. The
+// caller must hold the lock.
+func (db *InMemoryTaskDB) sortByPosition() {
+	db.byPosition = slices.Collect(maps.Keys(db.tasks))
+	slices.SortFunc(db.byPosition, func(a, b string) int {
+		ta, tb := db.tasks[a], db.tasks[b]
+		if c := cmp.Compare(ta.Position, tb.Position); c != 0 {
+			return c
+		}
+		return compareCreationKeys(newCreationKey(&ta), newCreationKey(&tb))
+	})
+	db.positionsChanged = false
+}
+
+// modified advances the revision of the task map after a modification. The
+// caller must hold the lock.
+func (db *InMemoryTaskDB) modified() {
+	db.revision.Counter++
+	db.revision.ModifiedAt = time.Now()
+}
+
+// lookup returns the task with the specified ID from the task map. The caller
+// must hold the lock.
+func (db *InMemoryTaskDB) lookup(id string) (*Task, bool) {
+	t, ok := db.tasks[id]
+	return &t, ok
+}
+
+// active returns the task with the specified ID from the task map, unless it
+// is in the trash. The caller must hold the lock.
+func (db *InMemoryTaskDB) active(id string) (Task, bool) {
+	t, ok := db.tasks[id]
+	return t, ok && t.DeletedAt.IsZero()
+}
+
+// withBlockedBy returns the specified task with its open dependencies. The
+// caller must hold the lock.
+func (db *InMemoryTaskDB) withBlockedBy(t Task) Task {
+	t.BlockedBy = OpenDependencies(&t, db.lookup)
+	return t
+}
+
+// indexTask adds the specified task to the full-text index, or removes it if
+// it is in the trash. The caller must hold the lock.
+func (db *InMemoryTaskDB) indexTask(t *Task) {
+	if !t.DeletedAt.IsZero() {
+		db.index.Remove(t.ID)
+		return
+	}
+	// Matches in the summary are more relevant than in the description.
+	db.index.Put(t.ID,
+		search.Field{Text: t.Summary, Weight: 2},
+		search.Field{Text: t.Description, Weight: 1},
+	)
+}
@@ -0,0 +1,153 @@
+package todo_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/todo"
+	"github.com/mwopitz/todo-daemon/internal/todo/todotest"
+)
+
+func TestInMemoryTaskDB(t *testing.T) {
+	todotest.RunRepositoryTests(t, func(_ *testing.T) todo.TaskRepository {
+		return todo.NewInMemoryTaskDB()
+	})
+}
+
+// newLargeDB creates an in-memory repository with the specified number of
+// tasks, every tenth of which is starred and every third of which is due.
+func newLargeDB(tb testing.TB, n int) *todo.InMemoryTaskDB {
+	tb.Helper()
+	ctx := context.Background()
+	db := todo.NewInMemoryTaskDB()
+	due := time.Date(2025, 12, 24, 18, 0, 0, 0, time.UTC)
+	for i := range n {
+		task := &todo.TaskCreate{Summary: fmt.Sprintf("Task %d", i), Starred: i%10 == 0}
+		if i%3 == 0 {
+			task.DueAt = due.Add(time.Duration(n-i) * time.Minute)
+		}
+		if _, err := db.Create(ctx, task); err != nil {
+			tb.Fatalf("cannot create task: %v", err)
+		}
+	}
+	return db
+}
+
+// TestInMemoryTaskDBListIndexes checks that listing the tasks using the
+// sorted indexes yields the same tasks as sorting all tasks, also after the
+// tasks have been modified.
+func TestInMemoryTaskDBListIndexes(t *testing.T) {
+	ctx := context.Background()
+	db := newLargeDB(t, 100)
+	starred, done := true, time.Now()
+	for _, id := range []string{"5", "17", "42"} {
+		if _, err := db.Update(ctx, id, &todo.TaskUpdate{Starred: &starred, CompletedAt: &done}); err != nil {
+			t.Fatalf("cannot update task: %v", err)
+		}
+	}
+	for _, id := range []string{"3", "64"} {
+		if err := db.Delete(ctx, id); err != nil {
+			t.Fatalf("cannot delete task: %v", err)
+		}
+	}
+	if _, err := db.Move(ctx, "90", &todo.TaskMove{Before: "2"}); err != nil {
+		t.Fatalf("cannot move task: %v", err)
+	}
+	if _, err := db.Create(ctx, &todo.TaskCreate{Summary: "last"}); err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+
+	all, err := db.List(ctx, &todo.ListOptions{SortBy: todo.SortByUpdated})
+	if err != nil {
+		t.Fatalf("cannot list tasks: %v", err)
+	}
+	for _, opts := range []todo.ListOptions{
+		{},
+		{Descending: true},
+		{Offset: 5, Limit: 10},
+		{Starred: true, Descending: true},
+		{Completion: todo.CompletionOpen, Offset: 20, Limit: 20},
+		{SortBy: todo.SortByPosition},
+		{SortBy: todo.SortByPosition, Descending: true, Limit: 3},
+		{SortBy: todo.SortByDue, Limit: 10},
+		{After: todo.NewCursor(&all[30]), Limit: 10},
+		{SortBy: todo.SortByPosition, After: todo.NewCursor(&all[60])},
+	} {
+		want := ids(opts.Apply(slices.Clone(all), time.Now()))
+		tasks, err := db.List(ctx, &opts)
+		if err != nil {
+			t.Fatalf("cannot list tasks: %v", err)
+		}
+		if got := ids(tasks); !slices.Equal(got, want) {
+			t.Errorf("%+v: want tasks: %v; got: %v", opts, want, got)
+		}
+	}
+}
+
+// TestInMemoryTaskDBSeq checks that tasks created at the same time are listed
+// in the order of their sequence numbers, and that new tasks get IDs that are
+// not taken, even if the IDs of the existing tasks have gaps.
+func TestInMemoryTaskDBSeq(t *testing.T) {
+	ctx := context.Background()
+	db := todo.NewInMemoryTaskDB()
+	created := time.Date(2025, 12, 24, 18, 0, 0, 0, time.UTC)
+	tasks := todo.Tasks{
+		{ID: "10", Summary: "b", CreatedAt: created},
+		{ID: "x", Summary: "c", CreatedAt: created},
+		{ID: "9", Summary: "a", CreatedAt: created},
+	}
+	if err := db.Replace(ctx, tasks); err != nil {
+		t.Fatalf("cannot replace tasks: %v", err)
+	}
+	task, err := db.Create(ctx, &todo.TaskCreate{Summary: "d"})
+	if err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+	if task.ID != "12" || task.Seq != 12 {
+		t.Errorf("want task 12; got: task %s with sequence number %d", task.ID, task.Seq)
+	}
+	for _, sortBy := range []todo.SortBy{todo.SortByCreated, todo.SortByDue} {
+		list, err := db.List(ctx, &todo.ListOptions{SortBy: sortBy})
+		if err != nil {
+			t.Fatalf("cannot list tasks: %v", err)
+		}
+		if got, want := ids(list), []string{"9", "10", "x", "12"}; !slices.Equal(got, want) {
+			t.Errorf("sorted by %v: want tasks: %v; got: %v", sortBy, want, got)
+		}
+	}
+}
+
+func ids(tasks todo.Tasks) []string {
+	ids := make([]string, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+	}
+	return ids
+}
+
+func BenchmarkList50k(b *testing.B) {
+	ctx := context.Background()
+	db := newLargeDB(b, 50000)
+	for _, bm := range []struct {
+		name string
+		opts todo.ListOptions
+	}{
+		{"FirstPage", todo.ListOptions{Limit: 50}},
+		{"LastPage", todo.ListOptions{Offset: 49950, Limit: 50}},
+		{"Starred", todo.ListOptions{Starred: true, Limit: 50}},
+		{"ManualOrder", todo.ListOptions{SortBy: todo.SortByPosition, Limit: 50}},
+		{"ByDue", todo.ListOptions{SortBy: todo.SortByDue, Limit: 50}},
+		{"All", todo.ListOptions{}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := db.List(ctx, &bm.opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package todo
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Revision identifies the state of all tasks in a [TaskRepository]. The
+// repository increments the counter and updates the modification time with
+// each modification of its tasks, see [TaskRepository.Revision].
+type Revision struct {
+	// Counter is the number of modifications since the repository was opened
+	// or its tasks were replaced.
+	Counter uint64
+	// ModifiedAt is the time of the last modification, or of opening the
+	// repository if its tasks have not been modified since.
+	ModifiedAt time.Time
+}
+
+// ETag formats the revision as HTTP entity tag. The modification time is part
+// of the entity tag, so that the counters of two instances of the repository,
+// e.g. before and after restarting the server, don't collide.
+func (r *Revision) ETag() string {
+	return strconv.Quote(fmt.Sprintf("%d-%x", r.Counter, r.ModifiedAt.UnixNano()))
+}
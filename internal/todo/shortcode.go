@@ -0,0 +1,59 @@
+package todo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// ShortCodeLength is the length of the short codes returned by [ShortCode].
+const ShortCodeLength = 7
+
+// ShortCode returns the short code of the task with the specified ID, which is
+// a prefix of the hex-encoded SHA-256 hash of the ID. Unlike the IDs, the
+// short codes have the same length for all storage backends, so they are
+// convenient for referring to tasks on the command line.
+func ShortCode(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])[:ShortCodeLength]
+}
+
+// ResolveTask returns the task that the specified reference refers to. The
+// reference can be the ID of the task, its short code, or a prefix of its short
+// code. Exact matches take precedence over prefix matches. If no task matches,
+// it returns a [TaskNotFoundError]; if several tasks match, it returns an
+// [AmbiguousTaskReferenceError].
+func ResolveTask(tasks Tasks, ref string) (*Task, error) {
+	if ref == "" {
+		return nil, NewTaskNotFoundError(ref)
+	}
+	// The short codes are lowercase, but accept uppercase references, too.
+	lower := strings.ToLower(ref)
+	var exact, prefix []int
+	for i := range tasks {
+		id := tasks[i].ID
+		code := ShortCode(id)
+		switch {
+		case id == ref || code == lower:
+			exact = append(exact, i)
+		case strings.HasPrefix(code, lower):
+			prefix = append(prefix, i)
+		}
+	}
+	matches := exact
+	if len(matches) == 0 {
+		matches = prefix
+	}
+	switch len(matches) {
+	case 0:
+		return nil, NewTaskNotFoundError(ref)
+	case 1:
+		return &tasks[matches[0]], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, m := range matches {
+			ids[i] = tasks[m].ID
+		}
+		return nil, NewAmbiguousTaskReferenceError(ref, ids)
+	}
+}
@@ -0,0 +1,89 @@
+package todo_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+func TestShortCode(t *testing.T) {
+	code := todo.ShortCode("1")
+	if len(code) != todo.ShortCodeLength {
+		t.Errorf("want length: %d; got: %q", todo.ShortCodeLength, code)
+	}
+	if code != todo.ShortCode("1") {
+		t.Error("want short codes to be stable")
+	}
+	if code == todo.ShortCode("2") {
+		t.Error("want different short codes for different IDs")
+	}
+}
+
+func TestResolveTask(t *testing.T) {
+	var tasks todo.Tasks
+	for i := 1; i <= 40; i++ {
+		tasks = append(tasks, todo.Task{ID: strconv.Itoa(i)})
+	}
+	// Find a one-character prefix shared by several tasks, and the shortest
+	// unique prefix of the first task's short code. Neither must be an ID, since
+	// IDs take precedence.
+	isID := func(ref string) bool {
+		n, err := strconv.Atoi(ref)
+		return err == nil && n >= 1 && n <= len(tasks)
+	}
+	counts := make(map[string]int)
+	for _, task := range tasks {
+		for n := 1; n <= todo.ShortCodeLength; n++ {
+			counts[todo.ShortCode(task.ID)[:n]]++
+		}
+	}
+	var shared string
+	for prefix, count := range counts {
+		if len(prefix) == 1 && count > 1 && !isID(prefix) {
+			shared = prefix
+			break
+		}
+	}
+	first := todo.ShortCode("1")
+	unique := first
+	for n := 1; n <= len(first); n++ {
+		if counts[first[:n]] == 1 && !isID(first[:n]) {
+			unique = first[:n]
+			break
+		}
+	}
+
+	tests := []struct {
+		name   string
+		ref    string
+		wantID string
+	}{
+		{"ID", "12", "12"},
+		{"ShortCode", todo.ShortCode("7"), "7"},
+		{"UppercaseShortCode", strings.ToUpper(todo.ShortCode("7")), "7"},
+		{"UniquePrefix", unique, "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := todo.ResolveTask(tasks, tt.ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if task.ID != tt.wantID {
+				t.Errorf("want task: %s; got: %s", tt.wantID, task.ID)
+			}
+		})
+	}
+
+	if _, err := todo.ResolveTask(tasks, shared); !todo.IsAmbiguousTaskReferenceError(err) {
+		t.Errorf("want ambiguous task reference error for '%s'; got: %v", shared, err)
+	}
+	if _, err := todo.ResolveTask(tasks, "zzz"); !todo.IsTaskNotFoundError(err) {
+		t.Errorf("want task not found error; got: %v", err)
+	}
+	if _, err := todo.ResolveTask(tasks, ""); !todo.IsTaskNotFoundError(err) {
+		t.Errorf("want task not found error for empty reference; got: %v", err)
+	}
+}
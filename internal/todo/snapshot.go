@@ -0,0 +1,166 @@
+package todo
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// SnapshotVersion is the version of the snapshot format written by
+// [WriteSnapshot]. It is incremented with each incompatible change.
+const SnapshotVersion = 1
+
+// Snapshot holds the entire content of a [TaskRepository] at a point in time.
+type Snapshot struct {
+	// Version is the version of the snapshot format.
+	Version int `json:"version"`
+	// CreatedAt is the time when the snapshot was taken.
+	CreatedAt time.Time `json:"created_at"`
+	// Tasks are all tasks of the repository.
+	Tasks []SnapshotTask `json:"tasks"`
+}
+
+// SnapshotTask is the representation of a [Task] in a [Snapshot].
+type SnapshotTask struct {
+	ID          string    `json:"id"`
+	Seq         uint64    `json:"seq,omitempty"`
+	UID         string    `json:"uid,omitempty"`
+	Summary     string    `json:"summary"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero"`
+	CompletedAt time.Time `json:"completed_at,omitzero"`
+	DeletedAt   time.Time `json:"deleted_at,omitzero"`
+	DueAt       time.Time `json:"due_at,omitzero"`
+	Version     uint64    `json:"version"`
+	Tags        []string  `json:"tags,omitempty"`
+	Project     string    `json:"project,omitempty"`
+	Position    int64     `json:"position,omitempty"`
+	DependsOn   []string  `json:"depends_on,omitempty"`
+	Recurrence  string    `json:"recurrence,omitempty"`
+	TimeZone    string    `json:"time_zone,omitempty"`
+	Starred     bool      `json:"starred,omitempty"`
+	Assignee    string    `json:"assignee,omitempty"`
+	Links       []string  `json:"links,omitempty"`
+}
+
+// NewSnapshot creates a [Snapshot] of the specified tasks.
+func NewSnapshot(tasks Tasks) *Snapshot {
+	s := &Snapshot{
+		Version:   SnapshotVersion,
+		CreatedAt: time.Now().UTC(),
+		Tasks:     make([]SnapshotTask, len(tasks)),
+	}
+	for i := range tasks {
+		s.Tasks[i] = NewSnapshotTask(&tasks[i])
+	}
+	return s
+}
+
+// TaskList returns the tasks of the snapshot.
+func (s *Snapshot) TaskList() Tasks {
+	tasks := make(Tasks, len(s.Tasks))
+	for i := range s.Tasks {
+		tasks[i] = s.Tasks[i].Task()
+	}
+	return tasks
+}
+
+// NewSnapshotTask creates the representation of the specified task in a
+// [Snapshot].
+func NewSnapshotTask(t *Task) SnapshotTask {
+	return SnapshotTask{
+		ID:          t.ID,
+		Seq:         t.Seq,
+		UID:         t.UID,
+		Summary:     t.Summary,
+		Description: t.Description,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+		CompletedAt: t.CompletedAt,
+		DeletedAt:   t.DeletedAt,
+		DueAt:       t.DueAt,
+		Version:     t.Version,
+		Tags:        t.Tags,
+		Project:     t.Project,
+		Position:    t.Position,
+		DependsOn:   t.DependsOn,
+		Recurrence:  t.Recurrence,
+		TimeZone:    t.TimeZone,
+		Starred:     t.Starred,
+		Assignee:    t.Assignee,
+		Links:       t.Links,
+	}
+}
+
+// Task returns the task represented by t.
+func (t *SnapshotTask) Task() Task {
+	return Task{
+		ID:          t.ID,
+		Seq:         t.Seq,
+		UID:         t.UID,
+		Summary:     t.Summary,
+		Description: t.Description,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+		CompletedAt: t.CompletedAt,
+		DeletedAt:   t.DeletedAt,
+		DueAt:       t.DueAt,
+		Version:     max(t.Version, 1),
+		Tags:        t.Tags,
+		Project:     t.Project,
+		Position:    t.Position,
+		DependsOn:   t.DependsOn,
+		Recurrence:  t.Recurrence,
+		TimeZone:    t.TimeZone,
+		Starred:     t.Starred,
+		Assignee:    t.Assignee,
+		Links:       t.Links,
+	}
+}
+
+// WriteSnapshot writes the specified snapshot as gzip-compressed JSON document
+// to the given writer.
+func WriteSnapshot(w io.Writer, s *Snapshot) error {
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(s); err != nil {
+		return fmt.Errorf("cannot write snapshot: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("cannot write snapshot: %w", err)
+	}
+	return nil
+}
+
+// ReadSnapshot reads a snapshot written by [WriteSnapshot] from the given
+// reader. It fails if the snapshot has an unsupported version.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read snapshot: %w", err)
+	}
+	var s Snapshot
+	if err := json.NewDecoder(zr).Decode(&s); err != nil {
+		return nil, fmt.Errorf("cannot read snapshot: %w", err)
+	}
+	if err := zr.Close(); err != nil {
+		return nil, fmt.Errorf("cannot read snapshot: %w", err)
+	}
+	if s.Version < 1 || s.Version > SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version: %d", s.Version)
+	}
+	ids := make(map[string]bool, len(s.Tasks))
+	for _, t := range s.Tasks {
+		if t.ID == "" {
+			return nil, errors.New("invalid snapshot: task without ID")
+		}
+		if ids[t.ID] {
+			return nil, fmt.Errorf("invalid snapshot: duplicate task ID '%s'", t.ID)
+		}
+		ids[t.ID] = true
+	}
+	return &s, nil
+}
@@ -0,0 +1,141 @@
+package todo
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+)
+
+// Stats are statistics aggregated over the tasks in a [TaskRepository]. Tasks
+// that have been moved to the trash are not counted.
+type Stats struct {
+	// Open is the number of tasks that have not been completed yet.
+	Open int
+	// Completed is the number of completed tasks.
+	Completed int
+	// CompletedToday is the number of tasks completed since midnight.
+	CompletedToday int
+	// CompletedThisWeek is the number of tasks completed since Monday
+	// midnight.
+	CompletedThisWeek int
+	// Overdue is the number of tasks that are overdue, see [Task.IsOverdue].
+	Overdue int
+	// AverageCompletionTime is the average time from the creation to the
+	// completion of the completed tasks, or zero if there are none.
+	AverageCompletionTime time.Duration
+	// Tags holds the number of tasks per tag.
+	Tags map[string]GroupStats
+	// Projects holds the number of tasks per project. Tasks without project
+	// are not counted.
+	Projects map[string]GroupStats
+}
+
+// GroupStats is the number of tasks in a group of tasks, e.g. with the same
+// tag.
+type GroupStats struct {
+	// Open is the number of tasks in the group that have not been completed
+	// yet.
+	Open int
+	// Completed is the number of completed tasks in the group.
+	Completed int
+}
+
+// ComputeStats aggregates the statistics of the specified tasks at the
+// specified time. Days and weeks start at midnight in the location of now.
+// Repositories without a more efficient way to aggregate the tasks can use it
+// to implement [TaskRepository.Stats].
+func ComputeStats(tasks Tasks, now time.Time) *Stats {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// Weeks start on Monday, but time.Weekday starts on Sunday.
+	week := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+
+	stats := &Stats{
+		Tags:     make(map[string]GroupStats),
+		Projects: make(map[string]GroupStats),
+	}
+	var completionTime time.Duration
+	for i := range tasks {
+		t := &tasks[i]
+		if !t.DeletedAt.IsZero() {
+			continue
+		}
+		completed := !t.CompletedAt.IsZero()
+		if completed {
+			stats.Completed++
+			completionTime += t.CompletedAt.Sub(t.CreatedAt)
+			if !t.CompletedAt.Before(today) {
+				stats.CompletedToday++
+			}
+			if !t.CompletedAt.Before(week) {
+				stats.CompletedThisWeek++
+			}
+		} else {
+			stats.Open++
+		}
+		if t.IsOverdue(now) {
+			stats.Overdue++
+		}
+		for _, tag := range t.Tags {
+			stats.Tags[tag] = stats.Tags[tag].add(completed)
+		}
+		if t.Project != "" {
+			stats.Projects[t.Project] = stats.Projects[t.Project].add(completed)
+		}
+	}
+	if stats.Completed > 0 {
+		stats.AverageCompletionTime = completionTime / time.Duration(stats.Completed)
+	}
+	return stats
+}
+
+// add returns the group statistics with one more open or completed task.
+func (g GroupStats) add(completed bool) GroupStats {
+	if completed {
+		g.Completed++
+	} else {
+		g.Open++
+	}
+	return g
+}
+
+func (s *Stats) toProto() *todopb.GetStatsResponse {
+	resp := &todopb.GetStatsResponse{
+		OpenCount:              count32(s.Open),
+		CompletedCount:         count32(s.Completed),
+		CompletedTodayCount:    count32(s.CompletedToday),
+		CompletedThisWeekCount: count32(s.CompletedThisWeek),
+		OverdueCount:           count32(s.Overdue),
+		Tags:                   groupStatsToProtos(s.Tags),
+		Projects:               groupStatsToProtos(s.Projects),
+	}
+	if s.Completed > 0 {
+		resp.AverageCompletionTime = durationpb.New(s.AverageCompletionTime)
+	}
+	return resp
+}
+
+// groupStatsToProtos converts the specified group statistics into protobuf
+// messages, ordered by the name of the group.
+func groupStatsToProtos(groups map[string]GroupStats) []*todopb.GroupStats {
+	protos := make([]*todopb.GroupStats, 0, len(groups))
+	for _, name := range slices.Sorted(maps.Keys(groups)) {
+		g := groups[name]
+		protos = append(protos, &todopb.GroupStats{
+			Name:           name,
+			OpenCount:      count32(g.Open),
+			CompletedCount: count32(g.Completed),
+		})
+	}
+	return protos
+}
+
+// count32 converts the specified number of tasks into a uint32, capping it at
+// the largest uint32.
+func count32(n int) uint32 {
+	return uint32(min(max(n, 0), math.MaxUint32))
+}
@@ -0,0 +1,45 @@
+package todo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeStats(t *testing.T) {
+	// A Wednesday, when the week started two and a half days ago.
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	tasks := Tasks{
+		{ID: "1", CreatedAt: now.Add(-2 * time.Hour), CompletedAt: now.Add(-time.Hour)},
+		{ID: "2", CreatedAt: now.AddDate(0, 0, -3), CompletedAt: now.AddDate(0, 0, -2).Add(-12 * time.Hour)},
+		{ID: "3", CreatedAt: now.AddDate(0, 0, -3), CompletedAt: now.AddDate(0, 0, -2).Add(-12*time.Hour - time.Second)},
+		{ID: "4", CreatedAt: now, DueAt: now.Add(-time.Minute)},
+		{ID: "5", CreatedAt: now, CompletedAt: now, DeletedAt: now},
+	}
+	stats := ComputeStats(tasks, now)
+	if stats.Open != 1 || stats.Completed != 3 || stats.Overdue != 1 {
+		t.Errorf("want 1 open, 3 completed, and 1 overdue task; got: %+v", stats)
+	}
+	if stats.CompletedToday != 1 {
+		t.Errorf("want 1 task completed today; got: %d", stats.CompletedToday)
+	}
+	if stats.CompletedThisWeek != 2 {
+		t.Errorf("want 2 tasks completed this week; got: %d", stats.CompletedThisWeek)
+	}
+	// (1h + 12h + 12h - 1s) / 3
+	if want := (25*time.Hour - time.Second) / 3; stats.AverageCompletionTime != want {
+		t.Errorf("want average completion time: %v; got: %v", want, stats.AverageCompletionTime)
+	}
+}
+
+func TestComputeStatsWeekStartsOnMonday(t *testing.T) {
+	// A Sunday, when the week started six days ago.
+	now := time.Date(2025, 1, 19, 12, 0, 0, 0, time.UTC)
+	monday := time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)
+	tasks := Tasks{
+		{ID: "1", CompletedAt: monday},
+		{ID: "2", CompletedAt: monday.Add(-time.Second)},
+	}
+	if got := ComputeStats(tasks, now).CompletedThisWeek; got != 1 {
+		t.Errorf("want 1 task completed this week; got: %d", got)
+	}
+}
@@ -0,0 +1,232 @@
+package todo
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+)
+
+// ErrSyncUnsupported is returned by the repositories that cannot be
+// synchronized with another to-do list.
+var ErrSyncUnsupported = errors.New("the storage backend does not support synchronization")
+
+// SyncRepository is a [TaskRepository] that can be synchronized with the
+// repository of another to-do list, e.g. of the To-do Daemon on another
+// computer. The tasks are matched by their [Task.UID], and the most recent
+// change of each task wins. Deleted tasks are kept in the trash as tombstones,
+// so their deletion is synchronized, too.
+type SyncRepository interface {
+	TaskRepository
+	// Changes retrieves the tasks that were created, updated, or deleted
+	// after the specified time, see [ChangedAt], including the tasks in the
+	// trash. It also returns the time up to which the changes are included,
+	// which is the since time of the next call. The DependsOn of the returned
+	// tasks hold UIDs instead of IDs.
+	Changes(ctx context.Context, since time.Time) (Tasks, time.Time, error)
+	// Merge merges the specified tasks retrieved from another repository with
+	// Changes into the repository, whose IDs, positions, and versions are
+	// ignored. Each task either replaces the task with the same UID, if it was
+	// changed more recently, or is added as a new task. Tasks changed in both
+	// repositories after the specified time are reported as conflicts.
+	Merge(ctx context.Context, tasks Tasks, since time.Time) (*MergeResult, error)
+}
+
+// MergeResult is the result of [SyncRepository.Merge].
+type MergeResult struct {
+	// Applied holds the events for the tasks that were created, updated, or
+	// deleted by the merge, like the events published for regular changes.
+	Applied []Event
+	// Conflicts are the tasks that were changed in both repositories.
+	Conflicts []MergeConflict
+}
+
+// MergeConflict describes a task that was changed in both of two synchronized
+// repositories.
+type MergeConflict struct {
+	// Task is the task after resolving the conflict.
+	Task Task
+	// RemoteWins reports whether the change in the other repository was more
+	// recent and was applied, rather than the local change being kept.
+	RemoteWins bool
+	// LocalChangedAt and RemoteChangedAt are the times of the changes.
+	LocalChangedAt  time.Time
+	RemoteChangedAt time.Time
+}
+
+// NewUID returns a new random UID for a task, see [Task.UID].
+func NewUID() string {
+	return rand.Text()
+}
+
+// ChangedAt returns the time of the last change of the specified task, i.e.
+// the latest of its creation, last update, and deletion time.
+func ChangedAt(t *Task) time.Time {
+	changed := t.CreatedAt
+	for _, u := range []time.Time{t.UpdatedAt, t.DeletedAt} {
+		if u.After(changed) {
+			changed = u
+		}
+	}
+	return changed
+}
+
+// resolve decides whether the remote state of a task replaces its local state,
+// and whether the task was changed in both repositories after the specified
+// time. The more recent change wins; if both changes happened at the same
+// time, the greater summary wins, so both repositories agree on the winner.
+func resolve(local, remote *Task, since time.Time) (remoteWins, conflict bool) {
+	if sameContent(local, remote) {
+		return false, false
+	}
+	lc, rc := ChangedAt(local), ChangedAt(remote)
+	switch c := rc.Compare(lc); {
+	case c > 0:
+		remoteWins = true
+	case c == 0:
+		remoteWins = remote.Summary > local.Summary
+	}
+	return remoteWins, lc.After(since) && rc.After(since)
+}
+
+// sameContent checks if the specified tasks have the same content, apart from
+// their IDs, times of changes, positions, and versions.
+func sameContent(a, b *Task) bool {
+	return a.Summary == b.Summary &&
+		a.Description == b.Description &&
+		a.CompletedAt.Equal(b.CompletedAt) &&
+		a.DueAt.Equal(b.DueAt) &&
+		a.DeletedAt.IsZero() == b.DeletedAt.IsZero() &&
+		slices.Equal(a.Tags, b.Tags) &&
+		a.Project == b.Project &&
+		slices.Equal(a.DependsOn, b.DependsOn) &&
+		a.Recurrence == b.Recurrence &&
+		a.TimeZone == b.TimeZone &&
+		a.Starred == b.Starred &&
+		a.Assignee == b.Assignee &&
+		slices.Equal(a.Links, b.Links)
+}
+
+// takeRemote copies the content and the times of the changes of the remote
+// task, whose DependsOn must hold local IDs, to the local task.
+func takeRemote(local *Task, remote *Task) {
+	local.Summary = remote.Summary
+	local.Description = remote.Description
+	local.UpdatedAt = remote.UpdatedAt
+	local.CompletedAt = remote.CompletedAt
+	local.DeletedAt = remote.DeletedAt
+	local.DueAt = InTimeZone(remote.DueAt, remote.TimeZone)
+	local.Tags = slices.Clone(remote.Tags)
+	local.Project = remote.Project
+	local.DependsOn = slices.Clone(remote.DependsOn)
+	local.Recurrence = remote.Recurrence
+	local.TimeZone = remote.TimeZone
+	local.Starred = remote.Starred
+	local.Assignee = remote.Assignee
+	local.Links = slices.Clone(remote.Links)
+	// The local change time must not be before the remote one, or the remote
+	// change would be applied again with each synchronization.
+	if rc := ChangedAt(remote); ChangedAt(local).Before(rc) {
+		local.UpdatedAt = rc
+	}
+}
+
+// mergeEvents returns the events describing how merging changed the specified
+// existing task, whose state before merging is old, like the events published
+// for regular changes: a deletion, or an update followed by an assignment if
+// the task was assigned to another user and a completion if the task was
+// completed.
+func mergeEvents(old, merged *Task, now time.Time) []Event {
+	if old.DeletedAt.IsZero() && !merged.DeletedAt.IsZero() {
+		return []Event{{Type: EventTaskDeleted, Task: *merged, Time: now}}
+	}
+	events := []Event{{Type: EventTaskUpdated, Task: *merged, Time: now}}
+	if merged.Assignee != "" && merged.Assignee != old.Assignee {
+		events = append(events, Event{Type: EventTaskAssigned, Task: *merged, Time: now})
+	}
+	if old.CompletedAt.IsZero() && !merged.CompletedAt.IsZero() {
+		events = append(events, Event{Type: EventTaskCompleted, Task: *merged, Time: now})
+	}
+	return events
+}
+
+// newTaskChangeProto converts a task returned by [SyncRepository.Changes] into
+// its protobuf representation, whose dependencies are UIDs.
+func newTaskChangeProto(t *Task) *todopb.TaskChange {
+	task := t.toProto()
+	task.DependsOn, task.BlockedBy, task.Blocked = nil, nil, false
+	return &todopb.TaskChange{
+		Task:          task,
+		DeletedAt:     optionalTimestamp(t.DeletedAt),
+		DependsOnUids: t.DependsOn,
+	}
+}
+
+// newTasksFromChangeProtos converts the changes pushed by another to-do list
+// into tasks for [SyncRepository.Merge]. The tasks must have UIDs and valid
+// fields like new tasks.
+func newTasksFromChangeProtos(changes []*todopb.TaskChange) (Tasks, error) {
+	v := validator{subject: "changes"}
+	tasks := make(Tasks, len(changes))
+	for i, c := range changes {
+		p := c.GetTask()
+		path := fmt.Sprintf("changes[%d].task", i)
+		if p.GetUid() == "" {
+			v.addf(path+".uid", "must not be empty")
+		}
+		create := &TaskCreate{
+			Summary:     p.GetSummary(),
+			Description: p.GetDescription(),
+			DueAt:       optionalTime(p.GetDueAt()),
+			Tags:        p.GetTags(),
+			Project:     p.GetProject(),
+			Recurrence:  p.GetRecurrence(),
+			TimeZone:    p.GetTimeZone(),
+			Assignee:    p.GetAssignee(),
+			Links:       p.GetLinks(),
+		}
+		var e *ValidationError
+		if errors.As(create.Validate(), &e) {
+			v.violations = append(v.violations, e.withPrefix(path).Violations...)
+		}
+		tasks[i] = Task{
+			UID:         p.GetUid(),
+			Summary:     p.GetSummary(),
+			Description: p.GetDescription(),
+			CreatedAt:   optionalTime(p.GetCreatedAt()),
+			UpdatedAt:   optionalTime(p.GetUpdatedAt()),
+			CompletedAt: optionalTime(p.GetCompletedAt()),
+			DeletedAt:   optionalTime(c.GetDeletedAt()),
+			DueAt:       create.DueAt,
+			Tags:        p.GetTags(),
+			Project:     p.GetProject(),
+			DependsOn:   c.GetDependsOnUids(),
+			Recurrence:  p.GetRecurrence(),
+			TimeZone:    p.GetTimeZone(),
+			Starred:     p.GetStarred(),
+			Assignee:    p.GetAssignee(),
+			Links:       p.GetLinks(),
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (c *MergeConflict) toProto() *todopb.SyncConflict {
+	resolution := todopb.SyncConflict_RESOLUTION_LOCAL_WINS
+	if c.RemoteWins {
+		resolution = todopb.SyncConflict_RESOLUTION_REMOTE_WINS
+	}
+	return &todopb.SyncConflict{
+		Task:            c.Task.toProto(),
+		Resolution:      resolution,
+		LocalChangedAt:  optionalTimestamp(c.LocalChangedAt),
+		RemoteChangedAt: optionalTimestamp(c.RemoteChangedAt),
+	}
+}
@@ -0,0 +1,176 @@
+package todo_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// syncDBs merges the changes of src since the specified time into dst and
+// returns the result and the time to pass as since next time.
+func syncDBs(t *testing.T, dst, src *todo.InMemoryTaskDB, since time.Time) (*todo.MergeResult, time.Time) {
+	t.Helper()
+	ctx := context.Background()
+	changes, now, err := src.Changes(ctx, since)
+	if err != nil {
+		t.Fatalf("cannot retrieve changes: %v", err)
+	}
+	result, err := dst.Merge(ctx, changes, since)
+	if err != nil {
+		t.Fatalf("cannot merge changes: %v", err)
+	}
+	return result, now
+}
+
+func mustGetSummary(t *testing.T, db *todo.InMemoryTaskDB, id string) string {
+	t.Helper()
+	task, err := db.Get(context.Background(), id)
+	if err != nil {
+		t.Fatalf("cannot get task %s: %v", id, err)
+	}
+	return task.Summary
+}
+
+func TestMergeNewTasks(t *testing.T) {
+	ctx := context.Background()
+	laptop, desktop := todo.NewInMemoryTaskDB(), todo.NewInMemoryTaskDB()
+	if _, err := desktop.Create(ctx, &todo.TaskCreate{Summary: "desktop"}); err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+	buy, err := laptop.Create(ctx, &todo.TaskCreate{Summary: "buy paint"})
+	if err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+	if _, err := laptop.Create(ctx, &todo.TaskCreate{Summary: "paint fence", DependsOn: []string{buy.ID}}); err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+
+	result, _ := syncDBs(t, desktop, laptop, time.Time{})
+	if len(result.Applied) != 2 || len(result.Conflicts) != 0 {
+		t.Fatalf("want 2 applied changes, no conflicts; got: %+v", result)
+	}
+	tasks, err := desktop.List(ctx, &todo.ListOptions{SortBy: todo.SortByPosition})
+	if err != nil {
+		t.Fatalf("cannot list tasks: %v", err)
+	}
+	if got, want := ids(tasks), []string{"1", "2", "3"}; !slices.Equal(got, want) {
+		t.Fatalf("want tasks %v; got: %v", want, got)
+	}
+	if tasks[1].UID != buy.UID || tasks[1].Summary != "buy paint" {
+		t.Errorf("want merged task with UID %s; got: %+v", buy.UID, tasks[1])
+	}
+	if !slices.Equal(tasks[2].DependsOn, []string{"2"}) {
+		t.Errorf("want dependency translated to ID 2; got: %v", tasks[2].DependsOn)
+	}
+}
+
+func TestMergeLastWriterWins(t *testing.T) {
+	ctx := context.Background()
+	laptop, desktop := todo.NewInMemoryTaskDB(), todo.NewInMemoryTaskDB()
+	for _, summary := range []string{"a", "b"} {
+		if _, err := laptop.Create(ctx, &todo.TaskCreate{Summary: summary}); err != nil {
+			t.Fatalf("cannot create task: %v", err)
+		}
+	}
+	syncDBs(t, desktop, laptop, time.Time{})
+	_, since := syncDBs(t, laptop, desktop, time.Time{})
+
+	// Task 1 is changed on the laptop only, task 2 on both, most recently on
+	// the desktop.
+	for _, u := range []struct {
+		db      *todo.InMemoryTaskDB
+		id      string
+		summary string
+	}{
+		{laptop, "1", "a (laptop)"},
+		{laptop, "2", "b (laptop)"},
+		{desktop, "2", "b (desktop)"},
+	} {
+		if _, err := u.db.Update(ctx, u.id, &todo.TaskUpdate{Summary: &u.summary}); err != nil {
+			t.Fatalf("cannot update task: %v", err)
+		}
+	}
+
+	result, _ := syncDBs(t, desktop, laptop, since)
+	if got := mustGetSummary(t, desktop, "1"); got != "a (laptop)" {
+		t.Errorf("want change from laptop applied; got: %s", got)
+	}
+	if got := mustGetSummary(t, desktop, "2"); got != "b (desktop)" {
+		t.Errorf("want more recent change kept; got: %s", got)
+	}
+	if len(result.Conflicts) != 1 {
+		t.Fatalf("want 1 conflict; got: %+v", result.Conflicts)
+	}
+	if c := result.Conflicts[0]; c.Task.ID != "2" || c.RemoteWins {
+		t.Errorf("want conflict for task 2 resolved locally; got: %+v", c)
+	}
+
+	result, _ = syncDBs(t, laptop, desktop, since)
+	if got := mustGetSummary(t, laptop, "2"); got != "b (desktop)" {
+		t.Errorf("want more recent change applied; got: %s", got)
+	}
+	if len(result.Conflicts) != 1 || !result.Conflicts[0].RemoteWins {
+		t.Errorf("want 1 conflict resolved remotely; got: %+v", result.Conflicts)
+	}
+}
+
+func TestMergeTombstones(t *testing.T) {
+	ctx := context.Background()
+	laptop, desktop := todo.NewInMemoryTaskDB(), todo.NewInMemoryTaskDB()
+	for _, summary := range []string{"a", "b"} {
+		if _, err := laptop.Create(ctx, &todo.TaskCreate{Summary: summary}); err != nil {
+			t.Fatalf("cannot create task: %v", err)
+		}
+	}
+	_, since := syncDBs(t, desktop, laptop, time.Time{})
+	if err := laptop.Delete(ctx, "1"); err != nil {
+		t.Fatalf("cannot delete task: %v", err)
+	}
+
+	changes, _, err := laptop.Changes(ctx, since)
+	if err != nil {
+		t.Fatalf("cannot retrieve changes: %v", err)
+	}
+	if len(changes) != 1 || changes[0].ID != "1" || changes[0].DeletedAt.IsZero() {
+		t.Fatalf("want only the deleted task 1 as change; got: %+v", changes)
+	}
+	result, err := desktop.Merge(ctx, changes, since)
+	if err != nil {
+		t.Fatalf("cannot merge changes: %v", err)
+	}
+	if len(result.Applied) != 1 || result.Applied[0].Type != todo.EventTaskDeleted {
+		t.Errorf("want deleted event; got: %+v", result.Applied)
+	}
+	if _, err := desktop.Get(ctx, "1"); !todo.IsTaskNotFoundError(err) {
+		t.Errorf("want deleted task not found; got: %v", err)
+	}
+	trash, err := desktop.List(ctx, &todo.ListOptions{IncludeDeleted: true})
+	if err != nil {
+		t.Fatalf("cannot list tasks: %v", err)
+	}
+	if len(trash) != 2 {
+		t.Errorf("want deleted task kept in trash; got: %v", ids(trash))
+	}
+
+	// A task deleted before it was ever synchronized is not created.
+	c, err := laptop.Create(ctx, &todo.TaskCreate{Summary: "c"})
+	if err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+	if err := laptop.Delete(ctx, c.ID); err != nil {
+		t.Fatalf("cannot delete task: %v", err)
+	}
+	if result, _ := syncDBs(t, desktop, laptop, since); len(result.Applied) != 0 {
+		t.Errorf("want no changes applied again; got: %+v", result.Applied)
+	}
+}
+
+func TestMergeWithoutUID(t *testing.T) {
+	db := todo.NewInMemoryTaskDB()
+	if _, err := db.Merge(context.Background(), todo.Tasks{{Summary: "a"}}, time.Time{}); err == nil {
+		t.Error("want task without UID to fail")
+	}
+}
@@ -1,6 +1,7 @@
 package todo
 
 import (
+	"slices"
 	"time"
 
 	"google.golang.org/protobuf/types/known/fieldmaskpb"
@@ -11,25 +12,166 @@ import (
 
 // Task represents a single to-do item.
 type Task struct {
-	ID          string
+	ID string
+	// Seq is the sequence number of the task, which the repository increments
+	// with each task it creates. It breaks ties when sorting tasks, e.g. of
+	// tasks created at the same time, so their order is deterministic.
+	Seq         uint64
 	Summary     string
+	Description string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	CompletedAt time.Time
 	DeletedAt   time.Time
+	DueAt       time.Time
+	// Version is incremented with each update of the task, starting at 1.
+	Version uint64
+	// Tags are the tags of the task, e.g. "errands".
+	Tags []string
+	// Project is the project the task belongs to, if any.
+	Project string
+	// Position is the position of the task in the manual order of the to-do
+	// list, which is maintained by the repository. New tasks come last.
+	Position int64
+	// DependsOn holds the IDs of the tasks that must be completed before this
+	// task, see [CheckDependencies].
+	DependsOn []string
+	// BlockedBy holds the IDs of the tasks in DependsOn that are still open.
+	// It is computed by the repository whenever the task is retrieved, see
+	// [OpenDependencies].
+	BlockedBy []string
+	// Recurrence is the rule that the task recurs by, if any, see
+	// [ParseRecurrence].
+	Recurrence string
+	// TimeZone is the IANA name of the time zone that the task's times refer
+	// to, e.g. "Europe/Berlin". If empty, the local time zone is used. The
+	// repository returns DueAt in this time zone, see [InTimeZone].
+	TimeZone string
+	// Starred specifies whether the task is starred. Starred tasks are listed
+	// first when the tasks are sorted by creation time.
+	Starred bool
+	// UID is the globally unique ID of the task, which identifies the task
+	// across synchronized to-do lists, whereas the ID is only unique within
+	// its to-do list, see [SyncRepository].
+	UID string
+	// Assignee is the user the task is assigned to, if any, e.g. "alice".
+	Assignee string
+	// Links are the URLs of web pages related to the task, e.g. tickets or
+	// documents. Each link is an absolute http or https URL.
+	Links []string
 }
 
 // Tasks is a list of to-do items.
 type Tasks []Task
 
+// SameContent checks if the task has the same content as the specified task,
+// apart from its version and update time, which a change by another program
+// than the To-do Daemon may not have incremented, and the fields computed by
+// the repository.
+func (t *Task) SameContent(u *Task) bool {
+	return t.ID == u.ID &&
+		t.UID == u.UID &&
+		t.Summary == u.Summary &&
+		t.Description == u.Description &&
+		t.CreatedAt.Equal(u.CreatedAt) &&
+		t.CompletedAt.Equal(u.CompletedAt) &&
+		t.DeletedAt.Equal(u.DeletedAt) &&
+		t.DueAt.Equal(u.DueAt) &&
+		slices.Equal(t.Tags, u.Tags) &&
+		t.Project == u.Project &&
+		t.Position == u.Position &&
+		slices.Equal(t.DependsOn, u.DependsOn) &&
+		t.Recurrence == u.Recurrence &&
+		t.TimeZone == u.TimeZone &&
+		t.Starred == u.Starred &&
+		t.Assignee == u.Assignee &&
+		slices.Equal(t.Links, u.Links)
+}
+
+// IsBlocked checks if the task depends on tasks that are still open.
+func (t *Task) IsBlocked() bool {
+	return len(t.BlockedBy) > 0
+}
+
+// IsOverdue checks if the task is due before the specified time but has not
+// been completed yet.
+func (t *Task) IsOverdue(now time.Time) bool {
+	return !t.DueAt.IsZero() && t.DueAt.Before(now) && t.CompletedAt.IsZero()
+}
+
 func (t *Task) toProto() *todopb.Task {
 	return &todopb.Task{
 		Id:          t.ID,
 		Summary:     t.Summary,
+		Description: t.Description,
 		CreatedAt:   timestamppb.New(t.CreatedAt),
-		UpdatedAt:   timestamppb.New(t.UpdatedAt),
-		CompletedAt: timestamppb.New(t.CompletedAt),
+		UpdatedAt:   optionalTimestamp(t.UpdatedAt),
+		CompletedAt: optionalTimestamp(t.CompletedAt),
+		DueAt:       optionalTimestamp(t.DueAt),
+		Version:     t.Version,
+		ShortCode:   ShortCode(t.ID),
+		Tags:        t.Tags,
+		Project:     t.Project,
+		Position:    t.Position,
+		DependsOn:   t.DependsOn,
+		BlockedBy:   t.BlockedBy,
+		Blocked:     t.IsBlocked(),
+		Recurrence:  t.Recurrence,
+		TimeZone:    t.TimeZone,
+		DueAtLocal:  optionalRFC3339(t.DueAt),
+		Starred:     t.Starred,
+		Uid:         t.UID,
+		Assignee:    t.Assignee,
+		Completed:   !t.CompletedAt.IsZero(),
+		Links:       t.Links,
+	}
+}
+
+// InTimeZone returns the specified time in the time zone with the specified
+// IANA name, or in the local time zone if the name is empty or unknown.
+func InTimeZone(t time.Time, zone string) time.Time {
+	if zone != "" {
+		if loc, err := time.LoadLocation(zone); err == nil {
+			return t.In(loc)
+		}
 	}
+	return t.Local()
+}
+
+// timeZoneName returns the IANA name of the specified time zone, or "" for the
+// local time zone, whose name is unknown.
+func timeZoneName(loc *time.Location) string {
+	if name := loc.String(); name != "Local" {
+		return name
+	}
+	return ""
+}
+
+// optionalRFC3339 formats the specified time as an RFC 3339 timestamp with the
+// offset of its location, or returns "" if the time is zero.
+func optionalRFC3339(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// optionalTimestamp converts the specified time into a protobuf timestamp, or
+// nil if the time is zero.
+func optionalTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// optionalTime converts the specified protobuf timestamp into a time, or the
+// zero time if the timestamp is nil.
+func optionalTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
 }
 
 func (ts Tasks) toProtos() []*todopb.Task {
@@ -44,19 +186,69 @@ func (ts Tasks) toProtos() []*todopb.Task {
 type TaskCreate struct {
 	// Summary is a concise description of the task.
 	Summary string
+	// Description is an optional, more detailed description of the task.
+	Description string
+	// DueAt is the optional time when the task is due.
+	DueAt time.Time
+	// Tags are the optional tags of the task.
+	Tags []string
+	// Project is the optional project the task belongs to.
+	Project string
+	// DependsOn holds the IDs of the tasks that must be completed before the
+	// task, if any.
+	DependsOn []string
+	// Recurrence is the optional rule that the task recurs by.
+	Recurrence string
+	// TimeZone is the optional IANA name of the time zone that the task's
+	// times refer to.
+	TimeZone string
+	// Starred specifies whether the task is starred.
+	Starred bool
+	// Assignee is the optional user the task is assigned to.
+	Assignee string
+	// Links are the optional URLs of web pages related to the task.
+	Links []string
+	// AllowDuplicate exempts the task from the check that its summary differs
+	// from the summaries of the open tasks, see [WithUniqueSummaries]. It is
+	// not stored with the task.
+	AllowDuplicate bool
 }
 
 func newTaskCreateFromProto(proto *todopb.NewTask) *TaskCreate {
 	return &TaskCreate{
-		Summary: proto.GetSummary(),
+		Summary:        proto.GetSummary(),
+		Description:    proto.GetDescription(),
+		DueAt:          optionalTime(proto.GetDueAt()),
+		Tags:           proto.GetTags(),
+		Project:        proto.GetProject(),
+		DependsOn:      proto.GetDependsOn(),
+		Recurrence:     proto.GetRecurrence(),
+		TimeZone:       proto.GetTimeZone(),
+		Starred:        proto.GetStarred(),
+		Assignee:       proto.GetAssignee(),
+		Links:          proto.GetLinks(),
+		AllowDuplicate: proto.GetAllowDuplicate(),
 	}
 }
 
 // TaskUpdate represents an modification to a task, which can include changing
-// the summary or marking the task as completed.
+// the summary or description, or marking the task as completed.
 type TaskUpdate struct {
 	Summary     *string
+	Description *string
 	CompletedAt *time.Time
+	DueAt       *time.Time
+	Tags        *[]string
+	Project     *string
+	DependsOn   *[]string
+	Recurrence  *string
+	TimeZone    *string
+	Starred     *bool
+	Assignee    *string
+	Links       *[]string
+	// ExpectedVersion is the version the task must have for the update to be
+	// applied. Zero means that the update is applied unconditionally.
+	ExpectedVersion uint64
 }
 
 func newTaskUpdateFromProto(proto *todopb.TaskUpdate, fields *fieldmaskpb.FieldMask) *TaskUpdate {
@@ -66,10 +258,55 @@ func newTaskUpdateFromProto(proto *todopb.TaskUpdate, fields *fieldmaskpb.FieldM
 		case "summary":
 			summary := proto.GetSummary()
 			u.Summary = &summary
+		case "description":
+			description := proto.GetDescription()
+			u.Description = &description
 		case "completed_at":
-			completedAt := proto.GetCompletedAt().AsTime()
+			completedAt := optionalTime(proto.GetCompletedAt())
 			u.CompletedAt = &completedAt
+		case "due_at":
+			dueAt := optionalTime(proto.GetDueAt())
+			u.DueAt = &dueAt
+		case "tags":
+			tags := proto.GetTags()
+			u.Tags = &tags
+		case "project":
+			project := proto.GetProject()
+			u.Project = &project
+		case "depends_on":
+			dependsOn := proto.GetDependsOn()
+			u.DependsOn = &dependsOn
+		case "recurrence":
+			recurrence := proto.GetRecurrence()
+			u.Recurrence = &recurrence
+		case "time_zone":
+			timeZone := proto.GetTimeZone()
+			u.TimeZone = &timeZone
+		case "starred":
+			starred := proto.GetStarred()
+			u.Starred = &starred
+		case "assignee":
+			assignee := proto.GetAssignee()
+			u.Assignee = &assignee
+		case "links":
+			links := proto.GetLinks()
+			u.Links = &links
 		}
 	}
 	return u
 }
+
+// SearchResult is a task matching a search query.
+type SearchResult struct {
+	// Task is the matching task.
+	Task Task
+	// Score is the relevance of the task; higher is more relevant.
+	Score float64
+}
+
+func (r *SearchResult) toProto() *todopb.SearchResult {
+	return &todopb.SearchResult{
+		Task:  r.Task.toProto(),
+		Score: r.Score,
+	}
+}
@@ -0,0 +1,121 @@
+package todo
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+)
+
+func TestTaskToProto(t *testing.T) {
+	createdAt := time.Date(2025, 12, 24, 18, 0, 0, 0, time.UTC)
+	task := &Task{ID: "1", Summary: "Buy presents", CreatedAt: createdAt}
+	p := task.toProto()
+	if !p.GetCreatedAt().AsTime().Equal(createdAt) {
+		t.Errorf("want creation time %s; got: %v", createdAt, p.GetCreatedAt())
+	}
+	if p.GetUpdatedAt() != nil || p.GetCompletedAt() != nil || p.GetDueAt() != nil {
+		t.Errorf("want unset times to be nil; got: %v", p)
+	}
+	if p.GetCompleted() {
+		t.Error("want open task; got completed task")
+	}
+
+	completedAt := createdAt.Add(time.Hour)
+	task.UpdatedAt, task.CompletedAt = completedAt, completedAt
+	p = task.toProto()
+	if !p.GetCompletedAt().AsTime().Equal(completedAt) || !p.GetUpdatedAt().AsTime().Equal(completedAt) {
+		t.Errorf("want update and completion at %s; got: %v", completedAt, p)
+	}
+	if !p.GetCompleted() {
+		t.Error("want completed task; got open task")
+	}
+}
+
+// updatedFields returns the names of the fields set by the specified update,
+// as named in the field masks of version 1 of the API.
+func updatedFields(u *TaskUpdate) []string {
+	var fields []string
+	for name, set := range map[string]bool{
+		"summary":      u.Summary != nil,
+		"description":  u.Description != nil,
+		"completed_at": u.CompletedAt != nil,
+		"due_at":       u.DueAt != nil,
+		"tags":         u.Tags != nil,
+		"project":      u.Project != nil,
+		"depends_on":   u.DependsOn != nil,
+		"recurrence":   u.Recurrence != nil,
+		"time_zone":    u.TimeZone != nil,
+		"starred":      u.Starred != nil,
+		"assignee":     u.Assignee != nil,
+		"links":        u.Links != nil,
+	} {
+		if set {
+			fields = append(fields, name)
+		}
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// FuzzNewTaskCreateFromJSON decodes request bodies to create tasks like the
+// gRPC gateway does, and checks that valid tasks have a summary.
+func FuzzNewTaskCreateFromJSON(f *testing.F) {
+	f.Add(`{"summary":"Buy milk"}`)
+	f.Add(`{"summary":"Buy milk","tags":["errands"],"dueAt":"2025-12-24T18:00:00Z","dependsOn":["1"]}`)
+	f.Add(`{"summary":"","recurrence":"FREQ=DAILY","timeZone":"Europe/Berlin"}`)
+	f.Add(`{"summary":"\u0000","unknown":true}`)
+	f.Add(`{"dueAt":"0001-01-01T00:00:00Z"}`)
+	f.Fuzz(func(t *testing.T, body string) {
+		var proto todopb.NewTask
+		if err := protojson.Unmarshal([]byte(body), &proto); err != nil {
+			return
+		}
+		create := newTaskCreateFromProto(&proto)
+		if err := create.Validate(); err != nil {
+			return
+		}
+		if strings.TrimSpace(create.Summary) == "" || !utf8.ValidString(create.Summary) {
+			t.Errorf("want invalid summary %q to be rejected", create.Summary)
+		}
+	})
+}
+
+// FuzzNewTaskUpdateFromProto decodes request bodies to update tasks like the
+// gRPC gateway does, and checks that the update only sets the fields listed
+// in its field mask, regardless of unknown, repeated, or missing paths.
+func FuzzNewTaskUpdateFromProto(f *testing.F) {
+	f.Add(`{"id":"1","update":{"summary":"Buy milk"},"fields":"summary"}`)
+	f.Add(`{"id":"1","update":{"summary":"Buy milk","starred":true},"fields":"starred,starred"}`)
+	f.Add(`{"id":"1","update":{"completedAt":"2025-12-24T18:00:00Z"},"fields":"completed_at,due_at,tags"}`)
+	f.Add(`{"id":"1","update":{"summary":"Buy milk"},"fields":""}`)
+	f.Add(`{"id":"1","update":{"summary":"Buy milk"},"fields":"id,version,unknown"}`)
+	f.Add(`{"id":"1","fields":"summary,description"}`)
+	f.Fuzz(func(t *testing.T, body string) {
+		var req todopb.UpdateTaskRequest
+		if err := protojson.Unmarshal([]byte(body), &req); err != nil {
+			return
+		}
+		update := newTaskUpdateFromProto(req.GetUpdate(), req.GetFields())
+		var want []string
+		for _, path := range req.GetFields().GetPaths() {
+			if slices.Contains([]string{
+				"summary", "description", "completed_at", "due_at", "tags", "project",
+				"depends_on", "recurrence", "time_zone", "starred", "assignee", "links",
+			}, path) && !slices.Contains(want, path) {
+				want = append(want, path)
+			}
+		}
+		slices.Sort(want)
+		if got := updatedFields(update); !slices.Equal(got, want) {
+			t.Errorf("want update of fields %v; got: %v", want, got)
+		}
+		// Validating must not panic, whatever the update contains.
+		_ = update.Validate()
+	})
+}
@@ -0,0 +1,378 @@
+package todo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	"github.com/mwopitz/todo-daemon/internal/logging"
+)
+
+// MaxTemplateTasks is the maximum number of tasks of a [Template].
+const MaxTemplateTasks = 100
+
+// TemplateTask is a task to be created from a [Template].
+type TemplateTask struct {
+	// Summary is a concise description of the task.
+	Summary string `json:"summary"`
+	// Description is an optional, more detailed description of the task.
+	Description string `json:"description,omitempty"`
+	// Tags are the optional tags of the task.
+	Tags []string `json:"tags,omitempty"`
+	// Project is the optional project the task belongs to.
+	Project string `json:"project,omitempty"`
+	// Starred specifies whether the task is starred.
+	Starred bool `json:"starred,omitempty"`
+	// DueAfter is the time between creating the task and its due time. If
+	// zero, the task has no due time.
+	DueAfter time.Duration `json:"due_after,omitempty"`
+}
+
+// Template is a named set of tasks that are created together, e.g. the items
+// of a weekly review checklist. A template with a schedule is applied at the
+// start of each day that its schedule selects.
+type Template struct {
+	// Name is the unique name of the template, e.g. "weekly-review".
+	Name string `json:"name"`
+	// Tasks are the tasks to create, in this order.
+	Tasks []TemplateTask `json:"tasks"`
+	// Schedule is the optional recurrence rule that the template is applied
+	// by, see [ParseRecurrence].
+	Schedule string `json:"schedule,omitempty"`
+	// NextRun is the time when the template is applied next, or the zero
+	// time if the template has no schedule.
+	NextRun time.Time `json:"next_run,omitzero"`
+}
+
+// Validate checks if the template has a valid name, schedule, and tasks.
+func (t *Template) Validate() error {
+	v := validator{subject: "template"}
+	v.name(t.Name)
+	switch n := len(t.Tasks); {
+	case n == 0:
+		v.addf("tasks", "must not be empty")
+	case n > MaxTemplateTasks:
+		v.addf("tasks", "must have at most %d tasks, got %d", MaxTemplateTasks, n)
+	}
+	for i, task := range t.Tasks {
+		path := fmt.Sprintf("tasks[%d]", i)
+		if task.DueAfter < 0 {
+			v.addf(path+".due_after", "must not be negative, got %s", task.DueAfter)
+		}
+		// The due time depends on when the template is applied, so it is not
+		// validated here.
+		task.DueAfter = 0
+		var e *ValidationError
+		if errors.As(task.create(time.Time{}, "").Validate(), &e) {
+			v.violations = append(v.violations, e.withPrefix(path).Violations...)
+		}
+	}
+	if t.Schedule != "" {
+		if _, err := ParseRecurrence(t.Schedule); err != nil {
+			v.addf("schedule", "%v", err)
+		}
+	}
+	return v.err()
+}
+
+// create returns the task to create at the specified time with the specified
+// default time zone.
+func (t *TemplateTask) create(now time.Time, timeZone string) *TaskCreate {
+	task := &TaskCreate{
+		Summary:     t.Summary,
+		Description: t.Description,
+		Tags:        slices.Clone(t.Tags),
+		Project:     t.Project,
+		Starred:     t.Starred,
+		TimeZone:    timeZone,
+	}
+	if t.DueAfter > 0 {
+		task.DueAt = now.Add(t.DueAfter)
+	}
+	return task
+}
+
+// nextRun returns the time when the template is applied next after the
+// specified time, i.e. the start of the next day that its schedule selects in
+// the location of the specified time, or the zero time if the template has no
+// valid schedule.
+func (t *Template) nextRun(after time.Time) time.Time {
+	if t.Schedule == "" {
+		return time.Time{}
+	}
+	r, err := ParseRecurrence(t.Schedule)
+	if err != nil {
+		return time.Time{}
+	}
+	next := r.Next(time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, after.Location()))
+	for !next.After(after) {
+		next = r.Next(next)
+	}
+	return next
+}
+
+// NewTemplateFromProto converts the protobuf representation of a template.
+func NewTemplateFromProto(p *todopb.Template) *Template {
+	t := &Template{Name: p.GetName(), Schedule: p.GetSchedule(), Tasks: make([]TemplateTask, len(p.GetTasks()))}
+	for i, task := range p.GetTasks() {
+		t.Tasks[i] = TemplateTask{
+			Summary:     task.GetSummary(),
+			Description: task.GetDescription(),
+			Tags:        task.GetTags(),
+			Project:     task.GetProject(),
+			Starred:     task.GetStarred(),
+			DueAfter:    task.GetDueAfter().AsDuration(),
+		}
+	}
+	return t
+}
+
+func (t *Template) toProto() *todopb.Template {
+	p := &todopb.Template{
+		Name:     t.Name,
+		Schedule: t.Schedule,
+		Tasks:    make([]*todopb.TemplateTask, len(t.Tasks)),
+	}
+	if !t.NextRun.IsZero() {
+		p.NextRunAt = timestamppb.New(t.NextRun)
+	}
+	for i, task := range t.Tasks {
+		p.Tasks[i] = &todopb.TemplateTask{
+			Summary:     task.Summary,
+			Description: task.Description,
+			Tags:        task.Tags,
+			Project:     task.Project,
+			Starred:     task.Starred,
+		}
+		if task.DueAfter > 0 {
+			p.Tasks[i].DueAfter = durationpb.New(task.DueAfter)
+		}
+	}
+	return p
+}
+
+// TemplateNotFoundError is returned by the [TemplateRegistry] when there is no
+// template with the specified name.
+type TemplateNotFoundError struct {
+	// Name is the name of the template that was not found.
+	Name string
+}
+
+// NewTemplateNotFoundError creates a [TemplateNotFoundError] for the template
+// with the specified name.
+func NewTemplateNotFoundError(name string) *TemplateNotFoundError {
+	return &TemplateNotFoundError{Name: name}
+}
+
+// IsTemplateNotFoundError checks if the provided error is a
+// [TemplateNotFoundError].
+func IsTemplateNotFoundError(err error) bool {
+	var e *TemplateNotFoundError
+	return err != nil && errors.As(err, &e)
+}
+
+func (e *TemplateNotFoundError) Error() string {
+	return fmt.Sprintf("no such template: '%s'", e.Name)
+}
+
+// TemplateExistsError is returned by [TemplateRegistry.Add] when there is
+// already a template with the same name.
+type TemplateExistsError struct {
+	// Name is the name of the existing template.
+	Name string
+}
+
+// NewTemplateExistsError creates a [TemplateExistsError] for the template with
+// the specified name.
+func NewTemplateExistsError(name string) *TemplateExistsError {
+	return &TemplateExistsError{Name: name}
+}
+
+// IsTemplateExistsError checks if the provided error is a
+// [TemplateExistsError].
+func IsTemplateExistsError(err error) bool {
+	var e *TemplateExistsError
+	return err != nil && errors.As(err, &e)
+}
+
+func (e *TemplateExistsError) Error() string {
+	return fmt.Sprintf("template '%s' already exists", e.Name)
+}
+
+// TemplateRegistry holds the templates saved via the API. They are kept in a
+// JSON file along with the time when each scheduled template is applied next,
+// so they survive restarts of the server.
+type TemplateRegistry struct {
+	mu        sync.Mutex
+	path      string
+	templates map[string]Template
+}
+
+// NewTemplateRegistry creates a template registry that keeps the templates in
+// the file at the specified path, and loads the templates saved in it before.
+// If the path is empty, the templates are kept in memory only.
+func NewTemplateRegistry(path string) (*TemplateRegistry, error) {
+	r := &TemplateRegistry{path: path, templates: map[string]Template{}}
+	if path == "" {
+		return r, nil
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read templates: %w", err)
+	}
+	var templates []Template
+	if err := json.Unmarshal(b, &templates); err != nil {
+		return nil, fmt.Errorf("invalid templates in %s: %w", path, err)
+	}
+	for _, t := range templates {
+		if err := t.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid templates in %s: %w", path, err)
+		}
+		r.templates[t.Name] = t
+	}
+	return r, nil
+}
+
+// Get returns the template with the specified name.
+func (r *TemplateRegistry) Get(name string) (*Template, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.templates[name]
+	if !ok {
+		return nil, NewTemplateNotFoundError(name)
+	}
+	return &t, nil
+}
+
+// List returns all templates ordered by name.
+func (r *TemplateRegistry) List() []Template {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sorted()
+}
+
+// Add saves the specified template. If it has a schedule, it is applied first
+// at the start of the next day after the specified time that the schedule
+// selects.
+func (r *TemplateRegistry) Add(t *Template, now time.Time) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.templates[t.Name]; ok {
+		return NewTemplateExistsError(t.Name)
+	}
+	t.NextRun = t.nextRun(now)
+	r.templates[t.Name] = *t
+	if err := r.write(); err != nil {
+		delete(r.templates, t.Name)
+		return err
+	}
+	return nil
+}
+
+// Remove deletes the template with the specified name.
+func (r *TemplateRegistry) Remove(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.templates[name]
+	if !ok {
+		return NewTemplateNotFoundError(name)
+	}
+	delete(r.templates, name)
+	if err := r.write(); err != nil {
+		r.templates[name] = t
+		return err
+	}
+	return nil
+}
+
+// Apply creates all tasks of the template with the specified name in the
+// specified repository at once, see [BatchRepository]. The tasks without a
+// time zone are assigned the time zone of the specified time.
+func (r *TemplateRegistry) Apply(ctx context.Context, tasks TaskRepository, name string, now time.Time) (Tasks, error) {
+	t, err := r.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return t.apply(ctx, tasks, now)
+}
+
+func (t *Template) apply(ctx context.Context, tasks TaskRepository, now time.Time) (Tasks, error) {
+	batch, ok := tasks.(BatchRepository)
+	if !ok {
+		return nil, ErrBatchUnsupported
+	}
+	creates := make([]*TaskCreate, len(t.Tasks))
+	for i := range t.Tasks {
+		creates[i] = t.Tasks[i].create(now, timeZoneName(now.Location()))
+	}
+	return batch.CreateAll(ctx, creates)
+}
+
+// ApplyDue applies the scheduled templates whose next run is due at the
+// specified time, and schedules their next run. A template that was due
+// several times, e.g. while the server was not running, is applied only once.
+// If a template cannot be applied, it is tried again by the next call.
+func (r *TemplateRegistry) ApplyDue(ctx context.Context, tasks TaskRepository, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	logger := logging.FromContext(ctx)
+	var errs []error
+	applied := false
+	for _, t := range r.sorted() {
+		if t.NextRun.IsZero() || t.NextRun.After(now) {
+			continue
+		}
+		created, err := t.apply(ctx, tasks, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cannot apply template '%s': %w", t.Name, err))
+			continue
+		}
+		logger.InfoContext(ctx, "applied scheduled template", "name", t.Name, "tasks", len(created))
+		t.NextRun = t.nextRun(now)
+		r.templates[t.Name] = t
+		applied = true
+	}
+	if applied {
+		errs = append(errs, r.write())
+	}
+	return errors.Join(errs...)
+}
+
+// sorted returns all templates ordered by name. The caller must hold the lock.
+func (r *TemplateRegistry) sorted() []Template {
+	templates := make([]Template, 0, len(r.templates))
+	for _, t := range r.templates {
+		templates = append(templates, t)
+	}
+	slices.SortFunc(templates, func(a, b Template) int { return strings.Compare(a.Name, b.Name) })
+	return templates
+}
+
+// write writes the templates to the file of the registry, if any. The caller
+// must hold the lock.
+func (r *TemplateRegistry) write() error {
+	if r.path == "" {
+		return nil
+	}
+	if err := writeJSONFile(r.path, r.sorted()); err != nil {
+		return fmt.Errorf("cannot save templates: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package todo
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+)
+
+func TestTemplateValidate(t *testing.T) {
+	tasks := []TemplateTask{{Summary: "Review inbox"}}
+	tests := []struct {
+		name     string
+		template Template
+		want     []string
+	}{
+		{"Valid", Template{Name: "weekly-review", Tasks: tasks, Schedule: "FREQ=WEEKLY;BYDAY=FR"}, nil},
+		{"EmptyName", Template{Tasks: tasks}, []string{"name"}},
+		{"NoTasks", Template{Name: "a"}, []string{"tasks"}},
+		{"TooManyTasks", Template{Name: "a", Tasks: slices.Repeat(tasks, MaxTemplateTasks+1)}, []string{"tasks"}},
+		{"InvalidTask", Template{Name: "a", Tasks: []TemplateTask{{Summary: "b"}, {}}}, []string{"tasks[1].summary"}},
+		{"NegativeDueAfter", Template{Name: "a", Tasks: []TemplateTask{{Summary: "b", DueAfter: -time.Hour}}},
+			[]string{"tasks[0].due_after"}},
+		{"InvalidSchedule", Template{Name: "a", Tasks: tasks, Schedule: "FREQ=HOURLY"}, []string{"schedule"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := violatedFields(t, tt.template.Validate()); !slices.Equal(got, tt.want) {
+				t.Errorf("want violated fields %v; got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTemplateNextRun(t *testing.T) {
+	// Wednesday afternoon.
+	now := time.Date(2025, 3, 12, 15, 30, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"":                     {},
+		"FREQ=DAILY":           time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC),
+		"FREQ=WEEKLY;BYDAY=FR": time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC),
+		"FREQ=WEEKLY;BYDAY=WE": time.Date(2025, 3, 19, 0, 0, 0, 0, time.UTC),
+	}
+	for schedule, want := range tests {
+		tmpl := Template{Name: "a", Schedule: schedule}
+		if got := tmpl.nextRun(now); !got.Equal(want) {
+			t.Errorf("%q: want next run %v; got: %v", schedule, want, got)
+		}
+	}
+}
+
+func TestTemplateRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "templates.json")
+	r, err := NewTemplateRegistry(path)
+	if err != nil {
+		t.Fatalf("cannot create registry: %v", err)
+	}
+	now := time.Date(2025, 3, 12, 15, 30, 0, 0, time.UTC)
+	tmpl := &Template{
+		Name:     "weekly-review",
+		Tasks:    []TemplateTask{{Summary: "Review inbox", DueAfter: 2 * time.Hour}, {Summary: "Plan week"}},
+		Schedule: "FREQ=WEEKLY;BYDAY=FR",
+	}
+	if err := r.Add(tmpl, now); err != nil {
+		t.Fatalf("cannot add template: %v", err)
+	}
+	if err := r.Add(&Template{Name: "weekly-review", Tasks: tmpl.Tasks}, now); !IsTemplateExistsError(err) {
+		t.Errorf("want TemplateExistsError; got: %v", err)
+	}
+	if err := r.Add(&Template{Name: "empty"}, now); !IsValidationError(err) {
+		t.Errorf("want ValidationError; got: %v", err)
+	}
+	if err := r.Remove("empty"); !IsTemplateNotFoundError(err) {
+		t.Errorf("want TemplateNotFoundError; got: %v", err)
+	}
+
+	// The templates survive restarts along with their next runs.
+	r, err = NewTemplateRegistry(path)
+	if err != nil {
+		t.Fatalf("cannot reopen registry: %v", err)
+	}
+	got := r.List()
+	if len(got) != 1 || got[0].Name != "weekly-review" || len(got[0].Tasks) != 2 ||
+		got[0].Tasks[0].DueAfter != 2*time.Hour || !got[0].NextRun.Equal(time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("want saved template weekly-review; got: %+v", got)
+	}
+	if err := r.Remove("weekly-review"); err != nil {
+		t.Fatalf("cannot remove template: %v", err)
+	}
+	if _, err := r.Get("weekly-review"); !IsTemplateNotFoundError(err) {
+		t.Errorf("want TemplateNotFoundError; got: %v", err)
+	}
+}
+
+func TestTemplateApply(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryTaskDB()
+	r, err := NewTemplateRegistry("")
+	if err != nil {
+		t.Fatalf("cannot create registry: %v", err)
+	}
+	now := time.Date(2025, 3, 12, 15, 30, 0, 0, time.UTC)
+	tasks := []TemplateTask{
+		{Summary: "Review inbox", Tags: []string{"review"}, DueAfter: time.Hour},
+		{Summary: "Plan week"},
+	}
+	if err := r.Add(&Template{Name: "weekly-review", Tasks: tasks}, now); err != nil {
+		t.Fatalf("cannot add template: %v", err)
+	}
+
+	created, err := r.Apply(ctx, db, "weekly-review", now)
+	if err != nil {
+		t.Fatalf("cannot apply template: %v", err)
+	}
+	if len(created) != 2 || created[0].Summary != "Review inbox" || !created[0].DueAt.Equal(now.Add(time.Hour)) ||
+		!slices.Equal(created[0].Tags, []string{"review"}) || !created[1].DueAt.IsZero() {
+		t.Errorf("want tasks of template; got: %+v", created)
+	}
+	if _, err := r.Apply(ctx, db, "nope", now); !IsTemplateNotFoundError(err) {
+		t.Errorf("want TemplateNotFoundError; got: %v", err)
+	}
+}
+
+func TestTemplateApplyDue(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryTaskDB()
+	r, err := NewTemplateRegistry("")
+	if err != nil {
+		t.Fatalf("cannot create registry: %v", err)
+	}
+	added := time.Date(2025, 3, 12, 15, 30, 0, 0, time.UTC)
+	tmpl := &Template{Name: "daily", Tasks: []TemplateTask{{Summary: "Stand-up"}}, Schedule: "FREQ=DAILY"}
+	if err := r.Add(tmpl, added); err != nil {
+		t.Fatalf("cannot add template: %v", err)
+	}
+	if err := r.Add(&Template{Name: "manual", Tasks: tmpl.Tasks}, added); err != nil {
+		t.Fatalf("cannot add template: %v", err)
+	}
+
+	count := func() int {
+		tasks, err := db.List(ctx, &ListOptions{})
+		if err != nil {
+			t.Fatalf("cannot list tasks: %v", err)
+		}
+		return len(tasks)
+	}
+	if err := r.ApplyDue(ctx, db, added.Add(time.Hour)); err != nil || count() != 0 {
+		t.Errorf("want no tasks before the next run; got: %d, %v", count(), err)
+	}
+	// The template was due three times, but is applied only once.
+	now := time.Date(2025, 3, 15, 8, 0, 0, 0, time.UTC)
+	if err := r.ApplyDue(ctx, db, now); err != nil || count() != 1 {
+		t.Errorf("want one task after the next run; got: %d, %v", count(), err)
+	}
+	if err := r.ApplyDue(ctx, db, now.Add(time.Hour)); err != nil || count() != 1 {
+		t.Errorf("want template applied only once a day; got: %d, %v", count(), err)
+	}
+	got, err := r.Get("daily")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC); !got.NextRun.Equal(want) {
+		t.Errorf("want next run %v; got: %v", want, got.NextRun)
+	}
+}
+
+func TestApplyTemplateAtomic(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryTaskDB()
+	r, err := NewTemplateRegistry("")
+	if err != nil {
+		t.Fatalf("cannot create registry: %v", err)
+	}
+	ctrl := NewController(nil, nil, db, NewEventBus(), WithTemplates(r))
+	template := &todopb.Template{
+		Name: "release",
+		Tasks: []*todopb.TemplateTask{
+			{Summary: "Tag release"},
+			{Summary: "Announce release", Tags: []string{"with space"}},
+		},
+	}
+	_, err = ctrl.CreateTemplate(ctx, &todopb.CreateTemplateRequest{Template: template})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("want InvalidArgument for invalid task; got: %v", err)
+	}
+	template.Tasks[1].Tags = []string{"announcement"}
+	if _, err := ctrl.CreateTemplate(ctx, &todopb.CreateTemplateRequest{Template: template}); err != nil {
+		t.Fatalf("cannot create template: %v", err)
+	}
+
+	// A task referring to a missing dependency makes the whole batch fail.
+	if _, err := db.CreateAll(ctx, []*TaskCreate{{Summary: "a"}, {Summary: "b", DependsOn: []string{"9"}}}); err == nil {
+		t.Error("want error for missing dependency")
+	}
+	resp, err := ctrl.ApplyTemplate(ctx, &todopb.ApplyTemplateRequest{Name: "release"})
+	if err != nil {
+		t.Fatalf("cannot apply template: %v", err)
+	}
+	if got := resp.GetTasks(); len(got) != 2 || got[0].GetId() != "1" || got[1].GetId() != "2" {
+		t.Errorf("want only the tasks of the template; got: %v", got)
+	}
+	if _, err := ctrl.ApplyTemplate(ctx, &todopb.ApplyTemplateRequest{Name: "nope"}); status.Code(err) != codes.NotFound {
+		t.Errorf("want NotFound for unknown template; got: %v", err)
+	}
+}
@@ -0,0 +1,983 @@
+// Package todotest provides a conformance test kit for implementations of
+// [todo.TaskRepository]. Each storage backend should run it in its tests, so
+// all backends behave the same:
+//
+//	func TestRepository(t *testing.T) {
+//		todotest.RunRepositoryTests(t, func(t *testing.T) todo.TaskRepository {
+//			return newBackend(t)
+//		})
+//	}
+package todotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// Factory creates a new, empty repository for a single test.
+type Factory func(t *testing.T) todo.TaskRepository
+
+// RunRepositoryTests runs the conformance test suite against the repositories
+// created by the specified factory. Each subtest gets its own repository.
+func RunRepositoryTests(t *testing.T, newRepo Factory) {
+	t.Helper()
+	tests := []struct {
+		name string
+		test func(t *testing.T, repo todo.TaskRepository)
+	}{
+		{"CreateAndGet", testCreateAndGet},
+		{"ListOrderedByCreation", testListOrderedByCreation},
+		{"ListOrderedAfterUpdate", testListOrderedAfterUpdate},
+		{"Update", testUpdate},
+		{"PartialUpdate", testPartialUpdate},
+		{"UpdateConflict", testUpdateConflict},
+		{"Delete", testDelete},
+		{"Restore", testRestore},
+		{"NotFound", testNotFound},
+		{"ListFilter", testListFilter},
+		{"ListSort", testListSort},
+		{"ListPagination", testListPagination},
+		{"Search", testSearch},
+		{"Replace", testReplace},
+		{"ReplaceKeepsPositions", testReplaceKeepsPositions},
+		{"Move", testMove},
+		{"MoveNotFound", testMoveNotFound},
+		{"RestoreAfterMove", testRestoreAfterMove},
+		{"Stats", testStats},
+		{"Dependencies", testDependencies},
+		{"DependencyCycle", testDependencyCycle},
+		{"CreateAll", testCreateAll},
+		{"ApplyBatch", testApplyBatch},
+		{"ApplyBatchRollback", testApplyBatchRollback},
+		{"TimeZone", testTimeZone},
+		{"Starred", testStarred},
+		{"Assignee", testAssignee},
+		{"Links", testLinks},
+		{"Revision", testRevision},
+		{"ConcurrentCreate", testConcurrentCreate},
+		{"ConcurrentUpdate", testConcurrentUpdate},
+		{"CanceledContext", testCanceledContext},
+		{"ExceededDeadline", testExceededDeadline},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newRepo(t))
+		})
+	}
+}
+
+func mustCreate(t *testing.T, repo todo.TaskRepository, task *todo.TaskCreate) *todo.Task {
+	t.Helper()
+	created, err := repo.Create(context.Background(), task)
+	if err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+	return created
+}
+
+func testCreateAndGet(t *testing.T, repo todo.TaskRepository) {
+	dueAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	created := mustCreate(t, repo, &todo.TaskCreate{Summary: "foo", Description: "bar", DueAt: dueAt})
+	if created.ID == "" {
+		t.Fatal("want created task to have an ID")
+	}
+	if created.CreatedAt.IsZero() {
+		t.Error("want created task to have a creation time")
+	}
+	if created.Version != 1 {
+		t.Errorf("want version of created task: 1; got: %d", created.Version)
+	}
+	got, err := repo.Get(context.Background(), created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Summary != "foo" || got.Description != "bar" || !got.DueAt.Equal(dueAt) {
+		t.Errorf("unexpected task: %+v", got)
+	}
+}
+
+func testListOrderedByCreation(t *testing.T, repo todo.TaskRepository) {
+	want := []string{"first", "second", "third"}
+	for _, summary := range want {
+		mustCreate(t, repo, &todo.TaskCreate{Summary: summary})
+	}
+	tasks, err := repo.List(context.Background(), &todo.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != len(want) {
+		t.Fatalf("want %d tasks; got: %d", len(want), len(tasks))
+	}
+	for i, task := range tasks {
+		if task.Summary != want[i] {
+			t.Errorf("want task %d: %q; got: %q", i, want[i], task.Summary)
+		}
+	}
+}
+
+func testListOrderedAfterUpdate(t *testing.T, repo todo.TaskRepository) {
+	first := mustCreate(t, repo, &todo.TaskCreate{Summary: "first"})
+	mustCreate(t, repo, &todo.TaskCreate{Summary: "second"})
+	summary := "updated"
+	if _, err := repo.Update(context.Background(), first.ID, &todo.TaskUpdate{Summary: &summary}); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := repo.List(context.Background(), &todo.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 || tasks[0].Summary != "updated" || tasks[1].Summary != "second" {
+		t.Errorf("want tasks to stay ordered by creation; got: %+v", tasks)
+	}
+}
+
+func testUpdate(t *testing.T, repo todo.TaskRepository) {
+	created := mustCreate(t, repo, &todo.TaskCreate{Summary: "foo"})
+	summary := "bar"
+	completedAt := time.Now().Truncate(time.Second)
+	updated, err := repo.Update(context.Background(), created.ID, &todo.TaskUpdate{
+		Summary:     &summary,
+		CompletedAt: &completedAt,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Summary != summary || !updated.CompletedAt.Equal(completedAt) {
+		t.Errorf("unexpected updated task: %+v", updated)
+	}
+	if updated.UpdatedAt.IsZero() {
+		t.Error("want updated task to have an update time")
+	}
+	if updated.Version != created.Version+1 {
+		t.Errorf("want version: %d; got: %d", created.Version+1, updated.Version)
+	}
+}
+
+// testPartialUpdate checks that only the fields set in an update are modified,
+// which is how the field mask of an UpdateTask request is applied.
+func testPartialUpdate(t *testing.T, repo todo.TaskRepository) {
+	dueAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	created := mustCreate(t, repo, &todo.TaskCreate{Summary: "foo", Description: "bar", DueAt: dueAt})
+
+	description := "baz"
+	updated, err := repo.Update(context.Background(), created.ID, &todo.TaskUpdate{Description: &description})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Summary != "foo" || updated.Description != "baz" || !updated.DueAt.Equal(dueAt) ||
+		!updated.CompletedAt.IsZero() {
+		t.Errorf("want only the description to be updated; got: %+v", updated)
+	}
+
+	// Setting a field to its zero value clears it.
+	var noDueAt time.Time
+	updated, err = repo.Update(context.Background(), created.ID, &todo.TaskUpdate{DueAt: &noDueAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated.DueAt.IsZero() || updated.Description != "baz" {
+		t.Errorf("want only the due time to be cleared; got: %+v", updated)
+	}
+	got, err := repo.Get(context.Background(), created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Summary != "foo" || got.Description != "baz" || !got.DueAt.IsZero() {
+		t.Errorf("want updates to be persisted; got: %+v", got)
+	}
+}
+
+func testUpdateConflict(t *testing.T, repo todo.TaskRepository) {
+	created := mustCreate(t, repo, &todo.TaskCreate{Summary: "foo"})
+	summary := "bar"
+	_, err := repo.Update(context.Background(), created.ID, &todo.TaskUpdate{
+		Summary:         &summary,
+		ExpectedVersion: created.Version + 1,
+	})
+	if !todo.IsTaskConflictError(err) {
+		t.Fatalf("want task conflict error; got: %v", err)
+	}
+	got, err := repo.Get(context.Background(), created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Summary != "foo" {
+		t.Errorf("want conflicting update not to be applied; got summary: %q", got.Summary)
+	}
+}
+
+func testDelete(t *testing.T, repo todo.TaskRepository) {
+	created := mustCreate(t, repo, &todo.TaskCreate{Summary: "foo"})
+	if err := repo.Delete(context.Background(), created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Get(context.Background(), created.ID); !todo.IsTaskNotFoundError(err) {
+		t.Errorf("want task not found error; got: %v", err)
+	}
+}
+
+func testRestore(t *testing.T, repo todo.TaskRepository) {
+	ctx := context.Background()
+	created := mustCreate(t, repo, &todo.TaskCreate{Summary: "foo"})
+	if _, err := repo.Restore(ctx, created.ID); !todo.IsTaskNotFoundError(err) {
+		t.Errorf("want task not found error for task that is not in the trash; got: %v", err)
+	}
+	if err := repo.Delete(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := repo.Restore(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("cannot restore task: %v", err)
+	}
+	if !restored.DeletedAt.IsZero() || restored.Version != created.Version+1 || restored.Summary != "foo" {
+		t.Errorf("want restored task with incremented version; got: %+v", restored)
+	}
+	checkList(t, repo, &todo.ListOptions{}, []string{"foo"})
+	results, err := repo.Search(ctx, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Errorf("want restored task to be found; got: %d results", len(results))
+	}
+	if _, err := repo.Restore(ctx, "missing"); !todo.IsTaskNotFoundError(err) {
+		t.Errorf("want task not found error for missing task; got: %v", err)
+	}
+}
+
+func testNotFound(t *testing.T, repo todo.TaskRepository) {
+	ctx := context.Background()
+	summary := "foo"
+	if _, err := repo.Get(ctx, "missing"); !todo.IsTaskNotFoundError(err) {
+		t.Errorf("Get: want task not found error; got: %v", err)
+	}
+	if _, err := repo.Update(ctx, "missing", &todo.TaskUpdate{Summary: &summary}); !todo.IsTaskNotFoundError(err) {
+		t.Errorf("Update: want task not found error; got: %v", err)
+	}
+	if err := repo.Delete(ctx, "missing"); !todo.IsTaskNotFoundError(err) {
+		t.Errorf("Delete: want task not found error; got: %v", err)
+	}
+}
+
+func testDependencies(t *testing.T, repo todo.TaskRepository) {
+	ctx := context.Background()
+	first := mustCreate(t, repo, &todo.TaskCreate{Summary: "first"})
+	second := mustCreate(t, repo, &todo.TaskCreate{Summary: "second", DependsOn: []string{first.ID}})
+	if !slices.Equal(second.DependsOn, []string{first.ID}) || !slices.Equal(second.BlockedBy, []string{first.ID}) {
+		t.Errorf("want created task to be blocked by %s; got: %+v", first.ID, second)
+	}
+	_, err := repo.Create(ctx, &todo.TaskCreate{Summary: "third", DependsOn: []string{"missing"}})
+	if !todo.IsTaskNotFoundError(err) {
+		t.Errorf("want task not found error for missing dependency; got: %v", err)
+	}
+
+	tasks, err := repo.List(ctx, &todo.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 || tasks[0].IsBlocked() || !tasks[1].IsBlocked() {
+		t.Errorf("want only the second task to be blocked in the list; got: %+v", tasks)
+	}
+
+	completedAt := time.Now()
+	if _, err := repo.Update(ctx, first.ID, &todo.TaskUpdate{CompletedAt: &completedAt}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := repo.Get(ctx, second.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.IsBlocked() {
+		t.Errorf("want task to be unblocked once its dependency is completed; got: %+v", got)
+	}
+
+	var none []string
+	updated, err := repo.Update(ctx, second.ID, &todo.TaskUpdate{DependsOn: &none})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated.DependsOn) != 0 || updated.Version != got.Version+1 {
+		t.Errorf("want dependencies to be removed; got: %+v", updated)
+	}
+}
+
+func testCreateAll(t *testing.T, repo todo.TaskRepository) {
+	batch, ok := repo.(todo.BatchRepository)
+	if !ok {
+		t.Skip("repository does not support batches")
+	}
+	ctx := context.Background()
+	first := mustCreate(t, repo, &todo.TaskCreate{Summary: "first"})
+	_, err := batch.CreateAll(ctx, []*todo.TaskCreate{
+		{Summary: "second"},
+		{Summary: "third", DependsOn: []string{"missing"}},
+	})
+	if !todo.IsTaskNotFoundError(err) {
+		t.Errorf("want task not found error for missing dependency; got: %v", err)
+	}
+	checkList(t, repo, &todo.ListOptions{}, []string{"first"})
+
+	created, err := batch.CreateAll(ctx, []*todo.TaskCreate{
+		{Summary: "second"},
+		{Summary: "third", DependsOn: []string{first.ID}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 2 || created[0].ID == created[1].ID || !created[1].IsBlocked() {
+		t.Errorf("want two new tasks, the second one blocked; got: %+v", created)
+	}
+	checkList(t, repo, &todo.ListOptions{}, []string{"first", "second", "third"})
+}
+
+func testApplyBatch(t *testing.T, repo todo.TaskRepository) {
+	batch, ok := repo.(todo.BatchRepository)
+	if !ok {
+		t.Skip("repository does not support batches")
+	}
+	ctx := context.Background()
+	first := mustCreate(t, repo, &todo.TaskCreate{Summary: "first"})
+	second := mustCreate(t, repo, &todo.TaskCreate{Summary: "second"})
+	summary := "renamed"
+	results, err := batch.ApplyBatch(ctx, []todo.BatchOperation{
+		{Create: &todo.TaskCreate{Summary: "third", DependsOn: []string{first.ID}}},
+		{ID: first.ID, Update: &todo.TaskUpdate{Summary: &summary, ExpectedVersion: first.Version}},
+		{ID: second.ID, Delete: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 || results[0].Summary != "third" || !results[0].IsBlocked() ||
+		results[1].Summary != "renamed" || results[1].Version != first.Version+1 || results[2].DeletedAt.IsZero() {
+		t.Errorf("want created, updated, and deleted task; got: %+v", results)
+	}
+	checkList(t, repo, &todo.ListOptions{}, []string{"renamed", "third"})
+}
+
+func testApplyBatchRollback(t *testing.T, repo todo.TaskRepository) {
+	batch, ok := repo.(todo.BatchRepository)
+	if !ok {
+		t.Skip("repository does not support batches")
+	}
+	ctx := context.Background()
+	first := mustCreate(t, repo, &todo.TaskCreate{Summary: "first"})
+	rev, err := repo.Revision(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := "renamed"
+	_, err = batch.ApplyBatch(ctx, []todo.BatchOperation{
+		{Create: &todo.TaskCreate{Summary: "second"}},
+		{ID: first.ID, Update: &todo.TaskUpdate{Summary: &summary}},
+		{ID: first.ID, Delete: true},
+		{ID: first.ID, Update: &todo.TaskUpdate{Summary: &summary}},
+	})
+	var opErr *todo.BatchOperationError
+	if !errors.As(err, &opErr) || opErr.Index != 3 || !todo.IsTaskNotFoundError(err) {
+		t.Errorf("want task not found error for the fourth operation; got: %v", err)
+	}
+	_, err = batch.ApplyBatch(ctx, []todo.BatchOperation{
+		{ID: first.ID, Update: &todo.TaskUpdate{Summary: &summary, ExpectedVersion: first.Version + 1}},
+	})
+	if !todo.IsTaskConflictError(err) {
+		t.Errorf("want task conflict error; got: %v", err)
+	}
+	checkList(t, repo, &todo.ListOptions{}, []string{"first"})
+	got, err := repo.Revision(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Counter != rev.Counter {
+		t.Errorf("want revision unchanged by failed batches; got: %+v, want: %+v", got, rev)
+	}
+}
+
+func testDependencyCycle(t *testing.T, repo todo.TaskRepository) {
+	ctx := context.Background()
+	a := mustCreate(t, repo, &todo.TaskCreate{Summary: "a"})
+	b := mustCreate(t, repo, &todo.TaskCreate{Summary: "b", DependsOn: []string{a.ID}})
+	c := mustCreate(t, repo, &todo.TaskCreate{Summary: "c", DependsOn: []string{b.ID}})
+
+	for _, deps := range [][]string{{c.ID}, {a.ID}} {
+		_, err := repo.Update(ctx, a.ID, &todo.TaskUpdate{DependsOn: &deps})
+		if !todo.IsDependencyCycleError(err) {
+			t.Errorf("want dependency cycle error for %v; got: %v", deps, err)
+		}
+	}
+	got, err := repo.Get(ctx, a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.DependsOn) != 0 || got.Version != a.Version {
+		t.Errorf("want rejected dependencies not to be applied; got: %+v", got)
+	}
+}
+
+func testTimeZone(t *testing.T, repo todo.TaskRepository) {
+	ctx := context.Background()
+	dueAt := time.Date(2025, 12, 24, 17, 0, 0, 0, time.UTC)
+	task := mustCreate(t, repo, &todo.TaskCreate{Summary: "a", DueAt: dueAt, TimeZone: "America/New_York"})
+	if task.TimeZone != "America/New_York" || !task.DueAt.Equal(dueAt) ||
+		task.DueAt.Location().String() != "America/New_York" {
+		t.Errorf("want due time in the task's time zone; got: %v (%s)", task.DueAt, task.TimeZone)
+	}
+
+	zone := "Asia/Tokyo"
+	updated, err := repo.Update(ctx, task.ID, &todo.TaskUpdate{TimeZone: &zone})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := repo.Get(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []*todo.Task{updated, got} {
+		if tt.TimeZone != zone || !tt.DueAt.Equal(dueAt) || tt.DueAt.Location().String() != zone {
+			t.Errorf("want due time in the new time zone; got: %v (%s)", tt.DueAt, tt.TimeZone)
+		}
+	}
+}
+
+func testStarred(t *testing.T, repo todo.TaskRepository) {
+	mustCreate(t, repo, &todo.TaskCreate{Summary: "a"})
+	b := mustCreate(t, repo, &todo.TaskCreate{Summary: "b"})
+	mustCreate(t, repo, &todo.TaskCreate{Summary: "c", Starred: true})
+	starred := true
+	updated, err := repo.Update(context.Background(), b.ID, &todo.TaskUpdate{Starred: &starred})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated.Starred {
+		t.Errorf("want starred task; got: %+v", updated)
+	}
+
+	tests := []struct {
+		name string
+		opts todo.ListOptions
+		want []string
+	}{
+		{"StarredFirst", todo.ListOptions{}, []string{"b", "c", "a"}},
+		{"StarredFirstDescending", todo.ListOptions{Descending: true}, []string{"c", "b", "a"}},
+		{"OnlyStarred", todo.ListOptions{Starred: true}, []string{"b", "c"}},
+		{"Due", todo.ListOptions{SortBy: todo.SortByDue}, []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkList(t, repo, &tt.opts, tt.want)
+		})
+	}
+}
+
+func testAssignee(t *testing.T, repo todo.TaskRepository) {
+	mustCreate(t, repo, &todo.TaskCreate{Summary: "a", Assignee: "alice"})
+	b := mustCreate(t, repo, &todo.TaskCreate{Summary: "b"})
+	c := mustCreate(t, repo, &todo.TaskCreate{Summary: "c", Assignee: "bob"})
+	alice := "alice"
+	updated, err := repo.Update(context.Background(), b.ID, &todo.TaskUpdate{Assignee: &alice})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Assignee != "alice" {
+		t.Errorf("want task assigned to alice; got: %+v", updated)
+	}
+	nobody := ""
+	if updated, err = repo.Update(context.Background(), c.ID, &todo.TaskUpdate{Assignee: &nobody}); err != nil {
+		t.Fatal(err)
+	}
+	if updated.Assignee != "" {
+		t.Errorf("want unassigned task; got: %+v", updated)
+	}
+
+	tests := []struct {
+		name string
+		opts todo.ListOptions
+		want []string
+	}{
+		{"All", todo.ListOptions{}, []string{"a", "b", "c"}},
+		{"Alice", todo.ListOptions{Assignee: "alice"}, []string{"a", "b"}},
+		{"Bob", todo.ListOptions{Assignee: "bob"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkList(t, repo, &tt.opts, tt.want)
+		})
+	}
+}
+
+func testLinks(t *testing.T, repo todo.TaskRepository) {
+	links := []string{"https://example.com/tickets/42", "https://example.com/docs"}
+	created := mustCreate(t, repo, &todo.TaskCreate{Summary: "a", Links: links})
+	links[0] = "https://example.com/modified"
+	got, err := repo.Get(context.Background(), created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"https://example.com/tickets/42", "https://example.com/docs"}; !slices.Equal(got.Links, want) {
+		t.Errorf("want links %q; got: %q", want, got.Links)
+	}
+	updated, err := repo.Update(context.Background(), created.ID, &todo.TaskUpdate{Links: &[]string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated.Links) != 0 {
+		t.Errorf("want no links; got: %q", updated.Links)
+	}
+}
+
+func testRevision(t *testing.T, repo todo.TaskRepository) {
+	ctx := context.Background()
+	var last *todo.Revision
+	// checkChanged checks that the revision has changed since the last check
+	// if and only if the repository has been modified.
+	checkChanged := func(op string, modified bool) {
+		t.Helper()
+		rev, err := repo.Revision(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if last != nil && (rev.ETag() != last.ETag()) != modified {
+			t.Errorf("%s: want revision changed: %t; got: %+v after %+v", op, modified, rev, last)
+		}
+		if last != nil && rev.ModifiedAt.Before(last.ModifiedAt) {
+			t.Errorf("%s: want modification time not to go back; got: %v after %v", op, rev.ModifiedAt, last.ModifiedAt)
+		}
+		last = rev
+	}
+	checkChanged("open", false)
+	a := mustCreate(t, repo, &todo.TaskCreate{Summary: "a"})
+	checkChanged("Create", true)
+	b := mustCreate(t, repo, &todo.TaskCreate{Summary: "b"})
+	checkChanged("Create", true)
+	if _, err := repo.List(ctx, &todo.ListOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Get(ctx, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	checkChanged("List and Get", false)
+	summary := "c"
+	if _, err := repo.Update(ctx, a.ID, &todo.TaskUpdate{Summary: &summary}); err != nil {
+		t.Fatal(err)
+	}
+	checkChanged("Update", true)
+	if _, err := repo.Update(ctx, "missing", &todo.TaskUpdate{Summary: &summary}); !todo.IsTaskNotFoundError(err) {
+		t.Fatalf("want TaskNotFoundError; got: %v", err)
+	}
+	checkChanged("failed Update", false)
+	if _, err := repo.Move(ctx, b.ID, &todo.TaskMove{Before: a.ID}); err != nil {
+		t.Fatal(err)
+	}
+	checkChanged("Move", true)
+	if err := repo.Delete(ctx, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	checkChanged("Delete", true)
+	if err := repo.Replace(ctx, todo.Tasks{{ID: "1", Summary: "a", CreatedAt: time.Now(), Version: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	checkChanged("Replace", true)
+}
+
+// summaries returns the summaries of the specified tasks in order.
+func summaries(tasks todo.Tasks) []string {
+	s := make([]string, len(tasks))
+	for i := range tasks {
+		s[i] = tasks[i].Summary
+	}
+	return s
+}
+
+// checkList checks that listing the tasks with the specified options returns
+// the tasks with the wanted summaries in order.
+func checkList(t *testing.T, repo todo.TaskRepository, opts *todo.ListOptions, want []string) {
+	t.Helper()
+	tasks, err := repo.List(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := summaries(tasks); !slices.Equal(got, want) {
+		t.Errorf("options %+v: want: %v; got: %v", opts, want, got)
+	}
+}
+
+func testListFilter(t *testing.T, repo todo.TaskRepository) {
+	now := time.Now()
+	mustCreate(t, repo, &todo.TaskCreate{Summary: "overdue", DueAt: now.Add(-time.Hour), Tags: []string{"errands"}})
+	soon := mustCreate(t, repo, &todo.TaskCreate{Summary: "soon", DueAt: now.Add(time.Hour), Project: "home"})
+	mustCreate(t, repo, &todo.TaskCreate{
+		Summary: "later",
+		DueAt:   now.Add(48 * time.Hour),
+		Tags:    []string{"errands", "urgent"},
+		Project: "home",
+	})
+	mustCreate(t, repo, &todo.TaskCreate{Summary: "whenever"})
+	completedAt := now.Truncate(time.Second)
+	if _, err := repo.Update(context.Background(), soon.ID, &todo.TaskUpdate{CompletedAt: &completedAt}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts todo.ListOptions
+		want []string
+	}{
+		{"All", todo.ListOptions{}, []string{"overdue", "soon", "later", "whenever"}},
+		{"DueBefore", todo.ListOptions{DueBefore: now.Add(24 * time.Hour)}, []string{"overdue", "soon"}},
+		{"DueAfter", todo.ListOptions{DueAfter: now}, []string{"soon", "later"}},
+		{"Overdue", todo.ListOptions{Overdue: true}, []string{"overdue"}},
+		{"Open", todo.ListOptions{Completion: todo.CompletionOpen}, []string{"overdue", "later", "whenever"}},
+		{"Completed", todo.ListOptions{Completion: todo.CompletionCompleted}, []string{"soon"}},
+		{"Tag", todo.ListOptions{Tags: []string{"errands"}}, []string{"overdue", "later"}},
+		{"Tags", todo.ListOptions{Tags: []string{"errands", "urgent"}}, []string{"later"}},
+		{"Project", todo.ListOptions{Project: "home"}, []string{"soon", "later"}},
+		{"Combined", todo.ListOptions{Project: "home", Completion: todo.CompletionOpen}, []string{"later"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkList(t, repo, &tt.opts, tt.want)
+		})
+	}
+}
+
+func testStats(t *testing.T, repo todo.TaskRepository) {
+	now := time.Now()
+	mustCreate(t, repo, &todo.TaskCreate{Summary: "overdue", DueAt: now.Add(-time.Hour), Tags: []string{"errands"}})
+	done := mustCreate(t, repo, &todo.TaskCreate{Summary: "done", Tags: []string{"errands"}, Project: "home"})
+	old := mustCreate(t, repo, &todo.TaskCreate{Summary: "old", Project: "home"})
+	mustCreate(t, repo, &todo.TaskCreate{Summary: "whenever"})
+	for id, completedAt := range map[string]time.Time{done.ID: now, old.ID: now.AddDate(0, 0, -8)} {
+		if _, err := repo.Update(context.Background(), id, &todo.TaskUpdate{CompletedAt: &completedAt}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := repo.Stats(context.Background(), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Open != 2 || stats.Completed != 2 || stats.Overdue != 1 {
+		t.Errorf("want 2 open, 2 completed, and 1 overdue task; got: %+v", stats)
+	}
+	if stats.CompletedToday != 1 || stats.CompletedThisWeek != 1 {
+		t.Errorf("want 1 task completed today and this week; got: %+v", stats)
+	}
+	if want := (todo.GroupStats{Open: 1, Completed: 1}); stats.Tags["errands"] != want {
+		t.Errorf("want stats of tag 'errands': %+v; got: %+v", want, stats.Tags["errands"])
+	}
+	if want := (todo.GroupStats{Completed: 2}); stats.Projects["home"] != want {
+		t.Errorf("want stats of project 'home': %+v; got: %+v", want, stats.Projects["home"])
+	}
+	if len(stats.Tags) != 1 || len(stats.Projects) != 1 {
+		t.Errorf("want 1 tag and 1 project; got: %v, %v", stats.Tags, stats.Projects)
+	}
+}
+
+func testMove(t *testing.T, repo todo.TaskRepository) {
+	a := mustCreate(t, repo, &todo.TaskCreate{Summary: "a"})
+	b := mustCreate(t, repo, &todo.TaskCreate{Summary: "b"})
+	c := mustCreate(t, repo, &todo.TaskCreate{Summary: "c"})
+	if a.Position >= b.Position || b.Position >= c.Position {
+		t.Fatalf("want new tasks to come last; got positions: %d, %d, %d", a.Position, b.Position, c.Position)
+	}
+	byPosition := &todo.ListOptions{SortBy: todo.SortByPosition}
+
+	moved, err := repo.Move(context.Background(), c.ID, &todo.TaskMove{Before: a.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.Version != c.Version+1 {
+		t.Errorf("want version of moved task: %d; got: %d", c.Version+1, moved.Version)
+	}
+	checkList(t, repo, byPosition, []string{"c", "a", "b"})
+
+	if _, err := repo.Move(context.Background(), c.ID, &todo.TaskMove{After: b.ID}); err != nil {
+		t.Fatal(err)
+	}
+	checkList(t, repo, byPosition, []string{"a", "b", "c"})
+
+	if _, err := repo.Move(context.Background(), a.ID, &todo.TaskMove{After: b.ID}); err != nil {
+		t.Fatal(err)
+	}
+	checkList(t, repo, byPosition, []string{"b", "a", "c"})
+
+	// New tasks still come last, and other tasks keep their versions.
+	mustCreate(t, repo, &todo.TaskCreate{Summary: "d"})
+	checkList(t, repo, byPosition, []string{"b", "a", "c", "d"})
+	if got, err := repo.Get(context.Background(), b.ID); err != nil || got.Version != b.Version {
+		t.Errorf("want version of task not moved: %d; got: %+v, %v", b.Version, got, err)
+	}
+}
+
+func testRestoreAfterMove(t *testing.T, repo todo.TaskRepository) {
+	ctx := context.Background()
+	a := mustCreate(t, repo, &todo.TaskCreate{Summary: "a"})
+	b := mustCreate(t, repo, &todo.TaskCreate{Summary: "b"})
+	c := mustCreate(t, repo, &todo.TaskCreate{Summary: "c"})
+	if err := repo.Delete(ctx, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	// The move renumbers b and c, so c takes the old position of a.
+	if _, err := repo.Move(ctx, c.ID, &todo.TaskMove{Before: b.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Restore(ctx, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	checkList(t, repo, &todo.ListOptions{SortBy: todo.SortByPosition}, []string{"c", "b", "a"})
+	tasks, err := repo.List(ctx, &todo.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	positions := make(map[int64]string)
+	for _, task := range tasks {
+		if other, ok := positions[task.Position]; ok {
+			t.Errorf("want distinct positions; got %d for tasks %s and %s", task.Position, other, task.Summary)
+		}
+		positions[task.Position] = task.Summary
+	}
+}
+
+func testMoveNotFound(t *testing.T, repo todo.TaskRepository) {
+	a := mustCreate(t, repo, &todo.TaskCreate{Summary: "a"})
+	_, err := repo.Move(context.Background(), "missing", &todo.TaskMove{After: a.ID})
+	if !todo.IsTaskNotFoundError(err) {
+		t.Errorf("want TaskNotFoundError for missing task; got: %v", err)
+	}
+	_, err = repo.Move(context.Background(), a.ID, &todo.TaskMove{Before: "missing"})
+	if !todo.IsTaskNotFoundError(err) {
+		t.Errorf("want TaskNotFoundError for missing target; got: %v", err)
+	}
+}
+
+func testListSort(t *testing.T, repo todo.TaskRepository) {
+	now := time.Now()
+	first := mustCreate(t, repo, &todo.TaskCreate{Summary: "first", DueAt: now.Add(2 * time.Hour)})
+	mustCreate(t, repo, &todo.TaskCreate{Summary: "second"})
+	mustCreate(t, repo, &todo.TaskCreate{Summary: "third", DueAt: now.Add(time.Hour)})
+	summary := "first"
+	if _, err := repo.Update(context.Background(), first.ID, &todo.TaskUpdate{Summary: &summary}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts todo.ListOptions
+		want []string
+	}{
+		{"CreatedDescending", todo.ListOptions{Descending: true}, []string{"third", "second", "first"}},
+		{"Due", todo.ListOptions{SortBy: todo.SortByDue}, []string{"third", "first", "second"}},
+		{"DueDescending", todo.ListOptions{SortBy: todo.SortByDue, Descending: true}, []string{"first", "third", "second"}},
+		{"Updated", todo.ListOptions{SortBy: todo.SortByUpdated}, []string{"second", "third", "first"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkList(t, repo, &tt.opts, tt.want)
+		})
+	}
+}
+
+func testListPagination(t *testing.T, repo todo.TaskRepository) {
+	for _, summary := range []string{"a", "b", "c", "d", "e"} {
+		mustCreate(t, repo, &todo.TaskCreate{Summary: summary})
+	}
+	tests := []struct {
+		name string
+		opts todo.ListOptions
+		want []string
+	}{
+		{"Limit", todo.ListOptions{Limit: 2}, []string{"a", "b"}},
+		{"Offset", todo.ListOptions{Offset: 3}, []string{"d", "e"}},
+		{"Page", todo.ListOptions{Offset: 2, Limit: 2}, []string{"c", "d"}},
+		{"OffsetPastEnd", todo.ListOptions{Offset: 10}, []string{}},
+		{"DescendingPage", todo.ListOptions{Offset: 1, Limit: 1, Descending: true}, []string{"d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkList(t, repo, &tt.opts, tt.want)
+		})
+	}
+}
+
+func testSearch(t *testing.T, repo todo.TaskRepository) {
+	mustCreate(t, repo, &todo.TaskCreate{Summary: "Walk the dog"})
+	milk := mustCreate(t, repo, &todo.TaskCreate{Summary: "Get some milk", Description: "Oat milk"})
+	results, err := repo.Search(context.Background(), "milk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Task.ID != milk.ID {
+		t.Errorf("unexpected search results: %+v", results)
+	}
+}
+
+func testReplaceKeepsPositions(t *testing.T, repo todo.TaskRepository) {
+	now := time.Now()
+	tasks := todo.Tasks{
+		{ID: "1", Summary: "second", CreatedAt: now, Version: 1, Position: 2},
+		{ID: "2", Summary: "first", CreatedAt: now, Version: 1, Position: 1},
+		{ID: "3", Summary: "unordered", CreatedAt: now, Version: 1},
+	}
+	if err := repo.Replace(context.Background(), tasks); err != nil {
+		t.Fatal(err)
+	}
+	mustCreate(t, repo, &todo.TaskCreate{Summary: "new"})
+	checkList(t, repo, &todo.ListOptions{SortBy: todo.SortByPosition}, []string{"first", "second", "unordered", "new"})
+}
+
+func testReplace(t *testing.T, repo todo.TaskRepository) {
+	mustCreate(t, repo, &todo.TaskCreate{Summary: "foo"})
+	tasks := todo.Tasks{
+		{ID: "42", Summary: "bar", CreatedAt: time.Now(), Version: 3},
+	}
+	if err := repo.Replace(context.Background(), tasks); err != nil {
+		t.Fatal(err)
+	}
+	all, err := repo.List(context.Background(), &todo.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[0].ID != "42" || all[0].Summary != "bar" || all[0].Version != 3 {
+		t.Errorf("unexpected tasks after replace: %+v", all)
+	}
+	results, err := repo.Search(context.Background(), "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 {
+		t.Errorf("want replaced tasks not to be found; got: %+v", results)
+	}
+}
+
+func testConcurrentCreate(t *testing.T, repo todo.TaskRepository) {
+	const n = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.Create(context.Background(), &todo.TaskCreate{Summary: fmt.Sprintf("task %d", i)})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("cannot create task: %v", err)
+		}
+	}
+	tasks, err := repo.List(context.Background(), &todo.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != n {
+		t.Fatalf("want %d tasks; got: %d", n, len(tasks))
+	}
+	ids := make(map[string]bool, n)
+	for _, task := range tasks {
+		if ids[task.ID] {
+			t.Errorf("duplicate task ID: %s", task.ID)
+		}
+		ids[task.ID] = true
+	}
+}
+
+// testConcurrentUpdate checks that of several concurrent updates expecting the
+// same version, exactly one is applied.
+func testConcurrentUpdate(t *testing.T, repo todo.TaskRepository) {
+	const n = 20
+	created := mustCreate(t, repo, &todo.TaskCreate{Summary: "foo"})
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			summary := fmt.Sprintf("update %d", i)
+			_, err := repo.Update(context.Background(), created.ID, &todo.TaskUpdate{
+				Summary:         &summary,
+				ExpectedVersion: created.Version,
+			})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	applied := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			applied++
+		case !todo.IsTaskConflictError(err):
+			t.Errorf("want task conflict error; got: %v", err)
+		}
+	}
+	if applied != 1 {
+		t.Errorf("want exactly one update to be applied; got: %d", applied)
+	}
+	got, err := repo.Get(context.Background(), created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != created.Version+1 {
+		t.Errorf("want version: %d; got: %d", created.Version+1, got.Version)
+	}
+}
+
+func testCanceledContext(t *testing.T, repo todo.TaskRepository) {
+	created := mustCreate(t, repo, &todo.TaskCreate{Summary: "foo"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	checkContextErrors(ctx, t, repo, created.ID, context.Canceled)
+}
+
+func testExceededDeadline(t *testing.T, repo todo.TaskRepository) {
+	created := mustCreate(t, repo, &todo.TaskCreate{Summary: "foo"})
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	checkContextErrors(ctx, t, repo, created.ID, context.DeadlineExceeded)
+}
+
+// checkContextErrors checks that all functions of the repository fail with
+// the specified context error and don't modify the repository.
+func checkContextErrors(ctx context.Context, t *testing.T, repo todo.TaskRepository, id string, want error) {
+	t.Helper()
+	summary := "bar"
+	check := func(name string, err error) {
+		if !errors.Is(err, want) {
+			t.Errorf("%s: want error: %v; got: %v", name, want, err)
+		}
+	}
+	_, err := repo.List(ctx, &todo.ListOptions{})
+	check("List", err)
+	_, err = repo.Get(ctx, id)
+	check("Get", err)
+	_, err = repo.Create(ctx, &todo.TaskCreate{Summary: "baz"})
+	check("Create", err)
+	_, err = repo.Update(ctx, id, &todo.TaskUpdate{Summary: &summary})
+	check("Update", err)
+	_, err = repo.Move(ctx, id, &todo.TaskMove{After: "missing"})
+	check("Move", err)
+	check("Delete", repo.Delete(ctx, id))
+	check("Replace", repo.Replace(ctx, nil))
+	_, err = repo.Search(ctx, "foo")
+	check("Search", err)
+	_, err = repo.Stats(ctx, time.Now())
+	check("Stats", err)
+
+	tasks, err := repo.List(context.Background(), &todo.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != id || tasks[0].Summary != "foo" {
+		t.Errorf("want repository to be unmodified; got: %+v", tasks)
+	}
+}
@@ -0,0 +1,53 @@
+package todo
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// checkUniqueSummaries rejects creating the specified tasks with an
+// ALREADY_EXISTS status if the controller enforces unique summaries and the
+// summary of a task equals the summary of an open task or of an earlier task
+// to be created, ignoring case. Tasks that allow duplicates and nil entries are
+// skipped; path returns the path of the task with the specified index in the
+// request. The check is not atomic with creating the tasks, so concurrent
+// requests can still add duplicates.
+func (c *Controller) checkUniqueSummaries(ctx context.Context, creates []*TaskCreate, path func(int) string) error {
+	if !c.uniqueSummaries || !slices.ContainsFunc(creates, func(t *TaskCreate) bool {
+		return t != nil && !t.AllowDuplicate
+	}) {
+		return nil
+	}
+	open, err := c.tasks.List(ctx, &ListOptions{Completion: CompletionOpen})
+	if err != nil {
+		return repositoryError(err, "cannot retrieve tasks")
+	}
+	ids := make(map[string]string, len(open)+len(creates))
+	for i := range open {
+		ids[strings.ToLower(open[i].Summary)] = open[i].ID
+	}
+	for i, create := range creates {
+		if create == nil {
+			continue
+		}
+		key := strings.ToLower(create.Summary)
+		id, exists := ids[key]
+		switch {
+		case !exists:
+			// Later tasks of the request must not have the same summary.
+			ids[key] = ""
+		case create.AllowDuplicate:
+		case id == "":
+			return status.Errorf(codes.AlreadyExists, "%s: summary '%s' duplicates an earlier task of the request",
+				path(i), create.Summary)
+		default:
+			return status.Errorf(codes.AlreadyExists, "%s: summary '%s' duplicates open task '%s'",
+				path(i), create.Summary, id)
+		}
+	}
+	return nil
+}
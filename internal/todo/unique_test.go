@@ -0,0 +1,73 @@
+package todo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todopb "github.com/mwopitz/todo-daemon/api/todo/v1"
+	todov2pb "github.com/mwopitz/todo-daemon/api/todo/v2"
+)
+
+func TestUniqueSummaries(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryTaskDB()
+	ctrl := NewController(nil, nil, db, NewEventBus(), WithUniqueSummaries(true))
+	if _, err := db.Create(ctx, &TaskCreate{Summary: "Buy milk"}); err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+	completed, err := db.Create(ctx, &TaskCreate{Summary: "Call mom"})
+	if err != nil {
+		t.Fatalf("cannot create task: %v", err)
+	}
+	now := time.Now()
+	if _, err := db.Update(ctx, completed.ID, &TaskUpdate{CompletedAt: &now}); err != nil {
+		t.Fatalf("cannot complete task: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		task *todopb.NewTask
+		want codes.Code
+	}{
+		{"Unique", &todopb.NewTask{Summary: "Buy bread"}, codes.OK},
+		{"Duplicate", &todopb.NewTask{Summary: "Buy milk"}, codes.AlreadyExists},
+		{"DuplicateIgnoringCase", &todopb.NewTask{Summary: "BUY MILK"}, codes.AlreadyExists},
+		{"AllowDuplicate", &todopb.NewTask{Summary: "buy milk", AllowDuplicate: true}, codes.OK},
+		{"DuplicateOfCompleted", &todopb.NewTask{Summary: "Call mom"}, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ctrl.CreateTask(ctx, &todopb.CreateTaskRequest{Task: tt.task})
+			if status.Code(err) != tt.want {
+				t.Errorf("want %v; got: %v", tt.want, err)
+			}
+		})
+	}
+
+	batch := &todopb.BatchCreateTasksRequest{
+		Tasks: []*todopb.NewTask{{Summary: "Water plants"}, {Summary: "water plants"}},
+	}
+	if _, err := ctrl.BatchCreateTasks(ctx, batch); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("want duplicate in batch to be rejected; got: %v", err)
+	}
+	v2 := NewControllerV2(ctrl)
+	req := &todov2pb.CreateTaskRequest{Task: &todov2pb.Task{Summary: "Buy bread"}}
+	if _, err := v2.CreateTask(ctx, req); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("want duplicate to be rejected by v2; got: %v", err)
+	}
+}
+
+func TestUniqueSummariesDisabled(t *testing.T) {
+	ctx := context.Background()
+	ctrl := NewController(nil, nil, NewInMemoryTaskDB(), NewEventBus())
+	for range 2 {
+		req := &todopb.CreateTaskRequest{Task: &todopb.NewTask{Summary: "Buy milk"}}
+		if _, err := ctrl.CreateTask(ctx, req); err != nil {
+			t.Errorf("want duplicates to be allowed by default; got: %v", err)
+		}
+	}
+}
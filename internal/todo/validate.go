@@ -0,0 +1,342 @@
+package todo
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The limits of the text fields of tasks, in characters.
+const (
+	MaxSummaryLength     = 500
+	MaxDescriptionLength = 10000
+	MaxProjectLength     = 100
+	MaxTagLength         = 50
+	MaxAssigneeLength    = 100
+	MaxLinkLength        = 2000
+	// MaxTags is the maximum number of tags of a task.
+	MaxTags = 50
+	// MaxLinks is the maximum number of links of a task.
+	MaxLinks = 20
+	// MaxNameLength is the maximum length of the name of a [Filter] or a
+	// [Template].
+	MaxNameLength = 64
+)
+
+// namePattern matches the valid names of filters and templates.
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// The range of the times of tasks, e.g. their due times. Times outside this
+// range are almost certainly mistakes, and some cannot even be formatted as
+// RFC 3339 timestamps.
+var (
+	minTime = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+	maxTime = time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// FieldViolation describes why a single field of a request is invalid.
+type FieldViolation struct {
+	// Field is the path of the invalid field, e.g. "task.summary" or
+	// "task.tags[1]".
+	Field string
+	// Description tells why the field is invalid.
+	Description string
+}
+
+// ValidationError is returned when the fields of a new task or of a task
+// update are invalid, see [TaskCreate.Validate] and [TaskUpdate.Validate].
+type ValidationError struct {
+	// Violations describe the invalid fields.
+	Violations []FieldViolation
+	// subject is what is invalid, e.g. "list request"; "task" if empty.
+	subject string
+}
+
+// NewValidationError creates a [ValidationError] for the specified invalid
+// fields.
+func NewValidationError(violations []FieldViolation) *ValidationError {
+	return &ValidationError{Violations: violations}
+}
+
+// IsValidationError checks if the provided error is a [ValidationError].
+func IsValidationError(err error) bool {
+	var e *ValidationError
+	return err != nil && errors.As(err, &e)
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Field + ": " + v.Description
+	}
+	return "invalid " + cmp.Or(e.subject, "task") + ": " + strings.Join(msgs, "; ")
+}
+
+// withPrefix returns a copy of the error whose field paths are prefixed with
+// the specified path of the validated message, e.g. "task".
+func (e *ValidationError) withPrefix(prefix string) *ValidationError {
+	violations := make([]FieldViolation, len(e.Violations))
+	for i, v := range e.Violations {
+		violations[i] = FieldViolation{Field: prefix + "." + v.Field, Description: v.Description}
+	}
+	return &ValidationError{Violations: violations, subject: e.subject}
+}
+
+// status converts the error into an INVALID_ARGUMENT status whose details
+// describe the invalid fields, which the gateway reports to REST clients.
+func (e *ValidationError) status() error {
+	br := &errdetails.BadRequest{}
+	for _, v := range e.Violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+	st, err := status.New(codes.InvalidArgument, e.Error()).WithDetails(br)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, e.Error())
+	}
+	return st.Err()
+}
+
+// validator collects the violations of the fields of a single message.
+type validator struct {
+	violations []FieldViolation
+	// subject is the subject of the error, see [ValidationError].
+	subject string
+}
+
+func (v *validator) addf(field, format string, args ...any) {
+	v.violations = append(v.violations, FieldViolation{Field: field, Description: fmt.Sprintf(format, args...)})
+}
+
+// err returns a [ValidationError] for the collected violations, or nil if
+// there are none.
+func (v *validator) err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v.violations, subject: v.subject}
+}
+
+// text checks that the specified text is valid UTF-8 without control
+// characters, apart from line breaks and tabs if multiline is true, and that
+// it is at most the specified number of characters long.
+func (v *validator) text(field, s string, maxLength int, multiline bool) {
+	if !utf8.ValidString(s) {
+		v.addf(field, "must be valid UTF-8")
+		return
+	}
+	for _, r := range s {
+		if unicode.IsControl(r) && !(multiline && (r == '\n' || r == '\r' || r == '\t')) {
+			v.addf(field, "must not contain control characters")
+			return
+		}
+	}
+	if n := utf8.RuneCountInString(s); n > maxLength {
+		v.addf(field, "must be at most %d characters long, got %d", maxLength, n)
+	}
+}
+
+func (v *validator) summary(s string) {
+	if strings.TrimSpace(s) == "" {
+		v.addf("summary", "must not be empty")
+		return
+	}
+	v.text("summary", s, MaxSummaryLength, false)
+}
+
+func (v *validator) description(s string) {
+	v.text("description", s, MaxDescriptionLength, true)
+}
+
+func (v *validator) project(s string) {
+	v.text("project", s, MaxProjectLength, false)
+}
+
+// assignee checks that the user the task is assigned to, if any, consists of
+// letters, digits, and the characters "-", "_", ".", and "@" only, so it can be
+// a user name or an email address.
+func (v *validator) assignee(s string) {
+	switch {
+	case s == "":
+	case !utf8.ValidString(s):
+		v.addf("assignee", "must be valid UTF-8")
+	case utf8.RuneCountInString(s) > MaxAssigneeLength:
+		v.addf("assignee", "must be at most %d characters long, got %d", MaxAssigneeLength, utf8.RuneCountInString(s))
+	case strings.IndexFunc(s, invalidAssigneeRune) >= 0:
+		v.addf("assignee", "must only contain letters, digits, '-', '_', '.', and '@', got '%s'", s)
+	}
+}
+
+func invalidAssigneeRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_.@", r)
+}
+
+// name checks the name of a filter or a template.
+func (v *validator) name(s string) {
+	switch {
+	case s == "":
+		v.addf("name", "must not be empty")
+	case len(s) > MaxNameLength:
+		v.addf("name", "must be at most %d characters long, got %d", MaxNameLength, len(s))
+	case !namePattern.MatchString(s):
+		v.addf("name", "must only contain letters, digits, '_', and '-', and start with a letter or digit, got '%s'", s)
+	}
+}
+
+// tags checks that there are not too many tags, and that each tag consists of
+// letters, digits, and the characters "-", "_", ".", "/", and ":" only.
+func (v *validator) tags(tags []string) {
+	if len(tags) > MaxTags {
+		v.addf("tags", "must not be more than %d, got %d", MaxTags, len(tags))
+	}
+	for i, tag := range tags {
+		field := fmt.Sprintf("tags[%d]", i)
+		switch {
+		case tag == "":
+			v.addf(field, "must not be empty")
+		case !utf8.ValidString(tag):
+			v.addf(field, "must be valid UTF-8")
+		case utf8.RuneCountInString(tag) > MaxTagLength:
+			v.addf(field, "must be at most %d characters long, got %d", MaxTagLength, utf8.RuneCountInString(tag))
+		case strings.IndexFunc(tag, invalidTagRune) >= 0:
+			v.addf(field, "must only contain letters, digits, '-', '_', '.', '/', and ':', got '%s'", tag)
+		}
+	}
+}
+
+func invalidTagRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_./:", r)
+}
+
+// links checks that there are not too many links, and that each link is an
+// absolute http or https URL without control characters, so it is safe to open
+// in a web browser.
+func (v *validator) links(links []string) {
+	if len(links) > MaxLinks {
+		v.addf("links", "must not be more than %d, got %d", MaxLinks, len(links))
+	}
+	for i, link := range links {
+		field := fmt.Sprintf("links[%d]", i)
+		switch u, err := url.Parse(link); {
+		case link == "":
+			v.addf(field, "must not be empty")
+		case !utf8.ValidString(link):
+			v.addf(field, "must be valid UTF-8")
+		case utf8.RuneCountInString(link) > MaxLinkLength:
+			v.addf(field, "must be at most %d characters long, got %d", MaxLinkLength, utf8.RuneCountInString(link))
+		case err != nil || u.Scheme == "":
+			v.addf(field, "must be an absolute URL, got '%s'", link)
+		case u.Scheme != "http" && u.Scheme != "https":
+			v.addf(field, "must be an http or https URL, got '%s'", link)
+		case u.Host == "":
+			v.addf(field, "must have a host, got '%s'", link)
+		}
+	}
+}
+
+// time checks that the specified time, if set, is within the supported
+// range.
+func (v *validator) time(field string, t time.Time) {
+	if !t.IsZero() && (t.Before(minTime) || !t.Before(maxTime)) {
+		v.addf(field, "must be between %d and %d, got %s", minTime.Year(), maxTime.Year()-1, t.UTC().Format(time.RFC3339))
+	}
+}
+
+func (v *validator) recurrence(rule string) {
+	if rule == "" {
+		return
+	}
+	if _, err := ParseRecurrence(rule); err != nil {
+		v.addf("recurrence", "%v", err)
+	}
+}
+
+func (v *validator) timeZone(name string) {
+	if name == "" {
+		return
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		v.addf("time_zone", "unknown time zone '%s'", name)
+	}
+}
+
+// Validate checks the fields of the new task: the summary must not be empty,
+// the text fields must be valid UTF-8 within the length limits, the tags must
+// be well-formed, the due time must be within a sensible range, and the
+// recurrence rule, time zone, assignee, and links must be valid. If any field
+// is invalid, it returns a [ValidationError].
+func (t *TaskCreate) Validate() error {
+	var v validator
+	v.summary(t.Summary)
+	v.description(t.Description)
+	v.time("due_at", t.DueAt)
+	v.tags(t.Tags)
+	v.project(t.Project)
+	v.recurrence(t.Recurrence)
+	v.timeZone(t.TimeZone)
+	v.assignee(t.Assignee)
+	v.links(t.Links)
+	return v.err()
+}
+
+// Validate checks the fields changed by the update like [TaskCreate.Validate].
+func (u *TaskUpdate) Validate() error {
+	var v validator
+	if u.Summary != nil {
+		v.summary(*u.Summary)
+	}
+	if u.Description != nil {
+		v.description(*u.Description)
+	}
+	if u.CompletedAt != nil {
+		v.time("completed_at", *u.CompletedAt)
+	}
+	if u.DueAt != nil {
+		v.time("due_at", *u.DueAt)
+	}
+	if u.Tags != nil {
+		v.tags(*u.Tags)
+	}
+	if u.Project != nil {
+		v.project(*u.Project)
+	}
+	if u.Recurrence != nil {
+		v.recurrence(*u.Recurrence)
+	}
+	if u.TimeZone != nil {
+		v.timeZone(*u.TimeZone)
+	}
+	if u.Assignee != nil {
+		v.assignee(*u.Assignee)
+	}
+	if u.Links != nil {
+		v.links(*u.Links)
+	}
+	return v.err()
+}
+
+// invalidArgument converts the specified validation error of the message at
+// the specified path of a request into an INVALID_ARGUMENT status. If the path
+// is empty, the fields are paths of the request already.
+func invalidArgument(err error, path string) error {
+	var e *ValidationError
+	if !errors.As(err, &e) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if path != "" {
+		e = e.withPrefix(path)
+	}
+	return e.status()
+}
@@ -0,0 +1,129 @@
+package todo
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// violatedFields returns the fields of the violations of the specified
+// validation error, or nil if the error is nil.
+func violatedFields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	if !IsValidationError(err) {
+		t.Fatalf("want ValidationError; got: %v", err)
+	}
+	var fields []string
+	for _, v := range err.(*ValidationError).Violations {
+		fields = append(fields, v.Field)
+	}
+	return fields
+}
+
+func TestTaskCreateValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		task TaskCreate
+		want []string
+	}{
+		{"Valid", TaskCreate{
+			Summary:     "Buy milk 🥛",
+			Description: "Oat milk.\n\tNot almond milk.",
+			DueAt:       time.Date(2025, 12, 24, 18, 0, 0, 0, time.UTC),
+			Tags:        []string{"errands", "home/kitchen", "prio:high", "Einkäufe"},
+			Project:     "home",
+			Recurrence:  "FREQ=WEEKLY",
+			TimeZone:    "Europe/Berlin",
+			Links:       []string{"https://example.com/tickets/42", "http://wiki.example.com/Kitchen?a=b#c"},
+		}, nil},
+		{"EmptySummary", TaskCreate{Summary: " \t"}, []string{"summary"}},
+		{"LongSummary", TaskCreate{Summary: strings.Repeat("x", MaxSummaryLength+1)}, []string{"summary"}},
+		{"MaxSummary", TaskCreate{Summary: strings.Repeat("ü", MaxSummaryLength)}, nil},
+		{"InvalidUTF8", TaskCreate{Summary: "a\xffb"}, []string{"summary"}},
+		{"ControlCharacter", TaskCreate{Summary: "a\x1b[31mb"}, []string{"summary"}},
+		{"MultilineSummary", TaskCreate{Summary: "a\nb"}, []string{"summary"}},
+		{"LongDescription", TaskCreate{Summary: "a", Description: strings.Repeat("x", MaxDescriptionLength+1)},
+			[]string{"description"}},
+		{"LongProject", TaskCreate{Summary: "a", Project: strings.Repeat("x", MaxProjectLength+1)}, []string{"project"}},
+		{"InvalidAssignee", TaskCreate{Summary: "a", Assignee: "alice smith"}, []string{"assignee"}},
+		{"InvalidTags", TaskCreate{Summary: "a", Tags: []string{"ok", "", "with space", "#hash"}},
+			[]string{"tags[1]", "tags[2]", "tags[3]"}},
+		{"LongTag", TaskCreate{Summary: "a", Tags: []string{strings.Repeat("x", MaxTagLength+1)}}, []string{"tags[0]"}},
+		{"TooManyTags", TaskCreate{Summary: "a", Tags: slices.Repeat([]string{"x"}, MaxTags+1)}, []string{"tags"}},
+		{"InvalidLinks", TaskCreate{Summary: "a", Links: []string{
+			"", "example.com", "/tickets/42", "javascript:alert(1)", "file:///etc/passwd", "https://a.example/\x00",
+		}}, []string{"links[0]", "links[1]", "links[2]", "links[3]", "links[4]", "links[5]"}},
+		{"LongLink", TaskCreate{Summary: "a", Links: []string{"https://a.example/" + strings.Repeat("x", MaxLinkLength)}},
+			[]string{"links[0]"}},
+		{"TooManyLinks", TaskCreate{Summary: "a", Links: slices.Repeat([]string{"https://a.example"}, MaxLinks+1)},
+			[]string{"links"}},
+		{"DueBefore1970", TaskCreate{Summary: "a", DueAt: time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)},
+			[]string{"due_at"}},
+		{"DueAfter9999", TaskCreate{Summary: "a", DueAt: time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)},
+			[]string{"due_at"}},
+		{"InvalidRecurrence", TaskCreate{Summary: "a", Recurrence: "FREQ=SOMETIMES"}, []string{"recurrence"}},
+		{"InvalidTimeZone", TaskCreate{Summary: "a", TimeZone: "Mars/Olympus_Mons"}, []string{"time_zone"}},
+		{"Several", TaskCreate{Summary: "", TimeZone: "Mars/Olympus_Mons"}, []string{"summary", "time_zone"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := violatedFields(t, tt.task.Validate()); !slices.Equal(got, tt.want) {
+				t.Errorf("want violated fields: %v; got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTaskUpdateValidate(t *testing.T) {
+	empty, summary := "", "b"
+	completedAt := time.Date(1, 1, 1, 0, 0, 0, 1, time.UTC)
+	var noTime time.Time
+	tests := []struct {
+		name   string
+		update TaskUpdate
+		want   []string
+	}{
+		{"Nothing", TaskUpdate{}, nil},
+		{"Summary", TaskUpdate{Summary: &summary}, nil},
+		{"EmptySummary", TaskUpdate{Summary: &empty}, []string{"summary"}},
+		{"EmptyDescription", TaskUpdate{Description: &empty}, nil},
+		{"Uncomplete", TaskUpdate{CompletedAt: &noTime}, nil},
+		{"InvalidCompletion", TaskUpdate{CompletedAt: &completedAt}, []string{"completed_at"}},
+		{"InvalidTags", TaskUpdate{Tags: &[]string{"a b"}}, []string{"tags[0]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := violatedFields(t, tt.update.Validate()); !slices.Equal(got, tt.want) {
+				t.Errorf("want violated fields: %v; got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestInvalidArgument(t *testing.T) {
+	task := &TaskCreate{Summary: "", Tags: []string{"a b"}}
+	err := invalidArgument(task.Validate(), "tasks[2]")
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Errorf("want code %s; got: %s", codes.InvalidArgument, st.Code())
+	}
+	var fields []string
+	for _, detail := range st.Details() {
+		if br, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				fields = append(fields, v.GetField())
+			}
+		}
+	}
+	if want := []string{"tasks[2].summary", "tasks[2].tags[0]"}; !slices.Equal(fields, want) {
+		t.Errorf("want field violations: %v; got: %v", want, fields)
+	}
+}
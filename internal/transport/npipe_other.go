@@ -0,0 +1,17 @@
+//go:build !windows
+
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+func listenPipe(path string) (net.Listener, error) {
+	return nil, fmt.Errorf("cannot listen on named pipe '%s': %w", path, ErrUnsupported)
+}
+
+func dialPipe(_ context.Context, path string) (net.Conn, error) {
+	return nil, fmt.Errorf("cannot dial named pipe '%s': %w", path, ErrUnsupported)
+}
@@ -0,0 +1,22 @@
+//go:build windows
+
+package transport
+
+import (
+	"context"
+	"net"
+
+	"github.com/Microsoft/go-winio"
+)
+
+func listenPipe(path string) (net.Listener, error) {
+	// Only allow access by the owner of the pipe, i.e. the user running the
+	// To-do Daemon server.
+	return winio.ListenPipe(path, &winio.PipeConfig{
+		SecurityDescriptor: "D:P(A;;GA;;;OW)",
+	})
+}
+
+func dialPipe(ctx context.Context, path string) (net.Conn, error) {
+	return winio.DialPipeContext(ctx, path)
+}
@@ -0,0 +1,127 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strconv"
+)
+
+// DefaultSocketMode is the default file mode of Unix domain sockets, which
+// only allows the user running the To-do Daemon server to connect.
+const DefaultSocketMode fs.FileMode = 0o600
+
+// ErrInsecureDir is returned by [Listen] when the directory of a Unix domain
+// socket is writable by all users, so any user could replace the socket.
+var ErrInsecureDir = errors.New("directory is writable by all users")
+
+// ListenOption configures the Unix domain socket created by [Listen]. The
+// options are ignored for named pipes, which only their owner can connect to.
+type ListenOption func(c *listenConfig)
+
+type listenConfig struct {
+	mode fs.FileMode
+	gid  int
+}
+
+// WithSocketMode sets the file mode of the socket. The default is
+// [DefaultSocketMode].
+func WithSocketMode(mode fs.FileMode) ListenOption {
+	return func(c *listenConfig) {
+		c.mode = mode
+	}
+}
+
+// WithSocketGroup sets the group owning the socket, so that its members can
+// connect to the server if the socket's mode allows it, e.g. 0660.
+func WithSocketGroup(gid int) ListenOption {
+	return func(c *listenConfig) {
+		c.gid = gid
+	}
+}
+
+// ParseSocketMode parses the specified octal file mode of a socket, e.g.
+// "0660". The mode must allow its owner to read and write the socket.
+func ParseSocketMode(s string) (fs.FileMode, error) {
+	m, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || m > 0o777 {
+		return 0, fmt.Errorf("invalid socket mode '%s': must be an octal file mode like 0600", s)
+	}
+	mode := fs.FileMode(m)
+	if mode&0o600 != 0o600 {
+		return 0, fmt.Errorf("invalid socket mode '%s': must allow the owner to read and write", s)
+	}
+	return mode, nil
+}
+
+// LookupGroup returns the ID of the group with the specified name or ID.
+func LookupGroup(name string) (int, error) {
+	g, err := user.LookupGroup(name)
+	if err != nil {
+		var unknown user.UnknownGroupError
+		if !errors.As(err, &unknown) {
+			return 0, fmt.Errorf("cannot look up group '%s': %w", name, err)
+		}
+		if g, err = user.LookupGroupId(name); err != nil {
+			return 0, fmt.Errorf("cannot look up group '%s': %w", name, err)
+		}
+	}
+	gid, err := strconv.Atoi(g.Gid)
+	if err != nil {
+		return 0, fmt.Errorf("cannot look up group '%s': %w", name, ErrUnsupported)
+	}
+	return gid, nil
+}
+
+// listenUnix creates a Unix domain socket at the specified path with the
+// configured mode and group, after checking that its directory is safe. The
+// socket is created with mode 0600, so no other user can connect to it before
+// its group and mode are changed.
+func listenUnix(path string, c *listenConfig) (net.Listener, error) {
+	if err := checkSocketDir(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+	l, err := listenSocket(path)
+	if err != nil {
+		return nil, err
+	}
+	if c.gid >= 0 {
+		if err := os.Chown(path, -1, c.gid); err != nil {
+			return nil, errors.Join(fmt.Errorf("cannot change group of socket: %w", err), l.Close())
+		}
+	}
+	if err := os.Chmod(path, c.mode); err != nil {
+		return nil, errors.Join(fmt.Errorf("cannot change mode of socket: %w", err), l.Close())
+	}
+	return l, nil
+}
+
+// checkSocketDir checks that the specified directory of a socket is not
+// writable by all users. A world-writable directory with the sticky bit set,
+// like /tmp, only lets the owners of its files remove them, so it is accepted
+// with a warning.
+func checkSocketDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("cannot check socket directory: %w", err)
+	}
+	mode := info.Mode()
+	switch {
+	case mode&0o002 == 0:
+		return nil
+	case mode&fs.ModeSticky == 0:
+		return fmt.Errorf("unsafe socket directory %s: %w", dir, ErrInsecureDir)
+	}
+	slog.Warn("socket directory is writable by all users; consider a private directory like $XDG_RUNTIME_DIR",
+		"dir", dir)
+	return nil
+}
@@ -0,0 +1,99 @@
+//go:build !windows
+
+package transport
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestListenSocketMode(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []ListenOption
+		want fs.FileMode
+	}{
+		{"Default", nil, DefaultSocketMode},
+		{"Group", []ListenOption{WithSocketMode(0o660), WithSocketGroup(os.Getgid())}, 0o660},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "todo-daemon.sock")
+			l, err := Listen(Address{Scheme: SchemeUnix, Path: path}, tt.opts...)
+			if err != nil {
+				t.Fatalf("cannot listen: %v", err)
+			}
+			defer l.Close()
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := info.Mode().Perm(); got != tt.want {
+				t.Errorf("want socket mode %v; got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestListenSocketIgnoresUmask(t *testing.T) {
+	old := syscall.Umask(0)
+	defer syscall.Umask(old)
+
+	path := filepath.Join(t.TempDir(), "todo-daemon.sock")
+	l, err := listenSocket(path)
+	if err != nil {
+		t.Fatalf("cannot listen: %v", err)
+	}
+	defer l.Close()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Mode().Perm(); got != 0o600 {
+		t.Errorf("want socket mode 0600 before chmod; got: %v", got)
+	}
+	if got := syscall.Umask(0); got != 0 {
+		t.Errorf("want umask restored to 0; got: %#o", got)
+	}
+}
+
+func TestListenInsecureDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	addr := Address{Scheme: SchemeUnix, Path: filepath.Join(dir, "todo-daemon.sock")}
+	if _, err := Listen(addr); !errors.Is(err, ErrInsecureDir) {
+		t.Errorf("want ErrInsecureDir for world-writable directory; got: %v", err)
+	}
+
+	// Like in /tmp, the sticky bit keeps other users from replacing the
+	// socket.
+	if err := os.Chmod(dir, 0o777|fs.ModeSticky); err != nil {
+		t.Fatal(err)
+	}
+	l, err := Listen(addr)
+	if err != nil {
+		t.Fatalf("want socket in sticky directory; got: %v", err)
+	}
+	// revive:disable-next-line:unhandled-error
+	l.Close()
+}
+
+func TestParseSocketMode(t *testing.T) {
+	valid := map[string]fs.FileMode{"0600": 0o600, "660": 0o660, "0777": 0o777}
+	for s, want := range valid {
+		if got, err := ParseSocketMode(s); err != nil || got != want {
+			t.Errorf("ParseSocketMode(%q): want %v; got: %v, %v", s, want, got, err)
+		}
+	}
+	for _, s := range []string{"", "rw", "0400", "0060", "01600", "0999"} {
+		if _, err := ParseSocketMode(s); err == nil {
+			t.Errorf("ParseSocketMode(%q): want error", s)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Package transport provides the listeners and dialers used for the
+// communication between the To-do Daemon server process and the command
+// processes.
+//
+// The To-do Daemon supports two transports: Unix domain sockets, which are the
+// default on all platforms except Windows, and named pipes, which are the
+// default on Windows. Addresses are written as URLs, e.g.
+// "unix:///run/user/1000/todo-daemon.sock" or "npipe:////./pipe/todo-daemon".
+// A plain file path is interpreted as the path of a Unix domain socket.
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+const (
+	// SchemeUnix is the URL scheme of Unix domain socket addresses.
+	SchemeUnix = "unix"
+	// SchemeNamedPipe is the URL scheme of Windows named pipe addresses.
+	SchemeNamedPipe = "npipe"
+)
+
+// ErrUnsupported is returned when a transport is not supported on the current
+// platform.
+var ErrUnsupported = errors.New("transport not supported on this platform")
+
+// Address is the address of an endpoint for inter-process communication.
+type Address struct {
+	// Scheme is the transport used for the communication, either [SchemeUnix]
+	// or [SchemeNamedPipe].
+	Scheme string
+	// Path is the path of the Unix domain socket file or the named pipe.
+	Path string
+}
+
+// ParseAddress parses the specified address, which is either a URL with one of
+// the supported schemes or a plain path to a Unix domain socket file.
+func ParseAddress(s string) (Address, error) {
+	scheme, path, found := strings.Cut(s, "://")
+	if !found {
+		if s == "" {
+			return Address{}, errors.New("empty address")
+		}
+		return Address{Scheme: SchemeUnix, Path: s}, nil
+	}
+	if path == "" {
+		return Address{}, fmt.Errorf("invalid address '%s': empty path", s)
+	}
+	switch scheme {
+	case SchemeUnix:
+		return Address{Scheme: SchemeUnix, Path: path}, nil
+	case SchemeNamedPipe:
+		// Named pipe paths use forward slashes in URLs, e.g.
+		// "npipe:////./pipe/todo-daemon" for "\\.\pipe\todo-daemon".
+		return Address{Scheme: SchemeNamedPipe, Path: strings.ReplaceAll(path, "/", `\`)}, nil
+	default:
+		return Address{}, fmt.Errorf("invalid address '%s': unsupported scheme '%s'", s, scheme)
+	}
+}
+
+// String returns the address in URL form.
+func (a Address) String() string {
+	switch a.Scheme {
+	case SchemeNamedPipe:
+		return a.Scheme + "://" + strings.ReplaceAll(a.Path, `\`, "/")
+	default:
+		return a.Scheme + "://" + a.Path
+	}
+}
+
+// Listen creates a listener for the specified address. Unix domain sockets are
+// created with the mode and group set by the specified options, and only in
+// directories that not all users can write to, see [ErrInsecureDir].
+func Listen(addr Address, opts ...ListenOption) (net.Listener, error) {
+	switch addr.Scheme {
+	case SchemeUnix:
+		c := &listenConfig{mode: DefaultSocketMode, gid: -1}
+		for _, opt := range opts {
+			opt(c)
+		}
+		return listenUnix(addr.Path, c)
+	case SchemeNamedPipe:
+		return listenPipe(addr.Path)
+	default:
+		return nil, fmt.Errorf("cannot listen on %s: %w", addr, ErrUnsupported)
+	}
+}
+
+// Dial connects to the specified address.
+func Dial(ctx context.Context, addr Address) (net.Conn, error) {
+	switch addr.Scheme {
+	case SchemeUnix:
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", addr.Path)
+	case SchemeNamedPipe:
+		return dialPipe(ctx, addr.Path)
+	default:
+		return nil, fmt.Errorf("cannot dial %s: %w", addr, ErrUnsupported)
+	}
+}
@@ -0,0 +1,48 @@
+package transport
+
+import "testing"
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    Address
+		str     string
+	}{
+		{
+			address: "/run/user/1000/todo-daemon.sock",
+			want:    Address{Scheme: SchemeUnix, Path: "/run/user/1000/todo-daemon.sock"},
+			str:     "unix:///run/user/1000/todo-daemon.sock",
+		},
+		{
+			address: "unix:///tmp/todo.sock",
+			want:    Address{Scheme: SchemeUnix, Path: "/tmp/todo.sock"},
+			str:     "unix:///tmp/todo.sock",
+		},
+		{
+			address: "npipe:////./pipe/todo-daemon",
+			want:    Address{Scheme: SchemeNamedPipe, Path: `\\.\pipe\todo-daemon`},
+			str:     "npipe:////./pipe/todo-daemon",
+		},
+	}
+	for _, tt := range tests {
+		got, err := ParseAddress(tt.address)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.address, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: want: %+v; got: %+v", tt.address, tt.want, got)
+		}
+		if s := got.String(); s != tt.str {
+			t.Errorf("%q: want string: %q; got: %q", tt.address, tt.str, s)
+		}
+	}
+}
+
+func TestParseInvalidAddress(t *testing.T) {
+	for _, address := range []string{"", "tcp://localhost:80", "unix://"} {
+		if _, err := ParseAddress(address); err == nil {
+			t.Errorf("%q: want error; got nil", address)
+		}
+	}
+}
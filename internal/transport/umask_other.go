@@ -0,0 +1,9 @@
+//go:build !unix
+
+package transport
+
+import "net"
+
+func listenSocket(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
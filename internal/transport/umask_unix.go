@@ -0,0 +1,17 @@
+//go:build unix
+
+package transport
+
+import (
+	"net"
+	"syscall"
+)
+
+// listenSocket creates a Unix domain socket at the specified path that only
+// its owner can connect to, regardless of the process umask. The umask is
+// process-wide, so other files created at the same time are also restricted.
+func listenSocket(path string) (net.Listener, error) {
+	old := syscall.Umask(0o177)
+	defer syscall.Umask(old)
+	return net.Listen("unix", path)
+}
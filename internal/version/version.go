@@ -1,7 +1,12 @@
 // Package version provides the version of the To-do Daemon.
 package version
 
-import "fmt"
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 var (
 	// Major is the major version of the To-do Daemon.
@@ -12,7 +17,65 @@ var (
 	Patch = 0
 )
 
+// MetadataKey is the gRPC metadata key holding the version of the CLI that
+// makes a call to the server.
+const MetadataKey = "x-todo-daemon-version"
+
+// MinClient is the minimum version of the CLI that the server supports. It
+// must be raised whenever a change to the gRPC API breaks older CLIs.
+var MinClient = Version{Major: 0, Minor: 0, Patch: 0}
+
+// Version is a semantic version.
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// Current returns the version of the To-do Daemon.
+func Current() Version {
+	return Version{Major: Major, Minor: Minor, Patch: Patch}
+}
+
 // Semantic returns the semantic version of the To-do Daemon.
 func Semantic() string {
-	return fmt.Sprintf("%d.%d.%d", Major, Minor, Patch)
+	return Current().String()
+}
+
+// Parse parses a semantic version like "1.2.3". A leading "v" as well as
+// pre-release and build metadata suffixes, e.g. "-rc.1", are ignored.
+func Parse(s string) (Version, error) {
+	core := strings.TrimPrefix(s, "v")
+	if i := strings.IndexAny(core, "-+"); i >= 0 {
+		core = core[:i]
+	}
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid version: '%s'", s)
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version: '%s'", s)
+		}
+		numbers[i] = n
+	}
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// String returns the version formatted as "major.minor.patch".
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1 if v is lower than w, +1 if v is higher than w, and 0 if
+// both versions are equal.
+func (v Version) Compare(w Version) int {
+	switch {
+	case v.Major != w.Major:
+		return cmp.Compare(v.Major, w.Major)
+	case v.Minor != w.Minor:
+		return cmp.Compare(v.Minor, w.Minor)
+	default:
+		return cmp.Compare(v.Patch, w.Patch)
+	}
 }
@@ -0,0 +1,47 @@
+package version
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Version
+	}{
+		{"1.2.3", Version{1, 2, 3}},
+		{"v0.10.0", Version{0, 10, 0}},
+		{"2.0.0-rc.1", Version{2, 0, 0}},
+		{"1.0.1+build.5", Version{1, 0, 1}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.s)
+		if err != nil {
+			t.Errorf("%s: %v", tt.s, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: want: %v; got: %v", tt.s, tt.want, got)
+		}
+	}
+	for _, s := range []string{"", "1.2", "1.2.3.4", "a.b.c", "1.-2.3"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("%s: want error", s)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		v, w Version
+		want int
+	}{
+		{Version{1, 2, 3}, Version{1, 2, 3}, 0},
+		{Version{1, 2, 3}, Version{1, 2, 4}, -1},
+		{Version{1, 3, 0}, Version{1, 2, 9}, 1},
+		{Version{0, 9, 9}, Version{1, 0, 0}, -1},
+	}
+	for _, tt := range tests {
+		if got := tt.v.Compare(tt.w); got != tt.want {
+			t.Errorf("%v.Compare(%v): want: %d; got: %d", tt.v, tt.w, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/logging"
+	"github.com/mwopitz/todo-daemon/internal/rest"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+const (
+	// SignatureHeader is the HTTP header holding the HMAC-SHA256 signature of
+	// the payload, formatted as "sha256=<hex digest>".
+	SignatureHeader = "X-Todo-Daemon-Signature"
+	// EventHeader is the HTTP header holding the type of the delivered event.
+	EventHeader = "X-Todo-Daemon-Event"
+	// DeliveryHeader is the HTTP header holding the ID of the delivery.
+	DeliveryHeader = "X-Todo-Daemon-Delivery"
+)
+
+// payload is the JSON body that is posted to the webhooks.
+type payload struct {
+	ID   string         `json:"id"`
+	Type todo.EventType `json:"type"`
+	Time time.Time      `json:"time"`
+	Task *rest.Task     `json:"task"`
+}
+
+// Dispatcher delivers task events to the webhooks in a [Registry]. Failed
+// deliveries are retried with exponential backoff.
+type Dispatcher struct {
+	// MaxAttempts is the maximum number of attempts to deliver an event.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. The delay doubles
+	// with each further retry.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay between two attempts.
+	MaxBackoff time.Duration
+
+	registry *Registry
+	client   *http.Client
+	seq      atomic.Uint64
+}
+
+// NewDispatcher creates a [Dispatcher] for the webhooks in the specified
+// registry.
+func NewDispatcher(registry *Registry) *Dispatcher {
+	return &Dispatcher{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		registry:       registry,
+		client:         &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Run delivers the events received from the specified channel until the
+// channel is closed or the context is canceled. It waits for all pending
+// deliveries to finish before returning.
+func (d *Dispatcher) Run(ctx context.Context, events <-chan todo.Event) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			for _, hook := range d.registry.List() {
+				if !hook.Matches(&e) {
+					continue
+				}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					d.deliver(ctx, hook, &e)
+				}()
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, hook *Webhook, e *todo.Event) {
+	id := strconv.FormatUint(d.seq.Add(1), 10)
+	body, err := json.Marshal(&payload{
+		ID:   id,
+		Type: e.Type,
+		Time: e.Time,
+		Task: rest.NewTask(&e.Task),
+	})
+	if err != nil {
+		logger().Error("cannot encode webhook payload", "webhook", hook.ID, "cause", err)
+		return
+	}
+
+	backoff := d.InitialBackoff
+	for attempt := 1; attempt <= d.MaxAttempts; attempt++ {
+		delivery := d.post(ctx, hook, id, e.Type, body)
+		delivery.Attempt = attempt
+		d.registry.record(delivery)
+		if delivery.Succeeded() {
+			return
+		}
+		logger().Warn("webhook delivery failed",
+			"webhook", hook.ID,
+			"delivery", id,
+			"attempt", attempt,
+			"status", delivery.StatusCode,
+			"cause", delivery.Error,
+		)
+		if attempt == d.MaxAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, d.MaxBackoff)
+	}
+	logger().Error("giving up on webhook delivery", "webhook", hook.ID, "delivery", id)
+}
+
+func (d *Dispatcher) post(ctx context.Context, hook *Webhook, id string, t todo.EventType, body []byte) *Delivery {
+	delivery := &Delivery{
+		ID:        id,
+		WebhookID: hook.ID,
+		EventType: t,
+		Time:      time.Now(),
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(t))
+	req.Header.Set(DeliveryHeader, id)
+	req.Header.Set(SignatureHeader, Sign(hook.Secret, body))
+
+	resp, err := d.client.Do(req)
+	delivery.Duration = time.Since(delivery.Time)
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logger().Warn("cannot close webhook response body", "cause", err)
+		}
+	}()
+	// Drain the body, so the connection can be reused.
+	if _, err := io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16)); err != nil {
+		logger().Warn("cannot read webhook response body", "cause", err)
+	}
+	delivery.StatusCode = resp.StatusCode
+	if !delivery.Succeeded() {
+		delivery.Error = fmt.Sprintf("unexpected status: %s", resp.Status)
+	}
+	return delivery
+}
+
+// Sign computes the value of the [SignatureHeader] for the specified payload.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// logger returns the logger of the messages about the webhook deliveries.
+func logger() *slog.Logger {
+	return logging.Component(logging.ComponentWebhook)
+}
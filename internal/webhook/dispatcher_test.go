@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+func TestDispatcherRetriesAndSigns(t *testing.T) {
+	var calls atomic.Int32
+	received := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		if got, want := r.Header.Get(SignatureHeader), Sign("s3cr3t", body); got != want {
+			t.Errorf("want signature: %q; got: %q", want, got)
+		}
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		received <- r.Header.Get(EventHeader)
+	}))
+	defer srv.Close()
+
+	registry := NewRegistry()
+	hook, err := registry.Add(&Spec{URL: srv.URL, Secret: "s3cr3t", Events: []todo.EventType{todo.EventTaskCreated}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewDispatcher(registry)
+	d.InitialBackoff = time.Millisecond
+	events := make(chan todo.Event, 2)
+	events <- todo.Event{Type: todo.EventTaskDeleted, Task: todo.Task{ID: "1"}, Time: time.Now()}
+	events <- todo.Event{Type: todo.EventTaskCreated, Task: todo.Task{ID: "2"}, Time: time.Now()}
+	close(events)
+	d.Run(context.Background(), events)
+
+	select {
+	case got := <-received:
+		if want := string(todo.EventTaskCreated); got != want {
+			t.Errorf("want event: %q; got: %q", want, got)
+		}
+	default:
+		t.Fatal("event was not delivered")
+	}
+
+	deliveries, err := registry.Deliveries(hook.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("want 2 deliveries; got: %d", len(deliveries))
+	}
+	if !deliveries[0].Succeeded() || deliveries[0].Attempt != 2 {
+		t.Errorf("want successful second attempt; got: %+v", deliveries[0])
+	}
+	if deliveries[1].Succeeded() || deliveries[1].StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("want failed first attempt; got: %+v", deliveries[1])
+	}
+}
@@ -0,0 +1,147 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/forwarded"
+	"github.com/mwopitz/todo-daemon/internal/rest"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// webhookDTO is the JSON representation of a webhook in the REST API. The
+// secret is only included in the response to the creation of a webhook.
+type webhookDTO struct {
+	ID        string           `json:"id"`
+	URL       string           `json:"url"`
+	Secret    string           `json:"secret,omitempty"`
+	Events    []todo.EventType `json:"events"`
+	Assignee  string           `json:"assignee,omitempty"`
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
+func newWebhookDTO(w *Webhook) *webhookDTO {
+	events := w.Events
+	if events == nil {
+		events = make([]todo.EventType, 0)
+	}
+	return &webhookDTO{
+		ID:        w.ID,
+		URL:       w.URL,
+		Events:    events,
+		Assignee:  w.Assignee,
+		CreatedAt: w.CreatedAt,
+	}
+}
+
+// deliveryDTO is the JSON representation of a webhook delivery in the REST API.
+type deliveryDTO struct {
+	ID         string         `json:"id"`
+	EventType  todo.EventType `json:"eventType"`
+	Attempt    int            `json:"attempt"`
+	StatusCode int            `json:"statusCode,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	Succeeded  bool           `json:"succeeded"`
+	Time       time.Time      `json:"time"`
+	DurationMS int64          `json:"durationMs"`
+}
+
+// Handler serves the REST API endpoints for managing webhooks.
+type Handler struct {
+	registry *Registry
+}
+
+// NewHandler creates a [Handler] for the webhooks in the specified registry.
+func NewHandler(registry *Registry) *Handler {
+	return &Handler{registry: registry}
+}
+
+// Register registers the webhook endpoints below the specified path prefix,
+// e.g. "/api/v1", with the given mux.
+func (h *Handler) Register(mux *http.ServeMux, prefix string) {
+	mux.HandleFunc("GET "+prefix+"/webhooks", h.list)
+	mux.HandleFunc("POST "+prefix+"/webhooks", h.create)
+	mux.HandleFunc("GET "+prefix+"/webhooks/{id}", h.get)
+	mux.HandleFunc("DELETE "+prefix+"/webhooks/{id}", h.remove)
+	mux.HandleFunc("GET "+prefix+"/webhooks/{id}/deliveries", h.deliveries)
+}
+
+func (h *Handler) list(w http.ResponseWriter, _ *http.Request) {
+	hooks := h.registry.List()
+	dtos := make([]*webhookDTO, len(hooks))
+	for i, hook := range hooks {
+		dtos[i] = newWebhookDTO(hook)
+	}
+	rest.WriteJSON(w, http.StatusOK, map[string]any{"webhooks": dtos})
+}
+
+func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		URL      string           `json:"url"`
+		Secret   string           `json:"secret"`
+		Events   []todo.EventType `json:"events"`
+		Assignee string           `json:"assignee"`
+	}
+	if err := rest.DecodeJSON(r, &body); err != nil {
+		rest.WriteError(w, r, http.StatusBadRequest, "%v", err)
+		return
+	}
+	hook, err := h.registry.Add(&Spec{URL: body.URL, Secret: body.Secret, Events: body.Events, Assignee: body.Assignee})
+	if err != nil {
+		rest.WriteError(w, r, http.StatusBadRequest, "%v", err)
+		return
+	}
+	dto := newWebhookDTO(hook)
+	dto.Secret = hook.Secret
+	w.Header().Set("Location", forwarded.URL(r.Context(), r.URL.Path+"/"+hook.ID))
+	rest.WriteJSON(w, http.StatusCreated, dto)
+}
+
+func (h *Handler) get(w http.ResponseWriter, r *http.Request) {
+	hook, ok := h.registry.Get(r.PathValue("id"))
+	if !ok {
+		h.writeError(w, r, ErrNotFound)
+		return
+	}
+	rest.WriteJSON(w, http.StatusOK, newWebhookDTO(hook))
+}
+
+func (h *Handler) remove(w http.ResponseWriter, r *http.Request) {
+	if err := h.registry.Remove(r.PathValue("id")); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) deliveries(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := h.registry.Deliveries(r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	dtos := make([]*deliveryDTO, len(deliveries))
+	for i := range deliveries {
+		d := &deliveries[i]
+		dtos[i] = &deliveryDTO{
+			ID:         d.ID,
+			EventType:  d.EventType,
+			Attempt:    d.Attempt,
+			StatusCode: d.StatusCode,
+			Error:      d.Error,
+			Succeeded:  d.Succeeded(),
+			Time:       d.Time,
+			DurationMS: d.Duration.Milliseconds(),
+		}
+	}
+	rest.WriteJSON(w, http.StatusOK, map[string]any{"deliveries": dtos})
+}
+
+func (*Handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, ErrNotFound) {
+		rest.WriteError(w, r, http.StatusNotFound, "%v: '%s'", err, r.PathValue("id"))
+		return
+	}
+	rest.WriteError(w, r, http.StatusInternalServerError, "%v", err)
+}
@@ -0,0 +1,300 @@
+// Package webhook implements the webhooks of the To-do Daemon, which notify
+// external services about task events via HTTP POST requests.
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+// maxDeliveries is the maximum number of deliveries kept per webhook.
+const maxDeliveries = 100
+
+// Webhook is an external HTTP endpoint that is notified about task events.
+type Webhook struct {
+	// ID is the unique identifier of the webhook.
+	ID string
+	// URL is the URL that the events are posted to.
+	URL string
+	// Secret is the key used for signing the payloads with HMAC-SHA256.
+	Secret string
+	// Events are the types of events that trigger the webhook. If empty, the
+	// webhook is triggered by all events.
+	Events []todo.EventType
+	// Assignee, if non-empty, restricts the webhook to the events of the
+	// tasks assigned to this user, e.g. to notify the user's own channel.
+	Assignee string
+	// CreatedAt is the time when the webhook was registered.
+	CreatedAt time.Time
+	// Configured specifies whether the webhook was registered from the
+	// configuration file rather than via the REST API.
+	Configured bool
+}
+
+// Spec holds the settings of a webhook from the configuration file.
+type Spec struct {
+	// URL is the URL that the events are posted to.
+	URL string
+	// Secret is the key used for signing the payloads. If empty, a random
+	// secret is generated.
+	Secret string
+	// Events are the types of events that trigger the webhook. If empty, the
+	// webhook is triggered by all events.
+	Events []todo.EventType
+	// Assignee, if non-empty, restricts the webhook to the events of the
+	// tasks assigned to this user.
+	Assignee string
+}
+
+// NewSpecs converts the webhooks from the specified configuration into specs.
+func NewSpecs(webhooks []config.Webhook) []Spec {
+	specs := make([]Spec, len(webhooks))
+	for i, w := range webhooks {
+		events := make([]todo.EventType, len(w.Events))
+		for j, event := range w.Events {
+			events[j] = todo.EventType(event)
+		}
+		specs[i] = Spec{URL: w.URL, Secret: w.Secret, Events: events, Assignee: w.Assignee}
+	}
+	return specs
+}
+
+// Validate checks if a webhook can be registered with the spec's settings.
+func (s *Spec) Validate() error {
+	_, err := newWebhook(s)
+	return err
+}
+
+// Matches checks if the webhook is triggered by the specified event, i.e. by
+// its type and, if the webhook has an assignee, by the assignee of its task.
+func (w *Webhook) Matches(e *todo.Event) bool {
+	return (len(w.Events) == 0 || slices.Contains(w.Events, e.Type)) &&
+		(w.Assignee == "" || w.Assignee == e.Task.Assignee)
+}
+
+// Delivery records a single attempt to deliver an event to a webhook.
+type Delivery struct {
+	// ID identifies the event delivery. All attempts to deliver the same event
+	// to the same webhook share the same ID.
+	ID string
+	// WebhookID is the ID of the webhook the event was delivered to.
+	WebhookID string
+	// EventType is the type of the delivered event.
+	EventType todo.EventType
+	// Attempt is the number of the delivery attempt, starting at 1.
+	Attempt int
+	// StatusCode is the HTTP status code returned by the webhook, or 0 if no
+	// response was received.
+	StatusCode int
+	// Error describes why the delivery failed, if it failed.
+	Error string
+	// Time is the time when the delivery was attempted.
+	Time time.Time
+	// Duration is the time it took to deliver the event.
+	Duration time.Duration
+}
+
+// Succeeded checks if the delivery attempt was successful.
+func (d *Delivery) Succeeded() bool {
+	return d.Error == "" && d.StatusCode >= 200 && d.StatusCode < 300
+}
+
+// Registry holds the registered webhooks and their delivery logs.
+type Registry struct {
+	mu         sync.Mutex
+	nextID     int
+	hooks      map[string]*Webhook
+	deliveries map[string][]Delivery
+}
+
+// NewRegistry creates a [Registry] without any webhooks.
+func NewRegistry() *Registry {
+	return &Registry{
+		hooks:      make(map[string]*Webhook),
+		deliveries: make(map[string][]Delivery),
+	}
+}
+
+// Add registers a new webhook with the settings of the specified spec. If the
+// secret is empty, a random secret is generated.
+func (r *Registry) Add(spec *Spec) (*Webhook, error) {
+	w, err := newWebhook(spec)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.add(w)
+	return w, nil
+}
+
+// SetConfigured replaces the webhooks registered from the configuration file
+// with webhooks for the specified specs. Webhooks whose settings are unchanged
+// keep their ID and delivery log; webhooks registered via the REST API are not
+// affected. If any spec is invalid, the registry is left unchanged.
+func (r *Registry) SetConfigured(specs []Spec) error {
+	hooks := make([]*Webhook, len(specs))
+	for i := range specs {
+		w, err := newWebhook(&specs[i])
+		if err != nil {
+			return err
+		}
+		w.Configured = true
+		hooks[i] = w
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := make([]bool, len(specs))
+	for id, w := range r.hooks {
+		if !w.Configured {
+			continue
+		}
+		if i := indexSpec(specs, hooks, kept, w); i >= 0 {
+			kept[i] = true
+			continue
+		}
+		delete(r.hooks, id)
+		delete(r.deliveries, id)
+	}
+	for i, w := range hooks {
+		if !kept[i] {
+			r.add(w)
+		}
+	}
+	return nil
+}
+
+// indexSpec returns the index of the first spec not kept yet with the same
+// settings as the registered webhook w, or -1 if there is none. The webhooks
+// in hooks are the ones created for the specs.
+func indexSpec(specs []Spec, hooks []*Webhook, kept []bool, w *Webhook) int {
+	for i, h := range hooks {
+		// A random secret is generated for specs without a secret, so any
+		// secret is fine then.
+		if !kept[i] && h.URL == w.URL && slices.Equal(h.Events, w.Events) && h.Assignee == w.Assignee &&
+			(specs[i].Secret == "" || specs[i].Secret == w.Secret) {
+			return i
+		}
+	}
+	return -1
+}
+
+// add assigns an ID to the specified webhook and registers it. The caller must
+// hold the registry's lock.
+func (r *Registry) add(w *Webhook) {
+	r.nextID++
+	w.ID = strconv.Itoa(r.nextID)
+	r.hooks[w.ID] = w
+}
+
+// Get returns the webhook with the specified ID.
+func (r *Registry) Get(id string) (*Webhook, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	w, ok := r.hooks[id]
+	return w, ok
+}
+
+// List returns all registered webhooks, ordered by their registration time.
+func (r *Registry) List() []*Webhook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hooks := make([]*Webhook, 0, len(r.hooks))
+	for _, w := range r.hooks {
+		hooks = append(hooks, w)
+	}
+	slices.SortFunc(hooks, func(a, b *Webhook) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return hooks
+}
+
+// Remove unregisters the webhook with the specified ID, including its
+// delivery log.
+func (r *Registry) Remove(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.hooks[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.hooks, id)
+	delete(r.deliveries, id)
+	return nil
+}
+
+// Deliveries returns the most recent deliveries to the webhook with the
+// specified ID, newest first.
+func (r *Registry) Deliveries(id string) ([]Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.hooks[id]; !ok {
+		return nil, ErrNotFound
+	}
+	deliveries := slices.Clone(r.deliveries[id])
+	slices.Reverse(deliveries)
+	return deliveries, nil
+}
+
+func (r *Registry) record(d *Delivery) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.hooks[d.WebhookID]; !ok {
+		return
+	}
+	deliveries := append(r.deliveries[d.WebhookID], *d)
+	if len(deliveries) > maxDeliveries {
+		deliveries = deliveries[len(deliveries)-maxDeliveries:]
+	}
+	r.deliveries[d.WebhookID] = deliveries
+}
+
+// ErrNotFound is returned when a webhook does not exist.
+var ErrNotFound = errors.New("no such webhook")
+
+// newWebhook creates an unregistered webhook with the settings of the
+// specified spec. If the secret is empty, a random secret is generated.
+func newWebhook(spec *Spec) (*Webhook, error) {
+	rawURL, secret, events := spec.URL, spec.Secret, spec.Events
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid webhook URL '%s': scheme must be http or https", rawURL)
+	}
+	for _, e := range events {
+		if !e.IsValid() {
+			return nil, fmt.Errorf("invalid event type: '%s'", e)
+		}
+	}
+	if secret == "" {
+		if secret, err = randomSecret(); err != nil {
+			return nil, err
+		}
+	}
+	return &Webhook{
+		URL:       u.String(),
+		Secret:    secret,
+		Events:    slices.Clone(events),
+		Assignee:  spec.Assignee,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func randomSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("cannot generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
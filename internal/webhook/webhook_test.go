@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/mwopitz/todo-daemon/internal/todo"
+)
+
+func TestRegistrySetConfigured(t *testing.T) {
+	registry := NewRegistry()
+	added, err := registry.Add(&Spec{URL: "https://example.com/api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = registry.SetConfigured([]Spec{
+		{URL: "https://example.com/a", Secret: "s3cr3t"},
+		{URL: "https://example.com/b", Events: []todo.EventType{todo.EventTaskCreated}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hooks := registry.List()
+	if len(hooks) != 3 {
+		t.Fatalf("want 3 webhooks; got: %d", len(hooks))
+	}
+	kept := findURL(hooks, "https://example.com/b")
+
+	// Keep the unchanged webhook, replace the changed one, and don't touch the
+	// webhook registered via the REST API.
+	err = registry.SetConfigured([]Spec{
+		{URL: "https://example.com/b", Events: []todo.EventType{todo.EventTaskCreated}},
+		{URL: "https://example.com/c"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hooks = registry.List()
+	if len(hooks) != 3 {
+		t.Fatalf("want 3 webhooks; got: %d", len(hooks))
+	}
+	if w := findURL(hooks, "https://example.com/api"); w != added || w.Configured {
+		t.Errorf("want webhook registered via the REST API to be kept; got: %+v", w)
+	}
+	if w := findURL(hooks, "https://example.com/b"); w == nil || w != kept {
+		t.Errorf("want unchanged webhook to be kept; got: %+v", w)
+	}
+	if w := findURL(hooks, "https://example.com/c"); w == nil || !w.Configured {
+		t.Errorf("want new webhook to be added; got: %+v", w)
+	}
+
+	// Invalid specs leave the registry unchanged.
+	if err := registry.SetConfigured([]Spec{{URL: "ftp://example.com"}}); err == nil {
+		t.Error("want error for invalid URL")
+	}
+	if got := len(registry.List()); got != 3 {
+		t.Errorf("want 3 webhooks; got: %d", got)
+	}
+}
+
+func TestWebhookMatches(t *testing.T) {
+	hook, err := newWebhook(&Spec{
+		URL:      "https://example.com/alice",
+		Events:   []todo.EventType{todo.EventTaskAssigned, todo.EventTaskCompleted},
+		Assignee: "alice",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		event    todo.EventType
+		assignee string
+		want     bool
+	}{
+		{"Assigned", todo.EventTaskAssigned, "alice", true},
+		{"Completed", todo.EventTaskCompleted, "alice", true},
+		{"OtherType", todo.EventTaskUpdated, "alice", false},
+		{"OtherAssignee", todo.EventTaskAssigned, "bob", false},
+		{"Unassigned", todo.EventTaskCompleted, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &todo.Event{Type: tt.event, Task: todo.Task{ID: "1", Assignee: tt.assignee}}
+			if got := hook.Matches(e); got != tt.want {
+				t.Errorf("want %t; got: %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func findURL(hooks []*Webhook, url string) *Webhook {
+	for _, w := range hooks {
+		if w.URL == url {
+			return w
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// The web UI of the To-do Daemon. It manages the tasks via the REST API and
+// keeps the list up to date via the event stream.
+"use strict";
+
+const api = "../api/v1";
+
+const list = document.getElementById("tasks");
+const empty = document.getElementById("empty");
+const error = document.getElementById("error");
+const connection = document.getElementById("connection");
+
+// request calls the REST API and returns the decoded JSON response. Errors are
+// reported as RFC 7807 problems, whose detail is thrown.
+async function request(method, path, body) {
+  const init = {method, headers: {}};
+  if (body !== undefined) {
+    init.headers["Content-Type"] = "application/json";
+    init.body = JSON.stringify(body);
+  }
+  const resp = await fetch(api + path, init);
+  const data = await resp.json().catch(() => ({}));
+  if (!resp.ok) {
+    throw new Error(data.detail || data.title || resp.statusText);
+  }
+  return data;
+}
+
+// isSet checks if the specified timestamp is set. The REST API returns unset
+// timestamps as null.
+function isSet(timestamp) {
+  return Boolean(timestamp);
+}
+
+function showError(err) {
+  error.textContent = err ? err.message : "";
+  error.hidden = !err;
+}
+
+// run runs the specified action and shows its error, if any.
+async function run(action) {
+  try {
+    await action();
+    showError(null);
+  } catch (err) {
+    showError(err);
+  }
+}
+
+function renderTask(task) {
+  const item = document.createElement("li");
+  const completed = task.completed;
+  item.classList.toggle("completed", completed);
+
+  const checkbox = document.createElement("input");
+  checkbox.type = "checkbox";
+  checkbox.checked = completed;
+  checkbox.title = completed ? "Mark as open" : "Mark as completed";
+  checkbox.addEventListener("change", () => run(() => complete(task, checkbox.checked)));
+
+  const summary = document.createElement("span");
+  summary.className = "summary";
+  summary.textContent = task.summary;
+  summary.title = task.description || "";
+  item.append(checkbox, summary);
+
+  if (isSet(task.dueAt)) {
+    const dueAt = new Date(task.dueAt);
+    const due = document.createElement("span");
+    due.className = "due";
+    due.textContent = "due " + dueAt.toLocaleString();
+    item.classList.toggle("overdue", !completed && dueAt < new Date());
+    item.append(due);
+  }
+
+  const remove = document.createElement("button");
+  remove.textContent = "Delete";
+  remove.addEventListener("click", () => run(() => request("DELETE", "/tasks/" + task.id)));
+  item.append(remove);
+  return item;
+}
+
+async function refresh() {
+  const data = await request("GET", "/tasks");
+  const tasks = data.tasks || [];
+  list.replaceChildren(...tasks.map(renderTask));
+  empty.hidden = tasks.length > 0;
+}
+
+function complete(task, completed) {
+  return request("PATCH", "/tasks/" + task.id, {
+    update: {completedAt: completed ? new Date().toISOString() : null},
+    fields: "completedAt",
+    expectedVersion: task.version,
+  });
+}
+
+document.getElementById("add").addEventListener("submit", (event) => {
+  event.preventDefault();
+  const form = event.target;
+  const task = {summary: form.summary.value};
+  if (form.due.value) {
+    task.dueAt = new Date(form.due.value).toISOString();
+  }
+  run(async () => {
+    await request("POST", "/tasks", task);
+    form.reset();
+  });
+});
+
+// Every change to the tasks, including those made by other clients, is
+// announced on the event stream. Fetching the whole list again keeps its
+// order and filters in line with the server.
+const events = new EventSource(api + "/events");
+for (const type of ["task.created", "task.updated", "task.deleted", "reset"]) {
+  events.addEventListener(type, () => run(refresh));
+}
+events.addEventListener("open", () => {
+  connection.textContent = "Connected";
+  // Changes might have been missed while disconnected.
+  run(refresh);
+});
+events.addEventListener("error", () => {
+  connection.textContent = "Disconnected, reconnecting…";
+});
+
+run(refresh);
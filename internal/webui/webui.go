@@ -0,0 +1,27 @@
+// Package webui provides the web UI of the To-do Daemon, a single-page
+// application that manages the to-do list via the REST API.
+//
+// The files of the web UI are embedded into the binary, so the web UI works
+// without any installation.
+package webui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var static embed.FS
+
+// Handler returns an HTTP handler that serves the files of the web UI. The web
+// UI expects to be served one level below the REST API's base path, e.g. at
+// /ui/ if the REST API is served at /api/.
+func Handler() http.Handler {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		// The directory is embedded, so this cannot happen.
+		panic(err)
+	}
+	return http.FileServerFS(files)
+}
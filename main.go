@@ -2,22 +2,48 @@
 // to a running To-do Daemon server instance as a client. It provides a
 // command-line interface that allows users to specify whether to run the To-do
 // Daemon in server mode or client mode.
+//
+// The exit code of a failed command tells why it failed, see package exitcode.
 package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+	// Embed the time zone database, which not all systems provide, for the
+	// time zones of the tasks.
+	_ "time/tzdata"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/mwopitz/todo-daemon/internal/cli"
+	"github.com/mwopitz/todo-daemon/internal/cli/run"
+	"github.com/mwopitz/todo-daemon/internal/client"
 	"github.com/mwopitz/todo-daemon/internal/config"
+	"github.com/mwopitz/todo-daemon/internal/exitcode"
+	"github.com/mwopitz/todo-daemon/internal/i18n"
+	"github.com/mwopitz/todo-daemon/internal/service"
+	"github.com/mwopitz/todo-daemon/internal/standalone"
 )
 
 func main() {
-	cmd := cli.NewTodoDaemonCommand(config.New())
+	i18n.SetLanguage(cli.Language(os.Args[1:]))
+	conf, err := config.Load(config.DefaultFile(), cli.Profile(os.Args[1:]))
+	if err != nil {
+		// revive:disable-next-line:unhandled-error
+		fmt.Fprintf(os.Stderr, "todo-daemon: %s\n", i18n.Error(err))
+		os.Exit(exitCode(err))
+	}
+
+	cmd := cli.NewTodoDaemonCommand(conf)
 	ctx, cancel := context.WithCancelCause(context.Background())
+	// When started as a Windows service, stopping the service cancels the
+	// context like the signals do.
+	stopped := service.Control(conf.ServiceName(), cancel)
 
 	errchan := make(chan error, 1)
 	sigchan := make(chan os.Signal, 1)
@@ -27,17 +53,51 @@ func main() {
 		close(errchan)
 	}()
 
-	var err error
 	select {
 	case err = <-errchan:
 	case sig := <-sigchan:
 		cancel(fmt.Errorf("received signal: %s", sig))
 		err = <-errchan
 	}
+	stopped(err)
 
+	code := exitCode(err)
+	if code == exitcode.NotRunning {
+		// Spare the user the details of the failed call.
+		err = client.ErrDaemonNotRunning
+	}
 	if err != nil {
 		// revive:disable-next-line:unhandled-error
-		fmt.Fprintf(os.Stderr, "todo-daemon: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "todo-daemon: %s\n", i18n.Error(err))
+		os.Exit(code)
+	}
+}
+
+// exitCode maps the specified error to the exit code that tells scripts why
+// the command failed.
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitcode.OK
+	case exitcode.IsUsageError(err):
+		return exitcode.Usage
+	case errors.Is(err, client.ErrDaemonNotRunning):
+		return exitcode.NotRunning
+	case errors.Is(err, run.ErrAlreadyRunning), errors.Is(err, standalone.ErrLocked):
+		return exitcode.Conflict
+	case errors.Is(err, context.DeadlineExceeded):
+		return exitcode.Unavailable
+	}
+	switch status.Code(err) {
+	case codes.NotFound:
+		return exitcode.NotFound
+	case codes.InvalidArgument:
+		return exitcode.Usage
+	case codes.Aborted, codes.FailedPrecondition, codes.AlreadyExists:
+		return exitcode.Conflict
+	case codes.DeadlineExceeded, codes.Unavailable, codes.ResourceExhausted:
+		return exitcode.Unavailable
+	default:
+		return exitcode.Failure
 	}
 }